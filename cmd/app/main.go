@@ -27,7 +27,12 @@ import (
 	"time"
 
 	docs "service/docs" // Swagger docs
+	appointmentSvc "service/internal/modules/appointments/service"
+	corporateSvc "service/internal/modules/corporate/service"
 	svc "service/internal/modules/payments/service"
+	privacySvc "service/internal/modules/privacy/service"
+	slaSvc "service/internal/modules/sla/service"
+	userSvc "service/internal/modules/users/service"
 	"service/internal/router"
 	"service/internal/shared/config"
 	"service/internal/shared/database"
@@ -59,6 +64,12 @@ func main() {
 	// Initialize validator
 	utils.InitValidator()
 
+	// Load JWT signing keys, creating the first key on a fresh database
+	signingKeys := userSvc.NewSigningKeyService()
+	if err := signingKeys.Sync(context.Background()); err != nil {
+		log.Printf("Failed to load JWT signing keys, falling back to HS256: %v", err)
+	}
+
 	// Initialize Swagger
 	docs.SwaggerInfo.BasePath = "/"
 
@@ -79,6 +90,70 @@ func main() {
 		}
 	}()
 
+	// Start background JWT signing key rotation and keyring refresh
+	go func() {
+		ticker := time.NewTicker(config.Config.JWTKeySyncInterval)
+		defer ticker.Stop()
+		for {
+			<-ticker.C
+			if err := signingKeys.Sync(context.Background()); err != nil {
+				log.Printf("JWT signing key sync failed: %v", err)
+			}
+		}
+	}()
+
+	// Start background job for personal data export and erasure requests
+	go func() {
+		ticker := time.NewTicker(config.Config.PrivacyJobInterval)
+		defer ticker.Stop()
+		ps := privacySvc.NewPrivacyService()
+		for {
+			<-ticker.C
+			if err := ps.ProcessApprovedRequests(context.Background()); err != nil {
+				log.Printf("Privacy request job failed: %v", err)
+			}
+		}
+	}()
+
+	// Start background job for day-before appointment reminders
+	go func() {
+		ticker := time.NewTicker(config.Config.AppointmentReminderInterval)
+		defer ticker.Stop()
+		as := appointmentSvc.NewAppointmentService()
+		for {
+			<-ticker.C
+			if err := as.SendDueReminders(context.Background()); err != nil {
+				log.Printf("Appointment reminder job failed: %v", err)
+			}
+		}
+	}()
+
+	// Start background job for SLA escalations
+	go func() {
+		ticker := time.NewTicker(config.Config.SLACheckInterval)
+		defer ticker.Stop()
+		ss := slaSvc.NewSLAService()
+		for {
+			<-ticker.C
+			if err := ss.CheckDue(context.Background()); err != nil {
+				log.Printf("SLA check job failed: %v", err)
+			}
+		}
+	}()
+
+	// Start background job for monthly corporate invoices
+	go func() {
+		ticker := time.NewTicker(config.Config.CorporateInvoiceInterval)
+		defer ticker.Stop()
+		cs := corporateSvc.NewCorporateService()
+		for {
+			<-ticker.C
+			if err := cs.GenerateDueInvoices(context.Background()); err != nil {
+				log.Printf("Corporate invoice job failed: %v", err)
+			}
+		}
+	}()
+
 	// Start server
 	log.Printf("🚀 iPhone Service API starting on port %s\n", config.Config.Port)
 	log.Printf("📊 Environment: %s\n", config.Config.Environment)
@@ -7,6 +7,7 @@ import (
 	"service/internal/shared/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func main() {
@@ -32,9 +33,15 @@ func runMigrations() {
 	log.Println("✓ Branch table migrated")
 
 	// Step 2: Create User table which depends on Branch
+	// Accounts that exist before contact verification was introduced are treated as verified
+	backfillVerification := db.Migrator().HasTable(&model.User{}) &&
+		!db.Migrator().HasColumn(&model.User{}, "EmailVerifiedAt")
 	if err := db.AutoMigrate(&model.User{}); err != nil {
 		log.Fatalf("Failed to migrate User table: %v", err)
 	}
+	if backfillVerification {
+		db.Exec("UPDATE users SET email_verified_at = created_at, phone_verified_at = created_at")
+	}
 	log.Println("✓ User table migrated")
 
 	// Step 3: Create ServiceOrder table which depends on User and Branch
@@ -97,12 +104,156 @@ func runMigrations() {
 	}
 	log.Println("✓ AuditTrail table migrated")
 
+	// Step 13: Create Role and RolePermission tables
+	if err := db.AutoMigrate(&model.Role{}, &model.RolePermission{}); err != nil {
+		log.Fatalf("Failed to migrate Role tables: %v", err)
+	}
+	log.Println("✓ Role tables migrated")
+
+	// Step 14: Create APIKey table
+	if err := db.AutoMigrate(&model.APIKey{}); err != nil {
+		log.Fatalf("Failed to migrate APIKey table: %v", err)
+	}
+	log.Println("✓ APIKey table migrated")
+
+	// Step 15: Create StaffInvitation table
+	if err := db.AutoMigrate(&model.StaffInvitation{}); err != nil {
+		log.Fatalf("Failed to migrate StaffInvitation table: %v", err)
+	}
+	log.Println("✓ StaffInvitation table migrated")
+
+	// Step 16: Create DataSubjectRequest table
+	if err := db.AutoMigrate(&model.DataSubjectRequest{}); err != nil {
+		log.Fatalf("Failed to migrate DataSubjectRequest table: %v", err)
+	}
+	log.Println("✓ DataSubjectRequest table migrated")
+
+	// Step 17: Create SigningKey table
+	if err := db.AutoMigrate(&model.SigningKey{}); err != nil {
+		log.Fatalf("Failed to migrate SigningKey table: %v", err)
+	}
+	log.Println("✓ SigningKey table migrated")
+
+	// Step 18: Create ContactVerification table
+	if err := db.AutoMigrate(&model.ContactVerification{}); err != nil {
+		log.Fatalf("Failed to migrate ContactVerification table: %v", err)
+	}
+	log.Println("✓ ContactVerification table migrated")
+
+	// Step 19: Create UserDevice table and move the single users.fcm_token into it
+	if err := db.AutoMigrate(&model.UserDevice{}); err != nil {
+		log.Fatalf("Failed to migrate UserDevice table: %v", err)
+	}
+	if db.Migrator().HasColumn("users", "fcm_token") {
+		if err := db.Exec(`INSERT INTO user_devices (user_id, token, last_seen_at, created_at, updated_at)
+			SELECT id, fcm_token, updated_at, NOW(), NOW() FROM users
+			WHERE fcm_token IS NOT NULL AND fcm_token <> '' AND deleted_at IS NULL
+			ON CONFLICT (token) DO NOTHING`).Error; err != nil {
+			log.Fatalf("Failed to move FCM tokens to user_devices: %v", err)
+		}
+		if err := db.Migrator().DropColumn("users", "fcm_token"); err != nil {
+			log.Fatalf("Failed to drop users.fcm_token: %v", err)
+		}
+	}
+	log.Println("✓ UserDevice table migrated")
+
+	// Step 20: Create device catalog, CustomerDevice and OrderPart tables
+	if err := db.AutoMigrate(&model.DeviceModel{}, &model.CustomerDevice{}, &model.OrderPart{}); err != nil {
+		log.Fatalf("Failed to migrate device registry tables: %v", err)
+	}
+	log.Println("✓ Device registry tables migrated")
+
+	// Step 21: Create appointment tables
+	if err := db.AutoMigrate(&model.AppointmentSlotTemplate{}, &model.AppointmentSlot{}, &model.Appointment{}); err != nil {
+		log.Fatalf("Failed to migrate appointment tables: %v", err)
+	}
+	log.Println("✓ Appointment tables migrated")
+
+	// Step 22: Create technician skill, shift and assignment tables
+	if err := db.AutoMigrate(&model.TechnicianSkill{}, &model.TechnicianShift{}, &model.TechnicianAssignment{}); err != nil {
+		log.Fatalf("Failed to migrate technician assignment tables: %v", err)
+	}
+	log.Println("✓ Technician assignment tables migrated")
+
+	// Step 23: Create courier route tables
+	if err := db.AutoMigrate(&model.CourierRoute{}, &model.CourierRouteStop{}); err != nil {
+		log.Fatalf("Failed to migrate courier route tables: %v", err)
+	}
+	log.Println("✓ Courier route tables migrated")
+
+	// Step 24: Create CourierLocation table
+	if err := db.AutoMigrate(&model.CourierLocation{}); err != nil {
+		log.Fatalf("Failed to migrate CourierLocation table: %v", err)
+	}
+	log.Println("✓ CourierLocation table migrated")
+
+	// Step 25: Create handover proof tables
+	if err := db.AutoMigrate(&model.HandoverCode{}, &model.HandoverProof{}, &model.HandoverAttempt{}); err != nil {
+		log.Fatalf("Failed to migrate handover tables: %v", err)
+	}
+	log.Println("✓ Handover tables migrated")
+
+	// Step 26: Create OrderStatusEvent table
+	if err := db.AutoMigrate(&model.OrderStatusEvent{}); err != nil {
+		log.Fatalf("Failed to migrate OrderStatusEvent table: %v", err)
+	}
+	log.Println("✓ OrderStatusEvent table migrated")
+
+	// Step 27: Create SLA tables
+	if err := db.AutoMigrate(&model.SLAPolicy{}, &model.BranchBusinessHours{}, &model.OrderSLA{}); err != nil {
+		log.Fatalf("Failed to migrate SLA tables: %v", err)
+	}
+	log.Println("✓ SLA tables migrated")
+
+	// Step 28: Create corporate account tables
+	if err := db.AutoMigrate(&model.CorporateAccount{}, &model.CorporateContact{}, &model.WorkOrder{},
+		&model.CorporateInvoice{}, &model.CorporateInvoiceLine{}); err != nil {
+		log.Fatalf("Failed to migrate corporate account tables: %v", err)
+	}
+	log.Println("✓ Corporate account tables migrated")
+
+	// Step 29: Create order cancellation tables
+	if err := db.AutoMigrate(&model.CancellationFeeRule{}, &model.OrderCancellation{}); err != nil {
+		log.Fatalf("Failed to migrate order cancellation tables: %v", err)
+	}
+	log.Println("✓ Order cancellation tables migrated")
+
+	// Step 30: Create order media table
+	if err := db.AutoMigrate(&model.OrderMedia{}); err != nil {
+		log.Fatalf("Failed to migrate order media table: %v", err)
+	}
+	log.Println("✓ Order media table migrated")
+
+	// Step 31: Create intake checklist and order intake tables
+	if err := db.AutoMigrate(&model.IntakeChecklist{}, &model.IntakeChecklistItem{}, &model.OrderIntake{}, &model.OrderIntakeItem{}); err != nil {
+		log.Fatalf("Failed to migrate intake tables: %v", err)
+	}
+	log.Println("✓ Intake tables migrated")
+
+	// Step 32: Create order notes tables
+	if err := db.AutoMigrate(&model.OrderNote{}, &model.OrderNoteMention{}); err != nil {
+		log.Fatalf("Failed to migrate order notes tables: %v", err)
+	}
+	log.Println("✓ Order notes tables migrated")
+
+	// Step 33: Create numbering tables
+	if err := db.AutoMigrate(&model.NumberingFormat{}, &model.NumberSequence{}); err != nil {
+		log.Fatalf("Failed to migrate numbering tables: %v", err)
+	}
+	log.Println("✓ Numbering tables migrated")
+
 	// Create indexes
 	createIndexes(db)
 
 	// Seed initial data
 	seedInitialData(db)
 
+	// Seed built-in roles and their permissions
+	seedRoles(db)
+
+	// Seed the device catalog
+	seedDeviceModels(db)
+
 	log.Println("✅ Database migrations completed successfully")
 }
 
@@ -117,6 +268,7 @@ func createIndexes(db *gorm.DB) {
 	db.Exec("CREATE INDEX IF NOT EXISTS idx_branches_city ON branches(city)")
 	db.Exec("CREATE INDEX IF NOT EXISTS idx_branches_province ON branches(province)")
 	db.Exec("CREATE INDEX IF NOT EXISTS idx_branches_is_active ON branches(is_active)")
+	db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_branches_code ON branches(UPPER(code)) WHERE code <> '' AND deleted_at IS NULL")
 
 	// Service order indexes
 	db.Exec("CREATE INDEX IF NOT EXISTS idx_service_orders_user_id ON service_orders(user_id)")
@@ -175,6 +327,47 @@ func createIndexes(db *gorm.DB) {
 	db.Exec("CREATE INDEX IF NOT EXISTS idx_audit_trails_action ON audit_trails(action)")
 	db.Exec("CREATE INDEX IF NOT EXISTS idx_audit_trails_created_at ON audit_trails(created_at)")
 	db.Exec("CREATE INDEX IF NOT EXISTS idx_audit_trails_ip_address ON audit_trails(ip_address)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_audit_trails_api_key_id ON audit_trails(api_key_id)")
+
+	// Role permission indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_role_permissions_permission ON role_permissions(permission)")
+
+	// APIKey indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id)")
+
+	// StaffInvitation indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_staff_invitations_status ON staff_invitations(status, expires_at)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_staff_invitations_branch_id ON staff_invitations(branch_id)")
+
+	// Data subject request indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_data_subject_requests_status ON data_subject_requests(status, created_at)")
+
+	// Customer device indexes
+	db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_customer_devices_customer_imei ON customer_devices(customer_id, imei) WHERE deleted_at IS NULL")
+
+	// Appointment indexes
+	db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_appointments_customer_slot ON appointments(slot_id, customer_id) WHERE status = 'booked'")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_appointment_slots_starts_at ON appointment_slots(starts_at)")
+
+	// Technician assignment indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_service_orders_technician_status ON service_orders(technician_id, status)")
+
+	// Courier route indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_courier_routes_branch_status ON courier_routes(branch_id, status)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_service_orders_branch_status_courier ON service_orders(branch_id, status, courier_id)")
+
+	// Search indexes. The document expressions must match the search repository.
+	db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_service_orders_search ON service_orders USING GIN (to_tsvector('simple', coalesce(order_number, '') || ' ' || coalesce(invoice_number, '') || ' ' || coalesce(i_phone_imei, '') || ' ' || coalesce(description, '')))")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_service_orders_order_number_trgm ON service_orders USING GIN (order_number gin_trgm_ops)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_service_orders_invoice_number_trgm ON service_orders USING GIN (invoice_number gin_trgm_ops)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_service_orders_imei_trgm ON service_orders USING GIN (i_phone_imei gin_trgm_ops)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_users_search ON users USING GIN (to_tsvector('simple', coalesce(full_name, '') || ' ' || coalesce(email, '') || ' ' || coalesce(phone, '')))")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_users_full_name_trgm ON users USING GIN (full_name gin_trgm_ops)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_users_email_trgm ON users USING GIN (email gin_trgm_ops)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_users_phone_trgm ON users USING GIN (phone gin_trgm_ops)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_branches_search ON branches USING GIN (to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(city, '') || ' ' || coalesce(province, '') || ' ' || coalesce(address, '')))")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_branches_name_trgm ON branches USING GIN (name gin_trgm_ops)")
 
 	log.Println("Database indexes created successfully")
 }
@@ -206,6 +399,7 @@ func seedInitialData(db *gorm.DB) {
 	branches := []*model.Branch{
 		{
 			Name:      "Jakarta Central",
+			Code:      "JKT01",
 			Address:   "Jl. Sudirman No. 123",
 			City:      "Jakarta",
 			Province:  "DKI Jakarta",
@@ -216,6 +410,7 @@ func seedInitialData(db *gorm.DB) {
 		},
 		{
 			Name:      "Surabaya Branch",
+			Code:      "SBY01",
 			Address:   "Jl. Tunjungan No. 456",
 			City:      "Surabaya",
 			Province:  "Jawa Timur",
@@ -226,6 +421,7 @@ func seedInitialData(db *gorm.DB) {
 		},
 		{
 			Name:      "Bandung Branch",
+			Code:      "BDG01",
 			Address:   "Jl. Asia Afrika No. 789",
 			City:      "Bandung",
 			Province:  "Jawa Barat",
@@ -244,3 +440,72 @@ func seedInitialData(db *gorm.DB) {
 
 	log.Println("Initial data seeded successfully")
 }
+
+func seedRoles(db *gorm.DB) {
+	// Built-in roles are only created once so that admin changes to their permissions are kept
+	for roleName, permissions := range model.DefaultRolePermissions {
+		var existing model.Role
+		if err := db.Where("name = ?", roleName).First(&existing).Error; err == nil {
+			// admin_pusat always holds every permission, including ones added after it was seeded
+			if roleName == model.RoleAdminPusat {
+				for _, p := range permissions {
+					db.Clauses(clause.OnConflict{DoNothing: true}).
+						Create(&model.RolePermission{RoleID: existing.ID, Permission: p})
+				}
+			}
+			continue
+		}
+
+		role := &model.Role{
+			Name:     roleName,
+			IsSystem: true,
+		}
+		for _, p := range permissions {
+			role.Permissions = append(role.Permissions, model.RolePermission{Permission: p})
+		}
+
+		if err := db.Create(role).Error; err != nil {
+			log.Printf("Failed to seed role %s: %v", roleName, err)
+		}
+	}
+
+	log.Println("Roles seeded successfully")
+}
+
+func seedDeviceModels(db *gorm.DB) {
+	// Models are only added; admins may rename or retire them afterwards
+	models := []model.DeviceModel{
+		{Name: "iPhone 11", Identifier: "iPhone12,1", ReleaseYear: 2019},
+		{Name: "iPhone 11 Pro", Identifier: "iPhone12,3", ReleaseYear: 2019},
+		{Name: "iPhone 11 Pro Max", Identifier: "iPhone12,5", ReleaseYear: 2019},
+		{Name: "iPhone SE (2nd generation)", Identifier: "iPhone12,8", ReleaseYear: 2020},
+		{Name: "iPhone 12 mini", Identifier: "iPhone13,1", ReleaseYear: 2020},
+		{Name: "iPhone 12", Identifier: "iPhone13,2", ReleaseYear: 2020},
+		{Name: "iPhone 12 Pro", Identifier: "iPhone13,3", ReleaseYear: 2020},
+		{Name: "iPhone 12 Pro Max", Identifier: "iPhone13,4", ReleaseYear: 2020},
+		{Name: "iPhone 13 mini", Identifier: "iPhone14,4", ReleaseYear: 2021},
+		{Name: "iPhone 13", Identifier: "iPhone14,5", ReleaseYear: 2021},
+		{Name: "iPhone 13 Pro", Identifier: "iPhone14,2", ReleaseYear: 2021},
+		{Name: "iPhone 13 Pro Max", Identifier: "iPhone14,3", ReleaseYear: 2021},
+		{Name: "iPhone SE (3rd generation)", Identifier: "iPhone14,6", ReleaseYear: 2022},
+		{Name: "iPhone 14", Identifier: "iPhone14,7", ReleaseYear: 2022},
+		{Name: "iPhone 14 Plus", Identifier: "iPhone14,8", ReleaseYear: 2022},
+		{Name: "iPhone 14 Pro", Identifier: "iPhone15,2", ReleaseYear: 2022},
+		{Name: "iPhone 14 Pro Max", Identifier: "iPhone15,3", ReleaseYear: 2022},
+		{Name: "iPhone 15", Identifier: "iPhone15,4", ReleaseYear: 2023},
+		{Name: "iPhone 15 Plus", Identifier: "iPhone15,5", ReleaseYear: 2023},
+		{Name: "iPhone 15 Pro", Identifier: "iPhone16,1", ReleaseYear: 2023},
+		{Name: "iPhone 15 Pro Max", Identifier: "iPhone16,2", ReleaseYear: 2023},
+		{Name: "iPhone 16", Identifier: "iPhone17,3", ReleaseYear: 2024},
+		{Name: "iPhone 16 Plus", Identifier: "iPhone17,4", ReleaseYear: 2024},
+		{Name: "iPhone 16 Pro", Identifier: "iPhone17,1", ReleaseYear: 2024},
+		{Name: "iPhone 16 Pro Max", Identifier: "iPhone17,2", ReleaseYear: 2024},
+	}
+
+	for i := range models {
+		models[i].IsActive = true
+		db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).Create(&models[i])
+	}
+
+	log.Println("Device models seeded successfully")
+}
@@ -138,6 +138,7 @@ func seedBranches(db *gorm.DB) {
 		{
 			ID:        uuid.New(),
 			Name:      "Jakarta Central",
+			Code:      "JKT01",
 			Address:   "Jl. Sudirman No. 123, Jakarta Selatan",
 			City:      "Jakarta",
 			Province:  "DKI Jakarta",
@@ -149,6 +150,7 @@ func seedBranches(db *gorm.DB) {
 		{
 			ID:        uuid.New(),
 			Name:      "Surabaya Branch",
+			Code:      "SBY01",
 			Address:   "Jl. Tunjungan No. 456, Surabaya",
 			City:      "Surabaya",
 			Province:  "Jawa Timur",
@@ -160,6 +162,7 @@ func seedBranches(db *gorm.DB) {
 		{
 			ID:        uuid.New(),
 			Name:      "Bandung Branch",
+			Code:      "BDG01",
 			Address:   "Jl. Asia Afrika No. 789, Bandung",
 			City:      "Bandung",
 			Province:  "Jawa Barat",
@@ -171,6 +174,7 @@ func seedBranches(db *gorm.DB) {
 		{
 			ID:        uuid.New(),
 			Name:      "Medan Branch",
+			Code:      "MDN01",
 			Address:   "Jl. Imam Bonjol No. 321, Medan",
 			City:      "Medan",
 			Province:  "Sumatera Utara",
@@ -182,6 +186,7 @@ func seedBranches(db *gorm.DB) {
 		{
 			ID:        uuid.New(),
 			Name:      "Semarang Branch",
+			Code:      "SMG01",
 			Address:   "Jl. Pemuda No. 654, Semarang",
 			City:      "Semarang",
 			Province:  "Jawa Tengah",
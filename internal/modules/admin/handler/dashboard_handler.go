@@ -66,7 +66,11 @@ func (h *DashboardHandler) GetDashboardStats(c *gin.Context) {
 
 	stats, err := h.dashboardService.GetDashboardStats(c.Request.Context(), &userUUID, branchID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+		status := http.StatusInternalServerError
+		if err == model.ErrForbidden {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, model.CreateErrorResponse(
 			"dashboard_stats_failed",
 			err.Error(),
 			nil,
@@ -121,7 +125,11 @@ func (h *DashboardHandler) GetServiceStats(c *gin.Context) {
 
 	stats, err := h.dashboardService.GetServiceStats(c.Request.Context(), &userUUID, branchID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+		status := http.StatusInternalServerError
+		if err == model.ErrForbidden {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, model.CreateErrorResponse(
 			"service_stats_failed",
 			err.Error(),
 			nil,
@@ -192,7 +200,11 @@ func (h *DashboardHandler) GetRevenueReport(c *gin.Context) {
 
 	report, err := h.dashboardService.GetRevenueReport(c.Request.Context(), &userUUID, branchID, dateFrom, dateTo)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+		status := http.StatusInternalServerError
+		if err == model.ErrForbidden {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, model.CreateErrorResponse(
 			"revenue_report_failed",
 			err.Error(),
 			nil,
@@ -230,7 +242,11 @@ func (h *DashboardHandler) GetBranchStats(c *gin.Context) {
 
 	stats, err := h.dashboardService.GetBranchStats(c.Request.Context(), branchID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+		status := http.StatusInternalServerError
+		if err == model.ErrForbidden {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, model.CreateErrorResponse(
 			"branch_stats_failed",
 			err.Error(),
 			nil,
@@ -331,7 +347,11 @@ func (h *DashboardHandler) GetPopularServices(c *gin.Context) {
 
 	stats, err := h.dashboardService.GetServiceStats(c.Request.Context(), &userUUID, branchID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+		status := http.StatusInternalServerError
+		if err == model.ErrForbidden {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, model.CreateErrorResponse(
 			"popular_services_failed",
 			err.Error(),
 			nil,
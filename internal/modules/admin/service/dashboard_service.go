@@ -31,6 +31,11 @@ func NewDashboardService() *DashboardService {
 
 // GetDashboardStats retrieves dashboard statistics
 func (s *DashboardService) GetDashboardStats(ctx context.Context, userID *uuid.UUID, branchID *uuid.UUID) (*model.DashboardStats, error) {
+	// Branch staff may only request statistics for branches inside their scope
+	if branchID != nil && !model.BranchScopeFromContext(ctx).Allows(*branchID) {
+		return nil, model.ErrForbidden
+	}
+
 	// Get user role if userID is provided
 	var userRole *model.UserRole
 	if userID != nil {
@@ -82,9 +87,8 @@ func (s *DashboardService) GetDashboardStats(ctx context.Context, userID *uuid.U
 	}
 
 	// Get payment statistics
-	paymentFilters := &paymentRepository.PaymentFilters{}
-	if branchID != nil {
-		paymentFilters.OrderID = branchID // This would need to be adjusted based on your payment structure
+	paymentFilters := &paymentRepository.PaymentFilters{
+		BranchID: filters.BranchID,
 	}
 
 	payments, _, err := s.paymentRepo.List(ctx, 0, 1000, paymentFilters)
@@ -126,6 +130,10 @@ func (s *DashboardService) GetDashboardStats(ctx context.Context, userID *uuid.U
 
 // GetServiceStats retrieves service type statistics
 func (s *DashboardService) GetServiceStats(ctx context.Context, userID *uuid.UUID, branchID *uuid.UUID) ([]model.ServiceStats, error) {
+	if branchID != nil && !model.BranchScopeFromContext(ctx).Allows(*branchID) {
+		return nil, model.ErrForbidden
+	}
+
 	// Set filters based on user role
 	filters := &orderRepository.ServiceOrderFilters{}
 	if branchID != nil {
@@ -171,6 +179,10 @@ func (s *DashboardService) GetServiceStats(ctx context.Context, userID *uuid.UUI
 
 // GetRevenueReport retrieves revenue report for a date range
 func (s *DashboardService) GetRevenueReport(ctx context.Context, userID *uuid.UUID, branchID *uuid.UUID, dateFrom, dateTo string) ([]model.RevenueReport, error) {
+	if branchID != nil && !model.BranchScopeFromContext(ctx).Allows(*branchID) {
+		return nil, model.ErrForbidden
+	}
+
 	// Set filters based on user role
 	filters := &orderRepository.ServiceOrderFilters{}
 	if branchID != nil {
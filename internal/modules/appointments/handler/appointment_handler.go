@@ -0,0 +1,480 @@
+package handler
+
+import (
+	"net/http"
+	"service/internal/modules/appointments/service"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AppointmentHandler handles branch slot and appointment endpoints
+type AppointmentHandler struct {
+	appointmentService *service.AppointmentService
+}
+
+// NewAppointmentHandler creates a new appointment handler
+func NewAppointmentHandler() *AppointmentHandler {
+	return &AppointmentHandler{
+		appointmentService: service.NewAppointmentService(),
+	}
+}
+
+// ListSlotTemplates godoc
+// @Summary List slot templates (admin)
+// @Description List the weekly appointment slot templates of a branch
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Branch ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /admin/branches/{id}/slot-templates [get]
+func (h *AppointmentHandler) ListSlotTemplates(c *gin.Context) {
+	branchID, ok := parseID(c, "Invalid branch ID")
+	if !ok {
+		return
+	}
+
+	templates, err := h.appointmentService.ListTemplates(c.Request.Context(), branchID)
+	if err != nil {
+		respondAppointmentError(c, "slot_templates_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(templates, "Slot templates retrieved successfully"))
+}
+
+// CreateSlotTemplate godoc
+// @Summary Add slot template (admin)
+// @Description Add a weekly appointment slot with its capacity to a branch
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Branch ID"
+// @Param request body model.AppointmentSlotTemplateRequest true "Slot template"
+// @Success 201 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /admin/branches/{id}/slot-templates [post]
+func (h *AppointmentHandler) CreateSlotTemplate(c *gin.Context) {
+	branchID, ok := parseID(c, "Invalid branch ID")
+	if !ok {
+		return
+	}
+
+	var req model.AppointmentSlotTemplateRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	template, err := h.appointmentService.CreateTemplate(c.Request.Context(), branchID, &req)
+	if err != nil {
+		respondAppointmentError(c, "slot_template_create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse(template, "Slot template created successfully"))
+}
+
+// UpdateSlotTemplate godoc
+// @Summary Update slot template (admin)
+// @Description Update a weekly appointment slot. Existing bookings are kept.
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Slot template ID"
+// @Param request body model.AppointmentSlotTemplateRequest true "Slot template"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /admin/slot-templates/{id} [put]
+func (h *AppointmentHandler) UpdateSlotTemplate(c *gin.Context) {
+	id, ok := parseID(c, "Invalid slot template ID")
+	if !ok {
+		return
+	}
+
+	var req model.AppointmentSlotTemplateRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	template, err := h.appointmentService.UpdateTemplate(c.Request.Context(), id, &req)
+	if err != nil {
+		respondAppointmentError(c, "slot_template_update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(template, "Slot template updated successfully"))
+}
+
+// DeleteSlotTemplate godoc
+// @Summary Delete slot template (admin)
+// @Description Delete a weekly appointment slot. Existing bookings are kept.
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Slot template ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /admin/slot-templates/{id} [delete]
+func (h *AppointmentHandler) DeleteSlotTemplate(c *gin.Context) {
+	id, ok := parseID(c, "Invalid slot template ID")
+	if !ok {
+		return
+	}
+
+	if err := h.appointmentService.DeleteTemplate(c.Request.Context(), id); err != nil {
+		respondAppointmentError(c, "slot_template_delete_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(nil, "Slot template deleted successfully"))
+}
+
+// ListAvailableSlots godoc
+// @Summary List appointment slots
+// @Description List the appointment slots of a branch on a date with the number of seats left
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Branch ID"
+// @Param date query string true "Date (YYYY-MM-DD)"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /branches/{id}/appointment-slots [get]
+func (h *AppointmentHandler) ListAvailableSlots(c *gin.Context) {
+	branchID, ok := parseID(c, "Invalid branch ID")
+	if !ok {
+		return
+	}
+
+	slots, err := h.appointmentService.ListAvailableSlots(c.Request.Context(), branchID, c.Query("date"))
+	if err != nil {
+		respondAppointmentError(c, "slots_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(slots, "Appointment slots retrieved successfully"))
+}
+
+// BookAppointment godoc
+// @Summary Book an appointment
+// @Description Book a branch time slot to bring a device in for service
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.AppointmentRequest true "Appointment"
+// @Success 201 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /appointments [post]
+func (h *AppointmentHandler) BookAppointment(c *gin.Context) {
+	userUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req model.AppointmentRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	appointment, err := h.appointmentService.BookAppointment(c.Request.Context(), userUUID, &req)
+	if err != nil {
+		respondAppointmentError(c, "appointment_booking_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse(appointment, "Appointment booked successfully"))
+}
+
+// ListMyAppointments godoc
+// @Summary List my appointments
+// @Description List the appointments of the current user
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse
+// @Router /appointments [get]
+func (h *AppointmentHandler) ListMyAppointments(c *gin.Context) {
+	userUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	appointments, err := h.appointmentService.ListMyAppointments(c.Request.Context(), userUUID)
+	if err != nil {
+		respondAppointmentError(c, "appointments_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(appointments, "Appointments retrieved successfully"))
+}
+
+// GetAppointment godoc
+// @Summary Get appointment
+// @Description Get an appointment by ID
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Appointment ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /appointments/{id} [get]
+func (h *AppointmentHandler) GetAppointment(c *gin.Context) {
+	userUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseID(c, "Invalid appointment ID")
+	if !ok {
+		return
+	}
+
+	userRole, _ := c.Get("user_role")
+	role, _ := userRole.(model.UserRole)
+
+	appointment, err := h.appointmentService.GetAppointment(c.Request.Context(), id, userUUID, role)
+	if err != nil {
+		respondAppointmentError(c, "appointment_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(appointment, "Appointment retrieved successfully"))
+}
+
+// RescheduleAppointment godoc
+// @Summary Reschedule appointment
+// @Description Move an appointment to another slot
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Appointment ID"
+// @Param request body model.RescheduleAppointmentRequest true "New slot"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /appointments/{id}/reschedule [put]
+func (h *AppointmentHandler) RescheduleAppointment(c *gin.Context) {
+	userUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseID(c, "Invalid appointment ID")
+	if !ok {
+		return
+	}
+
+	var req model.RescheduleAppointmentRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	appointment, err := h.appointmentService.RescheduleAppointment(c.Request.Context(), id, userUUID, &req)
+	if err != nil {
+		respondAppointmentError(c, "appointment_reschedule_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(appointment, "Appointment rescheduled successfully"))
+}
+
+// CancelAppointment godoc
+// @Summary Cancel appointment
+// @Description Cancel an appointment and free its slot
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Appointment ID"
+// @Param request body model.CancelAppointmentRequest false "Cancellation reason"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /appointments/{id}/cancel [post]
+func (h *AppointmentHandler) CancelAppointment(c *gin.Context) {
+	userUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseID(c, "Invalid appointment ID")
+	if !ok {
+		return
+	}
+
+	// The reason is optional, so an empty body is accepted
+	var req model.CancelAppointmentRequest
+	if c.Request.ContentLength > 0 && !bindRequest(c, &req) {
+		return
+	}
+
+	if err := h.appointmentService.CancelAppointment(c.Request.Context(), id, userUUID, &req); err != nil {
+		respondAppointmentError(c, "appointment_cancel_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(nil, "Appointment cancelled successfully"))
+}
+
+// ListBranchAppointments godoc
+// @Summary List branch appointments
+// @Description List the appointments of a branch on a date (defaults to today)
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Branch ID"
+// @Param date query string false "Date (YYYY-MM-DD)"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /cashier/branches/{id}/appointments [get]
+func (h *AppointmentHandler) ListBranchAppointments(c *gin.Context) {
+	branchID, ok := parseID(c, "Invalid branch ID")
+	if !ok {
+		return
+	}
+
+	date := c.DefaultQuery("date", time.Now().Format("2006-01-02"))
+	appointments, err := h.appointmentService.ListBranchAppointments(c.Request.Context(), branchID, date)
+	if err != nil {
+		respondAppointmentError(c, "appointments_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(appointments, "Appointments retrieved successfully"))
+}
+
+// CheckInAppointment godoc
+// @Summary Check in appointment
+// @Description Convert a booked appointment into a service order when the customer arrives at the branch
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Appointment ID"
+// @Param request body model.CheckInAppointmentRequest false "Device details"
+// @Success 201 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /cashier/appointments/{id}/check-in [post]
+func (h *AppointmentHandler) CheckInAppointment(c *gin.Context) {
+	id, ok := parseID(c, "Invalid appointment ID")
+	if !ok {
+		return
+	}
+
+	// Appointments booked with a registered device need no body
+	var req model.CheckInAppointmentRequest
+	if c.Request.ContentLength > 0 && !bindRequest(c, &req) {
+		return
+	}
+
+	order, err := h.appointmentService.CheckIn(c.Request.Context(), id, &req)
+	if err != nil {
+		respondAppointmentError(c, "appointment_check_in_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse(order, "Appointment checked in successfully"))
+}
+
+// currentUserID reads the authenticated user ID, writing an error response when it is missing
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, model.CreateErrorResponse(
+			"unauthorized",
+			"User not authenticated",
+			nil,
+		))
+		return uuid.Nil, false
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+			"internal_error",
+			"Invalid user ID type",
+			nil,
+		))
+		return uuid.Nil, false
+	}
+
+	return userUUID, true
+}
+
+// parseID parses the :id path parameter, writing an error response when it is invalid
+func parseID(c *gin.Context, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse("invalid_id", message, nil))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// bindRequest binds, sanitizes and validates a JSON payload
+func bindRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return false
+	}
+
+	utils.SanitizeStructStrings(req)
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return false
+	}
+	return true
+}
+
+// respondAppointmentError maps appointment errors to HTTP responses
+func respondAppointmentError(c *gin.Context, code string, err error) {
+	status := http.StatusBadRequest
+	switch err {
+	case model.ErrAppointmentNotFound, model.ErrSlotTemplateNotFound, model.ErrSlotNotFound,
+		model.ErrBranchNotFound, model.ErrCustomerDeviceNotFound, model.ErrUserNotFound:
+		status = http.StatusNotFound
+	case model.ErrSlotFull, model.ErrAppointmentExists, model.ErrAppointmentNotBooked:
+		status = http.StatusConflict
+	case model.ErrForbidden, model.ErrContactNotVerified:
+		status = http.StatusForbidden
+	}
+	c.JSON(status, model.CreateErrorResponse(code, err.Error(), nil))
+}
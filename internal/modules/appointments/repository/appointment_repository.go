@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AppointmentRepository handles slot templates, slots and appointments
+type AppointmentRepository struct {
+	db *gorm.DB
+}
+
+// NewAppointmentRepository creates a new appointment repository
+func NewAppointmentRepository() *AppointmentRepository {
+	return &AppointmentRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *AppointmentRepository) Available() bool {
+	return r.db != nil
+}
+
+// ListTemplates retrieves the slot templates of a branch
+func (r *AppointmentRepository) ListTemplates(ctx context.Context, branchID uuid.UUID, activeOnly bool) ([]*model.AppointmentSlotTemplate, error) {
+	var templates []*model.AppointmentSlotTemplate
+	query := r.db.WithContext(ctx).Where("branch_id = ?", branchID)
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Order("weekday ASC, start_time ASC").Find(&templates).Error
+	return templates, err
+}
+
+// GetTemplate retrieves a slot template by ID
+func (r *AppointmentRepository) GetTemplate(ctx context.Context, id uuid.UUID) (*model.AppointmentSlotTemplate, error) {
+	var template model.AppointmentSlotTemplate
+	if err := r.db.WithContext(ctx).First(&template, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// CreateTemplate creates a slot template
+func (r *AppointmentRepository) CreateTemplate(ctx context.Context, template *model.AppointmentSlotTemplate) error {
+	return r.db.WithContext(ctx).Create(template).Error
+}
+
+// SaveTemplate updates a slot template and brings its upcoming slots in line with it.
+// Empty slots are removed so they are generated again from the new template; slots that
+// already have bookings keep their time and never drop below the number booked.
+func (r *AppointmentRepository) SaveTemplate(ctx context.Context, template *model.AppointmentSlotTemplate) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(template).Error; err != nil {
+			return err
+		}
+		return syncTemplateSlots(tx, template)
+	})
+}
+
+// DeleteTemplate soft deletes a slot template and removes its upcoming empty slots
+func (r *AppointmentRepository) DeleteTemplate(ctx context.Context, template *model.AppointmentSlotTemplate) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(template).Error; err != nil {
+			return err
+		}
+		template.IsActive = false
+		return syncTemplateSlots(tx, template)
+	})
+}
+
+// syncTemplateSlots applies a changed template to the slots generated from it
+func syncTemplateSlots(tx *gorm.DB, template *model.AppointmentSlotTemplate) error {
+	now := time.Now()
+	if err := tx.Where("template_id = ? AND starts_at > ? AND booked = 0", template.ID, now).
+		Delete(&model.AppointmentSlot{}).Error; err != nil {
+		return err
+	}
+
+	// Booked slots of a retired template stay as they are until they have passed
+	capacity := gorm.Expr("booked")
+	if template.IsActive {
+		capacity = gorm.Expr("GREATEST(booked, ?)", template.Capacity)
+	}
+	return tx.Model(&model.AppointmentSlot{}).
+		Where("template_id = ? AND starts_at > ?", template.ID, now).
+		UpdateColumn("capacity", capacity).Error
+}
+
+// EnsureSlots creates the given slots unless a slot already starts at the same time at the branch
+func (r *AppointmentRepository) EnsureSlots(ctx context.Context, slots []*model.AppointmentSlot) error {
+	if len(slots) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "branch_id"}, {Name: "starts_at"}},
+			DoNothing: true,
+		}).
+		Create(&slots).Error
+}
+
+// ListSlots retrieves the slots of a branch starting in [from, to)
+func (r *AppointmentRepository) ListSlots(ctx context.Context, branchID uuid.UUID, from, to time.Time) ([]*model.AppointmentSlot, error) {
+	var slots []*model.AppointmentSlot
+	err := r.db.WithContext(ctx).
+		Where("branch_id = ? AND starts_at >= ? AND starts_at < ?", branchID, from, to).
+		Order("starts_at ASC").
+		Find(&slots).Error
+	return slots, err
+}
+
+// GetSlot retrieves a slot by ID
+func (r *AppointmentRepository) GetSlot(ctx context.Context, id uuid.UUID) (*model.AppointmentSlot, error) {
+	var slot model.AppointmentSlot
+	if err := r.db.WithContext(ctx).First(&slot, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &slot, nil
+}
+
+// takeSeat books one seat in a slot, failing with ErrSlotFull when it has none left
+func takeSeat(tx *gorm.DB, slotID uuid.UUID) error {
+	result := tx.Model(&model.AppointmentSlot{}).
+		Where("id = ? AND booked < capacity", slotID).
+		UpdateColumn("booked", gorm.Expr("booked + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrSlotFull
+	}
+	return nil
+}
+
+// releaseSeat gives a seat in a slot back
+func releaseSeat(tx *gorm.DB, slotID uuid.UUID) error {
+	return tx.Model(&model.AppointmentSlot{}).
+		Where("id = ? AND booked > 0", slotID).
+		UpdateColumn("booked", gorm.Expr("booked - 1")).Error
+}
+
+// Book takes a seat in the slot and creates the appointment in one transaction
+func (r *AppointmentRepository) Book(ctx context.Context, appointment *model.Appointment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := takeSeat(tx, appointment.SlotID); err != nil {
+			return err
+		}
+		return tx.Omit("Slot").Create(appointment).Error
+	})
+}
+
+// Reschedule moves a booked appointment to another slot, possibly at another branch, in one transaction
+func (r *AppointmentRepository) Reschedule(ctx context.Context, appointment *model.Appointment, slot *model.AppointmentSlot) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := takeSeat(tx, slot.ID); err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.Appointment{}).
+			Where("id = ? AND status = ?", appointment.ID, model.AppointmentStatusBooked).
+			Updates(map[string]interface{}{
+				"slot_id":          slot.ID,
+				"branch_id":        slot.BranchID,
+				"reminder_sent_at": nil,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrAppointmentNotBooked
+		}
+
+		return releaseSeat(tx, appointment.SlotID)
+	})
+}
+
+// Cancel cancels a booked appointment and gives its seat back in one transaction
+func (r *AppointmentRepository) Cancel(ctx context.Context, appointment *model.Appointment, reason string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Appointment{}).
+			Where("id = ? AND status = ?", appointment.ID, model.AppointmentStatusBooked).
+			Updates(map[string]interface{}{
+				"status":        model.AppointmentStatusCancelled,
+				"cancel_reason": reason,
+				"cancelled_at":  time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrAppointmentNotBooked
+		}
+
+		return releaseSeat(tx, appointment.SlotID)
+	})
+}
+
+// CheckIn creates the service order of a booked appointment and marks it checked in
+// in one transaction, so an appointment is never converted twice
+func (r *AppointmentRepository) CheckIn(ctx context.Context, appointment *model.Appointment, order *model.ServiceOrder) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.Appointment{}).
+			Where("id = ? AND status = ?", appointment.ID, model.AppointmentStatusBooked).
+			Updates(map[string]interface{}{
+				"status":        model.AppointmentStatusCheckedIn,
+				"order_id":      order.ID,
+				"checked_in_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrAppointmentNotBooked
+		}
+		return nil
+	})
+}
+
+// GetByID retrieves an appointment with its slot
+func (r *AppointmentRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Appointment, error) {
+	var appointment model.Appointment
+	if err := r.db.WithContext(ctx).Preload("Slot").First(&appointment, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &appointment, nil
+}
+
+// HasBooking reports whether the customer already holds a booking in the slot
+func (r *AppointmentRepository) HasBooking(ctx context.Context, customerID, slotID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Appointment{}).
+		Where("customer_id = ? AND slot_id = ? AND status = ?", customerID, slotID, model.AppointmentStatusBooked).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListByCustomer retrieves the appointments of a customer, most recent slot first
+func (r *AppointmentRepository) ListByCustomer(ctx context.Context, customerID uuid.UUID) ([]*model.Appointment, error) {
+	var appointments []*model.Appointment
+	err := r.db.WithContext(ctx).
+		Joins("Slot").
+		Where("appointments.customer_id = ?", customerID).
+		Order(`"Slot".starts_at DESC`).
+		Find(&appointments).Error
+	return appointments, err
+}
+
+// ListByBranch retrieves the appointments of a branch with slots starting in [from, to)
+func (r *AppointmentRepository) ListByBranch(ctx context.Context, branchID uuid.UUID, from, to time.Time) ([]*model.Appointment, error) {
+	var appointments []*model.Appointment
+	err := r.db.WithContext(ctx).
+		Joins("Slot").
+		Where("appointments.branch_id = ? AND \"Slot\".starts_at >= ? AND \"Slot\".starts_at < ?", branchID, from, to).
+		Order(`"Slot".starts_at ASC`).
+		Find(&appointments).Error
+	return appointments, err
+}
+
+// ListDueReminders retrieves booked appointments starting in [from, to) that have not been reminded yet
+func (r *AppointmentRepository) ListDueReminders(ctx context.Context, from, to time.Time) ([]*model.Appointment, error) {
+	var appointments []*model.Appointment
+	err := r.db.WithContext(ctx).
+		Joins("Slot").
+		Where("appointments.status = ? AND appointments.reminder_sent_at IS NULL", model.AppointmentStatusBooked).
+		Where("\"Slot\".starts_at >= ? AND \"Slot\".starts_at < ?", from, to).
+		Find(&appointments).Error
+	return appointments, err
+}
+
+// MarkReminded records that the reminder went out. It reports false when another
+// instance already claimed the appointment.
+func (r *AppointmentRepository) MarkReminded(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.Appointment{}).
+		Where("id = ? AND reminder_sent_at IS NULL", id).
+		UpdateColumn("reminder_sent_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
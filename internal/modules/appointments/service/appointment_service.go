@@ -0,0 +1,595 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"service/internal/modules/appointments/repository"
+	branchRepo "service/internal/modules/branches/repository"
+	deviceRepo "service/internal/modules/devices/repository"
+	notificationService "service/internal/modules/notification/service"
+	numberingService "service/internal/modules/numbering/service"
+	orderService "service/internal/modules/orders/service"
+	userRepo "service/internal/modules/users/repository"
+	"service/internal/shared/config"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// defaultBookingDays is how far ahead customers may book when no configuration is loaded
+const defaultBookingDays = 30
+
+// AppointmentService handles branch slot templates and customer appointments
+type AppointmentService struct {
+	appointmentRepo     *repository.AppointmentRepository
+	branchRepo          *branchRepo.BranchRepository
+	deviceRepo          *deviceRepo.CustomerDeviceRepository
+	userRepo            *userRepo.UserRepository
+	notificationService *notificationService.NotificationService
+	assignmentService   *orderService.AssignmentService
+	numberingService    *numberingService.NumberingService
+}
+
+// NewAppointmentService creates a new appointment service
+func NewAppointmentService() *AppointmentService {
+	return &AppointmentService{
+		appointmentRepo:     repository.NewAppointmentRepository(),
+		branchRepo:          branchRepo.NewBranchRepository(),
+		deviceRepo:          deviceRepo.NewCustomerDeviceRepository(),
+		userRepo:            userRepo.NewUserRepository(),
+		notificationService: notificationService.NewNotificationService(),
+		assignmentService:   orderService.NewAssignmentService(),
+		numberingService:    numberingService.NewNumberingService(),
+	}
+}
+
+// ListTemplates retrieves the slot templates of a branch
+func (s *AppointmentService) ListTemplates(ctx context.Context, branchID uuid.UUID) ([]*model.AppointmentSlotTemplate, error) {
+	if !model.BranchScopeFromContext(ctx).Allows(branchID) {
+		return nil, model.ErrForbidden
+	}
+	if !s.appointmentRepo.Available() {
+		return []*model.AppointmentSlotTemplate{}, nil
+	}
+	return s.appointmentRepo.ListTemplates(ctx, branchID, false)
+}
+
+// CreateTemplate adds a weekly slot template to a branch
+func (s *AppointmentService) CreateTemplate(ctx context.Context, branchID uuid.UUID, req *model.AppointmentSlotTemplateRequest) (*model.AppointmentSlotTemplate, error) {
+	if !model.BranchScopeFromContext(ctx).Allows(branchID) {
+		return nil, model.ErrForbidden
+	}
+	if !s.appointmentRepo.Available() {
+		return nil, errors.New("appointments are not available")
+	}
+	if _, err := s.branchRepo.GetByID(ctx, branchID); err != nil {
+		return nil, model.ErrBranchNotFound
+	}
+
+	template := &model.AppointmentSlotTemplate{BranchID: branchID, IsActive: true}
+	if err := applyTemplateRequest(template, req); err != nil {
+		return nil, err
+	}
+	if err := s.appointmentRepo.CreateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// UpdateTemplate changes a slot template. Existing bookings are kept.
+func (s *AppointmentService) UpdateTemplate(ctx context.Context, id uuid.UUID, req *model.AppointmentSlotTemplateRequest) (*model.AppointmentSlotTemplate, error) {
+	template, err := s.getTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyTemplateRequest(template, req); err != nil {
+		return nil, err
+	}
+	if err := s.appointmentRepo.SaveTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// DeleteTemplate removes a slot template. Existing bookings are kept.
+func (s *AppointmentService) DeleteTemplate(ctx context.Context, id uuid.UUID) error {
+	template, err := s.getTemplate(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.appointmentRepo.DeleteTemplate(ctx, template)
+}
+
+// ListAvailableSlots retrieves the slots of a branch on a date (YYYY-MM-DD) that have not started yet
+func (s *AppointmentService) ListAvailableSlots(ctx context.Context, branchID uuid.UUID, date string) ([]model.AppointmentSlotResponse, error) {
+	day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return nil, model.ErrInvalidInput
+	}
+
+	branch, err := s.branchRepo.GetByID(ctx, branchID)
+	if err != nil {
+		return nil, model.ErrBranchNotFound
+	}
+
+	responses := []model.AppointmentSlotResponse{}
+	if !s.appointmentRepo.Available() || !branch.IsActive || !withinBookingWindow(day) {
+		return responses, nil
+	}
+
+	if err := s.generateSlots(ctx, branchID, day); err != nil {
+		return nil, err
+	}
+
+	slots, err := s.appointmentRepo.ListSlots(ctx, branchID, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, slot := range slots {
+		if slot.StartsAt.After(now) {
+			responses = append(responses, slot.ToResponse())
+		}
+	}
+	return responses, nil
+}
+
+// BookAppointment books a slot for a customer
+func (s *AppointmentService) BookAppointment(ctx context.Context, customerID uuid.UUID, req *model.AppointmentRequest) (*model.AppointmentResponse, error) {
+	if !s.appointmentRepo.Available() {
+		return nil, errors.New("appointments are not available")
+	}
+
+	customer, err := s.userRepo.GetByID(ctx, customerID)
+	if err != nil {
+		return nil, model.ErrUserNotFound
+	}
+
+	// The appointment becomes an order at check-in, so the same contact rule applies
+	if config.Config != nil && config.Config.RequireVerifiedContactOrders &&
+		customer.Role == model.RolePelanggan && !customer.IsContactVerified() {
+		return nil, model.ErrContactNotVerified
+	}
+
+	slot, err := s.getBookableSlot(ctx, req.SlotID)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.appointmentRepo.HasBooking(ctx, customerID, slot.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, model.ErrAppointmentExists
+	}
+
+	appointment := &model.Appointment{
+		CustomerID:  customerID,
+		BranchID:    slot.BranchID,
+		SlotID:      slot.ID,
+		ServiceType: req.ServiceType,
+		Description: req.Description,
+		Status:      model.AppointmentStatusBooked,
+	}
+	if req.DeviceID != "" {
+		device, err := s.getCustomerDevice(ctx, customerID, req.DeviceID)
+		if err != nil {
+			return nil, err
+		}
+		appointment.DeviceID = &device.ID
+	}
+
+	if err := s.appointmentRepo.Book(ctx, appointment); err != nil {
+		return nil, err
+	}
+
+	appointment.Slot = *slot
+	response := appointment.ToResponse()
+	return &response, nil
+}
+
+// ListMyAppointments retrieves the appointments of a customer
+func (s *AppointmentService) ListMyAppointments(ctx context.Context, customerID uuid.UUID) ([]model.AppointmentResponse, error) {
+	responses := []model.AppointmentResponse{}
+	if !s.appointmentRepo.Available() {
+		return responses, nil
+	}
+
+	appointments, err := s.appointmentRepo.ListByCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range appointments {
+		responses = append(responses, a.ToResponse())
+	}
+	return responses, nil
+}
+
+// GetAppointment retrieves an appointment. Customers only see their own; staff only those of their branches.
+func (s *AppointmentService) GetAppointment(ctx context.Context, id, userID uuid.UUID, role model.UserRole) (*model.AppointmentResponse, error) {
+	appointment, err := s.getAppointment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if role == model.RolePelanggan && appointment.CustomerID != userID {
+		return nil, model.ErrAppointmentNotFound
+	}
+
+	response := appointment.ToResponse()
+	return &response, nil
+}
+
+// RescheduleAppointment moves a customer's appointment to another slot
+func (s *AppointmentService) RescheduleAppointment(ctx context.Context, id, customerID uuid.UUID, req *model.RescheduleAppointmentRequest) (*model.AppointmentResponse, error) {
+	appointment, err := s.getOwnedAppointment(ctx, id, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.Status != model.AppointmentStatusBooked || !appointment.Slot.StartsAt.After(time.Now()) {
+		return nil, model.ErrAppointmentNotBooked
+	}
+
+	slot, err := s.getBookableSlot(ctx, req.SlotID)
+	if err != nil {
+		return nil, err
+	}
+	if slot.ID == appointment.SlotID {
+		return nil, model.ErrAppointmentExists
+	}
+
+	if err := s.appointmentRepo.Reschedule(ctx, appointment, slot); err != nil {
+		return nil, err
+	}
+
+	appointment.SlotID = slot.ID
+	appointment.BranchID = slot.BranchID
+	appointment.Slot = *slot
+	response := appointment.ToResponse()
+	return &response, nil
+}
+
+// CancelAppointment cancels a customer's appointment and frees its seat
+func (s *AppointmentService) CancelAppointment(ctx context.Context, id, customerID uuid.UUID, req *model.CancelAppointmentRequest) error {
+	appointment, err := s.getOwnedAppointment(ctx, id, customerID)
+	if err != nil {
+		return err
+	}
+	if appointment.Status != model.AppointmentStatusBooked {
+		return model.ErrAppointmentNotBooked
+	}
+	return s.appointmentRepo.Cancel(ctx, appointment, req.Reason)
+}
+
+// ListBranchAppointments retrieves the appointments of a branch on a date (YYYY-MM-DD)
+func (s *AppointmentService) ListBranchAppointments(ctx context.Context, branchID uuid.UUID, date string) ([]model.AppointmentResponse, error) {
+	if !model.BranchScopeFromContext(ctx).Allows(branchID) {
+		return nil, model.ErrForbidden
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return nil, model.ErrInvalidInput
+	}
+
+	responses := []model.AppointmentResponse{}
+	if !s.appointmentRepo.Available() {
+		return responses, nil
+	}
+
+	appointments, err := s.appointmentRepo.ListByBranch(ctx, branchID, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range appointments {
+		responses = append(responses, a.ToResponse())
+	}
+	return responses, nil
+}
+
+// CheckIn converts a booked appointment into a service order when the customer arrives
+// at the branch. The device comes from the registered device of the appointment or the
+// details taken at the counter.
+func (s *AppointmentService) CheckIn(ctx context.Context, id uuid.UUID, req *model.CheckInAppointmentRequest) (*model.ServiceOrderResponse, error) {
+	appointment, err := s.getAppointment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.Status != model.AppointmentStatusBooked {
+		return nil, model.ErrAppointmentNotBooked
+	}
+	if !sameDay(appointment.Slot.StartsAt, time.Now()) {
+		return nil, errors.New("appointments can only be checked in on the day of the slot")
+	}
+
+	branch, err := s.branchRepo.GetByID(ctx, appointment.BranchID)
+	if err != nil {
+		return nil, model.ErrBranchNotFound
+	}
+
+	orderNumber, err := s.numberingService.NextOrderNumber(ctx, appointment.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &model.ServiceOrder{
+		OrderNumber:     orderNumber,
+		CustomerID:      appointment.CustomerID,
+		BranchID:        appointment.BranchID,
+		ServiceType:     appointment.ServiceType,
+		Description:     appointment.Description,
+		PickupAddress:   branch.Address, // walk-in, the device is already at the branch
+		PickupLatitude:  branch.Latitude,
+		PickupLongitude: branch.Longitude,
+		Status:          model.StatusInService,
+		Notes:           req.Notes,
+	}
+	if err := s.applyCheckInDevice(ctx, appointment, req, order); err != nil {
+		return nil, err
+	}
+	order.SetAliasFields()
+
+	if err := s.appointmentRepo.CheckIn(ctx, appointment, order); err != nil {
+		return nil, err
+	}
+	s.assignmentService.AssignOnArrival(ctx, order)
+
+	response := order.ToResponse()
+	return &response, nil
+}
+
+// SendDueReminders reminds customers of their appointments the day before.
+// Each appointment is claimed before sending so that it is reminded only once.
+func (s *AppointmentService) SendDueReminders(ctx context.Context) error {
+	if !s.appointmentRepo.Available() {
+		return nil
+	}
+
+	now := time.Now()
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	appointments, err := s.appointmentRepo.ListDueReminders(ctx, tomorrow, tomorrow.AddDate(0, 0, 1))
+	if err != nil {
+		return err
+	}
+
+	branchNames := map[uuid.UUID]string{}
+	for _, a := range appointments {
+		claimed, err := s.appointmentRepo.MarkReminded(ctx, a.ID)
+		if err != nil {
+			log.Printf("Failed to claim appointment reminder %s: %v", a.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		name, ok := branchNames[a.BranchID]
+		if !ok {
+			if branch, err := s.branchRepo.GetByID(ctx, a.BranchID); err == nil {
+				name = branch.Name
+			}
+			branchNames[a.BranchID] = name
+		}
+
+		message := fmt.Sprintf("Reminder: your service appointment is tomorrow at %s", a.Slot.StartsAt.In(time.Local).Format("15:04"))
+		if name != "" {
+			message += " at " + name
+		}
+		for _, channel := range []model.NotificationType{model.NotificationTypePush, model.NotificationTypeWhatsApp} {
+			if _, err := s.notificationService.SendNotification(ctx, &model.NotificationRequest{
+				UserID:  a.CustomerID.String(),
+				Type:    channel,
+				Title:   "Appointment Reminder",
+				Message: message,
+			}); err != nil {
+				log.Printf("Failed to send %s reminder for appointment %s: %v", channel, a.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// generateSlots creates the slots of a day from the active templates of the branch
+func (s *AppointmentService) generateSlots(ctx context.Context, branchID uuid.UUID, day time.Time) error {
+	templates, err := s.appointmentRepo.ListTemplates(ctx, branchID, true)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var slots []*model.AppointmentSlot
+	for _, t := range templates {
+		if t.Weekday != int(day.Weekday()) {
+			continue
+		}
+		startsAt, err := atClock(day, t.StartTime)
+		if err != nil || !startsAt.After(now) {
+			continue
+		}
+		endsAt, err := atClock(day, t.EndTime)
+		if err != nil {
+			continue
+		}
+		templateID := t.ID
+		slots = append(slots, &model.AppointmentSlot{
+			BranchID:   branchID,
+			TemplateID: &templateID,
+			StartsAt:   startsAt,
+			EndsAt:     endsAt,
+			Capacity:   t.Capacity,
+		})
+	}
+	return s.appointmentRepo.EnsureSlots(ctx, slots)
+}
+
+// applyCheckInDevice fills in the device fields of the order created at check-in
+func (s *AppointmentService) applyCheckInDevice(ctx context.Context, appointment *model.Appointment, req *model.CheckInAppointmentRequest, order *model.ServiceOrder) error {
+	deviceID := req.DeviceID
+	if deviceID == "" && appointment.DeviceID != nil {
+		deviceID = appointment.DeviceID.String()
+	}
+
+	if deviceID != "" {
+		device, err := s.getCustomerDevice(ctx, appointment.CustomerID, deviceID)
+		if err != nil {
+			return err
+		}
+		order.DeviceID = &device.ID
+		order.IPhoneModel = device.DeviceModel.Name
+		order.IPhoneColor = device.Color
+		order.IPhoneIMEI = device.IMEI
+		return nil
+	}
+
+	if req.IPhoneModel == "" || req.IPhoneColor == "" || req.IPhoneIMEI == "" {
+		return model.ErrAppointmentDeviceNeeded
+	}
+	order.IPhoneModel = req.IPhoneModel
+	order.IPhoneColor = req.IPhoneColor
+	order.IPhoneIMEI = req.IPhoneIMEI
+
+	// Link a registered device with the same IMEI so the history follows the phone
+	if s.deviceRepo.Available() && utils.IsValidIMEI(req.IPhoneIMEI) {
+		if device, err := s.deviceRepo.GetByCustomerIMEI(ctx, appointment.CustomerID, req.IPhoneIMEI); err == nil {
+			order.DeviceID = &device.ID
+		}
+	}
+	return nil
+}
+
+// getTemplate retrieves a slot template inside the caller's branch scope
+func (s *AppointmentService) getTemplate(ctx context.Context, id uuid.UUID) (*model.AppointmentSlotTemplate, error) {
+	if !s.appointmentRepo.Available() {
+		return nil, model.ErrSlotTemplateNotFound
+	}
+	template, err := s.appointmentRepo.GetTemplate(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrSlotTemplateNotFound
+		}
+		return nil, err
+	}
+	if !model.BranchScopeFromContext(ctx).Allows(template.BranchID) {
+		return nil, model.ErrSlotTemplateNotFound
+	}
+	return template, nil
+}
+
+// getBookableSlot retrieves a slot that has not started, lies inside the booking window
+// and belongs to an active branch
+func (s *AppointmentService) getBookableSlot(ctx context.Context, id string) (*model.AppointmentSlot, error) {
+	slotID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, model.ErrSlotNotFound
+	}
+	slot, err := s.appointmentRepo.GetSlot(ctx, slotID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrSlotNotFound
+		}
+		return nil, err
+	}
+
+	if !slot.StartsAt.After(time.Now()) || !withinBookingWindow(slot.StartsAt) {
+		return nil, model.ErrSlotUnavailable
+	}
+	branch, err := s.branchRepo.GetByID(ctx, slot.BranchID)
+	if err != nil || !branch.IsActive {
+		return nil, model.ErrSlotUnavailable
+	}
+	if slot.Booked >= slot.Capacity {
+		return nil, model.ErrSlotFull
+	}
+	return slot, nil
+}
+
+// getAppointment retrieves an appointment inside the caller's branch scope
+func (s *AppointmentService) getAppointment(ctx context.Context, id uuid.UUID) (*model.Appointment, error) {
+	if !s.appointmentRepo.Available() {
+		return nil, model.ErrAppointmentNotFound
+	}
+	appointment, err := s.appointmentRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrAppointmentNotFound
+		}
+		return nil, err
+	}
+	if !model.BranchScopeFromContext(ctx).Allows(appointment.BranchID) {
+		return nil, model.ErrAppointmentNotFound
+	}
+	return appointment, nil
+}
+
+// getOwnedAppointment retrieves an appointment of the customer
+func (s *AppointmentService) getOwnedAppointment(ctx context.Context, id, customerID uuid.UUID) (*model.Appointment, error) {
+	appointment, err := s.getAppointment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.CustomerID != customerID {
+		return nil, model.ErrAppointmentNotFound
+	}
+	return appointment, nil
+}
+
+// getCustomerDevice retrieves a registered device of the customer
+func (s *AppointmentService) getCustomerDevice(ctx context.Context, customerID uuid.UUID, id string) (*model.CustomerDevice, error) {
+	deviceID, err := uuid.Parse(id)
+	if err != nil || !s.deviceRepo.Available() {
+		return nil, model.ErrCustomerDeviceNotFound
+	}
+	device, err := s.deviceRepo.GetByID(ctx, deviceID)
+	if err != nil || device.CustomerID != customerID {
+		return nil, model.ErrCustomerDeviceNotFound
+	}
+	return device, nil
+}
+
+// applyTemplateRequest copies a template request onto a template
+func applyTemplateRequest(template *model.AppointmentSlotTemplate, req *model.AppointmentSlotTemplateRequest) error {
+	if req.EndTime <= req.StartTime {
+		return errors.New("end time must be after start time")
+	}
+
+	template.Weekday = *req.Weekday
+	template.StartTime = req.StartTime
+	template.EndTime = req.EndTime
+	template.Capacity = req.Capacity
+	if req.IsActive != nil {
+		template.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// withinBookingWindow reports whether t is no further ahead than customers may book
+func withinBookingWindow(t time.Time) bool {
+	days := defaultBookingDays
+	if config.Config != nil && config.Config.AppointmentBookingDays > 0 {
+		days = config.Config.AppointmentBookingDays
+	}
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	return !t.Before(today) && t.Before(today.AddDate(0, 0, days+1))
+}
+
+// atClock returns the moment on day at the given HH:MM clock time
+func atClock(day time.Time, clock string) (time.Time, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, time.Local), nil
+}
+
+// sameDay reports whether a and b fall on the same local date
+func sameDay(a, b time.Time) bool {
+	a, b = a.In(time.Local), b.In(time.Local)
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"service/internal/shared/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyTemplateRequest(t *testing.T) {
+	monday, active := 1, false
+	tests := []struct {
+		name    string
+		req     model.AppointmentSlotTemplateRequest
+		want    model.AppointmentSlotTemplate
+		wantErr bool
+	}{
+		{
+			name: "keeps active flag when not given",
+			req:  model.AppointmentSlotTemplateRequest{Weekday: &monday, StartTime: "09:00", EndTime: "10:00", Capacity: 3},
+			want: model.AppointmentSlotTemplate{Weekday: 1, StartTime: "09:00", EndTime: "10:00", Capacity: 3, IsActive: true},
+		},
+		{
+			name: "deactivates",
+			req:  model.AppointmentSlotTemplateRequest{Weekday: &monday, StartTime: "13:30", EndTime: "14:00", Capacity: 1, IsActive: &active},
+			want: model.AppointmentSlotTemplate{Weekday: 1, StartTime: "13:30", EndTime: "14:00", Capacity: 1, IsActive: false},
+		},
+		{
+			name:    "end before start",
+			req:     model.AppointmentSlotTemplateRequest{Weekday: &monday, StartTime: "10:00", EndTime: "09:00", Capacity: 1},
+			wantErr: true,
+		},
+		{
+			name:    "empty slot",
+			req:     model.AppointmentSlotTemplateRequest{Weekday: &monday, StartTime: "10:00", EndTime: "10:00", Capacity: 1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := model.AppointmentSlotTemplate{IsActive: true}
+			err := applyTemplateRequest(&template, &tt.req)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, template)
+		})
+	}
+}
+
+func TestAtClock(t *testing.T) {
+	day := time.Date(2026, time.March, 9, 17, 45, 12, 0, time.Local)
+	tests := []struct {
+		clock   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"09:00", time.Date(2026, time.March, 9, 9, 0, 0, 0, time.Local), false},
+		{"23:59", time.Date(2026, time.March, 9, 23, 59, 0, 0, time.Local), false},
+		{"00:00", time.Date(2026, time.March, 9, 0, 0, 0, 0, time.Local), false},
+		{"24:00", time.Time{}, true},
+		{"9am", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.clock, func(t *testing.T) {
+			got, err := atClock(day, tt.clock)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.True(t, tt.want.Equal(got), "got %s", got)
+		})
+	}
+}
+
+func TestSameDay(t *testing.T) {
+	morning := time.Date(2026, time.March, 9, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		name string
+		a, b time.Time
+		want bool
+	}{
+		{"same moment", morning, morning, true},
+		{"end of the day", morning, morning.Add(24*time.Hour - time.Second), true},
+		{"next day", morning, morning.AddDate(0, 0, 1), false},
+		{"same weekday a year later", morning, morning.AddDate(1, 0, 0), false},
+		{"other time zone", morning, morning.Add(time.Hour).UTC(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sameDay(tt.a, tt.b))
+		})
+	}
+}
+
+func TestWithinBookingWindow(t *testing.T) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"start of today", today, true},
+		{"last bookable day", today.AddDate(0, 0, defaultBookingDays).Add(23 * time.Hour), true},
+		{"beyond the window", today.AddDate(0, 0, defaultBookingDays+1), false},
+		{"yesterday", today.Add(-time.Minute), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, withinBookingWindow(tt.at))
+		})
+	}
+}
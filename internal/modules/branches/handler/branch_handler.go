@@ -34,6 +34,7 @@ func NewBranchHandler() *BranchHandler {
 // @Success 201 {object} model.APIResponse
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Router /branches [post]
 func (h *BranchHandler) CreateBranch(c *gin.Context) {
@@ -59,7 +60,11 @@ func (h *BranchHandler) CreateBranch(c *gin.Context) {
 
 	branch, err := h.branchService.CreateBranch(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+		statusCode := http.StatusInternalServerError
+		if err == model.ErrBranchCodeExists {
+			statusCode = http.StatusConflict
+		}
+		c.JSON(statusCode, model.CreateErrorResponse(
 			"branch_creation_failed",
 			err.Error(),
 			nil,
@@ -124,6 +129,7 @@ func (h *BranchHandler) GetBranch(c *gin.Context) {
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Router /branches/{id} [put]
 func (h *BranchHandler) UpdateBranch(c *gin.Context) {
@@ -161,8 +167,11 @@ func (h *BranchHandler) UpdateBranch(c *gin.Context) {
 	branch, err := h.branchService.UpdateBranch(c.Request.Context(), id, &req)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		if err == model.ErrBranchNotFound {
+		switch err {
+		case model.ErrBranchNotFound:
 			statusCode = http.StatusNotFound
+		case model.ErrBranchCodeExists:
+			statusCode = http.StatusConflict
 		}
 		c.JSON(statusCode, model.CreateErrorResponse(
 			"branch_update_failed",
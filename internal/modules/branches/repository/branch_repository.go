@@ -101,6 +101,33 @@ func (r *BranchRepository) Update(ctx context.Context, branch *model.Branch) err
 	return r.db.WithContext(ctx).Save(branch).Error
 }
 
+// CheckCodeExists checks if another branch already uses the code; codes compare case-insensitively
+func (r *BranchRepository) CheckCodeExists(ctx context.Context, code string, excludeID *uuid.UUID) (bool, error) {
+	code = strings.ToUpper(code)
+	if r.inMemory {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		for id, b := range r.branches {
+			if strings.ToUpper(b.Code) == code {
+				if excludeID != nil && id == *excludeID {
+					continue
+				}
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	var count int64
+	query := r.db.WithContext(ctx).Model(&model.Branch{}).Where("UPPER(code) = ?", code)
+
+	if excludeID != nil {
+		query = query.Where("id != ?", *excludeID)
+	}
+
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
 // Delete soft deletes a branch
 func (r *BranchRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	if r.inMemory {
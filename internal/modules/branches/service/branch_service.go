@@ -5,6 +5,7 @@ import (
 	"service/internal/cache"
 	"service/internal/modules/branches/repository"
 	"service/internal/shared/model"
+	"strings"
 
 	"github.com/google/uuid"
 )
@@ -28,6 +29,7 @@ func (s *BranchService) CreateBranch(ctx context.Context, req *model.BranchReque
 	// Create branch entity
 	branch := &model.Branch{
 		Name:      req.Name,
+		Code:      strings.ToUpper(req.Code),
 		Address:   req.Address,
 		City:      req.City,
 		Province:  req.Province,
@@ -37,6 +39,11 @@ func (s *BranchService) CreateBranch(ctx context.Context, req *model.BranchReque
 		IsActive:  true,
 	}
 
+	// The code ends up in order and invoice numbers, so no two branches may share it
+	if err := s.checkCodeAvailable(ctx, branch.Code, nil); err != nil {
+		return nil, err
+	}
+
 	// Save to database
 	if err := s.branchRepo.Create(ctx, branch); err != nil {
 		return nil, err
@@ -78,6 +85,12 @@ func (s *BranchService) UpdateBranch(ctx context.Context, id uuid.UUID, req *mod
 
 	// Update fields
 	branch.Name = req.Name
+	if req.Code != "" {
+		branch.Code = strings.ToUpper(req.Code)
+		if err := s.checkCodeAvailable(ctx, branch.Code, &branch.ID); err != nil {
+			return nil, err
+		}
+	}
 	branch.Address = req.Address
 	branch.City = req.City
 	branch.Province = req.Province
@@ -98,6 +111,21 @@ func (s *BranchService) UpdateBranch(ctx context.Context, id uuid.UUID, req *mod
 	return &response, nil
 }
 
+// checkCodeAvailable rejects a code another branch already uses; branches without a code are not checked
+func (s *BranchService) checkCodeAvailable(ctx context.Context, code string, excludeID *uuid.UUID) error {
+	if code == "" {
+		return nil
+	}
+	exists, err := s.branchRepo.CheckCodeExists(ctx, code, excludeID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return model.ErrBranchCodeExists
+	}
+	return nil
+}
+
 // DeleteBranch soft deletes a branch
 func (s *BranchService) DeleteBranch(ctx context.Context, id uuid.UUID) error {
 	// Check if branch exists
@@ -0,0 +1,561 @@
+package handler
+
+import (
+	"net/http"
+	"service/internal/modules/corporate/service"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CorporateHandler handles corporate account, work order and monthly invoice endpoints
+type CorporateHandler struct {
+	corporateService *service.CorporateService
+}
+
+// NewCorporateHandler creates a new corporate handler
+func NewCorporateHandler() *CorporateHandler {
+	return &CorporateHandler{
+		corporateService: service.NewCorporateService(),
+	}
+}
+
+// CreateAccount godoc
+// @Summary Create corporate account (admin)
+// @Description Register a company with its NPWP, credit limit and payment terms. A credit limit of 0 means no limit; payment terms default to net-30.
+// @Tags corporate
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.CorporateAccountRequest true "Corporate account"
+// @Success 201 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /admin/corporate-accounts [post]
+func (h *CorporateHandler) CreateAccount(c *gin.Context) {
+	var req model.CorporateAccountRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	account, err := h.corporateService.CreateAccount(c.Request.Context(), &req)
+	if err != nil {
+		respondCorporateError(c, "corporate_account_create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse(account, "Corporate account created successfully"))
+}
+
+// UpdateAccount godoc
+// @Summary Update corporate account (admin)
+// @Description Update the profile, credit limit or payment terms of a corporate account. Issued invoices keep their due dates.
+// @Tags corporate
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Corporate account ID"
+// @Param request body model.CorporateAccountRequest true "Corporate account"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /admin/corporate-accounts/{id} [put]
+func (h *CorporateHandler) UpdateAccount(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid corporate account ID")
+	if !ok {
+		return
+	}
+
+	var req model.CorporateAccountRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	account, err := h.corporateService.UpdateAccount(c.Request.Context(), id, &req)
+	if err != nil {
+		respondCorporateError(c, "corporate_account_update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(account, "Corporate account updated successfully"))
+}
+
+// ListAccounts godoc
+// @Summary List corporate accounts (admin)
+// @Description List the corporate accounts managed by the branches in scope
+// @Tags corporate
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} model.PaginatedResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /admin/corporate-accounts [get]
+func (h *CorporateHandler) ListAccounts(c *gin.Context) {
+	page, limit := pagination(c)
+
+	accounts, total, err := h.corporateService.ListAccounts(c.Request.Context(), page, limit)
+	if err != nil {
+		respondCorporateError(c, "corporate_accounts_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.PaginatedSuccessResponse(accounts, model.PaginationResponse{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Corporate accounts retrieved successfully"))
+}
+
+// SaveContact godoc
+// @Summary Authorize corporate contact (admin)
+// @Description Allow a user to send work orders for the corporate account, or update their position. A new primary contact replaces the previous one.
+// @Tags corporate
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Corporate account ID"
+// @Param request body model.CorporateContactRequest true "Contact"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /admin/corporate-accounts/{id}/contacts [post]
+func (h *CorporateHandler) SaveContact(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid corporate account ID")
+	if !ok {
+		return
+	}
+
+	var req model.CorporateContactRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	account, err := h.corporateService.SaveContact(c.Request.Context(), id, &req)
+	if err != nil {
+		respondCorporateError(c, "corporate_contact_save_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(account, "Corporate contact saved successfully"))
+}
+
+// RemoveContact godoc
+// @Summary Revoke corporate contact (admin)
+// @Description Revoke a user's authorization to send work orders for the corporate account
+// @Tags corporate
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Corporate account ID"
+// @Param user_id path string true "User ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /admin/corporate-accounts/{id}/contacts/{user_id} [delete]
+func (h *CorporateHandler) RemoveContact(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid corporate account ID")
+	if !ok {
+		return
+	}
+	userID, ok := parseID(c, "user_id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	if err := h.corporateService.RemoveContact(c.Request.Context(), id, userID); err != nil {
+		respondCorporateError(c, "corporate_contact_remove_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(nil, "Corporate contact removed successfully"))
+}
+
+// GenerateInvoice godoc
+// @Summary Invoice a month (admin)
+// @Description Invoice the corporate account for a past month now instead of waiting for the monthly job. Returns no data when the month is already invoiced or there is no completed work to bill.
+// @Tags corporate
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Corporate account ID"
+// @Param request body model.GenerateCorporateInvoiceRequest true "Invoice period"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /admin/corporate-accounts/{id}/invoices [post]
+func (h *CorporateHandler) GenerateInvoice(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid corporate account ID")
+	if !ok {
+		return
+	}
+
+	var req model.GenerateCorporateInvoiceRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	invoice, err := h.corporateService.GenerateInvoice(c.Request.Context(), id, req.Period)
+	if err != nil {
+		respondCorporateError(c, "corporate_invoice_generate_failed", err)
+		return
+	}
+	if invoice == nil {
+		c.JSON(http.StatusOK, model.SuccessResponse(nil, "Nothing to invoice for this period"))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(invoice, "Corporate invoice generated successfully"))
+}
+
+// PayInvoice godoc
+// @Summary Record corporate invoice payment (admin)
+// @Description Mark a corporate invoice as paid, freeing its amount on the account's credit limit
+// @Tags corporate
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Corporate invoice ID"
+// @Param request body model.PayCorporateInvoiceRequest true "Payment"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /admin/corporate-invoices/{id}/pay [post]
+func (h *CorporateHandler) PayInvoice(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid corporate invoice ID")
+	if !ok {
+		return
+	}
+
+	var req model.PayCorporateInvoiceRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	invoice, err := h.corporateService.PayInvoice(c.Request.Context(), id, &req)
+	if err != nil {
+		respondCorporateError(c, "corporate_invoice_pay_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(invoice, "Corporate invoice marked as paid"))
+}
+
+// ListMyAccounts godoc
+// @Summary List my corporate accounts
+// @Description List the corporate accounts the current user is an authorized contact of, with their credit usage
+// @Tags corporate
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Router /corporate/accounts [get]
+func (h *CorporateHandler) ListMyAccounts(c *gin.Context) {
+	userID, _, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	accounts, err := h.corporateService.ListMyAccounts(c.Request.Context(), userID)
+	if err != nil {
+		respondCorporateError(c, "corporate_accounts_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(accounts, "Corporate accounts retrieved successfully"))
+}
+
+// GetAccount godoc
+// @Summary Get corporate account
+// @Description Get a corporate account with its contacts and credit usage. Customers must be a contact of the account.
+// @Tags corporate
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Corporate account ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /corporate/accounts/{id} [get]
+func (h *CorporateHandler) GetAccount(c *gin.Context) {
+	userID, role, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseID(c, "id", "Invalid corporate account ID")
+	if !ok {
+		return
+	}
+
+	account, err := h.corporateService.GetAccount(c.Request.Context(), userID, role, id)
+	if err != nil {
+		respondCorporateError(c, "corporate_account_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(account, "Corporate account retrieved successfully"))
+}
+
+// CreateWorkOrder godoc
+// @Summary Create work order
+// @Description Send in several devices at once for a corporate account. Each device becomes a service order that progresses independently and is billed on the monthly invoice. Only authorized contacts may create work orders.
+// @Tags corporate
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.WorkOrderRequest true "Work order"
+// @Success 201 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /corporate/work-orders [post]
+func (h *CorporateHandler) CreateWorkOrder(c *gin.Context) {
+	userID, role, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req model.WorkOrderRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	workOrder, err := h.corporateService.CreateWorkOrder(c.Request.Context(), userID, role, &req)
+	if err != nil {
+		respondCorporateError(c, "work_order_create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse(workOrder, "Work order created successfully"))
+}
+
+// GetWorkOrder godoc
+// @Summary Get work order
+// @Description Get a work order with the progress of each device
+// @Tags corporate
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Work order ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /corporate/work-orders/{id} [get]
+func (h *CorporateHandler) GetWorkOrder(c *gin.Context) {
+	userID, role, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseID(c, "id", "Invalid work order ID")
+	if !ok {
+		return
+	}
+
+	workOrder, err := h.corporateService.GetWorkOrder(c.Request.Context(), userID, role, id)
+	if err != nil {
+		respondCorporateError(c, "work_order_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(workOrder, "Work order retrieved successfully"))
+}
+
+// ListWorkOrders godoc
+// @Summary List work orders
+// @Description List the work orders of a corporate account, newest first, with the progress of each device
+// @Tags corporate
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Corporate account ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} model.PaginatedResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /corporate/accounts/{id}/work-orders [get]
+func (h *CorporateHandler) ListWorkOrders(c *gin.Context) {
+	userID, role, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseID(c, "id", "Invalid corporate account ID")
+	if !ok {
+		return
+	}
+	page, limit := pagination(c)
+
+	workOrders, total, err := h.corporateService.ListWorkOrders(c.Request.Context(), userID, role, id, page, limit)
+	if err != nil {
+		respondCorporateError(c, "work_orders_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.PaginatedSuccessResponse(workOrders, model.PaginationResponse{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Work orders retrieved successfully"))
+}
+
+// ListInvoices godoc
+// @Summary List corporate invoices
+// @Description List the monthly invoices of a corporate account, newest period first. Unpaid invoices past their due date are reported as overdue.
+// @Tags corporate
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Corporate account ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /corporate/accounts/{id}/invoices [get]
+func (h *CorporateHandler) ListInvoices(c *gin.Context) {
+	userID, role, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseID(c, "id", "Invalid corporate account ID")
+	if !ok {
+		return
+	}
+
+	invoices, err := h.corporateService.ListInvoices(c.Request.Context(), userID, role, id)
+	if err != nil {
+		respondCorporateError(c, "corporate_invoices_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(invoices, "Corporate invoices retrieved successfully"))
+}
+
+// GetInvoice godoc
+// @Summary Get corporate invoice
+// @Description Get a monthly corporate invoice with one line per completed device
+// @Tags corporate
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Corporate invoice ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /corporate/invoices/{id} [get]
+func (h *CorporateHandler) GetInvoice(c *gin.Context) {
+	userID, role, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseID(c, "id", "Invalid corporate invoice ID")
+	if !ok {
+		return
+	}
+
+	invoice, err := h.corporateService.GetInvoice(c.Request.Context(), userID, role, id)
+	if err != nil {
+		respondCorporateError(c, "corporate_invoice_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(invoice, "Corporate invoice retrieved successfully"))
+}
+
+// currentUser reads the authenticated user and role from the context
+func currentUser(c *gin.Context) (uuid.UUID, model.UserRole, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, model.CreateErrorResponse(
+			"unauthorized",
+			"User ID not found in context",
+			nil,
+		))
+		return uuid.Nil, "", false
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+			"internal_error",
+			"Invalid user ID type",
+			nil,
+		))
+		return uuid.Nil, "", false
+	}
+
+	userRole, _ := c.Get("user_role")
+	role, _ := userRole.(model.UserRole)
+	return userUUID, role, true
+}
+
+// parseID reads a UUID path parameter
+func parseID(c *gin.Context, param, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse("invalid_id", message, nil))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// pagination reads the page and limit query parameters
+func pagination(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	return page, limit
+}
+
+// bindRequest binds, sanitizes and validates a JSON payload
+func bindRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return false
+	}
+
+	utils.SanitizeStructStrings(req)
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return false
+	}
+	return true
+}
+
+// respondCorporateError maps corporate account errors to HTTP responses
+func respondCorporateError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case model.ErrInvalidInvoicePeriod:
+		status = http.StatusBadRequest
+	case model.ErrForbidden, model.ErrNotCorporateContact:
+		status = http.StatusForbidden
+	case model.ErrCorporateAccountNotFound, model.ErrCorporateContactNotFound, model.ErrWorkOrderNotFound,
+		model.ErrCorporateInvoiceNotFound, model.ErrBranchNotFound, model.ErrUserNotFound:
+		status = http.StatusNotFound
+	case model.ErrCorporateAccountExists, model.ErrCorporateAccountInactive, model.ErrCreditLimitExceeded,
+		model.ErrCorporateInvoicePaid:
+		status = http.StatusConflict
+	}
+	c.JSON(status, model.CreateErrorResponse(code, err.Error(), nil))
+}
@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"context"
+	numberingRepo "service/internal/modules/numbering/repository"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// lineAmount is the billable amount of a work order line before tax
+const lineAmount = "CASE WHEN o.actual_cost > 0 THEN o.actual_cost ELSE o.estimated_cost END"
+
+// CompletedLine is a completed work order line, or a cancelled one that owes a
+// cancellation fee, together with the moment it was completed or cancelled
+type CompletedLine struct {
+	model.ServiceOrder
+	CompletedAt     time.Time
+	CancellationFee *float64 // set for cancelled lines
+}
+
+// CorporateRepository handles corporate accounts, their contacts, work orders and invoices
+type CorporateRepository struct {
+	db *gorm.DB
+}
+
+// NewCorporateRepository creates a new corporate repository
+func NewCorporateRepository() *CorporateRepository {
+	return &CorporateRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *CorporateRepository) Available() bool {
+	return r.db != nil
+}
+
+// CreateAccount creates a new corporate account
+func (r *CorporateRepository) CreateAccount(ctx context.Context, account *model.CorporateAccount) error {
+	return r.db.WithContext(ctx).Create(account).Error
+}
+
+// UpdateAccount updates a corporate account
+func (r *CorporateRepository) UpdateAccount(ctx context.Context, account *model.CorporateAccount) error {
+	return r.db.WithContext(ctx).Save(account).Error
+}
+
+// GetAccount retrieves a corporate account by ID
+func (r *CorporateRepository) GetAccount(ctx context.Context, id uuid.UUID) (*model.CorporateAccount, error) {
+	var account model.CorporateAccount
+	if err := r.db.WithContext(ctx).First(&account, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// ListAccounts retrieves the corporate accounts managed by the branches the caller may see
+func (r *CorporateRepository) ListAccounts(ctx context.Context, offset, limit int) ([]*model.CorporateAccount, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.CorporateAccount{}).
+		Scopes(model.ScopeByBranch(ctx, "branch_id"))
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var accounts []*model.CorporateAccount
+	err := query.Order("company_name ASC").Offset(offset).Limit(limit).Find(&accounts).Error
+	return accounts, total, err
+}
+
+// ListActiveAccounts retrieves every active corporate account
+func (r *CorporateRepository) ListActiveAccounts(ctx context.Context) ([]*model.CorporateAccount, error) {
+	var accounts []*model.CorporateAccount
+	err := r.db.WithContext(ctx).Where("is_active = ?", true).Find(&accounts).Error
+	return accounts, err
+}
+
+// ListAccountsForUser retrieves the corporate accounts the user is a contact of
+func (r *CorporateRepository) ListAccountsForUser(ctx context.Context, userID uuid.UUID) ([]*model.CorporateAccount, error) {
+	var accounts []*model.CorporateAccount
+	err := r.db.WithContext(ctx).
+		Joins("JOIN corporate_contacts AS c ON c.account_id = corporate_accounts.id").
+		Where("c.user_id = ?", userID).
+		Order("corporate_accounts.company_name ASC").
+		Find(&accounts).Error
+	return accounts, err
+}
+
+// NPWPExists checks whether another account is registered with the NPWP
+func (r *CorporateRepository) NPWPExists(ctx context.Context, npwp string, excludeID *uuid.UUID) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&model.CorporateAccount{}).Where("npwp = ?", npwp)
+	if excludeID != nil {
+		query = query.Where("id <> ?", *excludeID)
+	}
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// ListContacts retrieves the authorized contacts of an account, primary contacts first
+func (r *CorporateRepository) ListContacts(ctx context.Context, accountID uuid.UUID) ([]model.CorporateContactResponse, error) {
+	var contacts []model.CorporateContactResponse
+	err := r.db.WithContext(ctx).
+		Table("corporate_contacts AS c").
+		Select("c.user_id, u.full_name, u.email, u.phone, c.position, c.is_primary").
+		Joins("JOIN users AS u ON u.id = c.user_id AND u.deleted_at IS NULL").
+		Where("c.account_id = ?", accountID).
+		Order("c.is_primary DESC, u.full_name ASC").
+		Scan(&contacts).Error
+	return contacts, err
+}
+
+// IsContact checks whether the user is an authorized contact of the account
+func (r *CorporateRepository) IsContact(ctx context.Context, accountID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.CorporateContact{}).
+		Where("account_id = ? AND user_id = ?", accountID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// SaveContact authorizes a contact or updates their details. A new primary contact
+// replaces the previous one.
+func (r *CorporateRepository) SaveContact(ctx context.Context, contact *model.CorporateContact) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if contact.IsPrimary {
+			if err := tx.Model(&model.CorporateContact{}).
+				Where("account_id = ? AND user_id <> ?", contact.AccountID, contact.UserID).
+				Update("is_primary", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "account_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"position", "is_primary", "updated_at"}),
+		}).Create(contact).Error
+	})
+}
+
+// DeleteContact revokes the authorization of a contact
+func (r *CorporateRepository) DeleteContact(ctx context.Context, accountID, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("account_id = ? AND user_id = ?", accountID, userID).
+		Delete(&model.CorporateContact{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrCorporateContactNotFound
+	}
+	return nil
+}
+
+// CreateWorkOrder stores a work order together with its device lines in one transaction
+func (r *CorporateRepository) CreateWorkOrder(ctx context.Context, workOrder *model.WorkOrder, lines []*model.ServiceOrder) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(workOrder).Error; err != nil {
+			return err
+		}
+		for _, line := range lines {
+			line.WorkOrderID = &workOrder.ID
+		}
+		return tx.Create(&lines).Error
+	})
+}
+
+// GetWorkOrder retrieves a work order by ID
+func (r *CorporateRepository) GetWorkOrder(ctx context.Context, id uuid.UUID) (*model.WorkOrder, error) {
+	var workOrder model.WorkOrder
+	if err := r.db.WithContext(ctx).First(&workOrder, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &workOrder, nil
+}
+
+// ListWorkOrders retrieves the work orders of an account, newest first
+func (r *CorporateRepository) ListWorkOrders(ctx context.Context, accountID uuid.UUID, offset, limit int) ([]*model.WorkOrder, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.WorkOrder{}).Where("account_id = ?", accountID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var workOrders []*model.WorkOrder
+	err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&workOrders).Error
+	return workOrders, total, err
+}
+
+// ListLines retrieves the device lines of work orders in line order
+func (r *CorporateRepository) ListLines(ctx context.Context, workOrderIDs []uuid.UUID) ([]*model.ServiceOrder, error) {
+	var lines []*model.ServiceOrder
+	if len(workOrderIDs) == 0 {
+		return lines, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("work_order_id IN ?", workOrderIDs).
+		Order("order_number ASC").
+		Find(&lines).Error
+	return lines, err
+}
+
+// CreditUsage returns the unpaid invoiced amount of an account and the pre-tax amount of
+// its work that is not invoiced yet
+func (r *CorporateRepository) CreditUsage(ctx context.Context, accountID uuid.UUID) (unpaidInvoices, uninvoicedWork float64, err error) {
+	err = r.db.WithContext(ctx).Model(&model.CorporateInvoice{}).
+		Select("COALESCE(SUM(total), 0)").
+		Where("account_id = ? AND status = ?", accountID, model.CorporateInvoiceStatusIssued).
+		Scan(&unpaidInvoices).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	err = r.db.WithContext(ctx).
+		Table("service_orders AS o").
+		Select("COALESCE(SUM(CASE WHEN o.status = ? THEN COALESCE(c.fee, 0) ELSE "+lineAmount+" END), 0)", model.StatusCancelled).
+		Joins("LEFT JOIN order_cancellations AS c ON c.order_id = o.id").
+		Where("o.corporate_account_id = ? AND o.deleted_at IS NULL", accountID).
+		Where("NOT EXISTS (SELECT 1 FROM corporate_invoice_lines AS l WHERE l.order_id = o.id)").
+		Scan(&uninvoicedWork).Error
+	return unpaidInvoices, uninvoicedWork, err
+}
+
+// ListUninvoicedCompleted retrieves the completed lines of an account, and the cancelled
+// lines that owe a cancellation fee, that were completed or cancelled before the given
+// moment and are not on any invoice yet
+func (r *CorporateRepository) ListUninvoicedCompleted(ctx context.Context, accountID uuid.UUID, before time.Time) ([]CompletedLine, error) {
+	var lines []CompletedLine
+	err := r.db.WithContext(ctx).
+		Table("service_orders AS o").
+		Select("o.*, e.completed_at, c.fee AS cancellation_fee").
+		Joins("JOIN (SELECT order_id, MAX(created_at) AS completed_at FROM order_status_events WHERE status IN ? GROUP BY order_id) AS e ON e.order_id = o.id",
+			[]model.OrderStatus{model.StatusCompleted, model.StatusCancelled}).
+		Joins("LEFT JOIN order_cancellations AS c ON c.order_id = o.id").
+		Where("o.corporate_account_id = ? AND o.deleted_at IS NULL", accountID).
+		Where("o.status = ? OR (o.status = ? AND c.fee > 0)", model.StatusCompleted, model.StatusCancelled).
+		Where("e.completed_at < ?", before).
+		Where("NOT EXISTS (SELECT 1 FROM corporate_invoice_lines AS l WHERE l.order_id = o.id)").
+		Order("e.completed_at ASC").
+		Scan(&lines).Error
+	return lines, err
+}
+
+// InvoiceExists checks whether the account was already invoiced for the period
+func (r *CorporateRepository) InvoiceExists(ctx context.Context, accountID uuid.UUID, periodStart time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.CorporateInvoice{}).
+		Where("account_id = ? AND period_start = ?", accountID, periodStart).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CreateInvoice stores an invoice together with its lines in one transaction, under the
+// next invoice number of the scheme. Corporate invoices share the invoice counter of the
+// branch, so the invoice numbers of a branch stay unique and gapless.
+func (r *CorporateRepository) CreateInvoice(ctx context.Context, invoice *model.CorporateInvoice, scheme *model.NumberScheme) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		year := invoice.IssuedAt.Year()
+		seq, err := numberingRepo.Allocate(tx, scheme.BranchID, model.NumberingKindInvoice, year)
+		if err != nil {
+			return err
+		}
+		invoice.InvoiceNumber = scheme.Render(year, seq)
+		return tx.Create(invoice).Error
+	})
+}
+
+// GetInvoice retrieves an invoice with its lines
+func (r *CorporateRepository) GetInvoice(ctx context.Context, id uuid.UUID) (*model.CorporateInvoice, error) {
+	var invoice model.CorporateInvoice
+	err := r.db.WithContext(ctx).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("completed_at ASC") }).
+		First(&invoice, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// ListInvoices retrieves the invoices of an account, newest period first
+func (r *CorporateRepository) ListInvoices(ctx context.Context, accountID uuid.UUID) ([]*model.CorporateInvoice, error) {
+	var invoices []*model.CorporateInvoice
+	err := r.db.WithContext(ctx).
+		Where("account_id = ?", accountID).
+		Order("period_start DESC").
+		Find(&invoices).Error
+	return invoices, err
+}
+
+// MarkInvoicePaid records the payment of an issued invoice. It returns false when the
+// invoice was already paid.
+func (r *CorporateRepository) MarkInvoicePaid(ctx context.Context, id uuid.UUID, paymentRef string, paidAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.CorporateInvoice{}).
+		Where("id = ? AND status = ?", id, model.CorporateInvoiceStatusIssued).
+		Updates(map[string]interface{}{
+			"status":      model.CorporateInvoiceStatusPaid,
+			"payment_ref": paymentRef,
+			"paid_at":     paidAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
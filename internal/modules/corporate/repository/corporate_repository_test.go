@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"service/internal/shared/database/dbtest"
+	"service/internal/shared/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateInvoiceNumbering(t *testing.T) {
+	insertFailed := errors.New("insert failed")
+
+	tests := []struct {
+		name       string
+		insert     error
+		wantNumber string
+	}{
+		{"number taken with the invoice", nil, "JKT-INV-2026-000007"},
+		{"failed invoice gives the number back", insertFailed, "JKT-INV-2026-000007"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := dbtest.Mock(t)
+			repo := &CorporateRepository{db: db}
+			branchID := uuid.New()
+			scheme := &model.NumberScheme{BranchID: branchID, Kind: model.NumberingKindInvoice, BranchCode: "JKT", Format: model.DefaultInvoiceNumberFormat}
+			invoice := &model.CorporateInvoice{
+				AccountID: uuid.New(),
+				IssuedAt:  time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC),
+				Status:    model.CorporateInvoiceStatusIssued,
+			}
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(`INSERT INTO number_sequences`).
+				WithArgs(branchID, model.NumberingKindInvoice, 2026).
+				WillReturnRows(sqlmock.NewRows([]string{"last_value"}).AddRow(7))
+			insert := mock.ExpectQuery(`INSERT INTO "corporate_invoices"`)
+			if tt.insert != nil {
+				insert.WillReturnError(tt.insert)
+				mock.ExpectRollback()
+			} else {
+				insert.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+				mock.ExpectCommit()
+			}
+
+			err := repo.CreateInvoice(context.Background(), invoice, scheme)
+			assert.Equal(t, tt.insert, err)
+			assert.Equal(t, tt.wantNumber, invoice.InvoiceNumber)
+			assert.NoError(t, mock.ExpectationsWereMet(), "the number is allocated inside the invoice transaction")
+		})
+	}
+}
@@ -0,0 +1,653 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	branchRepo "service/internal/modules/branches/repository"
+	"service/internal/modules/corporate/repository"
+	notificationService "service/internal/modules/notification/service"
+	numberingService "service/internal/modules/numbering/service"
+	orderRepo "service/internal/modules/orders/repository"
+	slaService "service/internal/modules/sla/service"
+	userRepo "service/internal/modules/users/repository"
+	"service/internal/shared/config"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// defaultPaymentTermDays is the payment term used when no configuration is loaded (net-30)
+const defaultPaymentTermDays = 30
+
+// CorporateService handles corporate accounts, their multi-device work orders and
+// consolidated monthly invoicing
+type CorporateService struct {
+	corporateRepo *repository.CorporateRepository
+	branchRepo    *branchRepo.BranchRepository
+	userRepo      *userRepo.UserRepository
+	eventRepo     *orderRepo.StatusEventRepository
+
+	slaService          *slaService.SLAService
+	notificationService *notificationService.NotificationService
+	numberingService    *numberingService.NumberingService
+}
+
+// NewCorporateService creates a new corporate service
+func NewCorporateService() *CorporateService {
+	return &CorporateService{
+		corporateRepo: repository.NewCorporateRepository(),
+		branchRepo:    branchRepo.NewBranchRepository(),
+		userRepo:      userRepo.NewUserRepository(),
+		eventRepo:     orderRepo.NewStatusEventRepository(),
+
+		slaService:          slaService.NewSLAService(),
+		notificationService: notificationService.NewNotificationService(),
+		numberingService:    numberingService.NewNumberingService(),
+	}
+}
+
+// CreateAccount registers a corporate account managed by a branch
+func (s *CorporateService) CreateAccount(ctx context.Context, req *model.CorporateAccountRequest) (*model.CorporateAccountResponse, error) {
+	if !s.corporateRepo.Available() {
+		return nil, errors.New("corporate accounts are not available")
+	}
+
+	account := &model.CorporateAccount{IsActive: true}
+	if err := s.applyAccountRequest(ctx, account, req); err != nil {
+		return nil, err
+	}
+	exists, err := s.corporateRepo.NPWPExists(ctx, account.NPWP, nil)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, model.ErrCorporateAccountExists
+	}
+
+	if err := s.corporateRepo.CreateAccount(ctx, account); err != nil {
+		return nil, err
+	}
+	return s.accountResponse(ctx, account)
+}
+
+// UpdateAccount changes the profile, credit limit or payment terms of an account. Issued
+// invoices keep their due dates.
+func (s *CorporateService) UpdateAccount(ctx context.Context, id uuid.UUID, req *model.CorporateAccountRequest) (*model.CorporateAccountResponse, error) {
+	account, err := s.managedAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyAccountRequest(ctx, account, req); err != nil {
+		return nil, err
+	}
+	exists, err := s.corporateRepo.NPWPExists(ctx, account.NPWP, &account.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, model.ErrCorporateAccountExists
+	}
+
+	if err := s.corporateRepo.UpdateAccount(ctx, account); err != nil {
+		return nil, err
+	}
+	return s.accountResponse(ctx, account)
+}
+
+// GetAccount retrieves an account with its contacts and credit usage
+func (s *CorporateService) GetAccount(ctx context.Context, userID uuid.UUID, role model.UserRole, id uuid.UUID) (*model.CorporateAccountResponse, error) {
+	account, err := s.accessibleAccount(ctx, userID, role, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.accountResponse(ctx, account)
+}
+
+// ListAccounts retrieves the accounts managed by the branches the caller may see
+func (s *CorporateService) ListAccounts(ctx context.Context, page, limit int) ([]*model.CorporateAccount, int64, error) {
+	if !s.corporateRepo.Available() {
+		return []*model.CorporateAccount{}, 0, nil
+	}
+	return s.corporateRepo.ListAccounts(ctx, (page-1)*limit, limit)
+}
+
+// ListMyAccounts retrieves the accounts the user is an authorized contact of
+func (s *CorporateService) ListMyAccounts(ctx context.Context, userID uuid.UUID) ([]*model.CorporateAccountResponse, error) {
+	result := []*model.CorporateAccountResponse{}
+	if !s.corporateRepo.Available() {
+		return result, nil
+	}
+
+	accounts, err := s.corporateRepo.ListAccountsForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, account := range accounts {
+		response, err := s.accountResponse(ctx, account)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, response)
+	}
+	return result, nil
+}
+
+// SaveContact authorizes a user to send work orders for the account
+func (s *CorporateService) SaveContact(ctx context.Context, accountID uuid.UUID, req *model.CorporateContactRequest) (*model.CorporateAccountResponse, error) {
+	account, err := s.managedAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, model.ErrUserNotFound
+	}
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, model.ErrUserNotFound
+	}
+
+	contact := &model.CorporateContact{
+		AccountID: account.ID,
+		UserID:    userID,
+		Position:  req.Position,
+		IsPrimary: req.IsPrimary,
+	}
+	if err := s.corporateRepo.SaveContact(ctx, contact); err != nil {
+		return nil, err
+	}
+	return s.accountResponse(ctx, account)
+}
+
+// RemoveContact revokes the authorization of a contact. Their work orders stay with the account.
+func (s *CorporateService) RemoveContact(ctx context.Context, accountID, userID uuid.UUID) error {
+	account, err := s.managedAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	return s.corporateRepo.DeleteContact(ctx, account.ID, userID)
+}
+
+// CreateWorkOrder creates a work order with one service order per device. The lines go
+// through the normal order flow independently and are billed on the monthly invoice.
+func (s *CorporateService) CreateWorkOrder(ctx context.Context, userID uuid.UUID, role model.UserRole, req *model.WorkOrderRequest) (*model.WorkOrderResponse, error) {
+	accountID, err := uuid.Parse(req.AccountID)
+	if err != nil {
+		return nil, model.ErrCorporateAccountNotFound
+	}
+	account, err := s.accessibleAccount(ctx, userID, role, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if !account.IsActive {
+		return nil, model.ErrCorporateAccountInactive
+	}
+
+	// Lines belong to the contact who sent them in, so staff must act as a contact too
+	isContact, err := s.corporateRepo.IsContact(ctx, account.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isContact {
+		return nil, model.ErrNotCorporateContact
+	}
+
+	branchID, err := uuid.Parse(req.BranchID)
+	if err != nil {
+		return nil, model.ErrBranchNotFound
+	}
+	if _, err := s.branchRepo.GetByID(ctx, branchID); err != nil {
+		return nil, model.ErrBranchNotFound
+	}
+
+	estimated := 0.0
+	for _, line := range req.Lines {
+		estimated += line.EstimatedCost
+	}
+	if account.CreditLimit > 0 {
+		used, err := s.creditUsed(ctx, account.ID)
+		if err != nil {
+			return nil, err
+		}
+		if used+utils.CalculateAmountWithTax(estimated) > account.CreditLimit {
+			return nil, model.ErrCreditLimitExceeded
+		}
+	}
+
+	workOrder := &model.WorkOrder{
+		WorkOrderNumber: generateWorkOrderNumber(),
+		AccountID:       account.ID,
+		BranchID:        branchID,
+		RequestedBy:     userID,
+		PurchaseOrder:   req.PurchaseOrder,
+		PickupAddress:   req.PickupAddress,
+		PickupLatitude:  req.PickupLatitude,
+		PickupLongitude: req.PickupLongitude,
+		Notes:           req.Notes,
+	}
+	lines := make([]*model.ServiceOrder, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		orderNumber, err := s.numberingService.NextOrderNumber(ctx, branchID)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, &model.ServiceOrder{
+			OrderNumber:        orderNumber,
+			CustomerID:         userID,
+			BranchID:           branchID,
+			IPhoneModel:        line.IPhoneModel,
+			IPhoneColor:        line.IPhoneColor,
+			IPhoneIMEI:         line.IPhoneIMEI,
+			ServiceType:        line.ServiceType,
+			Description:        line.Description,
+			PickupAddress:      req.PickupAddress,
+			PickupLatitude:     req.PickupLatitude,
+			PickupLongitude:    req.PickupLongitude,
+			Status:             model.StatusPendingPickup,
+			EstimatedCost:      line.EstimatedCost,
+			CorporateAccountID: &account.ID,
+		})
+	}
+
+	if err := s.corporateRepo.CreateWorkOrder(ctx, workOrder, lines); err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		if s.eventRepo.Available() {
+			_ = s.eventRepo.Record(ctx, line.ID, line.Status)
+		}
+		s.slaService.OnStatusChange(ctx, line)
+	}
+	return workOrderResponse(workOrder, lines), nil
+}
+
+// GetWorkOrder retrieves a work order with the progress of each device
+func (s *CorporateService) GetWorkOrder(ctx context.Context, userID uuid.UUID, role model.UserRole, id uuid.UUID) (*model.WorkOrderResponse, error) {
+	if !s.corporateRepo.Available() {
+		return nil, model.ErrWorkOrderNotFound
+	}
+	workOrder, err := s.corporateRepo.GetWorkOrder(ctx, id)
+	if err != nil {
+		return nil, model.ErrWorkOrderNotFound
+	}
+	if _, err := s.accessibleAccount(ctx, userID, role, workOrder.AccountID); err != nil {
+		return nil, err
+	}
+
+	lines, err := s.corporateRepo.ListLines(ctx, []uuid.UUID{workOrder.ID})
+	if err != nil {
+		return nil, err
+	}
+	return workOrderResponse(workOrder, lines), nil
+}
+
+// ListWorkOrders retrieves the work orders of an account with the progress of each device
+func (s *CorporateService) ListWorkOrders(ctx context.Context, userID uuid.UUID, role model.UserRole, accountID uuid.UUID, page, limit int) ([]*model.WorkOrderResponse, int64, error) {
+	account, err := s.accessibleAccount(ctx, userID, role, accountID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	workOrders, total, err := s.corporateRepo.ListWorkOrders(ctx, account.ID, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	ids := make([]uuid.UUID, len(workOrders))
+	for i, workOrder := range workOrders {
+		ids[i] = workOrder.ID
+	}
+	lines, err := s.corporateRepo.ListLines(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	linesByWorkOrder := make(map[uuid.UUID][]*model.ServiceOrder)
+	for _, line := range lines {
+		linesByWorkOrder[*line.WorkOrderID] = append(linesByWorkOrder[*line.WorkOrderID], line)
+	}
+
+	result := make([]*model.WorkOrderResponse, 0, len(workOrders))
+	for _, workOrder := range workOrders {
+		result = append(result, workOrderResponse(workOrder, linesByWorkOrder[workOrder.ID]))
+	}
+	return result, total, nil
+}
+
+// GenerateInvoice bills all work of the account completed before the end of the month
+// that is not invoiced yet, due after the account's payment terms. It returns nil when
+// the month is already invoiced or there is nothing to bill.
+func (s *CorporateService) GenerateInvoice(ctx context.Context, accountID uuid.UUID, period string) (*model.CorporateInvoice, error) {
+	account, err := s.managedAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	month, err := time.ParseInLocation("2006-01", period, time.Local)
+	if err != nil {
+		return nil, model.ErrInvalidInvoicePeriod
+	}
+	// Only months that are over can be invoiced
+	if month.AddDate(0, 1, 0).After(time.Now()) {
+		return nil, model.ErrInvalidInvoicePeriod
+	}
+	return s.invoiceMonth(ctx, account, month)
+}
+
+// GenerateDueInvoices invoices the previous month for every active account. Safe to run
+// repeatedly and on several instances at once.
+func (s *CorporateService) GenerateDueInvoices(ctx context.Context) error {
+	if !s.corporateRepo.Available() {
+		return nil
+	}
+
+	accounts, err := s.corporateRepo.ListActiveAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	lastMonth := time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, time.Local)
+	for _, account := range accounts {
+		if _, err := s.invoiceMonth(ctx, account, lastMonth); err != nil {
+			log.Printf("Failed to invoice corporate account %s for %s: %v", account.ID, lastMonth.Format("2006-01"), err)
+		}
+	}
+	return nil
+}
+
+// ListInvoices retrieves the invoices of an account
+func (s *CorporateService) ListInvoices(ctx context.Context, userID uuid.UUID, role model.UserRole, accountID uuid.UUID) ([]*model.CorporateInvoice, error) {
+	account, err := s.accessibleAccount(ctx, userID, role, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := s.corporateRepo.ListInvoices(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, invoice := range invoices {
+		invoice.Status = invoice.StatusAt(now)
+	}
+	return invoices, nil
+}
+
+// GetInvoice retrieves an invoice with its lines
+func (s *CorporateService) GetInvoice(ctx context.Context, userID uuid.UUID, role model.UserRole, id uuid.UUID) (*model.CorporateInvoice, error) {
+	if !s.corporateRepo.Available() {
+		return nil, model.ErrCorporateInvoiceNotFound
+	}
+	invoice, err := s.corporateRepo.GetInvoice(ctx, id)
+	if err != nil {
+		return nil, model.ErrCorporateInvoiceNotFound
+	}
+	if _, err := s.accessibleAccount(ctx, userID, role, invoice.AccountID); err != nil {
+		return nil, err
+	}
+	invoice.Status = invoice.StatusAt(time.Now())
+	return invoice, nil
+}
+
+// PayInvoice records the payment of an invoice, freeing its amount on the credit limit
+func (s *CorporateService) PayInvoice(ctx context.Context, id uuid.UUID, req *model.PayCorporateInvoiceRequest) (*model.CorporateInvoice, error) {
+	if !s.corporateRepo.Available() {
+		return nil, model.ErrCorporateInvoiceNotFound
+	}
+	invoice, err := s.corporateRepo.GetInvoice(ctx, id)
+	if err != nil {
+		return nil, model.ErrCorporateInvoiceNotFound
+	}
+	if _, err := s.managedAccount(ctx, invoice.AccountID); err != nil {
+		return nil, err
+	}
+
+	paidAt := time.Now()
+	if req.PaidAt != nil {
+		paidAt = *req.PaidAt
+	}
+	paid, err := s.corporateRepo.MarkInvoicePaid(ctx, invoice.ID, req.PaymentRef, paidAt)
+	if err != nil {
+		return nil, err
+	}
+	if !paid {
+		return nil, model.ErrCorporateInvoicePaid
+	}
+	return s.corporateRepo.GetInvoice(ctx, invoice.ID)
+}
+
+// invoiceMonth bills the uninvoiced work completed before the end of the month
+func (s *CorporateService) invoiceMonth(ctx context.Context, account *model.CorporateAccount, month time.Time) (*model.CorporateInvoice, error) {
+	periodEnd := month.AddDate(0, 1, 0)
+	exists, err := s.corporateRepo.InvoiceExists(ctx, account.ID, month)
+	if err != nil || exists {
+		return nil, err
+	}
+
+	completed, err := s.corporateRepo.ListUninvoicedCompleted(ctx, account.ID, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+	if len(completed) == 0 {
+		return nil, nil
+	}
+
+	scheme, err := s.numberingService.InvoiceScheme(ctx, account.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invoice := &model.CorporateInvoice{
+		AccountID:   account.ID,
+		PeriodStart: month,
+		PeriodEnd:   periodEnd,
+		IssuedAt:    now,
+		DueAt:       now.AddDate(0, 0, paymentTermDays(account)),
+		Status:      model.CorporateInvoiceStatusIssued,
+	}
+	for _, line := range completed {
+		amount := line.ActualCost
+		if amount <= 0 {
+			amount = line.EstimatedCost
+		}
+		description := fmt.Sprintf("%s %s (%s) - %s", line.IPhoneModel, line.IPhoneColor, line.IPhoneIMEI, line.ServiceType)
+		if line.CancellationFee != nil {
+			amount = *line.CancellationFee
+			description = "Cancellation fee: " + description
+		}
+		tax := utils.CalculatePPN(amount)
+		invoice.Lines = append(invoice.Lines, model.CorporateInvoiceLine{
+			OrderID:     line.ID,
+			WorkOrderID: line.WorkOrderID,
+			OrderNumber: line.OrderNumber,
+			Description: description,
+			CompletedAt: line.CompletedAt,
+			Amount:      amount,
+			TaxAmount:   tax,
+		})
+		invoice.Subtotal += amount
+		invoice.TaxAmount += tax
+	}
+	invoice.Total = invoice.Subtotal + invoice.TaxAmount
+
+	if err := s.corporateRepo.CreateInvoice(ctx, invoice, scheme); err != nil {
+		return nil, err
+	}
+	s.notifyInvoice(ctx, account, invoice)
+	return invoice, nil
+}
+
+// notifyInvoice emails the account's contacts about a new invoice. Failures are logged only.
+func (s *CorporateService) notifyInvoice(ctx context.Context, account *model.CorporateAccount, invoice *model.CorporateInvoice) {
+	contacts, err := s.corporateRepo.ListContacts(ctx, account.ID)
+	if err != nil {
+		log.Printf("Failed to load contacts of corporate account %s: %v", account.ID, err)
+		return
+	}
+
+	message := fmt.Sprintf("Invoice %s for %s covering %d device(s) totals Rp %.0f and is due on %s.",
+		invoice.InvoiceNumber, invoice.PeriodStart.Format("January 2006"), len(invoice.Lines),
+		invoice.Total, invoice.DueAt.Format("02 Jan 2006"))
+	for _, contact := range contacts {
+		if _, err := s.notificationService.SendNotification(ctx, &model.NotificationRequest{
+			UserID:  contact.UserID.String(),
+			Type:    model.NotificationTypeEmail,
+			Title:   "Monthly Service Invoice " + account.CompanyName,
+			Message: message,
+		}); err != nil {
+			log.Printf("Failed to send invoice %s to %s: %v", invoice.InvoiceNumber, contact.UserID, err)
+		}
+	}
+}
+
+// managedAccount loads an account the caller's branch scope allows managing
+func (s *CorporateService) managedAccount(ctx context.Context, id uuid.UUID) (*model.CorporateAccount, error) {
+	if !s.corporateRepo.Available() {
+		return nil, model.ErrCorporateAccountNotFound
+	}
+	account, err := s.corporateRepo.GetAccount(ctx, id)
+	if err != nil {
+		return nil, model.ErrCorporateAccountNotFound
+	}
+	if !model.BranchScopeFromContext(ctx).Allows(account.BranchID) {
+		return nil, model.ErrForbidden
+	}
+	return account, nil
+}
+
+// accessibleAccount loads an account the caller may see: customers must be one of its
+// contacts, staff must have its branch in scope
+func (s *CorporateService) accessibleAccount(ctx context.Context, userID uuid.UUID, role model.UserRole, id uuid.UUID) (*model.CorporateAccount, error) {
+	if role != model.RolePelanggan {
+		return s.managedAccount(ctx, id)
+	}
+	if !s.corporateRepo.Available() {
+		return nil, model.ErrCorporateAccountNotFound
+	}
+
+	account, err := s.corporateRepo.GetAccount(ctx, id)
+	if err != nil {
+		return nil, model.ErrCorporateAccountNotFound
+	}
+	isContact, err := s.corporateRepo.IsContact(ctx, account.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isContact {
+		return nil, model.ErrNotCorporateContact
+	}
+	return account, nil
+}
+
+// applyAccountRequest copies the request onto an account
+func (s *CorporateService) applyAccountRequest(ctx context.Context, account *model.CorporateAccount, req *model.CorporateAccountRequest) error {
+	branchID, err := uuid.Parse(req.BranchID)
+	if err != nil {
+		return model.ErrBranchNotFound
+	}
+	if !model.BranchScopeFromContext(ctx).Allows(branchID) {
+		return model.ErrForbidden
+	}
+	if _, err := s.branchRepo.GetByID(ctx, branchID); err != nil {
+		return model.ErrBranchNotFound
+	}
+
+	account.CompanyName = req.CompanyName
+	account.NPWP = req.NPWP
+	account.Address = req.Address
+	account.Email = req.Email
+	account.Phone = req.Phone
+	account.BranchID = branchID
+	account.CreditLimit = req.CreditLimit
+	account.PaymentTermDays = req.PaymentTermDays
+	if account.PaymentTermDays == 0 {
+		account.PaymentTermDays = defaultTermDays()
+	}
+	if req.IsActive != nil {
+		account.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// accountResponse adds the contacts and credit usage to an account
+func (s *CorporateService) accountResponse(ctx context.Context, account *model.CorporateAccount) (*model.CorporateAccountResponse, error) {
+	contacts, err := s.corporateRepo.ListContacts(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+	used, err := s.creditUsed(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.CorporateAccountResponse{
+		CorporateAccount: *account,
+		Contacts:         contacts,
+		CreditUsed:       used,
+	}
+	if response.Contacts == nil {
+		response.Contacts = []model.CorporateContactResponse{}
+	}
+	if account.CreditLimit > 0 {
+		available := account.CreditLimit - used
+		response.CreditAvailable = &available
+	}
+	return response, nil
+}
+
+// creditUsed returns the unpaid invoices of an account plus its work not invoiced yet, with tax
+func (s *CorporateService) creditUsed(ctx context.Context, accountID uuid.UUID) (float64, error) {
+	unpaid, uninvoiced, err := s.corporateRepo.CreditUsage(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+	return unpaid + utils.CalculateAmountWithTax(uninvoiced), nil
+}
+
+// workOrderResponse summarizes the progress of a work order's lines
+func workOrderResponse(workOrder *model.WorkOrder, lines []*model.ServiceOrder) *model.WorkOrderResponse {
+	response := &model.WorkOrderResponse{
+		WorkOrder:    *workOrder,
+		Completed:    len(lines) > 0,
+		StatusCounts: make(map[model.OrderStatus]int),
+		Lines:        make([]model.ServiceOrderResponse, 0, len(lines)),
+	}
+	for _, line := range lines {
+		response.StatusCounts[line.Status]++
+		if line.Status != model.StatusCompleted && line.Status != model.StatusCancelled {
+			response.Completed = false
+		}
+		if line.Status != model.StatusCancelled {
+			response.EstimatedTotal += line.EstimatedCost
+		}
+		response.Lines = append(response.Lines, line.ToResponse())
+	}
+	return response
+}
+
+// generateWorkOrderNumber generates a work order number; its lines are numbered after it
+func generateWorkOrderNumber() string {
+	now := time.Now()
+	return fmt.Sprintf("WO-%s-%s", now.Format("20060102"), strings.ToUpper(uuid.New().String()[:6]))
+}
+
+// paymentTermDays returns the payment term of an account in days
+func paymentTermDays(account *model.CorporateAccount) int {
+	if account.PaymentTermDays > 0 {
+		return account.PaymentTermDays
+	}
+	return defaultTermDays()
+}
+
+// defaultTermDays returns the configured payment term for new accounts
+func defaultTermDays() int {
+	if config.Config != nil && config.Config.CorporatePaymentTermDays > 0 {
+		return config.Config.CorporatePaymentTermDays
+	}
+	return defaultPaymentTermDays
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"service/internal/modules/corporate/repository"
+	numberingService "service/internal/modules/numbering/service"
+	"service/internal/shared/database/dbtest"
+	"service/internal/shared/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockedCorporateService returns a corporate service whose repositories run on sqlmock
+func mockedCorporateService(t *testing.T) (*CorporateService, sqlmock.Sqlmock) {
+	t.Helper()
+	mock := dbtest.MockGlobal(t)
+	return &CorporateService{
+		corporateRepo:    repository.NewCorporateRepository(),
+		numberingService: numberingService.NewNumberingService(),
+	}, mock
+}
+
+func TestInvoiceMonth(t *testing.T) {
+	account := &model.CorporateAccount{ID: uuid.New(), BranchID: uuid.New(), CompanyName: "PT Maju", PaymentTermDays: 45}
+	month := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.Local)
+	completedAt := time.Date(2026, time.September, 20, 0, 0, 0, 0, time.Local)
+	lineColumns := []string{"id", "order_number", "i_phone_model", "service_type", "status", "estimated_cost", "actual_cost", "completed_at", "cancellation_fee"}
+
+	t.Run("month already invoiced", func(t *testing.T) {
+		s, mock := mockedCorporateService(t)
+		dbtest.ExpectCount(mock, "corporate_invoices", 1).WithArgs(account.ID, month)
+
+		invoice, err := s.invoiceMonth(context.Background(), account, month)
+		assert.NoError(t, err)
+		assert.Nil(t, invoice)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("nothing to bill", func(t *testing.T) {
+		s, mock := mockedCorporateService(t)
+		dbtest.ExpectCount(mock, "corporate_invoices", 0)
+		mock.ExpectQuery(`SELECT o\.\*, e\.completed_at, c\.fee AS cancellation_fee FROM service_orders AS o`).
+			WithArgs(model.StatusCompleted, model.StatusCancelled, account.ID, model.StatusCompleted, model.StatusCancelled, month.AddDate(0, 1, 0)).
+			WillReturnRows(sqlmock.NewRows(lineColumns))
+
+		invoice, err := s.invoiceMonth(context.Background(), account, month)
+		assert.NoError(t, err)
+		assert.Nil(t, invoice)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("bills completed work and cancellation fees", func(t *testing.T) {
+		s, mock := mockedCorporateService(t)
+		dbtest.ExpectCount(mock, "corporate_invoices", 0)
+		mock.ExpectQuery(`FROM service_orders AS o`).
+			WillReturnRows(sqlmock.NewRows(lineColumns).
+				AddRow(uuid.New(), "ORD-1", "iPhone 13", model.ServiceTypeScreenRepair, model.StatusCompleted, 400000.0, 500000.0, completedAt, nil).
+				AddRow(uuid.New(), "ORD-2", "iPhone 12", model.ServiceTypeBatteryReplacement, model.StatusCompleted, 300000.0, 0.0, completedAt, nil).
+				AddRow(uuid.New(), "ORD-3", "iPhone 11", model.ServiceTypeOther, model.StatusCancelled, 200000.0, 0.0, completedAt, 50000.0))
+		mock.ExpectQuery(`SELECT \* FROM "branches"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "code"}).AddRow(account.BranchID, "jkt"))
+		mock.ExpectQuery(`SELECT \* FROM "numbering_formats"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO number_sequences`).
+			WithArgs(account.BranchID, model.NumberingKindInvoice, time.Now().Year()).
+			WillReturnRows(sqlmock.NewRows([]string{"last_value"}).AddRow(12))
+		mock.ExpectQuery(`INSERT INTO "corporate_invoices"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+		mock.ExpectQuery(`INSERT INTO "corporate_invoice_lines"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()).AddRow(uuid.New()).AddRow(uuid.New()))
+		mock.ExpectCommit()
+		mock.ExpectQuery(`FROM corporate_contacts AS c`).WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+		invoice, err := s.invoiceMonth(context.Background(), account, month)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+
+		assert.Len(t, invoice.Lines, 3)
+		assert.Equal(t, 500000.0, invoice.Lines[0].Amount, "the actual cost is billed")
+		assert.Equal(t, 300000.0, invoice.Lines[1].Amount, "the estimate stands in until costed")
+		assert.Equal(t, 50000.0, invoice.Lines[2].Amount, "cancelled lines owe only the fee")
+		assert.Contains(t, invoice.Lines[2].Description, "Cancellation fee: ")
+		assert.InDelta(t, 850000.0, invoice.Subtotal, 0.001)
+		assert.InDelta(t, 93500.0, invoice.TaxAmount, 0.001)
+		assert.InDelta(t, 943500.0, invoice.Total, 0.001)
+		assert.Equal(t, month.AddDate(0, 1, 0), invoice.PeriodEnd)
+		assert.Equal(t, invoice.IssuedAt.AddDate(0, 0, 45), invoice.DueAt)
+		assert.Regexp(t, `^JKT-INV-\d{4}-000012$`, invoice.InvoiceNumber)
+	})
+}
+
+func TestGenerateInvoicePeriod(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		period string
+	}{
+		{"not a month", "2026-13"},
+		{"current month", now.Format("2006-01")},
+		{"next month", now.AddDate(0, 1, 0).Format("2006-01")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := mockedCorporateService(t)
+			accountID := uuid.New()
+			mock.ExpectQuery(`SELECT \* FROM "corporate_accounts"`).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "branch_id"}).AddRow(accountID, uuid.New()))
+
+			invoice, err := s.GenerateInvoice(context.Background(), accountID, tt.period)
+			assert.Equal(t, model.ErrInvalidInvoicePeriod, err)
+			assert.Nil(t, invoice)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPayInvoiceTwice(t *testing.T) {
+	s, mock := mockedCorporateService(t)
+	accountID := uuid.New()
+	invoiceID := uuid.New()
+
+	mock.ExpectQuery(`SELECT \* FROM "corporate_invoices"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "account_id", "status"}).AddRow(invoiceID, accountID, model.CorporateInvoiceStatusPaid))
+	mock.ExpectQuery(`SELECT \* FROM "corporate_invoice_lines"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`SELECT \* FROM "corporate_accounts"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "branch_id"}).AddRow(accountID, uuid.New()))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "corporate_invoices" SET .* WHERE id = \$\d+ AND status = \$\d+`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	invoice, err := s.PayInvoice(context.Background(), invoiceID, &model.PayCorporateInvoiceRequest{PaymentRef: "TRF-1"})
+	assert.Equal(t, model.ErrCorporateInvoicePaid, err)
+	assert.Nil(t, invoice)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCorporateInvoiceStatusAt(t *testing.T) {
+	due := time.Date(2026, time.October, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		status model.CorporateInvoiceStatus
+		now    time.Time
+		want   model.CorporateInvoiceStatus
+	}{
+		{"issued before due", model.CorporateInvoiceStatusIssued, due.Add(-time.Hour), model.CorporateInvoiceStatusIssued},
+		{"issued past due", model.CorporateInvoiceStatusIssued, due.Add(time.Hour), model.CorporateInvoiceStatusOverdue},
+		{"paid past due", model.CorporateInvoiceStatusPaid, due.Add(time.Hour), model.CorporateInvoiceStatusPaid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoice := &model.CorporateInvoice{Status: tt.status, DueAt: due}
+			assert.Equal(t, tt.want, invoice.StatusAt(tt.now))
+		})
+	}
+}
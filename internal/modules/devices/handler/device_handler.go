@@ -0,0 +1,417 @@
+package handler
+
+import (
+	"net/http"
+	"service/internal/modules/devices/service"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DeviceHandler handles the device catalog, customer device and device history endpoints
+type DeviceHandler struct {
+	deviceService *service.DeviceService
+}
+
+// NewDeviceHandler creates a new device handler
+func NewDeviceHandler() *DeviceHandler {
+	return &DeviceHandler{
+		deviceService: service.NewDeviceService(),
+	}
+}
+
+// ListDeviceModels godoc
+// @Summary List device models
+// @Description List the iPhone models that can be registered
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse
+// @Router /device-models [get]
+func (h *DeviceHandler) ListDeviceModels(c *gin.Context) {
+	models, err := h.deviceService.ListModels(c.Request.Context(), true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse("device_models_fetch_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(models, "Device models retrieved successfully"))
+}
+
+// ListAllDeviceModels godoc
+// @Summary List device catalog (admin)
+// @Description List every catalog model, including inactive ones
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse
+// @Router /admin/device-models [get]
+func (h *DeviceHandler) ListAllDeviceModels(c *gin.Context) {
+	models, err := h.deviceService.ListModels(c.Request.Context(), false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse("device_models_fetch_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(models, "Device models retrieved successfully"))
+}
+
+// CreateDeviceModel godoc
+// @Summary Add device model (admin)
+// @Description Add an iPhone model to the catalog
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.DeviceModelRequest true "Device model"
+// @Success 201 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /admin/device-models [post]
+func (h *DeviceHandler) CreateDeviceModel(c *gin.Context) {
+	var req model.DeviceModelRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	deviceModel, err := h.deviceService.CreateModel(c.Request.Context(), &req)
+	if err != nil {
+		respondDeviceError(c, "device_model_create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse(deviceModel, "Device model created successfully"))
+}
+
+// UpdateDeviceModel godoc
+// @Summary Update device model (admin)
+// @Description Update a catalog model. Set is_active to false to retire it.
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device model ID"
+// @Param request body model.DeviceModelRequest true "Device model"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /admin/device-models/{id} [put]
+func (h *DeviceHandler) UpdateDeviceModel(c *gin.Context) {
+	id, ok := parseID(c, "Invalid device model ID")
+	if !ok {
+		return
+	}
+
+	var req model.DeviceModelRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	deviceModel, err := h.deviceService.UpdateModel(c.Request.Context(), id, &req)
+	if err == model.ErrDeviceModelNotFound {
+		c.JSON(http.StatusNotFound, model.CreateErrorResponse("device_model_update_failed", err.Error(), nil))
+		return
+	}
+	if err != nil {
+		respondDeviceError(c, "device_model_update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(deviceModel, "Device model updated successfully"))
+}
+
+// RegisterDevice godoc
+// @Summary Register a device
+// @Description Register one of your phones so that its repairs are kept in one history
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.CustomerDeviceRequest true "Device data"
+// @Success 201 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /devices [post]
+func (h *DeviceHandler) RegisterDevice(c *gin.Context) {
+	userUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req model.CustomerDeviceRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	device, err := h.deviceService.RegisterDevice(c.Request.Context(), userUUID, &req)
+	if err != nil {
+		respondDeviceError(c, "device_register_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse(device, "Device registered successfully"))
+}
+
+// ListDevices godoc
+// @Summary List my devices
+// @Description List the phones you have registered
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse
+// @Router /devices [get]
+func (h *DeviceHandler) ListDevices(c *gin.Context) {
+	userUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	devices, err := h.deviceService.ListDevices(c.Request.Context(), userUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse("devices_fetch_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(devices, "Devices retrieved successfully"))
+}
+
+// GetDevice godoc
+// @Summary Get a device
+// @Description Get one of your registered phones
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /devices/{id} [get]
+func (h *DeviceHandler) GetDevice(c *gin.Context) {
+	userUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseID(c, "Invalid device ID")
+	if !ok {
+		return
+	}
+
+	device, err := h.deviceService.GetDevice(c.Request.Context(), userUUID, id)
+	if err != nil {
+		respondDeviceError(c, "device_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(device, "Device retrieved successfully"))
+}
+
+// UpdateDevice godoc
+// @Summary Update a device
+// @Description Update one of your registered phones
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device ID"
+// @Param request body model.CustomerDeviceRequest true "Device data"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /devices/{id} [put]
+func (h *DeviceHandler) UpdateDevice(c *gin.Context) {
+	userUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseID(c, "Invalid device ID")
+	if !ok {
+		return
+	}
+
+	var req model.CustomerDeviceRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	device, err := h.deviceService.UpdateDevice(c.Request.Context(), userUUID, id, &req)
+	if err != nil {
+		respondDeviceError(c, "device_update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(device, "Device updated successfully"))
+}
+
+// DeleteDevice godoc
+// @Summary Delete a device
+// @Description Remove one of your registered phones. Orders already placed for it are kept.
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /devices/{id} [delete]
+func (h *DeviceHandler) DeleteDevice(c *gin.Context) {
+	userUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseID(c, "Invalid device ID")
+	if !ok {
+		return
+	}
+
+	if err := h.deviceService.DeleteDevice(c.Request.Context(), userUUID, id); err != nil {
+		respondDeviceError(c, "device_delete_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(nil, "Device deleted successfully"))
+}
+
+// GetDeviceHistory godoc
+// @Summary Get device service history
+// @Description Get the timeline of orders, warranties, installed parts and photos of one of your phones
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /devices/{id}/history [get]
+func (h *DeviceHandler) GetDeviceHistory(c *gin.Context) {
+	userUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseID(c, "Invalid device ID")
+	if !ok {
+		return
+	}
+
+	history, err := h.deviceService.GetDeviceHistory(c.Request.Context(), userUUID, id)
+	if err != nil {
+		respondDeviceError(c, "device_history_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(history, "Device history retrieved successfully"))
+}
+
+// GetOrderDeviceHistory godoc
+// @Summary Get prior repairs of an order's device
+// @Description Get the earlier repairs of the phone in an order, matched by IMEI, so staff can see what was done to it before
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /orders/{id}/device-history [get]
+func (h *DeviceHandler) GetOrderDeviceHistory(c *gin.Context) {
+	userUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseID(c, "Invalid order ID format")
+	if !ok {
+		return
+	}
+
+	userRole, _ := c.Get("user_role")
+	role, _ := userRole.(model.UserRole)
+
+	history, err := h.deviceService.GetOrderDeviceHistory(c.Request.Context(), id, userUUID, role)
+	if err != nil {
+		respondDeviceError(c, "device_history_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(history, "Device history retrieved successfully"))
+}
+
+// currentUserID reads the authenticated user ID, writing an error response when it is missing
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, model.CreateErrorResponse(
+			"unauthorized",
+			"User not authenticated",
+			nil,
+		))
+		return uuid.Nil, false
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+			"internal_error",
+			"Invalid user ID type",
+			nil,
+		))
+		return uuid.Nil, false
+	}
+
+	return userUUID, true
+}
+
+// parseID parses the :id path parameter, writing an error response when it is invalid
+func parseID(c *gin.Context, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse("invalid_id", message, nil))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// bindRequest binds, sanitizes and validates a JSON payload
+func bindRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return false
+	}
+
+	utils.SanitizeStructStrings(req)
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return false
+	}
+	return true
+}
+
+// respondDeviceError maps device registry errors to HTTP responses
+func respondDeviceError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case model.ErrCustomerDeviceNotFound, model.ErrOrderNotFound:
+		status = http.StatusNotFound
+	case model.ErrDeviceModelNotFound, model.ErrInvalidInput:
+		status = http.StatusBadRequest
+	case model.ErrCustomerDeviceExists, model.ErrDeviceModelExists:
+		status = http.StatusConflict
+	case model.ErrForbidden:
+		status = http.StatusForbidden
+	}
+	c.JSON(status, model.CreateErrorResponse(code, err.Error(), nil))
+}
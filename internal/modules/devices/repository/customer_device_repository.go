@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CustomerDeviceRepository handles the device catalog, customer devices and their service history
+type CustomerDeviceRepository struct {
+	db *gorm.DB
+}
+
+// NewCustomerDeviceRepository creates a new customer device repository
+func NewCustomerDeviceRepository() *CustomerDeviceRepository {
+	return &CustomerDeviceRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *CustomerDeviceRepository) Available() bool {
+	return r.db != nil
+}
+
+// ListModels retrieves the device catalog ordered by release year, newest first
+func (r *CustomerDeviceRepository) ListModels(ctx context.Context, activeOnly bool) ([]*model.DeviceModel, error) {
+	var models []*model.DeviceModel
+	query := r.db.WithContext(ctx)
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Order("release_year DESC, name ASC").Find(&models).Error
+	return models, err
+}
+
+// GetModel retrieves a catalog model by ID
+func (r *CustomerDeviceRepository) GetModel(ctx context.Context, id uuid.UUID) (*model.DeviceModel, error) {
+	var deviceModel model.DeviceModel
+	if err := r.db.WithContext(ctx).First(&deviceModel, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &deviceModel, nil
+}
+
+// ModelNameExists checks if a catalog model with the name exists, optionally excluding one ID
+func (r *CustomerDeviceRepository) ModelNameExists(ctx context.Context, name string, excludeID *uuid.UUID) (bool, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Model(&model.DeviceModel{}).Where("LOWER(name) = LOWER(?)", name)
+	if excludeID != nil {
+		query = query.Where("id != ?", *excludeID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// CreateModel creates a catalog model
+func (r *CustomerDeviceRepository) CreateModel(ctx context.Context, deviceModel *model.DeviceModel) error {
+	return r.db.WithContext(ctx).Create(deviceModel).Error
+}
+
+// UpdateModel updates a catalog model
+func (r *CustomerDeviceRepository) UpdateModel(ctx context.Context, deviceModel *model.DeviceModel) error {
+	return r.db.WithContext(ctx).Save(deviceModel).Error
+}
+
+// Create registers a customer device
+func (r *CustomerDeviceRepository) Create(ctx context.Context, device *model.CustomerDevice) error {
+	return r.db.WithContext(ctx).Create(device).Error
+}
+
+// GetByID retrieves a customer device with its catalog model
+func (r *CustomerDeviceRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.CustomerDevice, error) {
+	var device model.CustomerDevice
+	err := r.db.WithContext(ctx).Preload("DeviceModel").First(&device, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// GetByCustomerIMEI retrieves the device a customer registered with the IMEI
+func (r *CustomerDeviceRepository) GetByCustomerIMEI(ctx context.Context, customerID uuid.UUID, imei string) (*model.CustomerDevice, error) {
+	var device model.CustomerDevice
+	err := r.db.WithContext(ctx).
+		Preload("DeviceModel").
+		First(&device, "customer_id = ? AND imei = ?", customerID, imei).Error
+	if err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// ListByCustomer retrieves the devices of a customer, newest first
+func (r *CustomerDeviceRepository) ListByCustomer(ctx context.Context, customerID uuid.UUID) ([]*model.CustomerDevice, error) {
+	var devices []*model.CustomerDevice
+	err := r.db.WithContext(ctx).
+		Preload("DeviceModel").
+		Where("customer_id = ?", customerID).
+		Order("created_at DESC").
+		Find(&devices).Error
+	return devices, err
+}
+
+// IMEIExists checks if a customer already registered a device with the IMEI, optionally excluding one ID
+func (r *CustomerDeviceRepository) IMEIExists(ctx context.Context, customerID uuid.UUID, imei string, excludeID *uuid.UUID) (bool, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Model(&model.CustomerDevice{}).Where("customer_id = ? AND imei = ?", customerID, imei)
+	if excludeID != nil {
+		query = query.Where("id != ?", *excludeID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// Update updates a customer device
+func (r *CustomerDeviceRepository) Update(ctx context.Context, device *model.CustomerDevice) error {
+	return r.db.WithContext(ctx).Omit("DeviceModel").Save(device).Error
+}
+
+// Delete soft deletes a customer device; its orders keep the link
+func (r *CustomerDeviceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&model.CustomerDevice{}, "id = ?", id).Error
+}
+
+// ListOrdersByDevice retrieves the orders of a device, newest first
+func (r *CustomerDeviceRepository) ListOrdersByDevice(ctx context.Context, deviceID uuid.UUID) ([]*model.ServiceOrder, error) {
+	var orders []*model.ServiceOrder
+	err := r.db.WithContext(ctx).
+		Where("device_id = ?", deviceID).
+		Order("created_at DESC").
+		Find(&orders).Error
+	return orders, err
+}
+
+// ListOrdersByIMEI retrieves every order of a phone across owners, newest first.
+// Orders are matched through registered devices and through the IMEI typed on older orders.
+func (r *CustomerDeviceRepository) ListOrdersByIMEI(ctx context.Context, imei string) ([]*model.ServiceOrder, error) {
+	var orders []*model.ServiceOrder
+	devices := r.db.Unscoped().Model(&model.CustomerDevice{}).Select("id").Where("imei = ?", imei)
+	err := r.db.WithContext(ctx).
+		Where("device_id IN (?) OR i_phone_imei = ?", devices, imei).
+		Order("created_at DESC").
+		Find(&orders).Error
+	return orders, err
+}
+
+// ListWarranties retrieves the warranties of the given orders
+func (r *CustomerDeviceRepository) ListWarranties(ctx context.Context, orderIDs []uuid.UUID) ([]*model.Warranty, error) {
+	var warranties []*model.Warranty
+	if len(orderIDs) == 0 {
+		return warranties, nil
+	}
+	err := r.db.WithContext(ctx).Where("order_id IN ?", orderIDs).Find(&warranties).Error
+	return warranties, err
+}
+
+// ListParts retrieves the parts installed during the given orders
+func (r *CustomerDeviceRepository) ListParts(ctx context.Context, orderIDs []uuid.UUID) ([]*model.OrderPart, error) {
+	var parts []*model.OrderPart
+	if len(orderIDs) == 0 {
+		return parts, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("order_id IN ?", orderIDs).
+		Order("created_at ASC").
+		Find(&parts).Error
+	return parts, err
+}
+
+// ListMedia retrieves the gallery photos of the given orders by stage and position,
+// optionally only those of one visibility
+func (r *CustomerDeviceRepository) ListMedia(ctx context.Context, orderIDs []uuid.UUID, visibility *model.MediaVisibility) ([]*model.OrderMedia, error) {
+	var media []*model.OrderMedia
+	if len(orderIDs) == 0 {
+		return media, nil
+	}
+	query := r.db.WithContext(ctx).Where("order_id IN ?", orderIDs)
+	if visibility != nil {
+		query = query.Where("visibility = ?", *visibility)
+	}
+	err := query.
+		Order("CASE stage WHEN 'pickup' THEN 0 WHEN 'service' THEN 1 ELSE 2 END").
+		Order("position ASC, created_at ASC").
+		Find(&media).Error
+	return media, err
+}
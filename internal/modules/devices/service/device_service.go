@@ -0,0 +1,414 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	deviceRepository "service/internal/modules/devices/repository"
+	mediaService "service/internal/modules/media/service"
+	orderRepository "service/internal/modules/orders/repository"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// devicePhotoURLExpiry is how long photo links in a device history stay valid
+const devicePhotoURLExpiry = 15 * time.Minute
+
+// DeviceService handles the device catalog, customer devices and their service history
+type DeviceService struct {
+	deviceRepo  *deviceRepository.CustomerDeviceRepository
+	orderRepo   *orderRepository.ServiceOrderRepository
+	fileService *mediaService.FileService
+}
+
+// NewDeviceService creates a new device service
+func NewDeviceService() *DeviceService {
+	fileService, err := mediaService.NewFileService()
+	if err != nil {
+		// Histories are still served, only without photos
+		log.Printf("Failed to initialize file service for device histories: %v", err)
+		fileService = nil
+	}
+	return &DeviceService{
+		deviceRepo:  deviceRepository.NewCustomerDeviceRepository(),
+		orderRepo:   orderRepository.NewServiceOrderRepository(),
+		fileService: fileService,
+	}
+}
+
+// ListModels retrieves the device catalog; customers only see active models
+func (s *DeviceService) ListModels(ctx context.Context, activeOnly bool) ([]*model.DeviceModel, error) {
+	if !s.deviceRepo.Available() {
+		return []*model.DeviceModel{}, nil
+	}
+	return s.deviceRepo.ListModels(ctx, activeOnly)
+}
+
+// CreateModel adds a model to the catalog
+func (s *DeviceService) CreateModel(ctx context.Context, req *model.DeviceModelRequest) (*model.DeviceModel, error) {
+	if !s.deviceRepo.Available() {
+		return nil, errors.New("device catalog is not available")
+	}
+
+	exists, err := s.deviceRepo.ModelNameExists(ctx, req.Name, nil)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, model.ErrDeviceModelExists
+	}
+
+	deviceModel := &model.DeviceModel{
+		Name:        req.Name,
+		Identifier:  req.Identifier,
+		ReleaseYear: req.ReleaseYear,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+	}
+	if err := s.deviceRepo.CreateModel(ctx, deviceModel); err != nil {
+		return nil, err
+	}
+	return deviceModel, nil
+}
+
+// UpdateModel updates a catalog model. Retired models are deactivated rather than deleted
+// because registered devices keep referring to them.
+func (s *DeviceService) UpdateModel(ctx context.Context, id uuid.UUID, req *model.DeviceModelRequest) (*model.DeviceModel, error) {
+	if !s.deviceRepo.Available() {
+		return nil, model.ErrDeviceModelNotFound
+	}
+
+	deviceModel, err := s.deviceRepo.GetModel(ctx, id)
+	if err != nil {
+		return nil, model.ErrDeviceModelNotFound
+	}
+
+	exists, err := s.deviceRepo.ModelNameExists(ctx, req.Name, &id)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, model.ErrDeviceModelExists
+	}
+
+	deviceModel.Name = req.Name
+	deviceModel.Identifier = req.Identifier
+	deviceModel.ReleaseYear = req.ReleaseYear
+	if req.IsActive != nil {
+		deviceModel.IsActive = *req.IsActive
+	}
+	if err := s.deviceRepo.UpdateModel(ctx, deviceModel); err != nil {
+		return nil, err
+	}
+	return deviceModel, nil
+}
+
+// RegisterDevice registers a phone for a customer
+func (s *DeviceService) RegisterDevice(ctx context.Context, customerID uuid.UUID, req *model.CustomerDeviceRequest) (*model.CustomerDeviceResponse, error) {
+	if !s.deviceRepo.Available() {
+		return nil, errors.New("device registry is not available")
+	}
+
+	device := &model.CustomerDevice{CustomerID: customerID}
+	if err := s.applyRequest(ctx, device, req); err != nil {
+		return nil, err
+	}
+	if err := s.deviceRepo.Create(ctx, device); err != nil {
+		return nil, err
+	}
+
+	response := device.ToResponse()
+	return &response, nil
+}
+
+// ListDevices retrieves the devices of a customer
+func (s *DeviceService) ListDevices(ctx context.Context, customerID uuid.UUID) ([]model.CustomerDeviceResponse, error) {
+	responses := []model.CustomerDeviceResponse{}
+	if !s.deviceRepo.Available() {
+		return responses, nil
+	}
+
+	devices, err := s.deviceRepo.ListByCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range devices {
+		responses = append(responses, d.ToResponse())
+	}
+	return responses, nil
+}
+
+// GetDevice retrieves a device owned by the customer
+func (s *DeviceService) GetDevice(ctx context.Context, customerID, id uuid.UUID) (*model.CustomerDeviceResponse, error) {
+	device, err := s.getOwnedDevice(ctx, customerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := device.ToResponse()
+	return &response, nil
+}
+
+// UpdateDevice updates a device owned by the customer
+func (s *DeviceService) UpdateDevice(ctx context.Context, customerID, id uuid.UUID, req *model.CustomerDeviceRequest) (*model.CustomerDeviceResponse, error) {
+	device, err := s.getOwnedDevice(ctx, customerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyRequest(ctx, device, req); err != nil {
+		return nil, err
+	}
+	if err := s.deviceRepo.Update(ctx, device); err != nil {
+		return nil, err
+	}
+
+	response := device.ToResponse()
+	return &response, nil
+}
+
+// DeleteDevice removes a device owned by the customer. Past orders keep their link to it.
+func (s *DeviceService) DeleteDevice(ctx context.Context, customerID, id uuid.UUID) error {
+	if _, err := s.getOwnedDevice(ctx, customerID, id); err != nil {
+		return err
+	}
+	return s.deviceRepo.Delete(ctx, id)
+}
+
+// GetDeviceHistory retrieves the service timeline of a device owned by the customer
+func (s *DeviceService) GetDeviceHistory(ctx context.Context, customerID, id uuid.UUID) (*model.DeviceHistoryResponse, error) {
+	device, err := s.getOwnedDevice(ctx, customerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := s.deviceRepo.ListOrdersByDevice(ctx, device.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Customers only see the photos staff marked as customer-visible
+	customerVisible := model.MediaVisibilityCustomer
+	records, err := s.buildRecords(ctx, orders, &customerVisible)
+	if err != nil {
+		return nil, err
+	}
+
+	response := device.ToResponse()
+	return &model.DeviceHistoryResponse{
+		Device:  &response,
+		IMEI:    device.IMEI,
+		Records: records,
+	}, nil
+}
+
+// GetOrderDeviceHistory retrieves the earlier repairs of the phone in an order, so staff
+// can see what was done to it before. The phone is matched by IMEI across owners.
+// Technicians only see the history of orders assigned to them.
+func (s *DeviceService) GetOrderDeviceHistory(ctx context.Context, orderID, userID uuid.UUID, role model.UserRole) (*model.DeviceHistoryResponse, error) {
+	if role == model.RolePelanggan {
+		return nil, model.ErrForbidden
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+	if role == model.RoleTeknisi && (order.TechnicianID == nil || *order.TechnicianID != userID) {
+		return nil, model.ErrForbidden
+	}
+
+	history := &model.DeviceHistoryResponse{Records: []model.DeviceServiceRecord{}}
+	if !s.deviceRepo.Available() {
+		return history, nil
+	}
+
+	imei := order.IPhoneIMEI
+	if order.DeviceID != nil {
+		if device, err := s.deviceRepo.GetByID(ctx, *order.DeviceID); err == nil {
+			response := device.ToResponse()
+			history.Device = &response
+			imei = device.IMEI
+		}
+	}
+	history.IMEI = imei
+	if !utils.IsValidIMEI(imei) {
+		return history, nil
+	}
+
+	orders, err := s.deviceRepo.ListOrdersByIMEI(ctx, imei)
+	if err != nil {
+		return nil, err
+	}
+	previous := make([]*model.ServiceOrder, 0, len(orders))
+	for _, o := range orders {
+		if o.ID != order.ID {
+			previous = append(previous, o)
+		}
+	}
+
+	history.Records, err = s.buildRecords(ctx, previous, nil)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// getOwnedDevice loads a device and checks that it belongs to the customer
+func (s *DeviceService) getOwnedDevice(ctx context.Context, customerID, id uuid.UUID) (*model.CustomerDevice, error) {
+	if !s.deviceRepo.Available() {
+		return nil, model.ErrCustomerDeviceNotFound
+	}
+
+	device, err := s.deviceRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrCustomerDeviceNotFound
+		}
+		return nil, err
+	}
+	if device.CustomerID != customerID {
+		return nil, model.ErrCustomerDeviceNotFound
+	}
+	return device, nil
+}
+
+// applyRequest validates a device request against the catalog and copies it onto the device
+func (s *DeviceService) applyRequest(ctx context.Context, device *model.CustomerDevice, req *model.CustomerDeviceRequest) error {
+	modelID, err := uuid.Parse(req.DeviceModelID)
+	if err != nil {
+		return model.ErrDeviceModelNotFound
+	}
+	deviceModel, err := s.deviceRepo.GetModel(ctx, modelID)
+	if err != nil || (!deviceModel.IsActive && deviceModel.ID != device.DeviceModelID) {
+		return model.ErrDeviceModelNotFound
+	}
+
+	var excludeID *uuid.UUID
+	if device.ID != uuid.Nil {
+		excludeID = &device.ID
+	}
+	exists, err := s.deviceRepo.IMEIExists(ctx, device.CustomerID, req.IMEI, excludeID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return model.ErrCustomerDeviceExists
+	}
+
+	var purchaseDate *time.Time
+	if req.PurchaseDate != "" {
+		date, err := time.Parse("2006-01-02", req.PurchaseDate)
+		if err != nil {
+			return model.ErrInvalidInput
+		}
+		purchaseDate = &date
+	}
+
+	device.DeviceModelID = deviceModel.ID
+	device.DeviceModel = *deviceModel
+	device.IMEI = req.IMEI
+	device.SerialNumber = strings.ToUpper(req.SerialNumber)
+	device.Color = req.Color
+	device.PurchaseDate = purchaseDate
+	return nil
+}
+
+// buildRecords turns orders into history records with their warranty, parts and gallery
+// photos, optionally only the photos of one visibility
+func (s *DeviceService) buildRecords(ctx context.Context, orders []*model.ServiceOrder, visibility *model.MediaVisibility) ([]model.DeviceServiceRecord, error) {
+	records := make([]model.DeviceServiceRecord, 0, len(orders))
+	if len(orders) == 0 {
+		return records, nil
+	}
+
+	orderIDs := make([]uuid.UUID, 0, len(orders))
+	for _, o := range orders {
+		orderIDs = append(orderIDs, o.ID)
+	}
+
+	warranties, err := s.deviceRepo.ListWarranties(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	warrantyByOrder := make(map[uuid.UUID]*model.Warranty, len(warranties))
+	for _, w := range warranties {
+		warrantyByOrder[w.OrderID] = w
+	}
+
+	parts, err := s.deviceRepo.ListParts(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	partsByOrder := make(map[uuid.UUID][]model.OrderPartResponse)
+	for _, p := range parts {
+		partsByOrder[p.OrderID] = append(partsByOrder[p.OrderID], p.ToResponse())
+	}
+
+	media, err := s.deviceRepo.ListMedia(ctx, orderIDs, visibility)
+	if err != nil {
+		return nil, err
+	}
+	mediaByOrder := make(map[uuid.UUID][]*model.OrderMedia)
+	for _, m := range media {
+		mediaByOrder[m.OrderID] = append(mediaByOrder[m.OrderID], m)
+	}
+
+	for _, o := range orders {
+		record := model.DeviceServiceRecord{
+			OrderID:      o.ID,
+			OrderNumber:  o.OrderNumber,
+			BranchID:     o.BranchID,
+			TechnicianID: o.TechnicianID,
+			ServiceType:  o.ServiceType,
+			Description:  o.Description,
+			Status:       o.Status,
+			ActualCost:   o.ActualCost,
+			Parts:        partsByOrder[o.ID],
+			Photos:       s.orderPhotos(ctx, mediaByOrder[o.ID]),
+			CreatedAt:    o.CreatedAt,
+			UpdatedAt:    o.UpdatedAt,
+		}
+		if record.Parts == nil {
+			record.Parts = []model.OrderPartResponse{}
+		}
+		if w, ok := warrantyByOrder[o.ID]; ok {
+			record.Warranty = &model.DeviceWarranty{
+				StartDate:     w.StartDate,
+				EndDate:       w.EndDate,
+				IsActive:      w.IsActive && !w.IsExpired(),
+				DaysRemaining: w.DaysRemaining(),
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// orderPhotos turns the gallery photos of an order into short-lived links. Only photos in
+// the gallery are listed; handover photos, signatures and other files stored under the
+// order are not part of a device's history. Storage errors only leave the photos out.
+func (s *DeviceService) orderPhotos(ctx context.Context, media []*model.OrderMedia) []model.DevicePhoto {
+	photos := []model.DevicePhoto{}
+	if s.fileService == nil {
+		return photos
+	}
+
+	for _, m := range media {
+		url, err := s.fileService.GetFileURL(ctx, m.ObjectName, devicePhotoURLExpiry)
+		if err != nil {
+			log.Printf("Failed to sign photo %s of order %s: %v", m.ID, m.OrderID, err)
+			continue
+		}
+		photos = append(photos, model.DevicePhoto{
+			Stage:      string(m.Stage),
+			URL:        url,
+			UploadedAt: m.CreatedAt,
+		})
+	}
+	return photos
+}
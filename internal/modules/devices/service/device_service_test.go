@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	deviceRepository "service/internal/modules/devices/repository"
+	"service/internal/shared/database/dbtest"
+	"service/internal/shared/model"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockedDeviceService returns a device service whose repository runs on sqlmock
+func mockedDeviceService(t *testing.T) (*DeviceService, sqlmock.Sqlmock) {
+	t.Helper()
+	mock := dbtest.MockGlobal(t)
+	return &DeviceService{deviceRepo: deviceRepository.NewCustomerDeviceRepository()}, mock
+}
+
+func TestDeviceHistoryPhotoVisibility(t *testing.T) {
+	customerID := uuid.New()
+	deviceID := uuid.New()
+	orderID := uuid.New()
+
+	tests := []struct {
+		name  string
+		query func(mock sqlmock.Sqlmock)
+		run   func(s *DeviceService) error
+	}{
+		{
+			name: "customer history only lists customer-visible photos",
+			query: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT \* FROM "customer_devices"`).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "customer_id", "device_model_id"}).
+						AddRow(deviceID, customerID, uuid.New()))
+				mock.ExpectQuery(`SELECT \* FROM "device_models"`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery(`SELECT \* FROM "service_orders" WHERE device_id = \$1`).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "device_id"}).AddRow(orderID, deviceID))
+				mock.ExpectQuery(`SELECT \* FROM "warranties"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery(`SELECT \* FROM "order_parts"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery(`SELECT \* FROM "order_media" WHERE order_id IN \(\$1\) AND visibility = \$2`).
+					WithArgs(orderID, model.MediaVisibilityCustomer).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			run: func(s *DeviceService) error {
+				_, err := s.GetDeviceHistory(context.Background(), customerID, deviceID)
+				return err
+			},
+		},
+		{
+			name: "staff history lists the whole gallery",
+			query: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT \* FROM "warranties"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery(`SELECT \* FROM "order_parts"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery(`SELECT \* FROM "order_media" WHERE order_id IN \(\$1\) AND "order_media"."deleted_at" IS NULL`).
+					WithArgs(orderID).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			run: func(s *DeviceService) error {
+				_, err := s.buildRecords(context.Background(), []*model.ServiceOrder{{ID: orderID}}, nil)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := mockedDeviceService(t)
+			tt.query(mock)
+			assert.NoError(t, tt.run(s))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // SparePartInventoryRepository handles spare part inventory database operations
@@ -23,6 +24,12 @@ func NewSparePartInventoryRepository() *SparePartInventoryRepository {
 
 // Create creates a new spare part inventory entry
 func (r *SparePartInventoryRepository) Create(ctx context.Context, sparePart *model.SparePartInventory) error {
+	if !model.BranchScopeFromContext(ctx).Allows(sparePart.BranchID) {
+		return model.ErrForbidden
+	}
+	if sparePart.Version == 0 {
+		sparePart.Version = 1
+	}
 	return r.db.WithContext(ctx).Create(sparePart).Error
 }
 
@@ -30,6 +37,7 @@ func (r *SparePartInventoryRepository) Create(ctx context.Context, sparePart *mo
 func (r *SparePartInventoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.SparePartInventory, error) {
 	var sparePart model.SparePartInventory
 	err := r.db.WithContext(ctx).
+		Scopes(model.ScopeByBranch(ctx, "branch_id")).
 		Preload("Branch").
 		First(&sparePart, "id = ?", id).Error
 	if err != nil {
@@ -42,6 +50,7 @@ func (r *SparePartInventoryRepository) GetByID(ctx context.Context, id uuid.UUID
 func (r *SparePartInventoryRepository) GetByPartCode(ctx context.Context, partCode string, branchID uuid.UUID) (*model.SparePartInventory, error) {
 	var sparePart model.SparePartInventory
 	err := r.db.WithContext(ctx).
+		Scopes(model.ScopeByBranch(ctx, "branch_id")).
 		Preload("Branch").
 		Where("part_code = ? AND branch_id = ?", partCode, branchID).
 		First(&sparePart).Error
@@ -56,7 +65,10 @@ func (r *SparePartInventoryRepository) List(ctx context.Context, offset, limit i
 	var spareParts []model.SparePartInventory
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&model.SparePartInventory{}).Preload("Branch")
+	query := r.db.WithContext(ctx).
+		Scopes(model.ScopeByBranch(ctx, "branch_id")).
+		Model(&model.SparePartInventory{}).
+		Preload("Branch")
 
 	// Apply filters
 	if filters != nil {
@@ -89,14 +101,37 @@ func (r *SparePartInventoryRepository) List(ctx context.Context, offset, limit i
 	return spareParts, total, err
 }
 
-// Update updates a spare part
+// Update updates a spare part. The write only applies while the stored part still has the
+// version it was read at; otherwise it fails with ErrVersionConflict and nothing is
+// written. The part's version is raised on success.
 func (r *SparePartInventoryRepository) Update(ctx context.Context, sparePart *model.SparePartInventory) error {
-	return r.db.WithContext(ctx).Save(sparePart).Error
+	if !model.BranchScopeFromContext(ctx).Allows(sparePart.BranchID) {
+		return model.ErrForbidden
+	}
+
+	read := sparePart.Version
+	sparePart.Version = read + 1
+	result := r.db.WithContext(ctx).
+		Model(sparePart).
+		Where("version = ?", read).
+		Select("*").
+		Omit(clause.Associations).
+		Updates(sparePart)
+	if result.Error != nil || result.RowsAffected == 0 {
+		sparePart.Version = read
+		if result.Error != nil {
+			return result.Error
+		}
+		return model.ErrVersionConflict
+	}
+	return nil
 }
 
 // Delete soft deletes a spare part
 func (r *SparePartInventoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&model.SparePartInventory{}, "id = ?", id).Error
+	return r.db.WithContext(ctx).
+		Scopes(model.ScopeByBranch(ctx, "branch_id")).
+		Delete(&model.SparePartInventory{}, "id = ?", id).Error
 }
 
 // SparePartInventoryFilters represents filters for spare part queries
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"service/internal/shared/database/dbtest"
+	"service/internal/shared/model"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparePartInventoryRepositoryUpdateVersion(t *testing.T) {
+	ctx := context.Background()
+	branchID := uuid.New()
+	writeFailed := errors.New("write failed")
+	ownBranch := model.WithBranchScope(ctx, &model.BranchScope{BranchIDs: []uuid.UUID{branchID}})
+	otherBranch := model.WithBranchScope(ctx, &model.BranchScope{BranchIDs: []uuid.UUID{uuid.New()}})
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		matched     int64
+		writeErr    error
+		want        error
+		wantVersion int64
+	}{
+		{"current version", ctx, 1, nil, nil, 4},
+		{"own branch", ownBranch, 1, nil, nil, 4},
+		{"stale version", ctx, 0, nil, model.ErrVersionConflict, 3},
+		{"failed write", ctx, 0, writeFailed, writeFailed, 3},
+		{"other branch", otherBranch, 0, nil, model.ErrForbidden, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := dbtest.Mock(t)
+			repo := &SparePartInventoryRepository{db: db}
+			part := &model.SparePartInventory{ID: uuid.New(), BranchID: branchID, PartCode: "LCD-13", Stock: 4, Version: 3}
+
+			// Parts of other branches are refused before anything is written
+			if tt.want != model.ErrForbidden {
+				mock.ExpectBegin()
+				update := dbtest.ExpectVersionedUpdate(mock, "spare_part_inventory", tt.matched)
+				if tt.writeErr != nil {
+					update.WillReturnError(tt.writeErr)
+					mock.ExpectRollback()
+				} else {
+					mock.ExpectCommit()
+				}
+			}
+
+			assert.Equal(t, tt.want, repo.Update(tt.ctx, part))
+			assert.Equal(t, tt.wantVersion, part.Version, "a failed write keeps the version it was read at")
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSparePartInventoryRepositoryCreate(t *testing.T) {
+	branchID := uuid.New()
+
+	tests := []struct {
+		name  string
+		scope *model.BranchScope
+		want  error
+	}{
+		{"unscoped", nil, nil},
+		{"own branch", &model.BranchScope{BranchIDs: []uuid.UUID{branchID}}, nil},
+		{"other branch", &model.BranchScope{BranchIDs: []uuid.UUID{uuid.New()}}, model.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := dbtest.Mock(t)
+			repo := &SparePartInventoryRepository{db: db}
+			part := &model.SparePartInventory{BranchID: branchID, PartCode: "LCD-13", Stock: 4}
+
+			if tt.want == nil {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO "spare_part_inventory"`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+				mock.ExpectCommit()
+			}
+
+			ctx := model.WithBranchScope(context.Background(), tt.scope)
+			assert.Equal(t, tt.want, repo.Create(ctx, part))
+			if tt.want == nil {
+				assert.Equal(t, int64(1), part.Version, "new parts start at the first version")
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -93,7 +93,7 @@ func (h *FileHandler) UploadFile(c *gin.Context) {
 
 // UploadOrderPhoto godoc
 // @Summary Upload order photo
-// @Description Upload a photo for an order (pickup, service, or delivery)
+// @Description Upload a photo for an order (pickup, service, or delivery). Use POST /orders/{id}/media to keep several photos per stage in the order's gallery.
 // @Tags files
 // @Accept multipart/form-data
 // @Produce json
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"path/filepath"
 	"service/internal/shared/config"
@@ -141,6 +142,33 @@ func (s *FileService) GetFileURL(ctx context.Context, objectName string, expiry
 	return url.String(), nil
 }
 
+// GetFile opens a stored file for reading. The caller must close the returned reader.
+func (s *FileService) GetFile(ctx context.Context, objectName string) (io.ReadCloser, error) {
+	return s.minioClient.GetObject(ctx, s.bucketName, objectName, minio.GetObjectOptions{})
+}
+
+// PutFile stores content under the given object name
+func (s *FileService) PutFile(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) error {
+	_, err := s.minioClient.PutObject(ctx, s.bucketName, objectName, reader, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	return err
+}
+
+// DeleteFolder deletes every file in a folder
+func (s *FileService) DeleteFolder(ctx context.Context, folder string) error {
+	files, err := s.ListFiles(ctx, folder)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := s.DeleteFile(ctx, f.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ListFiles lists files in a folder
 func (s *FileService) ListFiles(ctx context.Context, folder string) ([]model.FileInfo, error) {
 	objectCh := s.minioClient.ListObjects(ctx, s.bucketName, minio.ListObjectsOptions{
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/smtp"
+	"service/internal/shared/config"
+	"strings"
+)
+
+// EmailService handles outgoing email over SMTP
+type EmailService struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+}
+
+// NewEmailService creates a new email service
+func NewEmailService() *EmailService {
+	// Without configuration (e.g. during tests) emails are only logged
+	if config.Config == nil {
+		return &EmailService{}
+	}
+	return &EmailService{
+		host:     config.Config.SMTPHost,
+		port:     config.Config.SMTPPort,
+		username: config.Config.SMTPUsername,
+		password: config.Config.SMTPPassword,
+		from:     config.Config.SMTPFrom,
+	}
+}
+
+// Send sends a plain text email
+func (s *EmailService) Send(to, subject, body string) error {
+	if s.username == "" {
+		log.Printf("📧 Mock email (SMTP_USERNAME not set) to %s: %s\n%s", to, subject, body)
+		return nil
+	}
+
+	if to == "" {
+		return fmt.Errorf("email recipient is empty")
+	}
+
+	from := s.from
+	if from == "" {
+		from = s.username
+	}
+
+	headers := []string{
+		"From: " + from,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"UTF-8\"",
+	}
+	message := strings.Join(headers, "\r\n") + "\r\n\r\n" + body
+
+	auth := smtp.PlainAuth("", s.username, s.password, s.host)
+	addr := fmt.Sprintf("%s:%d", s.host, s.port)
+	if err := smtp.SendMail(addr, auth, from, []string{to}, []byte(message)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	log.Printf("✅ Email sent to %s", to)
+	return nil
+}
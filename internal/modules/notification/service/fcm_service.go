@@ -7,23 +7,33 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	userRepo "service/internal/modules/users/repository"
 	"service/internal/shared/config"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // FCMService handles Firebase Cloud Messaging operations
 type FCMService struct {
-	serverKey string
-	client    *http.Client
+	serverKey  string
+	client     *http.Client
+	deviceRepo *userRepo.DeviceRepository
 }
 
 // NewFCMService creates a new FCM service
 func NewFCMService() *FCMService {
+	serverKey := ""
+	if config.Config != nil {
+		serverKey = config.Config.FirebaseServerKey
+	}
+
 	return &FCMService{
-		serverKey: config.Config.FirebaseServerKey,
+		serverKey: serverKey,
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		deviceRepo: userRepo.NewDeviceRepository(),
 	}
 }
 
@@ -74,8 +84,20 @@ const (
 	PriorityHigh   = "high"
 	PriorityNormal = "normal"
 	SoundDefault   = "default"
+
+	// fcmMaxRegistrationIDs is the most tokens FCM accepts in one multicast message
+	fcmMaxRegistrationIDs = 1000
+	// deviceStaleAfter is how long a device may go unseen before pushes stop going to it
+	deviceStaleAfter = 60 * 24 * time.Hour
 )
 
+// fcmInvalidTokenErrors are result errors meaning a token will never work again
+var fcmInvalidTokenErrors = map[string]bool{
+	"NotRegistered":       true,
+	"InvalidRegistration": true,
+	"MismatchSenderId":    true,
+}
+
 // SendToToken sends FCM message to a single device token
 func (s *FCMService) SendToToken(ctx context.Context, token string, notification *FCMNotification, data map[string]interface{}) error {
 	if s.serverKey == "" {
@@ -95,7 +117,8 @@ func (s *FCMService) SendToToken(ctx context.Context, token string, notification
 		Priority:     PriorityHigh,
 	}
 
-	return s.sendMessage(ctx, message)
+	_, err := s.sendMessage(ctx, message)
+	return err
 }
 
 // SendToTokens sends FCM message to multiple device tokens
@@ -122,7 +145,93 @@ func (s *FCMService) SendToTokens(ctx context.Context, tokens []string, notifica
 		Priority:        PriorityHigh,
 	}
 
-	return s.sendMessage(ctx, message)
+	_, err := s.sendMessage(ctx, message)
+	return err
+}
+
+// SendToUser sends FCM message to every active device of a user.
+// Tokens that FCM reports as invalid are removed and replaced tokens are updated,
+// so a failed delivery to one device does not affect the others.
+func (s *FCMService) SendToUser(ctx context.Context, userID uuid.UUID, notification *FCMNotification, data map[string]interface{}) error {
+	if !s.deviceRepo.Available() {
+		return fmt.Errorf("device storage is not available")
+	}
+
+	tokens, err := s.deviceRepo.ListActiveTokens(ctx, userID, time.Now().Add(-deviceStaleAfter))
+	if err != nil {
+		return fmt.Errorf("failed to list devices: %w", err)
+	}
+	if len(tokens) == 0 {
+		return fmt.Errorf("user has no registered devices")
+	}
+
+	if s.serverKey == "" {
+		log.Printf("🔔 Mock FCM notification to %d devices (FIREBASE_SERVER_KEY not set): %s - %s", len(tokens), notification.Title, notification.Body)
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	}
+
+	delivered := 0
+	var lastErr error
+	for start := 0; start < len(tokens); start += fcmMaxRegistrationIDs {
+		end := start + fcmMaxRegistrationIDs
+		if end > len(tokens) {
+			end = len(tokens)
+		}
+		batch := tokens[start:end]
+
+		// RegistrationIDs is used even for one token so that results are always reported per token
+		message := &FCMMessage{
+			RegistrationIDs: batch,
+			Notification:    notification,
+			Data:            data,
+			Priority:        PriorityHigh,
+		}
+
+		fcmResp, err := s.sendMessage(ctx, message)
+		if fcmResp == nil {
+			lastErr = err
+			continue
+		}
+		delivered += fcmResp.Success
+		s.pruneTokens(ctx, batch, fcmResp.Results)
+	}
+
+	if delivered == 0 {
+		if lastErr != nil {
+			return lastErr
+		}
+		return fmt.Errorf("FCM delivery failed for all %d devices", len(tokens))
+	}
+	return nil
+}
+
+// pruneTokens removes tokens FCM no longer accepts and stores canonical replacements.
+// Results are in the same order as the tokens of the request.
+func (s *FCMService) pruneTokens(ctx context.Context, tokens []string, results []FCMResult) {
+	var invalid []string
+	for i, result := range results {
+		if i >= len(tokens) {
+			break
+		}
+		switch {
+		case fcmInvalidTokenErrors[result.Error]:
+			invalid = append(invalid, tokens[i])
+		case result.RegistrationID != "" && result.RegistrationID != tokens[i]:
+			if err := s.deviceRepo.ReplaceToken(ctx, tokens[i], result.RegistrationID); err != nil {
+				log.Printf("⚠️ FCM: failed to update canonical token: %v", err)
+			}
+		}
+	}
+
+	if len(invalid) == 0 {
+		return
+	}
+	if err := s.deviceRepo.DeleteByTokens(ctx, invalid); err != nil {
+		log.Printf("⚠️ FCM: failed to prune %d invalid tokens: %v", len(invalid), err)
+		return
+	}
+	log.Printf("🧹 FCM: pruned %d invalid tokens", len(invalid))
 }
 
 // SendToTopic sends FCM message to a topic
@@ -144,19 +253,21 @@ func (s *FCMService) SendToTopic(ctx context.Context, topic string, notification
 		Priority:     PriorityHigh,
 	}
 
-	return s.sendMessage(ctx, message)
+	_, err := s.sendMessage(ctx, message)
+	return err
 }
 
-// sendMessage sends message to FCM API
-func (s *FCMService) sendMessage(ctx context.Context, message *FCMMessage) error {
+// sendMessage sends message to FCM API.
+// The decoded response is returned alongside per-token failures so callers can inspect the results.
+func (s *FCMService) sendMessage(ctx context.Context, message *FCMMessage) (*FCMResponse, error) {
 	payload, err := json.Marshal(message)
 	if err != nil {
-		return fmt.Errorf("failed to marshal FCM message: %w", err)
+		return nil, fmt.Errorf("failed to marshal FCM message: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", FCMAPIEndpoint, bytes.NewBuffer(payload))
 	if err != nil {
-		return fmt.Errorf("failed to create FCM request: %w", err)
+		return nil, fmt.Errorf("failed to create FCM request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -164,17 +275,17 @@ func (s *FCMService) sendMessage(ctx context.Context, message *FCMMessage) error
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send FCM request: %w", err)
+		return nil, fmt.Errorf("failed to send FCM request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	var fcmResp FCMResponse
 	if err := json.NewDecoder(resp.Body).Decode(&fcmResp); err != nil {
-		return fmt.Errorf("failed to decode FCM response: %w", err)
+		return nil, fmt.Errorf("failed to decode FCM response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("FCM API error: status=%d, error=%v", resp.StatusCode, fcmResp.Error)
+		return nil, fmt.Errorf("FCM API error: status=%d, error=%v", resp.StatusCode, fcmResp.Error)
 	}
 
 	// Check for failures in multicast response
@@ -185,9 +296,9 @@ func (s *FCMService) sendMessage(ctx context.Context, message *FCMMessage) error
 				log.Printf("  Token %d error: %s", i, result.Error)
 			}
 		}
-		return fmt.Errorf("FCM delivery failed for %d tokens", fcmResp.Failure)
+		return &fcmResp, fmt.Errorf("FCM delivery failed for %d tokens", fcmResp.Failure)
 	}
 
 	log.Printf("✅ FCM notification sent successfully")
-	return nil
+	return &fcmResp, nil
 }
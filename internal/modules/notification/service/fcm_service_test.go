@@ -0,0 +1,166 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	userRepo "service/internal/modules/users/repository"
+	"service/internal/shared/database/dbtest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fcmStub answers FCM requests with the results the test chooses for their tokens
+type fcmStub func(tokens []string) []FCMResult
+
+func (f fcmStub) RoundTrip(req *http.Request) (*http.Response, error) {
+	var message FCMMessage
+	if err := json.NewDecoder(req.Body).Decode(&message); err != nil {
+		return nil, err
+	}
+	response := FCMResponse{Results: f(message.RegistrationIDs)}
+	for _, result := range response.Results {
+		if result.Error != "" {
+			response.Failure++
+		} else {
+			response.Success++
+		}
+	}
+	body, _ := json.Marshal(response)
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body)), Request: req}, nil
+}
+
+// mockedFCMService returns an FCM service that sends to the stub and stores devices on sqlmock
+func mockedFCMService(t *testing.T, stub fcmStub) (*FCMService, sqlmock.Sqlmock) {
+	t.Helper()
+	mock := dbtest.MockGlobal(t)
+	return &FCMService{
+		serverKey:  "test",
+		client:     &http.Client{Transport: stub},
+		deviceRepo: userRepo.NewDeviceRepository(),
+	}, mock
+}
+
+func expectTokens(mock sqlmock.Sqlmock, tokens ...string) {
+	rows := sqlmock.NewRows([]string{"token"})
+	for _, token := range tokens {
+		rows.AddRow(token)
+	}
+	mock.ExpectQuery(`SELECT "token" FROM "user_devices" WHERE user_id = \$1 AND last_seen_at > \$2`).WillReturnRows(rows)
+}
+
+func TestSendToUserPrunesTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		results map[string]FCMResult
+		pruned  []string
+		wantErr string
+	}{
+		{
+			name:    "all delivered",
+			results: map[string]FCMResult{},
+		},
+		{
+			name: "invalid tokens are removed",
+			results: map[string]FCMResult{
+				"old-phone": {Error: "NotRegistered"},
+				"bad-token": {Error: "InvalidRegistration"},
+			},
+			pruned: []string{"old-phone", "bad-token"},
+		},
+		{
+			name:    "temporary failures keep the token",
+			results: map[string]FCMResult{"old-phone": {Error: "Unavailable"}},
+		},
+		{
+			name: "every device failing is an error",
+			results: map[string]FCMResult{
+				"phone":     {Error: "NotRegistered"},
+				"tablet":    {Error: "NotRegistered"},
+				"old-phone": {Error: "NotRegistered"},
+				"bad-token": {Error: "MismatchSenderId"},
+			},
+			pruned:  []string{"phone", "tablet", "old-phone", "bad-token"},
+			wantErr: "FCM delivery failed for all 4 devices",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := mockedFCMService(t, func(tokens []string) []FCMResult {
+				results := make([]FCMResult, len(tokens))
+				for i, token := range tokens {
+					results[i] = FCMResult{MessageID: "m-" + token}
+					if result, ok := tt.results[token]; ok {
+						results[i] = result
+					}
+				}
+				return results
+			})
+
+			expectTokens(mock, "phone", "tablet", "old-phone", "bad-token")
+			if len(tt.pruned) > 0 {
+				args := make([]driver.Value, len(tt.pruned))
+				for i, token := range tt.pruned {
+					args[i] = token
+				}
+				mock.ExpectBegin()
+				mock.ExpectExec(`(UPDATE|DELETE FROM) "user_devices" .*WHERE token IN`).
+					WithArgs(args...).
+					WillReturnResult(sqlmock.NewResult(0, int64(len(tt.pruned))))
+				mock.ExpectCommit()
+			}
+
+			err := s.SendToUser(context.Background(), uuid.New(), &FCMNotification{Title: "Order ready"}, nil)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSendToUserReplacesCanonicalTokens(t *testing.T) {
+	s, mock := mockedFCMService(t, func(tokens []string) []FCMResult {
+		return []FCMResult{{MessageID: "1", RegistrationID: "phone-v2"}, {MessageID: "2", RegistrationID: "tablet"}}
+	})
+
+	expectTokens(mock, "phone", "tablet")
+	// Only a token FCM reports under a new value is rewritten
+	mock.ExpectBegin()
+	dbtest.ExpectCount(mock, "user_devices", 0)
+	mock.ExpectExec(`UPDATE "user_devices" SET "token"=\$1,"updated_at"=\$2 WHERE token = \$3`).
+		WithArgs("phone-v2", sqlmock.AnyArg(), "phone").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, s.SendToUser(context.Background(), uuid.New(), &FCMNotification{Title: "Order ready"}, nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSendToUserBatches(t *testing.T) {
+	var batches []int
+	s, mock := mockedFCMService(t, func(tokens []string) []FCMResult {
+		batches = append(batches, len(tokens))
+		return make([]FCMResult, len(tokens))
+	})
+
+	tokens := make([]string, fcmMaxRegistrationIDs+1)
+	for i := range tokens {
+		tokens[i] = fmt.Sprintf("token-%d", i)
+	}
+	expectTokens(mock, tokens...)
+
+	assert.NoError(t, s.SendToUser(context.Background(), uuid.New(), &FCMNotification{Title: "Promo"}, nil))
+	assert.Equal(t, []int{fcmMaxRegistrationIDs, 1}, batches)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,9 +2,11 @@ package service
 
 import (
 	"context"
+	"log"
 	notificationRepo "service/internal/modules/notification/repository"
 	orderRepo "service/internal/modules/orders/repository"
 	userRepo "service/internal/modules/users/repository"
+	"service/internal/shared/config"
 	"service/internal/shared/model"
 
 	"github.com/google/uuid"
@@ -15,6 +17,7 @@ type NotificationService struct {
 	notificationRepo *notificationRepo.NotificationRepository
 	userRepo         *userRepo.UserRepository
 	orderRepo        *orderRepo.ServiceOrderRepository
+	fcmService       *FCMService
 }
 
 // NewNotificationService creates a new notification service
@@ -23,6 +26,7 @@ func NewNotificationService() *NotificationService {
 		notificationRepo: notificationRepo.NewNotificationRepository(),
 		userRepo:         userRepo.NewUserRepository(),
 		orderRepo:        orderRepo.NewServiceOrderRepository(),
+		fcmService:       NewFCMService(),
 	}
 }
 
@@ -34,7 +38,7 @@ func (s *NotificationService) SendNotification(ctx context.Context, req *model.N
 		return nil, err
 	}
 
-	_, err = s.userRepo.GetByID(ctx, userID)
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, model.ErrUserNotFound
 	}
@@ -65,8 +69,20 @@ func (s *NotificationService) SendNotification(ctx context.Context, req *model.N
 	}
 
 	// TODO: Send actual notification based on type
-	// For now, just mark as sent
-	notification.Status = model.NotificationStatusSent
+	// For now, push goes out to every device of the user and the rest is marked as sent
+	// unless the contact is unverified
+	notification.Status = deliveryStatus(user, notification.Type)
+	if notification.Type == model.NotificationTypePush {
+		err := s.fcmService.SendToUser(ctx, userID, &FCMNotification{
+			Title: notification.Title,
+			Body:  notification.Message,
+			Sound: SoundDefault,
+		}, pushData(notification))
+		if err != nil {
+			log.Printf("Failed to send push notification %s: %v", notification.ID, err)
+			notification.Status = model.NotificationStatusFailed
+		}
+	}
 	s.notificationRepo.Update(ctx, notification)
 
 	response := notification.ToResponse()
@@ -81,6 +97,11 @@ func (s *NotificationService) SendOrderStatusNotification(ctx context.Context, o
 		return err
 	}
 
+	customer, err := s.userRepo.GetByID(ctx, order.CustomerID)
+	if err != nil {
+		return model.ErrUserNotFound
+	}
+
 	// Create notification for customer
 	notification := &model.Notification{
 		UserID:  order.CustomerID,
@@ -97,7 +118,7 @@ func (s *NotificationService) SendOrderStatusNotification(ctx context.Context, o
 	}
 
 	// TODO: Send actual notification
-	notification.Status = model.NotificationStatusSent
+	notification.Status = deliveryStatus(customer, notification.Type)
 	s.notificationRepo.Update(ctx, notification)
 
 	return nil
@@ -111,6 +132,11 @@ func (s *NotificationService) SendPaymentNotification(ctx context.Context, order
 		return err
 	}
 
+	customer, err := s.userRepo.GetByID(ctx, order.CustomerID)
+	if err != nil {
+		return model.ErrUserNotFound
+	}
+
 	// Create notification for customer
 	notification := &model.Notification{
 		UserID:  order.CustomerID,
@@ -127,12 +153,44 @@ func (s *NotificationService) SendPaymentNotification(ctx context.Context, order
 	}
 
 	// TODO: Send actual notification
-	notification.Status = model.NotificationStatusSent
+	notification.Status = deliveryStatus(customer, notification.Type)
 	s.notificationRepo.Update(ctx, notification)
 
 	return nil
 }
 
+// deliveryStatus decides whether a notification may go out to the user's contact.
+// Email, WhatsApp and SMS are held back from unverified contacts when configured, so
+// that someone registering with another person's number does not receive their messages.
+func deliveryStatus(user *model.User, notificationType model.NotificationType) model.NotificationStatus {
+	if config.Config == nil || !config.Config.RequireVerifiedContactMessages {
+		return model.NotificationStatusSent
+	}
+
+	switch notificationType {
+	case model.NotificationTypeEmail:
+		if user.EmailVerifiedAt == nil {
+			return model.NotificationStatusSkipped
+		}
+	case model.NotificationTypeWhatsApp, model.NotificationTypeSMS:
+		if user.PhoneVerifiedAt == nil {
+			return model.NotificationStatusSkipped
+		}
+	}
+	return model.NotificationStatusSent
+}
+
+// pushData builds the data payload the apps use to open the related screen
+func pushData(notification *model.Notification) map[string]interface{} {
+	data := map[string]interface{}{
+		"notification_id": notification.ID.String(),
+	}
+	if notification.OrderID != nil {
+		data["order_id"] = notification.OrderID.String()
+	}
+	return data
+}
+
 // GetNotifications retrieves notifications for a user
 func (s *NotificationService) GetNotifications(ctx context.Context, userID uuid.UUID, page, limit int) (*model.PaginatedResponse, error) {
 	offset := (page - 1) * limit
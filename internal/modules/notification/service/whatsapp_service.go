@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"service/internal/shared/config"
+	"strings"
+	"time"
+)
+
+// WhatsAppService handles outgoing WhatsApp messages through the configured gateway
+type WhatsAppService struct {
+	apiKey string
+	apiURL string
+	client *http.Client
+}
+
+// NewWhatsAppService creates a new WhatsApp service
+func NewWhatsAppService() *WhatsAppService {
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+	// Without configuration (e.g. during tests) messages are only logged
+	if config.Config == nil {
+		return &WhatsAppService{client: client}
+	}
+	return &WhatsAppService{
+		apiKey: config.Config.WhatsAppAPIKey,
+		apiURL: config.Config.WhatsAppAPIURL,
+		client: client,
+	}
+}
+
+// Send sends a text message to a phone number
+func (s *WhatsAppService) Send(ctx context.Context, phone, message string) error {
+	if s.apiKey == "" {
+		log.Printf("💬 Mock WhatsApp message (WHATSAPP_API_KEY not set) to %s: %s", phone, message)
+		return nil
+	}
+
+	if phone == "" {
+		return fmt.Errorf("phone number is empty")
+	}
+
+	form := url.Values{}
+	form.Set("target", phone)
+	form.Set("message", message)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.apiURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create WhatsApp request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", s.apiKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send WhatsApp request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("WhatsApp API error: status=%d", resp.StatusCode)
+	}
+
+	log.Printf("✅ WhatsApp message sent to %s", phone)
+	return nil
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"net/http"
+	"service/internal/modules/numbering/service"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// NumberingHandler handles the number format endpoints of branches
+type NumberingHandler struct {
+	numberingService *service.NumberingService
+}
+
+// NewNumberingHandler creates a new numbering handler
+func NewNumberingHandler() *NumberingHandler {
+	return &NumberingHandler{
+		numberingService: service.NewNumberingService(),
+	}
+}
+
+// GetFormats godoc
+// @Summary Get branch number formats (admin)
+// @Description Get the order and invoice number formats of a branch with the next number each gives out. Numbers count up per branch and start again every year.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Branch ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /admin/branches/{id}/numbering [get]
+func (h *NumberingHandler) GetFormats(c *gin.Context) {
+	branchID, ok := parseID(c, "Invalid branch ID")
+	if !ok {
+		return
+	}
+
+	formats, err := h.numberingService.ListFormats(c.Request.Context(), branchID)
+	if err != nil {
+		respondNumberingError(c, "numbering_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(formats, "Number formats retrieved successfully"))
+}
+
+// UpdateFormat godoc
+// @Summary Set branch number format (admin)
+// @Description Set the order or invoice number format of a branch, e.g. {branch}-{yyyy}-{seq:6}. The format must hold {branch}, {yyyy} or {yy} and {seq} or {seq:N}. Numbers already given out are kept and the sequence carries on.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Branch ID"
+// @Param kind path string true "order or invoice"
+// @Param request body model.NumberingFormatRequest true "Number format"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /admin/branches/{id}/numbering/{kind} [put]
+func (h *NumberingHandler) UpdateFormat(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	branchID, ok := parseID(c, "Invalid branch ID")
+	if !ok {
+		return
+	}
+
+	var req model.NumberingFormatRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	format, err := h.numberingService.SetFormat(c.Request.Context(), branchID, userID, model.NumberingKind(c.Param("kind")), &req)
+	if err != nil {
+		respondNumberingError(c, "numbering_update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(format, "Number format updated successfully"))
+}
+
+// currentUserID reads the authenticated user ID, writing an error response when it is missing
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, model.CreateErrorResponse(
+			"unauthorized",
+			"User not authenticated",
+			nil,
+		))
+		return uuid.Nil, false
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+			"internal_error",
+			"Invalid user ID type",
+			nil,
+		))
+		return uuid.Nil, false
+	}
+
+	return userUUID, true
+}
+
+// parseID parses the :id path parameter, writing an error response when it is invalid
+func parseID(c *gin.Context, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse("invalid_id", message, nil))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// bindRequest binds, sanitizes and validates a JSON payload
+func bindRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return false
+	}
+
+	utils.SanitizeStructStrings(req)
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return false
+	}
+	return true
+}
+
+// respondNumberingError writes the error response of a failed numbering request
+func respondNumberingError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case model.ErrInvalidNumberFormat, model.ErrInvalidNumberingKind:
+		status = http.StatusBadRequest
+	case model.ErrBranchNotFound:
+		status = http.StatusNotFound
+	case model.ErrForbidden:
+		status = http.StatusForbidden
+	}
+	c.JSON(status, model.CreateErrorResponse(code, err.Error(), nil))
+}
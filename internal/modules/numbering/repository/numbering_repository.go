@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NumberingRepository handles the number formats of branches and the counters numbers
+// are taken from
+type NumberingRepository struct {
+	db *gorm.DB
+}
+
+// NewNumberingRepository creates a new numbering repository
+func NewNumberingRepository() *NumberingRepository {
+	return &NumberingRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *NumberingRepository) Available() bool {
+	return r.db != nil
+}
+
+// ListFormats retrieves the number formats a branch configured
+func (r *NumberingRepository) ListFormats(ctx context.Context, branchID uuid.UUID) ([]*model.NumberingFormat, error) {
+	var formats []*model.NumberingFormat
+	err := r.db.WithContext(ctx).
+		Where("branch_id = ?", branchID).
+		Order("kind ASC").
+		Find(&formats).Error
+	return formats, err
+}
+
+// GetFormat retrieves the number format a branch configured for a kind
+func (r *NumberingRepository) GetFormat(ctx context.Context, branchID uuid.UUID, kind model.NumberingKind) (*model.NumberingFormat, error) {
+	var format model.NumberingFormat
+	if err := r.db.WithContext(ctx).First(&format, "branch_id = ? AND kind = ?", branchID, kind).Error; err != nil {
+		return nil, err
+	}
+	return &format, nil
+}
+
+// SaveFormat creates or replaces the number format of a branch for a kind
+func (r *NumberingRepository) SaveFormat(ctx context.Context, format *model.NumberingFormat) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "branch_id"}, {Name: "kind"}},
+			DoUpdates: clause.AssignmentColumns([]string{"format", "updated_by", "updated_at"}),
+		}).
+		Create(format).Error
+}
+
+// LastValue returns the last number taken from the counter of a branch and year
+func (r *NumberingRepository) LastValue(ctx context.Context, branchID uuid.UUID, kind model.NumberingKind, year int) (int64, error) {
+	var last int64
+	err := r.db.WithContext(ctx).Model(&model.NumberSequence{}).
+		Where("branch_id = ? AND kind = ? AND year = ?", branchID, kind, year).
+		Select("COALESCE(MAX(last_value), 0)").
+		Scan(&last).Error
+	return last, err
+}
+
+// Next takes the next number from the counter of a branch and year on its own. Numbers
+// taken this way are lost when the caller fails afterwards; use Allocate inside the
+// caller's transaction where gaps are not allowed.
+func (r *NumberingRepository) Next(ctx context.Context, branchID uuid.UUID, kind model.NumberingKind, year int) (int64, error) {
+	return Allocate(r.db.WithContext(ctx), branchID, kind, year)
+}
+
+// Allocate takes the next number from the counter of a branch and year within the given
+// transaction. The counter row stays locked until the transaction ends and the number is
+// given back when it rolls back, so concurrent callers get consecutive numbers without gaps.
+func Allocate(tx *gorm.DB, branchID uuid.UUID, kind model.NumberingKind, year int) (int64, error) {
+	var value int64
+	err := tx.Raw(`INSERT INTO number_sequences (branch_id, kind, year, last_value, updated_at)
+		VALUES (?, ?, ?, 1, NOW())
+		ON CONFLICT (branch_id, kind, year)
+		DO UPDATE SET last_value = number_sequences.last_value + 1, updated_at = NOW()
+		RETURNING last_value`, branchID, kind, year).
+		Scan(&value).Error
+	return value, err
+}
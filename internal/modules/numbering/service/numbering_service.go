@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	branchRepo "service/internal/modules/branches/repository"
+	"service/internal/modules/numbering/repository"
+	"service/internal/shared/config"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NumberingService gives out the order and invoice numbers of branches. Numbers count up
+// per branch and start again every year.
+type NumberingService struct {
+	numberingRepo *repository.NumberingRepository
+	branchRepo    *branchRepo.BranchRepository
+}
+
+// NewNumberingService creates a new numbering service
+func NewNumberingService() *NumberingService {
+	return &NumberingService{
+		numberingRepo: repository.NewNumberingRepository(),
+		branchRepo:    branchRepo.NewBranchRepository(),
+	}
+}
+
+// NextOrderNumber returns the next order number of a branch. Order numbers may skip a
+// value when creating the order fails; only invoice numbers are gapless.
+func (s *NumberingService) NextOrderNumber(ctx context.Context, branchID uuid.UUID) (string, error) {
+	if !s.numberingRepo.Available() {
+		return utils.GenerateOrderNumber(), nil
+	}
+
+	scheme, err := s.Scheme(ctx, branchID, model.NumberingKindOrder)
+	if err != nil {
+		return "", err
+	}
+	year := time.Now().Year()
+	seq, err := s.numberingRepo.Next(ctx, branchID, model.NumberingKindOrder, year)
+	if err != nil {
+		return "", err
+	}
+	return scheme.Render(year, seq), nil
+}
+
+// InvoiceScheme returns how the invoice numbers of a branch are rendered. The number itself
+// is taken when the payment or corporate invoice is stored, in the same transaction. It is
+// nil without a database.
+func (s *NumberingService) InvoiceScheme(ctx context.Context, branchID uuid.UUID) (*model.NumberScheme, error) {
+	if !s.numberingRepo.Available() {
+		return nil, nil
+	}
+	return s.Scheme(ctx, branchID, model.NumberingKindInvoice)
+}
+
+// Scheme returns the branch code and the number format a branch uses for a kind
+func (s *NumberingService) Scheme(ctx context.Context, branchID uuid.UUID, kind model.NumberingKind) (*model.NumberScheme, error) {
+	branch, err := s.branchRepo.GetByID(ctx, branchID)
+	if err != nil {
+		return nil, model.ErrBranchNotFound
+	}
+
+	scheme := &model.NumberScheme{
+		BranchID:   branchID,
+		Kind:       kind,
+		BranchCode: branch.NumberingCode(),
+		Format:     defaultFormat(kind),
+	}
+	if format, err := s.numberingRepo.GetFormat(ctx, branchID, kind); err == nil {
+		scheme.Format = format.Format
+	}
+	return scheme, nil
+}
+
+// ListFormats returns the number formats of a branch with the next number of each
+func (s *NumberingService) ListFormats(ctx context.Context, branchID uuid.UUID) ([]*model.NumberingFormatResponse, error) {
+	if !model.BranchScopeFromContext(ctx).Allows(branchID) {
+		return nil, model.ErrForbidden
+	}
+	if !s.numberingRepo.Available() {
+		return nil, errors.New("numbering is not available")
+	}
+
+	configured, err := s.numberingRepo.ListFormats(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+	isConfigured := make(map[model.NumberingKind]bool, len(configured))
+	for _, format := range configured {
+		isConfigured[format.Kind] = true
+	}
+
+	responses := make([]*model.NumberingFormatResponse, 0, 2)
+	for _, kind := range []model.NumberingKind{model.NumberingKindOrder, model.NumberingKindInvoice} {
+		response, err := s.formatResponse(ctx, branchID, kind)
+		if err != nil {
+			return nil, err
+		}
+		response.IsDefault = !isConfigured[kind]
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+// SetFormat sets the number format a branch uses for a kind. The sequence carries on, so
+// numbers given out after the change continue from the last one.
+func (s *NumberingService) SetFormat(ctx context.Context, branchID, userID uuid.UUID, kind model.NumberingKind, req *model.NumberingFormatRequest) (*model.NumberingFormatResponse, error) {
+	if !model.BranchScopeFromContext(ctx).Allows(branchID) {
+		return nil, model.ErrForbidden
+	}
+	if !kind.IsValid() {
+		return nil, model.ErrInvalidNumberingKind
+	}
+	if err := model.ValidateNumberFormat(req.Format); err != nil {
+		return nil, err
+	}
+	if !s.numberingRepo.Available() {
+		return nil, errors.New("numbering is not available")
+	}
+	if _, err := s.branchRepo.GetByID(ctx, branchID); err != nil {
+		return nil, model.ErrBranchNotFound
+	}
+
+	format := &model.NumberingFormat{
+		BranchID:  branchID,
+		Kind:      kind,
+		Format:    req.Format,
+		UpdatedBy: userID,
+	}
+	if err := s.numberingRepo.SaveFormat(ctx, format); err != nil {
+		return nil, err
+	}
+	return s.formatResponse(ctx, branchID, kind)
+}
+
+// formatResponse renders the number the branch gives out next for a kind
+func (s *NumberingService) formatResponse(ctx context.Context, branchID uuid.UUID, kind model.NumberingKind) (*model.NumberingFormatResponse, error) {
+	scheme, err := s.Scheme(ctx, branchID, kind)
+	if err != nil {
+		return nil, err
+	}
+	year := time.Now().Year()
+	last, err := s.numberingRepo.LastValue(ctx, branchID, kind, year)
+	if err != nil {
+		return nil, err
+	}
+	return &model.NumberingFormatResponse{
+		Kind:       kind,
+		Format:     scheme.Format,
+		NextNumber: scheme.Render(year, last+1),
+	}, nil
+}
+
+// defaultFormat returns the configured format of a kind, or the built-in one when it is
+// not configured or not valid
+func defaultFormat(kind model.NumberingKind) string {
+	format := model.DefaultOrderNumberFormat
+	if kind == model.NumberingKindInvoice {
+		format = model.DefaultInvoiceNumberFormat
+	}
+	if config.Config == nil {
+		return format
+	}
+
+	configured := config.Config.OrderNumberFormat
+	if kind == model.NumberingKindInvoice {
+		configured = config.Config.InvoiceNumberFormat
+	}
+	if model.ValidateNumberFormat(configured) == nil {
+		return configured
+	}
+	return format
+}
@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"net/http"
+	"service/internal/modules/orders/service"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AssignmentHandler handles technician assignment, workload and profile endpoints
+type AssignmentHandler struct {
+	assignmentService *service.AssignmentService
+}
+
+// NewAssignmentHandler creates a new assignment handler
+func NewAssignmentHandler() *AssignmentHandler {
+	return &AssignmentHandler{
+		assignmentService: service.NewAssignmentService(),
+	}
+}
+
+// AutoAssign godoc
+// @Summary Assign a technician automatically
+// @Description Assign the best available technician of the order's branch. Without a strategy the configured one is used.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body model.AutoAssignRequest false "Assignment strategy"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /orders/{id}/auto-assign [post]
+func (h *AssignmentHandler) AutoAssign(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid order ID format",
+			nil,
+		))
+		return
+	}
+
+	// The body is optional
+	var req model.AutoAssignRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+				"validation_error",
+				"Invalid request data",
+				err.Error(),
+			))
+			return
+		}
+		if err := utils.ValidateStruct(&req); err != nil {
+			c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+				"validation_error",
+				"Validation failed",
+				err.Error(),
+			))
+			return
+		}
+	}
+
+	order, err := h.assignmentService.AutoAssign(c.Request.Context(), orderID, req.Strategy)
+	if err != nil {
+		respondAssignmentError(c, "auto_assign_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(order, "Technician assigned successfully"))
+}
+
+// GetCandidates godoc
+// @Summary List technician candidates
+// @Description List the technicians of the order's branch ranked for the order, best first, with the reason when someone is not eligible
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param strategy query string false "Strategy (round_robin, least_loaded, skill_weighted)"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /orders/{id}/technician-candidates [get]
+func (h *AssignmentHandler) GetCandidates(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid order ID format",
+			nil,
+		))
+		return
+	}
+
+	strategy := model.AssignmentStrategy(c.Query("strategy"))
+	if strategy != "" && !model.IsValidAssignmentStrategy(strategy) {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_strategy",
+			"Strategy must be one of round_robin, least_loaded, skill_weighted",
+			nil,
+		))
+		return
+	}
+
+	candidates, err := h.assignmentService.GetCandidates(c.Request.Context(), orderID, strategy)
+	if err != nil {
+		respondAssignmentError(c, "candidates_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(candidates, "Technician candidates retrieved successfully"))
+}
+
+// GetBranchWorkload godoc
+// @Summary Get technician workload of a branch
+// @Description List the technicians of a branch with their open orders, shift and recent rating
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Branch ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /branches/{id}/technician-workload [get]
+func (h *AssignmentHandler) GetBranchWorkload(c *gin.Context) {
+	branchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid branch ID format",
+			nil,
+		))
+		return
+	}
+
+	workload, err := h.assignmentService.GetBranchWorkload(c.Request.Context(), branchID)
+	if err != nil {
+		respondAssignmentError(c, "workload_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(workload, "Technician workload retrieved successfully"))
+}
+
+// GetTechnicianProfile godoc
+// @Summary Get technician profile (admin)
+// @Description Get the skills and weekly shifts of a technician
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Technician ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /admin/technicians/{id}/profile [get]
+func (h *AssignmentHandler) GetTechnicianProfile(c *gin.Context) {
+	technicianID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid technician ID format",
+			nil,
+		))
+		return
+	}
+
+	profile, err := h.assignmentService.GetProfile(c.Request.Context(), technicianID)
+	if err != nil {
+		respondAssignmentError(c, "technician_profile_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(profile, "Technician profile retrieved successfully"))
+}
+
+// UpdateTechnicianSkills godoc
+// @Summary Update technician skills (admin)
+// @Description Replace the skill levels of a technician per service type
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Technician ID"
+// @Param request body model.TechnicianSkillsRequest true "Skills"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /admin/technicians/{id}/skills [put]
+func (h *AssignmentHandler) UpdateTechnicianSkills(c *gin.Context) {
+	technicianID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid technician ID format",
+			nil,
+		))
+		return
+	}
+
+	var req model.TechnicianSkillsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return
+	}
+
+	profile, err := h.assignmentService.UpdateSkills(c.Request.Context(), technicianID, &req)
+	if err != nil {
+		respondAssignmentError(c, "technician_skills_update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(profile, "Technician skills updated successfully"))
+}
+
+// UpdateTechnicianShifts godoc
+// @Summary Update technician shifts (admin)
+// @Description Replace the weekly shifts of a technician. Technicians without shifts are always available.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Technician ID"
+// @Param request body model.TechnicianShiftsRequest true "Shifts"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /admin/technicians/{id}/shifts [put]
+func (h *AssignmentHandler) UpdateTechnicianShifts(c *gin.Context) {
+	technicianID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid technician ID format",
+			nil,
+		))
+		return
+	}
+
+	var req model.TechnicianShiftsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return
+	}
+
+	profile, err := h.assignmentService.UpdateShifts(c.Request.Context(), technicianID, &req)
+	if err != nil {
+		respondAssignmentError(c, "technician_shifts_update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(profile, "Technician shifts updated successfully"))
+}
+
+// respondAssignmentError maps assignment errors to HTTP status codes
+func respondAssignmentError(c *gin.Context, code string, err error) {
+	status := http.StatusBadRequest
+	switch err {
+	case model.ErrOrderNotFound, model.ErrUserNotFound:
+		status = http.StatusNotFound
+	case model.ErrOrderAlreadyAssigned, model.ErrNoTechnicianAvailable:
+		status = http.StatusConflict
+	case model.ErrForbidden:
+		status = http.StatusForbidden
+	}
+	c.JSON(status, model.CreateErrorResponse(code, err.Error(), nil))
+}
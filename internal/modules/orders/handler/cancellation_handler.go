@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"net/http"
+	"service/internal/modules/orders/service"
+	"service/internal/shared/middleware"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CancellationHandler handles order cancellation, the cancellation fee schedule and
+// cancellation report endpoints
+type CancellationHandler struct {
+	orderService        *service.OrderService
+	cancellationService *service.CancellationService
+}
+
+// NewCancellationHandler creates a new cancellation handler
+func NewCancellationHandler() *CancellationHandler {
+	return &CancellationHandler{
+		orderService:        service.NewOrderService(),
+		cancellationService: service.NewCancellationService(),
+	}
+}
+
+// CancelOrder godoc
+// @Summary Cancel order
+// @Description Cancel an order with a reason code. The fee scheduled for how far the order progressed is charged, or deducted from what was already paid with the rest refunded. Parts recorded on the order go back into stock. Customers can only cancel their own orders. Staff need the order.update_status permission, and technicians and couriers can only cancel orders assigned to them. Waiving the fee needs the order.waive_fee permission. Staff cancellations that refund payments need the payment.refund permission; customers cancelling their own order are refunded without it. Returns 409 when the order or its payments changed meanwhile; nothing is cancelled then.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body model.CancelOrderRequest true "Cancellation reason"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /orders/{id}/cancel [post]
+func (h *CancellationHandler) CancelOrder(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid order ID format",
+			nil,
+		))
+		return
+	}
+
+	userID, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+	userRole, _ := c.Get("user_role")
+	role, _ := userRole.(model.UserRole)
+
+	var req model.CancelOrderRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	grants := model.CancellationGrants{
+		UpdateStatus: middleware.HasPermissions(c, model.PermissionOrderUpdateStatus),
+		WaiveFee:     middleware.HasPermissions(c, model.PermissionOrderWaiveFee),
+		Refund:       middleware.HasPermissions(c, model.PermissionPaymentRefund),
+	}
+	result, err := h.orderService.CancelOrder(c.Request.Context(), orderID, userID, role, &req, grants)
+	if err != nil {
+		respondCancellationError(c, "order_cancel_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(result, "Order cancelled successfully"))
+}
+
+// GetFeeSchedule godoc
+// @Summary Get cancellation fee schedule
+// @Description Get the fee charged for cancelling an order in each status, showing which fees override the configured defaults
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse
+// @Router /admin/cancellation-fees [get]
+func (h *CancellationHandler) GetFeeSchedule(c *gin.Context) {
+	schedule, err := h.cancellationService.FeeSchedule(c.Request.Context())
+	if err != nil {
+		respondCancellationError(c, "fee_schedule_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(schedule, "Cancellation fee schedule retrieved successfully"))
+}
+
+// UpdateFeeSchedule godoc
+// @Summary Update cancellation fee schedule
+// @Description Override the fee charged for cancelling an order in the given statuses
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.CancellationFeeScheduleRequest true "Fees by status"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Router /admin/cancellation-fees [put]
+func (h *CancellationHandler) UpdateFeeSchedule(c *gin.Context) {
+	userID, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req model.CancellationFeeScheduleRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	schedule, err := h.cancellationService.UpdateFeeSchedule(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondCancellationError(c, "fee_schedule_update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(schedule, "Cancellation fee schedule updated successfully"))
+}
+
+// GetCancellationReport godoc
+// @Summary Get cancellation report
+// @Description Get cancellation analytics for a period: cancel rate, cancellations by reason and by stage, fees charged or waived, refunds and released parts. Defaults to the current month.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param branch_id query string false "Branch ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /reports/cancellations [get]
+func (h *CancellationHandler) GetCancellationReport(c *gin.Context) {
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	if startStr := c.Query("start_date"); startStr != "" {
+		parsed, err := time.Parse("2006-01-02", startStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.CreateErrorResponse("validation_error", "Invalid start_date (use YYYY-MM-DD)", nil))
+			return
+		}
+		start = parsed
+	}
+	if endStr := c.Query("end_date"); endStr != "" {
+		parsed, err := time.Parse("2006-01-02", endStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.CreateErrorResponse("validation_error", "Invalid end_date (use YYYY-MM-DD)", nil))
+			return
+		}
+		end = parsed.AddDate(0, 0, 1)
+	}
+	if !end.After(start) {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse("validation_error", "end_date must not be before start_date", nil))
+		return
+	}
+
+	var branchID *uuid.UUID
+	if branchIDStr := c.Query("branch_id"); branchIDStr != "" {
+		parsed, err := uuid.Parse(branchIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.CreateErrorResponse("invalid_id", "Invalid branch ID", nil))
+			return
+		}
+		branchID = &parsed
+	}
+
+	report, err := h.cancellationService.Report(c.Request.Context(), start, end, branchID)
+	if err != nil {
+		respondCancellationError(c, "report_generation_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(report, "Cancellation report generated successfully"))
+}
+
+// bindRequest binds and validates a JSON request body, responding with 400 on failure
+func bindRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return false
+	}
+	utils.SanitizeStructStrings(req)
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return false
+	}
+	return true
+}
+
+// respondCancellationError maps cancellation errors to HTTP status codes
+func respondCancellationError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case model.ErrCancellationNoteRequired, model.ErrInvalidCancellationStatus:
+		status = http.StatusBadRequest
+	case model.ErrForbidden, model.ErrNotOrderCourier, model.ErrCancellationRefundDenied, model.ErrCancellationWaiveDenied:
+		status = http.StatusForbidden
+	case model.ErrOrderNotFound:
+		status = http.StatusNotFound
+	case model.ErrOrderNotCancellable, model.ErrVersionConflict:
+		status = http.StatusConflict
+	}
+	c.JSON(status, model.CreateErrorResponse(code, err.Error(), nil))
+}
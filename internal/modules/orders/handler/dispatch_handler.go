@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"net/http"
+	"service/internal/modules/orders/service"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DispatchHandler handles courier route planning endpoints
+type DispatchHandler struct {
+	dispatchService *service.DispatchService
+}
+
+// NewDispatchHandler creates a new dispatch handler
+func NewDispatchHandler() *DispatchHandler {
+	return &DispatchHandler{
+		dispatchService: service.NewDispatchService(),
+	}
+}
+
+// PlanRoutes godoc
+// @Summary Plan courier routes
+// @Description Group the open pickups and deliveries of a branch by proximity into multi-stop routes that respect time windows. Replaces the routes no courier has taken yet.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Branch ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /branches/{id}/dispatch-plan [post]
+func (h *DispatchHandler) PlanRoutes(c *gin.Context) {
+	branchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid branch ID format",
+			nil,
+		))
+		return
+	}
+
+	plan, err := h.dispatchService.PlanRoutes(c.Request.Context(), branchID)
+	if err != nil {
+		respondDispatchError(c, "dispatch_plan_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(plan, "Courier routes planned successfully"))
+}
+
+// GetCourierRoutes godoc
+// @Summary Get proposed routes
+// @Description Get the proposed multi-stop routes of the courier's branch, best taken as a whole
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /courier/routes [get]
+func (h *DispatchHandler) GetCourierRoutes(c *gin.Context) {
+	courierID, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	plan, err := h.dispatchService.GetCourierRoutes(c.Request.Context(), courierID)
+	if err != nil {
+		respondDispatchError(c, "routes_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(plan, "Courier routes retrieved successfully"))
+}
+
+// AcceptRoute godoc
+// @Summary Accept route
+// @Description Take every pickup and delivery of a proposed route at once
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Route ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /courier/routes/{id}/accept [post]
+func (h *DispatchHandler) AcceptRoute(c *gin.Context) {
+	routeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid route ID format",
+			nil,
+		))
+		return
+	}
+
+	courierID, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	route, err := h.dispatchService.AcceptRoute(c.Request.Context(), routeID, courierID)
+	if err != nil {
+		respondDispatchError(c, "accept_route_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(route, "Route accepted successfully"))
+}
+
+// ListMyRoutes godoc
+// @Summary List my routes
+// @Description List the routes the courier has accepted and not finished yet
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Router /courier/my-routes [get]
+func (h *DispatchHandler) ListMyRoutes(c *gin.Context) {
+	courierID, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	routes, err := h.dispatchService.ListMyRoutes(c.Request.Context(), courierID)
+	if err != nil {
+		respondDispatchError(c, "routes_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(routes, "Routes retrieved successfully"))
+}
+
+// SetDeliveryWindow godoc
+// @Summary Set delivery window
+// @Description Choose when the repaired device should be brought back. Couriers' routes respect the window.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body model.DeliveryWindowRequest true "Delivery window"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /orders/{id}/delivery-window [put]
+func (h *DispatchHandler) SetDeliveryWindow(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid order ID format",
+			nil,
+		))
+		return
+	}
+
+	userID, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+	userRole, _ := c.Get("user_role")
+	role, _ := userRole.(model.UserRole)
+
+	var req model.DeliveryWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return
+	}
+
+	order, err := h.dispatchService.SetDeliveryWindow(c.Request.Context(), orderID, userID, role, &req)
+	if err != nil {
+		respondDispatchError(c, "delivery_window_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(order, "Delivery window updated successfully"))
+}
+
+// userFromContext reads the authenticated user ID, responding with an error when it is missing
+func userFromContext(c *gin.Context) (uuid.UUID, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, model.CreateErrorResponse(
+			"unauthorized",
+			"User ID not found in context",
+			nil,
+		))
+		return uuid.Nil, false
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+			"internal_error",
+			"Invalid user ID type",
+			nil,
+		))
+		return uuid.Nil, false
+	}
+	return userUUID, true
+}
+
+// respondDispatchError maps dispatch errors to HTTP status codes
+func respondDispatchError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case model.ErrInvalidTimeWindow, model.ErrNotCourier:
+		status = http.StatusBadRequest
+	case model.ErrRouteNotFound, model.ErrOrderNotFound, model.ErrBranchNotFound, model.ErrUserNotFound:
+		status = http.StatusNotFound
+	case model.ErrRouteNotProposed, model.ErrRouteStale, model.ErrDeliveryWindowState:
+		status = http.StatusConflict
+	case model.ErrForbidden:
+		status = http.StatusForbidden
+	}
+	c.JSON(status, model.CreateErrorResponse(code, err.Error(), nil))
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"net/http"
+	"service/internal/modules/orders/service"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// HandoverHandler handles proof of handover endpoints
+type HandoverHandler struct {
+	orderService *service.OrderService
+}
+
+// NewHandoverHandler creates a new handover handler
+func NewHandoverHandler() *HandoverHandler {
+	return &HandoverHandler{
+		orderService: service.NewOrderService(),
+	}
+}
+
+// CompleteHandover godoc
+// @Summary Complete handover
+// @Description Prove a handover between courier and customer with the customer's one-time code or a signature, a photo of the device and the GPS position. Moves the order into the branch after a pickup, or to delivered after a delivery. Refused attempts are logged.
+// @Tags orders
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param code formData string false "Handover code from the customer"
+// @Param latitude formData number true "Latitude"
+// @Param longitude formData number true "Longitude"
+// @Param accuracy formData number false "GPS accuracy in meters"
+// @Param captured_at formData string false "When the photo was taken (RFC 3339)"
+// @Param notes formData string false "Notes"
+// @Param photo formData file true "Photo of the device"
+// @Param signature formData file false "Customer signature image"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 422 {object} model.ErrorResponse
+// @Router /courier/orders/{id}/handover [post]
+func (h *HandoverHandler) CompleteHandover(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid order ID format",
+			nil,
+		))
+		return
+	}
+
+	userID, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+	userRole, _ := c.Get("user_role")
+	role, _ := userRole.(model.UserRole)
+
+	var req model.HandoverRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return
+	}
+
+	// Missing files are checked by the service so that the attempt is logged
+	photo, _ := c.FormFile("photo")
+	signature, _ := c.FormFile("signature")
+
+	proof, err := h.orderService.CompleteHandover(c.Request.Context(), orderID, userID, role, &req, photo, signature)
+	if err != nil {
+		respondHandoverError(c, "handover_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(proof, "Handover completed successfully"))
+}
+
+// RequestHandoverCode godoc
+// @Summary Get a new handover code
+// @Description Issue a new one-time code for the next handover of the customer's order. The code is also sent over WhatsApp when the customer's phone number is verified, and replaces any earlier code.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 429 {object} model.ErrorResponse
+// @Router /orders/{id}/handover-code [post]
+func (h *HandoverHandler) RequestHandoverCode(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid order ID format",
+			nil,
+		))
+		return
+	}
+
+	customerID, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	code, err := h.orderService.RequestHandoverCode(c.Request.Context(), orderID, customerID)
+	if err != nil {
+		respondHandoverError(c, "handover_code_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(code, "Handover code issued successfully"))
+}
+
+// GetHandoverHistory godoc
+// @Summary Get handover history
+// @Description Get the handover proofs of an order with links to their photos and signatures, and the refused attempts
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /orders/{id}/handovers [get]
+func (h *HandoverHandler) GetHandoverHistory(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid order ID format",
+			nil,
+		))
+		return
+	}
+
+	history, err := h.orderService.GetHandoverHistory(c.Request.Context(), orderID)
+	if err != nil {
+		respondHandoverError(c, "handover_history_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(history, "Handover history retrieved successfully"))
+}
+
+// respondHandoverError maps handover errors to HTTP status codes
+func respondHandoverError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case model.ErrHandoverProofMissing, model.ErrHandoverPhotoRequired, model.ErrHandoverInvalidImage:
+		status = http.StatusBadRequest
+	case model.ErrOrderNotFound:
+		status = http.StatusNotFound
+	case model.ErrNotOrderCourier:
+		status = http.StatusForbidden
+	case model.ErrHandoverNotExpected:
+		status = http.StatusConflict
+	case model.ErrInvalidHandoverCode:
+		status = http.StatusUnprocessableEntity
+	case model.ErrHandoverCodeThrottled:
+		status = http.StatusTooManyRequests
+	}
+	c.JSON(status, model.CreateErrorResponse(code, err.Error(), nil))
+}
@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"net/http"
+	"service/internal/modules/orders/service"
+	"service/internal/shared/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IntakeHandler handles intake checklist, device intake and job ticket endpoints
+type IntakeHandler struct {
+	intakeService *service.IntakeService
+}
+
+// NewIntakeHandler creates a new intake handler
+func NewIntakeHandler() *IntakeHandler {
+	return &IntakeHandler{
+		intakeService: service.NewIntakeService(),
+	}
+}
+
+// ListChecklists godoc
+// @Summary List intake checklists
+// @Description Get the intake checklist configured for each service type. The "default" checklist is used for service types without one.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse
+// @Router /admin/intake-checklists [get]
+func (h *IntakeHandler) ListChecklists(c *gin.Context) {
+	checklists, err := h.intakeService.ListChecklists(c.Request.Context())
+	if err != nil {
+		respondIntakeError(c, "checklist_list_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(checklists, "Intake checklists retrieved successfully"))
+}
+
+// SaveChecklist godoc
+// @Summary Configure intake checklist
+// @Description Replace the intake checklist of a service type, or the "default" checklist. Intakes already recorded keep the items they were filled out with.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param service_type path string true "Service type or default"
+// @Param request body model.IntakeChecklistRequest true "Checklist"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Router /admin/intake-checklists/{service_type} [put]
+func (h *IntakeHandler) SaveChecklist(c *gin.Context) {
+	userID, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req model.IntakeChecklistRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	checklist, err := h.intakeService.SaveChecklist(c.Request.Context(), userID, c.Param("service_type"), &req)
+	if err != nil {
+		respondIntakeError(c, "checklist_save_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(checklist, "Intake checklist saved successfully"))
+}
+
+// GetIntake godoc
+// @Summary Get order intake
+// @Description Get the device condition recorded at intake, or the checklist to fill out when it has not been recorded yet. Customers can only see their own orders.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /orders/{id}/intake [get]
+func (h *IntakeHandler) GetIntake(c *gin.Context) {
+	orderID, ok := parseOrderID(c)
+	if !ok {
+		return
+	}
+	userID, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+	userRole, _ := c.Get("user_role")
+	role, _ := userRole.(model.UserRole)
+
+	intake, err := h.intakeService.GetIntake(c.Request.Context(), orderID, userID, role)
+	if err != nil {
+		respondIntakeError(c, "intake_get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(intake, "Intake retrieved successfully"))
+}
+
+// FillIntake godoc
+// @Summary Fill out order intake
+// @Description Record the condition of the device against the checklist of the order's service type. Done by the assigned courier at pickup or by the cashier at the counter; it can be corrected until the customer acknowledged it.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body model.OrderIntakeRequest true "Checklist results"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /orders/{id}/intake [put]
+func (h *IntakeHandler) FillIntake(c *gin.Context) {
+	orderID, ok := parseOrderID(c)
+	if !ok {
+		return
+	}
+	userID, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+	userRole, _ := c.Get("user_role")
+	role, _ := userRole.(model.UserRole)
+
+	var req model.OrderIntakeRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	intake, err := h.intakeService.FillIntake(c.Request.Context(), orderID, userID, role, &req)
+	if err != nil {
+		respondIntakeError(c, "intake_fill_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(intake, "Intake recorded successfully"))
+}
+
+// AcknowledgeIntake godoc
+// @Summary Acknowledge order intake
+// @Description Confirm, as the customer, the device condition recorded at intake. A comment can be left about any point the customer disagrees with.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body model.AcknowledgeIntakeRequest false "Customer comment"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /orders/{id}/intake/acknowledge [post]
+func (h *IntakeHandler) AcknowledgeIntake(c *gin.Context) {
+	orderID, ok := parseOrderID(c)
+	if !ok {
+		return
+	}
+	userID, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req model.AcknowledgeIntakeRequest
+	if c.Request.ContentLength != 0 && !bindRequest(c, &req) {
+		return
+	}
+
+	intake, err := h.intakeService.AcknowledgeIntake(c.Request.Context(), orderID, userID, &req)
+	if err != nil {
+		respondIntakeError(c, "intake_acknowledge_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(intake, "Intake acknowledged successfully"))
+}
+
+// GetJobTicket godoc
+// @Summary Get job ticket
+// @Description Get what is printed on the job ticket that travels with the device: order, customer and device details, the condition recorded at intake and the tracking QR code
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /orders/{id}/job-ticket [get]
+func (h *IntakeHandler) GetJobTicket(c *gin.Context) {
+	orderID, ok := parseOrderID(c)
+	if !ok {
+		return
+	}
+
+	ticket, err := h.intakeService.JobTicket(c.Request.Context(), orderID)
+	if err != nil {
+		respondIntakeError(c, "job_ticket_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(ticket, "Job ticket retrieved successfully"))
+}
+
+// respondIntakeError maps intake errors to HTTP status codes
+func respondIntakeError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case model.ErrIntakeItemUnknown, model.ErrIntakeItemMissing, model.ErrIntakeInvalidResult,
+		model.ErrInvalidChecklistType, model.ErrDuplicateChecklistItem:
+		status = http.StatusBadRequest
+	case model.ErrForbidden, model.ErrNotOrderCourier:
+		status = http.StatusForbidden
+	case model.ErrOrderNotFound, model.ErrIntakeNotFound:
+		status = http.StatusNotFound
+	case model.ErrIntakeAcknowledged, model.ErrIntakeNotOpen:
+		status = http.StatusConflict
+	}
+	c.JSON(status, model.CreateErrorResponse(code, err.Error(), nil))
+}
@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"mime/multipart"
+	"net/http"
+	"service/internal/modules/orders/service"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// MediaHandler handles order photo gallery endpoints
+type MediaHandler struct {
+	mediaService *service.MediaService
+}
+
+// NewMediaHandler creates a new media handler
+func NewMediaHandler() *MediaHandler {
+	return &MediaHandler{
+		mediaService: service.NewMediaService(),
+	}
+}
+
+// UploadMedia godoc
+// @Summary Upload order photos
+// @Description Add one or more photos to a stage of the order's gallery. Photos are internal unless marked customer-visible; the time each was taken is read from its EXIF data.
+// @Tags orders
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param files formData file true "Photos (repeat the field for several)"
+// @Param stage formData string true "Stage (pickup, service, delivery)"
+// @Param caption formData string false "Caption for the photos"
+// @Param visibility formData string false "Visibility (internal, customer)" default(internal)
+// @Success 201 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 503 {object} model.ErrorResponse
+// @Router /orders/{id}/media [post]
+func (h *MediaHandler) UploadMedia(c *gin.Context) {
+	orderID, ok := parseOrderID(c)
+	if !ok {
+		return
+	}
+	userID, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req model.UploadOrderMediaRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return
+	}
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return
+	}
+
+	var files []*multipart.FileHeader
+	if form, err := c.MultipartForm(); err == nil {
+		files = append(form.File["files"], form.File["file"]...)
+	}
+
+	media, err := h.mediaService.Upload(c.Request.Context(), orderID, userID, &req, files)
+	if err != nil {
+		respondMediaError(c, "media_upload_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse(media, "Photos uploaded successfully"))
+}
+
+// ListMedia godoc
+// @Summary List order photos
+// @Description Get the photo gallery of an order by stage and position, with temporary links. Customers only see customer-visible photos of their own orders.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param stage query string false "Stage (pickup, service, delivery)"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /orders/{id}/media [get]
+func (h *MediaHandler) ListMedia(c *gin.Context) {
+	orderID, ok := parseOrderID(c)
+	if !ok {
+		return
+	}
+	userID, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+	userRole, _ := c.Get("user_role")
+	role, _ := userRole.(model.UserRole)
+
+	var stage *model.MediaStage
+	if stageStr := c.Query("stage"); stageStr != "" {
+		mediaStage := model.MediaStage(stageStr)
+		stage = &mediaStage
+	}
+
+	media, err := h.mediaService.List(c.Request.Context(), orderID, userID, role, stage)
+	if err != nil {
+		respondMediaError(c, "media_list_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(media, "Photos retrieved successfully"))
+}
+
+// UpdateMedia godoc
+// @Summary Update order photo
+// @Description Change the caption or visibility of a photo
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param media_id path string true "Photo ID"
+// @Param request body model.UpdateOrderMediaRequest true "Photo changes"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /orders/{id}/media/{media_id} [put]
+func (h *MediaHandler) UpdateMedia(c *gin.Context) {
+	orderID, ok := parseOrderID(c)
+	if !ok {
+		return
+	}
+	mediaID, err := uuid.Parse(c.Param("media_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse("invalid_id", "Invalid photo ID format", nil))
+		return
+	}
+
+	var req model.UpdateOrderMediaRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	media, err := h.mediaService.Update(c.Request.Context(), orderID, mediaID, &req)
+	if err != nil {
+		respondMediaError(c, "media_update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(media, "Photo updated successfully"))
+}
+
+// ReorderMedia godoc
+// @Summary Reorder order photos
+// @Description Set the order of the photos of a stage. Listed photos come first; photos not listed keep their order after them.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body model.ReorderOrderMediaRequest true "Photo order"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /orders/{id}/media [put]
+func (h *MediaHandler) ReorderMedia(c *gin.Context) {
+	orderID, ok := parseOrderID(c)
+	if !ok {
+		return
+	}
+
+	var req model.ReorderOrderMediaRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	media, err := h.mediaService.Reorder(c.Request.Context(), orderID, &req)
+	if err != nil {
+		respondMediaError(c, "media_reorder_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(media, "Photos reordered successfully"))
+}
+
+// DeleteMedia godoc
+// @Summary Delete order photo
+// @Description Remove a photo from the order's gallery. The stored file is kept as evidence.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param media_id path string true "Photo ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /orders/{id}/media/{media_id} [delete]
+func (h *MediaHandler) DeleteMedia(c *gin.Context) {
+	orderID, ok := parseOrderID(c)
+	if !ok {
+		return
+	}
+	mediaID, err := uuid.Parse(c.Param("media_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse("invalid_id", "Invalid photo ID format", nil))
+		return
+	}
+
+	if err := h.mediaService.Delete(c.Request.Context(), orderID, mediaID); err != nil {
+		respondMediaError(c, "media_delete_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(nil, "Photo deleted successfully"))
+}
+
+// parseOrderID reads the order ID path parameter, responding with 400 when it is invalid
+func parseOrderID(c *gin.Context) (uuid.UUID, bool) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid order ID format",
+			nil,
+		))
+		return uuid.Nil, false
+	}
+	return orderID, true
+}
+
+// respondMediaError maps order media errors to HTTP status codes
+func respondMediaError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case model.ErrMediaFilesRequired, model.ErrMediaTooManyFiles, model.ErrMediaInvalidImage:
+		status = http.StatusBadRequest
+	case model.ErrOrderNotFound, model.ErrMediaNotFound:
+		status = http.StatusNotFound
+	case model.ErrMediaStorageDisabled:
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, model.CreateErrorResponse(code, err.Error(), nil))
+}
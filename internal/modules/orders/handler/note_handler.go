@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"net/http"
+	"service/internal/modules/orders/service"
+	"service/internal/shared/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// NoteHandler handles internal order notes and order history endpoints
+type NoteHandler struct {
+	noteService *service.NoteService
+}
+
+// NewNoteHandler creates a new note handler
+func NewNoteHandler() *NoteHandler {
+	return &NoteHandler{
+		noteService: service.NewNoteService(),
+	}
+}
+
+// AddNote godoc
+// @Summary Add internal order note
+// @Description Add a note to the internal thread of an order. Notes are only visible to staff, never to the customer. Staff mentioned in the note get a push notification.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body model.OrderNoteRequest true "Note"
+// @Success 201 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /orders/{id}/notes [post]
+func (h *NoteHandler) AddNote(c *gin.Context) {
+	orderID, ok := parseOrderID(c)
+	if !ok {
+		return
+	}
+	userID, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+	userRole, _ := c.Get("user_role")
+	role, _ := userRole.(model.UserRole)
+
+	var req model.OrderNoteRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	note, err := h.noteService.AddNote(c.Request.Context(), orderID, userID, role, &req)
+	if err != nil {
+		respondNoteError(c, "note_create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse(note, "Note added successfully"))
+}
+
+// ListNotes godoc
+// @Summary List internal order notes
+// @Description Get the internal notes of an order, pinned notes first. Technicians and couriers only see the notes of orders assigned to them.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /orders/{id}/notes [get]
+func (h *NoteHandler) ListNotes(c *gin.Context) {
+	orderID, ok := parseOrderID(c)
+	if !ok {
+		return
+	}
+	userID, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+	userRole, _ := c.Get("user_role")
+	role, _ := userRole.(model.UserRole)
+
+	notes, err := h.noteService.ListNotes(c.Request.Context(), orderID, userID, role)
+	if err != nil {
+		respondNoteError(c, "note_list_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(notes, "Notes retrieved successfully"))
+}
+
+// PinNote godoc
+// @Summary Pin internal order note
+// @Description Pin a note to the top of the order's internal thread
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param note_id path string true "Note ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /orders/{id}/notes/{note_id}/pin [post]
+func (h *NoteHandler) PinNote(c *gin.Context) {
+	h.setPinned(c, true)
+}
+
+// UnpinNote godoc
+// @Summary Unpin internal order note
+// @Description Remove a note from the top of the order's internal thread
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param note_id path string true "Note ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /orders/{id}/notes/{note_id}/pin [delete]
+func (h *NoteHandler) UnpinNote(c *gin.Context) {
+	h.setPinned(c, false)
+}
+
+// GetOrderHistory godoc
+// @Summary Get order history
+// @Description Get the staff view of an order's history: pinned notes, then status changes and internal notes by time
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /orders/{id}/history [get]
+func (h *NoteHandler) GetOrderHistory(c *gin.Context) {
+	orderID, ok := parseOrderID(c)
+	if !ok {
+		return
+	}
+	userID, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+	userRole, _ := c.Get("user_role")
+	role, _ := userRole.(model.UserRole)
+
+	history, err := h.noteService.History(c.Request.Context(), orderID, userID, role)
+	if err != nil {
+		respondNoteError(c, "order_history_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(history, "Order history retrieved successfully"))
+}
+
+// setPinned pins or unpins the note in the path
+func (h *NoteHandler) setPinned(c *gin.Context, pinned bool) {
+	orderID, ok := parseOrderID(c)
+	if !ok {
+		return
+	}
+	noteID, err := uuid.Parse(c.Param("note_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse("invalid_id", "Invalid note ID format", nil))
+		return
+	}
+	userID, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+	userRole, _ := c.Get("user_role")
+	role, _ := userRole.(model.UserRole)
+
+	note, err := h.noteService.PinNote(c.Request.Context(), orderID, noteID, userID, role, pinned)
+	if err != nil {
+		respondNoteError(c, "note_pin_failed", err)
+		return
+	}
+
+	message := "Note pinned successfully"
+	if !pinned {
+		message = "Note unpinned successfully"
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(note, message))
+}
+
+// respondNoteError maps order note errors to HTTP status codes
+func respondNoteError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case model.ErrNoteMentionInvalid:
+		status = http.StatusBadRequest
+	case model.ErrForbidden:
+		status = http.StatusForbidden
+	case model.ErrOrderNotFound, model.ErrNoteNotFound, model.ErrUserNotFound:
+		status = http.StatusNotFound
+	}
+	c.JSON(status, model.CreateErrorResponse(code, err.Error(), nil))
+}
@@ -32,10 +32,13 @@ func NewOrderHandler() *OrderHandler {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param Idempotency-Key header string false "Retries with the same key replay the first response"
 // @Param request body dto.ServiceOrderRequest true "Order data"
 // @Success 201 {object} dto.APIResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /orders [post]
 func (h *OrderHandler) CreateOrder(c *gin.Context) {
@@ -121,8 +124,11 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 	order, err := h.orderService.CreateOrder(c.Request.Context(), customerUUID, &req)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		if err == model.ErrUserNotFound || err == model.ErrBranchNotFound {
+		if err == model.ErrUserNotFound || err == model.ErrBranchNotFound || err == model.ErrCustomerDeviceNotFound ||
+			err == model.ErrInvalidTimeWindow {
 			statusCode = http.StatusBadRequest
+		} else if err == model.ErrForbidden || err == model.ErrContactNotVerified {
+			statusCode = http.StatusForbidden
 		}
 		c.JSON(statusCode, model.CreateErrorResponse(
 			"order_creation_failed",
@@ -137,12 +143,13 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 
 // GetOrder godoc
 // @Summary Get order by ID
-// @Description Get order details by ID
+// @Description Get order details by ID. The ETag header holds the order's version for use in If-Match.
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Param id path string true "Order ID"
 // @Success 200 {object} dto.APIResponse
+// @Header 200 {string} ETag "Order version"
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
@@ -173,6 +180,7 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", utils.FormatETag(order.Version))
 	c.JSON(http.StatusOK, model.SuccessResponse(order, "Order retrieved successfully"))
 }
 
@@ -210,17 +218,19 @@ func (h *OrderHandler) GetOrderByNumber(c *gin.Context) {
 
 // UpdateOrderStatus godoc
 // @Summary Update order status
-// @Description Update the status of an order
+// @Description Update the status of an order. With If-Match the update only applies to that version of the order; 409 returns the current order.
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Order ID"
+// @Param If-Match header string false "ETag of the order version being changed"
 // @Param request body dto.UpdateOrderStatusRequest true "Status update data"
 // @Success 200 {object} dto.APIResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /orders/{id}/status [put]
 func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
@@ -258,11 +268,25 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 		return
 	}
 
-	order, err := h.orderService.UpdateOrderStatus(c.Request.Context(), id, &req)
+	expectedVersion, ok := utils.IfMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.UpdateOrderStatus(c.Request.Context(), id, &req, expectedVersion)
+	if err == model.ErrVersionConflict {
+		h.respondOrderConflict(c, id, "order_update_failed")
+		return
+	}
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		if err == model.ErrOrderNotFound {
+		switch err {
+		case model.ErrOrderNotFound:
 			statusCode = http.StatusNotFound
+		case model.ErrHandoverRequired:
+			statusCode = http.StatusConflict
+		case model.ErrCancelRequiresReason:
+			statusCode = http.StatusBadRequest
 		}
 		c.JSON(statusCode, model.CreateErrorResponse(
 			"order_update_failed",
@@ -272,22 +296,25 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", utils.FormatETag(order.Version))
 	c.JSON(http.StatusOK, model.SuccessResponse(order, "Order status updated successfully"))
 }
 
 // AssignTechnician godoc
 // @Summary Assign technician to order
-// @Description Assign a technician to handle the order
+// @Description Assign a technician to handle the order. With If-Match the assignment only applies to that version of the order; 409 returns the current order.
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Order ID"
+// @Param If-Match header string false "ETag of the order version being changed"
 // @Param technician_id query string true "Technician ID"
 // @Success 200 {object} dto.APIResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /orders/{id}/assign-technician [post]
 func (h *OrderHandler) AssignTechnician(c *gin.Context) {
@@ -313,10 +340,26 @@ func (h *OrderHandler) AssignTechnician(c *gin.Context) {
 		return
 	}
 
-	order, err := h.orderService.AssignTechnician(c.Request.Context(), id, technicianID)
+	var assignedBy *uuid.UUID
+	if userID, ok := c.Get("user_id"); ok {
+		if userUUID, ok := userID.(uuid.UUID); ok {
+			assignedBy = &userUUID
+		}
+	}
+
+	expectedVersion, ok := utils.IfMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.AssignTechnician(c.Request.Context(), id, technicianID, assignedBy, expectedVersion)
+	if err == model.ErrVersionConflict {
+		h.respondOrderConflict(c, id, "technician_assignment_failed")
+		return
+	}
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		if err == model.ErrOrderNotFound || err.Error() == "user is not a technician" {
+		if err == model.ErrOrderNotFound || err == model.ErrNotTechnician {
 			statusCode = http.StatusBadRequest
 		}
 		c.JSON(statusCode, model.CreateErrorResponse(
@@ -327,6 +370,7 @@ func (h *OrderHandler) AssignTechnician(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", utils.FormatETag(order.Version))
 	c.JSON(http.StatusOK, model.SuccessResponse(order, "Technician assigned successfully"))
 }
 
@@ -371,7 +415,7 @@ func (h *OrderHandler) AssignCourier(c *gin.Context) {
 	order, err := h.orderService.AssignCourier(c.Request.Context(), id, courierID)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		if err == model.ErrOrderNotFound || err.Error() == "user is not a courier" {
+		if err == model.ErrOrderNotFound || err == model.ErrNotCourier {
 			statusCode = http.StatusBadRequest
 		}
 		c.JSON(statusCode, model.CreateErrorResponse(
@@ -665,11 +709,13 @@ func (h *OrderHandler) GetAllOrders(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Order ID"
+// @Param If-Match header string false "ETag of the order version being changed"
 // @Param request body dto.ServiceOrderRequest true "Order update data"
 // @Success 200 {object} dto.APIResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /admin/orders/{id} [put]
 func (h *OrderHandler) UpdateOrder(c *gin.Context) {
@@ -694,11 +740,22 @@ func (h *OrderHandler) UpdateOrder(c *gin.Context) {
 		return
 	}
 
-	order, err := h.orderService.UpdateOrder(c.Request.Context(), id, &req)
+	expectedVersion, ok := utils.IfMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.UpdateOrder(c.Request.Context(), id, &req, expectedVersion)
+	if err == model.ErrVersionConflict {
+		h.respondOrderConflict(c, id, "update_order_failed")
+		return
+	}
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		if err == model.ErrOrderNotFound {
 			statusCode = http.StatusNotFound
+		} else if err == model.ErrForbidden {
+			statusCode = http.StatusForbidden
 		}
 		c.JSON(statusCode, model.CreateErrorResponse(
 			"update_order_failed",
@@ -708,6 +765,7 @@ func (h *OrderHandler) UpdateOrder(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", utils.FormatETag(order.Version))
 	c.JSON(http.StatusOK, model.SuccessResponse(order, "Order updated successfully"))
 }
 
@@ -802,7 +860,8 @@ func (h *OrderHandler) GetCashierOrders(c *gin.Context) {
 		return
 	}
 
-	orders, total, err := h.orderService.GetOrdersByBranch(c.Request.Context(), userUUID, page, limit)
+	// Cashiers see the orders of their own branch
+	orders, total, err := h.orderService.GetOrders(c.Request.Context(), userUUID, page, limit, c.Query("status"), nil)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
 			"cashier_orders_failed",
@@ -830,16 +889,17 @@ func (h *OrderHandler) GetCashierOrders(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param branch_id path string true "Branch ID"
+// @Param id path string true "Branch ID"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Success 200 {object} dto.APIResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
-// @Router /branches/{branch_id}/orders [get]
+// @Router /cashier/branches/{id}/orders [get]
 func (h *OrderHandler) GetBranchOrders(c *gin.Context) {
-	branchIDStr := c.Param("branch_id")
+	branchIDStr := c.Param("id")
 	branchID, err := uuid.Parse(branchIDStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
@@ -864,7 +924,11 @@ func (h *OrderHandler) GetBranchOrders(c *gin.Context) {
 
 	orders, total, err := h.orderService.GetOrdersByBranchID(c.Request.Context(), branchID, page, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+		status := http.StatusInternalServerError
+		if err == model.ErrForbidden {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, model.CreateErrorResponse(
 			"branch_orders_failed",
 			err.Error(),
 			nil,
@@ -1132,3 +1196,14 @@ func (h *OrderHandler) AcceptJob(c *gin.Context) {
 
 	c.JSON(http.StatusOK, model.SuccessResponse(order, "Job accepted successfully"))
 }
+
+// respondOrderConflict responds with 409 and the current order, so the client can redo
+// its change on top of it
+func (h *OrderHandler) respondOrderConflict(c *gin.Context, id uuid.UUID, code string) {
+	var current interface{}
+	if order, err := h.orderService.GetOrder(c.Request.Context(), id); err == nil {
+		c.Header("ETag", utils.FormatETag(order.Version))
+		current = order
+	}
+	c.JSON(http.StatusConflict, model.CreateErrorResponse(code, model.ErrVersionConflict.Error(), current))
+}
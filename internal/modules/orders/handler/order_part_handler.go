@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"net/http"
+	"service/internal/modules/orders/service"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// OrderPartHandler handles endpoints for parts installed during orders
+type OrderPartHandler struct {
+	partService *service.OrderPartService
+}
+
+// NewOrderPartHandler creates a new order part handler
+func NewOrderPartHandler() *OrderPartHandler {
+	return &OrderPartHandler{
+		partService: service.NewOrderPartService(),
+	}
+}
+
+// RecordPart godoc
+// @Summary Record an installed part
+// @Description Record a spare part installed in the device of an order. The part is taken out of the branch stock.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body model.OrderPartRequest true "Installed part"
+// @Success 201 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /technician/orders/{id}/parts [post]
+func (h *OrderPartHandler) RecordPart(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, model.CreateErrorResponse(
+			"unauthorized",
+			"User ID not found in context",
+			nil,
+		))
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+			"internal_error",
+			"Invalid user ID type",
+			nil,
+		))
+		return
+	}
+
+	userRole, _ := c.Get("user_role")
+	role, _ := userRole.(model.UserRole)
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid order ID format",
+			nil,
+		))
+		return
+	}
+
+	var req model.OrderPartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return
+	}
+
+	part, err := h.partService.RecordPart(c.Request.Context(), orderID, userUUID, role, &req)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch err {
+		case model.ErrOrderNotFound, model.ErrSparePartNotFound:
+			status = http.StatusNotFound
+		case model.ErrForbidden:
+			status = http.StatusForbidden
+		case model.ErrInsufficientStock:
+			status = http.StatusConflict
+		}
+		c.JSON(status, model.CreateErrorResponse("part_record_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse(part, "Part recorded successfully"))
+}
+
+// ListParts godoc
+// @Summary List installed parts
+// @Description List the spare parts installed in the device of an order
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /orders/{id}/parts [get]
+func (h *OrderPartHandler) ListParts(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid order ID format",
+			nil,
+		))
+		return
+	}
+
+	parts, err := h.partService.ListParts(c.Request.Context(), orderID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == model.ErrOrderNotFound {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, model.CreateErrorResponse("parts_fetch_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(parts, "Parts retrieved successfully"))
+}
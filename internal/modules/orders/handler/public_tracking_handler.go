@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+	"service/internal/modules/orders/service"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PublicTrackingHandler handles order tracking for customers without an account
+type PublicTrackingHandler struct {
+	trackingService *service.PublicTrackingService
+}
+
+// NewPublicTrackingHandler creates a new public tracking handler
+func NewPublicTrackingHandler() *PublicTrackingHandler {
+	return &PublicTrackingHandler{
+		trackingService: service.NewPublicTrackingService(),
+	}
+}
+
+// TrackOrder godoc
+// @Summary Track order without login
+// @Description Get the status timeline of an order by its number and the last four digits of the customer's phone. Personal data is never returned. Rate limited; a client is locked out of an order number for a while after repeated wrong digits.
+// @Tags tracking
+// @Accept json
+// @Produce json
+// @Param request body model.PublicTrackingRequest true "Order number and phone digits"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 429 {object} model.ErrorResponse
+// @Router /track [post]
+func (h *PublicTrackingHandler) TrackOrder(c *gin.Context) {
+	var req model.PublicTrackingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return
+	}
+
+	tracking, err := h.trackingService.TrackByPhone(c.Request.Context(), &req, c.ClientIP())
+	if err != nil {
+		respondPublicTrackingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(tracking, "Order tracking retrieved successfully"))
+}
+
+// TrackOrderByToken godoc
+// @Summary Track order from QR code
+// @Description Get the status timeline of an order through the signed link printed as QR code on its receipt. Personal data is never returned.
+// @Tags tracking
+// @Accept json
+// @Produce json
+// @Param token path string true "Tracking token"
+// @Success 200 {object} model.APIResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 429 {object} model.ErrorResponse
+// @Router /track/{token} [get]
+func (h *PublicTrackingHandler) TrackOrderByToken(c *gin.Context) {
+	tracking, err := h.trackingService.TrackByToken(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		respondPublicTrackingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(tracking, "Order tracking retrieved successfully"))
+}
+
+// respondPublicTrackingError maps public tracking errors to HTTP status codes
+func respondPublicTrackingError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case model.ErrOrderNotFound, model.ErrInvalidTrackingToken:
+		status = http.StatusNotFound
+	case model.ErrTrackingLocked:
+		status = http.StatusTooManyRequests
+	}
+	c.JSON(status, model.CreateErrorResponse("order_tracking_failed", err.Error(), nil))
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CancellationRepository handles order cancellations and the cancellation fee schedule
+type CancellationRepository struct {
+	db *gorm.DB
+}
+
+// NewCancellationRepository creates a new cancellation repository
+func NewCancellationRepository() *CancellationRepository {
+	return &CancellationRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *CancellationRepository) Available() bool {
+	return r.db != nil
+}
+
+// TxWrites are writes that run inside a transaction opened by another repository
+type TxWrites func(tx *gorm.DB) error
+
+// CancellationWrites returns the writes that go with cancelling an order: the parts
+// recorded on it go back into stock and the cancellation is stored. They run inside the
+// transaction that saves the cancelled order, so the order, the stock and the
+// cancellation report cannot diverge.
+func CancellationWrites(cancellation *model.OrderCancellation) TxWrites {
+	return func(tx *gorm.DB) error {
+		released, err := releaseParts(tx, cancellation.OrderID)
+		if err != nil {
+			return err
+		}
+		cancellation.PartsReleased = released
+		return tx.Create(cancellation).Error
+	}
+}
+
+// GetByOrder retrieves the cancellation of an order
+func (r *CancellationRepository) GetByOrder(ctx context.Context, orderID uuid.UUID) (*model.OrderCancellation, error) {
+	var cancellation model.OrderCancellation
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).First(&cancellation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cancellation, nil
+}
+
+// ListFeeRules retrieves the fee overrides of the cancellation fee schedule
+func (r *CancellationRepository) ListFeeRules(ctx context.Context) ([]*model.CancellationFeeRule, error) {
+	var rules []*model.CancellationFeeRule
+	err := r.db.WithContext(ctx).Find(&rules).Error
+	return rules, err
+}
+
+// SaveFeeRules creates or replaces the fee overrides for the given statuses in one transaction
+func (r *CancellationRepository) SaveFeeRules(ctx context.Context, rules []*model.CancellationFeeRule) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, rule := range rules {
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "status"}},
+				DoUpdates: clause.AssignmentColumns([]string{"fee", "updated_by", "updated_at"}),
+			}).Create(rule).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Summarize aggregates the cancellations made in a period, optionally for one branch,
+// into the report
+func (r *CancellationRepository) Summarize(ctx context.Context, report *model.CancellationReport, branchID *uuid.UUID) error {
+	base := func() *gorm.DB {
+		query := r.db.WithContext(ctx).
+			Model(&model.OrderCancellation{}).
+			Scopes(model.ScopeByBranch(ctx, "branch_id")).
+			Where("created_at >= ? AND created_at < ?", report.StartDate, report.EndDate)
+		if branchID != nil {
+			query = query.Where("branch_id = ?", *branchID)
+		}
+		return query
+	}
+
+	var totals struct {
+		Cancelled     int64
+		FeesCharged   float64
+		FeesWaived    int64
+		RefundAmount  float64
+		PartsReleased int64
+	}
+	err := base().
+		Select("COUNT(*) AS cancelled, COALESCE(SUM(fee), 0) AS fees_charged, " +
+			"COUNT(*) FILTER (WHERE fee_waived) AS fees_waived, " +
+			"COALESCE(SUM(refund_amount), 0) AS refund_amount, COALESCE(SUM(parts_released), 0) AS parts_released").
+		Scan(&totals).Error
+	if err != nil {
+		return err
+	}
+	report.Cancelled = totals.Cancelled
+	report.FeesCharged = totals.FeesCharged
+	report.FeesWaived = totals.FeesWaived
+	report.RefundAmount = totals.RefundAmount
+	report.PartsReleased = totals.PartsReleased
+
+	var reasons []struct {
+		ReasonCode model.CancellationReason
+		Count      int64
+	}
+	if err := base().Select("reason_code, COUNT(*) AS count").Group("reason_code").Scan(&reasons).Error; err != nil {
+		return err
+	}
+	for _, row := range reasons {
+		report.ByReason[row.ReasonCode] = row.Count
+	}
+
+	var stages []struct {
+		StatusAtCancel model.OrderStatus
+		Count          int64
+	}
+	if err := base().Select("status_at_cancel, COUNT(*) AS count").Group("status_at_cancel").Scan(&stages).Error; err != nil {
+		return err
+	}
+	for _, row := range stages {
+		report.ByStage[row.StatusAtCancel] = row.Count
+	}
+	return nil
+}
+
+// CountOrders counts the orders created in a period, optionally for one branch
+func (r *CancellationRepository) CountOrders(ctx context.Context, start, end time.Time, branchID *uuid.UUID) (int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&model.ServiceOrder{}).
+		Scopes(model.ScopeByBranch(ctx, "branch_id")).
+		Where("created_at >= ? AND created_at < ?", start, end)
+	if branchID != nil {
+		query = query.Where("branch_id = ?", *branchID)
+	}
+	var count int64
+	err := query.Count(&count).Error
+	return count, err
+}
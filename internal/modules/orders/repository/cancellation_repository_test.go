@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"service/internal/shared/database/dbtest"
+	"service/internal/shared/model"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestUpdateWithCancellationWrites(t *testing.T) {
+	insertFailed := errors.New("insert failed")
+
+	tests := []struct {
+		name    string
+		insert  error
+		want    error
+		version int64
+	}{
+		{"all writes commit together", nil, nil, 4},
+		{"failed cancellation record rolls back the order and stock", insertFailed, insertFailed, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := dbtest.Mock(t)
+			repo := &ServiceOrderRepository{db: db}
+			order := &model.ServiceOrder{ID: uuid.New(), BranchID: uuid.New(), Status: model.StatusCancelled, Version: 3}
+			cancellation := &model.OrderCancellation{OrderID: order.ID, BranchID: order.BranchID, ReasonCode: model.CancellationCustomerRequest}
+
+			mock.ExpectBegin()
+			dbtest.ExpectVersionedUpdate(mock, "service_orders", 1)
+			mock.ExpectQuery(`SELECT \* FROM "order_parts" WHERE order_id = \$1 AND released_at IS NULL`).
+				WithArgs(order.ID).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "spare_part_id", "quantity"}).
+					AddRow(uuid.New(), order.ID, uuid.New(), 2))
+			mock.ExpectExec(`UPDATE "spare_part_inventory" SET "stock"=stock \+ \$1`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(`UPDATE "order_parts" SET "released_at"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			insert := mock.ExpectQuery(`INSERT INTO "order_cancellations"`)
+			if tt.insert != nil {
+				insert.WillReturnError(tt.insert)
+				mock.ExpectRollback()
+			} else {
+				insert.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+				mock.ExpectCommit()
+			}
+
+			err := repo.UpdateWith(context.Background(), order, CancellationWrites(cancellation))
+			assert.Equal(t, tt.want, err)
+			assert.Equal(t, tt.version, order.Version)
+			assert.Equal(t, 2, cancellation.PartsReleased)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateWithVersionConflict(t *testing.T) {
+	db, mock := dbtest.Mock(t)
+	repo := &ServiceOrderRepository{db: db}
+	order := &model.ServiceOrder{ID: uuid.New(), BranchID: uuid.New(), Version: 3}
+	called := false
+
+	mock.ExpectBegin()
+	dbtest.ExpectVersionedUpdate(mock, "service_orders", 0)
+	mock.ExpectRollback()
+
+	err := repo.UpdateWith(context.Background(), order, func(tx *gorm.DB) error {
+		called = true
+		return nil
+	})
+	assert.Equal(t, model.ErrVersionConflict, err)
+	assert.Equal(t, int64(3), order.Version)
+	assert.False(t, called, "nothing else is written for a stale order")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DispatchRepository handles planned courier routes and their stops
+type DispatchRepository struct {
+	db *gorm.DB
+}
+
+// NewDispatchRepository creates a new dispatch repository
+func NewDispatchRepository() *DispatchRepository {
+	return &DispatchRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *DispatchRepository) Available() bool {
+	return r.db != nil
+}
+
+// PendingPickups retrieves the orders of a branch still waiting for a courier to collect them
+func (r *DispatchRepository) PendingPickups(ctx context.Context, branchID uuid.UUID) ([]*model.ServiceOrder, error) {
+	var orders []*model.ServiceOrder
+	err := r.db.WithContext(ctx).
+		Where("branch_id = ? AND status = ? AND courier_id IS NULL", branchID, model.StatusPendingPickup).
+		Order("created_at ASC").
+		Find(&orders).Error
+	return orders, err
+}
+
+// PendingDeliveries retrieves the ready orders of a branch that no courier has taken on a route yet
+func (r *DispatchRepository) PendingDeliveries(ctx context.Context, branchID uuid.UUID) ([]*model.ServiceOrder, error) {
+	var orders []*model.ServiceOrder
+	err := r.db.WithContext(ctx).
+		Where("branch_id = ? AND status = ?", branchID, model.StatusReady).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM courier_route_stops s
+			JOIN courier_routes cr ON cr.id = s.route_id
+			WHERE s.order_id = service_orders.id AND s.kind = ? AND cr.status IN ?)`,
+			model.RouteStopDelivery, []model.CourierRouteStatus{model.CourierRouteStatusAccepted, model.CourierRouteStatusCompleted}).
+		Order("updated_at ASC").
+		Find(&orders).Error
+	return orders, err
+}
+
+// LatestPlanAt returns when the proposed routes of a branch were planned, or nil when there are none
+func (r *DispatchRepository) LatestPlanAt(ctx context.Context, branchID uuid.UUID) (*time.Time, error) {
+	var route model.CourierRoute
+	err := r.db.WithContext(ctx).
+		Where("branch_id = ? AND status = ?", branchID, model.CourierRouteStatusProposed).
+		Order("created_at DESC").
+		Limit(1).
+		Find(&route).Error
+	if err != nil || route.ID == uuid.Nil {
+		return nil, err
+	}
+	return &route.CreatedAt, nil
+}
+
+// ListProposed retrieves the routes of a branch waiting for a courier
+func (r *DispatchRepository) ListProposed(ctx context.Context, branchID uuid.UUID) ([]*model.CourierRoute, error) {
+	var routes []*model.CourierRoute
+	err := r.withStops(ctx).
+		Where("branch_id = ? AND status = ?", branchID, model.CourierRouteStatusProposed).
+		Order("depart_at ASC, created_at ASC").
+		Find(&routes).Error
+	return routes, err
+}
+
+// ReplacePlan supersedes the proposed routes of a branch with a new plan in one transaction
+func (r *DispatchRepository) ReplacePlan(ctx context.Context, branchID uuid.UUID, routes []*model.CourierRoute) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.CourierRoute{}).
+			Where("branch_id = ? AND status = ?", branchID, model.CourierRouteStatusProposed).
+			Update("status", model.CourierRouteStatusSuperseded).Error; err != nil {
+			return err
+		}
+		if len(routes) == 0 {
+			return nil
+		}
+		return tx.Create(&routes).Error
+	})
+}
+
+// GetByID retrieves a route with its stops
+func (r *DispatchRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.CourierRoute, error) {
+	var route model.CourierRoute
+	if err := r.withStops(ctx).Where("id = ?", id).First(&route).Error; err != nil {
+		return nil, err
+	}
+	return &route, nil
+}
+
+// ListByCourier retrieves the routes a courier has taken, newest first
+func (r *DispatchRepository) ListByCourier(ctx context.Context, courierID uuid.UUID, statuses []model.CourierRouteStatus) ([]*model.CourierRoute, error) {
+	var routes []*model.CourierRoute
+	err := r.withStops(ctx).
+		Where("courier_id = ? AND status IN ?", courierID, statuses).
+		Order("accepted_at DESC").
+		Find(&routes).Error
+	return routes, err
+}
+
+// Accept gives a proposed route and all of its orders to a courier in one transaction.
+// It fails with ErrRouteNotProposed when the route was taken or replaced, and with
+// ErrRouteStale when one of its orders was meanwhile handed to someone else.
+func (r *DispatchRepository) Accept(ctx context.Context, route *model.CourierRoute, courierID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&model.CourierRoute{}).
+			Where("id = ? AND status = ?", route.ID, model.CourierRouteStatusProposed).
+			Updates(map[string]interface{}{
+				"status":      model.CourierRouteStatusAccepted,
+				"courier_id":  courierID,
+				"accepted_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrRouteNotProposed
+		}
+
+		for _, stop := range route.Stops {
+			query := tx.Model(&model.ServiceOrder{}).Where("id = ?", stop.OrderID)
+			if stop.Kind == model.RouteStopPickup {
+				query = query.Where("status = ? AND courier_id IS NULL", model.StatusPendingPickup)
+			} else {
+				query = query.Where("status = ?", model.StatusReady)
+			}
+			result := query.Updates(map[string]interface{}{
+				"courier_id": courierID,
+				"version":    gorm.Expr("version + 1"),
+			})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return model.ErrRouteStale
+			}
+		}
+
+		route.Status = model.CourierRouteStatusAccepted
+		route.CourierID = &courierID
+		route.AcceptedAt = &now
+		return nil
+	})
+}
+
+// CompleteStops marks the open stops of an order on taken routes as visited and completes
+// the routes that have no open stops left
+func (r *DispatchRepository) CompleteStops(ctx context.Context, orderID uuid.UUID, kinds []model.RouteStopKind) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		var routeIDs []uuid.UUID
+		if err := tx.Model(&model.CourierRouteStop{}).
+			Joins("JOIN courier_routes cr ON cr.id = courier_route_stops.route_id").
+			Where("courier_route_stops.order_id = ? AND courier_route_stops.kind IN ? AND courier_route_stops.completed_at IS NULL AND cr.status = ?",
+				orderID, kinds, model.CourierRouteStatusAccepted).
+			Pluck("courier_route_stops.route_id", &routeIDs).Error; err != nil {
+			return err
+		}
+		if len(routeIDs) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&model.CourierRouteStop{}).
+			Where("order_id = ? AND kind IN ? AND route_id IN ? AND completed_at IS NULL", orderID, kinds, routeIDs).
+			Update("completed_at", now).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.CourierRoute{}).
+			Where("id IN ? AND status = ?", routeIDs, model.CourierRouteStatusAccepted).
+			Where("NOT EXISTS (SELECT 1 FROM courier_route_stops s WHERE s.route_id = courier_routes.id AND s.completed_at IS NULL)").
+			Updates(map[string]interface{}{
+				"status":       model.CourierRouteStatusCompleted,
+				"completed_at": now,
+			}).Error
+	})
+}
+
+// withStops starts a route query that loads the stops in driving order
+func (r *DispatchRepository) withStops(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Preload("Stops", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sequence ASC")
+	})
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HandoverRepository handles handover codes, proofs and refused attempts
+type HandoverRepository struct {
+	db *gorm.DB
+}
+
+// NewHandoverRepository creates a new handover repository
+func NewHandoverRepository() *HandoverRepository {
+	return &HandoverRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *HandoverRepository) Available() bool {
+	return r.db != nil
+}
+
+// GetCode retrieves the handover code of an order
+func (r *HandoverRepository) GetCode(ctx context.Context, orderID uuid.UUID, kind model.HandoverKind) (*model.HandoverCode, error) {
+	var code model.HandoverCode
+	err := r.db.WithContext(ctx).
+		First(&code, "order_id = ? AND kind = ?", orderID, kind).Error
+	if err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
+
+// UpsertCode stores a handover code, replacing any earlier one for the same order and handover
+func (r *HandoverRepository) UpsertCode(ctx context.Context, code *model.HandoverCode) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "order_id"}, {Name: "kind"}},
+			DoUpdates: clause.AssignmentColumns([]string{"code_hash", "expires_at", "attempts", "used_at", "created_at", "updated_at"}),
+		}).
+		Create(code).Error
+}
+
+// IncrementAttempts records a wrong handover code
+func (r *HandoverRepository) IncrementAttempts(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&model.HandoverCode{}).
+		Where("id = ?", id).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+// CreateProof stores a completed handover. When a code was used it is claimed in the
+// same transaction, so that a code only ever proves one handover.
+func (r *HandoverRepository) CreateProof(ctx context.Context, proof *model.HandoverProof, codeID *uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if codeID != nil {
+			result := tx.Model(&model.HandoverCode{}).
+				Where("id = ? AND used_at IS NULL", *codeID).
+				Update("used_at", time.Now())
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return model.ErrInvalidHandoverCode
+			}
+		}
+		return tx.Create(proof).Error
+	})
+}
+
+// LogAttempt records a refused handover
+func (r *HandoverRepository) LogAttempt(ctx context.Context, attempt *model.HandoverAttempt) error {
+	return r.db.WithContext(ctx).Create(attempt).Error
+}
+
+// ListProofs retrieves the completed handovers of an order, oldest first
+func (r *HandoverRepository) ListProofs(ctx context.Context, orderID uuid.UUID) ([]*model.HandoverProof, error) {
+	var proofs []*model.HandoverProof
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&proofs).Error
+	return proofs, err
+}
+
+// ListAttempts retrieves the refused handovers of an order, oldest first
+func (r *HandoverRepository) ListAttempts(ctx context.Context, orderID uuid.UUID) ([]*model.HandoverAttempt, error) {
+	var attempts []*model.HandoverAttempt
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&attempts).Error
+	return attempts, err
+}
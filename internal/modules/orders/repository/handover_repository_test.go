@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database/dbtest"
+	"service/internal/shared/model"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateProofClaimsCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		claimed int64 // rows the claim of the code matches
+		want    error
+	}{
+		{"unused code proves the handover", 1, nil},
+		{"code claimed by a concurrent handover", 0, model.ErrInvalidHandoverCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := dbtest.Mock(t)
+			repo := &HandoverRepository{db: db}
+			codeID := uuid.New()
+			proof := &model.HandoverProof{OrderID: uuid.New(), Kind: model.HandoverDelivery, Method: model.HandoverMethodCode}
+
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE "handover_codes" SET "used_at"=\$1,"updated_at"=\$2 WHERE id = \$3 AND used_at IS NULL`).
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), codeID).
+				WillReturnResult(sqlmock.NewResult(0, tt.claimed))
+			if tt.want == nil {
+				mock.ExpectQuery(`INSERT INTO "handover_proofs"`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			err := repo.CreateProof(context.Background(), proof, &codeID)
+			assert.Equal(t, tt.want, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
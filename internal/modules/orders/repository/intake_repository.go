@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// IntakeRepository handles intake checklists and the intakes recorded for orders
+type IntakeRepository struct {
+	db *gorm.DB
+}
+
+// NewIntakeRepository creates a new intake repository
+func NewIntakeRepository() *IntakeRepository {
+	return &IntakeRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *IntakeRepository) Available() bool {
+	return r.db != nil
+}
+
+// ListChecklists retrieves the configured checklists with their items
+func (r *IntakeRepository) ListChecklists(ctx context.Context) ([]*model.IntakeChecklist, error) {
+	var checklists []*model.IntakeChecklist
+	err := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Order("service_type ASC").
+		Find(&checklists).Error
+	return checklists, err
+}
+
+// GetChecklist retrieves the checklist configured for a service type
+func (r *IntakeRepository) GetChecklist(ctx context.Context, serviceType string) (*model.IntakeChecklist, error) {
+	var checklist model.IntakeChecklist
+	err := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		First(&checklist, "service_type = ?", serviceType).Error
+	if err != nil {
+		return nil, err
+	}
+	return &checklist, nil
+}
+
+// SaveChecklist creates or replaces the checklist of a service type together with its
+// items in one transaction
+func (r *IntakeRepository) SaveChecklist(ctx context.Context, checklist *model.IntakeChecklist) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing model.IntakeChecklist
+		err := tx.First(&existing, "service_type = ?", checklist.ServiceType).Error
+		switch err {
+		case nil:
+			checklist.ID = existing.ID
+			checklist.CreatedAt = existing.CreatedAt
+			if err := tx.Where("checklist_id = ?", existing.ID).Delete(&model.IntakeChecklistItem{}).Error; err != nil {
+				return err
+			}
+		case gorm.ErrRecordNotFound:
+		default:
+			return err
+		}
+
+		items := checklist.Items
+		checklist.Items = nil
+		if err := tx.Save(checklist).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].ChecklistID = checklist.ID
+		}
+		checklist.Items = items
+		return tx.Create(&checklist.Items).Error
+	})
+}
+
+// GetByOrder retrieves the intake of an order with its items
+func (r *IntakeRepository) GetByOrder(ctx context.Context, orderID uuid.UUID) (*model.OrderIntake, error) {
+	var intake model.OrderIntake
+	err := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		First(&intake, "order_id = ?", orderID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &intake, nil
+}
+
+// SaveIntake creates or replaces the intake of an order together with its items in one
+// transaction. A recorded intake is only replaced while it is not acknowledged; otherwise
+// it fails with ErrIntakeAcknowledged, so a correction cannot overwrite an acknowledgement
+// given after the intake was read.
+func (r *IntakeRepository) SaveIntake(ctx context.Context, intake *model.OrderIntake) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		items := intake.Items
+		intake.Items = nil
+		defer func() { intake.Items = items }()
+
+		if intake.ID == uuid.Nil {
+			if err := tx.Create(intake).Error; err != nil {
+				return err
+			}
+		} else {
+			result := tx.Model(intake).
+				Where("acknowledged_at IS NULL").
+				Updates(map[string]interface{}{
+					"checklist_name": intake.ChecklistName,
+					"notes":          intake.Notes,
+					"filled_by":      intake.FilledBy,
+					"filled_at":      intake.FilledAt,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return model.ErrIntakeAcknowledged
+			}
+			if err := tx.Where("intake_id = ?", intake.ID).Delete(&model.OrderIntakeItem{}).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range items {
+			items[i].IntakeID = intake.ID
+		}
+		return tx.Create(&items).Error
+	})
+}
+
+// Acknowledge records the customer's acknowledgement of an intake. It fails with
+// ErrIntakeAcknowledged when the intake was acknowledged before.
+func (r *IntakeRepository) Acknowledge(ctx context.Context, intake *model.OrderIntake) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.OrderIntake{}).
+		Where("id = ? AND acknowledged_at IS NULL", intake.ID).
+		Updates(map[string]interface{}{
+			"acknowledged_at":  intake.AcknowledgedAt,
+			"customer_comment": intake.CustomerComment,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrIntakeAcknowledged
+	}
+	return nil
+}
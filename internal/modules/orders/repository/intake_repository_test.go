@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database/dbtest"
+	"service/internal/shared/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveIntake(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing bool
+		updated  int64
+		want     error
+	}{
+		{"first intake is created", false, 0, nil},
+		{"unacknowledged intake is corrected", true, 1, nil},
+		{"intake acknowledged meanwhile is kept", true, 0, model.ErrIntakeAcknowledged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := dbtest.Mock(t)
+			repo := &IntakeRepository{db: db}
+			intake := &model.OrderIntake{
+				OrderID:       uuid.New(),
+				ChecklistName: "Screen replacement",
+				FilledBy:      uuid.New(),
+				FilledAt:      time.Now(),
+				Items:         []model.OrderIntakeItem{{Key: "screen", Label: "Screen", Kind: model.IntakeItemCondition, Result: model.IntakeResultOK}},
+			}
+			if tt.existing {
+				intake.ID = uuid.New()
+			}
+
+			mock.ExpectBegin()
+			if tt.existing {
+				mock.ExpectExec(`UPDATE "order_intakes" SET .* WHERE acknowledged_at IS NULL AND "id" = \$\d+`).
+					WillReturnResult(sqlmock.NewResult(0, tt.updated))
+			} else {
+				mock.ExpectQuery(`INSERT INTO "order_intakes"`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+			}
+			if tt.want != nil {
+				mock.ExpectRollback()
+			} else {
+				if tt.existing {
+					mock.ExpectExec(`DELETE FROM "order_intake_items" WHERE intake_id = \$1`).
+						WithArgs(intake.ID).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+				mock.ExpectQuery(`INSERT INTO "order_intake_items"`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+				mock.ExpectCommit()
+			}
+
+			err := repo.SaveIntake(context.Background(), intake)
+			assert.Equal(t, tt.want, err)
+			assert.Len(t, intake.Items, 1)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAcknowledgeIntake(t *testing.T) {
+	tests := []struct {
+		name    string
+		updated int64
+		want    error
+	}{
+		{"first acknowledgement", 1, nil},
+		{"already acknowledged", 0, model.ErrIntakeAcknowledged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := dbtest.Mock(t)
+			repo := &IntakeRepository{db: db}
+			now := time.Now()
+			intake := &model.OrderIntake{ID: uuid.New(), AcknowledgedAt: &now}
+
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE "order_intakes" SET .* WHERE id = \$\d+ AND acknowledged_at IS NULL`).
+				WillReturnResult(sqlmock.NewResult(0, tt.updated))
+			mock.ExpectCommit()
+
+			assert.Equal(t, tt.want, repo.Acknowledge(context.Background(), intake))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MediaRepository handles the photo galleries of orders
+type MediaRepository struct {
+	db *gorm.DB
+}
+
+// NewMediaRepository creates a new media repository
+func NewMediaRepository() *MediaRepository {
+	return &MediaRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *MediaRepository) Available() bool {
+	return r.db != nil
+}
+
+// NextPosition returns the position after the last photo of a stage
+func (r *MediaRepository) NextPosition(ctx context.Context, orderID uuid.UUID, stage model.MediaStage) (int, error) {
+	var position int
+	err := r.db.WithContext(ctx).
+		Model(&model.OrderMedia{}).
+		Select("COALESCE(MAX(position) + 1, 0)").
+		Where("order_id = ? AND stage = ?", orderID, stage).
+		Scan(&position).Error
+	return position, err
+}
+
+// Create stores a photo
+func (r *MediaRepository) Create(ctx context.Context, media *model.OrderMedia) error {
+	return r.db.WithContext(ctx).Create(media).Error
+}
+
+// Get retrieves a photo of an order
+func (r *MediaRepository) Get(ctx context.Context, orderID, id uuid.UUID) (*model.OrderMedia, error) {
+	var media model.OrderMedia
+	err := r.db.WithContext(ctx).First(&media, "id = ? AND order_id = ?", id, orderID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// Update saves changes to a photo
+func (r *MediaRepository) Update(ctx context.Context, media *model.OrderMedia) error {
+	return r.db.WithContext(ctx).Save(media).Error
+}
+
+// Delete removes a photo from the gallery
+func (r *MediaRepository) Delete(ctx context.Context, media *model.OrderMedia) error {
+	return r.db.WithContext(ctx).Delete(media).Error
+}
+
+// ListByOrder retrieves the photos of an order by stage and position, optionally only
+// those of one stage or visibility
+func (r *MediaRepository) ListByOrder(ctx context.Context, orderID uuid.UUID, stage *model.MediaStage, visibility *model.MediaVisibility) ([]*model.OrderMedia, error) {
+	query := r.db.WithContext(ctx).Where("order_id = ?", orderID)
+	if stage != nil {
+		query = query.Where("stage = ?", *stage)
+	}
+	if visibility != nil {
+		query = query.Where("visibility = ?", *visibility)
+	}
+
+	var media []*model.OrderMedia
+	err := query.
+		Order("CASE stage WHEN 'pickup' THEN 0 WHEN 'service' THEN 1 ELSE 2 END").
+		Order("position ASC, created_at ASC").
+		Find(&media).Error
+	return media, err
+}
+
+// Reorder sets the positions of the photos of a stage in the given order in one transaction.
+// It fails with ErrMediaNotFound when an ID is not a photo of that stage.
+func (r *MediaRepository) Reorder(ctx context.Context, orderID uuid.UUID, stage model.MediaStage, ids []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for position, id := range ids {
+			result := tx.Model(&model.OrderMedia{}).
+				Where("id = ? AND order_id = ? AND stage = ?", id, orderID, stage).
+				Update("position", position)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return model.ErrMediaNotFound
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NoteRepository handles internal notes on orders
+type NoteRepository struct {
+	db *gorm.DB
+}
+
+// NewNoteRepository creates a new note repository
+func NewNoteRepository() *NoteRepository {
+	return &NoteRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *NoteRepository) Available() bool {
+	return r.db != nil
+}
+
+// Create stores a note together with its mentions
+func (r *NoteRepository) Create(ctx context.Context, note *model.OrderNote) error {
+	return r.db.WithContext(ctx).Create(note).Error
+}
+
+// Get retrieves a note of an order
+func (r *NoteRepository) Get(ctx context.Context, orderID, noteID uuid.UUID) (*model.OrderNote, error) {
+	var note model.OrderNote
+	err := r.db.WithContext(ctx).
+		Preload("Mentions").
+		First(&note, "id = ? AND order_id = ?", noteID, orderID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// ListByOrder retrieves the notes of an order, oldest first
+func (r *NoteRepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*model.OrderNote, error) {
+	var notes []*model.OrderNote
+	err := r.db.WithContext(ctx).
+		Preload("Mentions").
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&notes).Error
+	return notes, err
+}
+
+// SetPinned pins or unpins a note
+func (r *NoteRepository) SetPinned(ctx context.Context, note *model.OrderNote, pinned bool, userID uuid.UUID) error {
+	note.Pinned = pinned
+	note.PinnedBy = nil
+	note.PinnedAt = nil
+	if pinned {
+		now := time.Now()
+		note.PinnedBy = &userID
+		note.PinnedAt = &now
+	}
+	return r.db.WithContext(ctx).
+		Model(note).
+		Select("pinned", "pinned_by", "pinned_at").
+		Updates(note).Error
+}
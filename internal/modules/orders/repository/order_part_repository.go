@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OrderPartRepository handles parts installed during orders
+type OrderPartRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderPartRepository creates a new order part repository
+func NewOrderPartRepository() *OrderPartRepository {
+	return &OrderPartRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *OrderPartRepository) Available() bool {
+	return r.db != nil
+}
+
+// Record takes the part out of stock and records it on the order in one transaction.
+// It fails with ErrInsufficientStock when the branch does not have enough left.
+func (r *OrderPartRepository) Record(ctx context.Context, part *model.OrderPart) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.SparePartInventory{}).
+			Where("id = ? AND stock >= ?", part.SparePartID, part.Quantity).
+			UpdateColumns(map[string]interface{}{
+				"stock":   gorm.Expr("stock - ?", part.Quantity),
+				"version": gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrInsufficientStock
+		}
+		return tx.Create(part).Error
+	})
+}
+
+// ListByOrder retrieves the parts installed during an order
+func (r *OrderPartRepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*model.OrderPart, error) {
+	var parts []*model.OrderPart
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&parts).Error
+	return parts, err
+}
+
+// releaseParts puts the parts recorded on an order back into stock and marks them as
+// released, within the given transaction. Parts released before are skipped, so it returns
+// the number of parts released by this call.
+func releaseParts(tx *gorm.DB, orderID uuid.UUID) (int, error) {
+	var parts []*model.OrderPart
+	if err := tx.Where("order_id = ? AND released_at IS NULL", orderID).Find(&parts).Error; err != nil {
+		return 0, err
+	}
+
+	released := 0
+	now := time.Now()
+	for _, part := range parts {
+		if err := tx.Model(&model.SparePartInventory{}).
+			Where("id = ?", part.SparePartID).
+			UpdateColumns(map[string]interface{}{
+				"stock":   gorm.Expr("stock + ?", part.Quantity),
+				"version": gorm.Expr("version + 1"),
+			}).Error; err != nil {
+			return 0, err
+		}
+		if err := tx.Model(part).Update("released_at", now).Error; err != nil {
+			return 0, err
+		}
+		released += part.Quantity
+	}
+	return released, nil
+}
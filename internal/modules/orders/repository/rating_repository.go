@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RatingRepository handles rating data operations
+type RatingRepository struct {
+	db *gorm.DB
+}
+
+// NewRatingRepository creates a new rating repository
+func NewRatingRepository() *RatingRepository {
+	return &RatingRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *RatingRepository) Available() bool {
+	return r.db != nil
+}
+
+// Average calculates rating statistics, optionally limited to a branch, a technician
+// and ratings given since a moment
+func (r *RatingRepository) Average(ctx context.Context, branchID, technicianID *uuid.UUID, since *time.Time) (*model.AverageRating, error) {
+	query := r.db.WithContext(ctx).Model(&model.Rating{}).
+		Select(`COALESCE(AVG(rating), 0) AS average_rating,
+			COUNT(*) AS total_ratings,
+			COUNT(*) FILTER (WHERE rating = 5) AS rating5,
+			COUNT(*) FILTER (WHERE rating = 4) AS rating4,
+			COUNT(*) FILTER (WHERE rating = 3) AS rating3,
+			COUNT(*) FILTER (WHERE rating = 2) AS rating2,
+			COUNT(*) FILTER (WHERE rating = 1) AS rating1`)
+	if branchID != nil {
+		query = query.Where("branch_id = ?", *branchID)
+	}
+	if technicianID != nil {
+		query = query.Where("technician_id = ?", *technicianID)
+	}
+	if since != nil {
+		query = query.Where("created_at >= ?", *since)
+	}
+
+	var average model.AverageRating
+	if err := query.Scan(&average).Error; err != nil {
+		return nil, err
+	}
+	return &average, nil
+}
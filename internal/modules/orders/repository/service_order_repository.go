@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ServiceOrderRepository handles service order data operations
@@ -66,6 +67,12 @@ func NewServiceOrderRepository() *ServiceOrderRepository {
 
 // Create creates a new service order
 func (r *ServiceOrderRepository) Create(ctx context.Context, order *model.ServiceOrder) error {
+	if !model.BranchScopeFromContext(ctx).Allows(order.BranchID) {
+		return model.ErrForbidden
+	}
+	if order.Version == 0 {
+		order.Version = 1
+	}
 	if r.inMemory {
 		r.mu.Lock()
 		defer r.mu.Unlock()
@@ -87,13 +94,14 @@ func (r *ServiceOrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*mo
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		o, ok := r.orders[id]
-		if !ok {
+		if !ok || !model.BranchScopeFromContext(ctx).Allows(o.BranchID) {
 			return nil, gorm.ErrRecordNotFound
 		}
 		return o, nil
 	}
 	var order model.ServiceOrder
 	err := r.db.WithContext(ctx).
+		Scopes(model.ScopeByBranch(ctx, "branch_id")).
 		Preload("Customer").
 		Preload("Branch").
 		Preload("Technician").
@@ -110,7 +118,11 @@ func (r *ServiceOrderRepository) GetByOrderNumber(ctx context.Context, orderNumb
 	if r.inMemory {
 		r.mu.RLock()
 		defer r.mu.RUnlock()
+		scope := model.BranchScopeFromContext(ctx)
 		for _, o := range r.orders {
+			if !scope.Allows(o.BranchID) {
+				continue
+			}
 			if o.OrderNumber == orderNumber {
 				return o, nil
 			}
@@ -119,6 +131,7 @@ func (r *ServiceOrderRepository) GetByOrderNumber(ctx context.Context, orderNumb
 	}
 	var order model.ServiceOrder
 	err := r.db.WithContext(ctx).
+		Scopes(model.ScopeByBranch(ctx, "branch_id")).
 		Preload("Customer").
 		Preload("Branch").
 		Preload("Technician").
@@ -130,19 +143,76 @@ func (r *ServiceOrderRepository) GetByOrderNumber(ctx context.Context, orderNumb
 	return &order, nil
 }
 
-// Update updates a service order
+// Update updates a service order. The write only applies while the stored order still
+// has the version it was read at; otherwise it fails with ErrVersionConflict and nothing
+// is written. The order's version is raised on success.
 func (r *ServiceOrderRepository) Update(ctx context.Context, order *model.ServiceOrder) error {
+	// Branch staff may not write orders belonging to (or moved to) another branch
+	if !model.BranchScopeFromContext(ctx).Allows(order.BranchID) {
+		return model.ErrForbidden
+	}
 	if r.inMemory {
 		r.mu.Lock()
 		defer r.mu.Unlock()
-		if _, ok := r.orders[order.ID]; !ok {
+		stored, ok := r.orders[order.ID]
+		if !ok {
 			return gorm.ErrRecordNotFound
 		}
+		if stored.Version != order.Version {
+			return model.ErrVersionConflict
+		}
+		order.Version++
 		order.UpdatedAt = time.Now()
 		r.orders[order.ID] = order
 		return nil
 	}
-	return r.db.WithContext(ctx).Save(order).Error
+
+	return UpdateVersioned(r.db.WithContext(ctx), order)
+}
+
+// UpdateWith updates a service order like Update and runs the given writes in the same
+// transaction. Without a database there is nothing else to write and only the order is saved.
+func (r *ServiceOrderRepository) UpdateWith(ctx context.Context, order *model.ServiceOrder, writes TxWrites) error {
+	if r.inMemory {
+		return r.Update(ctx, order)
+	}
+	if !model.BranchScopeFromContext(ctx).Allows(order.BranchID) {
+		return model.ErrForbidden
+	}
+
+	read := order.Version
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := UpdateVersioned(tx, order); err != nil {
+			return err
+		}
+		return writes(tx)
+	})
+	if err != nil {
+		// The version raised by a rolled back write is restored, so the caller can retry
+		order.Version = read
+	}
+	return err
+}
+
+// UpdateVersioned writes an order under the same version check as Update, within the given
+// transaction. It is meant for callers that change an order together with other records.
+func UpdateVersioned(tx *gorm.DB, order *model.ServiceOrder) error {
+	read := order.Version
+	order.Version = read + 1
+	result := tx.
+		Model(order).
+		Where("version = ?", read).
+		Select("*").
+		Omit(clause.Associations).
+		Updates(order)
+	if result.Error != nil || result.RowsAffected == 0 {
+		order.Version = read
+		if result.Error != nil {
+			return result.Error
+		}
+		return model.ErrVersionConflict
+	}
+	return nil
 }
 
 // Delete soft deletes a service order
@@ -150,13 +220,13 @@ func (r *ServiceOrderRepository) Delete(ctx context.Context, id uuid.UUID) error
 	if r.inMemory {
 		r.mu.Lock()
 		defer r.mu.Unlock()
-		if _, ok := r.orders[id]; !ok {
+		if o, ok := r.orders[id]; !ok || !model.BranchScopeFromContext(ctx).Allows(o.BranchID) {
 			return gorm.ErrRecordNotFound
 		}
 		delete(r.orders, id)
 		return nil
 	}
-	return r.db.WithContext(ctx).Delete(&model.ServiceOrder{}, "id = ?", id).Error
+	return r.db.WithContext(ctx).Scopes(model.ScopeByBranch(ctx, "branch_id")).Delete(&model.ServiceOrder{}, "id = ?", id).Error
 }
 
 // List retrieves service orders with pagination
@@ -165,7 +235,11 @@ func (r *ServiceOrderRepository) List(ctx context.Context, offset, limit int, fi
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		var list []*model.ServiceOrder
+		scope := model.BranchScopeFromContext(ctx)
 		for _, o := range r.orders {
+			if !scope.Allows(o.BranchID) {
+				continue
+			}
 			if filters != nil {
 				if filters.CustomerID != nil && o.CustomerID != *filters.CustomerID {
 					continue
@@ -207,7 +281,7 @@ func (r *ServiceOrderRepository) List(ctx context.Context, offset, limit int, fi
 	var orders []*model.ServiceOrder
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&model.ServiceOrder{})
+	query := r.db.WithContext(ctx).Scopes(model.ScopeByBranch(ctx, "branch_id")).Model(&model.ServiceOrder{})
 
 	if filters != nil {
 		if filters.CustomerID != nil {
@@ -260,7 +334,11 @@ func (r *ServiceOrderRepository) GetByCustomerID(ctx context.Context, customerID
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		var list []*model.ServiceOrder
+		scope := model.BranchScopeFromContext(ctx)
 		for _, o := range r.orders {
+			if !scope.Allows(o.BranchID) {
+				continue
+			}
 			if o.CustomerID == customerID {
 				list = append(list, o)
 			}
@@ -270,6 +348,7 @@ func (r *ServiceOrderRepository) GetByCustomerID(ctx context.Context, customerID
 	}
 	var orders []*model.ServiceOrder
 	err := r.db.WithContext(ctx).
+		Scopes(model.ScopeByBranch(ctx, "branch_id")).
 		Preload("Customer").
 		Preload("Branch").
 		Preload("Technician").
@@ -286,7 +365,11 @@ func (r *ServiceOrderRepository) GetByBranchID(ctx context.Context, branchID uui
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		var list []*model.ServiceOrder
+		scope := model.BranchScopeFromContext(ctx)
 		for _, o := range r.orders {
+			if !scope.Allows(o.BranchID) {
+				continue
+			}
 			if o.BranchID == branchID {
 				list = append(list, o)
 			}
@@ -296,6 +379,7 @@ func (r *ServiceOrderRepository) GetByBranchID(ctx context.Context, branchID uui
 	}
 	var orders []*model.ServiceOrder
 	err := r.db.WithContext(ctx).
+		Scopes(model.ScopeByBranch(ctx, "branch_id")).
 		Preload("Customer").
 		Preload("Branch").
 		Preload("Technician").
@@ -312,7 +396,11 @@ func (r *ServiceOrderRepository) GetByStatus(ctx context.Context, status model.O
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		var list []*model.ServiceOrder
+		scope := model.BranchScopeFromContext(ctx)
 		for _, o := range r.orders {
+			if !scope.Allows(o.BranchID) {
+				continue
+			}
 			if o.Status == status {
 				list = append(list, o)
 			}
@@ -322,6 +410,7 @@ func (r *ServiceOrderRepository) GetByStatus(ctx context.Context, status model.O
 	}
 	var orders []*model.ServiceOrder
 	err := r.db.WithContext(ctx).
+		Scopes(model.ScopeByBranch(ctx, "branch_id")).
 		Preload("Customer").
 		Preload("Branch").
 		Preload("Technician").
@@ -338,7 +427,11 @@ func (r *ServiceOrderRepository) GetByTechnicianID(ctx context.Context, technici
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		var list []*model.ServiceOrder
+		scope := model.BranchScopeFromContext(ctx)
 		for _, o := range r.orders {
+			if !scope.Allows(o.BranchID) {
+				continue
+			}
 			if o.TechnicianID != nil && *o.TechnicianID == technicianID {
 				list = append(list, o)
 			}
@@ -348,6 +441,7 @@ func (r *ServiceOrderRepository) GetByTechnicianID(ctx context.Context, technici
 	}
 	var orders []*model.ServiceOrder
 	err := r.db.WithContext(ctx).
+		Scopes(model.ScopeByBranch(ctx, "branch_id")).
 		Preload("Customer").
 		Preload("Branch").
 		Preload("Technician").
@@ -364,7 +458,11 @@ func (r *ServiceOrderRepository) GetByCourierID(ctx context.Context, courierID u
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		var list []*model.ServiceOrder
+		scope := model.BranchScopeFromContext(ctx)
 		for _, o := range r.orders {
+			if !scope.Allows(o.BranchID) {
+				continue
+			}
 			if o.CourierID != nil && *o.CourierID == courierID {
 				list = append(list, o)
 			}
@@ -374,6 +472,7 @@ func (r *ServiceOrderRepository) GetByCourierID(ctx context.Context, courierID u
 	}
 	var orders []*model.ServiceOrder
 	err := r.db.WithContext(ctx).
+		Scopes(model.ScopeByBranch(ctx, "branch_id")).
 		Preload("Customer").
 		Preload("Branch").
 		Preload("Technician").
@@ -390,21 +489,24 @@ func (r *ServiceOrderRepository) UpdateStatus(ctx context.Context, id uuid.UUID,
 		r.mu.Lock()
 		defer r.mu.Unlock()
 		o, ok := r.orders[id]
-		if !ok {
+		if !ok || !model.BranchScopeFromContext(ctx).Allows(o.BranchID) {
 			return gorm.ErrRecordNotFound
 		}
 		o.Status = status
 		o.Notes = notes
+		o.Version++
 		o.UpdatedAt = time.Now()
 		r.orders[id] = o
 		return nil
 	}
 	return r.db.WithContext(ctx).
+		Scopes(model.ScopeByBranch(ctx, "branch_id")).
 		Model(&model.ServiceOrder{}).
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
-			"status": status,
-			"notes":  notes,
+			"status":  status,
+			"notes":   notes,
+			"version": gorm.Expr("version + 1"),
 		}).Error
 }
 
@@ -414,18 +516,23 @@ func (r *ServiceOrderRepository) AssignTechnician(ctx context.Context, id uuid.U
 		r.mu.Lock()
 		defer r.mu.Unlock()
 		o, ok := r.orders[id]
-		if !ok {
+		if !ok || !model.BranchScopeFromContext(ctx).Allows(o.BranchID) {
 			return gorm.ErrRecordNotFound
 		}
 		o.TechnicianID = &technicianID
+		o.Version++
 		o.UpdatedAt = time.Now()
 		r.orders[id] = o
 		return nil
 	}
 	return r.db.WithContext(ctx).
+		Scopes(model.ScopeByBranch(ctx, "branch_id")).
 		Model(&model.ServiceOrder{}).
 		Where("id = ?", id).
-		Update("technician_id", technicianID).Error
+		Updates(map[string]interface{}{
+			"technician_id": technicianID,
+			"version":       gorm.Expr("version + 1"),
+		}).Error
 }
 
 // AssignCourier assigns a courier to a service order
@@ -434,18 +541,23 @@ func (r *ServiceOrderRepository) AssignCourier(ctx context.Context, id uuid.UUID
 		r.mu.Lock()
 		defer r.mu.Unlock()
 		o, ok := r.orders[id]
-		if !ok {
+		if !ok || !model.BranchScopeFromContext(ctx).Allows(o.BranchID) {
 			return gorm.ErrRecordNotFound
 		}
 		o.CourierID = &courierID
+		o.Version++
 		o.UpdatedAt = time.Now()
 		r.orders[id] = o
 		return nil
 	}
 	return r.db.WithContext(ctx).
+		Scopes(model.ScopeByBranch(ctx, "branch_id")).
 		Model(&model.ServiceOrder{}).
 		Where("id = ?", id).
-		Update("courier_id", courierID).Error
+		Updates(map[string]interface{}{
+			"courier_id": courierID,
+			"version":    gorm.Expr("version + 1"),
+		}).Error
 }
 
 // CheckOrderNumberExists checks if order number already exists
@@ -480,7 +592,11 @@ func (r *ServiceOrderRepository) CountOrdersByDateRange(ctx context.Context, sta
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		var count int64
+		scope := model.BranchScopeFromContext(ctx)
 		for _, o := range r.orders {
+			if !scope.Allows(o.BranchID) {
+				continue
+			}
 			if !o.CreatedAt.Before(startDate) && !o.CreatedAt.After(endDate) {
 				count++
 			}
@@ -488,7 +604,7 @@ func (r *ServiceOrderRepository) CountOrdersByDateRange(ctx context.Context, sta
 		return count, nil
 	}
 	var count int64
-	err := r.db.WithContext(ctx).Model(&model.ServiceOrder{}).
+	err := r.db.WithContext(ctx).Scopes(model.ScopeByBranch(ctx, "branch_id")).Model(&model.ServiceOrder{}).
 		Where("created_at >= ? AND created_at <= ?", startDate, endDate).
 		Count(&count).Error
 	return count, err
@@ -504,7 +620,11 @@ func (r *ServiceOrderRepository) GetOrdersByStatusInDateRange(ctx context.Contex
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		m := make(map[string]int64)
+		scope := model.BranchScopeFromContext(ctx)
 		for _, o := range r.orders {
+			if !scope.Allows(o.BranchID) {
+				continue
+			}
 			if !o.CreatedAt.Before(startDate) && !o.CreatedAt.After(endDate) {
 				m[string(o.Status)]++
 			}
@@ -512,7 +632,7 @@ func (r *ServiceOrderRepository) GetOrdersByStatusInDateRange(ctx context.Contex
 		return m, nil
 	}
 
-	err := r.db.WithContext(ctx).Model(&model.ServiceOrder{}).
+	err := r.db.WithContext(ctx).Scopes(model.ScopeByBranch(ctx, "branch_id")).Model(&model.ServiceOrder{}).
 		Select("status, COUNT(*) as count").
 		Where("created_at >= ? AND created_at <= ?", startDate, endDate).
 		Group("status").
@@ -540,7 +660,11 @@ func (r *ServiceOrderRepository) GetOrdersByBranchInDateRange(ctx context.Contex
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		m := make(map[string]int64)
+		scope := model.BranchScopeFromContext(ctx)
 		for _, o := range r.orders {
+			if !scope.Allows(o.BranchID) {
+				continue
+			}
 			if !o.CreatedAt.Before(startDate) && !o.CreatedAt.After(endDate) {
 				m[o.BranchID.String()]++
 			}
@@ -548,7 +672,7 @@ func (r *ServiceOrderRepository) GetOrdersByBranchInDateRange(ctx context.Contex
 		return m, nil
 	}
 
-	err := r.db.WithContext(ctx).Model(&model.ServiceOrder{}).
+	err := r.db.WithContext(ctx).Scopes(model.ScopeByBranch(ctx, "branch_id")).Model(&model.ServiceOrder{}).
 		Select("branch_id, COUNT(*) as count").
 		Where("created_at >= ? AND created_at <= ?", startDate, endDate).
 		Group("branch_id").
@@ -576,7 +700,11 @@ func (r *ServiceOrderRepository) GetOrdersByServiceTypeInDateRange(ctx context.C
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		m := make(map[string]int64)
+		scope := model.BranchScopeFromContext(ctx)
 		for _, o := range r.orders {
+			if !scope.Allows(o.BranchID) {
+				continue
+			}
 			if !o.CreatedAt.Before(startDate) && !o.CreatedAt.After(endDate) {
 				m[string(o.ServiceType)]++
 			}
@@ -584,7 +712,7 @@ func (r *ServiceOrderRepository) GetOrdersByServiceTypeInDateRange(ctx context.C
 		return m, nil
 	}
 
-	err := r.db.WithContext(ctx).Model(&model.ServiceOrder{}).
+	err := r.db.WithContext(ctx).Scopes(model.ScopeByBranch(ctx, "branch_id")).Model(&model.ServiceOrder{}).
 		Select("service_type, COUNT(*) as count").
 		Where("created_at >= ? AND created_at <= ?", startDate, endDate).
 		Group("service_type").
@@ -609,7 +737,11 @@ func (r *ServiceOrderRepository) GetTopServiceTypesInDateRange(ctx context.Conte
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		counts := make(map[string]int64)
+		scope := model.BranchScopeFromContext(ctx)
 		for _, o := range r.orders {
+			if !scope.Allows(o.BranchID) {
+				continue
+			}
 			if !o.CreatedAt.Before(startDate) && !o.CreatedAt.After(endDate) {
 				counts[string(o.ServiceType)]++
 			}
@@ -627,7 +759,7 @@ func (r *ServiceOrderRepository) GetTopServiceTypesInDateRange(ctx context.Conte
 		return stats, nil
 	}
 
-	err := r.db.WithContext(ctx).Model(&model.ServiceOrder{}).
+	err := r.db.WithContext(ctx).Scopes(model.ScopeByBranch(ctx, "branch_id")).Model(&model.ServiceOrder{}).
 		Select("service_type, COUNT(*) as order_count").
 		Where("created_at >= ? AND created_at <= ?", startDate, endDate).
 		Group("service_type").
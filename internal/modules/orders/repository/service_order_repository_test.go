@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"service/internal/shared/database/dbtest"
+	"service/internal/shared/model"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceOrderRepositoryUpdateVersion(t *testing.T) {
+	ctx := context.Background()
+	branchID := uuid.New()
+	writeFailed := errors.New("write failed")
+	otherBranch := model.WithBranchScope(ctx, &model.BranchScope{BranchIDs: []uuid.UUID{uuid.New()}})
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		matched     int64
+		writeErr    error
+		want        error
+		wantVersion int64
+	}{
+		{"current version", ctx, 1, nil, nil, 4},
+		{"stale version", ctx, 0, nil, model.ErrVersionConflict, 3},
+		{"failed write", ctx, 0, writeFailed, writeFailed, 3},
+		{"other branch", otherBranch, 0, nil, model.ErrForbidden, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := dbtest.Mock(t)
+			repo := &ServiceOrderRepository{db: db}
+			order := &model.ServiceOrder{ID: uuid.New(), BranchID: branchID, Status: model.StatusOnPickup, Version: 3}
+
+			// Orders outside the caller's branches are refused before anything is written
+			if tt.want != model.ErrForbidden {
+				mock.ExpectBegin()
+				update := dbtest.ExpectVersionedUpdate(mock, "service_orders", tt.matched)
+				if tt.writeErr != nil {
+					update.WillReturnError(tt.writeErr)
+					mock.ExpectRollback()
+				} else {
+					mock.ExpectCommit()
+				}
+			}
+
+			assert.Equal(t, tt.want, repo.Update(tt.ctx, order))
+			assert.Equal(t, tt.wantVersion, order.Version, "a failed write keeps the version it was read at")
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestServiceOrderRepositoryConcurrentWriters(t *testing.T) {
+	ctx := context.Background()
+	repo := NewServiceOrderRepository()
+	order := &model.ServiceOrder{BranchID: uuid.New(), Status: model.StatusInService}
+	assert.NoError(t, repo.Create(ctx, order))
+
+	first, second := *order, *order
+	first.Status = model.StatusReady
+	second.Status = model.StatusCancelled
+
+	assert.NoError(t, repo.Update(ctx, &first))
+	assert.Equal(t, model.ErrVersionConflict, repo.Update(ctx, &second))
+
+	stored, err := repo.GetByID(ctx, order.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.StatusReady, stored.Status)
+	assert.Equal(t, int64(2), stored.Version)
+}
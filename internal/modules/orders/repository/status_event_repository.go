@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StatusEventRepository handles the status history of orders
+type StatusEventRepository struct {
+	db *gorm.DB
+}
+
+// NewStatusEventRepository creates a new status event repository
+func NewStatusEventRepository() *StatusEventRepository {
+	return &StatusEventRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *StatusEventRepository) Available() bool {
+	return r.db != nil
+}
+
+// Record stores that an order reached a status
+func (r *StatusEventRepository) Record(ctx context.Context, orderID uuid.UUID, status model.OrderStatus) error {
+	return r.db.WithContext(ctx).Create(&model.OrderStatusEvent{
+		OrderID: orderID,
+		Status:  status,
+	}).Error
+}
+
+// ListByOrder retrieves the status history of an order, oldest first
+func (r *StatusEventRepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*model.OrderStatusEvent, error) {
+	var events []*model.OrderStatusEvent
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&events).Error
+	return events, err
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// openOrderStatuses are the statuses in which an order still needs its technician
+var openOrderStatuses = []model.OrderStatus{
+	model.StatusPendingPickup,
+	model.StatusOnPickup,
+	model.StatusInService,
+}
+
+// TechnicianRepository handles technician skills, shifts, workload and assignments
+type TechnicianRepository struct {
+	db *gorm.DB
+}
+
+// NewTechnicianRepository creates a new technician repository
+func NewTechnicianRepository() *TechnicianRepository {
+	return &TechnicianRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *TechnicianRepository) Available() bool {
+	return r.db != nil
+}
+
+// ListTechnicians retrieves the active technicians of a branch
+func (r *TechnicianRepository) ListTechnicians(ctx context.Context, branchID uuid.UUID) ([]*model.User, error) {
+	var users []*model.User
+	err := r.db.WithContext(ctx).
+		Where("role = ? AND branch_id = ? AND is_active = ? AND status = ?",
+			model.RoleTeknisi, branchID, true, model.UserStatusActive).
+		Order("created_at ASC").
+		Find(&users).Error
+	return users, err
+}
+
+// ListSkills retrieves the skills of the given technicians
+func (r *TechnicianRepository) ListSkills(ctx context.Context, userIDs []uuid.UUID) ([]*model.TechnicianSkill, error) {
+	var skills []*model.TechnicianSkill
+	err := r.db.WithContext(ctx).
+		Where("user_id IN ?", userIDs).
+		Order("service_type ASC").
+		Find(&skills).Error
+	return skills, err
+}
+
+// ListShifts retrieves the shifts of the given technicians
+func (r *TechnicianRepository) ListShifts(ctx context.Context, userIDs []uuid.UUID) ([]*model.TechnicianShift, error) {
+	var shifts []*model.TechnicianShift
+	err := r.db.WithContext(ctx).
+		Where("user_id IN ?", userIDs).
+		Order("weekday ASC, start_time ASC").
+		Find(&shifts).Error
+	return shifts, err
+}
+
+// ReplaceSkills replaces all skills of a technician in one transaction
+func (r *TechnicianRepository) ReplaceSkills(ctx context.Context, userID uuid.UUID, skills []*model.TechnicianSkill) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.TechnicianSkill{}).Error; err != nil {
+			return err
+		}
+		if len(skills) == 0 {
+			return nil
+		}
+		return tx.Create(&skills).Error
+	})
+}
+
+// ReplaceShifts replaces all shifts of a technician in one transaction
+func (r *TechnicianRepository) ReplaceShifts(ctx context.Context, userID uuid.UUID, shifts []*model.TechnicianShift) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.TechnicianShift{}).Error; err != nil {
+			return err
+		}
+		if len(shifts) == 0 {
+			return nil
+		}
+		return tx.Create(&shifts).Error
+	})
+}
+
+// CountOpenOrders counts the open orders assigned to each of the given technicians
+func (r *TechnicianRepository) CountOpenOrders(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	var rows []struct {
+		TechnicianID uuid.UUID
+		Count        int64
+	}
+	err := r.db.WithContext(ctx).Model(&model.ServiceOrder{}).
+		Select("technician_id, COUNT(*) AS count").
+		Where("technician_id IN ? AND status IN ?", userIDs, openOrderStatuses).
+		Group("technician_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.TechnicianID] = row.Count
+	}
+	return counts, nil
+}
+
+// LastAssignedAt returns when each of the given technicians was last assigned an order
+func (r *TechnicianRepository) LastAssignedAt(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	var rows []struct {
+		TechnicianID uuid.UUID
+		AssignedAt   time.Time
+	}
+	err := r.db.WithContext(ctx).Model(&model.TechnicianAssignment{}).
+		Select("technician_id, MAX(created_at) AS assigned_at").
+		Where("technician_id IN ?", userIDs).
+		Group("technician_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	last := make(map[uuid.UUID]time.Time, len(rows))
+	for _, row := range rows {
+		last[row.TechnicianID] = row.AssignedAt
+	}
+	return last, nil
+}
+
+// AutoAssign sets the technician of an order that has none yet and records the assignment
+// in one transaction. It returns the version the order was raised to, and fails with
+// ErrOrderAlreadyAssigned when someone else was faster.
+func (r *TechnicianRepository) AutoAssign(ctx context.Context, assignment *model.TechnicianAssignment) (int64, error) {
+	var updated model.ServiceOrder
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&updated).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "version"}}}).
+			Where("id = ? AND technician_id IS NULL", assignment.OrderID).
+			Updates(map[string]interface{}{
+				"technician_id": assignment.TechnicianID,
+				"version":       gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrOrderAlreadyAssigned
+		}
+		return tx.Create(assignment).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return updated.Version, nil
+}
+
+// RecordAssignment records an assignment made outside the engine
+func (r *TechnicianRepository) RecordAssignment(ctx context.Context, assignment *model.TechnicianAssignment) error {
+	return r.db.WithContext(ctx).Create(assignment).Error
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database/dbtest"
+	"service/internal/shared/model"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoAssignVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		rows        *sqlmock.Rows
+		want        error
+		wantVersion int64
+	}{
+		{"unassigned order", sqlmock.NewRows([]string{"version"}).AddRow(5), nil, 5},
+		{"assigned meanwhile", sqlmock.NewRows([]string{"version"}), model.ErrOrderAlreadyAssigned, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := dbtest.Mock(t)
+			repo := &TechnicianRepository{db: db}
+			assignment := &model.TechnicianAssignment{OrderID: uuid.New(), TechnicianID: uuid.New()}
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(`UPDATE "service_orders" SET .*"version"=version \+ 1.* WHERE \(id = \$\d+ AND technician_id IS NULL\) .*RETURNING "version"`).
+				WillReturnRows(tt.rows)
+			if tt.want != nil {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectQuery(`INSERT INTO "technician_assignments"`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+				mock.ExpectCommit()
+			}
+
+			version, err := repo.AutoAssign(context.Background(), assignment)
+			assert.Equal(t, tt.want, err)
+			assert.Equal(t, tt.wantVersion, version)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
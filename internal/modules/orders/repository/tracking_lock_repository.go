@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"service/internal/shared/database"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TrackingLockRepository counts failed public tracking lookups per client and order number
+// in Redis, so that a client guessing the phone digits of an order is cut off.
+//
+// The count is kept per client rather than per order number because order numbers count
+// up per branch and are easy to enumerate: a lock shared by all clients would let anyone
+// lock every customer out of tracking their order. The trade-off is that a client with
+// many addresses can spread its guesses over them, which the rate limit on the public
+// tracking routes bounds.
+type TrackingLockRepository struct {
+	redis *redis.Client
+}
+
+// NewTrackingLockRepository creates a new tracking lock repository
+func NewTrackingLockRepository() *TrackingLockRepository {
+	return &TrackingLockRepository{
+		redis: database.Redis,
+	}
+}
+
+// Available reports whether the repository is backed by Redis
+func (r *TrackingLockRepository) Available() bool {
+	return r.redis != nil
+}
+
+// Failures returns the failed lookups of an order number by a client in the current window
+func (r *TrackingLockRepository) Failures(ctx context.Context, client, orderNumber string) (int, error) {
+	count, err := r.redis.Get(ctx, trackingFailureKey(client, orderNumber)).Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+	return count, nil
+}
+
+// RecordFailure counts a failed lookup by a client. The window starts at the first failure.
+func (r *TrackingLockRepository) RecordFailure(ctx context.Context, client, orderNumber string, window time.Duration) error {
+	key := trackingFailureKey(client, orderNumber)
+	count, err := r.redis.Incr(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	if count == 1 {
+		return r.redis.Expire(ctx, key, window).Err()
+	}
+	return nil
+}
+
+// trackingFailureKey returns the Redis key counting failed lookups of an order number by a client
+func trackingFailureKey(client, orderNumber string) string {
+	return "public_tracking_failures:" + client + ":" + strings.ToUpper(orderNumber)
+}
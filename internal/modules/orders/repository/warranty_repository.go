@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+
+	"gorm.io/gorm"
+)
+
+// WarrantyRepository handles warranty data operations
+type WarrantyRepository struct {
+	db *gorm.DB
+}
+
+// NewWarrantyRepository creates a new warranty repository
+func NewWarrantyRepository() *WarrantyRepository {
+	return &WarrantyRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *WarrantyRepository) Available() bool {
+	return r.db != nil
+}
+
+// CreateForOrder starts the warranty of an order unless it already has one
+func (r *WarrantyRepository) CreateForOrder(ctx context.Context, warranty *model.Warranty) error {
+	return r.db.WithContext(ctx).
+		Omit("Order").
+		Where("order_id = ?", warranty.OrderID).
+		FirstOrCreate(warranty).Error
+}
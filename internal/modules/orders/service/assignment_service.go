@@ -0,0 +1,401 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"service/internal/modules/orders/repository"
+	userRepo "service/internal/modules/users/repository"
+	"service/internal/shared/config"
+	"service/internal/shared/model"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Defaults used when no configuration is loaded
+const (
+	defaultMaxOpenOrders = 10
+	defaultRatingWindow  = 90 * 24 * time.Hour
+
+	// neutralRating stands in for technicians who have not been rated recently
+	neutralRating = 3.0
+)
+
+// AssignmentService picks technicians for orders based on workload, skills, shifts and ratings
+type AssignmentService struct {
+	technicianRepo *repository.TechnicianRepository
+	orderRepo      *repository.ServiceOrderRepository
+	userRepo       *userRepo.UserRepository
+	ratingService  *RatingService
+}
+
+// NewAssignmentService creates a new assignment service
+func NewAssignmentService() *AssignmentService {
+	return &AssignmentService{
+		technicianRepo: repository.NewTechnicianRepository(),
+		orderRepo:      repository.NewServiceOrderRepository(),
+		userRepo:       userRepo.NewUserRepository(),
+		ratingService:  NewRatingService(),
+	}
+}
+
+// GetProfile retrieves the skills and shifts of a technician
+func (s *AssignmentService) GetProfile(ctx context.Context, technicianID uuid.UUID) (*model.TechnicianProfileResponse, error) {
+	if _, err := s.getTechnician(ctx, technicianID); err != nil {
+		return nil, err
+	}
+
+	profile := &model.TechnicianProfileResponse{
+		TechnicianID: technicianID,
+		Skills:       []*model.TechnicianSkill{},
+		Shifts:       []*model.TechnicianShift{},
+	}
+	if !s.technicianRepo.Available() {
+		return profile, nil
+	}
+
+	ids := []uuid.UUID{technicianID}
+	skills, err := s.technicianRepo.ListSkills(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	shifts, err := s.technicianRepo.ListShifts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	profile.Skills = append(profile.Skills, skills...)
+	profile.Shifts = append(profile.Shifts, shifts...)
+	return profile, nil
+}
+
+// UpdateSkills replaces the skills of a technician
+func (s *AssignmentService) UpdateSkills(ctx context.Context, technicianID uuid.UUID, req *model.TechnicianSkillsRequest) (*model.TechnicianProfileResponse, error) {
+	if _, err := s.getTechnician(ctx, technicianID); err != nil {
+		return nil, err
+	}
+	if !s.technicianRepo.Available() {
+		return nil, errors.New("technician assignment is not available")
+	}
+
+	seen := map[model.ServiceType]bool{}
+	skills := make([]*model.TechnicianSkill, 0, len(req.Skills))
+	for _, skill := range req.Skills {
+		if seen[skill.ServiceType] {
+			return nil, errors.New("each service type may only be listed once")
+		}
+		seen[skill.ServiceType] = true
+		skills = append(skills, &model.TechnicianSkill{
+			UserID:      technicianID,
+			ServiceType: skill.ServiceType,
+			Level:       skill.Level,
+		})
+	}
+
+	if err := s.technicianRepo.ReplaceSkills(ctx, technicianID, skills); err != nil {
+		return nil, err
+	}
+	return s.GetProfile(ctx, technicianID)
+}
+
+// UpdateShifts replaces the weekly shifts of a technician
+func (s *AssignmentService) UpdateShifts(ctx context.Context, technicianID uuid.UUID, req *model.TechnicianShiftsRequest) (*model.TechnicianProfileResponse, error) {
+	if _, err := s.getTechnician(ctx, technicianID); err != nil {
+		return nil, err
+	}
+	if !s.technicianRepo.Available() {
+		return nil, errors.New("technician assignment is not available")
+	}
+
+	shifts := make([]*model.TechnicianShift, 0, len(req.Shifts))
+	for _, shift := range req.Shifts {
+		if shift.EndTime <= shift.StartTime {
+			return nil, errors.New("shift end time must be after start time")
+		}
+		shifts = append(shifts, &model.TechnicianShift{
+			UserID:    technicianID,
+			Weekday:   *shift.Weekday,
+			StartTime: shift.StartTime,
+			EndTime:   shift.EndTime,
+		})
+	}
+
+	if err := s.technicianRepo.ReplaceShifts(ctx, technicianID, shifts); err != nil {
+		return nil, err
+	}
+	return s.GetProfile(ctx, technicianID)
+}
+
+// GetBranchWorkload lists the technicians of a branch with their current load, shift and rating
+func (s *AssignmentService) GetBranchWorkload(ctx context.Context, branchID uuid.UUID) ([]model.TechnicianCandidate, error) {
+	if !model.BranchScopeFromContext(ctx).Allows(branchID) {
+		return nil, model.ErrForbidden
+	}
+	return s.rankCandidates(ctx, branchID, "", resolveStrategy(""))
+}
+
+// GetCandidates lists the technicians of the order's branch ranked by the strategy, best first
+func (s *AssignmentService) GetCandidates(ctx context.Context, orderID uuid.UUID, strategy model.AssignmentStrategy) ([]model.TechnicianCandidate, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+	return s.rankCandidates(ctx, order.BranchID, order.ServiceType, resolveStrategy(strategy))
+}
+
+// AutoAssign assigns the best eligible technician to an order that has none yet.
+// An empty strategy uses the configured one.
+func (s *AssignmentService) AutoAssign(ctx context.Context, orderID uuid.UUID, strategy model.AssignmentStrategy) (*model.ServiceOrderResponse, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+	if order.TechnicianID != nil {
+		return nil, model.ErrOrderAlreadyAssigned
+	}
+	if !s.technicianRepo.Available() {
+		return nil, model.ErrNoTechnicianAvailable
+	}
+
+	resolved := resolveStrategy(strategy)
+	candidates, err := s.rankCandidates(ctx, order.BranchID, order.ServiceType, resolved)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 || !candidates[0].Eligible {
+		return nil, model.ErrNoTechnicianAvailable
+	}
+
+	best := candidates[0]
+	version, err := s.technicianRepo.AutoAssign(ctx, &model.TechnicianAssignment{
+		OrderID:      order.ID,
+		TechnicianID: best.TechnicianID,
+		Strategy:     resolved,
+		Score:        best.Score,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	order.TechnicianID = &best.TechnicianID
+	order.Version = version
+	response := order.ToResponse()
+	return &response, nil
+}
+
+// AssignOnArrival assigns a technician automatically once an order has arrived at the branch,
+// when enabled. Failures are logged only; staff can still assign by hand.
+func (s *AssignmentService) AssignOnArrival(ctx context.Context, order *model.ServiceOrder) {
+	if config.Config == nil || !config.Config.TechnicianAutoAssign {
+		return
+	}
+	if order.TechnicianID != nil || order.Status != model.StatusInService || !s.technicianRepo.Available() {
+		return
+	}
+
+	response, err := s.AutoAssign(ctx, order.ID, "")
+	if err != nil {
+		log.Printf("Automatic technician assignment for order %s failed: %v", order.ID, err)
+		return
+	}
+	// The assignment raised the version; without it the caller would hand out a stale ETag
+	order.TechnicianID = response.TechnicianID
+	order.Version = response.Version
+}
+
+// RecordManualAssignment records a technician chosen by staff, which overrides the engine
+func (s *AssignmentService) RecordManualAssignment(ctx context.Context, orderID, technicianID uuid.UUID, assignedBy *uuid.UUID) {
+	if !s.technicianRepo.Available() {
+		return
+	}
+	if err := s.technicianRepo.RecordAssignment(ctx, &model.TechnicianAssignment{
+		OrderID:      orderID,
+		TechnicianID: technicianID,
+		Strategy:     model.AssignmentStrategyManual,
+		AssignedBy:   assignedBy,
+	}); err != nil {
+		log.Printf("Failed to record manual assignment of order %s: %v", orderID, err)
+	}
+}
+
+// rankCandidates weighs the technicians of a branch for a service type, best first.
+// Technicians are only eligible while on shift, below the open order limit and, when
+// anyone in the branch has the skill, skilled for the service type.
+func (s *AssignmentService) rankCandidates(ctx context.Context, branchID uuid.UUID, serviceType model.ServiceType, strategy model.AssignmentStrategy) ([]model.TechnicianCandidate, error) {
+	candidates := []model.TechnicianCandidate{}
+	if !s.technicianRepo.Available() {
+		return candidates, nil
+	}
+
+	technicians, err := s.technicianRepo.ListTechnicians(ctx, branchID)
+	if err != nil || len(technicians) == 0 {
+		return candidates, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(technicians))
+	for _, t := range technicians {
+		ids = append(ids, t.ID)
+	}
+	skills, err := s.technicianRepo.ListSkills(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	shifts, err := s.technicianRepo.ListShifts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	openOrders, err := s.technicianRepo.CountOpenOrders(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	lastAssigned, err := s.technicianRepo.LastAssignedAt(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	skillLevels := map[uuid.UUID]int{}
+	skillRequired := false
+	for _, skill := range skills {
+		if skill.ServiceType == serviceType {
+			skillLevels[skill.UserID] = skill.Level
+			skillRequired = true
+		}
+	}
+	shiftsByUser := map[uuid.UUID][]*model.TechnicianShift{}
+	for _, shift := range shifts {
+		shiftsByUser[shift.UserID] = append(shiftsByUser[shift.UserID], shift)
+	}
+
+	now := time.Now()
+	maxOpen := maxOpenOrders()
+	since := now.Add(-ratingWindow())
+	for _, t := range technicians {
+		candidate := model.TechnicianCandidate{
+			TechnicianID: t.ID,
+			FullName:     t.FullName,
+			OpenOrders:   openOrders[t.ID],
+			SkillLevel:   skillLevels[t.ID],
+			OnShift:      onShift(shiftsByUser[t.ID], now),
+			Eligible:     true,
+		}
+		if at, ok := lastAssigned[t.ID]; ok {
+			candidate.LastAssignedAt = &at
+		}
+		if rating, err := s.ratingService.GetRecentAverageRating(ctx, t.ID, since); err == nil {
+			candidate.AverageRating = rating.AverageRating
+			candidate.TotalRatings = rating.TotalRatings
+		}
+
+		switch {
+		case !candidate.OnShift:
+			candidate.Eligible, candidate.Reason = false, "off shift"
+		case maxOpen > 0 && candidate.OpenOrders >= int64(maxOpen):
+			candidate.Eligible, candidate.Reason = false, "open order limit reached"
+		case serviceType != "" && skillRequired && candidate.SkillLevel == 0:
+			candidate.Eligible, candidate.Reason = false, "not skilled for this service type"
+		}
+		candidate.Score = score(candidate, strategy, maxOpen)
+		candidates = append(candidates, candidate)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Eligible != b.Eligible {
+			return a.Eligible
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if strategy != model.AssignmentStrategyRoundRobin && a.AverageRating != b.AverageRating {
+			return a.AverageRating > b.AverageRating
+		}
+		// Whoever waited longest for a job goes first
+		if a.LastAssignedAt == nil || b.LastAssignedAt == nil {
+			return a.LastAssignedAt == nil && b.LastAssignedAt != nil
+		}
+		return a.LastAssignedAt.Before(*b.LastAssignedAt)
+	})
+	return candidates, nil
+}
+
+// getTechnician retrieves a technician inside the caller's branch scope
+func (s *AssignmentService) getTechnician(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, model.ErrUserNotFound
+	}
+	if user.Role != model.RoleTeknisi {
+		return nil, model.ErrNotTechnician
+	}
+	scope := model.BranchScopeFromContext(ctx)
+	if scope.IsRestricted() && (user.BranchID == nil || !scope.Allows(*user.BranchID)) {
+		return nil, model.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// resolveStrategy resolves the strategy to use, falling back to the configured one
+func resolveStrategy(strategy model.AssignmentStrategy) model.AssignmentStrategy {
+	if model.IsValidAssignmentStrategy(strategy) {
+		return strategy
+	}
+	if config.Config != nil {
+		if configured := model.AssignmentStrategy(config.Config.TechnicianAssignmentStrategy); model.IsValidAssignmentStrategy(configured) {
+			return configured
+		}
+	}
+	return model.AssignmentStrategyLeastLoaded
+}
+
+// score rates a candidate for a strategy; higher is better
+func score(c model.TechnicianCandidate, strategy model.AssignmentStrategy, maxOpen int) float64 {
+	switch strategy {
+	case model.AssignmentStrategyRoundRobin:
+		return 0
+	case model.AssignmentStrategySkillWeighted:
+		rating := c.AverageRating
+		if c.TotalRatings == 0 {
+			rating = neutralRating
+		}
+		var capacity float64
+		if maxOpen > 0 {
+			capacity = 1 - float64(c.OpenOrders)/float64(maxOpen)
+		} else {
+			capacity = 1 / float64(1+c.OpenOrders)
+		}
+		return 0.5*float64(c.SkillLevel)/5 + 0.3*capacity + 0.2*rating/5
+	default:
+		return 1 / float64(1+c.OpenOrders)
+	}
+}
+
+// onShift reports whether any of the shifts covers now. Technicians without shifts
+// are treated as always available.
+func onShift(shifts []*model.TechnicianShift, now time.Time) bool {
+	if len(shifts) == 0 {
+		return true
+	}
+	for _, shift := range shifts {
+		if shift.Covers(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxOpenOrders returns the open order limit per technician; 0 means unlimited
+func maxOpenOrders() int {
+	if config.Config != nil {
+		return config.Config.TechnicianMaxOpenOrders
+	}
+	return defaultMaxOpenOrders
+}
+
+// ratingWindow returns how far back ratings count for assignment
+func ratingWindow() time.Duration {
+	if config.Config != nil && config.Config.TechnicianRatingWindow > 0 {
+		return config.Config.TechnicianRatingWindow
+	}
+	return defaultRatingWindow
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"service/internal/modules/orders/repository"
+	"service/internal/shared/database/dbtest"
+	"service/internal/shared/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankCandidates(t *testing.T) {
+	now := time.Now()
+	ids := map[string]uuid.UUID{}
+	for _, name := range []string{"Adi", "Budi", "Citra", "Dewi", "Eka"} {
+		ids[name] = uuid.New()
+	}
+
+	tests := []struct {
+		strategy model.AssignmentStrategy
+		eligible []string
+	}{
+		{model.AssignmentStrategyLeastLoaded, []string{"Eka", "Adi"}},
+		{model.AssignmentStrategySkillWeighted, []string{"Adi", "Eka"}},
+		{model.AssignmentStrategyRoundRobin, []string{"Adi", "Eka"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			mock := dbtest.MockGlobal(t)
+			s := &AssignmentService{
+				technicianRepo: repository.NewTechnicianRepository(),
+				ratingService:  &RatingService{ratingRepo: &repository.RatingRepository{}},
+			}
+
+			users := sqlmock.NewRows([]string{"id", "full_name"})
+			for _, name := range []string{"Adi", "Budi", "Citra", "Dewi", "Eka"} {
+				users.AddRow(ids[name], name)
+			}
+			mock.ExpectQuery(`SELECT \* FROM "users"`).WillReturnRows(users)
+			// Budi only repairs batteries while others in the branch repair screens
+			mock.ExpectQuery(`SELECT \* FROM "technician_skills"`).WillReturnRows(sqlmock.NewRows([]string{"user_id", "service_type", "level"}).
+				AddRow(ids["Adi"], model.ServiceTypeScreenRepair, 3).
+				AddRow(ids["Budi"], model.ServiceTypeBatteryReplacement, 5).
+				AddRow(ids["Citra"], model.ServiceTypeScreenRepair, 5).
+				AddRow(ids["Dewi"], model.ServiceTypeScreenRepair, 4).
+				AddRow(ids["Eka"], model.ServiceTypeScreenRepair, 2))
+			// Dewi only works tomorrow
+			mock.ExpectQuery(`SELECT \* FROM "technician_shifts"`).WillReturnRows(sqlmock.NewRows([]string{"user_id", "weekday", "start_time", "end_time"}).
+				AddRow(ids["Dewi"], (int(now.Weekday())+1)%7, "00:00", "23:59"))
+			// Citra is at the open order limit
+			mock.ExpectQuery(`SELECT technician_id, COUNT\(\*\) AS count FROM "service_orders"`).WillReturnRows(sqlmock.NewRows([]string{"technician_id", "count"}).
+				AddRow(ids["Adi"], 2).
+				AddRow(ids["Citra"], defaultMaxOpenOrders))
+			mock.ExpectQuery(`SELECT technician_id, MAX\(created_at\) AS assigned_at FROM "technician_assignments"`).WillReturnRows(sqlmock.NewRows([]string{"technician_id", "assigned_at"}).
+				AddRow(ids["Adi"], now.Add(-48*time.Hour)).
+				AddRow(ids["Eka"], now.Add(-time.Hour)))
+
+			candidates, err := s.rankCandidates(context.Background(), uuid.New(), model.ServiceTypeScreenRepair, tt.strategy)
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			var eligible []string
+			reasons := map[string]string{}
+			for _, c := range candidates {
+				if c.Eligible {
+					eligible = append(eligible, c.FullName)
+				} else {
+					reasons[c.FullName] = c.Reason
+				}
+			}
+			assert.Equal(t, tt.eligible, eligible)
+			assert.Equal(t, map[string]string{
+				"Budi":  "not skilled for this service type",
+				"Citra": "open order limit reached",
+				"Dewi":  "off shift",
+			}, reasons)
+			assert.True(t, candidates[0].Eligible, "eligible technicians come first")
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		candidate model.TechnicianCandidate
+		strategy  model.AssignmentStrategy
+		maxOpen   int
+		want      float64
+	}{
+		{"least loaded without orders", model.TechnicianCandidate{}, model.AssignmentStrategyLeastLoaded, 10, 1},
+		{"least loaded with orders", model.TechnicianCandidate{OpenOrders: 3}, model.AssignmentStrategyLeastLoaded, 10, 0.25},
+		{"round robin ignores load", model.TechnicianCandidate{OpenOrders: 3}, model.AssignmentStrategyRoundRobin, 10, 0},
+		{"skill weighted", model.TechnicianCandidate{SkillLevel: 5, OpenOrders: 5, AverageRating: 4, TotalRatings: 8}, model.AssignmentStrategySkillWeighted, 10, 0.5 + 0.15 + 0.16},
+		{"skill weighted unrated is neutral", model.TechnicianCandidate{SkillLevel: 5}, model.AssignmentStrategySkillWeighted, 10, 0.5 + 0.3 + 0.12},
+		{"skill weighted without a limit", model.TechnicianCandidate{OpenOrders: 1, AverageRating: 5, TotalRatings: 1}, model.AssignmentStrategySkillWeighted, 0, 0.15 + 0.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, score(tt.candidate, tt.strategy, tt.maxOpen), 1e-9)
+		})
+	}
+}
+
+func TestOnShift(t *testing.T) {
+	// A Wednesday at 10:30
+	now := time.Date(2026, time.October, 14, 10, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name   string
+		shifts []*model.TechnicianShift
+		want   bool
+	}{
+		{"no shifts means always available", nil, true},
+		{"inside a shift", []*model.TechnicianShift{{Weekday: 3, StartTime: "08:00", EndTime: "17:00"}}, true},
+		{"shift starting now", []*model.TechnicianShift{{Weekday: 3, StartTime: "10:30", EndTime: "17:00"}}, true},
+		{"shift ending now", []*model.TechnicianShift{{Weekday: 3, StartTime: "08:00", EndTime: "10:30"}}, false},
+		{"other weekday", []*model.TechnicianShift{{Weekday: 4, StartTime: "08:00", EndTime: "17:00"}}, false},
+		{"any of several", []*model.TechnicianShift{
+			{Weekday: 3, StartTime: "06:00", EndTime: "09:00"},
+			{Weekday: 3, StartTime: "10:00", EndTime: "14:00"},
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, onShift(tt.shifts, now))
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"log"
+	"service/internal/modules/orders/repository"
+	"service/internal/shared/config"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// cancellableStatuses lists the statuses an order can be cancelled in, in the order the
+// order progresses through them
+var cancellableStatuses = []model.OrderStatus{
+	model.StatusPendingPickup,
+	model.StatusOnPickup,
+	model.StatusInService,
+	model.StatusReady,
+}
+
+// CancellationService handles the cancellation fee schedule, cancellation records and
+// cancellation analytics
+type CancellationService struct {
+	cancellationRepo *repository.CancellationRepository
+}
+
+// NewCancellationService creates a new cancellation service
+func NewCancellationService() *CancellationService {
+	return &CancellationService{
+		cancellationRepo: repository.NewCancellationRepository(),
+	}
+}
+
+// defaultCancellationFee returns the configured fee for cancelling an order in a status.
+// Cancelling is free until a courier is on the way; once the device reached the branch the
+// diagnostic work is charged.
+func defaultCancellationFee(status model.OrderStatus) float64 {
+	if config.Config == nil {
+		return 0
+	}
+	switch status {
+	case model.StatusOnPickup:
+		return config.Config.CancellationPickupFee
+	case model.StatusInService, model.StatusReady:
+		return config.Config.CancellationDiagnosticFee
+	}
+	return 0
+}
+
+// FeeFor returns the fee for cancelling an order in the given status
+func (s *CancellationService) FeeFor(ctx context.Context, status model.OrderStatus) float64 {
+	schedule, err := s.FeeSchedule(ctx)
+	if err != nil {
+		log.Printf("Failed to load cancellation fee schedule: %v", err)
+		return defaultCancellationFee(status)
+	}
+	for _, entry := range schedule {
+		if entry.Status == status {
+			return entry.Fee
+		}
+	}
+	return 0
+}
+
+// FeeSchedule returns the cancellation fee of every cancellable status, with the
+// overrides set by admins taking precedence over the configured defaults
+func (s *CancellationService) FeeSchedule(ctx context.Context) ([]model.CancellationFeeScheduleEntry, error) {
+	overrides := make(map[model.OrderStatus]float64)
+	if s.cancellationRepo.Available() {
+		rules, err := s.cancellationRepo.ListFeeRules(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range rules {
+			overrides[rule.Status] = rule.Fee
+		}
+	}
+
+	schedule := make([]model.CancellationFeeScheduleEntry, 0, len(cancellableStatuses))
+	for _, status := range cancellableStatuses {
+		entry := model.CancellationFeeScheduleEntry{Status: status, Fee: defaultCancellationFee(status)}
+		if fee, ok := overrides[status]; ok {
+			entry.Fee = fee
+			entry.IsOverride = true
+		}
+		schedule = append(schedule, entry)
+	}
+	return schedule, nil
+}
+
+// UpdateFeeSchedule overrides the cancellation fees of the given statuses
+func (s *CancellationService) UpdateFeeSchedule(ctx context.Context, userID uuid.UUID, req *model.CancellationFeeScheduleRequest) ([]model.CancellationFeeScheduleEntry, error) {
+	rules := make([]*model.CancellationFeeRule, 0, len(req.Fees))
+	for _, fee := range req.Fees {
+		if !model.IsCancellable(fee.Status) {
+			return nil, model.ErrInvalidCancellationStatus
+		}
+		rules = append(rules, &model.CancellationFeeRule{
+			Status:    fee.Status,
+			Fee:       fee.Fee,
+			UpdatedBy: userID,
+		})
+	}
+
+	if s.cancellationRepo.Available() {
+		if err := s.cancellationRepo.SaveFeeRules(ctx, rules); err != nil {
+			return nil, err
+		}
+	}
+	return s.FeeSchedule(ctx)
+}
+
+// Report summarizes the cancellations of a period: how many orders were cancelled and why,
+// at which stage, and what was charged and refunded
+func (s *CancellationService) Report(ctx context.Context, start, end time.Time, branchID *uuid.UUID) (*model.CancellationReport, error) {
+	if branchID != nil && !model.BranchScopeFromContext(ctx).Allows(*branchID) {
+		return nil, model.ErrForbidden
+	}
+
+	report := &model.CancellationReport{
+		StartDate: start,
+		EndDate:   end,
+		ByReason:  make(map[model.CancellationReason]int64),
+		ByStage:   make(map[model.OrderStatus]int64),
+	}
+	if !s.cancellationRepo.Available() {
+		return report, nil
+	}
+
+	if err := s.cancellationRepo.Summarize(ctx, report, branchID); err != nil {
+		return nil, err
+	}
+	total, err := s.cancellationRepo.CountOrders(ctx, start, end, branchID)
+	if err != nil {
+		return nil, err
+	}
+	report.TotalOrders = total
+	if total > 0 {
+		report.CancelRate = float64(report.Cancelled) / float64(total) * 100
+	}
+	return report, nil
+}
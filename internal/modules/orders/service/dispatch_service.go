@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	branchRepo "service/internal/modules/branches/repository"
+	"service/internal/modules/orders/repository"
+	userRepo "service/internal/modules/users/repository"
+	"service/internal/shared/config"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Dispatch defaults used when no configuration is loaded
+const (
+	defaultClusterRadiusKm = 5
+	defaultMaxRouteStops   = 8
+	defaultCourierSpeedKmh = 25
+	defaultStopDuration    = 10 * time.Minute
+	defaultPlanTTL         = 10 * time.Minute
+)
+
+// DispatchService plans multi-stop courier routes for pickups and deliveries
+type DispatchService struct {
+	dispatchRepo *repository.DispatchRepository
+	orderRepo    *repository.ServiceOrderRepository
+	branchRepo   *branchRepo.BranchRepository
+	userRepo     *userRepo.UserRepository
+}
+
+// NewDispatchService creates a new dispatch service
+func NewDispatchService() *DispatchService {
+	return &DispatchService{
+		dispatchRepo: repository.NewDispatchRepository(),
+		orderRepo:    repository.NewServiceOrderRepository(),
+		branchRepo:   branchRepo.NewBranchRepository(),
+		userRepo:     userRepo.NewUserRepository(),
+	}
+}
+
+// PlanRoutes replaces the proposed routes of a branch with a fresh plan covering every
+// pickup and delivery that no courier has taken yet
+func (s *DispatchService) PlanRoutes(ctx context.Context, branchID uuid.UUID) (*model.DispatchPlanResponse, error) {
+	if !model.BranchScopeFromContext(ctx).Allows(branchID) {
+		return nil, model.ErrForbidden
+	}
+	if !s.dispatchRepo.Available() {
+		return nil, errors.New("dispatch planning is not available")
+	}
+
+	branch, err := s.branchRepo.GetByID(ctx, branchID)
+	if err != nil {
+		return nil, model.ErrBranchNotFound
+	}
+
+	stops, err := s.pendingStops(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	routes := planCourierRoutes(routePoint{lat: branch.Latitude, lon: branch.Longitude}, stops, now, plannerSettings())
+	if err := s.dispatchRepo.ReplacePlan(ctx, branchID, routes); err != nil {
+		return nil, err
+	}
+
+	return &model.DispatchPlanResponse{
+		BranchID:   branchID,
+		PlannedAt:  now,
+		Routes:     routes,
+		TotalStops: len(stops),
+	}, nil
+}
+
+// GetCourierRoutes lists the proposed routes of the courier's branch. The plan is refreshed
+// when it is missing or older than the configured lifetime.
+func (s *DispatchService) GetCourierRoutes(ctx context.Context, courierID uuid.UUID) (*model.DispatchPlanResponse, error) {
+	courier, err := s.getCourier(ctx, courierID)
+	if err != nil {
+		return nil, err
+	}
+	if courier.BranchID == nil {
+		return nil, model.ErrForbidden
+	}
+	if !s.dispatchRepo.Available() {
+		return nil, errors.New("dispatch planning is not available")
+	}
+
+	plannedAt, err := s.dispatchRepo.LatestPlanAt(ctx, *courier.BranchID)
+	if err != nil {
+		return nil, err
+	}
+	if plannedAt == nil || time.Since(*plannedAt) > planTTL() {
+		return s.PlanRoutes(ctx, *courier.BranchID)
+	}
+
+	routes, err := s.dispatchRepo.ListProposed(ctx, *courier.BranchID)
+	if err != nil {
+		return nil, err
+	}
+	plan := &model.DispatchPlanResponse{
+		BranchID:  *courier.BranchID,
+		PlannedAt: *plannedAt,
+		Routes:    routes,
+	}
+	for _, route := range routes {
+		plan.TotalStops += len(route.Stops)
+	}
+	return plan, nil
+}
+
+// AcceptRoute gives a whole proposed route to a courier of the same branch
+func (s *DispatchService) AcceptRoute(ctx context.Context, routeID, courierID uuid.UUID) (*model.CourierRoute, error) {
+	courier, err := s.getCourier(ctx, courierID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.dispatchRepo.Available() {
+		return nil, model.ErrRouteNotFound
+	}
+
+	route, err := s.dispatchRepo.GetByID(ctx, routeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrRouteNotFound
+		}
+		return nil, err
+	}
+	if courier.BranchID == nil || *courier.BranchID != route.BranchID {
+		return nil, model.ErrRouteNotFound
+	}
+
+	if err := s.dispatchRepo.Accept(ctx, route, courierID); err != nil {
+		return nil, err
+	}
+	return route, nil
+}
+
+// ListMyRoutes lists the routes a courier has taken and not finished yet
+func (s *DispatchService) ListMyRoutes(ctx context.Context, courierID uuid.UUID) ([]*model.CourierRoute, error) {
+	if !s.dispatchRepo.Available() {
+		return []*model.CourierRoute{}, nil
+	}
+	return s.dispatchRepo.ListByCourier(ctx, courierID, []model.CourierRouteStatus{model.CourierRouteStatusAccepted})
+}
+
+// SetDeliveryWindow sets when the customer wants the device back. Customers can only
+// change their own orders.
+func (s *DispatchService) SetDeliveryWindow(ctx context.Context, orderID, userID uuid.UUID, role model.UserRole, req *model.DeliveryWindowRequest) (*model.ServiceOrderResponse, error) {
+	if !model.IsValidTimeWindow(req.Start, req.End) {
+		return nil, model.ErrInvalidTimeWindow
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+	if role == model.RolePelanggan && order.CustomerID != userID {
+		return nil, model.ErrOrderNotFound
+	}
+	switch order.Status {
+	case model.StatusDelivered, model.StatusCompleted, model.StatusCancelled:
+		return nil, model.ErrDeliveryWindowState
+	}
+
+	order.DeliveryWindowStart = req.Start
+	order.DeliveryWindowEnd = req.End
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return nil, err
+	}
+
+	response := order.ToResponse()
+	return &response, nil
+}
+
+// CompleteStops marks the route stops of an order as visited once its status shows the
+// courier has been there. Failures are logged only.
+func (s *DispatchService) CompleteStops(ctx context.Context, order *model.ServiceOrder) {
+	if !s.dispatchRepo.Available() {
+		return
+	}
+
+	var kinds []model.RouteStopKind
+	switch order.Status {
+	case model.StatusInService:
+		kinds = []model.RouteStopKind{model.RouteStopPickup}
+	case model.StatusDelivered:
+		kinds = []model.RouteStopKind{model.RouteStopDelivery}
+	case model.StatusCancelled:
+		kinds = []model.RouteStopKind{model.RouteStopPickup, model.RouteStopDelivery}
+	default:
+		return
+	}
+
+	if err := s.dispatchRepo.CompleteStops(ctx, order.ID, kinds); err != nil {
+		log.Printf("Failed to complete route stops of order %s: %v", order.ID, err)
+	}
+}
+
+// pendingStops collects the pickups and deliveries of a branch that still need a courier
+func (s *DispatchService) pendingStops(ctx context.Context, branchID uuid.UUID) ([]plannedStop, error) {
+	pickups, err := s.dispatchRepo.PendingPickups(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+	deliveries, err := s.dispatchRepo.PendingDeliveries(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	stops := make([]plannedStop, 0, len(pickups)+len(deliveries))
+	for _, order := range pickups {
+		stops = append(stops, plannedStop{
+			order:       order,
+			kind:        model.RouteStopPickup,
+			point:       routePoint{lat: order.PickupLatitude, lon: order.PickupLongitude},
+			address:     order.PickupAddress,
+			windowStart: order.PickupWindowStart,
+			windowEnd:   order.PickupWindowEnd,
+		})
+	}
+	// Devices go back to where they were collected
+	for _, order := range deliveries {
+		stops = append(stops, plannedStop{
+			order:       order,
+			kind:        model.RouteStopDelivery,
+			point:       routePoint{lat: order.PickupLatitude, lon: order.PickupLongitude},
+			address:     order.PickupAddress,
+			windowStart: order.DeliveryWindowStart,
+			windowEnd:   order.DeliveryWindowEnd,
+		})
+	}
+	return stops, nil
+}
+
+// getCourier retrieves a courier user
+func (s *DispatchService) getCourier(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, model.ErrUserNotFound
+	}
+	if user.Role != model.RoleKurir {
+		return nil, model.ErrNotCourier
+	}
+	return user, nil
+}
+
+// plannerSettings returns the dispatch planner options from the configuration
+func plannerSettings() plannerOptions {
+	opts := plannerOptions{
+		clusterRadiusKm: defaultClusterRadiusKm,
+		maxStops:        defaultMaxRouteStops,
+		speedKmh:        defaultCourierSpeedKmh,
+		stopDuration:    defaultStopDuration,
+	}
+	if config.Config == nil {
+		return opts
+	}
+	if config.Config.DispatchClusterRadiusKm > 0 {
+		opts.clusterRadiusKm = float64(config.Config.DispatchClusterRadiusKm)
+	}
+	if config.Config.DispatchMaxStops > 0 {
+		opts.maxStops = config.Config.DispatchMaxStops
+	}
+	if config.Config.DispatchAverageSpeedKmh > 0 {
+		opts.speedKmh = float64(config.Config.DispatchAverageSpeedKmh)
+	}
+	if config.Config.DispatchStopDuration > 0 {
+		opts.stopDuration = config.Config.DispatchStopDuration
+	}
+	return opts
+}
+
+// planTTL returns how long a plan is offered to couriers before it is recomputed
+func planTTL() time.Duration {
+	if config.Config != nil && config.Config.DispatchPlanTTL > 0 {
+		return config.Config.DispatchPlanTTL
+	}
+	return defaultPlanTTL
+}
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	mediaService "service/internal/modules/media/service"
+	notificationService "service/internal/modules/notification/service"
+	"service/internal/modules/orders/repository"
+	userRepo "service/internal/modules/users/repository"
+	"service/internal/shared/config"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// handoverCodeLength is the number of digits in a handover code
+	handoverCodeLength = 6
+	// handoverCodeMaxAttempts is the number of wrong codes allowed before a new code is needed
+	handoverCodeMaxAttempts = 5
+	// handoverResendInterval throttles how often a customer can get a new code
+	handoverResendInterval = time.Minute
+	// handoverMaxUploadSize caps the size of handover photos and signatures
+	handoverMaxUploadSize = 10 << 20
+	// handoverPhotoURLExpiry is how long links to handover evidence stay valid
+	handoverPhotoURLExpiry = 15 * time.Minute
+)
+
+// HandoverService issues handover codes and records the proof of each device handover
+type HandoverService struct {
+	handoverRepo    *repository.HandoverRepository
+	userRepo        *userRepo.UserRepository
+	whatsAppService *notificationService.WhatsAppService
+	fileService     *mediaService.FileService
+}
+
+// NewHandoverService creates a new handover service
+func NewHandoverService() *HandoverService {
+	var fileService *mediaService.FileService
+	if config.Config != nil {
+		var err error
+		fileService, err = mediaService.NewFileService()
+		if err != nil {
+			// Codes are still issued; proofs cannot be stored until storage is back
+			log.Printf("Failed to initialize file service for handovers: %v", err)
+			fileService = nil
+		}
+	}
+	return &HandoverService{
+		handoverRepo:    repository.NewHandoverRepository(),
+		userRepo:        userRepo.NewUserRepository(),
+		whatsAppService: notificationService.NewWhatsAppService(),
+		fileService:     fileService,
+	}
+}
+
+// IssueCode creates a new one-time code for a handover of the order and sends it to the
+// customer over WhatsApp when their phone number is verified. Any earlier code for the same
+// handover stops working.
+func (s *HandoverService) IssueCode(ctx context.Context, order *model.ServiceOrder, kind model.HandoverKind) (*model.HandoverCodeResponse, error) {
+	if !s.handoverRepo.Available() {
+		return nil, errors.New("handover codes are not available")
+	}
+
+	existing, err := s.handoverRepo.GetCode(ctx, order.ID, kind)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	now := time.Now()
+	if existing != nil && existing.UsedAt == nil && now.Sub(existing.CreatedAt) < handoverResendInterval {
+		return nil, model.ErrHandoverCodeThrottled
+	}
+
+	code, err := utils.RandomDigits(handoverCodeLength)
+	if err != nil {
+		return nil, err
+	}
+	record := &model.HandoverCode{
+		OrderID:   order.ID,
+		Kind:      kind,
+		CodeHash:  hashHandoverCode(order.ID, kind, code),
+		ExpiresAt: now.Add(handoverCodeExpiry()),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.handoverRepo.UpsertCode(ctx, record); err != nil {
+		return nil, err
+	}
+
+	s.sendCode(ctx, order, kind, code)
+	return &model.HandoverCodeResponse{
+		OrderID:   order.ID,
+		Kind:      kind,
+		Code:      code,
+		ExpiresAt: record.ExpiresAt,
+	}, nil
+}
+
+// IssueOnTransition sends the customer a code when a courier leg starts: on the way to
+// collect the device, or when the repaired device is ready to go back. Failures are logged only.
+func (s *HandoverService) IssueOnTransition(ctx context.Context, order *model.ServiceOrder) {
+	if !s.handoverRepo.Available() {
+		return
+	}
+	kind, _, ok := model.HandoverKindFor(order.Status)
+	if !ok {
+		return
+	}
+	if _, err := s.IssueCode(ctx, order, kind); err != nil && err != model.ErrHandoverCodeThrottled {
+		log.Printf("Failed to issue %s handover code for order %s: %v", kind, order.ID, err)
+	}
+}
+
+// Verify checks the customer's code or signature and stores the handover proof with its
+// photo, location and time. Refused handovers are logged for dispute resolution.
+func (s *HandoverService) Verify(ctx context.Context, order *model.ServiceOrder, kind model.HandoverKind, performedBy uuid.UUID, req *model.HandoverRequest, photo, signature *multipart.FileHeader) (*model.HandoverProofResponse, error) {
+	if !s.handoverRepo.Available() || s.fileService == nil {
+		return nil, errors.New("handover proof storage is not available")
+	}
+
+	fail := func(reason string, err error) error {
+		s.logAttempt(ctx, order.ID, kind, performedBy, req, reason)
+		return err
+	}
+
+	if req.Code == "" && signature == nil {
+		return nil, fail(model.HandoverFailMissingProof, model.ErrHandoverProofMissing)
+	}
+	if photo == nil {
+		return nil, fail(model.HandoverFailMissingPhoto, model.ErrHandoverPhotoRequired)
+	}
+	if !isImageUpload(photo) || (signature != nil && !isImageUpload(signature)) {
+		return nil, fail(model.HandoverFailInvalidImage, model.ErrHandoverInvalidImage)
+	}
+
+	// A code, when given, must be right even if a signature was captured too
+	method := model.HandoverMethodSignature
+	var codeID *uuid.UUID
+	if req.Code != "" {
+		code, err := s.handoverRepo.GetCode(ctx, order.ID, kind)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fail(model.HandoverFailNoCode, model.ErrInvalidHandoverCode)
+			}
+			return nil, err
+		}
+		switch {
+		case code.UsedAt != nil:
+			return nil, fail(model.HandoverFailNoCode, model.ErrInvalidHandoverCode)
+		case time.Now().After(code.ExpiresAt):
+			return nil, fail(model.HandoverFailCodeExpired, model.ErrInvalidHandoverCode)
+		case code.Attempts >= handoverCodeMaxAttempts:
+			return nil, fail(model.HandoverFailCodeLocked, model.ErrInvalidHandoverCode)
+		}
+		if code.CodeHash != hashHandoverCode(order.ID, kind, req.Code) {
+			if err := s.handoverRepo.IncrementAttempts(ctx, code.ID); err != nil {
+				return nil, err
+			}
+			return nil, fail(model.HandoverFailWrongCode, model.ErrInvalidHandoverCode)
+		}
+		method = model.HandoverMethodCode
+		codeID = &code.ID
+	}
+
+	capturedAt := time.Now()
+	if req.CapturedAt != nil && !req.CapturedAt.IsZero() && req.CapturedAt.Before(capturedAt) {
+		capturedAt = *req.CapturedAt
+	}
+	distance := utils.CalculateDistance(req.Latitude, req.Longitude, order.PickupLatitude, order.PickupLongitude)
+
+	proof := &model.HandoverProof{
+		OrderID:     order.ID,
+		Kind:        kind,
+		PerformedBy: performedBy,
+		Method:      method,
+		Latitude:    req.Latitude,
+		Longitude:   req.Longitude,
+		Accuracy:    req.Accuracy,
+		DistanceKm:  math.Round(distance*100) / 100,
+		CapturedAt:  capturedAt,
+		Notes:       req.Notes,
+	}
+
+	var err error
+	if proof.PhotoObject, err = s.store(ctx, order.ID, kind, "photo", photo); err != nil {
+		return nil, err
+	}
+	if signature != nil {
+		if proof.SignatureObject, err = s.store(ctx, order.ID, kind, "signature", signature); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.handoverRepo.CreateProof(ctx, proof, codeID); err != nil {
+		return nil, err
+	}
+	response := s.toResponse(ctx, proof)
+	return &response, nil
+}
+
+// GetHistory retrieves the handovers of an order and the refused attempts
+func (s *HandoverService) GetHistory(ctx context.Context, orderID uuid.UUID) (*model.HandoverHistoryResponse, error) {
+	history := &model.HandoverHistoryResponse{
+		Proofs:   []model.HandoverProofResponse{},
+		Attempts: []*model.HandoverAttempt{},
+	}
+	if !s.handoverRepo.Available() {
+		return history, nil
+	}
+
+	proofs, err := s.handoverRepo.ListProofs(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, proof := range proofs {
+		history.Proofs = append(history.Proofs, s.toResponse(ctx, proof))
+	}
+
+	attempts, err := s.handoverRepo.ListAttempts(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	history.Attempts = attempts
+	return history, nil
+}
+
+// sendCode sends a handover code to the customer. The code releases the device, so it only
+// goes to a verified phone number; otherwise the customer gets it in the app only. Failures
+// are logged only; the customer can request the code again in the app.
+func (s *HandoverService) sendCode(ctx context.Context, order *model.ServiceOrder, kind model.HandoverKind, code string) {
+	customer, err := s.userRepo.GetByID(ctx, order.CustomerID)
+	if err != nil || customer.Phone == "" {
+		return
+	}
+	if customer.PhoneVerifiedAt == nil {
+		log.Printf("Not sending handover code for order %s: customer phone number is not verified", order.ID)
+		return
+	}
+
+	action := "hand your device to"
+	if kind == model.HandoverDelivery {
+		action = "receive your device from"
+	}
+	message := fmt.Sprintf(
+		"Your handover code for order %s is %s. Only give it to the courier when you %s them. Do not share this code with anyone else.",
+		order.OrderNumber, code, action,
+	)
+	if err := s.whatsAppService.Send(ctx, customer.Phone, message); err != nil {
+		log.Printf("Failed to send handover code for order %s: %v", order.ID, err)
+	}
+}
+
+// logAttempt records a refused handover. Failures are logged only.
+func (s *HandoverService) logAttempt(ctx context.Context, orderID uuid.UUID, kind model.HandoverKind, performedBy uuid.UUID, req *model.HandoverRequest, reason string) {
+	if err := s.handoverRepo.LogAttempt(ctx, &model.HandoverAttempt{
+		OrderID:     orderID,
+		Kind:        kind,
+		PerformedBy: performedBy,
+		Reason:      reason,
+		Latitude:    req.Latitude,
+		Longitude:   req.Longitude,
+	}); err != nil {
+		log.Printf("Failed to log handover attempt for order %s: %v", orderID, err)
+	}
+}
+
+// store uploads a handover image next to the other photos of the order
+func (s *HandoverService) store(ctx context.Context, orderID uuid.UUID, kind model.HandoverKind, name string, file *multipart.FileHeader) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	objectName := fmt.Sprintf("orders/%s/handover/%s/%s_%s%s",
+		orderID, kind, name, uuid.New().String(), strings.ToLower(filepath.Ext(file.Filename)))
+	if err := s.fileService.PutFile(ctx, objectName, src, file.Size, file.Header.Get("Content-Type")); err != nil {
+		return "", err
+	}
+	return objectName, nil
+}
+
+// toResponse adds temporary links to the evidence of a proof
+func (s *HandoverService) toResponse(ctx context.Context, proof *model.HandoverProof) model.HandoverProofResponse {
+	response := model.HandoverProofResponse{HandoverProof: *proof}
+	if s.fileService == nil {
+		return response
+	}
+	if url, err := s.fileService.GetFileURL(ctx, proof.PhotoObject, handoverPhotoURLExpiry); err == nil {
+		response.PhotoURL = url
+	}
+	if proof.SignatureObject != "" {
+		if url, err := s.fileService.GetFileURL(ctx, proof.SignatureObject, handoverPhotoURLExpiry); err == nil {
+			response.SignatureURL = url
+		}
+	}
+	return response
+}
+
+// isImageUpload checks the size and the actual content of an uploaded image
+func isImageUpload(file *multipart.FileHeader) bool {
+	if file.Size == 0 || file.Size > handoverMaxUploadSize {
+		return false
+	}
+	src, err := file.Open()
+	if err != nil {
+		return false
+	}
+	defer src.Close()
+
+	head := make([]byte, 512)
+	n, _ := src.Read(head)
+	return strings.HasPrefix(http.DetectContentType(head[:n]), "image/")
+}
+
+// hashHandoverCode binds a code to its order and handover before hashing
+func hashHandoverCode(orderID uuid.UUID, kind model.HandoverKind, code string) string {
+	return utils.SHA256Hex(orderID.String() + ":" + string(kind) + ":" + code)
+}
+
+// handoverCodeExpiry returns how long a handover code stays valid
+func handoverCodeExpiry() time.Duration {
+	if config.Config != nil && config.Config.HandoverCodeExpiry > 0 {
+		return config.Config.HandoverCodeExpiry
+	}
+	return 72 * time.Hour
+}
+
+// handoverProofRequired reports whether courier handovers can only be completed with proof
+func handoverProofRequired() bool {
+	if config.Config != nil {
+		return config.Config.HandoverProofRequired
+	}
+	return true
+}
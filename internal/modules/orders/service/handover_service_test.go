@@ -0,0 +1,147 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	mediaService "service/internal/modules/media/service"
+	"service/internal/modules/orders/repository"
+	userRepo "service/internal/modules/users/repository"
+	"service/internal/shared/database/dbtest"
+	"service/internal/shared/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockedHandoverService returns a handover service whose repositories run on sqlmock.
+// Its file service is never reached by refused handovers.
+func mockedHandoverService(t *testing.T) (*HandoverService, sqlmock.Sqlmock) {
+	t.Helper()
+	mock := dbtest.MockGlobal(t)
+	return &HandoverService{
+		handoverRepo: repository.NewHandoverRepository(),
+		userRepo:     userRepo.NewUserRepository(),
+		fileService:  &mediaService.FileService{},
+	}, mock
+}
+
+// handoverPhoto returns an uploaded PNG as the handler would receive it
+func handoverPhoto(t *testing.T) *multipart.FileHeader {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("photo", "device.png")
+	assert.NoError(t, err)
+	part.Write([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"))
+	assert.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	assert.NoError(t, err)
+	t.Cleanup(func() { form.RemoveAll() })
+	return form.File["photo"][0]
+}
+
+func TestVerifyHandoverCode(t *testing.T) {
+	order := &model.ServiceOrder{ID: uuid.New(), Status: model.StatusOnPickup}
+	courierID := uuid.New()
+	codeHash := hashHandoverCode(order.ID, model.HandoverPickup, "123456")
+	used := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		code      string
+		stored    *model.HandoverCode // nil when no code was issued
+		increment bool
+		reason    string
+		want      error
+	}{
+		{"neither code nor signature", "", nil, false, model.HandoverFailMissingProof, model.ErrHandoverProofMissing},
+		{"no code issued", "123456", nil, false, model.HandoverFailNoCode, model.ErrInvalidHandoverCode},
+		{"code already used", "123456", &model.HandoverCode{CodeHash: codeHash, ExpiresAt: time.Now().Add(time.Hour), UsedAt: &used}, false, model.HandoverFailNoCode, model.ErrInvalidHandoverCode},
+		{"code expired", "123456", &model.HandoverCode{CodeHash: codeHash, ExpiresAt: time.Now().Add(-time.Minute)}, false, model.HandoverFailCodeExpired, model.ErrInvalidHandoverCode},
+		{"wrong code counts an attempt", "654321", &model.HandoverCode{CodeHash: codeHash, ExpiresAt: time.Now().Add(time.Hour), Attempts: handoverCodeMaxAttempts - 1}, true, model.HandoverFailWrongCode, model.ErrInvalidHandoverCode},
+		{"locked after too many wrong codes", "123456", &model.HandoverCode{CodeHash: codeHash, ExpiresAt: time.Now().Add(time.Hour), Attempts: handoverCodeMaxAttempts}, false, model.HandoverFailCodeLocked, model.ErrInvalidHandoverCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := mockedHandoverService(t)
+			codeID := uuid.New()
+
+			if tt.code != "" {
+				rows := sqlmock.NewRows([]string{"id", "order_id", "kind", "code_hash", "expires_at", "attempts", "used_at"})
+				if tt.stored != nil {
+					rows.AddRow(codeID, order.ID, model.HandoverPickup, tt.stored.CodeHash, tt.stored.ExpiresAt, tt.stored.Attempts, tt.stored.UsedAt)
+				}
+				mock.ExpectQuery(`SELECT \* FROM "handover_codes" WHERE order_id = \$1 AND kind = \$2`).
+					WithArgs(order.ID, model.HandoverPickup, 1).
+					WillReturnRows(rows)
+			}
+			if tt.increment {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "handover_codes" SET "attempts"=attempts \+ 1 WHERE id = \$1`).
+					WithArgs(codeID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			}
+			mock.ExpectBegin()
+			mock.ExpectQuery(`INSERT INTO "handover_attempts"`).
+				WithArgs(order.ID, model.HandoverPickup, courierID, tt.reason, -6.2, 106.8, sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+			mock.ExpectCommit()
+
+			req := &model.HandoverRequest{Code: tt.code, Latitude: -6.2, Longitude: 106.8}
+			proof, err := s.Verify(context.Background(), order, model.HandoverPickup, courierID, req, handoverPhoto(t), nil)
+			assert.Equal(t, tt.want, err)
+			assert.Nil(t, proof)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestIssueHandoverCodeThrottle(t *testing.T) {
+	used := time.Now()
+
+	tests := []struct {
+		name     string
+		existing *model.HandoverCode
+		want     error
+	}{
+		{"first code", nil, nil},
+		{"unused code issued just now", &model.HandoverCode{CreatedAt: time.Now().Add(-10 * time.Second)}, model.ErrHandoverCodeThrottled},
+		{"unused code issued a while ago", &model.HandoverCode{CreatedAt: time.Now().Add(-handoverResendInterval)}, nil},
+		{"code used just now", &model.HandoverCode{CreatedAt: time.Now().Add(-10 * time.Second), UsedAt: &used}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := mockedHandoverService(t)
+			order := &model.ServiceOrder{ID: uuid.New(), CustomerID: uuid.New()}
+
+			rows := sqlmock.NewRows([]string{"id", "order_id", "kind", "created_at", "used_at"})
+			if tt.existing != nil {
+				rows.AddRow(uuid.New(), order.ID, model.HandoverDelivery, tt.existing.CreatedAt, tt.existing.UsedAt)
+			}
+			mock.ExpectQuery(`SELECT \* FROM "handover_codes"`).WillReturnRows(rows)
+			if tt.want == nil {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO "handover_codes" .* ON CONFLICT \("order_id","kind"\) DO UPDATE SET .*"attempts"="excluded"."attempts","used_at"="excluded"."used_at"`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+				mock.ExpectCommit()
+				// The customer is not found, so the code is only shown in the app
+				mock.ExpectQuery(`SELECT \* FROM "users"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			}
+
+			response, err := s.IssueCode(context.Background(), order, model.HandoverDelivery)
+			assert.Equal(t, tt.want, err)
+			if tt.want == nil {
+				assert.Len(t, response.Code, handoverCodeLength)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
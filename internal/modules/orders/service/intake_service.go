@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"log"
+	branchRepo "service/internal/modules/branches/repository"
+	"service/internal/modules/orders/repository"
+	userRepo "service/internal/modules/users/repository"
+	"service/internal/shared/config"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IntakeService handles intake checklists, the condition of devices recorded at intake
+// and the job tickets that carry it
+type IntakeService struct {
+	intakeRepo *repository.IntakeRepository
+	orderRepo  *repository.ServiceOrderRepository
+	userRepo   *userRepo.UserRepository
+	branchRepo *branchRepo.BranchRepository
+}
+
+// NewIntakeService creates a new intake service
+func NewIntakeService() *IntakeService {
+	return &IntakeService{
+		intakeRepo: repository.NewIntakeRepository(),
+		orderRepo:  repository.NewServiceOrderRepository(),
+		userRepo:   userRepo.NewUserRepository(),
+		branchRepo: branchRepo.NewBranchRepository(),
+	}
+}
+
+// ListChecklists returns the configured checklists. The built-in default checklist is
+// included until admins configure their own.
+func (s *IntakeService) ListChecklists(ctx context.Context) ([]*model.IntakeChecklist, error) {
+	checklists := []*model.IntakeChecklist{}
+	if s.intakeRepo.Available() {
+		configured, err := s.intakeRepo.ListChecklists(ctx)
+		if err != nil {
+			return nil, err
+		}
+		checklists = append(checklists, configured...)
+	}
+
+	for _, checklist := range checklists {
+		if checklist.ServiceType == model.IntakeDefaultChecklist {
+			return checklists, nil
+		}
+	}
+	return append(checklists, defaultChecklist()), nil
+}
+
+// SaveChecklist replaces the checklist of a service type, or the default checklist
+func (s *IntakeService) SaveChecklist(ctx context.Context, userID uuid.UUID, serviceType string, req *model.IntakeChecklistRequest) (*model.IntakeChecklist, error) {
+	if !isChecklistType(serviceType) {
+		return nil, model.ErrInvalidChecklistType
+	}
+
+	checklist := &model.IntakeChecklist{
+		ServiceType: serviceType,
+		Name:        req.Name,
+		UpdatedBy:   userID,
+	}
+	seen := make(map[string]bool, len(req.Items))
+	for i, item := range req.Items {
+		if seen[item.Key] {
+			return nil, model.ErrDuplicateChecklistItem
+		}
+		seen[item.Key] = true
+		checklist.Items = append(checklist.Items, model.IntakeChecklistItem{
+			Key:      item.Key,
+			Label:    item.Label,
+			Kind:     item.Kind,
+			Required: item.Required,
+			Position: i,
+		})
+	}
+
+	if s.intakeRepo.Available() {
+		if err := s.intakeRepo.SaveChecklist(ctx, checklist); err != nil {
+			return nil, err
+		}
+	}
+	return checklist, nil
+}
+
+// GetIntake returns the intake recorded for an order, or the checklist to fill out when
+// there is none yet. Customers can only see the intake of their own orders.
+func (s *IntakeService) GetIntake(ctx context.Context, orderID, userID uuid.UUID, role model.UserRole) (*model.OrderIntakeResponse, error) {
+	order, err := s.accessibleOrder(ctx, orderID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if intake := s.findIntake(ctx, order.ID); intake != nil {
+		return &model.OrderIntakeResponse{Intake: intake}, nil
+	}
+	return &model.OrderIntakeResponse{Checklist: s.checklistFor(ctx, order.ServiceType)}, nil
+}
+
+// FillIntake records the condition of the device against the checklist of the order's
+// service type. It is done by the courier at pickup or the cashier at the counter, and
+// can be corrected until the customer acknowledged it.
+func (s *IntakeService) FillIntake(ctx context.Context, orderID, userID uuid.UUID, role model.UserRole, req *model.OrderIntakeRequest) (*model.OrderIntake, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+	switch role {
+	case model.RolePelanggan, model.RoleTeknisi:
+		return nil, model.ErrForbidden
+	case model.RoleKurir:
+		if order.CourierID == nil || *order.CourierID != userID {
+			return nil, model.ErrNotOrderCourier
+		}
+	}
+	switch order.Status {
+	case model.StatusPendingPickup, model.StatusOnPickup, model.StatusInService:
+	default:
+		return nil, model.ErrIntakeNotOpen
+	}
+
+	intake := s.findIntake(ctx, order.ID)
+	if intake == nil {
+		intake = &model.OrderIntake{OrderID: order.ID}
+	} else if intake.AcknowledgedAt != nil {
+		return nil, model.ErrIntakeAcknowledged
+	}
+
+	checklist := s.checklistFor(ctx, order.ServiceType)
+	items, err := intakeItems(checklist, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	intake.ChecklistName = checklist.Name
+	intake.Notes = req.Notes
+	intake.FilledBy = userID
+	intake.FilledAt = time.Now()
+	intake.Items = items
+	if s.intakeRepo.Available() {
+		if err := s.intakeRepo.SaveIntake(ctx, intake); err != nil {
+			return nil, err
+		}
+	}
+	return intake, nil
+}
+
+// AcknowledgeIntake records that the customer agrees with the recorded condition of their
+// device. A comment can be left when they disagree with part of it.
+func (s *IntakeService) AcknowledgeIntake(ctx context.Context, orderID, customerID uuid.UUID, req *model.AcknowledgeIntakeRequest) (*model.OrderIntake, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil || order.CustomerID != customerID {
+		return nil, model.ErrOrderNotFound
+	}
+
+	intake := s.findIntake(ctx, order.ID)
+	if intake == nil {
+		return nil, model.ErrIntakeNotFound
+	}
+	if intake.AcknowledgedAt != nil {
+		return nil, model.ErrIntakeAcknowledged
+	}
+
+	now := time.Now()
+	intake.AcknowledgedAt = &now
+	intake.CustomerComment = req.Comment
+	if err := s.intakeRepo.Acknowledge(ctx, intake); err != nil {
+		return nil, err
+	}
+	return intake, nil
+}
+
+// JobTicket gathers what is printed on the ticket that travels with the device: the order,
+// customer and device, the condition recorded at intake and the public tracking QR code
+func (s *IntakeService) JobTicket(ctx context.Context, orderID uuid.UUID) (*model.JobTicket, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+
+	ticket := &model.JobTicket{
+		OrderNumber:   order.OrderNumber,
+		CreatedAt:     order.CreatedAt,
+		Status:        order.Status,
+		IPhoneModel:   order.IPhoneModel,
+		IPhoneColor:   order.IPhoneColor,
+		IPhoneIMEI:    order.IPhoneIMEI,
+		ServiceType:   order.ServiceType,
+		Description:   order.Description,
+		EstimatedCost: order.EstimatedCost,
+		Intake:        s.findIntake(ctx, order.ID),
+		PrintedAt:     time.Now(),
+	}
+	if customer, err := s.userRepo.GetByID(ctx, order.CustomerID); err == nil {
+		ticket.CustomerName = customer.FullName
+		ticket.CustomerPhone = customer.Phone
+	}
+	if branch, err := s.branchRepo.GetByID(ctx, order.BranchID); err == nil {
+		ticket.BranchName = branch.Name
+		ticket.BranchAddress = branch.Address
+		ticket.BranchPhone = branch.Phone
+	}
+	if config.Config != nil {
+		if png, err := utils.GenerateQRCodeForOrder(order.OrderNumber); err == nil {
+			ticket.TrackingQR = "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+		} else {
+			log.Printf("Failed to generate tracking QR code for order %s: %v", order.ID, err)
+		}
+	}
+	return ticket, nil
+}
+
+// accessibleOrder retrieves an order the caller may see; customers only see their own
+func (s *IntakeService) accessibleOrder(ctx context.Context, orderID, userID uuid.UUID, role model.UserRole) (*model.ServiceOrder, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+	if role == model.RolePelanggan && order.CustomerID != userID {
+		return nil, model.ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// findIntake returns the intake of an order, or nil when none was recorded
+func (s *IntakeService) findIntake(ctx context.Context, orderID uuid.UUID) *model.OrderIntake {
+	if !s.intakeRepo.Available() {
+		return nil
+	}
+	intake, err := s.intakeRepo.GetByOrder(ctx, orderID)
+	if err != nil {
+		return nil
+	}
+	return intake
+}
+
+// checklistFor returns the checklist of a service type, falling back to the configured
+// default checklist and then to the built-in one
+func (s *IntakeService) checklistFor(ctx context.Context, serviceType model.ServiceType) *model.IntakeChecklist {
+	if s.intakeRepo.Available() {
+		for _, key := range []string{string(serviceType), model.IntakeDefaultChecklist} {
+			if checklist, err := s.intakeRepo.GetChecklist(ctx, key); err == nil {
+				return checklist
+			}
+		}
+	}
+	return defaultChecklist()
+}
+
+// defaultChecklist returns the built-in checklist
+func defaultChecklist() *model.IntakeChecklist {
+	checklist := &model.IntakeChecklist{
+		ServiceType: model.IntakeDefaultChecklist,
+		Name:        "Standard intake",
+		Items:       make([]model.IntakeChecklistItem, len(model.DefaultIntakeItems)),
+	}
+	copy(checklist.Items, model.DefaultIntakeItems)
+	for i := range checklist.Items {
+		checklist.Items[i].Position = i
+	}
+	return checklist
+}
+
+// intakeItems checks the submitted results against the checklist and returns them in
+// checklist order
+func intakeItems(checklist *model.IntakeChecklist, results []model.IntakeItemRequest) ([]model.OrderIntakeItem, error) {
+	byKey := make(map[string]model.IntakeItemRequest, len(results))
+	for _, result := range results {
+		byKey[result.Key] = result
+	}
+
+	items := make([]model.OrderIntakeItem, 0, len(results))
+	for _, item := range checklist.Items {
+		result, ok := byKey[item.Key]
+		if !ok {
+			if item.Required {
+				return nil, model.ErrIntakeItemMissing
+			}
+			continue
+		}
+		if !item.Kind.Accepts(result.Result) {
+			return nil, model.ErrIntakeInvalidResult
+		}
+		delete(byKey, item.Key)
+		items = append(items, model.OrderIntakeItem{
+			Key:      item.Key,
+			Label:    item.Label,
+			Kind:     item.Kind,
+			Result:   result.Result,
+			Note:     result.Note,
+			Position: item.Position,
+		})
+	}
+	if len(byKey) > 0 {
+		return nil, model.ErrIntakeItemUnknown
+	}
+	return items, nil
+}
+
+// isChecklistType reports whether a checklist can be configured under the key
+func isChecklistType(serviceType string) bool {
+	switch model.ServiceType(serviceType) {
+	case model.ServiceTypeScreenRepair, model.ServiceTypeBatteryReplacement, model.ServiceTypeWaterDamage,
+		model.ServiceTypeSoftwareIssue, model.ServiceTypeHardwareRepair, model.ServiceTypeOther:
+		return true
+	}
+	return serviceType == model.IntakeDefaultChecklist
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"path/filepath"
+	mediaService "service/internal/modules/media/service"
+	"service/internal/modules/orders/repository"
+	"service/internal/shared/config"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// mediaMaxFilesPerUpload caps how many photos can be sent in one request
+	mediaMaxFilesPerUpload = 20
+	// mediaExifScanSize is how much of a photo is read to find its EXIF data
+	mediaExifScanSize = 128 << 10
+	// mediaURLExpiry is how long links to order photos stay valid
+	mediaURLExpiry = 15 * time.Minute
+)
+
+// MediaService handles the photo galleries of orders
+type MediaService struct {
+	orderRepo   *repository.ServiceOrderRepository
+	mediaRepo   *repository.MediaRepository
+	fileService *mediaService.FileService
+}
+
+// NewMediaService creates a new media service
+func NewMediaService() *MediaService {
+	var fileService *mediaService.FileService
+	if config.Config != nil {
+		var err error
+		fileService, err = mediaService.NewFileService()
+		if err != nil {
+			// Galleries can still be listed; links and uploads need storage
+			log.Printf("Failed to initialize file service for order media: %v", err)
+			fileService = nil
+		}
+	}
+	return &MediaService{
+		orderRepo:   repository.NewServiceOrderRepository(),
+		mediaRepo:   repository.NewMediaRepository(),
+		fileService: fileService,
+	}
+}
+
+// Upload adds photos to a stage of an order's gallery, after the photos already there.
+// Every file is checked before anything is stored. The time each photo was taken is read
+// from its EXIF data when present.
+func (s *MediaService) Upload(ctx context.Context, orderID, uploadedBy uuid.UUID, req *model.UploadOrderMediaRequest, files []*multipart.FileHeader) ([]model.OrderMediaResponse, error) {
+	if !s.mediaRepo.Available() || s.fileService == nil {
+		return nil, model.ErrMediaStorageDisabled
+	}
+	if _, err := s.orderRepo.GetByID(ctx, orderID); err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+	if len(files) == 0 {
+		return nil, model.ErrMediaFilesRequired
+	}
+	if len(files) > mediaMaxFilesPerUpload {
+		return nil, model.ErrMediaTooManyFiles
+	}
+	for _, file := range files {
+		if !isImageUpload(file) {
+			return nil, model.ErrMediaInvalidImage
+		}
+	}
+
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = model.MediaVisibilityInternal
+	}
+	position, err := s.mediaRepo.NextPosition(ctx, orderID, req.Stage)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.OrderMediaResponse, 0, len(files))
+	for i, file := range files {
+		media := &model.OrderMedia{
+			OrderID:     orderID,
+			Stage:       req.Stage,
+			ContentType: file.Header.Get("Content-Type"),
+			Size:        file.Size,
+			Caption:     req.Caption,
+			Visibility:  visibility,
+			Position:    position + i,
+			UploadedBy:  uploadedBy,
+		}
+		if err := s.store(ctx, media, file); err != nil {
+			return nil, err
+		}
+		if err := s.mediaRepo.Create(ctx, media); err != nil {
+			return nil, err
+		}
+		responses = append(responses, s.toResponse(ctx, media))
+	}
+	return responses, nil
+}
+
+// List returns the gallery of an order, optionally of one stage. Customers only see the
+// photos of their own orders that are marked customer-visible.
+func (s *MediaService) List(ctx context.Context, orderID, userID uuid.UUID, role model.UserRole, stage *model.MediaStage) ([]model.OrderMediaResponse, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+
+	var visibility *model.MediaVisibility
+	if role == model.RolePelanggan {
+		if order.CustomerID != userID {
+			return nil, model.ErrOrderNotFound
+		}
+		customerVisible := model.MediaVisibilityCustomer
+		visibility = &customerVisible
+	}
+
+	responses := []model.OrderMediaResponse{}
+	if !s.mediaRepo.Available() {
+		return responses, nil
+	}
+	media, err := s.mediaRepo.ListByOrder(ctx, orderID, stage, visibility)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range media {
+		responses = append(responses, s.toResponse(ctx, m))
+	}
+	return responses, nil
+}
+
+// Update changes the caption or visibility of a photo
+func (s *MediaService) Update(ctx context.Context, orderID, mediaID uuid.UUID, req *model.UpdateOrderMediaRequest) (*model.OrderMediaResponse, error) {
+	media, err := s.get(ctx, orderID, mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Caption != nil {
+		media.Caption = *req.Caption
+	}
+	if req.Visibility != nil {
+		media.Visibility = *req.Visibility
+	}
+	if err := s.mediaRepo.Update(ctx, media); err != nil {
+		return nil, err
+	}
+
+	response := s.toResponse(ctx, media)
+	return &response, nil
+}
+
+// Reorder puts the listed photos of a stage first, in the given order. Photos of the stage
+// that are not listed keep their relative order after them.
+func (s *MediaService) Reorder(ctx context.Context, orderID uuid.UUID, req *model.ReorderOrderMediaRequest) ([]model.OrderMediaResponse, error) {
+	if !s.mediaRepo.Available() {
+		return nil, model.ErrMediaStorageDisabled
+	}
+	if _, err := s.orderRepo.GetByID(ctx, orderID); err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+
+	listed := make(map[uuid.UUID]bool, len(req.MediaIDs))
+	ids := make([]uuid.UUID, 0, len(req.MediaIDs))
+	for _, idStr := range req.MediaIDs {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			return nil, model.ErrMediaNotFound
+		}
+		if !listed[id] {
+			listed[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	current, err := s.mediaRepo.ListByOrder(ctx, orderID, &req.Stage, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range current {
+		if !listed[m.ID] {
+			ids = append(ids, m.ID)
+		}
+	}
+
+	if err := s.mediaRepo.Reorder(ctx, orderID, req.Stage, ids); err != nil {
+		return nil, err
+	}
+	return s.List(ctx, orderID, uuid.Nil, "", &req.Stage)
+}
+
+// Delete removes a photo from the gallery. The stored file is kept, as photos can be
+// evidence in disputes.
+func (s *MediaService) Delete(ctx context.Context, orderID, mediaID uuid.UUID) error {
+	media, err := s.get(ctx, orderID, mediaID)
+	if err != nil {
+		return err
+	}
+	return s.mediaRepo.Delete(ctx, media)
+}
+
+// get retrieves a photo of an order the caller can access
+func (s *MediaService) get(ctx context.Context, orderID, mediaID uuid.UUID) (*model.OrderMedia, error) {
+	if !s.mediaRepo.Available() {
+		return nil, model.ErrMediaNotFound
+	}
+	if _, err := s.orderRepo.GetByID(ctx, orderID); err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+	media, err := s.mediaRepo.Get(ctx, orderID, mediaID)
+	if err != nil {
+		return nil, model.ErrMediaNotFound
+	}
+	return media, nil
+}
+
+// store reads the photo's EXIF date and uploads it to the order's media folder
+func (s *MediaService) store(ctx context.Context, media *model.OrderMedia, file *multipart.FileHeader) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	head := make([]byte, mediaExifScanSize)
+	n, _ := io.ReadFull(src, head)
+	if taken, ok := utils.ExifDateTaken(head[:n]); ok {
+		media.TakenAt = &taken
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	media.ObjectName = fmt.Sprintf("orders/%s/media/%s/%s%s",
+		media.OrderID, media.Stage, uuid.New().String(), strings.ToLower(filepath.Ext(file.Filename)))
+	return s.fileService.PutFile(ctx, media.ObjectName, src, file.Size, media.ContentType)
+}
+
+// toResponse adds a temporary link to a photo
+func (s *MediaService) toResponse(ctx context.Context, media *model.OrderMedia) model.OrderMediaResponse {
+	response := model.OrderMediaResponse{OrderMedia: *media}
+	if s.fileService == nil {
+		return response
+	}
+	if url, err := s.fileService.GetFileURL(ctx, media.ObjectName, mediaURLExpiry); err == nil {
+		response.URL = url
+	}
+	return response
+}
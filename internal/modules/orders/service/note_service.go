@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	notificationService "service/internal/modules/notification/service"
+	"service/internal/modules/orders/repository"
+	userRepo "service/internal/modules/users/repository"
+	"service/internal/shared/model"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// mentionPreviewLength is how much of a note is shown in a mention notification
+const mentionPreviewLength = 100
+
+// NoteService handles the internal notes thread of orders and the staff view of an
+// order's history
+type NoteService struct {
+	noteRepo  *repository.NoteRepository
+	orderRepo *repository.ServiceOrderRepository
+	eventRepo *repository.StatusEventRepository
+	userRepo  *userRepo.UserRepository
+
+	notificationService *notificationService.NotificationService
+}
+
+// NewNoteService creates a new note service
+func NewNoteService() *NoteService {
+	return &NoteService{
+		noteRepo:  repository.NewNoteRepository(),
+		orderRepo: repository.NewServiceOrderRepository(),
+		eventRepo: repository.NewStatusEventRepository(),
+		userRepo:  userRepo.NewUserRepository(),
+
+		notificationService: notificationService.NewNotificationService(),
+	}
+}
+
+// AddNote adds a note to the order's internal thread and notifies the staff mentioned in it
+func (s *NoteService) AddNote(ctx context.Context, orderID, userID uuid.UUID, role model.UserRole, req *model.OrderNoteRequest) (*model.OrderNote, error) {
+	order, err := s.staffOrder(ctx, orderID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if !s.noteRepo.Available() {
+		return nil, errors.New("order notes are not available")
+	}
+	author, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, model.ErrUserNotFound
+	}
+
+	note := &model.OrderNote{
+		OrderID:    order.ID,
+		AuthorID:   author.ID,
+		AuthorName: author.FullName,
+		AuthorRole: role,
+		Body:       req.Body,
+		Mentions:   []model.OrderNoteMention{},
+	}
+	seen := make(map[uuid.UUID]bool, len(req.MentionIDs))
+	for _, idStr := range req.MentionIDs {
+		id, err := uuid.Parse(idStr)
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		staff, err := s.userRepo.GetByID(ctx, id)
+		if err != nil || !canBeMentioned(staff, order) {
+			return nil, model.ErrNoteMentionInvalid
+		}
+		note.Mentions = append(note.Mentions, model.OrderNoteMention{UserID: staff.ID, Name: staff.FullName})
+	}
+
+	if err := s.noteRepo.Create(ctx, note); err != nil {
+		return nil, err
+	}
+	s.notifyMentions(ctx, order, note)
+	return note, nil
+}
+
+// ListNotes retrieves the internal notes of an order, pinned notes first
+func (s *NoteService) ListNotes(ctx context.Context, orderID, userID uuid.UUID, role model.UserRole) ([]*model.OrderNote, error) {
+	order, err := s.staffOrder(ctx, orderID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := s.notes(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		return notes[i].Pinned && !notes[j].Pinned
+	})
+	return notes, nil
+}
+
+// PinNote pins a note to the top of the order's thread, or unpins it
+func (s *NoteService) PinNote(ctx context.Context, orderID, noteID, userID uuid.UUID, role model.UserRole, pinned bool) (*model.OrderNote, error) {
+	order, err := s.staffOrder(ctx, orderID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if !s.noteRepo.Available() {
+		return nil, model.ErrNoteNotFound
+	}
+
+	note, err := s.noteRepo.Get(ctx, order.ID, noteID)
+	if err != nil {
+		return nil, model.ErrNoteNotFound
+	}
+	if note.Pinned == pinned {
+		return note, nil
+	}
+	if err := s.noteRepo.SetPinned(ctx, note, pinned, userID); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// History retrieves the status changes and internal notes of an order in one timeline
+func (s *NoteService) History(ctx context.Context, orderID, userID uuid.UUID, role model.UserRole) (*model.OrderHistoryResponse, error) {
+	order, err := s.staffOrder(ctx, orderID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &model.OrderHistoryResponse{
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		Status:      order.Status,
+		Pinned:      []*model.OrderNote{},
+		Entries:     []model.OrderHistoryEntry{},
+	}
+
+	if s.eventRepo.Available() {
+		events, err := s.eventRepo.ListByOrder(ctx, order.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			history.Entries = append(history.Entries, model.OrderHistoryEntry{
+				Kind:   model.OrderHistoryStatus,
+				At:     event.CreatedAt,
+				Status: event.Status,
+			})
+		}
+	}
+
+	notes, err := s.notes(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, note := range notes {
+		if note.Pinned {
+			history.Pinned = append(history.Pinned, note)
+		}
+		history.Entries = append(history.Entries, model.OrderHistoryEntry{
+			Kind: model.OrderHistoryNote,
+			At:   note.CreatedAt,
+			Note: note,
+		})
+	}
+
+	sort.SliceStable(history.Entries, func(i, j int) bool {
+		return history.Entries[i].At.Before(history.Entries[j].At)
+	})
+	return history, nil
+}
+
+// staffOrder retrieves an order whose notes the caller may see. Customers never see
+// notes; technicians and couriers only see the notes of orders assigned to them.
+func (s *NoteService) staffOrder(ctx context.Context, orderID, userID uuid.UUID, role model.UserRole) (*model.ServiceOrder, error) {
+	if role == model.RolePelanggan {
+		return nil, model.ErrForbidden
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+	switch role {
+	case model.RoleTeknisi:
+		if order.TechnicianID == nil || *order.TechnicianID != userID {
+			return nil, model.ErrForbidden
+		}
+	case model.RoleKurir:
+		if order.CourierID == nil || *order.CourierID != userID {
+			return nil, model.ErrForbidden
+		}
+	}
+	return order, nil
+}
+
+// notes returns the notes of an order, or none when notes are not stored
+func (s *NoteService) notes(ctx context.Context, orderID uuid.UUID) ([]*model.OrderNote, error) {
+	if !s.noteRepo.Available() {
+		return []*model.OrderNote{}, nil
+	}
+	return s.noteRepo.ListByOrder(ctx, orderID)
+}
+
+// notifyMentions sends a push notification to every staff member mentioned in a note,
+// except the author. Failures are logged only.
+func (s *NoteService) notifyMentions(ctx context.Context, order *model.ServiceOrder, note *model.OrderNote) {
+	preview := []rune(note.Body)
+	if len(preview) > mentionPreviewLength {
+		preview = append(preview[:mentionPreviewLength], '…')
+	}
+	message := fmt.Sprintf("%s mentioned you on order %s: %s", note.AuthorName, order.OrderNumber, string(preview))
+
+	orderID := order.ID.String()
+	for _, mention := range note.Mentions {
+		if mention.UserID == note.AuthorID {
+			continue
+		}
+		if _, err := s.notificationService.SendNotification(ctx, &model.NotificationRequest{
+			UserID:  mention.UserID.String(),
+			OrderID: &orderID,
+			Type:    model.NotificationTypePush,
+			Title:   "Mentioned in Order Notes",
+			Message: message,
+		}); err != nil {
+			log.Printf("Failed to notify mention on order %s: %v", order.ID, err)
+		}
+	}
+}
+
+// canBeMentioned reports whether a user is active staff who works on the order's branch
+// or is assigned to the order. Head office admins can be mentioned on any order.
+func canBeMentioned(user *model.User, order *model.ServiceOrder) bool {
+	if !user.IsActive || user.Role == model.RolePelanggan {
+		return false
+	}
+	if user.Role == model.RoleAdminPusat {
+		return true
+	}
+	if (order.TechnicianID != nil && *order.TechnicianID == user.ID) || (order.CourierID != nil && *order.CourierID == user.ID) {
+		return true
+	}
+	return user.BranchID != nil && *user.BranchID == order.BranchID
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	notificationService "service/internal/modules/notification/service"
+	"service/internal/modules/orders/repository"
+	userRepo "service/internal/modules/users/repository"
+	"service/internal/shared/database/dbtest"
+	"service/internal/shared/model"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanBeMentioned(t *testing.T) {
+	branchID := uuid.New()
+	otherBranchID := uuid.New()
+	technicianID := uuid.New()
+	order := &model.ServiceOrder{ID: uuid.New(), BranchID: branchID, TechnicianID: &technicianID}
+
+	tests := []struct {
+		name string
+		user *model.User
+		want bool
+	}{
+		{"staff of the order's branch", &model.User{ID: uuid.New(), Role: model.RoleKasir, IsActive: true, BranchID: &branchID}, true},
+		{"staff of another branch", &model.User{ID: uuid.New(), Role: model.RoleKasir, IsActive: true, BranchID: &otherBranchID}, false},
+		{"assigned technician of another branch", &model.User{ID: technicianID, Role: model.RoleTeknisi, IsActive: true, BranchID: &otherBranchID}, true},
+		{"head office admin", &model.User{ID: uuid.New(), Role: model.RoleAdminPusat, IsActive: true}, true},
+		{"inactive staff", &model.User{ID: uuid.New(), Role: model.RoleKasir, IsActive: false, BranchID: &branchID}, false},
+		{"customer", &model.User{ID: uuid.New(), Role: model.RolePelanggan, IsActive: true, BranchID: &branchID}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, canBeMentioned(tt.user, order))
+		})
+	}
+}
+
+func TestStaffOrderAccess(t *testing.T) {
+	ctx := context.Background()
+	s := &NoteService{orderRepo: repository.NewServiceOrderRepository()}
+	technicianID := uuid.New()
+	courierID := uuid.New()
+	order := &model.ServiceOrder{ID: uuid.New(), BranchID: uuid.New(), TechnicianID: &technicianID, CourierID: &courierID}
+	assert.NoError(t, s.orderRepo.Create(ctx, order))
+
+	tests := []struct {
+		name    string
+		orderID uuid.UUID
+		userID  uuid.UUID
+		role    model.UserRole
+		want    error
+	}{
+		{"customer", order.ID, order.CustomerID, model.RolePelanggan, model.ErrForbidden},
+		{"cashier", order.ID, uuid.New(), model.RoleKasir, nil},
+		{"assigned technician", order.ID, technicianID, model.RoleTeknisi, nil},
+		{"other technician", order.ID, uuid.New(), model.RoleTeknisi, model.ErrForbidden},
+		{"assigned courier", order.ID, courierID, model.RoleKurir, nil},
+		{"other courier", order.ID, uuid.New(), model.RoleKurir, model.ErrForbidden},
+		{"unknown order", uuid.New(), uuid.New(), model.RoleKasir, model.ErrOrderNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.staffOrder(ctx, tt.orderID, tt.userID, tt.role)
+			assert.Equal(t, tt.want, err)
+		})
+	}
+}
+
+func TestAddNoteMentions(t *testing.T) {
+	userColumns := []string{"id", "full_name", "role", "is_active"}
+	authorID := uuid.New()
+	technicianID := uuid.New()
+	customerID := uuid.New()
+
+	// expectUser answers one user lookup by ID
+	expectUser := func(mock sqlmock.Sqlmock, id uuid.UUID, name string, role model.UserRole) {
+		mock.ExpectQuery(`SELECT \* FROM "users" WHERE id = \$1`).
+			WithArgs(id, 1).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(id, name, role, true))
+	}
+
+	t.Run("customers cannot be mentioned", func(t *testing.T) {
+		s, mock, order := mockedNoteService(t, technicianID)
+		expectUser(mock, authorID, "Rina", model.RoleAdminPusat)
+		expectUser(mock, customerID, "Customer", model.RolePelanggan)
+
+		note, err := s.AddNote(context.Background(), order.ID, authorID, model.RoleAdminPusat, &model.OrderNoteRequest{
+			Body:       "Please call the customer",
+			MentionIDs: []string{customerID.String()},
+		})
+		assert.Equal(t, model.ErrNoteMentionInvalid, err)
+		assert.Nil(t, note)
+		assert.NoError(t, mock.ExpectationsWereMet(), "nothing is stored")
+	})
+
+	t.Run("mentions are deduplicated and the author is not notified", func(t *testing.T) {
+		s, mock, order := mockedNoteService(t, technicianID)
+		expectUser(mock, authorID, "Rina", model.RoleAdminPusat)
+		expectUser(mock, technicianID, "Tono", model.RoleTeknisi)
+		expectUser(mock, authorID, "Rina", model.RoleAdminPusat)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "order_notes"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+		mock.ExpectQuery(`INSERT INTO "order_note_mentions"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()).AddRow(uuid.New()))
+		mock.ExpectCommit()
+		// Only the technician is looked up for a notification; it fails and is logged
+		mock.ExpectQuery(`SELECT \* FROM "users" WHERE id = \$1`).
+			WithArgs(technicianID, 1).
+			WillReturnRows(sqlmock.NewRows(userColumns))
+
+		note, err := s.AddNote(context.Background(), order.ID, authorID, model.RoleAdminPusat, &model.OrderNoteRequest{
+			Body:       "Screen ordered, waiting for delivery",
+			MentionIDs: []string{technicianID.String(), technicianID.String(), "not-a-user", authorID.String()},
+		})
+		assert.NoError(t, err)
+		var mentioned []string
+		for _, mention := range note.Mentions {
+			mentioned = append(mentioned, mention.Name)
+		}
+		assert.Equal(t, []string{"Tono", "Rina"}, mentioned)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// mockedNoteService returns a note service over an in-memory order assigned to the
+// technician, with users, notes and notifications on sqlmock
+func mockedNoteService(t *testing.T, technicianID uuid.UUID) (*NoteService, sqlmock.Sqlmock, *model.ServiceOrder) {
+	t.Helper()
+	orders := repository.NewServiceOrderRepository()
+	order := &model.ServiceOrder{ID: uuid.New(), OrderNumber: "ORD-1", BranchID: uuid.New(), TechnicianID: &technicianID}
+	assert.NoError(t, orders.Create(context.Background(), order))
+
+	mock := dbtest.MockGlobal(t)
+	return &NoteService{
+		noteRepo:            repository.NewNoteRepository(),
+		orderRepo:           orders,
+		userRepo:            userRepo.NewUserRepository(),
+		notificationService: notificationService.NewNotificationService(),
+	}, mock, order
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	inventoryRepo "service/internal/modules/inventory/repository"
+	"service/internal/modules/orders/repository"
+	"service/internal/shared/model"
+
+	"github.com/google/uuid"
+)
+
+// OrderPartService records the spare parts installed in devices during service
+type OrderPartService struct {
+	partRepo      *repository.OrderPartRepository
+	orderRepo     *repository.ServiceOrderRepository
+	sparePartRepo *inventoryRepo.SparePartInventoryRepository
+}
+
+// NewOrderPartService creates a new order part service
+func NewOrderPartService() *OrderPartService {
+	return &OrderPartService{
+		partRepo:      repository.NewOrderPartRepository(),
+		orderRepo:     repository.NewServiceOrderRepository(),
+		sparePartRepo: inventoryRepo.NewSparePartInventoryRepository(),
+	}
+}
+
+// RecordPart records a part installed during an order and takes it out of the branch stock.
+// Technicians may only record parts on orders assigned to them.
+func (s *OrderPartService) RecordPart(ctx context.Context, orderID, userID uuid.UUID, role model.UserRole, req *model.OrderPartRequest) (*model.OrderPartResponse, error) {
+	if !s.partRepo.Available() {
+		return nil, errors.New("part records are not available")
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+	if role == model.RoleTeknisi && (order.TechnicianID == nil || *order.TechnicianID != userID) {
+		return nil, model.ErrForbidden
+	}
+	if order.Status != model.StatusInService {
+		return nil, errors.New("parts can only be recorded while the order is in service")
+	}
+
+	sparePartID, err := uuid.Parse(req.SparePartID)
+	if err != nil {
+		return nil, model.ErrSparePartNotFound
+	}
+	sparePart, err := s.sparePartRepo.GetByID(ctx, sparePartID)
+	if err != nil || sparePart.BranchID != order.BranchID {
+		return nil, model.ErrSparePartNotFound
+	}
+
+	part := &model.OrderPart{
+		OrderID:     order.ID,
+		SparePartID: sparePart.ID,
+		PartCode:    sparePart.PartCode,
+		PartName:    sparePart.PartName,
+		Quantity:    req.Quantity,
+		UnitPrice:   sparePart.Price,
+		InstalledBy: userID,
+	}
+	if err := s.partRepo.Record(ctx, part); err != nil {
+		return nil, err
+	}
+
+	response := part.ToResponse()
+	return &response, nil
+}
+
+// ListParts retrieves the parts installed during an order
+func (s *OrderPartService) ListParts(ctx context.Context, orderID uuid.UUID) ([]model.OrderPartResponse, error) {
+	if _, err := s.orderRepo.GetByID(ctx, orderID); err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+
+	responses := []model.OrderPartResponse{}
+	if !s.partRepo.Available() {
+		return responses, nil
+	}
+
+	parts, err := s.partRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range parts {
+		responses = append(responses, p.ToResponse())
+	}
+	return responses, nil
+}
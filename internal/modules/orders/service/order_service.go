@@ -3,38 +3,79 @@ package service
 import (
 	"context"
 	"errors"
+	"log"
+	"mime/multipart"
 	branchRepo "service/internal/modules/branches/repository"
+	deviceRepo "service/internal/modules/devices/repository"
+	numberingService "service/internal/modules/numbering/service"
 	"service/internal/modules/orders/repository"
+	paymentService "service/internal/modules/payments/service"
+	slaService "service/internal/modules/sla/service"
+	trackingService "service/internal/modules/tracking/service"
 	userRepo "service/internal/modules/users/repository"
+	"service/internal/shared/config"
 	"service/internal/shared/model"
 	"service/internal/shared/utils"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type OrderService struct {
-	orderRepo  *repository.ServiceOrderRepository
-	userRepo   *userRepo.UserRepository
-	branchRepo *branchRepo.BranchRepository
+	orderRepo    *repository.ServiceOrderRepository
+	userRepo     *userRepo.UserRepository
+	branchRepo   *branchRepo.BranchRepository
+	deviceRepo   *deviceRepo.CustomerDeviceRepository
+	warrantyRepo *repository.WarrantyRepository
+	eventRepo    *repository.StatusEventRepository
+
+	assignmentService   *AssignmentService
+	dispatchService     *DispatchService
+	trackingService     *trackingService.TrackingService
+	handoverService     *HandoverService
+	slaService          *slaService.SLAService
+	cancellationService *CancellationService
+	paymentService      *paymentService.PaymentService
+	numberingService    *numberingService.NumberingService
 }
 
 // NewOrderService creates a new order service
 func NewOrderService() *OrderService {
 	return &OrderService{
-		orderRepo:  repository.NewServiceOrderRepository(),
-		userRepo:   userRepo.NewUserRepository(),
-		branchRepo: branchRepo.NewBranchRepository(),
+		orderRepo:    repository.NewServiceOrderRepository(),
+		userRepo:     userRepo.NewUserRepository(),
+		branchRepo:   branchRepo.NewBranchRepository(),
+		deviceRepo:   deviceRepo.NewCustomerDeviceRepository(),
+		warrantyRepo: repository.NewWarrantyRepository(),
+		eventRepo:    repository.NewStatusEventRepository(),
+
+		assignmentService:   NewAssignmentService(),
+		dispatchService:     NewDispatchService(),
+		trackingService:     trackingService.NewTrackingService(),
+		handoverService:     NewHandoverService(),
+		slaService:          slaService.NewSLAService(),
+		cancellationService: NewCancellationService(),
+		paymentService:      paymentService.NewPaymentService(),
+		numberingService:    numberingService.NewNumberingService(),
 	}
 }
 
 // CreateOrder creates a new service order
 func (s *OrderService) CreateOrder(ctx context.Context, customerID uuid.UUID, req *model.ServiceOrderRequest) (*model.ServiceOrderResponse, error) {
 	// Validate customer exists
-	_, err := s.userRepo.GetByID(ctx, customerID)
+	customer, err := s.userRepo.GetByID(ctx, customerID)
 	if err != nil {
 		return nil, model.ErrUserNotFound
 	}
 
+	// Customers must confirm their email and phone before ordering (configurable)
+	if config.Config != nil && config.Config.RequireVerifiedContactOrders &&
+		customer.Role == model.RolePelanggan && !customer.IsContactVerified() {
+		return nil, model.ErrContactNotVerified
+	}
+
 	// Validate branch exists
 	branchID, err := uuid.Parse(req.BranchID)
 	if err != nil {
@@ -46,17 +87,20 @@ func (s *OrderService) CreateOrder(ctx context.Context, customerID uuid.UUID, re
 		return nil, model.ErrBranchNotFound
 	}
 
-	// Generate unique order number
-	orderNumber := utils.GenerateOrderNumber()
+	if !model.IsValidTimeWindow(req.PickupWindowStart, req.PickupWindowEnd) {
+		return nil, model.ErrInvalidTimeWindow
+	}
 
-	// Check if order number already exists (very unlikely but safety check)
-	exists, err := s.orderRepo.CheckOrderNumberExists(ctx, orderNumber, nil)
+	// Link the customer's registered device so its service history follows it
+	deviceID, err := s.resolveDevice(ctx, customerID, req)
 	if err != nil {
 		return nil, err
 	}
-	if exists {
-		// Regenerate if exists
-		orderNumber = utils.GenerateOrderNumber()
+
+	// Take the next order number of the branch
+	orderNumber, err := s.numberingService.NextOrderNumber(ctx, branchID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create order entity
@@ -77,12 +121,17 @@ func (s *OrderService) CreateOrder(ctx context.Context, customerID uuid.UUID, re
 		ActualCost:        0,
 		EstimatedDuration: 0,
 		ActualDuration:    0,
+		DeviceID:          deviceID,
+		PickupWindowStart: req.PickupWindowStart,
+		PickupWindowEnd:   req.PickupWindowEnd,
 	}
 
 	// Save to database
 	if err := s.orderRepo.Create(ctx, order); err != nil {
 		return nil, err
 	}
+	s.recordStatus(ctx, order)
+	s.slaService.OnStatusChange(ctx, order)
 
 	// Return response with populated data
 	response := order.ToResponse()
@@ -111,31 +160,306 @@ func (s *OrderService) GetOrderByNumber(ctx context.Context, orderNumber string)
 	return &response, nil
 }
 
-// UpdateOrderStatus updates the status of an order
-func (s *OrderService) UpdateOrderStatus(ctx context.Context, id uuid.UUID, req *model.UpdateOrderStatusRequest) (*model.ServiceOrderResponse, error) {
+// UpdateOrderStatus updates the status of an order. When expectedVersion is set the
+// order must still be at that version.
+func (s *OrderService) UpdateOrderStatus(ctx context.Context, id uuid.UUID, req *model.UpdateOrderStatusRequest, expectedVersion *int64) (*model.ServiceOrderResponse, error) {
 	// Get existing order
 	order, err := s.orderRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, model.ErrOrderNotFound
 	}
+	if err := utils.CheckVersion(order.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	// Cancelling needs a reason and settles fees, see CancelOrder
+	if req.Status == model.StatusCancelled {
+		return nil, model.ErrCancelRequiresReason
+	}
+
+	// Handovers between courier and customer go through CompleteHandover with proof
+	if _, next, ok := model.HandoverKindFor(order.Status); ok && next == req.Status && handoverProofRequired() {
+		return nil, model.ErrHandoverRequired
+	}
+
+	return s.changeStatus(ctx, order, req.Status, req.Notes)
+}
 
+// changeStatus moves an order to a new status and runs everything that follows from it
+func (s *OrderService) changeStatus(ctx context.Context, order *model.ServiceOrder, status model.OrderStatus, notes string) (*model.ServiceOrderResponse, error) {
 	// Update status
-	order.Status = req.Status
-	if req.Notes != "" {
-		order.Notes = req.Notes
+	wasTracked := order.IsCourierTracked()
+	order.Status = status
+	if notes != "" {
+		order.Notes = notes
 	}
 
 	// Save changes
 	if err := s.orderRepo.Update(ctx, order); err != nil {
 		return nil, err
 	}
+	return s.statusChanged(ctx, order, wasTracked), nil
+}
+
+// statusChanged follows up on a saved status change: history, SLAs, assignment, dispatch,
+// courier tracking and handover codes
+func (s *OrderService) statusChanged(ctx context.Context, order *model.ServiceOrder, wasTracked bool) *model.ServiceOrderResponse {
+	s.recordStatus(ctx, order)
+	s.slaService.OnStatusChange(ctx, order)
+
+	if order.Status == model.StatusCompleted {
+		s.startWarranty(ctx, order)
+	}
+
+	// The order has arrived at the branch and needs someone to work on it
+	if order.Status == model.StatusInService {
+		s.assignmentService.AssignOnArrival(ctx, order)
+	}
+	s.dispatchService.CompleteStops(ctx, order)
+
+	// Live courier tracking ends with the courier part of the journey
+	if wasTracked && !order.IsCourierTracked() {
+		s.trackingService.StopIfInactive(ctx, order)
+	}
+
+	// The customer needs a code for the handover that ends the courier's next leg
+	if order.Status == model.StatusOnPickup || order.Status == model.StatusReady {
+		s.handoverService.IssueOnTransition(ctx, order)
+	}
 
 	response := order.ToResponse()
-	return &response, nil
+	return &response
+}
+
+// CancelOrder cancels an order for the given reason. The fee scheduled for how far the
+// order progressed is settled against what the customer paid, parts recorded on the order
+// go back into stock and the cancellation is kept for reporting. Customers can only cancel
+// their own orders. Staff need the status grant, and technicians and couriers can only
+// cancel orders assigned to them. Waiving the fee needs its own grant, and so does a staff
+// cancellation that refunds payments; customers cancelling their own order are refunded
+// without one. Work order lines are not settled here; their fee is billed on the
+// corporate account's monthly invoice.
+func (s *OrderService) CancelOrder(ctx context.Context, orderID, userID uuid.UUID, role model.UserRole, req *model.CancelOrderRequest, grants model.CancellationGrants) (*model.CancelOrderResponse, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+	switch role {
+	case model.RolePelanggan:
+		if order.CustomerID != userID {
+			return nil, model.ErrOrderNotFound
+		}
+	case model.RoleTeknisi:
+		if !grants.UpdateStatus || order.TechnicianID == nil || *order.TechnicianID != userID {
+			return nil, model.ErrForbidden
+		}
+	case model.RoleKurir:
+		if !grants.UpdateStatus {
+			return nil, model.ErrForbidden
+		}
+		if order.CourierID == nil || *order.CourierID != userID {
+			return nil, model.ErrNotOrderCourier
+		}
+	default:
+		if !grants.UpdateStatus {
+			return nil, model.ErrForbidden
+		}
+	}
+	if req.WaiveFee && !grants.WaiveFee {
+		return nil, model.ErrCancellationWaiveDenied
+	}
+	if !model.IsCancellable(order.Status) {
+		return nil, model.ErrOrderNotCancellable
+	}
+	if req.ReasonCode == model.CancellationOther && strings.TrimSpace(req.Note) == "" {
+		return nil, model.ErrCancellationNoteRequired
+	}
+
+	cancellation := &model.OrderCancellation{
+		OrderID:        order.ID,
+		BranchID:       order.BranchID,
+		ReasonCode:     req.ReasonCode,
+		ReasonNote:     req.Note,
+		StatusAtCancel: order.Status,
+		CancelledBy:    userID,
+	}
+	if req.WaiveFee {
+		cancellation.FeeWaived = true
+	} else {
+		cancellation.Fee = s.cancellationService.FeeFor(ctx, order.Status)
+	}
+
+	// The order is saved together with its settled payments, the parts going back into
+	// stock and the cancellation record, so that either all of them change or none does.
+	wasTracked := order.IsCourierTracked()
+	order.Status = model.StatusCancelled
+	cancellation.CreatedAt = time.Now()
+	writes := repository.CancellationWrites(cancellation)
+	saved := false
+	if order.WorkOrderID == nil {
+		refund := cancellationRefundAllowed(role, grants)
+		settlement, err := s.paymentService.SettleCancellation(ctx, order, cancellation.Fee, refund, writes)
+		if err != nil {
+			order.Status = cancellation.StatusAtCancel
+			return nil, err
+		}
+		cancellation.PaidAmount = settlement.PaidAmount
+		cancellation.RefundAmount = settlement.RefundAmount
+		cancellation.ChargePaymentID = settlement.ChargePaymentID
+		saved = settlement.OrderSaved
+	}
+	if !saved {
+		if err := s.orderRepo.UpdateWith(ctx, order, writes); err != nil {
+			order.Status = cancellation.StatusAtCancel
+			return nil, err
+		}
+	}
+	response := s.statusChanged(ctx, order, wasTracked)
+
+	return &model.CancelOrderResponse{
+		Order:        *response,
+		Cancellation: *cancellation,
+	}, nil
+}
+
+// cancellationRefundAllowed reports whether a cancellation may refund what was paid beyond
+// the fee. Customers reaching this point cancel their own order and get their deposit back;
+// staff need the refund grant.
+func cancellationRefundAllowed(role model.UserRole, grants model.CancellationGrants) bool {
+	return role == model.RolePelanggan || grants.Refund
+}
+
+// CompleteHandover records the proof of a handover between courier and customer and moves
+// the order on: into the branch after a pickup, to delivered after a delivery. Couriers can
+// only complete handovers of orders assigned to them.
+func (s *OrderService) CompleteHandover(ctx context.Context, orderID, performedBy uuid.UUID, role model.UserRole, req *model.HandoverRequest, photo, signature *multipart.FileHeader) (*model.HandoverProofResponse, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+	if role == model.RoleKurir && (order.CourierID == nil || *order.CourierID != performedBy) {
+		return nil, model.ErrNotOrderCourier
+	}
+
+	kind, next, ok := model.HandoverKindFor(order.Status)
+	if !ok {
+		return nil, model.ErrHandoverNotExpected
+	}
+
+	proof, err := s.handoverService.Verify(ctx, order, kind, performedBy, req, photo, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.changeStatus(ctx, order, next, ""); err != nil {
+		return nil, err
+	}
+	return proof, nil
 }
 
-// AssignTechnician assigns a technician to an order
-func (s *OrderService) AssignTechnician(ctx context.Context, orderID, technicianID uuid.UUID) (*model.ServiceOrderResponse, error) {
+// RequestHandoverCode issues a new handover code for the customer's next handover, e.g.
+// when the code sent over WhatsApp was lost
+func (s *OrderService) RequestHandoverCode(ctx context.Context, orderID, customerID uuid.UUID) (*model.HandoverCodeResponse, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil || order.CustomerID != customerID {
+		return nil, model.ErrOrderNotFound
+	}
+
+	var kind model.HandoverKind
+	switch order.Status {
+	case model.StatusPendingPickup, model.StatusOnPickup:
+		kind = model.HandoverPickup
+	case model.StatusInService, model.StatusReady:
+		kind = model.HandoverDelivery
+	default:
+		return nil, model.ErrHandoverNotExpected
+	}
+	return s.handoverService.IssueCode(ctx, order, kind)
+}
+
+// GetHandoverHistory retrieves the handover proofs and refused attempts of an order
+func (s *OrderService) GetHandoverHistory(ctx context.Context, orderID uuid.UUID) (*model.HandoverHistoryResponse, error) {
+	if _, err := s.orderRepo.GetByID(ctx, orderID); err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+	return s.handoverService.GetHistory(ctx, orderID)
+}
+
+// recordStatus adds the order's current status to its history. Failures are logged only.
+func (s *OrderService) recordStatus(ctx context.Context, order *model.ServiceOrder) {
+	if !s.eventRepo.Available() {
+		return
+	}
+	if err := s.eventRepo.Record(ctx, order.ID, order.Status); err != nil {
+		log.Printf("Failed to record status history of order %s: %v", order.ID, err)
+	}
+}
+
+// resolveDevice finds the registered device an order is for.
+// An explicit device ID must belong to the customer and fills in the iPhone fields;
+// otherwise a device the customer registered with the same IMEI is linked when there is one.
+func (s *OrderService) resolveDevice(ctx context.Context, customerID uuid.UUID, req *model.ServiceOrderRequest) (*uuid.UUID, error) {
+	if !s.deviceRepo.Available() {
+		if req.DeviceID != "" {
+			return nil, model.ErrCustomerDeviceNotFound
+		}
+		return nil, nil
+	}
+
+	if req.DeviceID == "" {
+		if !utils.IsValidIMEI(req.IPhoneIMEI) {
+			return nil, nil
+		}
+		device, err := s.deviceRepo.GetByCustomerIMEI(ctx, customerID, req.IPhoneIMEI)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return &device.ID, nil
+	}
+
+	id, err := uuid.Parse(req.DeviceID)
+	if err != nil {
+		return nil, model.ErrCustomerDeviceNotFound
+	}
+	device, err := s.deviceRepo.GetByID(ctx, id)
+	if err != nil || device.CustomerID != customerID {
+		return nil, model.ErrCustomerDeviceNotFound
+	}
+
+	req.IPhoneModel = device.DeviceModel.Name
+	req.IPhoneType = device.DeviceModel.Name
+	req.IPhoneColor = device.Color
+	req.IPhoneIMEI = device.IMEI
+	return &device.ID, nil
+}
+
+// startWarranty starts the warranty of a completed order based on its service type.
+// Failures are logged only; the status change has already been saved.
+func (s *OrderService) startWarranty(ctx context.Context, order *model.ServiceOrder) {
+	if !s.warrantyRepo.Available() {
+		return
+	}
+
+	days := model.GetServiceEstimate(order.ServiceType).Warranty
+	start := time.Now()
+	warranty := &model.Warranty{
+		OrderID:      order.ID,
+		WarrantyDays: days,
+		StartDate:    start,
+		EndDate:      start.AddDate(0, 0, days),
+		IsActive:     true,
+	}
+	if err := s.warrantyRepo.CreateForOrder(ctx, warranty); err != nil {
+		log.Printf("Failed to start warranty for order %s: %v", order.ID, err)
+	}
+}
+
+// AssignTechnician assigns a technician to an order chosen by staff, overriding any automatic
+// assignment. When expectedVersion is set the order must still be at that version.
+func (s *OrderService) AssignTechnician(ctx context.Context, orderID, technicianID uuid.UUID, assignedBy *uuid.UUID, expectedVersion *int64) (*model.ServiceOrderResponse, error) {
 	// Validate technician exists and has correct role
 	technician, err := s.userRepo.GetByID(ctx, technicianID)
 	if err != nil {
@@ -143,7 +467,7 @@ func (s *OrderService) AssignTechnician(ctx context.Context, orderID, technician
 	}
 
 	if string(technician.Role) != string(model.RoleTeknisi) {
-		return nil, errors.New("user is not a technician")
+		return nil, model.ErrNotTechnician
 	}
 
 	// Get order
@@ -152,16 +476,19 @@ func (s *OrderService) AssignTechnician(ctx context.Context, orderID, technician
 		return nil, model.ErrOrderNotFound
 	}
 
-	// Assign technician
-	if err := s.orderRepo.AssignTechnician(ctx, orderID, technicianID); err != nil {
+	if err := utils.CheckVersion(order.Version, expectedVersion); err != nil {
 		return nil, err
 	}
 
-	// Update order status if needed
+	// Assign technician, moving the order into service if needed, in one write
+	order.TechnicianID = &technicianID
 	if order.Status == model.StatusPendingPickup {
 		order.Status = model.StatusInService
-		s.orderRepo.Update(ctx, order)
 	}
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return nil, err
+	}
+	s.assignmentService.RecordManualAssignment(ctx, orderID, technicianID, assignedBy)
 
 	response := order.ToResponse()
 	return &response, nil
@@ -176,7 +503,7 @@ func (s *OrderService) AssignCourier(ctx context.Context, orderID, courierID uui
 	}
 
 	if string(courier.Role) != string(model.RoleKurir) {
-		return nil, errors.New("user is not a courier")
+		return nil, model.ErrNotCourier
 	}
 
 	// Get order
@@ -360,12 +687,16 @@ func (s *OrderService) GetAllOrders(ctx context.Context, page, limit int, status
 	return responses, total, nil
 }
 
-// UpdateOrder updates an order's details (admin)
-func (s *OrderService) UpdateOrder(ctx context.Context, id uuid.UUID, req *model.ServiceOrderRequest) (*model.ServiceOrderResponse, error) {
+// UpdateOrder updates an order's details (admin). When expectedVersion is set the order
+// must still be at that version.
+func (s *OrderService) UpdateOrder(ctx context.Context, id uuid.UUID, req *model.ServiceOrderRequest, expectedVersion *int64) (*model.ServiceOrderResponse, error) {
 	order, err := s.orderRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, model.ErrOrderNotFound
 	}
+	if err := utils.CheckVersion(order.Version, expectedVersion); err != nil {
+		return nil, err
+	}
 
 	if req.BranchID != "" {
 		if bid, err := uuid.Parse(req.BranchID); err == nil {
@@ -406,6 +737,9 @@ func (s *OrderService) DeleteOrder(ctx context.Context, id uuid.UUID) error {
 
 // GetOrdersByBranchID retrieves orders for a specific branch with pagination
 func (s *OrderService) GetOrdersByBranchID(ctx context.Context, branchID uuid.UUID, page, limit int) ([]model.ServiceOrderResponse, int64, error) {
+	if !model.BranchScopeFromContext(ctx).Allows(branchID) {
+		return nil, 0, model.ErrForbidden
+	}
 	offset := (page - 1) * limit
 	orders, total, err := s.orderRepo.List(ctx, offset, limit, &repository.ServiceOrderFilters{BranchID: &branchID})
 	if err != nil {
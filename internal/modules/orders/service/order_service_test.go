@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"service/internal/shared/model"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancelOrderAccess(t *testing.T) {
+	customerID, technicianID, courierID, staffID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	statusGrant := model.CancellationGrants{UpdateStatus: true}
+
+	tests := []struct {
+		name     string
+		userID   uuid.UUID
+		role     model.UserRole
+		grants   model.CancellationGrants
+		waiveFee bool
+		want     error
+	}{
+		{"owning customer", customerID, model.RolePelanggan, model.CancellationGrants{}, false, nil},
+		{"another customer", uuid.New(), model.RolePelanggan, model.CancellationGrants{}, false, model.ErrOrderNotFound},
+		{"customer waiving the fee", customerID, model.RolePelanggan, model.CancellationGrants{}, true, model.ErrCancellationWaiveDenied},
+		{"assigned technician", technicianID, model.RoleTeknisi, statusGrant, false, nil},
+		{"technician of another order", uuid.New(), model.RoleTeknisi, statusGrant, false, model.ErrForbidden},
+		{"technician without the status grant", technicianID, model.RoleTeknisi, model.CancellationGrants{}, false, model.ErrForbidden},
+		{"assigned courier", courierID, model.RoleKurir, statusGrant, false, nil},
+		{"courier of another order", uuid.New(), model.RoleKurir, statusGrant, false, model.ErrNotOrderCourier},
+		{"courier without the status grant", courierID, model.RoleKurir, model.CancellationGrants{}, false, model.ErrForbidden},
+		{"cashier", staffID, model.RoleKasir, statusGrant, false, nil},
+		{"staff without the status grant", staffID, model.RoleKasir, model.CancellationGrants{}, false, model.ErrForbidden},
+		{"staff waiving the fee", staffID, model.RoleAdminCabang, model.CancellationGrants{UpdateStatus: true, WaiveFee: true}, true, nil},
+		{"staff waiving without the grant", staffID, model.RoleKasir, statusGrant, true, model.ErrCancellationWaiveDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := NewOrderService()
+			order := &model.ServiceOrder{
+				CustomerID:   customerID,
+				BranchID:     uuid.New(),
+				TechnicianID: &technicianID,
+				CourierID:    &courierID,
+				Status:       model.StatusPendingPickup,
+			}
+			assert.NoError(t, s.orderRepo.Create(ctx, order))
+
+			req := &model.CancelOrderRequest{ReasonCode: model.CancellationCustomerRequest, WaiveFee: tt.waiveFee}
+			result, err := s.CancelOrder(ctx, order.ID, tt.userID, tt.role, req, tt.grants)
+			assert.Equal(t, tt.want, err)
+
+			stored, _ := s.orderRepo.GetByID(ctx, order.ID)
+			if err != nil {
+				assert.Equal(t, model.StatusPendingPickup, stored.Status, "a refused cancellation leaves the order")
+				return
+			}
+			assert.Equal(t, model.StatusCancelled, stored.Status)
+			assert.Equal(t, tt.waiveFee, result.Cancellation.FeeWaived)
+			assert.False(t, result.Cancellation.CreatedAt.IsZero())
+		})
+	}
+}
+
+func TestCancellationRefundAllowed(t *testing.T) {
+	tests := []struct {
+		name   string
+		role   model.UserRole
+		grants model.CancellationGrants
+		want   bool
+	}{
+		{"customer cancelling their own order", model.RolePelanggan, model.CancellationGrants{}, true},
+		{"staff with the refund grant", model.RoleAdminCabang, model.CancellationGrants{UpdateStatus: true, Refund: true}, true},
+		{"staff without the refund grant", model.RoleKasir, model.CancellationGrants{UpdateStatus: true}, false},
+		{"courier", model.RoleKurir, model.CancellationGrants{UpdateStatus: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, cancellationRefundAllowed(tt.role, tt.grants))
+		})
+	}
+}
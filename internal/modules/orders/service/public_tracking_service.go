@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"log"
+	branchRepo "service/internal/modules/branches/repository"
+	"service/internal/modules/orders/repository"
+	userRepo "service/internal/modules/users/repository"
+	"service/internal/shared/config"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Public tracking defaults used when no configuration is loaded
+const (
+	defaultTrackingMaxFailures = 5
+	defaultTrackingLockout     = 15 * time.Minute
+)
+
+// PublicTrackingService lets customers without an account follow their order by order
+// number and phone digits, or through the signed link in the order's QR code
+type PublicTrackingService struct {
+	orderRepo       *repository.ServiceOrderRepository
+	statusEventRepo *repository.StatusEventRepository
+	lockRepo        *repository.TrackingLockRepository
+	userRepo        *userRepo.UserRepository
+	branchRepo      *branchRepo.BranchRepository
+}
+
+// NewPublicTrackingService creates a new public tracking service
+func NewPublicTrackingService() *PublicTrackingService {
+	return &PublicTrackingService{
+		orderRepo:       repository.NewServiceOrderRepository(),
+		statusEventRepo: repository.NewStatusEventRepository(),
+		lockRepo:        repository.NewTrackingLockRepository(),
+		userRepo:        userRepo.NewUserRepository(),
+		branchRepo:      branchRepo.NewBranchRepository(),
+	}
+}
+
+// TrackByPhone looks an order up by its number and the last four digits of the customer's
+// phone. Unknown orders and wrong digits look the same to the caller, and a client is
+// locked out of an order number for a while after too many failures. Other clients, such
+// as the customer, can still track the order meanwhile.
+func (s *PublicTrackingService) TrackByPhone(ctx context.Context, req *model.PublicTrackingRequest, client string) (*model.PublicTrackingResponse, error) {
+	orderNumber := strings.ToUpper(strings.TrimSpace(req.OrderNumber))
+
+	if s.lockRepo.Available() {
+		failures, err := s.lockRepo.Failures(ctx, client, orderNumber)
+		if err != nil {
+			return nil, err
+		}
+		if failures >= trackingMaxFailures() {
+			return nil, model.ErrTrackingLocked
+		}
+	}
+
+	order, err := s.orderRepo.GetByOrderNumber(ctx, orderNumber)
+	if err != nil || !s.phoneMatches(ctx, order, req.PhoneLast4) {
+		s.recordFailure(ctx, client, orderNumber)
+		return nil, model.ErrOrderNotFound
+	}
+	return s.toPublicResponse(ctx, order), nil
+}
+
+// TrackByToken looks an order up through the signed token of its QR code
+func (s *PublicTrackingService) TrackByToken(ctx context.Context, token string) (*model.PublicTrackingResponse, error) {
+	if config.Config == nil {
+		return nil, model.ErrInvalidTrackingToken
+	}
+	orderNumber, err := utils.ParseOrderTrackingToken(token, config.Config.JWTSecret)
+	if err != nil {
+		return nil, model.ErrInvalidTrackingToken
+	}
+
+	order, err := s.orderRepo.GetByOrderNumber(ctx, orderNumber)
+	if err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+	return s.toPublicResponse(ctx, order), nil
+}
+
+// phoneMatches compares the given digits with the end of the customer's phone number
+func (s *PublicTrackingService) phoneMatches(ctx context.Context, order *model.ServiceOrder, last4 string) bool {
+	customer, err := s.userRepo.GetByID(ctx, order.CustomerID)
+	if err != nil {
+		return false
+	}
+
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, customer.Phone)
+	if len(digits) < 4 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(digits[len(digits)-4:]), []byte(last4)) == 1
+}
+
+// recordFailure counts a failed lookup by a client. Failures are logged only.
+func (s *PublicTrackingService) recordFailure(ctx context.Context, client, orderNumber string) {
+	if !s.lockRepo.Available() {
+		return
+	}
+	if err := s.lockRepo.RecordFailure(ctx, client, orderNumber, trackingLockout()); err != nil {
+		log.Printf("Failed to record tracking failure for order %s: %v", orderNumber, err)
+	}
+}
+
+// toPublicResponse reduces an order to what can be shown without login
+func (s *PublicTrackingService) toPublicResponse(ctx context.Context, order *model.ServiceOrder) *model.PublicTrackingResponse {
+	response := &model.PublicTrackingResponse{
+		OrderNumber: order.OrderNumber,
+		Status:      order.Status,
+		ServiceType: order.ServiceType,
+		IPhoneModel: order.IPhoneModel,
+		CreatedAt:   order.CreatedAt,
+		UpdatedAt:   order.UpdatedAt,
+		Timeline:    s.timeline(ctx, order),
+	}
+	if branch, err := s.branchRepo.GetByID(ctx, order.BranchID); err == nil {
+		response.BranchName = branch.Name
+		response.BranchPhone = branch.Phone
+	}
+	return response
+}
+
+// timeline lists the statuses the order reached. Orders from before the status history
+// only show when they were created and their current status.
+func (s *PublicTrackingService) timeline(ctx context.Context, order *model.ServiceOrder) []model.PublicTrackingStep {
+	var steps []model.PublicTrackingStep
+	if s.statusEventRepo.Available() {
+		events, err := s.statusEventRepo.ListByOrder(ctx, order.ID)
+		if err != nil {
+			log.Printf("Failed to load status history of order %s: %v", order.ID, err)
+		}
+		for _, event := range events {
+			// Repeated updates to the same status are one step
+			if len(steps) > 0 && steps[len(steps)-1].Status == event.Status {
+				continue
+			}
+			steps = append(steps, model.PublicTrackingStep{Status: event.Status, At: event.CreatedAt})
+		}
+	}
+	if len(steps) > 0 {
+		return steps
+	}
+
+	steps = append(steps, model.PublicTrackingStep{Status: model.StatusPendingPickup, At: order.CreatedAt})
+	if order.Status != model.StatusPendingPickup {
+		steps = append(steps, model.PublicTrackingStep{Status: order.Status, At: order.UpdatedAt})
+	}
+	return steps
+}
+
+// trackingMaxFailures returns how many failed lookups lock a client out of an order number
+func trackingMaxFailures() int {
+	if config.Config != nil && config.Config.PublicTrackingMaxFailures > 0 {
+		return config.Config.PublicTrackingMaxFailures
+	}
+	return defaultTrackingMaxFailures
+}
+
+// trackingLockout returns how long a client stays locked out of an order number
+func trackingLockout() time.Duration {
+	if config.Config != nil && config.Config.PublicTrackingLockout > 0 {
+		return config.Config.PublicTrackingLockout
+	}
+	return defaultTrackingLockout
+}
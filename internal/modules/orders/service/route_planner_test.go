@@ -0,0 +1,117 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	plannerDepart = time.Date(2026, time.March, 9, 9, 0, 0, 0, time.UTC)
+	plannerOpts   = plannerOptions{clusterRadiusKm: 5, maxStops: 8, speedKmh: 30, stopDuration: 5 * time.Minute}
+	plannerBranch = routePoint{lat: -6.2, lon: 106.8}
+)
+
+// stopAt places a stop the given number of hundredths of a degree north and east of the branch
+func stopAt(name string, north, east float64) plannedStop {
+	return plannedStop{
+		address: name,
+		point:   routePoint{lat: plannerBranch.lat + north/100, lon: plannerBranch.lon + east/100},
+	}
+}
+
+// closingAt gives a stop a window that ends at the given time
+func closingAt(stop plannedStop, end time.Time) plannedStop {
+	stop.windowEnd = &end
+	return stop
+}
+
+// stopNames lists the addresses of a sequence in order
+func stopNames(sequence []plannedStop) []string {
+	names := make([]string, len(sequence))
+	for i, stop := range sequence {
+		names[i] = stop.address
+	}
+	return names
+}
+
+func TestNearestNeighbour(t *testing.T) {
+	tests := []struct {
+		name  string
+		stops []plannedStop
+		want  []string
+	}{
+		{
+			name:  "closest stop first",
+			stops: []plannedStop{stopAt("far", 0, 3), stopAt("near", 0, 1), stopAt("middle", 0, 2)},
+			want:  []string{"near", "middle", "far"},
+		},
+		{
+			name: "skips stops whose window has closed",
+			stops: []plannedStop{
+				closingAt(stopAt("missed", 0, 1), plannerDepart.Add(-time.Hour)),
+				stopAt("reachable", 0, 2),
+			},
+			want: []string{"reachable", "missed"},
+		},
+		{
+			name: "earliest deadline first when none can be reached",
+			stops: []plannedStop{
+				closingAt(stopAt("later", 0, 1), plannerDepart.Add(-time.Minute)),
+				closingAt(stopAt("earlier", 0, 2), plannerDepart.Add(-time.Hour)),
+			},
+			want: []string{"earlier", "later"},
+		},
+		{
+			name:  "no stops",
+			stops: nil,
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sequence := nearestNeighbour(plannerBranch, tt.stops, plannerDepart, plannerOpts)
+			assert.Equal(t, tt.want, stopNames(sequence))
+		})
+	}
+}
+
+func TestTwoOpt(t *testing.T) {
+	north, corner, east := stopAt("north", 1, 0), stopAt("corner", 1, 1), stopAt("east", 0, 1)
+
+	// A window on east that the crossing route meets and the square around the block misses,
+	// and one on north that only going there first meets
+	crossing := []plannedStop{north, east, corner}
+	eastDue := simulate(plannerBranch, crossing, plannerDepart, plannerOpts).arrivals[1].Add(time.Minute)
+	northDue := simulate(plannerBranch, crossing, plannerDepart, plannerOpts).arrivals[0].Add(time.Minute)
+	windowed := []plannedStop{closingAt(north, northDue), closingAt(east, eastDue), corner}
+
+	tests := []struct {
+		name     string
+		sequence []plannedStop
+		want     [][]string // either direction around a loop is as short
+	}{
+		{"uncrosses the route", crossing, [][]string{{"north", "corner", "east"}, {"east", "corner", "north"}}},
+		{"keeps a shortest route", []plannedStop{north, corner, east}, [][]string{{"north", "corner", "east"}}},
+		{"does not make stops late to save distance", windowed, [][]string{{"north", "east", "corner"}}},
+		{"single stop", []plannedStop{north}, [][]string{{"north"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := simulate(plannerBranch, tt.sequence, plannerDepart, plannerOpts)
+			sequence := twoOpt(plannerBranch, tt.sequence, plannerDepart, plannerOpts)
+			after := simulate(plannerBranch, sequence, plannerDepart, plannerOpts)
+
+			assert.Contains(t, tt.want, stopNames(sequence))
+			assert.LessOrEqual(t, after.lateStops, before.lateStops)
+			assert.LessOrEqual(t, after.distanceKm, before.distanceKm+1e-9)
+		})
+	}
+
+	// The shorter square around the block would make east late
+	square := simulate(plannerBranch, []plannedStop{windowed[0], windowed[2], windowed[1]}, plannerDepart, plannerOpts)
+	assert.Equal(t, 1, square.lateStops)
+}
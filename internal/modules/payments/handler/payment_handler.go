@@ -49,8 +49,12 @@ func (h *PaymentHandler) MidtransCallback(c *gin.Context) {
 	// Delegate to service for signature verification and updates
 	if err := h.paymentService.HandleMidtransCallback(c.Request.Context(), &payload, config.Config.MidtransServerKey); err != nil {
 		status := http.StatusInternalServerError
-		if err == model.ErrPaymentNotFound {
+		switch err {
+		case model.ErrPaymentNotFound:
 			status = http.StatusBadRequest
+		case model.ErrVersionConflict:
+			// Midtrans retries the notification, which then applies to the current payment
+			status = http.StatusConflict
 		}
 		c.JSON(status, model.CreateErrorResponse(
 			"midtrans_callback_error",
@@ -74,6 +78,7 @@ func (h *PaymentHandler) MidtransCallback(c *gin.Context) {
 // @Success 201 {object} model.APIResponse
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Router /payments [post]
 func (h *PaymentHandler) CreatePayment(c *gin.Context) {
@@ -105,6 +110,10 @@ func (h *PaymentHandler) CreatePayment(c *gin.Context) {
 		statusCode := http.StatusInternalServerError
 		if err == model.ErrOrderNotFound {
 			statusCode = http.StatusBadRequest
+		} else if err == model.ErrOrderBilledToAccount {
+			statusCode = http.StatusConflict
+		} else if err == model.ErrForbidden {
+			statusCode = http.StatusForbidden
 		}
 		c.JSON(statusCode, model.CreateErrorResponse(
 			"payment_creation_failed",
@@ -128,6 +137,7 @@ func (h *PaymentHandler) CreatePayment(c *gin.Context) {
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
+// @Header 200 {string} ETag "Payment version"
 // @Router /payments/{id} [get]
 func (h *PaymentHandler) GetPayment(c *gin.Context) {
 	idStr := c.Param("id")
@@ -155,6 +165,7 @@ func (h *PaymentHandler) GetPayment(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", utils.FormatETag(payment.Version))
 	c.JSON(http.StatusOK, model.SuccessResponse(payment, "Payment retrieved successfully"))
 }
 
@@ -192,18 +203,20 @@ func (h *PaymentHandler) GetPaymentByInvoice(c *gin.Context) {
 
 // UpdatePaymentStatus godoc
 // @Summary Update payment status
-// @Description Update the status of a payment
+// @Description Update the status of a payment. With If-Match the update only applies to that version of the payment; 409 returns the current payment.
 // @Tags payments
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Payment ID"
+// @Param If-Match header string false "ETag of the payment version being changed"
 // @Param status query string true "Payment Status"
 // @Param transaction_id query string false "Transaction ID"
 // @Success 200 {object} model.APIResponse
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Router /payments/{id}/status [put]
 func (h *PaymentHandler) UpdatePaymentStatus(c *gin.Context) {
@@ -249,11 +262,22 @@ func (h *PaymentHandler) UpdatePaymentStatus(c *gin.Context) {
 
 	transactionID := c.Query("transaction_id")
 
-	payment, err := h.paymentService.UpdatePaymentStatus(c.Request.Context(), id, status, transactionID)
+	expectedVersion, ok := utils.IfMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	payment, err := h.paymentService.UpdatePaymentStatus(c.Request.Context(), id, status, transactionID, expectedVersion)
+	if err == model.ErrVersionConflict {
+		h.respondPaymentConflict(c, id)
+		return
+	}
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		if err == model.ErrPaymentNotFound {
 			statusCode = http.StatusNotFound
+		} else if err == model.ErrForbidden {
+			statusCode = http.StatusForbidden
 		}
 		c.JSON(statusCode, model.CreateErrorResponse(
 			"payment_update_failed",
@@ -263,6 +287,7 @@ func (h *PaymentHandler) UpdatePaymentStatus(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", utils.FormatETag(payment.Version))
 	c.JSON(http.StatusOK, model.SuccessResponse(payment, "Payment status updated successfully"))
 }
 
@@ -308,6 +333,8 @@ func (h *PaymentHandler) ProcessMidtransPayment(c *gin.Context) {
 		statusCode := http.StatusInternalServerError
 		if err == model.ErrOrderNotFound {
 			statusCode = http.StatusBadRequest
+		} else if err == model.ErrOrderBilledToAccount {
+			statusCode = http.StatusConflict
 		}
 		c.JSON(statusCode, model.CreateErrorResponse(
 			"midtrans_payment_failed",
@@ -329,6 +356,7 @@ func (h *PaymentHandler) ProcessMidtransPayment(c *gin.Context) {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param order_id query string false "Filter by order ID"
+// @Param branch_id query string false "Filter by branch ID"
 // @Param status query string false "Filter by status"
 // @Param payment_method query string false "Filter by payment method"
 // @Success 200 {object} model.PaginatedResponse
@@ -359,6 +387,12 @@ func (h *PaymentHandler) ListPayments(c *gin.Context) {
 		}
 	}
 
+	if branchIDStr := c.Query("branch_id"); branchIDStr != "" {
+		if branchID, err := uuid.Parse(branchIDStr); err == nil {
+			filters.BranchID = &branchID
+		}
+	}
+
 	if statusStr := c.Query("status"); statusStr != "" {
 		status := model.PaymentStatus(statusStr)
 		filters.Status = &status
@@ -411,6 +445,8 @@ func (h *PaymentHandler) CreateInvoice(c *gin.Context) {
 		statusCode := http.StatusInternalServerError
 		if err == model.ErrOrderNotFound {
 			statusCode = http.StatusBadRequest
+		} else if err == model.ErrOrderBilledToAccount {
+			statusCode = http.StatusConflict
 		}
 		c.JSON(statusCode, model.CreateErrorResponse(
 			"payment_creation_failed",
@@ -451,6 +487,8 @@ func (h *PaymentHandler) ProcessPayment(c *gin.Context) {
 		statusCode := http.StatusInternalServerError
 		if err == model.ErrOrderNotFound {
 			statusCode = http.StatusBadRequest
+		} else if err == model.ErrOrderBilledToAccount {
+			statusCode = http.StatusConflict
 		}
 		c.JSON(statusCode, model.CreateErrorResponse(
 			"process_payment_failed",
@@ -469,7 +507,7 @@ func (h *PaymentHandler) GetAllPayments(c *gin.Context) {
 	h.ListPayments(c)
 }
 
-// UpdatePayment updates payment (admin)
+// UpdatePayment updates payment (admin). It honours If-Match like UpdatePaymentStatus.
 func (h *PaymentHandler) UpdatePayment(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -487,11 +525,22 @@ func (h *PaymentHandler) UpdatePayment(c *gin.Context) {
 
 	transactionID := c.Query("transaction_id")
 
-	payment, err := h.paymentService.UpdatePaymentStatus(c.Request.Context(), id, status, transactionID)
+	expectedVersion, ok := utils.IfMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	payment, err := h.paymentService.UpdatePaymentStatus(c.Request.Context(), id, status, transactionID, expectedVersion)
+	if err == model.ErrVersionConflict {
+		h.respondPaymentConflict(c, id)
+		return
+	}
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		if err == model.ErrPaymentNotFound {
 			statusCode = http.StatusNotFound
+		} else if err == model.ErrForbidden {
+			statusCode = http.StatusForbidden
 		}
 		c.JSON(statusCode, model.CreateErrorResponse(
 			"payment_update_failed",
@@ -501,6 +550,7 @@ func (h *PaymentHandler) UpdatePayment(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", utils.FormatETag(payment.Version))
 	c.JSON(http.StatusOK, model.SuccessResponse(payment, "Payment updated successfully"))
 }
 
@@ -539,3 +589,14 @@ func (h *PaymentHandler) GetPaymentsByOrder(c *gin.Context) {
 
 	c.JSON(http.StatusOK, model.SuccessResponse(payments, "Payments retrieved successfully"))
 }
+
+// respondPaymentConflict responds with 409 and the current payment, so the client can
+// redo its change on top of it
+func (h *PaymentHandler) respondPaymentConflict(c *gin.Context, id uuid.UUID) {
+	var current interface{}
+	if payment, err := h.paymentService.GetPayment(c.Request.Context(), id); err == nil {
+		c.Header("ETag", utils.FormatETag(payment.Version))
+		current = payment
+	}
+	c.JSON(http.StatusConflict, model.CreateErrorResponse("payment_update_failed", model.ErrVersionConflict.Error(), current))
+}
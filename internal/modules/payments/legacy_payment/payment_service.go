@@ -463,7 +463,7 @@ func (s *PaymentService) GenerateInvoice(ctx context.Context, paymentID uuid.UUI
 
 	// Generate invoice
 	invoice := &model.Invoice{
-		InvoiceNumber: utils.GenerateInvoiceNumber(),
+		InvoiceNumber: payment.InvoiceNumber,
 		OrderNumber:   order.OrderNumber,
 		CustomerName:  user.Name,
 		CustomerEmail: user.Email,
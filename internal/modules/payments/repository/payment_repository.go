@@ -2,14 +2,19 @@ package repository
 
 import (
 	"context"
+	"errors"
+	numberingRepo "service/internal/modules/numbering/repository"
+	orderRepo "service/internal/modules/orders/repository"
 	"service/internal/shared/database"
 	"service/internal/shared/model"
+	"service/internal/shared/utils"
 	"sort"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // PaymentRepository handles payment data operations
@@ -37,6 +42,12 @@ func NewPaymentRepository() *PaymentRepository {
 
 // Create creates a new payment
 func (r *PaymentRepository) Create(ctx context.Context, payment *model.Payment) error {
+	if err := r.checkOrderBranch(ctx, payment); err != nil {
+		return err
+	}
+	if payment.Version == 0 {
+		payment.Version = 1
+	}
 	if r.inMemory {
 		r.mu.Lock()
 		defer r.mu.Unlock()
@@ -52,19 +63,46 @@ func (r *PaymentRepository) Create(ctx context.Context, payment *model.Payment)
 	return r.db.WithContext(ctx).Create(payment).Error
 }
 
+// CreateInvoiced creates a new payment under the next invoice number of the scheme. The
+// number is taken in the same transaction, so a payment that fails to store does not use
+// one up and the invoices of a branch stay gapless. Without a scheme a generated invoice
+// number is used.
+func (r *PaymentRepository) CreateInvoiced(ctx context.Context, payment *model.Payment, scheme *model.NumberScheme) error {
+	if r.inMemory || scheme == nil {
+		payment.InvoiceNumber = utils.GenerateInvoiceNumber()
+		return r.Create(ctx, payment)
+	}
+	if err := r.checkOrderBranch(ctx, payment); err != nil {
+		return err
+	}
+	if payment.Version == 0 {
+		payment.Version = 1
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		year := time.Now().Year()
+		seq, err := numberingRepo.Allocate(tx, scheme.BranchID, model.NumberingKindInvoice, year)
+		if err != nil {
+			return err
+		}
+		payment.InvoiceNumber = scheme.Render(year, seq)
+		return tx.Create(payment).Error
+	})
+}
+
 // GetByID retrieves a payment by ID
 func (r *PaymentRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Payment, error) {
 	if r.inMemory {
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		p, ok := r.payments[id]
-		if !ok {
+		if !ok || !model.BranchScopeFromContext(ctx).Allows(p.Order.BranchID) {
 			return nil, gorm.ErrRecordNotFound
 		}
 		return p, nil
 	}
 	var payment model.Payment
 	err := r.db.WithContext(ctx).
+		Scopes(scopeByOrderBranch(ctx)).
 		Preload("Order").
 		Preload("Order.Customer").
 		Preload("Order.Branch").
@@ -80,7 +118,11 @@ func (r *PaymentRepository) GetByInvoiceNumber(ctx context.Context, invoiceNumbe
 	if r.inMemory {
 		r.mu.RLock()
 		defer r.mu.RUnlock()
+		scope := model.BranchScopeFromContext(ctx)
 		for _, p := range r.payments {
+			if !scope.Allows(p.Order.BranchID) {
+				continue
+			}
 			if p.InvoiceNumber == invoiceNumber {
 				return p, nil
 			}
@@ -89,6 +131,7 @@ func (r *PaymentRepository) GetByInvoiceNumber(ctx context.Context, invoiceNumbe
 	}
 	var payment model.Payment
 	err := r.db.WithContext(ctx).
+		Scopes(scopeByOrderBranch(ctx)).
 		Preload("Order").
 		Preload("Order.Customer").
 		Preload("Order.Branch").
@@ -123,19 +166,130 @@ func (r *PaymentRepository) GetByTransactionID(ctx context.Context, transactionI
 	return &payment, nil
 }
 
-// Update updates a payment
+// Update updates a payment. The write only applies while the stored payment still has
+// the version it was read at; otherwise it fails with ErrVersionConflict and nothing is
+// written. The payment's version is raised on success.
 func (r *PaymentRepository) Update(ctx context.Context, payment *model.Payment) error {
 	if r.inMemory {
 		r.mu.Lock()
 		defer r.mu.Unlock()
-		if _, ok := r.payments[payment.ID]; !ok {
+		stored, ok := r.payments[payment.ID]
+		if !ok {
 			return gorm.ErrRecordNotFound
 		}
+		if !model.BranchScopeFromContext(ctx).Allows(stored.Order.BranchID) {
+			return model.ErrForbidden
+		}
+		if stored.Version != payment.Version {
+			return model.ErrVersionConflict
+		}
+		payment.Version++
 		payment.UpdatedAt = time.Now()
 		r.payments[payment.ID] = payment
 		return nil
 	}
-	return r.db.WithContext(ctx).Save(payment).Error
+
+	if err := r.checkOrderBranch(ctx, payment); err != nil {
+		return err
+	}
+	return UpdateVersioned(r.db.WithContext(ctx), payment)
+}
+
+// UpdateVersioned writes a payment under the same version check as Update, within the given
+// transaction. It is meant for callers that change a payment together with other records.
+func UpdateVersioned(tx *gorm.DB, payment *model.Payment) error {
+	read := payment.Version
+	payment.Version = read + 1
+	result := tx.
+		Model(payment).
+		Where("version = ?", read).
+		Select("*").
+		Omit(clause.Associations).
+		Updates(payment)
+	if result.Error != nil || result.RowsAffected == 0 {
+		payment.Version = read
+		if result.Error != nil {
+			return result.Error
+		}
+		return model.ErrVersionConflict
+	}
+	return nil
+}
+
+// SettleCancellation saves a cancelled order together with the payments its cancellation
+// changed and the charge it creates, in one transaction: when any write fails, for example
+// because the order or a payment changed in the meantime, nothing is written. The charge is
+// numbered from the scheme like CreateInvoiced, and writes, when given, run last in the
+// same transaction. It reports whether the order was saved; without a database orders are
+// not kept here, so only the payments are written and the caller saves the order.
+func (r *PaymentRepository) SettleCancellation(ctx context.Context, order *model.ServiceOrder, changed []*model.Payment, charge *model.Payment, scheme *model.NumberScheme, writes orderRepo.TxWrites) (bool, error) {
+	if !model.BranchScopeFromContext(ctx).Allows(order.BranchID) {
+		return false, model.ErrForbidden
+	}
+	if r.inMemory {
+		for _, payment := range changed {
+			if err := r.Update(ctx, payment); err != nil {
+				return false, err
+			}
+		}
+		if charge != nil {
+			return false, r.CreateInvoiced(ctx, charge, scheme)
+		}
+		return false, nil
+	}
+
+	// Versions raised by writes that are rolled back are restored, so the caller can retry
+	versions := make(map[*model.Payment]int64, len(changed))
+	for _, payment := range changed {
+		versions[payment] = payment.Version
+	}
+	orderVersion := order.Version
+	if charge != nil && charge.Version == 0 {
+		charge.Version = 1
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := orderRepo.UpdateVersioned(tx, order); err != nil {
+			return err
+		}
+		for _, payment := range changed {
+			if err := UpdateVersioned(tx, payment); err != nil {
+				return err
+			}
+		}
+		if charge != nil {
+			if err := createCharge(tx, charge, scheme); err != nil {
+				return err
+			}
+		}
+		if writes != nil {
+			return writes(tx)
+		}
+		return nil
+	})
+	if err != nil {
+		order.Version = orderVersion
+		for payment, version := range versions {
+			payment.Version = version
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// createCharge numbers a cancellation charge and stores it within the given transaction
+func createCharge(tx *gorm.DB, charge *model.Payment, scheme *model.NumberScheme) error {
+	if scheme == nil {
+		charge.InvoiceNumber = utils.GenerateInvoiceNumber()
+		return tx.Create(charge).Error
+	}
+	year := time.Now().Year()
+	seq, err := numberingRepo.Allocate(tx, scheme.BranchID, model.NumberingKindInvoice, year)
+	if err != nil {
+		return err
+	}
+	charge.InvoiceNumber = scheme.Render(year, seq)
+	return tx.Create(charge).Error
 }
 
 // Delete soft deletes a payment
@@ -143,13 +297,13 @@ func (r *PaymentRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	if r.inMemory {
 		r.mu.Lock()
 		defer r.mu.Unlock()
-		if _, ok := r.payments[id]; !ok {
+		if p, ok := r.payments[id]; !ok || !model.BranchScopeFromContext(ctx).Allows(p.Order.BranchID) {
 			return gorm.ErrRecordNotFound
 		}
 		delete(r.payments, id)
 		return nil
 	}
-	return r.db.WithContext(ctx).Delete(&model.Payment{}, "id = ?", id).Error
+	return r.db.WithContext(ctx).Scopes(scopeByOrderBranch(ctx)).Delete(&model.Payment{}, "id = ?", id).Error
 }
 
 // List retrieves payments with pagination
@@ -158,11 +312,18 @@ func (r *PaymentRepository) List(ctx context.Context, offset, limit int, filters
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		var list []*model.Payment
+		scope := model.BranchScopeFromContext(ctx)
 		for _, p := range r.payments {
+			if !scope.Allows(p.Order.BranchID) {
+				continue
+			}
 			if filters != nil {
 				if filters.OrderID != nil && p.OrderID != *filters.OrderID {
 					continue
 				}
+				if filters.BranchID != nil && p.Order.BranchID != *filters.BranchID {
+					continue
+				}
 				if filters.Status != nil && p.Status != *filters.Status {
 					continue
 				}
@@ -186,12 +347,15 @@ func (r *PaymentRepository) List(ctx context.Context, offset, limit int, filters
 	var payments []*model.Payment
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&model.Payment{})
+	query := r.db.WithContext(ctx).Scopes(scopeByOrderBranch(ctx)).Model(&model.Payment{})
 
 	if filters != nil {
 		if filters.OrderID != nil {
 			query = query.Where("order_id = ?", *filters.OrderID)
 		}
+		if filters.BranchID != nil {
+			query = query.Where("order_id IN (SELECT id FROM service_orders WHERE branch_id = ?)", *filters.BranchID)
+		}
 		if filters.Status != nil {
 			query = query.Where("status = ?", *filters.Status)
 		}
@@ -229,7 +393,11 @@ func (r *PaymentRepository) GetByOrderID(ctx context.Context, orderID uuid.UUID)
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		var list []*model.Payment
+		scope := model.BranchScopeFromContext(ctx)
 		for _, p := range r.payments {
+			if !scope.Allows(p.Order.BranchID) {
+				continue
+			}
 			if p.OrderID == orderID {
 				list = append(list, p)
 			}
@@ -239,6 +407,7 @@ func (r *PaymentRepository) GetByOrderID(ctx context.Context, orderID uuid.UUID)
 	}
 	var payments []*model.Payment
 	err := r.db.WithContext(ctx).
+		Scopes(scopeByOrderBranch(ctx)).
 		Preload("Order").
 		Preload("Order.Customer").
 		Preload("Order.Branch").
@@ -254,7 +423,11 @@ func (r *PaymentRepository) GetByStatus(ctx context.Context, status model.Paymen
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		var list []*model.Payment
+		scope := model.BranchScopeFromContext(ctx)
 		for _, p := range r.payments {
+			if !scope.Allows(p.Order.BranchID) {
+				continue
+			}
 			if p.Status == status {
 				list = append(list, p)
 			}
@@ -264,6 +437,7 @@ func (r *PaymentRepository) GetByStatus(ctx context.Context, status model.Paymen
 	}
 	var payments []*model.Payment
 	err := r.db.WithContext(ctx).
+		Scopes(scopeByOrderBranch(ctx)).
 		Preload("Order").
 		Preload("Order.Customer").
 		Preload("Order.Branch").
@@ -305,7 +479,11 @@ func (r *PaymentRepository) GetTotalRevenueByDateRange(ctx context.Context, star
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		var total float64
+		scope := model.BranchScopeFromContext(ctx)
 		for _, p := range r.payments {
+			if !scope.Allows(p.Order.BranchID) {
+				continue
+			}
 			if !p.CreatedAt.Before(startDate) && !p.CreatedAt.After(endDate) && p.Status == model.PaymentStatusPaid {
 				total += p.Amount
 			}
@@ -313,7 +491,7 @@ func (r *PaymentRepository) GetTotalRevenueByDateRange(ctx context.Context, star
 		return total, nil
 	}
 	var total float64
-	err := r.db.WithContext(ctx).Model(&model.Payment{}).
+	err := r.db.WithContext(ctx).Scopes(scopeByOrderBranch(ctx)).Model(&model.Payment{}).
 		Select("COALESCE(SUM(amount), 0)").
 		Where("created_at >= ? AND created_at <= ? AND status = ?", startDate, endDate, model.PaymentStatusPaid).
 		Scan(&total).Error
@@ -330,7 +508,11 @@ func (r *PaymentRepository) GetRevenueByBranchInDateRange(ctx context.Context, s
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		m := make(map[string]float64)
+		scope := model.BranchScopeFromContext(ctx)
 		for _, p := range r.payments {
+			if !scope.Allows(p.Order.BranchID) {
+				continue
+			}
 			if !p.CreatedAt.Before(startDate) && !p.CreatedAt.After(endDate) && p.Status == model.PaymentStatusPaid {
 				m[p.OrderID.String()] += p.Amount
 			}
@@ -338,7 +520,7 @@ func (r *PaymentRepository) GetRevenueByBranchInDateRange(ctx context.Context, s
 		return m, nil
 	}
 
-	err := r.db.WithContext(ctx).Model(&model.Payment{}).
+	err := r.db.WithContext(ctx).Scopes(model.ScopeByBranch(ctx, "o.branch_id")).Model(&model.Payment{}).
 		Select("o.branch_id, COALESCE(SUM(p.amount), 0) as revenue").
 		Joins("JOIN service_orders o ON p.order_id = o.id").
 		Where("p.created_at >= ? AND p.created_at <= ? AND p.status = ?", startDate, endDate, model.PaymentStatusPaid).
@@ -367,7 +549,11 @@ func (r *PaymentRepository) GetRevenueByPaymentMethodInDateRange(ctx context.Con
 		r.mu.RLock()
 		defer r.mu.RUnlock()
 		m := make(map[string]float64)
+		scope := model.BranchScopeFromContext(ctx)
 		for _, p := range r.payments {
+			if !scope.Allows(p.Order.BranchID) {
+				continue
+			}
 			if !p.CreatedAt.Before(startDate) && !p.CreatedAt.After(endDate) && p.Status == model.PaymentStatusPaid {
 				m[string(p.PaymentMethod)] += p.Amount
 			}
@@ -375,7 +561,7 @@ func (r *PaymentRepository) GetRevenueByPaymentMethodInDateRange(ctx context.Con
 		return m, nil
 	}
 
-	err := r.db.WithContext(ctx).Model(&model.Payment{}).
+	err := r.db.WithContext(ctx).Scopes(scopeByOrderBranch(ctx)).Model(&model.Payment{}).
 		Select("payment_method, COALESCE(SUM(amount), 0) as revenue").
 		Where("created_at >= ? AND created_at <= ? AND status = ?", startDate, endDate, model.PaymentStatusPaid).
 		Group("payment_method").
@@ -393,9 +579,53 @@ func (r *PaymentRepository) GetRevenueByPaymentMethodInDateRange(ctx context.Con
 	return methodMap, nil
 }
 
+// checkOrderBranch returns ErrForbidden when the order a payment belongs to is outside the
+// caller's branch scope. The branch is read from the stored order rather than from the
+// payment's Order, which is empty unless it was preloaded.
+func (r *PaymentRepository) checkOrderBranch(ctx context.Context, payment *model.Payment) error {
+	scope := model.BranchScopeFromContext(ctx)
+	if !scope.IsRestricted() {
+		return nil
+	}
+	if r.inMemory {
+		if !scope.Allows(payment.Order.BranchID) {
+			return model.ErrForbidden
+		}
+		return nil
+	}
+
+	var order model.ServiceOrder
+	err := r.db.WithContext(ctx).Select("id", "branch_id").First(&order, "id = ?", payment.OrderID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.ErrOrderNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !scope.Allows(order.BranchID) {
+		return model.ErrForbidden
+	}
+	return nil
+}
+
+// scopeByOrderBranch restricts payments to orders inside the caller's branch scope
+func scopeByOrderBranch(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		scope := model.BranchScopeFromContext(ctx)
+		if !scope.IsRestricted() {
+			return db
+		}
+		if len(scope.BranchIDs) == 0 {
+			return db.Where("1 = 0")
+		}
+		return db.Where("order_id IN (SELECT id FROM service_orders WHERE branch_id IN ?)", scope.BranchIDs)
+	}
+}
+
 // PaymentFilters represents filters for payment queries
 type PaymentFilters struct {
 	OrderID       *uuid.UUID
+	BranchID      *uuid.UUID
 	Status        *model.PaymentStatus
 	PaymentMethod *model.PaymentMethod
 	DateFrom      *string
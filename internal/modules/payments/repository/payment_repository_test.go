@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"service/internal/shared/database/dbtest"
+	"service/internal/shared/model"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentRepositoryUpdateVersion(t *testing.T) {
+	ctx := context.Background()
+	branchID := uuid.New()
+	writeFailed := errors.New("write failed")
+	ownBranch := model.WithBranchScope(ctx, &model.BranchScope{BranchIDs: []uuid.UUID{branchID}})
+	otherBranch := model.WithBranchScope(ctx, &model.BranchScope{BranchIDs: []uuid.UUID{uuid.New()}})
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		order       *sqlmock.Rows // the stored order, read when the caller is limited to branches
+		matched     int64
+		writeErr    error
+		want        error
+		wantVersion int64
+	}{
+		{"current version", ctx, nil, 1, nil, nil, 4},
+		{"own branch", ownBranch, sqlmock.NewRows([]string{"id", "branch_id"}).AddRow(uuid.New(), branchID), 1, nil, nil, 4},
+		{"stale version", ctx, nil, 0, nil, model.ErrVersionConflict, 3},
+		{"failed write", ctx, nil, 0, writeFailed, writeFailed, 3},
+		{"other branch", otherBranch, sqlmock.NewRows([]string{"id", "branch_id"}).AddRow(uuid.New(), branchID), 0, nil, model.ErrForbidden, 3},
+		{"unknown order", ownBranch, sqlmock.NewRows([]string{"id", "branch_id"}), 0, nil, model.ErrOrderNotFound, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := dbtest.Mock(t)
+			repo := &PaymentRepository{db: db}
+			payment := &model.Payment{ID: uuid.New(), OrderID: uuid.New(), Amount: 150000, Status: model.PaymentStatusPaid, Version: 3}
+
+			if tt.order != nil {
+				mock.ExpectQuery(`SELECT "id","branch_id" FROM "service_orders" WHERE id = \$1`).
+					WithArgs(payment.OrderID, 1).
+					WillReturnRows(tt.order)
+			}
+			// Payments of orders outside the caller's branches are refused before anything is written
+			if tt.want != model.ErrForbidden && tt.want != model.ErrOrderNotFound {
+				mock.ExpectBegin()
+				update := dbtest.ExpectVersionedUpdate(mock, "payments", tt.matched)
+				if tt.writeErr != nil {
+					update.WillReturnError(tt.writeErr)
+					mock.ExpectRollback()
+				} else {
+					mock.ExpectCommit()
+				}
+			}
+
+			assert.Equal(t, tt.want, repo.Update(tt.ctx, payment))
+			assert.Equal(t, tt.wantVersion, payment.Version, "a failed write keeps the version it was read at")
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPaymentRepositoryCreateBranchScope(t *testing.T) {
+	branchID := uuid.New()
+	tests := []struct {
+		name  string
+		scope *model.BranchScope
+		want  error
+	}{
+		{"unscoped", nil, nil},
+		{"own branch", &model.BranchScope{BranchIDs: []uuid.UUID{branchID}}, nil},
+		{"other branch", &model.BranchScope{BranchIDs: []uuid.UUID{uuid.New()}}, model.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := model.WithBranchScope(context.Background(), tt.scope)
+			payment := &model.Payment{OrderID: uuid.New(), Order: model.ServiceOrder{BranchID: branchID}, Amount: 50000}
+			assert.Equal(t, tt.want, NewPaymentRepository().Create(ctx, payment))
+		})
+	}
+}
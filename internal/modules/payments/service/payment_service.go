@@ -3,19 +3,29 @@ package service
 import (
 	"context"
 	"errors"
+	numberingService "service/internal/modules/numbering/service"
 	"service/internal/modules/orders/repository"
 	pay "service/internal/modules/payments/legacy_payment"
 	repo "service/internal/modules/payments/repository"
+	slaService "service/internal/modules/sla/service"
 	"service/internal/shared/model"
 	"service/internal/shared/utils"
 
 	"github.com/google/uuid"
 )
 
+// orderUpdateAttempts is how often a paid order is reloaded and marked ready when it was
+// changed concurrently
+const orderUpdateAttempts = 3
+
 // PaymentService handles payment business logic
 type PaymentService struct {
 	paymentRepo *repo.PaymentRepository
 	orderRepo   *repository.ServiceOrderRepository
+	eventRepo   *repository.StatusEventRepository
+	slaService  *slaService.SLAService
+
+	numberingService *numberingService.NumberingService
 }
 
 // NewPaymentService creates a new payment service
@@ -23,6 +33,10 @@ func NewPaymentService() *PaymentService {
 	return &PaymentService{
 		paymentRepo: repo.NewPaymentRepository(),
 		orderRepo:   repository.NewServiceOrderRepository(),
+		eventRepo:   repository.NewStatusEventRepository(),
+		slaService:  slaService.NewSLAService(),
+
+		numberingService: numberingService.NewNumberingService(),
 	}
 }
 
@@ -83,16 +97,34 @@ func (s *PaymentService) HandleMidtransCallback(ctx context.Context, cb *model.M
 		return err
 	}
 
-	// Update order on success
+	// Update order on success, reloading it when staff changed it meanwhile
 	if mapped == model.PaymentStatusPaid {
-		if order, err := s.orderRepo.GetByID(ctx, payment.OrderID); err == nil {
+		for attempt := 0; attempt < orderUpdateAttempts; attempt++ {
+			order, err := s.orderRepo.GetByID(ctx, payment.OrderID)
+			if err != nil {
+				break
+			}
 			order.Status = model.StatusReady
-			_ = s.orderRepo.Update(ctx, order)
+			err = s.orderRepo.Update(ctx, order)
+			if err == nil {
+				s.recordReady(ctx, order)
+			}
+			if err != model.ErrVersionConflict {
+				break
+			}
 		}
 	}
 	return nil
 }
 
+// recordReady adds the paid order's new status to its history and starts its delivery SLA
+func (s *PaymentService) recordReady(ctx context.Context, order *model.ServiceOrder) {
+	if s.eventRepo.Available() {
+		_ = s.eventRepo.Record(ctx, order.ID, order.Status)
+	}
+	s.slaService.OnStatusChange(ctx, order)
+}
+
 // CreatePayment creates a new payment
 func (s *PaymentService) CreatePayment(ctx context.Context, req *model.PaymentRequest) (*model.PaymentResponse, error) {
 	// Validate order exists
@@ -101,23 +133,21 @@ func (s *PaymentService) CreatePayment(ctx context.Context, req *model.PaymentRe
 		return nil, errors.New("invalid order ID")
 	}
 
-	_, err = s.orderRepo.GetByID(ctx, orderID)
+	order, err := s.orderRepo.GetByID(ctx, orderID)
 	if err != nil {
 		return nil, model.ErrOrderNotFound
 	}
 
-	// Generate unique invoice number
-	invoiceNumber := utils.GenerateInvoiceNumber()
+	// Work order lines are paid through the corporate account's monthly invoice
+	if order.WorkOrderID != nil {
+		return nil, model.ErrOrderBilledToAccount
+	}
 
-	// Check if invoice number already exists (very unlikely but safety check)
-	exists, err := s.paymentRepo.CheckInvoiceExists(ctx, invoiceNumber, nil)
+	// Invoices are numbered per branch
+	scheme, err := s.numberingService.InvoiceScheme(ctx, order.BranchID)
 	if err != nil {
 		return nil, err
 	}
-	if exists {
-		// Regenerate if exists
-		invoiceNumber = utils.GenerateInvoiceNumber()
-	}
 
 	// Create payment entity
 	payment := &model.Payment{
@@ -125,12 +155,11 @@ func (s *PaymentService) CreatePayment(ctx context.Context, req *model.PaymentRe
 		Amount:        req.Amount,
 		PaymentMethod: req.PaymentMethod,
 		Status:        model.PaymentStatusPending,
-		InvoiceNumber: invoiceNumber,
 		Notes:         req.Notes,
 	}
 
-	// Save to database
-	if err := s.paymentRepo.Create(ctx, payment); err != nil {
+	// Save to database under the next invoice number
+	if err := s.paymentRepo.CreateInvoiced(ctx, payment, scheme); err != nil {
 		return nil, err
 	}
 
@@ -161,13 +190,17 @@ func (s *PaymentService) GetPaymentByInvoice(ctx context.Context, invoiceNumber
 	return &response, nil
 }
 
-// UpdatePaymentStatus updates the status of a payment
-func (s *PaymentService) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, status model.PaymentStatus, transactionID string) (*model.PaymentResponse, error) {
+// UpdatePaymentStatus updates the status of a payment. When expectedVersion is set the
+// payment must still be at that version.
+func (s *PaymentService) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, status model.PaymentStatus, transactionID string, expectedVersion *int64) (*model.PaymentResponse, error) {
 	// Get existing payment
 	payment, err := s.paymentRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, model.ErrPaymentNotFound
 	}
+	if err := utils.CheckVersion(payment.Version, expectedVersion); err != nil {
+		return nil, err
+	}
 
 	// Update status
 	payment.Status = status
@@ -198,13 +231,21 @@ func (s *PaymentService) ProcessMidtransPayment(ctx context.Context, req *model.
 		return nil, errors.New("invalid order ID")
 	}
 
-	_, err = s.orderRepo.GetByID(ctx, orderID)
+	order, err := s.orderRepo.GetByID(ctx, orderID)
 	if err != nil {
 		return nil, model.ErrOrderNotFound
 	}
 
-	// Generate invoice number
-	invoiceNumber := utils.GenerateInvoiceNumber()
+	// Work order lines are paid through the corporate account's monthly invoice
+	if order.WorkOrderID != nil {
+		return nil, model.ErrOrderBilledToAccount
+	}
+
+	// Invoices are numbered per branch
+	scheme, err := s.numberingService.InvoiceScheme(ctx, order.BranchID)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create payment record
 	payment := &model.Payment{
@@ -212,11 +253,10 @@ func (s *PaymentService) ProcessMidtransPayment(ctx context.Context, req *model.
 		Amount:        req.Amount,
 		PaymentMethod: model.PaymentMethodMidtrans,
 		Status:        model.PaymentStatusPending,
-		InvoiceNumber: invoiceNumber,
 	}
 
-	// Save payment
-	if err := s.paymentRepo.Create(ctx, payment); err != nil {
+	// Save payment under the next invoice number
+	if err := s.paymentRepo.CreateInvoiced(ctx, payment, scheme); err != nil {
 		return nil, err
 	}
 
@@ -279,7 +319,9 @@ func (s *PaymentService) ReconcilePendingPayments(ctx context.Context) error {
 		if newStatus == model.PaymentStatusPaid {
 			if order, err := s.orderRepo.GetByID(ctx, p.OrderID); err == nil {
 				order.Status = model.StatusReady
-				_ = s.orderRepo.Update(ctx, order)
+				if err := s.orderRepo.Update(ctx, order); err == nil {
+					s.recordReady(ctx, order)
+				}
 			}
 		}
 	}
@@ -365,8 +407,103 @@ func (s *PaymentService) GetPaymentsByStatus(ctx context.Context, status model.P
 // PaymentFilters represents filters for payment queries
 type PaymentFilters struct {
 	OrderID       *uuid.UUID
+	BranchID      *uuid.UUID
 	Status        *model.PaymentStatus
 	PaymentMethod *model.PaymentMethod
 	DateFrom      *string
 	DateTo        *string
 }
+
+// CancellationSettlement is the outcome of settling the payments of a cancelled order
+type CancellationSettlement struct {
+	PaidAmount      float64    // paid by the customer before the order was cancelled
+	RefundAmount    float64    // paid beyond the cancellation fee, owed back to the customer
+	ChargePaymentID *uuid.UUID // payment that carries the cancellation fee, if any
+	OrderSaved      bool       // the order was saved with the payments
+}
+
+// SettleCancellation settles the payments of a cancelled order against its cancellation
+// fee. Pending payments are cancelled. When the customer paid less than the fee, a pending
+// charge for the remainder is created. When they paid more, the paid payments are marked
+// refunded and the retained fee is kept as a paid payment of its own, so revenue reports
+// only count the fee. Refunds are only made when allowRefund is set; otherwise nothing is
+// changed and ErrCancellationRefundDenied is returned.
+//
+// The order must already carry its cancelled status. It is saved in the same transaction
+// as the payments, together with the given writes, so a failure, such as the order having
+// changed since it was read, leaves the order, its payments and the writes undone.
+func (s *PaymentService) SettleCancellation(ctx context.Context, order *model.ServiceOrder, fee float64, allowRefund bool, writes repository.TxWrites) (*CancellationSettlement, error) {
+	payments, err := s.paymentRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	settlement := &CancellationSettlement{}
+	var paid, changed []*model.Payment
+	for _, payment := range payments {
+		if payment.Status == model.PaymentStatusPaid {
+			settlement.PaidAmount += payment.Amount
+			paid = append(paid, payment)
+		}
+	}
+	if settlement.PaidAmount > fee && !allowRefund {
+		return nil, model.ErrCancellationRefundDenied
+	}
+
+	// Statuses are only changed on copies, so a failed settlement leaves the payments as read
+	for _, payment := range payments {
+		if payment.Status == model.PaymentStatusPending {
+			cancelled := *payment
+			cancelled.Status = model.PaymentStatusCancelled
+			changed = append(changed, &cancelled)
+		}
+	}
+
+	var charge *model.Payment
+	switch {
+	case settlement.PaidAmount < fee:
+		charge = &model.Payment{
+			OrderID:       order.ID,
+			UserID:        order.CustomerID,
+			Amount:        fee - settlement.PaidAmount,
+			PaymentMethod: model.PaymentMethodCash,
+			Status:        model.PaymentStatusPending,
+			Notes:         "Cancellation fee",
+		}
+	case settlement.PaidAmount > fee:
+		// The fee is retained from what was paid and the rest is refunded
+		settlement.RefundAmount = settlement.PaidAmount - fee
+		for _, payment := range paid {
+			refunded := *payment
+			refunded.Status = model.PaymentStatusRefunded
+			changed = append(changed, &refunded)
+		}
+		if fee > 0 {
+			now := model.GetCurrentTimestamp()
+			charge = &model.Payment{
+				OrderID:       order.ID,
+				UserID:        order.CustomerID,
+				Amount:        fee,
+				PaymentMethod: paid[0].PaymentMethod,
+				Status:        model.PaymentStatusPaid,
+				PaidAt:        &now,
+				Notes:         "Cancellation fee retained from payment " + paid[0].InvoiceNumber,
+			}
+		}
+	}
+
+	var scheme *model.NumberScheme
+	if charge != nil {
+		if scheme, err = s.numberingService.InvoiceScheme(ctx, order.BranchID); err != nil {
+			return nil, err
+		}
+	}
+	settlement.OrderSaved, err = s.paymentRepo.SettleCancellation(ctx, order, changed, charge, scheme, writes)
+	if err != nil {
+		return nil, err
+	}
+	if charge != nil {
+		settlement.ChargePaymentID = &charge.ID
+	}
+	return settlement, nil
+}
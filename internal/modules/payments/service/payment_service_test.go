@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"service/internal/shared/model"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettleCancellation(t *testing.T) {
+	type stored struct {
+		amount float64
+		status model.PaymentStatus
+	}
+	tests := []struct {
+		name         string
+		fee          float64
+		payments     []stored
+		allowRefund  bool
+		wantErr      error
+		wantPaid     float64
+		wantRefund   float64
+		wantCharge   *stored
+		wantStatuses []model.PaymentStatus
+	}{
+		{
+			name:         "no fee before pickup refunds everything",
+			fee:          0,
+			payments:     []stored{{200000, model.PaymentStatusPaid}},
+			allowRefund:  true,
+			wantPaid:     200000,
+			wantRefund:   200000,
+			wantStatuses: []model.PaymentStatus{model.PaymentStatusRefunded},
+		},
+		{
+			name:         "fee retained from what was paid",
+			fee:          50000,
+			payments:     []stored{{200000, model.PaymentStatusPaid}},
+			allowRefund:  true,
+			wantPaid:     200000,
+			wantRefund:   150000,
+			wantCharge:   &stored{50000, model.PaymentStatusPaid},
+			wantStatuses: []model.PaymentStatus{model.PaymentStatusRefunded},
+		},
+		{
+			name:         "refund without the grant",
+			fee:          50000,
+			payments:     []stored{{200000, model.PaymentStatusPaid}},
+			wantErr:      model.ErrCancellationRefundDenied,
+			wantStatuses: []model.PaymentStatus{model.PaymentStatusPaid},
+		},
+		{
+			name:         "fee charged when nothing was paid",
+			fee:          50000,
+			payments:     []stored{{200000, model.PaymentStatusPending}},
+			wantCharge:   &stored{50000, model.PaymentStatusPending},
+			wantStatuses: []model.PaymentStatus{model.PaymentStatusCancelled},
+		},
+		{
+			name:         "rest of the fee charged",
+			fee:          50000,
+			payments:     []stored{{20000, model.PaymentStatusPaid}},
+			wantPaid:     20000,
+			wantCharge:   &stored{30000, model.PaymentStatusPending},
+			wantStatuses: []model.PaymentStatus{model.PaymentStatusPaid},
+		},
+		{
+			name:         "paid exactly the fee needs no refund",
+			fee:          50000,
+			payments:     []stored{{50000, model.PaymentStatusPaid}},
+			wantPaid:     50000,
+			wantStatuses: []model.PaymentStatus{model.PaymentStatusPaid},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := NewPaymentService()
+			order := &model.ServiceOrder{ID: uuid.New(), CustomerID: uuid.New(), BranchID: uuid.New(), Status: model.StatusCancelled}
+
+			var ids []uuid.UUID
+			for _, p := range tt.payments {
+				payment := &model.Payment{
+					OrderID:       order.ID,
+					Order:         *order,
+					UserID:        order.CustomerID,
+					Amount:        p.amount,
+					Status:        p.status,
+					PaymentMethod: model.PaymentMethodCash,
+				}
+				assert.NoError(t, s.paymentRepo.Create(ctx, payment))
+				ids = append(ids, payment.ID)
+			}
+
+			settlement, err := s.SettleCancellation(ctx, order, tt.fee, tt.allowRefund, nil)
+			assert.Equal(t, tt.wantErr, err)
+			for i, id := range ids {
+				payment, _ := s.paymentRepo.GetByID(ctx, id)
+				assert.Equal(t, tt.wantStatuses[i], payment.Status)
+			}
+			if err != nil {
+				return
+			}
+
+			assert.Equal(t, tt.wantPaid, settlement.PaidAmount)
+			assert.Equal(t, tt.wantRefund, settlement.RefundAmount)
+			assert.False(t, settlement.OrderSaved, "without a database the caller saves the order")
+			if tt.wantCharge == nil {
+				assert.Nil(t, settlement.ChargePaymentID)
+				return
+			}
+			charge, err := s.paymentRepo.GetByID(ctx, *settlement.ChargePaymentID)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCharge.amount, charge.Amount)
+			assert.Equal(t, tt.wantCharge.status, charge.Status)
+		})
+	}
+}
@@ -0,0 +1,379 @@
+package handler
+
+import (
+	"net/http"
+	privacyRepository "service/internal/modules/privacy/repository"
+	"service/internal/modules/privacy/service"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PrivacyHandler handles personal data export and erasure endpoints
+type PrivacyHandler struct {
+	privacyService *service.PrivacyService
+}
+
+// NewPrivacyHandler creates a new privacy handler
+func NewPrivacyHandler() *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: service.NewPrivacyService(),
+	}
+}
+
+// CreateMyRequest godoc
+// @Summary Request data export or erasure
+// @Description Request a machine-readable export of your personal data, or erasure of your account. Erasure requests are reviewed before they are carried out.
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.DataSubjectRequestRequest true "Request data"
+// @Success 201 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /privacy/requests [post]
+func (h *PrivacyHandler) CreateMyRequest(c *gin.Context) {
+	userUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	req, ok := bindDataSubjectRequest(c)
+	if !ok {
+		return
+	}
+
+	request, err := h.privacyService.CreateRequest(c.Request.Context(), userUUID, userUUID, false, req)
+	if err != nil {
+		respondCreateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse(request, "Request submitted successfully"))
+}
+
+// ListMyRequests godoc
+// @Summary List my data requests
+// @Description List your personal data export and erasure requests
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} model.PaginatedResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /privacy/requests [get]
+func (h *PrivacyHandler) ListMyRequests(c *gin.Context) {
+	userUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	h.listRequests(c, &privacyRepository.RequestFilters{UserID: &userUUID})
+}
+
+// DownloadMyExport godoc
+// @Summary Download data export
+// @Description Get a short-lived download link for a completed export archive
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Request ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /privacy/requests/{id}/download [get]
+func (h *PrivacyHandler) DownloadMyExport(c *gin.Context) {
+	userUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid request ID format",
+			nil,
+		))
+		return
+	}
+
+	download, err := h.privacyService.GetDownloadURL(c.Request.Context(), id, &userUUID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err {
+		case model.ErrDataRequestNotFound:
+			status = http.StatusNotFound
+		case model.ErrExportNotReady:
+			status = http.StatusConflict
+		}
+		c.JSON(status, model.CreateErrorResponse("export_download_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(download, "Download link generated successfully"))
+}
+
+// CreateRequest godoc
+// @Summary File data request for a user
+// @Description File an export or erasure request on behalf of a user. Requests filed by an administrator are approved immediately.
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.DataSubjectRequestRequest true "Request data"
+// @Success 201 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /admin/privacy/requests [post]
+func (h *PrivacyHandler) CreateRequest(c *gin.Context) {
+	adminUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	req, ok := bindDataSubjectRequest(c)
+	if !ok {
+		return
+	}
+
+	userUUID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"user_id is required",
+			nil,
+		))
+		return
+	}
+
+	request, err := h.privacyService.CreateRequest(c.Request.Context(), userUUID, adminUUID, true, req)
+	if err != nil {
+		respondCreateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse(request, "Request created successfully"))
+}
+
+// ListRequests godoc
+// @Summary List data requests
+// @Description List personal data export and erasure requests of all users
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param user_id query string false "Filter by user ID"
+// @Param type query string false "Filter by type (export, erasure)"
+// @Param status query string false "Filter by status (pending, approved, processing, completed, failed, rejected)"
+// @Success 200 {object} model.PaginatedResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /admin/privacy/requests [get]
+func (h *PrivacyHandler) ListRequests(c *gin.Context) {
+	filters := &privacyRepository.RequestFilters{
+		Type:   model.DataSubjectRequestType(c.Query("type")),
+		Status: model.DataSubjectRequestStatus(c.Query("status")),
+	}
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		if id, err := uuid.Parse(userIDStr); err == nil {
+			filters.UserID = &id
+		}
+	}
+
+	h.listRequests(c, filters)
+}
+
+// ApproveRequest godoc
+// @Summary Approve data request
+// @Description Approve a pending request so the background job carries it out
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Request ID"
+// @Param request body model.ReviewDataSubjectRequest false "Review notes"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /admin/privacy/requests/{id}/approve [post]
+func (h *PrivacyHandler) ApproveRequest(c *gin.Context) {
+	h.reviewRequest(c, true)
+}
+
+// RejectRequest godoc
+// @Summary Reject data request
+// @Description Reject a pending request
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Request ID"
+// @Param request body model.ReviewDataSubjectRequest false "Review notes"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /admin/privacy/requests/{id}/reject [post]
+func (h *PrivacyHandler) RejectRequest(c *gin.Context) {
+	h.reviewRequest(c, false)
+}
+
+// reviewRequest approves or rejects a pending request
+func (h *PrivacyHandler) reviewRequest(c *gin.Context, approve bool) {
+	adminUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid request ID format",
+			nil,
+		))
+		return
+	}
+
+	// The review notes are optional, so an empty body is accepted
+	var req model.ReviewDataSubjectRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+				"validation_error",
+				"Invalid request data",
+				err.Error(),
+			))
+			return
+		}
+		utils.SanitizeStructStrings(&req)
+	}
+
+	var request *model.DataSubjectRequest
+	if approve {
+		request, err = h.privacyService.ApproveRequest(c.Request.Context(), id, adminUUID, req.Notes)
+	} else {
+		request, err = h.privacyService.RejectRequest(c.Request.Context(), id, adminUUID, req.Notes)
+	}
+	if err != nil {
+		status := http.StatusBadRequest
+		switch err {
+		case model.ErrDataRequestNotFound:
+			status = http.StatusNotFound
+		case model.ErrDataRequestState, model.ErrActiveOrdersExist:
+			status = http.StatusConflict
+		}
+		c.JSON(status, model.CreateErrorResponse("privacy_request_review_failed", err.Error(), nil))
+		return
+	}
+
+	message := "Request rejected successfully"
+	if approve {
+		message = "Request approved successfully"
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(request, message))
+}
+
+// listRequests responds with a page of requests matching the filters
+func (h *PrivacyHandler) listRequests(c *gin.Context, filters *privacyRepository.RequestFilters) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	requests, total, err := h.privacyService.ListRequests(c.Request.Context(), page, limit, filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+			"privacy_requests_fetch_failed",
+			err.Error(),
+			nil,
+		))
+		return
+	}
+
+	pagination := model.PaginationResponse{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}
+
+	c.JSON(http.StatusOK, model.PaginatedSuccessResponse(requests, pagination, "Requests retrieved successfully"))
+}
+
+// currentUserID reads the authenticated user ID, writing an error response when it is missing
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, model.CreateErrorResponse(
+			"unauthorized",
+			"User not authenticated",
+			nil,
+		))
+		return uuid.Nil, false
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+			"internal_error",
+			"Invalid user ID type",
+			nil,
+		))
+		return uuid.Nil, false
+	}
+
+	return userUUID, true
+}
+
+// bindDataSubjectRequest binds and validates the request payload
+func bindDataSubjectRequest(c *gin.Context) (*model.DataSubjectRequestRequest, bool) {
+	var req model.DataSubjectRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return nil, false
+	}
+
+	utils.SanitizeStructStrings(&req)
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return nil, false
+	}
+
+	return &req, true
+}
+
+// respondCreateError maps request creation errors to HTTP responses
+func respondCreateError(c *gin.Context, err error) {
+	status := http.StatusBadRequest
+	switch err {
+	case model.ErrDataRequestExists, model.ErrActiveOrdersExist:
+		status = http.StatusConflict
+	}
+	c.JSON(status, model.CreateErrorResponse("privacy_request_failed", err.Error(), nil))
+}
@@ -0,0 +1,430 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// PrivacyRepository handles data subject requests and the personal data they cover
+type PrivacyRepository struct {
+	db *gorm.DB
+}
+
+// NewPrivacyRepository creates a new privacy repository
+func NewPrivacyRepository() *PrivacyRepository {
+	return &PrivacyRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *PrivacyRepository) Available() bool {
+	return r.db != nil
+}
+
+// CreateRequest creates a new data subject request
+func (r *PrivacyRepository) CreateRequest(ctx context.Context, req *model.DataSubjectRequest) error {
+	return r.db.WithContext(ctx).Create(req).Error
+}
+
+// GetRequestByID retrieves a data subject request by ID
+func (r *PrivacyRepository) GetRequestByID(ctx context.Context, id uuid.UUID) (*model.DataSubjectRequest, error) {
+	var req model.DataSubjectRequest
+	err := r.db.WithContext(ctx).First(&req, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// ListRequests retrieves data subject requests with filters
+func (r *PrivacyRepository) ListRequests(ctx context.Context, offset, limit int, filters *RequestFilters) ([]*model.DataSubjectRequest, int64, error) {
+	var requests []*model.DataSubjectRequest
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.DataSubjectRequest{})
+
+	// Apply filters
+	if filters != nil {
+		if filters.UserID != nil {
+			query = query.Where("user_id = ?", *filters.UserID)
+		}
+		if filters.Type != "" {
+			query = query.Where("type = ?", filters.Type)
+		}
+		if filters.Status != "" {
+			query = query.Where("status = ?", filters.Status)
+		}
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get paginated results
+	err := query.
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&requests).Error
+
+	return requests, total, err
+}
+
+// UpdateRequest updates a data subject request
+func (r *PrivacyRepository) UpdateRequest(ctx context.Context, req *model.DataSubjectRequest) error {
+	return r.db.WithContext(ctx).Save(req).Error
+}
+
+// HasOpenRequest reports whether the user already has an unfinished request of the given type
+func (r *PrivacyRepository) HasOpenRequest(ctx context.Context, userID uuid.UUID, requestType model.DataSubjectRequestType) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.DataSubjectRequest{}).
+		Where("user_id = ? AND type = ? AND status IN ?", userID, requestType, []model.DataSubjectRequestStatus{
+			model.DataSubjectRequestPending,
+			model.DataSubjectRequestApproved,
+			model.DataSubjectRequestProcessing,
+		}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListApproved retrieves approved requests waiting to be processed, oldest first
+func (r *PrivacyRepository) ListApproved(ctx context.Context, limit int) ([]*model.DataSubjectRequest, error) {
+	var requests []*model.DataSubjectRequest
+	err := r.db.WithContext(ctx).
+		Where("status = ?", model.DataSubjectRequestApproved).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&requests).Error
+	return requests, err
+}
+
+// MarkProcessing moves an approved request to processing.
+// It returns false when another worker claimed the request first.
+func (r *PrivacyRepository) MarkProcessing(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.DataSubjectRequest{}).
+		Where("id = ? AND status = ?", id, model.DataSubjectRequestApproved).
+		Updates(map[string]interface{}{
+			"status":     model.DataSubjectRequestProcessing,
+			"started_at": time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ClearArchives forgets the export archives of a user after they were removed from storage
+func (r *PrivacyRepository) ClearArchives(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&model.DataSubjectRequest{}).
+		Where("user_id = ? AND type = ? AND archive_object <> ''", userID, model.DataSubjectRequestExport).
+		Updates(map[string]interface{}{
+			"archive_object": "",
+			"archive_size":   0,
+		}).Error
+}
+
+// CountActiveOrders counts the customer's orders that are still being serviced
+func (r *PrivacyRepository) CountActiveOrders(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.ServiceOrder{}).
+		Where("customer_id = ? AND status NOT IN ?", userID, []model.OrderStatus{model.StatusCompleted, model.StatusCancelled}).
+		Count(&count).Error
+	return count, err
+}
+
+// GetCustomerOrders retrieves every order placed by the customer, including soft-deleted ones
+func (r *PrivacyRepository) GetCustomerOrders(ctx context.Context, userID uuid.UUID) ([]*model.ServiceOrder, error) {
+	var orders []*model.ServiceOrder
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("customer_id = ?", userID).
+		Order("created_at ASC").
+		Find(&orders).Error
+	return orders, err
+}
+
+// CollectUserData gathers all personal data linked to a user
+func (r *PrivacyRepository) CollectUserData(ctx context.Context, userID uuid.UUID) (*model.PersonalDataExport, error) {
+	db := r.db.WithContext(ctx)
+
+	var user model.User
+	if err := db.First(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+
+	export := &model.PersonalDataExport{
+		GeneratedAt: time.Now(),
+		User: model.UserDataExport{
+			ID:          user.ID,
+			Email:       user.Email,
+			FullName:    user.FullName,
+			Phone:       user.Phone,
+			Role:        user.Role,
+			AvatarURL:   user.AvatarURL,
+			BranchID:    user.BranchID,
+			LastLoginAt: user.LastLoginAt,
+			CreatedAt:   user.CreatedAt,
+			UpdatedAt:   user.UpdatedAt,
+		},
+		Orders:        []model.OrderDataExport{},
+		Payments:      []model.PaymentDataExport{},
+		ChatMessages:  []model.ChatMessageDataExport{},
+		Notifications: []model.NotificationDataExport{},
+		Ratings:       []model.RatingDataExport{},
+		Devices:       []model.UserDeviceResponse{},
+		Files:         []string{},
+
+		RegisteredDevices: []model.CustomerDeviceResponse{},
+		Appointments:      []model.AppointmentResponse{},
+	}
+
+	orders, err := r.GetCustomerOrders(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range orders {
+		export.Orders = append(export.Orders, model.NewOrderDataExport(o))
+	}
+
+	var payments []model.Payment
+	if err := db.Where("user_id = ?", userID).Order("created_at ASC").Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	for _, p := range payments {
+		export.Payments = append(export.Payments, model.PaymentDataExport{
+			ID:            p.ID,
+			OrderID:       p.OrderID,
+			InvoiceNumber: p.InvoiceNumber,
+			Amount:        p.Amount,
+			PaymentMethod: p.PaymentMethod,
+			Status:        p.Status,
+			PaidAt:        p.PaidAt,
+			CreatedAt:     p.CreatedAt,
+		})
+	}
+
+	var messages []model.ChatMessage
+	if err := db.Where("sender_id = ? OR receiver_id = ?", userID, userID).Order("created_at ASC").Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	for _, m := range messages {
+		export.ChatMessages = append(export.ChatMessages, model.ChatMessageDataExport{
+			ID:         m.ID,
+			OrderID:    m.OrderID,
+			SenderID:   m.SenderID,
+			ReceiverID: m.ReceiverID,
+			Message:    m.Message,
+			CreatedAt:  m.CreatedAt,
+		})
+	}
+
+	var notifications []model.Notification
+	if err := db.Where("user_id = ?", userID).Order("created_at ASC").Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+	for _, n := range notifications {
+		export.Notifications = append(export.Notifications, model.NotificationDataExport{
+			ID:        n.ID,
+			OrderID:   n.OrderID,
+			Type:      n.Type,
+			Title:     n.Title,
+			Message:   n.Message,
+			CreatedAt: n.CreatedAt,
+		})
+	}
+
+	var ratings []model.Rating
+	if err := db.Where("customer_id = ?", userID).Order("created_at ASC").Find(&ratings).Error; err != nil {
+		return nil, err
+	}
+	for _, rt := range ratings {
+		export.Ratings = append(export.Ratings, model.RatingDataExport{
+			ID:        rt.ID,
+			OrderID:   rt.OrderID,
+			Rating:    rt.Rating,
+			Review:    rt.Review,
+			IsPublic:  rt.IsPublic,
+			CreatedAt: rt.CreatedAt,
+		})
+	}
+
+	var devices []*model.UserDevice
+	if err := db.Where("user_id = ?", userID).Order("created_at ASC").Find(&devices).Error; err != nil {
+		return nil, err
+	}
+	for _, d := range devices {
+		export.Devices = append(export.Devices, d.ToResponse(""))
+	}
+
+	var customerDevices []*model.CustomerDevice
+	if err := db.Preload("DeviceModel").Where("customer_id = ?", userID).Order("created_at ASC").Find(&customerDevices).Error; err != nil {
+		return nil, err
+	}
+	for _, d := range customerDevices {
+		export.RegisteredDevices = append(export.RegisteredDevices, d.ToResponse())
+	}
+
+	var appointments []*model.Appointment
+	if err := db.Preload("Slot").Where("customer_id = ?", userID).Order("created_at ASC").Find(&appointments).Error; err != nil {
+		return nil, err
+	}
+	for _, a := range appointments {
+		export.Appointments = append(export.Appointments, a.ToResponse())
+	}
+
+	var membership model.Membership
+	if err := db.Where("user_id = ?", userID).First(&membership).Error; err == nil {
+		response := membership.ToResponse()
+		export.Membership = &response
+	}
+
+	return export, nil
+}
+
+// AnonymizeUser erases the personal data of a user in one transaction.
+// Financial records (order amounts, tax, invoice numbers and payments) are kept for tax
+// retention; the buyer's NPWP is only kept on orders that were invoiced.
+func (r *PrivacyRepository) AnonymizeUser(ctx context.Context, userID uuid.UUID) error {
+	// The account keeps a random unusable password so it can never be logged into again
+	password, err := bcrypt.GenerateFromPassword([]byte(uuid.New().String()), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"email":       fmt.Sprintf("erased-%s@anonymized.invalid", userID),
+			"full_name":   "Erased User",
+			"phone":       "",
+			"password":    string(password),
+			"avatar_url":  "",
+			"mfa_enabled": false,
+			"mfa_secret":  "",
+			"is_active":   false,
+			"status":      model.UserStatusInactive,
+
+			"email_verified_at": nil,
+			"phone_verified_at": nil,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&model.ContactVerification{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&model.UserDevice{}).Error; err != nil {
+			return err
+		}
+
+		// Registered devices carry IMEIs and serial numbers, so they are removed for good
+		if err := tx.Unscoped().Where("customer_id = ?", userID).Delete(&model.CustomerDevice{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.Appointment{}).Where("customer_id = ?", userID).Updates(map[string]interface{}{
+			"description":   "",
+			"cancel_reason": "",
+		}).Error; err != nil {
+			return err
+		}
+
+		orderUpdates := map[string]interface{}{
+			"i_phone_imei":     "",
+			"pickup_address":   "",
+			"pickup_location":  "",
+			"pickup_latitude":  0,
+			"pickup_longitude": 0,
+			"customer_id_card": "",
+			"pickup_photo":     "",
+			"service_photo":    "",
+			"delivery_photo":   "",
+			"notes":            "",
+			"version":          gorm.Expr("version + 1"),
+		}
+		if err := tx.Unscoped().Model(&model.ServiceOrder{}).Where("customer_id = ?", userID).Updates(orderUpdates).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&model.ServiceOrder{}).
+			Where("customer_id = ? AND (invoice_number IS NULL OR invoice_number = '')", userID).
+			Update("customer_npwp", "").Error; err != nil {
+			return err
+		}
+
+		// Handovers and courier trails show where the customer lives. Handover proofs and
+		// attempts are kept as evidence without their location and notes. The files of the
+		// orders are removed from storage, so the references to handover photos, signatures
+		// and gallery photos go too.
+		orders := tx.Unscoped().Model(&model.ServiceOrder{}).Select("id").Where("customer_id = ?", userID)
+		if err := tx.Model(&model.HandoverProof{}).Where("order_id IN (?)", orders).Updates(map[string]interface{}{
+			"photo_object":     "",
+			"signature_object": "",
+			"latitude":         0,
+			"longitude":        0,
+			"accuracy":         0,
+			"notes":            "",
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.HandoverAttempt{}).Where("order_id IN (?)", orders).Updates(map[string]interface{}{
+			"latitude":  0,
+			"longitude": 0,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("order_id IN (?) OR courier_id = ?", orders, userID).Delete(&model.CourierLocation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("order_id IN (?)", orders).Delete(&model.OrderMedia{}).Error; err != nil {
+			return err
+		}
+
+		// Conversations are redacted on both sides so other participants keep the thread structure
+		if err := tx.Unscoped().Model(&model.ChatMessage{}).
+			Where("sender_id = ? OR receiver_id = ?", userID, userID).
+			Update("message", "[erased]").Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&model.Notification{}).Error; err != nil {
+			return err
+		}
+
+		// Star ratings stay for branch statistics, the written review does not
+		if err := tx.Unscoped().Model(&model.Rating{}).Where("customer_id = ?", userID).Updates(map[string]interface{}{
+			"review":    "",
+			"is_public": false,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.AuditTrail{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+			"ip_address": "",
+			"user_agent": "",
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.APIKey{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", time.Now()).Error
+	})
+}
+
+// RequestFilters represents filters for data subject request queries
+type RequestFilters struct {
+	UserID *uuid.UUID
+	Type   model.DataSubjectRequestType
+	Status model.DataSubjectRequestStatus
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"service/internal/shared/database/dbtest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// erasureWrites are the writes of an erasure, in the order they are made
+var erasureWrites = []string{
+	`UPDATE "users" SET .*"email"=.*"full_name"=`,
+	`(UPDATE|DELETE FROM) "contact_verifications"`,
+	`(UPDATE|DELETE FROM) "user_devices"`,
+	`DELETE FROM "customer_devices" WHERE customer_id = \$1`,
+	`UPDATE "appointments" SET "cancel_reason"=\$1,"description"=\$2`,
+	`UPDATE "service_orders" SET .*"i_phone_imei"=.*"pickup_latitude"=`,
+	`UPDATE "service_orders" SET "customer_npwp"=.* WHERE customer_id = \$\d+ AND \(invoice_number IS NULL OR invoice_number = ''\)`,
+	`UPDATE "handover_proofs" SET .*"latitude"=.*"notes"=.*"signature_object"=.* WHERE order_id IN \(SELECT "id" FROM "service_orders" WHERE customer_id = \$\d+\)`,
+	`UPDATE "handover_attempts" SET "latitude"=\$1,"longitude"=\$2 WHERE order_id IN \(SELECT "id" FROM "service_orders" WHERE customer_id = \$3\)`,
+	`DELETE FROM "courier_locations" WHERE order_id IN \(SELECT "id" FROM "service_orders" WHERE customer_id = \$1\) OR courier_id = \$2`,
+	`DELETE FROM "order_media" WHERE order_id IN \(SELECT "id" FROM "service_orders" WHERE customer_id = \$1\)`,
+	`UPDATE "chat_messages" SET "message"=`,
+	`DELETE FROM "notifications" WHERE user_id = \$1`,
+	`UPDATE "ratings" SET "is_public"=\$1,"review"=\$2`,
+	`UPDATE "audit_trails" SET "ip_address"=\$1,"user_agent"=\$2`,
+	`UPDATE "api_keys" SET "revoked_at"=.* WHERE \(user_id = \$\d+ AND revoked_at IS NULL\)`,
+}
+
+func TestAnonymizeUser(t *testing.T) {
+	db, mock := dbtest.Mock(t)
+	repo := &PrivacyRepository{db: db}
+
+	mock.ExpectBegin()
+	for _, write := range erasureWrites {
+		mock.ExpectExec(write).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.AnonymizeUser(context.Background(), uuid.New()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnonymizeUserRollsBack(t *testing.T) {
+	writeFailed := errors.New("write failed")
+
+	// Failing at each write in turn leaves nothing of the erasure behind
+	for failing := range erasureWrites {
+		db, mock := dbtest.Mock(t)
+		repo := &PrivacyRepository{db: db}
+
+		mock.ExpectBegin()
+		for _, write := range erasureWrites[:failing] {
+			mock.ExpectExec(write).WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		mock.ExpectExec(erasureWrites[failing]).WillReturnError(writeFailed)
+		mock.ExpectRollback()
+
+		assert.Equal(t, writeFailed, repo.AnonymizeUser(context.Background(), uuid.New()), erasureWrites[failing])
+		assert.NoError(t, mock.ExpectationsWereMet(), erasureWrites[failing])
+	}
+}
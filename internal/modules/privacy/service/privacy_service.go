@@ -0,0 +1,359 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	mediaService "service/internal/modules/media/service"
+	privacyRepository "service/internal/modules/privacy/repository"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// exportURLExpiry is how long a download link for an export archive stays valid
+const exportURLExpiry = 15 * time.Minute
+
+// processBatchSize limits how many requests one run of the background job handles
+const processBatchSize = 10
+
+// PrivacyService handles personal data export and erasure requests
+type PrivacyService struct {
+	privacyRepo *privacyRepository.PrivacyRepository
+	fileService *mediaService.FileService
+}
+
+// NewPrivacyService creates a new privacy service
+func NewPrivacyService() *PrivacyService {
+	fileService, err := mediaService.NewFileService()
+	if err != nil {
+		// Exports are still produced without the stored photos; archives cannot be
+		// uploaded until storage is reachable, so those requests are marked failed.
+		log.Printf("Failed to initialize file service for privacy requests: %v", err)
+		fileService = nil
+	}
+	return &PrivacyService{
+		privacyRepo: privacyRepository.NewPrivacyRepository(),
+		fileService: fileService,
+	}
+}
+
+// CreateRequest files a data subject request for a user.
+// Exports are approved immediately; erasure requests by the data subject wait for
+// review, while requests filed by an administrator are approved directly.
+func (s *PrivacyService) CreateRequest(ctx context.Context, userID, requestedBy uuid.UUID, byAdmin bool, req *model.DataSubjectRequestRequest) (*model.DataSubjectRequest, error) {
+	if !s.privacyRepo.Available() {
+		return nil, errors.New("privacy request storage is not available")
+	}
+
+	open, err := s.privacyRepo.HasOpenRequest(ctx, userID, req.Type)
+	if err != nil {
+		return nil, err
+	}
+	if open {
+		return nil, model.ErrDataRequestExists
+	}
+
+	if req.Type == model.DataSubjectRequestErasure {
+		if err := s.ensureNoActiveOrders(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	request := &model.DataSubjectRequest{
+		UserID:      userID,
+		Type:        req.Type,
+		Status:      model.DataSubjectRequestPending,
+		Reason:      req.Reason,
+		RequestedBy: requestedBy,
+	}
+	if req.Type == model.DataSubjectRequestExport || byAdmin {
+		request.Status = model.DataSubjectRequestApproved
+		if byAdmin {
+			request.ReviewedBy = &requestedBy
+		}
+	}
+
+	if err := s.privacyRepo.CreateRequest(ctx, request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// GetRequest retrieves a request. When ownerID is set the request must belong to that user.
+func (s *PrivacyService) GetRequest(ctx context.Context, id uuid.UUID, ownerID *uuid.UUID) (*model.DataSubjectRequest, error) {
+	if !s.privacyRepo.Available() {
+		return nil, model.ErrDataRequestNotFound
+	}
+
+	request, err := s.privacyRepo.GetRequestByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrDataRequestNotFound
+		}
+		return nil, err
+	}
+	if ownerID != nil && request.UserID != *ownerID {
+		return nil, model.ErrDataRequestNotFound
+	}
+
+	return request, nil
+}
+
+// ListRequests lists data subject requests with pagination
+func (s *PrivacyService) ListRequests(ctx context.Context, page, limit int, filters *privacyRepository.RequestFilters) ([]*model.DataSubjectRequest, int64, error) {
+	if !s.privacyRepo.Available() {
+		return []*model.DataSubjectRequest{}, 0, nil
+	}
+
+	offset := (page - 1) * limit
+	return s.privacyRepo.ListRequests(ctx, offset, limit, filters)
+}
+
+// ApproveRequest queues a pending request for processing
+func (s *PrivacyService) ApproveRequest(ctx context.Context, id, reviewerID uuid.UUID, notes string) (*model.DataSubjectRequest, error) {
+	request, err := s.GetRequest(ctx, id, nil)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != model.DataSubjectRequestPending {
+		return nil, model.ErrDataRequestState
+	}
+
+	if request.Type == model.DataSubjectRequestErasure {
+		if err := s.ensureNoActiveOrders(ctx, request.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	request.Status = model.DataSubjectRequestApproved
+	request.ReviewedBy = &reviewerID
+	request.ReviewNotes = notes
+	if err := s.privacyRepo.UpdateRequest(ctx, request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// RejectRequest rejects a pending request
+func (s *PrivacyService) RejectRequest(ctx context.Context, id, reviewerID uuid.UUID, notes string) (*model.DataSubjectRequest, error) {
+	request, err := s.GetRequest(ctx, id, nil)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != model.DataSubjectRequestPending {
+		return nil, model.ErrDataRequestState
+	}
+
+	now := time.Now()
+	request.Status = model.DataSubjectRequestRejected
+	request.ReviewedBy = &reviewerID
+	request.ReviewNotes = notes
+	request.CompletedAt = &now
+	if err := s.privacyRepo.UpdateRequest(ctx, request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// GetDownloadURL returns a short-lived link to a completed export archive
+func (s *PrivacyService) GetDownloadURL(ctx context.Context, id uuid.UUID, ownerID *uuid.UUID) (*model.DataExportDownloadResponse, error) {
+	request, err := s.GetRequest(ctx, id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Type != model.DataSubjectRequestExport ||
+		request.Status != model.DataSubjectRequestCompleted ||
+		request.ArchiveObject == "" || s.fileService == nil {
+		return nil, model.ErrExportNotReady
+	}
+
+	url, err := s.fileService.GetFileURL(ctx, request.ArchiveObject, exportURLExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.DataExportDownloadResponse{
+		URL:       url,
+		ExpiresAt: time.Now().Add(exportURLExpiry),
+	}, nil
+}
+
+// ProcessApprovedRequests runs queued export and erasure requests.
+// It is called periodically by the background job in cmd/app.
+func (s *PrivacyService) ProcessApprovedRequests(ctx context.Context) error {
+	if !s.privacyRepo.Available() {
+		return nil
+	}
+
+	requests, err := s.privacyRepo.ListApproved(ctx, processBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, request := range requests {
+		claimed, err := s.privacyRepo.MarkProcessing(ctx, request.ID)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		switch request.Type {
+		case model.DataSubjectRequestExport:
+			err = s.processExport(ctx, request)
+		case model.DataSubjectRequestErasure:
+			err = s.processErasure(ctx, request)
+		default:
+			err = fmt.Errorf("unknown request type %q", request.Type)
+		}
+
+		now := time.Now()
+		request.CompletedAt = &now
+		if err != nil {
+			log.Printf("Data subject request %s failed: %v", request.ID, err)
+			request.Status = model.DataSubjectRequestFailed
+			request.ErrorMessage = err.Error()
+		} else {
+			request.Status = model.DataSubjectRequestCompleted
+			request.ErrorMessage = ""
+		}
+		if err := s.privacyRepo.UpdateRequest(ctx, request); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// processExport builds the export archive and uploads it to storage
+func (s *PrivacyService) processExport(ctx context.Context, request *model.DataSubjectRequest) error {
+	if s.fileService == nil {
+		return errors.New("file storage is not available")
+	}
+
+	export, err := s.privacyRepo.CollectUserData(ctx, request.UserID)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	// Stored photos of the user's orders and their avatar are included as files
+	folders := []string{fmt.Sprintf("users/%s", request.UserID)}
+	for _, order := range export.Orders {
+		folders = append(folders, fmt.Sprintf("orders/%s", order.ID))
+	}
+	for _, folder := range folders {
+		files, err := s.fileService.ListFiles(ctx, folder)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			if err := s.addStoredFile(ctx, archive, f.Name); err != nil {
+				return err
+			}
+			export.Files = append(export.Files, "files/"+f.Name)
+		}
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return err
+	}
+	w, err := archive.Create("data.json")
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+
+	objectName := fmt.Sprintf("privacy/exports/%s/%s.zip", request.UserID, request.ID)
+	size := int64(buf.Len())
+	if err := s.fileService.PutFile(ctx, objectName, &buf, size, "application/zip"); err != nil {
+		return err
+	}
+
+	request.ArchiveObject = objectName
+	request.ArchiveSize = size
+	return nil
+}
+
+// addStoredFile copies a stored object into the archive under files/
+func (s *PrivacyService) addStoredFile(ctx context.Context, archive *zip.Writer, objectName string) error {
+	src, err := s.fileService.GetFile(ctx, objectName)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	w, err := archive.Create("files/" + objectName)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, src)
+	return err
+}
+
+// processErasure removes stored files and anonymizes the user's records
+func (s *PrivacyService) processErasure(ctx context.Context, request *model.DataSubjectRequest) error {
+	if err := s.ensureNoActiveOrders(ctx, request.UserID); err != nil {
+		return err
+	}
+
+	if s.fileService == nil {
+		return errors.New("file storage is not available")
+	}
+
+	orders, err := s.privacyRepo.GetCustomerOrders(ctx, request.UserID)
+	if err != nil {
+		return err
+	}
+	folders := []string{
+		fmt.Sprintf("users/%s", request.UserID),
+		fmt.Sprintf("privacy/exports/%s", request.UserID),
+	}
+	for _, order := range orders {
+		folders = append(folders, fmt.Sprintf("orders/%s", order.ID))
+	}
+	for _, folder := range folders {
+		if err := s.fileService.DeleteFolder(ctx, folder); err != nil {
+			return err
+		}
+	}
+
+	if err := s.privacyRepo.AnonymizeUser(ctx, request.UserID); err != nil {
+		return err
+	}
+
+	// Earlier export archives are gone from storage, so their links must not be served
+	return s.privacyRepo.ClearArchives(ctx, request.UserID)
+}
+
+// ensureNoActiveOrders refuses erasure while the user's orders are still being serviced
+func (s *PrivacyService) ensureNoActiveOrders(ctx context.Context, userID uuid.UUID) error {
+	count, err := s.privacyRepo.CountActiveOrders(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return model.ErrActiveOrdersExist
+	}
+	return nil
+}
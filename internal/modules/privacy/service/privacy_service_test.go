@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	privacyRepository "service/internal/modules/privacy/repository"
+	"service/internal/shared/database/dbtest"
+	"service/internal/shared/model"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockedPrivacyService returns a privacy service whose repository runs on sqlmock
+func mockedPrivacyService(t *testing.T) (*PrivacyService, sqlmock.Sqlmock) {
+	t.Helper()
+	mock := dbtest.MockGlobal(t)
+	return &PrivacyService{privacyRepo: privacyRepository.NewPrivacyRepository()}, mock
+}
+
+func TestCreateRequest(t *testing.T) {
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	tests := []struct {
+		name         string
+		requestType  model.DataSubjectRequestType
+		byAdmin      bool
+		openRequests int64
+		activeOrders int64
+		want         error
+		wantStatus   model.DataSubjectRequestStatus
+	}{
+		{"export is approved right away", model.DataSubjectRequestExport, false, 0, 2, nil, model.DataSubjectRequestApproved},
+		{"erasure by the user waits for review", model.DataSubjectRequestErasure, false, 0, 0, nil, model.DataSubjectRequestPending},
+		{"erasure by an administrator is approved", model.DataSubjectRequestErasure, true, 0, 0, nil, model.DataSubjectRequestApproved},
+		{"one open request per type", model.DataSubjectRequestExport, false, 1, 0, model.ErrDataRequestExists, ""},
+		{"no erasure while orders are serviced", model.DataSubjectRequestErasure, true, 0, 1, model.ErrActiveOrdersExist, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := mockedPrivacyService(t)
+			requestedBy := userID
+			if tt.byAdmin {
+				requestedBy = adminID
+			}
+
+			dbtest.ExpectCount(mock, "data_subject_requests", tt.openRequests)
+			if tt.openRequests == 0 && tt.requestType == model.DataSubjectRequestErasure {
+				dbtest.ExpectCount(mock, "service_orders", tt.activeOrders)
+			}
+			if tt.want == nil {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO "data_subject_requests"`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+				mock.ExpectCommit()
+			}
+
+			request, err := s.CreateRequest(context.Background(), userID, requestedBy, tt.byAdmin,
+				&model.DataSubjectRequestRequest{Type: tt.requestType})
+			assert.Equal(t, tt.want, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.wantStatus, request.Status)
+			if tt.byAdmin {
+				assert.Equal(t, &adminID, request.ReviewedBy)
+			} else {
+				assert.Nil(t, request.ReviewedBy)
+			}
+		})
+	}
+}
+
+func TestProcessErasureWithActiveOrders(t *testing.T) {
+	s, mock := mockedPrivacyService(t)
+	dbtest.ExpectCount(mock, "service_orders", 1)
+
+	// Orders taken after the request was approved still block it; nothing is erased
+	err := s.processErasure(context.Background(), &model.DataSubjectRequest{UserID: uuid.New(), Type: model.DataSubjectRequestErasure})
+	assert.Equal(t, model.ErrActiveOrdersExist, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"net/http"
+	"service/internal/modules/search/repository"
+	"service/internal/modules/search/service"
+	"service/internal/shared/model"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SearchHandler handles order search and admin global search endpoints
+type SearchHandler struct {
+	searchService *service.SearchService
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler() *SearchHandler {
+	return &SearchHandler{
+		searchService: service.NewSearchService(),
+	}
+}
+
+// SearchOrders godoc
+// @Summary Search orders
+// @Description Search orders by partial order number, invoice number, IMEI, customer name, phone or email, and description. Results are ranked best match first and matched fields are returned in highlights with <mark> tags.
+// @Tags search
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search text"
+// @Param branch_id query string false "Branch ID"
+// @Param status query string false "Order status"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} model.PaginatedResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /search/orders [get]
+func (h *SearchHandler) SearchOrders(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	filters := &repository.OrderSearchFilters{}
+	if branchIDStr := c.Query("branch_id"); branchIDStr != "" {
+		branchID, err := uuid.Parse(branchIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.CreateErrorResponse("invalid_id", "Invalid branch ID", nil))
+			return
+		}
+		filters.BranchID = &branchID
+	}
+	if status := c.Query("status"); status != "" {
+		orderStatus := model.OrderStatus(status)
+		filters.Status = &orderStatus
+	}
+
+	results, total, err := h.searchService.SearchOrders(c.Request.Context(), c.Query("q"), filters, page, limit)
+	if err != nil {
+		respondSearchError(c, "order_search_failed", err)
+		return
+	}
+
+	pagination := model.PaginationResponse{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}
+
+	c.JSON(http.StatusOK, model.PaginatedSuccessResponse(results, pagination, "Orders found successfully"))
+}
+
+// GlobalSearch godoc
+// @Summary Global search (admin)
+// @Description Search orders, users and branches at once and return the best matches of each. Branch-scoped admins only find data of their branches.
+// @Tags search
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search text"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /admin/search [get]
+func (h *SearchHandler) GlobalSearch(c *gin.Context) {
+	results, err := h.searchService.GlobalSearch(c.Request.Context(), c.Query("q"))
+	if err != nil {
+		respondSearchError(c, "global_search_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(results, "Search completed successfully"))
+}
+
+// respondSearchError maps search errors to HTTP responses
+func respondSearchError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case model.ErrSearchQueryTooShort:
+		status = http.StatusBadRequest
+	case model.ErrForbidden:
+		status = http.StatusForbidden
+	}
+	c.JSON(status, model.CreateErrorResponse(code, err.Error(), nil))
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Search documents. They must stay identical to the expression indexes created by the
+// migration, otherwise the indexes are not used.
+const (
+	orderDocument = "to_tsvector('simple', coalesce(o.order_number, '') || ' ' || coalesce(o.invoice_number, '') || ' ' || coalesce(o.i_phone_imei, '') || ' ' || coalesce(o.description, ''))"
+	userDocument  = "to_tsvector('simple', coalesce(u.full_name, '') || ' ' || coalesce(u.email, '') || ' ' || coalesce(u.phone, ''))"
+
+	branchDocument = "to_tsvector('simple', coalesce(b.name, '') || ' ' || coalesce(b.city, '') || ' ' || coalesce(b.province, '') || ' ' || coalesce(b.address, ''))"
+
+	// searchJoin binds the query once so that every condition can refer to it
+	searchJoin = "CROSS JOIN (SELECT to_tsquery('simple', ?) AS q, ?::text AS term, ?::text AS pattern) AS search"
+)
+
+// SearchQuery is a normalized search. TSQuery matches word prefixes, Term is compared by
+// trigram similarity and Pattern matches anywhere inside identifiers.
+type SearchQuery struct {
+	TSQuery string
+	Term    string
+	Pattern string
+}
+
+// OrderSearchFilters narrows an order search
+type OrderSearchFilters struct {
+	BranchID *uuid.UUID
+	Status   *model.OrderStatus
+}
+
+// SearchRepository runs ranked full-text and trigram searches over orders, users and branches
+type SearchRepository struct {
+	db *gorm.DB
+}
+
+// NewSearchRepository creates a new search repository
+func NewSearchRepository() *SearchRepository {
+	return &SearchRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *SearchRepository) Available() bool {
+	return r.db != nil
+}
+
+// SearchOrders retrieves the orders matching the query in the branches the caller may
+// see, best match first
+func (r *SearchRepository) SearchOrders(ctx context.Context, query SearchQuery, filters *OrderSearchFilters, offset, limit int) ([]model.OrderSearchResult, int64, error) {
+	base := r.db.WithContext(ctx).
+		Table("service_orders AS o").
+		Joins("JOIN users AS u ON u.id = o.customer_id").
+		Joins(searchJoin, query.TSQuery, query.Term, query.Pattern).
+		Scopes(model.ScopeByBranch(ctx, "o.branch_id")).
+		Where("o.deleted_at IS NULL").
+		Where("(" + orderDocument + " @@ search.q OR " + userDocument + " @@ search.q" +
+			" OR o.order_number ILIKE search.pattern OR o.invoice_number ILIKE search.pattern" +
+			" OR o.i_phone_imei ILIKE search.pattern OR u.full_name ILIKE search.pattern" +
+			" OR u.phone ILIKE search.pattern OR u.email ILIKE search.pattern)")
+	if filters != nil {
+		if filters.BranchID != nil {
+			base = base.Where("o.branch_id = ?", *filters.BranchID)
+		}
+		if filters.Status != nil {
+			base = base.Where("o.status = ?", *filters.Status)
+		}
+	}
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var results []model.OrderSearchResult
+	err := base.
+		Select("o.id, o.order_number, o.invoice_number, o.branch_id, o.status, o.service_type, o.i_phone_model, o.i_phone_imei, o.description, o.created_at," +
+			" o.customer_id, u.full_name AS customer_name, u.phone AS customer_phone, u.email AS customer_email," +
+			" ts_rank(" + orderDocument + ", search.q) + ts_rank(" + userDocument + ", search.q)" +
+			" + GREATEST(similarity(o.order_number, search.term), similarity(coalesce(o.invoice_number, ''), search.term)," +
+			" similarity(o.i_phone_imei, search.term), similarity(u.full_name, search.term), similarity(u.phone, search.term)," +
+			" similarity(u.email, search.term)) AS rank").
+		Order("rank DESC, o.created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(&results).Error
+	return results, total, err
+}
+
+// SearchUsers retrieves the users matching the query in the branches the caller may see,
+// best match first. Branch-scoped callers only find staff of their branches.
+func (r *SearchRepository) SearchUsers(ctx context.Context, query SearchQuery, limit int) ([]model.UserSearchResult, error) {
+	var results []model.UserSearchResult
+	err := r.db.WithContext(ctx).
+		Table("users AS u").
+		Joins(searchJoin, query.TSQuery, query.Term, query.Pattern).
+		Scopes(model.ScopeByBranch(ctx, "u.branch_id")).
+		Where("u.deleted_at IS NULL").
+		Where("(" + userDocument + " @@ search.q OR u.full_name ILIKE search.pattern" +
+			" OR u.phone ILIKE search.pattern OR u.email ILIKE search.pattern)").
+		Select("u.id, u.full_name, u.email, u.phone, u.role, u.branch_id, u.is_active," +
+			" ts_rank(" + userDocument + ", search.q)" +
+			" + GREATEST(similarity(u.full_name, search.term), similarity(u.email, search.term), similarity(u.phone, search.term)) AS rank").
+		Order("rank DESC, u.full_name ASC").
+		Limit(limit).
+		Scan(&results).Error
+	return results, err
+}
+
+// SearchBranches retrieves the branches matching the query that the caller may see, best
+// match first
+func (r *SearchRepository) SearchBranches(ctx context.Context, query SearchQuery, limit int) ([]model.BranchSearchResult, error) {
+	var results []model.BranchSearchResult
+	err := r.db.WithContext(ctx).
+		Table("branches AS b").
+		Joins(searchJoin, query.TSQuery, query.Term, query.Pattern).
+		Scopes(model.ScopeByBranch(ctx, "b.id")).
+		Where("b.deleted_at IS NULL").
+		Where("(" + branchDocument + " @@ search.q OR b.name ILIKE search.pattern" +
+			" OR b.city ILIKE search.pattern OR b.phone ILIKE search.pattern)").
+		Select("b.id, b.name, b.address, b.city, b.province, b.phone, b.is_active," +
+			" ts_rank(" + branchDocument + ", search.q)" +
+			" + GREATEST(similarity(b.name, search.term), similarity(b.city, search.term)) AS rank").
+		Order("rank DESC, b.name ASC").
+		Limit(limit).
+		Scan(&results).Error
+	return results, err
+}
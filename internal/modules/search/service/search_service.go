@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"html"
+	"service/internal/modules/search/repository"
+	"service/internal/shared/model"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// minSearchLength is the shortest query worth searching for
+	minSearchLength = 2
+	// globalSearchLimit is the number of results of each kind in a global search
+	globalSearchLimit = 10
+	// snippetRadius is the number of characters kept around the first match of long text
+	snippetRadius = 60
+)
+
+// likeEscaper escapes the wildcards of a LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchService handles order search and the admin global search
+type SearchService struct {
+	searchRepo *repository.SearchRepository
+}
+
+// NewSearchService creates a new search service
+func NewSearchService() *SearchService {
+	return &SearchService{
+		searchRepo: repository.NewSearchRepository(),
+	}
+}
+
+// SearchOrders searches orders by order number, invoice number, IMEI, customer name,
+// phone and email, and description, in the branches the caller may see
+func (s *SearchService) SearchOrders(ctx context.Context, q string, filters *repository.OrderSearchFilters, page, limit int) ([]model.OrderSearchResult, int64, error) {
+	query, terms, err := parseSearchQuery(q)
+	if err != nil {
+		return nil, 0, err
+	}
+	if filters != nil && filters.BranchID != nil && !model.BranchScopeFromContext(ctx).Allows(*filters.BranchID) {
+		return nil, 0, model.ErrForbidden
+	}
+	if !s.searchRepo.Available() {
+		return []model.OrderSearchResult{}, 0, nil
+	}
+
+	results, total, err := s.searchRepo.SearchOrders(ctx, query, filters, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range results {
+		r := &results[i]
+		r.Highlights = highlightFields(terms, map[string]string{
+			"order_number":   r.OrderNumber,
+			"invoice_number": r.InvoiceNumber,
+			"iphone_imei":    r.IPhoneIMEI,
+			"customer_name":  r.CustomerName,
+			"customer_phone": r.CustomerPhone,
+			"customer_email": r.CustomerEmail,
+		})
+		if snippet, ok := highlight(r.Description, terms, true); ok {
+			r.Highlights["description"] = snippet
+		}
+	}
+	return results, total, nil
+}
+
+// GlobalSearch searches orders, users and branches at once, returning the best matches of
+// each kind in the branches the caller may see
+func (s *SearchService) GlobalSearch(ctx context.Context, q string) (*model.GlobalSearchResponse, error) {
+	orders, _, err := s.SearchOrders(ctx, q, nil, 1, globalSearchLimit)
+	if err != nil {
+		return nil, err
+	}
+	query, terms, _ := parseSearchQuery(q)
+
+	response := &model.GlobalSearchResponse{
+		Query:    query.Term,
+		Orders:   orders,
+		Users:    []model.UserSearchResult{},
+		Branches: []model.BranchSearchResult{},
+	}
+	if !s.searchRepo.Available() {
+		return response, nil
+	}
+
+	users, err := s.searchRepo.SearchUsers(ctx, query, globalSearchLimit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		u := &users[i]
+		u.Highlights = highlightFields(terms, map[string]string{
+			"full_name": u.FullName,
+			"email":     u.Email,
+			"phone":     u.Phone,
+		})
+	}
+	response.Users = append(response.Users, users...)
+
+	branches, err := s.searchRepo.SearchBranches(ctx, query, globalSearchLimit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range branches {
+		b := &branches[i]
+		b.Highlights = highlightFields(terms, map[string]string{
+			"name":     b.Name,
+			"city":     b.City,
+			"province": b.Province,
+			"phone":    b.Phone,
+		})
+		if snippet, ok := highlight(b.Address, terms, true); ok {
+			b.Highlights["address"] = snippet
+		}
+	}
+	response.Branches = append(response.Branches, branches...)
+	return response, nil
+}
+
+// parseSearchQuery normalizes user input into a search. Words are matched by prefix, so
+// "budi 0812" finds "Budi Santoso" with phone "081234567890".
+func parseSearchQuery(q string) (repository.SearchQuery, []string, error) {
+	q = strings.TrimSpace(q)
+	terms := strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	length := 0
+	for _, term := range terms {
+		length += utf8.RuneCountInString(term)
+	}
+	if length < minSearchLength {
+		return repository.SearchQuery{}, nil, model.ErrSearchQueryTooShort
+	}
+
+	prefixes := make([]string, len(terms))
+	for i, term := range terms {
+		prefixes[i] = term + ":*"
+	}
+	return repository.SearchQuery{
+		TSQuery: strings.Join(prefixes, " & "),
+		Term:    strings.ToLower(q),
+		Pattern: "%" + likeEscaper.Replace(q) + "%",
+	}, terms, nil
+}
+
+// highlightFields highlights the fields that contain a search term
+func highlightFields(terms []string, fields map[string]string) map[string]string {
+	highlights := make(map[string]string)
+	for name, value := range fields {
+		if marked, ok := highlight(value, terms, false); ok {
+			highlights[name] = marked
+		}
+	}
+	return highlights
+}
+
+// highlight HTML-escapes the value and marks every occurrence of the terms. Long text is
+// cut down to a snippet around the first match. It reports false when nothing matched.
+func highlight(value string, terms []string, snippet bool) (string, bool) {
+	if value == "" {
+		return "", false
+	}
+
+	// Byte offsets are shared between value and its lowercase form only for runes whose
+	// case mapping keeps their width, so fall back to exact matching otherwise
+	lower := strings.ToLower(value)
+	if len(lower) != len(value) {
+		lower = value
+	}
+
+	type span struct{ start, end int }
+	var spans []span
+	for _, term := range terms {
+		for offset := 0; offset < len(lower); {
+			i := strings.Index(lower[offset:], term)
+			if i < 0 {
+				break
+			}
+			start := offset + i
+			spans = append(spans, span{start, start + len(term)})
+			offset = start + len(term)
+		}
+	}
+	if len(spans) == 0 {
+		return "", false
+	}
+
+	// Merge overlapping matches
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	merged := spans[:1]
+	for _, sp := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if sp.start <= last.end {
+			if sp.end > last.end {
+				last.end = sp.end
+			}
+			continue
+		}
+		merged = append(merged, sp)
+	}
+
+	from, to := 0, len(value)
+	if snippet {
+		from = snippetBoundary(value, merged[0].start-snippetRadius)
+		to = snippetBoundary(value, merged[0].end+snippetRadius)
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	cursor := from
+	for _, sp := range merged {
+		if sp.start >= to {
+			break
+		}
+		end := sp.end
+		if end > to {
+			end = to
+		}
+		b.WriteString(html.EscapeString(value[cursor:sp.start]))
+		b.WriteString(model.SearchHighlightStart)
+		b.WriteString(html.EscapeString(value[sp.start:end]))
+		b.WriteString(model.SearchHighlightStop)
+		cursor = end
+	}
+	b.WriteString(html.EscapeString(value[cursor:to]))
+	if to < len(value) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
+
+// snippetBoundary clamps a byte offset into the value and moves it onto a rune boundary
+func snippetBoundary(value string, offset int) int {
+	if offset <= 0 {
+		return 0
+	}
+	if offset >= len(value) {
+		return len(value)
+	}
+	for offset > 0 && !utf8.RuneStart(value[offset]) {
+		offset--
+	}
+	return offset
+}
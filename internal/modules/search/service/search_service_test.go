@@ -0,0 +1,111 @@
+package service
+
+import (
+	"service/internal/modules/search/repository"
+	"service/internal/shared/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		q         string
+		want      repository.SearchQuery
+		wantTerms []string
+		wantErr   error
+	}{
+		{
+			name:      "single term",
+			q:         "iphone",
+			want:      repository.SearchQuery{TSQuery: "iphone:*", Term: "iphone", Pattern: "%iphone%"},
+			wantTerms: []string{"iphone"},
+		},
+		{
+			name:      "several terms are all required",
+			q:         "  Budi Santoso ",
+			want:      repository.SearchQuery{TSQuery: "budi:* & santoso:*", Term: "budi santoso", Pattern: "%Budi Santoso%"},
+			wantTerms: []string{"budi", "santoso"},
+		},
+		{
+			name:      "punctuation splits terms",
+			q:         "JKT01-2026-000123",
+			want:      repository.SearchQuery{TSQuery: "jkt01:* & 2026:* & 000123:*", Term: "jkt01-2026-000123", Pattern: "%JKT01-2026-000123%"},
+			wantTerms: []string{"jkt01", "2026", "000123"},
+		},
+		{
+			name:      "tsquery operators are dropped",
+			q:         "screen & !(battery | :*)",
+			want:      repository.SearchQuery{TSQuery: "screen:* & battery:*", Term: "screen & !(battery | :*)", Pattern: "%screen & !(battery | :*)%"},
+			wantTerms: []string{"screen", "battery"},
+		},
+		{
+			name:      "like wildcards are escaped",
+			q:         `50%_off\`,
+			want:      repository.SearchQuery{TSQuery: "50:* & off:*", Term: `50%_off\`, Pattern: `%50\%\_off\\%`},
+			wantTerms: []string{"50", "off"},
+		},
+		{
+			name:      "letters of any script",
+			q:         "Ñandú",
+			want:      repository.SearchQuery{TSQuery: "ñandú:*", Term: "ñandú", Pattern: "%Ñandú%"},
+			wantTerms: []string{"ñandú"},
+		},
+		{name: "too short", q: "a", wantErr: model.ErrSearchQueryTooShort},
+		{name: "only punctuation", q: "%%--", wantErr: model.ErrSearchQueryTooShort},
+		{name: "empty", q: "   ", wantErr: model.ErrSearchQueryTooShort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, terms, err := parseSearchQuery(tt.q)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, query)
+			assert.Equal(t, tt.wantTerms, terms)
+		})
+	}
+}
+
+func TestLikeEscaper(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"100%", `100\%`},
+		{"a_b", `a\_b`},
+		{`C:\path`, `C:\\path`},
+		{`\%`, `\\\%`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			assert.Equal(t, tt.want, likeEscaper.Replace(tt.in))
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		terms  []string
+		want   string
+		wantOK bool
+	}{
+		{"no match", "Screen replacement", []string{"battery"}, "", false},
+		{"case-insensitive", "Screen replacement", []string{"screen"}, "<mark>Screen</mark> replacement", true},
+		{"every occurrence", "ab ab", []string{"ab"}, "<mark>ab</mark> <mark>ab</mark>", true},
+		{"overlapping terms merge", "santoso", []string{"san", "ntos"}, "<mark>santos</mark>o", true},
+		{"escapes html", "<b>screen</b>", []string{"screen"}, "&lt;b&gt;<mark>screen</mark>&lt;/b&gt;", true},
+		{"empty value", "", []string{"screen"}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := highlight(tt.value, tt.terms, false)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
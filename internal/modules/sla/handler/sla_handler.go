@@ -0,0 +1,299 @@
+package handler
+
+import (
+	"net/http"
+	"service/internal/modules/sla/service"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SLAHandler handles SLA policy, business hours and SLA monitoring endpoints
+type SLAHandler struct {
+	slaService *service.SLAService
+}
+
+// NewSLAHandler creates a new SLA handler
+func NewSLAHandler() *SLAHandler {
+	return &SLAHandler{
+		slaService: service.NewSLAService(),
+	}
+}
+
+// ListAtRiskOrders godoc
+// @Summary List orders at risk of missing their SLA
+// @Description List the open orders that passed the at-risk share of their current SLA target or already missed it, highest priority and earliest due first
+// @Tags sla
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param branch_id query string false "Branch ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /sla/at-risk [get]
+func (h *SLAHandler) ListAtRiskOrders(c *gin.Context) {
+	var branchID *uuid.UUID
+	if branchIDStr := c.Query("branch_id"); branchIDStr != "" {
+		parsed, err := uuid.Parse(branchIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.CreateErrorResponse("invalid_id", "Invalid branch ID", nil))
+			return
+		}
+		branchID = &parsed
+	}
+
+	orders, err := h.slaService.ListAtRisk(c.Request.Context(), branchID)
+	if err != nil {
+		respondSLAError(c, "at_risk_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(orders, "At-risk orders retrieved successfully"))
+}
+
+// GetOrderSLA godoc
+// @Summary Get order SLA
+// @Description Get the SLA stages of an order with their due times and the time the order spent in each status
+// @Tags sla
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /orders/{id}/sla [get]
+func (h *SLAHandler) GetOrderSLA(c *gin.Context) {
+	orderID, ok := parseID(c, "Invalid order ID")
+	if !ok {
+		return
+	}
+
+	sla, err := h.slaService.GetOrderSLA(c.Request.Context(), orderID)
+	if err != nil {
+		respondSLAError(c, "order_sla_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(sla, "Order SLA retrieved successfully"))
+}
+
+// ListPolicies godoc
+// @Summary List SLA policies (admin)
+// @Description List the SLA targets per service type and membership tier
+// @Tags sla
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /admin/sla-policies [get]
+func (h *SLAHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.slaService.ListPolicies(c.Request.Context())
+	if err != nil {
+		respondSLAError(c, "sla_policies_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(policies, "SLA policies retrieved successfully"))
+}
+
+// CreatePolicy godoc
+// @Summary Create SLA policy (admin)
+// @Description Create the SLA targets, in business minutes, for a service type and membership tier. Leave either empty to match any.
+// @Tags sla
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.SLAPolicyRequest true "SLA policy"
+// @Success 201 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /admin/sla-policies [post]
+func (h *SLAHandler) CreatePolicy(c *gin.Context) {
+	var req model.SLAPolicyRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	policy, err := h.slaService.CreatePolicy(c.Request.Context(), &req)
+	if err != nil {
+		respondSLAError(c, "sla_policy_create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse(policy, "SLA policy created successfully"))
+}
+
+// UpdatePolicy godoc
+// @Summary Update SLA policy (admin)
+// @Description Update an SLA policy. Stages already started keep their due times.
+// @Tags sla
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "SLA policy ID"
+// @Param request body model.SLAPolicyRequest true "SLA policy"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /admin/sla-policies/{id} [put]
+func (h *SLAHandler) UpdatePolicy(c *gin.Context) {
+	id, ok := parseID(c, "Invalid SLA policy ID")
+	if !ok {
+		return
+	}
+
+	var req model.SLAPolicyRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	policy, err := h.slaService.UpdatePolicy(c.Request.Context(), id, &req)
+	if err != nil {
+		respondSLAError(c, "sla_policy_update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(policy, "SLA policy updated successfully"))
+}
+
+// DeletePolicy godoc
+// @Summary Delete SLA policy (admin)
+// @Description Delete an SLA policy. Stages already started keep their due times.
+// @Tags sla
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "SLA policy ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /admin/sla-policies/{id} [delete]
+func (h *SLAHandler) DeletePolicy(c *gin.Context) {
+	id, ok := parseID(c, "Invalid SLA policy ID")
+	if !ok {
+		return
+	}
+
+	if err := h.slaService.DeletePolicy(c.Request.Context(), id); err != nil {
+		respondSLAError(c, "sla_policy_delete_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(nil, "SLA policy deleted successfully"))
+}
+
+// GetBusinessHours godoc
+// @Summary Get branch business hours (admin)
+// @Description Get the weekly business hours SLA targets are counted in. A branch without hours uses the default opening every day.
+// @Tags sla
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Branch ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /admin/branches/{id}/business-hours [get]
+func (h *SLAHandler) GetBusinessHours(c *gin.Context) {
+	branchID, ok := parseID(c, "Invalid branch ID")
+	if !ok {
+		return
+	}
+
+	hours, err := h.slaService.GetBusinessHours(c.Request.Context(), branchID)
+	if err != nil {
+		respondSLAError(c, "business_hours_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(hours, "Business hours retrieved successfully"))
+}
+
+// UpdateBusinessHours godoc
+// @Summary Replace branch business hours (admin)
+// @Description Replace the weekly business hours of a branch. New SLA stages use them; stages already started keep their due times.
+// @Tags sla
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Branch ID"
+// @Param request body model.BranchBusinessHoursRequest true "Business hours"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /admin/branches/{id}/business-hours [put]
+func (h *SLAHandler) UpdateBusinessHours(c *gin.Context) {
+	branchID, ok := parseID(c, "Invalid branch ID")
+	if !ok {
+		return
+	}
+
+	var req model.BranchBusinessHoursRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	hours, err := h.slaService.UpdateBusinessHours(c.Request.Context(), branchID, &req)
+	if err != nil {
+		respondSLAError(c, "business_hours_update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(hours, "Business hours updated successfully"))
+}
+
+// parseID reads the ID path parameter
+func parseID(c *gin.Context, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse("invalid_id", message, nil))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// bindRequest binds, sanitizes and validates a JSON payload
+func bindRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return false
+	}
+
+	utils.SanitizeStructStrings(req)
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return false
+	}
+	return true
+}
+
+// respondSLAError maps SLA errors to HTTP responses
+func respondSLAError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case model.ErrInvalidBusinessHours:
+		status = http.StatusBadRequest
+	case model.ErrOrderNotFound, model.ErrSLAPolicyNotFound, model.ErrBranchNotFound:
+		status = http.StatusNotFound
+	case model.ErrSLAPolicyExists:
+		status = http.StatusConflict
+	case model.ErrForbidden:
+		status = http.StatusForbidden
+	}
+	c.JSON(status, model.CreateErrorResponse(code, err.Error(), nil))
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SLARepository handles SLA policies, branch business hours and the SLA stages of orders
+type SLARepository struct {
+	db *gorm.DB
+}
+
+// NewSLARepository creates a new SLA repository
+func NewSLARepository() *SLARepository {
+	return &SLARepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *SLARepository) Available() bool {
+	return r.db != nil
+}
+
+// ListPolicies retrieves all SLA policies
+func (r *SLARepository) ListPolicies(ctx context.Context) ([]*model.SLAPolicy, error) {
+	var policies []*model.SLAPolicy
+	err := r.db.WithContext(ctx).
+		Order("service_type ASC, membership_tier ASC").
+		Find(&policies).Error
+	return policies, err
+}
+
+// GetPolicy retrieves an SLA policy by ID
+func (r *SLARepository) GetPolicy(ctx context.Context, id uuid.UUID) (*model.SLAPolicy, error) {
+	var policy model.SLAPolicy
+	if err := r.db.WithContext(ctx).First(&policy, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// PolicyExists checks whether another policy has the same service type and membership tier
+func (r *SLARepository) PolicyExists(ctx context.Context, serviceType model.ServiceType, tier model.MembershipTier, excludeID *uuid.UUID) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&model.SLAPolicy{}).
+		Where("service_type = ? AND membership_tier = ?", serviceType, tier)
+	if excludeID != nil {
+		query = query.Where("id <> ?", *excludeID)
+	}
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// CreatePolicy creates a new SLA policy
+func (r *SLARepository) CreatePolicy(ctx context.Context, policy *model.SLAPolicy) error {
+	return r.db.WithContext(ctx).Create(policy).Error
+}
+
+// UpdatePolicy updates an SLA policy
+func (r *SLARepository) UpdatePolicy(ctx context.Context, policy *model.SLAPolicy) error {
+	return r.db.WithContext(ctx).Save(policy).Error
+}
+
+// DeletePolicy deletes an SLA policy. Stages already started keep their due times.
+func (r *SLARepository) DeletePolicy(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&model.SLAPolicy{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrSLAPolicyNotFound
+	}
+	return nil
+}
+
+// ListBusinessHours retrieves the weekly business hours of a branch
+func (r *SLARepository) ListBusinessHours(ctx context.Context, branchID uuid.UUID) ([]*model.BranchBusinessHours, error) {
+	var hours []*model.BranchBusinessHours
+	err := r.db.WithContext(ctx).
+		Where("branch_id = ?", branchID).
+		Order("weekday ASC, open_time ASC").
+		Find(&hours).Error
+	return hours, err
+}
+
+// ReplaceBusinessHours replaces all business hours of a branch in one transaction
+func (r *SLARepository) ReplaceBusinessHours(ctx context.Context, branchID uuid.UUID, hours []*model.BranchBusinessHours) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("branch_id = ?", branchID).Delete(&model.BranchBusinessHours{}).Error; err != nil {
+			return err
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+		return tx.Create(&hours).Error
+	})
+}
+
+// StartStage stores a newly started stage. A stage an order already went through is kept
+// as it was, so that going back and forth does not reset the clock.
+func (r *SLARepository) StartStage(ctx context.Context, stage *model.OrderSLA) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(stage).Error
+}
+
+// CloseOpenStages completes the open stages of an order other than the given one
+func (r *SLARepository) CloseOpenStages(ctx context.Context, orderID uuid.UUID, except model.SLAStage, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.OrderSLA{}).
+		Where("order_id = ? AND completed_at IS NULL AND stage <> ?", orderID, except).
+		Update("completed_at", at).Error
+}
+
+// ListByOrder retrieves the stages of an order, oldest first
+func (r *SLARepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*model.OrderSLA, error) {
+	var stages []*model.OrderSLA
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("started_at ASC").
+		Find(&stages).Error
+	return stages, err
+}
+
+// ListPendingAlerts retrieves the open stages that are at risk or breached and whose
+// escalation has not been sent yet
+func (r *SLARepository) ListPendingAlerts(ctx context.Context, now time.Time) ([]*model.OrderSLA, error) {
+	var stages []*model.OrderSLA
+	err := r.db.WithContext(ctx).
+		Where("completed_at IS NULL").
+		Where("(risk_at <= ? AND at_risk_notified_at IS NULL) OR (due_at <= ? AND breach_notified_at IS NULL)", now, now).
+		Order("due_at ASC").
+		Find(&stages).Error
+	return stages, err
+}
+
+// MarkNotified claims the escalation of a stage. It returns false when another instance
+// already sent it.
+func (r *SLARepository) MarkNotified(ctx context.Context, id uuid.UUID, breach bool) (bool, error) {
+	column := "at_risk_notified_at"
+	if breach {
+		column = "breach_notified_at"
+	}
+	result := r.db.WithContext(ctx).
+		Model(&model.OrderSLA{}).
+		Where("id = ? AND "+column+" IS NULL", id).
+		Update(column, time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// CompleteStage completes a single stage
+func (r *SLARepository) CompleteStage(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.OrderSLA{}).
+		Where("id = ? AND completed_at IS NULL", id).
+		Update("completed_at", at).Error
+}
+
+// ListAtRisk retrieves the open stages past their risk threshold together with their
+// orders, in the branches the caller may see, highest priority and earliest due first
+func (r *SLARepository) ListAtRisk(ctx context.Context, branchID *uuid.UUID, now time.Time) ([]model.AtRiskOrder, error) {
+	query := r.db.WithContext(ctx).
+		Table("order_slas AS s").
+		Select("s.order_id, o.order_number, s.branch_id, o.status, s.stage, s.priority, s.due_at, o.technician_id, o.courier_id").
+		Joins("JOIN service_orders AS o ON o.id = s.order_id AND o.deleted_at IS NULL").
+		Scopes(model.ScopeByBranch(ctx, "s.branch_id")).
+		Where("s.completed_at IS NULL AND s.risk_at <= ?", now)
+	if branchID != nil {
+		query = query.Where("s.branch_id = ?", *branchID)
+	}
+
+	var orders []model.AtRiskOrder
+	err := query.Order("s.priority DESC, s.due_at ASC").Scan(&orders).Error
+	return orders, err
+}
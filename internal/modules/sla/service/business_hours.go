@@ -0,0 +1,93 @@
+package service
+
+import (
+	"service/internal/shared/model"
+	"sort"
+	"time"
+)
+
+// calendarSearchDays bounds how far ahead an SLA due time is looked for
+const calendarSearchDays = 400
+
+// openingWindow is an opening within a day in minutes since midnight
+type openingWindow struct {
+	open, close int
+}
+
+// businessCalendar holds the weekly openings of a branch. An empty calendar is always open.
+type businessCalendar map[time.Weekday][]openingWindow
+
+// newBusinessCalendar builds the calendar of a branch. Branches without business hours
+// use the default opening on every day.
+func newBusinessCalendar(hours []*model.BranchBusinessHours, defaultOpen, defaultClose string) businessCalendar {
+	calendar := businessCalendar{}
+	for _, h := range hours {
+		open, okOpen := clockMinutes(h.OpenTime)
+		closing, okClose := clockMinutes(h.CloseTime)
+		if okOpen && okClose && closing > open {
+			weekday := time.Weekday(h.Weekday)
+			calendar[weekday] = append(calendar[weekday], openingWindow{open: open, close: closing})
+		}
+	}
+
+	if len(calendar) == 0 {
+		open, okOpen := clockMinutes(defaultOpen)
+		closing, okClose := clockMinutes(defaultClose)
+		if okOpen && okClose && closing > open {
+			for day := time.Sunday; day <= time.Saturday; day++ {
+				calendar[day] = []openingWindow{{open: open, close: closing}}
+			}
+		}
+	}
+
+	for day := range calendar {
+		windows := calendar[day]
+		sort.Slice(windows, func(i, j int) bool { return windows[i].open < windows[j].open })
+	}
+	return calendar
+}
+
+// add returns the moment the given number of business minutes after start have passed
+func (c businessCalendar) add(start time.Time, minutes int) time.Time {
+	remaining := time.Duration(minutes) * time.Minute
+	if len(c) == 0 {
+		return start.Add(remaining)
+	}
+
+	current := start.In(time.Local)
+	first := current
+	for day := 0; day < calendarSearchDays; day++ {
+		date := time.Date(first.Year(), first.Month(), first.Day()+day, 0, 0, 0, 0, time.Local)
+		for _, window := range c[date.Weekday()] {
+			opens := atClock(date, window.open)
+			closes := atClock(date, window.close)
+			if !closes.After(current) {
+				continue
+			}
+			if opens.After(current) {
+				current = opens
+			}
+			available := closes.Sub(current)
+			if remaining <= available {
+				return current.Add(remaining)
+			}
+			remaining -= available
+			current = closes
+		}
+	}
+	return current.Add(remaining)
+}
+
+// clockMinutes parses an HH:MM clock time into minutes since midnight
+func clockMinutes(clock string) (int, bool) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// atClock returns the moment on the date the given minutes after midnight
+func atClock(date time.Time, minutes int) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), minutes/60, minutes%60, 0, 0, time.Local)
+}
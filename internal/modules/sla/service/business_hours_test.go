@@ -0,0 +1,110 @@
+package service
+
+import (
+	"service/internal/shared/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// at returns a local time in the week of Monday 9 March 2026
+func at(day, hour, minute int) time.Time {
+	return time.Date(2026, time.March, day, hour, minute, 0, 0, time.Local)
+}
+
+// weekHours opens Monday to Friday with a lunch break and Saturday mornings; Sunday is closed
+func weekHours() []*model.BranchBusinessHours {
+	hours := []*model.BranchBusinessHours{{Weekday: int(time.Saturday), OpenTime: "09:00", CloseTime: "13:00"}}
+	for day := time.Monday; day <= time.Friday; day++ {
+		// Afternoon first, the calendar sorts the windows of a day
+		hours = append(hours,
+			&model.BranchBusinessHours{Weekday: int(day), OpenTime: "13:00", CloseTime: "17:00"},
+			&model.BranchBusinessHours{Weekday: int(day), OpenTime: "09:00", CloseTime: "12:00"},
+		)
+	}
+	return hours
+}
+
+func TestBusinessCalendarAdd(t *testing.T) {
+	calendar := newBusinessCalendar(weekHours(), "", "")
+	tests := []struct {
+		name    string
+		start   time.Time
+		minutes int
+		want    time.Time
+	}{
+		{"no time", at(9, 10, 0), 0, at(9, 10, 0)},
+		{"within a window", at(9, 10, 0), 60, at(9, 11, 0)},
+		{"before opening", at(9, 7, 0), 30, at(9, 9, 30)},
+		{"across the lunch break", at(9, 11, 30), 60, at(9, 13, 30)},
+		{"during the lunch break", at(9, 12, 15), 30, at(9, 13, 30)},
+		{"ends exactly at closing", at(9, 16, 0), 60, at(9, 17, 0)},
+		{"into the next day", at(9, 16, 30), 60, at(10, 9, 30)},
+		{"after closing", at(9, 18, 0), 30, at(10, 9, 30)},
+		{"into the Saturday morning", at(13, 16, 0), 120, at(14, 10, 0)},
+		{"over the closed Sunday", at(14, 12, 30), 60, at(16, 9, 30)},
+		{"started on Sunday", at(15, 10, 0), 15, at(16, 9, 15)},
+		{"a whole week ends at the last closing", at(9, 9, 0), 7*60*5 + 4*60, at(14, 13, 0)},
+		{"a minute past a whole week", at(9, 9, 0), 7*60*5 + 4*60 + 1, at(16, 9, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calendar.add(tt.start, tt.minutes)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+}
+
+func TestNewBusinessCalendar(t *testing.T) {
+	tests := []struct {
+		name     string
+		hours    []*model.BranchBusinessHours
+		start    time.Time
+		minutes  int
+		want     time.Time
+		wantDays int
+	}{
+		{
+			name:     "default opening every day",
+			start:    at(15, 7, 0),
+			minutes:  60,
+			want:     at(15, 9, 0),
+			wantDays: 7,
+		},
+		{
+			name:     "branch hours replace the default",
+			hours:    []*model.BranchBusinessHours{{Weekday: int(time.Monday), OpenTime: "10:00", CloseTime: "11:00"}},
+			start:    at(9, 10, 30),
+			minutes:  60,
+			want:     at(16, 10, 30),
+			wantDays: 1,
+		},
+		{
+			name: "invalid hours are ignored",
+			hours: []*model.BranchBusinessHours{
+				{Weekday: int(time.Monday), OpenTime: "12:00", CloseTime: "09:00"},
+				{Weekday: int(time.Tuesday), OpenTime: "nine", CloseTime: "17:00"},
+			},
+			start:    at(9, 7, 0),
+			minutes:  60,
+			want:     at(9, 9, 0),
+			wantDays: 7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := newBusinessCalendar(tt.hours, "08:00", "17:00")
+			assert.Len(t, calendar, tt.wantDays)
+			got := calendar.add(tt.start, tt.minutes)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+
+	// Without any valid opening the calendar is always open
+	open := newBusinessCalendar(nil, "", "")
+	assert.Empty(t, open)
+	assert.True(t, at(15, 3, 20).Equal(open.add(at(15, 3, 0), 20)))
+}
@@ -0,0 +1,504 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	branchRepo "service/internal/modules/branches/repository"
+	membershipRepo "service/internal/modules/membership/repository"
+	notificationService "service/internal/modules/notification/service"
+	orderRepo "service/internal/modules/orders/repository"
+	"service/internal/modules/sla/repository"
+	userRepo "service/internal/modules/users/repository"
+	"service/internal/shared/config"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SLA defaults used when no configuration is loaded
+const (
+	defaultPickupTarget   = 4 * time.Hour
+	defaultRepairTarget   = 24 * time.Hour
+	defaultDeliveryTarget = 4 * time.Hour
+	defaultAtRiskPercent  = 80
+	defaultOpenTime       = "09:00"
+	defaultCloseTime      = "18:00"
+)
+
+// SLAService measures orders against their SLA targets and escalates delays to the
+// branch admins
+type SLAService struct {
+	slaRepo             *repository.SLARepository
+	orderRepo           *orderRepo.ServiceOrderRepository
+	eventRepo           *orderRepo.StatusEventRepository
+	branchRepo          *branchRepo.BranchRepository
+	membershipRepo      *membershipRepo.MembershipRepository
+	userRepo            *userRepo.UserRepository
+	notificationService *notificationService.NotificationService
+}
+
+// NewSLAService creates a new SLA service
+func NewSLAService() *SLAService {
+	return &SLAService{
+		slaRepo:             repository.NewSLARepository(),
+		orderRepo:           orderRepo.NewServiceOrderRepository(),
+		eventRepo:           orderRepo.NewStatusEventRepository(),
+		branchRepo:          branchRepo.NewBranchRepository(),
+		membershipRepo:      membershipRepo.NewMembershipRepository(),
+		userRepo:            userRepo.NewUserRepository(),
+		notificationService: notificationService.NewNotificationService(),
+	}
+}
+
+// OnStatusChange completes the stage the order left and starts the stage it entered,
+// with a due time counted in the branch's business hours. Failures are logged only.
+func (s *SLAService) OnStatusChange(ctx context.Context, order *model.ServiceOrder) {
+	if !s.slaRepo.Available() {
+		return
+	}
+
+	now := time.Now()
+	stage, open := model.SLAStageFor(order.Status)
+	if err := s.slaRepo.CloseOpenStages(ctx, order.ID, stage, now); err != nil {
+		log.Printf("Failed to complete SLA stages of order %s: %v", order.ID, err)
+	}
+	if !open {
+		return
+	}
+
+	policy := s.resolvePolicy(ctx, order)
+	calendar := s.calendar(ctx, order.BranchID)
+	minutes := policy.StageMinutes(stage)
+	record := &model.OrderSLA{
+		OrderID:   order.ID,
+		Stage:     stage,
+		BranchID:  order.BranchID,
+		Priority:  policy.Priority,
+		StartedAt: now,
+		RiskAt:    calendar.add(now, minutes*policy.AtRiskPercent/100),
+		DueAt:     calendar.add(now, minutes),
+	}
+	if policy.ID != uuid.Nil {
+		record.PolicyID = &policy.ID
+	}
+	if err := s.slaRepo.StartStage(ctx, record); err != nil {
+		log.Printf("Failed to start %s SLA of order %s: %v", stage, order.ID, err)
+	}
+}
+
+// CheckDue sends one escalation to the branch admins when an order becomes at risk and
+// one when it breaches. Safe to run on several instances at once.
+func (s *SLAService) CheckDue(ctx context.Context) error {
+	if !s.slaRepo.Available() {
+		return nil
+	}
+
+	now := time.Now()
+	stages, err := s.slaRepo.ListPendingAlerts(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, stage := range stages {
+		order, err := s.orderRepo.GetByID(ctx, stage.OrderID)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Printf("Failed to load order %s for SLA check: %v", stage.OrderID, err)
+				continue
+			}
+			// Deleted orders no longer count
+			if err := s.slaRepo.CompleteStage(ctx, stage.ID, now); err != nil {
+				log.Printf("Failed to complete SLA stage %s: %v", stage.ID, err)
+			}
+			continue
+		}
+
+		// Orders moved on outside the order service, e.g. by an online payment
+		if current, ok := model.SLAStageFor(order.Status); !ok || current != stage.Stage {
+			if err := s.slaRepo.CompleteStage(ctx, stage.ID, order.UpdatedAt); err != nil {
+				log.Printf("Failed to complete SLA stage %s: %v", stage.ID, err)
+			}
+			continue
+		}
+
+		breach := !now.Before(stage.DueAt)
+		claimed, err := s.slaRepo.MarkNotified(ctx, stage.ID, breach)
+		if err != nil {
+			log.Printf("Failed to claim SLA escalation %s: %v", stage.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if breach && stage.AtRiskNotifiedAt == nil {
+			// A breach found first makes the at-risk warning pointless
+			if _, err := s.slaRepo.MarkNotified(ctx, stage.ID, false); err != nil {
+				log.Printf("Failed to claim SLA escalation %s: %v", stage.ID, err)
+			}
+		}
+		s.escalate(ctx, order, stage, breach)
+	}
+	return nil
+}
+
+// ListAtRisk retrieves the open orders that are about to miss or have missed their SLA
+func (s *SLAService) ListAtRisk(ctx context.Context, branchID *uuid.UUID) ([]model.AtRiskOrder, error) {
+	if branchID != nil && !model.BranchScopeFromContext(ctx).Allows(*branchID) {
+		return nil, model.ErrForbidden
+	}
+	result := []model.AtRiskOrder{}
+	if !s.slaRepo.Available() {
+		return result, nil
+	}
+
+	now := time.Now()
+	orders, err := s.slaRepo.ListAtRisk(ctx, branchID, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range orders {
+		// Stages the order has left are completed by the next check
+		if current, ok := model.SLAStageFor(order.Status); !ok || current != order.Stage {
+			continue
+		}
+		order.SLAStatus = model.SLAStatusAtRisk
+		if !now.Before(order.DueAt) {
+			order.SLAStatus = model.SLAStatusBreached
+		}
+		order.RemainingMinutes = remainingMinutes(now, order.DueAt)
+		result = append(result, order)
+	}
+	return result, nil
+}
+
+// GetOrderSLA retrieves the SLA stages of an order and the time it spent in each status
+func (s *SLAService) GetOrderSLA(ctx context.Context, orderID uuid.UUID) (*model.OrderSLAResponse, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+	if !model.BranchScopeFromContext(ctx).Allows(order.BranchID) {
+		return nil, model.ErrForbidden
+	}
+
+	response := &model.OrderSLAResponse{
+		OrderID:      order.ID,
+		Status:       order.Status,
+		Stages:       []model.OrderSLAStageResponse{},
+		TimeInStatus: []model.StatusDuration{},
+	}
+	if !s.slaRepo.Available() {
+		return response, nil
+	}
+
+	now := time.Now()
+	stages, err := s.slaRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, stage := range stages {
+		item := model.OrderSLAStageResponse{
+			Stage:       stage.Stage,
+			Status:      stage.StatusAt(now),
+			StartedAt:   stage.StartedAt,
+			DueAt:       stage.DueAt,
+			CompletedAt: stage.CompletedAt,
+		}
+		if stage.CompletedAt == nil {
+			item.RemainingMinutes = remainingMinutes(now, stage.DueAt)
+		}
+		response.Stages = append(response.Stages, item)
+		response.Priority = stage.Priority
+	}
+
+	events, err := s.eventRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	response.TimeInStatus = timeInStatus(events, now)
+	return response, nil
+}
+
+// ListPolicies retrieves all SLA policies
+func (s *SLAService) ListPolicies(ctx context.Context) ([]*model.SLAPolicy, error) {
+	if !s.slaRepo.Available() {
+		return []*model.SLAPolicy{}, nil
+	}
+	return s.slaRepo.ListPolicies(ctx)
+}
+
+// CreatePolicy creates an SLA policy for a service type and membership tier
+func (s *SLAService) CreatePolicy(ctx context.Context, req *model.SLAPolicyRequest) (*model.SLAPolicy, error) {
+	if !s.slaRepo.Available() {
+		return nil, errors.New("SLA policies are not available")
+	}
+
+	policy := &model.SLAPolicy{IsActive: true}
+	applyPolicyRequest(policy, req)
+	exists, err := s.slaRepo.PolicyExists(ctx, policy.ServiceType, policy.MembershipTier, nil)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, model.ErrSLAPolicyExists
+	}
+
+	if err := s.slaRepo.CreatePolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// UpdatePolicy changes an SLA policy. Stages already started keep their due times.
+func (s *SLAService) UpdatePolicy(ctx context.Context, id uuid.UUID, req *model.SLAPolicyRequest) (*model.SLAPolicy, error) {
+	if !s.slaRepo.Available() {
+		return nil, model.ErrSLAPolicyNotFound
+	}
+	policy, err := s.slaRepo.GetPolicy(ctx, id)
+	if err != nil {
+		return nil, model.ErrSLAPolicyNotFound
+	}
+
+	applyPolicyRequest(policy, req)
+	exists, err := s.slaRepo.PolicyExists(ctx, policy.ServiceType, policy.MembershipTier, &policy.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, model.ErrSLAPolicyExists
+	}
+
+	if err := s.slaRepo.UpdatePolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// DeletePolicy deletes an SLA policy
+func (s *SLAService) DeletePolicy(ctx context.Context, id uuid.UUID) error {
+	if !s.slaRepo.Available() {
+		return model.ErrSLAPolicyNotFound
+	}
+	return s.slaRepo.DeletePolicy(ctx, id)
+}
+
+// GetBusinessHours retrieves the business hours of a branch
+func (s *SLAService) GetBusinessHours(ctx context.Context, branchID uuid.UUID) ([]*model.BranchBusinessHours, error) {
+	if !model.BranchScopeFromContext(ctx).Allows(branchID) {
+		return nil, model.ErrForbidden
+	}
+	if !s.slaRepo.Available() {
+		return []*model.BranchBusinessHours{}, nil
+	}
+	return s.slaRepo.ListBusinessHours(ctx, branchID)
+}
+
+// UpdateBusinessHours replaces the weekly business hours of a branch. An empty list
+// falls back to the default opening hours.
+func (s *SLAService) UpdateBusinessHours(ctx context.Context, branchID uuid.UUID, req *model.BranchBusinessHoursRequest) ([]*model.BranchBusinessHours, error) {
+	if !model.BranchScopeFromContext(ctx).Allows(branchID) {
+		return nil, model.ErrForbidden
+	}
+	if !s.slaRepo.Available() {
+		return nil, errors.New("business hours are not available")
+	}
+	if _, err := s.branchRepo.GetByID(ctx, branchID); err != nil {
+		return nil, model.ErrBranchNotFound
+	}
+
+	hours := make([]*model.BranchBusinessHours, 0, len(req.Hours))
+	for _, h := range req.Hours {
+		if h.CloseTime <= h.OpenTime {
+			return nil, model.ErrInvalidBusinessHours
+		}
+		hours = append(hours, &model.BranchBusinessHours{
+			BranchID:  branchID,
+			Weekday:   *h.Weekday,
+			OpenTime:  h.OpenTime,
+			CloseTime: h.CloseTime,
+		})
+	}
+
+	if err := s.slaRepo.ReplaceBusinessHours(ctx, branchID, hours); err != nil {
+		return nil, err
+	}
+	return s.slaRepo.ListBusinessHours(ctx, branchID)
+}
+
+// resolvePolicy returns the most specific active policy for the order's service type and
+// the customer's membership tier, or the configured defaults
+func (s *SLAService) resolvePolicy(ctx context.Context, order *model.ServiceOrder) *model.SLAPolicy {
+	fallback := defaultPolicy()
+	policies, err := s.slaRepo.ListPolicies(ctx)
+	if err != nil {
+		log.Printf("Failed to load SLA policies: %v", err)
+		return fallback
+	}
+	if len(policies) == 0 {
+		return fallback
+	}
+
+	tier := s.customerTier(ctx, order.CustomerID)
+	var best *model.SLAPolicy
+	bestScore := -1
+	for _, policy := range policies {
+		if score, ok := policy.Matches(order.ServiceType, tier); ok && score > bestScore {
+			best, bestScore = policy, score
+		}
+	}
+	if best == nil {
+		return fallback
+	}
+	if best.AtRiskPercent <= 0 || best.AtRiskPercent >= 100 {
+		best.AtRiskPercent = fallback.AtRiskPercent
+	}
+	return best
+}
+
+// customerTier returns the tier of the customer's active membership, if any
+func (s *SLAService) customerTier(ctx context.Context, customerID uuid.UUID) model.MembershipTier {
+	membership, err := s.membershipRepo.GetByUserID(ctx, customerID)
+	if err != nil {
+		return ""
+	}
+	if membership.Status != model.MembershipStatusActive && membership.Status != model.MembershipStatusTrial {
+		return ""
+	}
+	return membership.Tier
+}
+
+// calendar returns the business calendar of a branch
+func (s *SLAService) calendar(ctx context.Context, branchID uuid.UUID) businessCalendar {
+	hours, err := s.slaRepo.ListBusinessHours(ctx, branchID)
+	if err != nil {
+		log.Printf("Failed to load business hours of branch %s: %v", branchID, err)
+	}
+
+	open, closing := defaultOpenTime, defaultCloseTime
+	if config.Config != nil && config.Config.SLADefaultOpenTime != "" && config.Config.SLADefaultCloseTime != "" {
+		open, closing = config.Config.SLADefaultOpenTime, config.Config.SLADefaultCloseTime
+	}
+	return newBusinessCalendar(hours, open, closing)
+}
+
+// escalate notifies the admins of the order's branch. Failures are logged only.
+func (s *SLAService) escalate(ctx context.Context, order *model.ServiceOrder, stage *model.OrderSLA, breach bool) {
+	admins, err := s.userRepo.GetByBranchID(ctx, order.BranchID)
+	if err != nil {
+		log.Printf("Failed to load admins of branch %s for SLA escalation: %v", order.BranchID, err)
+		return
+	}
+
+	due := stage.DueAt.In(time.Local).Format("02 Jan 15:04")
+	title := "SLA At Risk"
+	message := fmt.Sprintf("Order %s is about to miss its %s SLA, due %s", order.OrderNumber, stage.Stage, due)
+	if breach {
+		title = "SLA Breached"
+		message = fmt.Sprintf("Order %s missed its %s SLA, due %s", order.OrderNumber, stage.Stage, due)
+	}
+
+	orderID := order.ID.String()
+	for _, admin := range admins {
+		if admin.Role != model.RoleAdminCabang || !admin.IsActive {
+			continue
+		}
+		if _, err := s.notificationService.SendNotification(ctx, &model.NotificationRequest{
+			UserID:  admin.ID.String(),
+			OrderID: &orderID,
+			Type:    model.NotificationTypePush,
+			Title:   title,
+			Message: message,
+		}); err != nil {
+			log.Printf("Failed to send SLA escalation for order %s: %v", order.ID, err)
+		}
+	}
+}
+
+// defaultPolicy returns the targets used when no policy matches
+func defaultPolicy() *model.SLAPolicy {
+	pickup, repair, delivery := defaultPickupTarget, defaultRepairTarget, defaultDeliveryTarget
+	atRisk := defaultAtRiskPercent
+	if config.Config != nil {
+		if config.Config.SLADefaultPickupTarget > 0 {
+			pickup = config.Config.SLADefaultPickupTarget
+		}
+		if config.Config.SLADefaultRepairTarget > 0 {
+			repair = config.Config.SLADefaultRepairTarget
+		}
+		if config.Config.SLADefaultDeliveryTarget > 0 {
+			delivery = config.Config.SLADefaultDeliveryTarget
+		}
+		if config.Config.SLAAtRiskPercent > 0 && config.Config.SLAAtRiskPercent < 100 {
+			atRisk = config.Config.SLAAtRiskPercent
+		}
+	}
+	return &model.SLAPolicy{
+		Name:            "default",
+		PickupMinutes:   int(pickup.Minutes()),
+		RepairMinutes:   int(repair.Minutes()),
+		DeliveryMinutes: int(delivery.Minutes()),
+		AtRiskPercent:   atRisk,
+		IsActive:        true,
+	}
+}
+
+// applyPolicyRequest copies the request onto a policy
+func applyPolicyRequest(policy *model.SLAPolicy, req *model.SLAPolicyRequest) {
+	policy.Name = req.Name
+	policy.ServiceType = req.ServiceType
+	policy.MembershipTier = req.MembershipTier
+	policy.PickupMinutes = req.PickupMinutes
+	policy.RepairMinutes = req.RepairMinutes
+	policy.DeliveryMinutes = req.DeliveryMinutes
+	policy.AtRiskPercent = req.AtRiskPercent
+	if policy.AtRiskPercent == 0 {
+		policy.AtRiskPercent = defaultAtRiskPercent
+	}
+	policy.Priority = req.Priority
+	if req.IsActive != nil {
+		policy.IsActive = *req.IsActive
+	}
+}
+
+// timeInStatus turns the status history of an order into the time spent in each status.
+// The final status of a closed order has no duration.
+func timeInStatus(events []*model.OrderStatusEvent, now time.Time) []model.StatusDuration {
+	durations := []model.StatusDuration{}
+	for i, event := range events {
+		// Repeated updates to the same status are one stay
+		if len(durations) > 0 && durations[len(durations)-1].Status == event.Status {
+			continue
+		}
+
+		var until time.Time
+		for _, next := range events[i+1:] {
+			if next.Status != event.Status {
+				until = next.CreatedAt
+				break
+			}
+		}
+
+		item := model.StatusDuration{Status: event.Status, Since: event.CreatedAt}
+		switch {
+		case !until.IsZero():
+			item.Until = &until
+			item.Minutes = int(until.Sub(event.CreatedAt).Minutes())
+		case event.Status == model.StatusCompleted || event.Status == model.StatusCancelled:
+			item.Until = &item.Since
+		default:
+			item.Minutes = int(now.Sub(event.CreatedAt).Minutes())
+		}
+		durations = append(durations, item)
+	}
+	return durations
+}
+
+// remainingMinutes returns the whole minutes until due, negative once past
+func remainingMinutes(now, due time.Time) int {
+	return int(math.Floor(due.Sub(now).Minutes()))
+}
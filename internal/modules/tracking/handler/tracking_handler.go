@@ -0,0 +1,365 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"service/internal/modules/tracking/service"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// WebSocket timings, matching the chat socket
+const (
+	socketWriteWait  = 10 * time.Second
+	socketPongWait   = 60 * time.Second
+	socketPingPeriod = 54 * time.Second
+)
+
+// TrackingHandler handles live courier location endpoints
+type TrackingHandler struct {
+	trackingService *service.TrackingService
+	upgrader        websocket.Upgrader
+}
+
+// NewTrackingHandler creates a new tracking handler
+func NewTrackingHandler() *TrackingHandler {
+	return &TrackingHandler{
+		trackingService: service.NewTrackingService(),
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				return true // Allow all origins in development
+			},
+		},
+	}
+}
+
+// socketError is sent over a socket when a message cannot be handled
+type socketError struct {
+	Type    string `json:"type"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// RecordLocation godoc
+// @Summary Send courier location
+// @Description Send a GPS ping of the courier while the order is on the way. Only the assigned courier can send pings.
+// @Tags tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body model.CourierLocationRequest true "GPS ping"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /courier/orders/{id}/location [post]
+func (h *TrackingHandler) RecordLocation(c *gin.Context) {
+	orderID, userID, ok := parseTrackingRequest(c)
+	if !ok {
+		return
+	}
+
+	var req model.CourierLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return
+	}
+
+	position, err := h.trackingService.RecordPing(c.Request.Context(), orderID, userID, &req)
+	if err != nil {
+		respondTrackingError(c, "location_record_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(position, "Location recorded successfully"))
+}
+
+// LocationSocket godoc
+// @Summary Stream courier location
+// @Description Open a WebSocket on which the courier sends GPS pings as JSON. Every ping is answered with the stored position, or a stopped message once the order is no longer on the way.
+// @Tags tracking
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 400 {object} model.ErrorResponse
+// @Router /courier/orders/{id}/location/ws [get]
+func (h *TrackingHandler) LocationSocket(c *gin.Context) {
+	orderID, userID, ok := parseTrackingRequest(c)
+	if !ok {
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	conn.SetReadLimit(1024)
+	conn.SetReadDeadline(time.Now().Add(socketPongWait))
+	conn.SetPongHandler(func(string) error {
+		conn.SetReadDeadline(time.Now().Add(socketPongWait))
+		return nil
+	})
+
+	ctx := c.Request.Context()
+	for {
+		var req model.CourierLocationRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				log.Printf("Courier location socket error: %v", err)
+			}
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(socketPongWait))
+
+		var reply interface{}
+		if err := utils.ValidateStruct(&req); err != nil {
+			reply = socketError{Type: "error", Code: "validation_error", Message: err.Error()}
+		} else if position, err := h.trackingService.RecordPing(ctx, orderID, userID, &req); err != nil {
+			if err == model.ErrTrackingInactive {
+				writeSocketJSON(conn, model.CourierLocationEvent{Type: model.CourierLocationEventStopped, OrderID: orderID})
+				return
+			}
+			reply = socketError{Type: "error", Code: "location_record_failed", Message: err.Error()}
+		} else {
+			reply = model.CourierLocationEvent{
+				Type:     model.CourierLocationEventLocation,
+				OrderID:  orderID,
+				Status:   position.Status,
+				Position: position,
+			}
+		}
+
+		if err := writeSocketJSON(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// GetCourierLocation godoc
+// @Summary Get courier location
+// @Description Get where the courier of an order is and when they are expected at the pickup address
+// @Tags tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /orders/{id}/courier-location [get]
+func (h *TrackingHandler) GetCourierLocation(c *gin.Context) {
+	orderID, userID, ok := parseTrackingRequest(c)
+	if !ok {
+		return
+	}
+
+	position, err := h.trackingService.GetPosition(c.Request.Context(), orderID, userID, userRole(c))
+	if err != nil {
+		respondTrackingError(c, "location_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(position, "Courier location retrieved successfully"))
+}
+
+// SubscribeCourierLocation godoc
+// @Summary Follow courier location
+// @Description Open a WebSocket that pushes every new courier position of an order with its ETA. A stopped message is sent and the socket closed when the courier part of the order ends.
+// @Tags tracking
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /orders/{id}/courier-location/ws [get]
+func (h *TrackingHandler) SubscribeCourierLocation(c *gin.Context) {
+	orderID, userID, ok := parseTrackingRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	// Authorise before upgrading so that errors are plain HTTP responses
+	events, unsubscribe, latest, err := h.trackingService.Subscribe(ctx, orderID, userID, userRole(c))
+	if err != nil {
+		respondTrackingError(c, "location_subscribe_failed", err)
+		return
+	}
+	defer unsubscribe()
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	// Subscribers only listen; reading detects when they go away
+	go func() {
+		defer cancel()
+		conn.SetReadLimit(512)
+		conn.SetReadDeadline(time.Now().Add(socketPongWait))
+		conn.SetPongHandler(func(string) error {
+			conn.SetReadDeadline(time.Now().Add(socketPongWait))
+			return nil
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if latest != nil {
+		if err := writeSocketJSON(conn, model.CourierLocationEvent{
+			Type:     model.CourierLocationEventLocation,
+			OrderID:  orderID,
+			Status:   latest.Status,
+			Position: latest,
+		}); err != nil {
+			return
+		}
+	}
+
+	ticker := time.NewTicker(socketPingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeSocketJSON(conn, event); err != nil {
+				return
+			}
+			if event.Type == model.CourierLocationEventStopped {
+				conn.SetWriteDeadline(time.Now().Add(socketWriteWait))
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "tracking stopped"))
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(socketWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// GetCourierTrail godoc
+// @Summary Get courier trail
+// @Description Get the thinned trail the courier drove for an order
+// @Tags tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /orders/{id}/courier-trail [get]
+func (h *TrackingHandler) GetCourierTrail(c *gin.Context) {
+	orderID, _, ok := parseTrackingRequest(c)
+	if !ok {
+		return
+	}
+
+	trail, err := h.trackingService.GetTrail(c.Request.Context(), orderID)
+	if err != nil {
+		respondTrackingError(c, "trail_fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(trail, "Courier trail retrieved successfully"))
+}
+
+// parseTrackingRequest reads the order ID from the path and the authenticated user
+func parseTrackingRequest(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid order ID format",
+			nil,
+		))
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, model.CreateErrorResponse(
+			"unauthorized",
+			"User ID not found in context",
+			nil,
+		))
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+			"internal_error",
+			"Invalid user ID type",
+			nil,
+		))
+		return uuid.Nil, uuid.Nil, false
+	}
+	return orderID, userUUID, true
+}
+
+// userRole reads the role of the authenticated user
+func userRole(c *gin.Context) model.UserRole {
+	value, _ := c.Get("user_role")
+	role, _ := value.(model.UserRole)
+	return role
+}
+
+// writeSocketJSON writes one JSON message with a deadline
+func writeSocketJSON(conn *websocket.Conn, v interface{}) error {
+	conn.SetWriteDeadline(time.Now().Add(socketWriteWait))
+	return conn.WriteJSON(v)
+}
+
+// respondTrackingError maps tracking errors to HTTP status codes
+func respondTrackingError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case model.ErrOrderNotFound, model.ErrCourierLocationUnknown:
+		status = http.StatusNotFound
+	case model.ErrNotOrderCourier:
+		status = http.StatusForbidden
+	case model.ErrTrackingInactive:
+		status = http.StatusConflict
+	}
+	c.JSON(status, model.CreateErrorResponse(code, err.Error(), nil))
+}
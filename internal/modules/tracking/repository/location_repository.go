@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// memoryLocations keeps positions and subscribers in process when Redis is not configured
+var memoryLocations = struct {
+	mu          sync.RWMutex
+	latest      map[uuid.UUID]*model.CourierPosition
+	subscribers map[uuid.UUID]map[chan model.CourierLocationEvent]struct{}
+}{
+	latest:      make(map[uuid.UUID]*model.CourierPosition),
+	subscribers: make(map[uuid.UUID]map[chan model.CourierLocationEvent]struct{}),
+}
+
+// LocationRepository stores the latest courier position in Redis and the thinned trail in Postgres
+type LocationRepository struct {
+	db    *gorm.DB
+	redis *redis.Client
+}
+
+// NewLocationRepository creates a new location repository
+func NewLocationRepository() *LocationRepository {
+	return &LocationRepository{
+		db:    database.DB,
+		redis: database.Redis,
+	}
+}
+
+// SaveLatest stores the latest position of an order's courier for the given time
+func (r *LocationRepository) SaveLatest(ctx context.Context, position *model.CourierPosition, ttl time.Duration) error {
+	if r.redis == nil {
+		memoryLocations.mu.Lock()
+		memoryLocations.latest[position.OrderID] = position
+		memoryLocations.mu.Unlock()
+		return nil
+	}
+
+	data, err := json.Marshal(position)
+	if err != nil {
+		return err
+	}
+	return r.redis.Set(ctx, latestKey(position.OrderID), data, ttl).Err()
+}
+
+// GetLatest retrieves the latest position of an order's courier, or nil when none is known
+func (r *LocationRepository) GetLatest(ctx context.Context, orderID uuid.UUID) (*model.CourierPosition, error) {
+	if r.redis == nil {
+		memoryLocations.mu.RLock()
+		defer memoryLocations.mu.RUnlock()
+		return memoryLocations.latest[orderID], nil
+	}
+
+	data, err := r.redis.Get(ctx, latestKey(orderID)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var position model.CourierPosition
+	if err := json.Unmarshal(data, &position); err != nil {
+		return nil, err
+	}
+	return &position, nil
+}
+
+// DeleteLatest forgets the latest position of an order's courier
+func (r *LocationRepository) DeleteLatest(ctx context.Context, orderID uuid.UUID) error {
+	if r.redis == nil {
+		memoryLocations.mu.Lock()
+		delete(memoryLocations.latest, orderID)
+		memoryLocations.mu.Unlock()
+		return nil
+	}
+	return r.redis.Del(ctx, latestKey(orderID)).Err()
+}
+
+// Publish sends an event to everyone following the order, on every instance
+func (r *LocationRepository) Publish(ctx context.Context, event *model.CourierLocationEvent) error {
+	if r.redis == nil {
+		memoryLocations.mu.RLock()
+		defer memoryLocations.mu.RUnlock()
+		for ch := range memoryLocations.subscribers[event.OrderID] {
+			select {
+			case ch <- *event:
+			default:
+				// Slow subscribers miss a ping; the next one supersedes it anyway
+			}
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return r.redis.Publish(ctx, channelKey(event.OrderID), data).Err()
+}
+
+// Subscribe follows the events of an order until the context ends or the returned
+// function is called
+func (r *LocationRepository) Subscribe(ctx context.Context, orderID uuid.UUID) (<-chan model.CourierLocationEvent, func()) {
+	events := make(chan model.CourierLocationEvent, 16)
+
+	if r.redis == nil {
+		memoryLocations.mu.Lock()
+		if memoryLocations.subscribers[orderID] == nil {
+			memoryLocations.subscribers[orderID] = make(map[chan model.CourierLocationEvent]struct{})
+		}
+		memoryLocations.subscribers[orderID][events] = struct{}{}
+		memoryLocations.mu.Unlock()
+
+		var once sync.Once
+		return events, func() {
+			once.Do(func() {
+				memoryLocations.mu.Lock()
+				delete(memoryLocations.subscribers[orderID], events)
+				if len(memoryLocations.subscribers[orderID]) == 0 {
+					delete(memoryLocations.subscribers, orderID)
+				}
+				memoryLocations.mu.Unlock()
+			})
+		}
+	}
+
+	pubsub := r.redis.Subscribe(ctx, channelKey(orderID))
+	go func() {
+		defer close(events)
+		for message := range pubsub.Channel() {
+			var event model.CourierLocationEvent
+			if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+				continue
+			}
+			select {
+			case events <- event:
+			default:
+			}
+		}
+	}()
+	return events, func() { pubsub.Close() }
+}
+
+// AddTrailPoint stores a point of the courier's trail
+func (r *LocationRepository) AddTrailPoint(ctx context.Context, point *model.CourierLocation) error {
+	if r.db == nil {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(point).Error
+}
+
+// LastTrailPoint retrieves the newest trail point of an order, or nil when there is none
+func (r *LocationRepository) LastTrailPoint(ctx context.Context, orderID uuid.UUID) (*model.CourierLocation, error) {
+	if r.db == nil {
+		return nil, nil
+	}
+	var point model.CourierLocation
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("recorded_at DESC").
+		Limit(1).
+		Find(&point).Error
+	if err != nil || point.ID == uuid.Nil {
+		return nil, err
+	}
+	return &point, nil
+}
+
+// ListTrail retrieves the trail of an order in driving order
+func (r *LocationRepository) ListTrail(ctx context.Context, orderID uuid.UUID) ([]*model.CourierLocation, error) {
+	if r.db == nil {
+		return []*model.CourierLocation{}, nil
+	}
+	var points []*model.CourierLocation
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("recorded_at ASC").
+		Find(&points).Error
+	return points, err
+}
+
+// latestKey is the Redis key of an order's latest courier position
+func latestKey(orderID uuid.UUID) string {
+	return "courier_location:" + orderID.String()
+}
+
+// channelKey is the Redis channel of an order's courier location events
+func channelKey(orderID uuid.UUID) string {
+	return "courier_location_events:" + orderID.String()
+}
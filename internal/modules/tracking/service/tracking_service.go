@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	orderRepo "service/internal/modules/orders/repository"
+	"service/internal/modules/tracking/repository"
+	"service/internal/shared/config"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tracking defaults used when no configuration is loaded
+const (
+	defaultLocationTTL      = 10 * time.Minute
+	defaultTrailMinInterval = 30 * time.Second
+	defaultTrailMinDistance = 50 // meters
+	defaultCourierSpeedKmh  = 25
+)
+
+// TrackingService records courier GPS pings and shares them with the customer of the order
+type TrackingService struct {
+	locationRepo *repository.LocationRepository
+	orderRepo    *orderRepo.ServiceOrderRepository
+}
+
+// NewTrackingService creates a new tracking service
+func NewTrackingService() *TrackingService {
+	return &TrackingService{
+		locationRepo: repository.NewLocationRepository(),
+		orderRepo:    orderRepo.NewServiceOrderRepository(),
+	}
+}
+
+// RecordPing stores a GPS ping of the courier of an order and pushes it to the order's
+// subscribers. Only the assigned courier can ping, and only while the order is on the way.
+func (s *TrackingService) RecordPing(ctx context.Context, orderID, courierID uuid.UUID, req *model.CourierLocationRequest) (*model.CourierPosition, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+	if order.CourierID == nil || *order.CourierID != courierID {
+		return nil, model.ErrNotOrderCourier
+	}
+	if !order.IsCourierTracked() {
+		return nil, model.ErrTrackingInactive
+	}
+
+	recordedAt := time.Now()
+	if req.RecordedAt != nil && !req.RecordedAt.IsZero() && req.RecordedAt.Before(recordedAt) {
+		recordedAt = *req.RecordedAt
+	}
+
+	// Pings arriving out of order must not move the courier backwards
+	latest, err := s.locationRepo.GetLatest(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && recordedAt.Before(latest.RecordedAt) {
+		return latest, nil
+	}
+
+	position := &model.CourierPosition{
+		OrderID:    orderID,
+		CourierID:  courierID,
+		Status:     order.Status,
+		Latitude:   req.Latitude,
+		Longitude:  req.Longitude,
+		Accuracy:   req.Accuracy,
+		Heading:    req.Heading,
+		Speed:      req.Speed,
+		RecordedAt: recordedAt,
+	}
+	estimateArrival(position, order)
+
+	if err := s.locationRepo.SaveLatest(ctx, position, locationTTL()); err != nil {
+		return nil, err
+	}
+	if err := s.locationRepo.Publish(ctx, &model.CourierLocationEvent{
+		Type:     model.CourierLocationEventLocation,
+		OrderID:  orderID,
+		Status:   order.Status,
+		Position: position,
+	}); err != nil {
+		log.Printf("Failed to publish courier location of order %s: %v", orderID, err)
+	}
+
+	s.addTrailPoint(ctx, position)
+	return position, nil
+}
+
+// GetPosition retrieves the latest position of the courier of an order with the ETA
+func (s *TrackingService) GetPosition(ctx context.Context, orderID, userID uuid.UUID, role model.UserRole) (*model.CourierPosition, error) {
+	order, err := s.getTrackedOrder(ctx, orderID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if !order.IsCourierTracked() {
+		return nil, model.ErrTrackingInactive
+	}
+
+	position, err := s.locationRepo.GetLatest(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if position == nil {
+		return nil, model.ErrCourierLocationUnknown
+	}
+
+	// The order may have moved on since the ping, e.g. from pickup to delivery
+	position.Status = order.Status
+	estimateArrival(position, order)
+	return position, nil
+}
+
+// Subscribe follows the courier location of an order. The latest known position, if any,
+// is returned so that the subscriber can draw it right away.
+func (s *TrackingService) Subscribe(ctx context.Context, orderID, userID uuid.UUID, role model.UserRole) (<-chan model.CourierLocationEvent, func(), *model.CourierPosition, error) {
+	order, err := s.getTrackedOrder(ctx, orderID, userID, role)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !order.IsCourierTracked() {
+		return nil, nil, nil, model.ErrTrackingInactive
+	}
+
+	events, unsubscribe := s.locationRepo.Subscribe(ctx, orderID)
+	latest, err := s.locationRepo.GetLatest(ctx, orderID)
+	if err != nil {
+		unsubscribe()
+		return nil, nil, nil, err
+	}
+	if latest != nil {
+		latest.Status = order.Status
+		estimateArrival(latest, order)
+	}
+	return events, unsubscribe, latest, nil
+}
+
+// GetTrail retrieves the thinned trail the courier drove for an order
+func (s *TrackingService) GetTrail(ctx context.Context, orderID uuid.UUID) ([]*model.CourierLocation, error) {
+	if _, err := s.orderRepo.GetByID(ctx, orderID); err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+	return s.locationRepo.ListTrail(ctx, orderID)
+}
+
+// StopIfInactive ends tracking once an order has left the courier states: the latest
+// position is dropped and subscribers are told to stop. Failures are logged only.
+func (s *TrackingService) StopIfInactive(ctx context.Context, order *model.ServiceOrder) {
+	if order.IsCourierTracked() {
+		return
+	}
+	if err := s.locationRepo.DeleteLatest(ctx, order.ID); err != nil {
+		log.Printf("Failed to clear courier location of order %s: %v", order.ID, err)
+	}
+	if err := s.locationRepo.Publish(ctx, &model.CourierLocationEvent{
+		Type:    model.CourierLocationEventStopped,
+		OrderID: order.ID,
+		Status:  order.Status,
+	}); err != nil {
+		log.Printf("Failed to publish end of tracking for order %s: %v", order.ID, err)
+	}
+}
+
+// getTrackedOrder retrieves an order the user may follow: customers only their own orders,
+// couriers only the orders they carry and staff the orders of their branches
+func (s *TrackingService) getTrackedOrder(ctx context.Context, orderID, userID uuid.UUID, role model.UserRole) (*model.ServiceOrder, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, model.ErrOrderNotFound
+	}
+	switch role {
+	case model.RolePelanggan:
+		if order.CustomerID != userID {
+			return nil, model.ErrOrderNotFound
+		}
+	case model.RoleKurir:
+		if order.CourierID == nil || *order.CourierID != userID {
+			return nil, model.ErrOrderNotFound
+		}
+	}
+	return order, nil
+}
+
+// addTrailPoint stores the position in the trail when the courier has moved far enough
+// and long enough since the previous point. Failures are logged only.
+func (s *TrackingService) addTrailPoint(ctx context.Context, position *model.CourierPosition) {
+	last, err := s.locationRepo.LastTrailPoint(ctx, position.OrderID)
+	if err != nil {
+		log.Printf("Failed to load courier trail of order %s: %v", position.OrderID, err)
+		return
+	}
+	if last != nil {
+		if position.RecordedAt.Sub(last.RecordedAt) < trailMinInterval() {
+			return
+		}
+		movedMeters := utils.CalculateDistance(last.Latitude, last.Longitude, position.Latitude, position.Longitude) * 1000
+		if movedMeters < float64(trailMinDistance()) {
+			return
+		}
+	}
+
+	if err := s.locationRepo.AddTrailPoint(ctx, &model.CourierLocation{
+		OrderID:    position.OrderID,
+		CourierID:  position.CourierID,
+		Latitude:   position.Latitude,
+		Longitude:  position.Longitude,
+		Accuracy:   position.Accuracy,
+		RecordedAt: position.RecordedAt,
+	}); err != nil {
+		log.Printf("Failed to store courier trail of order %s: %v", position.OrderID, err)
+	}
+}
+
+// estimateArrival fills in the distance to the customer's address and the ETA. The
+// courier's own speed is used while moving, otherwise the configured average.
+func estimateArrival(position *model.CourierPosition, order *model.ServiceOrder) {
+	distance := utils.CalculateDistance(position.Latitude, position.Longitude, order.PickupLatitude, order.PickupLongitude)
+	speed := averageSpeed()
+	if position.Speed >= 5 {
+		speed = position.Speed
+	}
+
+	minutes := int(math.Ceil(distance / speed * 60))
+	position.DistanceKm = math.Round(distance*100) / 100
+	position.ETAMinutes = minutes
+	position.EstimatedArrival = position.RecordedAt.Add(time.Duration(minutes) * time.Minute)
+}
+
+// locationTTL returns how long a position is shown after the last ping
+func locationTTL() time.Duration {
+	if config.Config != nil && config.Config.TrackingLocationTTL > 0 {
+		return config.Config.TrackingLocationTTL
+	}
+	return defaultLocationTTL
+}
+
+// trailMinInterval returns the minimum time between two trail points
+func trailMinInterval() time.Duration {
+	if config.Config != nil && config.Config.TrackingTrailMinInterval > 0 {
+		return config.Config.TrackingTrailMinInterval
+	}
+	return defaultTrailMinInterval
+}
+
+// trailMinDistance returns the minimum distance in meters between two trail points
+func trailMinDistance() int {
+	if config.Config != nil && config.Config.TrackingTrailMinDistance > 0 {
+		return config.Config.TrackingTrailMinDistance
+	}
+	return defaultTrailMinDistance
+}
+
+// averageSpeed returns the courier speed in km/h assumed for ETAs
+func averageSpeed() float64 {
+	if config.Config != nil && config.Config.DispatchAverageSpeedKmh > 0 {
+		return float64(config.Config.DispatchAverageSpeedKmh)
+	}
+	return defaultCourierSpeedKmh
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	orderRepo "service/internal/modules/orders/repository"
+	"service/internal/modules/tracking/repository"
+	"service/internal/shared/database/dbtest"
+	"service/internal/shared/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// trackedOrder creates an order on its way to the customer in an in-memory order store
+func trackedOrder(t *testing.T, s *TrackingService, status model.OrderStatus) *model.ServiceOrder {
+	courierID := uuid.New()
+	order := &model.ServiceOrder{
+		ID:              uuid.New(),
+		CustomerID:      uuid.New(),
+		BranchID:        uuid.New(),
+		CourierID:       &courierID,
+		Status:          status,
+		PickupLatitude:  -6.2,
+		PickupLongitude: 106.8,
+	}
+	assert.NoError(t, s.orderRepo.Create(context.Background(), order))
+	return order
+}
+
+// memoryTrackingService uses the in-process position store and no trail database
+func memoryTrackingService() *TrackingService {
+	return &TrackingService{
+		locationRepo: &repository.LocationRepository{},
+		orderRepo:    orderRepo.NewServiceOrderRepository(),
+	}
+}
+
+func TestRecordPing(t *testing.T) {
+	ctx := context.Background()
+	s := memoryTrackingService()
+	onPickup := trackedOrder(t, s, model.StatusOnPickup)
+	inService := trackedOrder(t, s, model.StatusInService)
+	ping := &model.CourierLocationRequest{Latitude: -6.2, Longitude: 106.85}
+
+	tests := []struct {
+		name      string
+		orderID   uuid.UUID
+		courierID uuid.UUID
+		want      error
+	}{
+		{"unknown order", uuid.New(), *onPickup.CourierID, model.ErrOrderNotFound},
+		{"other courier", onPickup.ID, uuid.New(), model.ErrNotOrderCourier},
+		{"order not on the way", inService.ID, *inService.CourierID, model.ErrTrackingInactive},
+		{"assigned courier on the way", onPickup.ID, *onPickup.CourierID, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			position, err := s.RecordPing(ctx, tt.orderID, tt.courierID, ping)
+			assert.Equal(t, tt.want, err)
+			if tt.want != nil {
+				assert.Nil(t, position)
+				return
+			}
+			assert.Equal(t, model.StatusOnPickup, position.Status)
+			assert.Greater(t, position.DistanceKm, 0.0)
+			assert.Greater(t, position.ETAMinutes, 0)
+		})
+	}
+}
+
+func TestRecordPingPublishesAndIgnoresStalePings(t *testing.T) {
+	ctx := context.Background()
+	s := memoryTrackingService()
+	order := trackedOrder(t, s, model.StatusReady)
+	events, unsubscribe := s.locationRepo.Subscribe(ctx, order.ID)
+	defer unsubscribe()
+
+	now := time.Now()
+	_, err := s.RecordPing(ctx, order.ID, *order.CourierID, &model.CourierLocationRequest{Latitude: -6.21, Longitude: 106.81, RecordedAt: &now})
+	assert.NoError(t, err)
+
+	select {
+	case event := <-events:
+		assert.Equal(t, model.CourierLocationEventLocation, event.Type)
+		assert.Equal(t, -6.21, event.Position.Latitude)
+	default:
+		t.Fatal("subscribers are sent the new position")
+	}
+
+	// A ping the device took earlier arrives late and must not move the courier back
+	earlier := now.Add(-time.Minute)
+	position, err := s.RecordPing(ctx, order.ID, *order.CourierID, &model.CourierLocationRequest{Latitude: -6.3, Longitude: 106.9, RecordedAt: &earlier})
+	assert.NoError(t, err)
+	assert.Equal(t, -6.21, position.Latitude)
+	assert.Len(t, events, 0)
+
+	// Timestamps from the future are clamped to the time of arrival
+	later := time.Now().Add(time.Hour)
+	position, err = s.RecordPing(ctx, order.ID, *order.CourierID, &model.CourierLocationRequest{Latitude: -6.22, Longitude: 106.82, RecordedAt: &later})
+	assert.NoError(t, err)
+	assert.True(t, position.RecordedAt.Before(later))
+}
+
+func TestGetPositionAccess(t *testing.T) {
+	ctx := context.Background()
+	s := memoryTrackingService()
+	order := trackedOrder(t, s, model.StatusOnPickup)
+	unpinged := trackedOrder(t, s, model.StatusOnPickup)
+	_, err := s.RecordPing(ctx, order.ID, *order.CourierID, &model.CourierLocationRequest{Latitude: -6.2, Longitude: 106.85})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		orderID uuid.UUID
+		userID  uuid.UUID
+		role    model.UserRole
+		want    error
+	}{
+		{"own customer", order.ID, order.CustomerID, model.RolePelanggan, nil},
+		{"other customer", order.ID, uuid.New(), model.RolePelanggan, model.ErrOrderNotFound},
+		{"assigned courier", order.ID, *order.CourierID, model.RoleKurir, nil},
+		{"other courier", order.ID, uuid.New(), model.RoleKurir, model.ErrOrderNotFound},
+		{"staff", order.ID, uuid.New(), model.RoleKasir, nil},
+		{"no ping yet", unpinged.ID, unpinged.CustomerID, model.RolePelanggan, model.ErrCourierLocationUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			position, err := s.GetPosition(ctx, tt.orderID, tt.userID, tt.role)
+			assert.Equal(t, tt.want, err)
+			assert.Equal(t, tt.want == nil, position != nil)
+		})
+	}
+}
+
+func TestStopIfInactive(t *testing.T) {
+	ctx := context.Background()
+	s := memoryTrackingService()
+	order := trackedOrder(t, s, model.StatusOnPickup)
+	_, err := s.RecordPing(ctx, order.ID, *order.CourierID, &model.CourierLocationRequest{Latitude: -6.2, Longitude: 106.85})
+	assert.NoError(t, err)
+	events, unsubscribe := s.locationRepo.Subscribe(ctx, order.ID)
+	defer unsubscribe()
+
+	// Still on the way: nothing changes
+	s.StopIfInactive(ctx, order)
+	assert.Len(t, events, 0)
+
+	order.Status = model.StatusInService
+	s.StopIfInactive(ctx, order)
+
+	latest, err := s.locationRepo.GetLatest(ctx, order.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, latest)
+	event := <-events
+	assert.Equal(t, model.CourierLocationEventStopped, event.Type)
+	assert.Equal(t, model.StatusInService, event.Status)
+
+	_, err = s.GetPosition(ctx, order.ID, order.CustomerID, model.RolePelanggan)
+	assert.Equal(t, model.ErrTrackingInactive, err)
+}
+
+func TestAddTrailPoint(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		last   []driver.Value // recorded_at, latitude, longitude of the newest trail point
+		moved  float64
+		stored bool
+	}{
+		{"first point", nil, 0, true},
+		{"far and long enough", []driver.Value{now.Add(-time.Minute), -6.2, 106.8}, 0.001, true},
+		{"too soon", []driver.Value{now.Add(-10 * time.Second), -6.2, 106.8}, 0.001, false},
+		{"too close", []driver.Value{now.Add(-time.Minute), -6.2, 106.8}, 0.0002, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := dbtest.MockGlobal(t)
+			s := &TrackingService{locationRepo: repository.NewLocationRepository()}
+			orderID := uuid.New()
+
+			rows := sqlmock.NewRows([]string{"id", "order_id", "recorded_at", "latitude", "longitude"})
+			if tt.last != nil {
+				rows.AddRow(append([]driver.Value{uuid.New(), orderID}, tt.last...)...)
+			}
+			mock.ExpectQuery(`SELECT \* FROM "courier_locations" WHERE order_id = \$1 ORDER BY recorded_at DESC`).
+				WithArgs(orderID, 1).
+				WillReturnRows(rows)
+			if tt.stored {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO "courier_locations"`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+				mock.ExpectCommit()
+			}
+
+			s.addTrailPoint(context.Background(), &model.CourierPosition{
+				OrderID:    orderID,
+				CourierID:  uuid.New(),
+				Latitude:   -6.2 + tt.moved,
+				Longitude:  106.8,
+				RecordedAt: now,
+			})
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestEstimateArrival(t *testing.T) {
+	order := &model.ServiceOrder{PickupLatitude: -6.2, PickupLongitude: 106.8}
+	recordedAt := time.Date(2026, time.October, 14, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		speed   float64
+		minutes int
+	}{
+		// About 11.1 km north of the customer
+		{"standing still uses the average speed", 0, 27},
+		{"crawling uses the average speed", 4, 27},
+		{"moving uses the courier's speed", 50, 14},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			position := &model.CourierPosition{Latitude: -6.1, Longitude: 106.8, Speed: tt.speed, RecordedAt: recordedAt}
+			estimateArrival(position, order)
+			assert.Equal(t, 11.12, position.DistanceKm)
+			assert.Equal(t, tt.minutes, position.ETAMinutes)
+			assert.Equal(t, recordedAt.Add(time.Duration(tt.minutes)*time.Minute), position.EstimatedArrival)
+		})
+	}
+}
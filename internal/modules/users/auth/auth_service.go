@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"service/internal/modules/users/repository"
+	"service/internal/modules/users/service"
 	"service/internal/shared/model"
 	"service/internal/shared/utils"
 	"time"
@@ -15,18 +16,26 @@ import (
 
 // AuthService handles authentication business logic
 type AuthService struct {
-	userRepo *repository.UserRepository
+	userRepo            *repository.UserRepository
+	verificationService *service.VerificationService
+	deviceService       *service.DeviceService
 }
 
 // NewAuthService creates a new auth service
 func NewAuthService() *AuthService {
 	return &AuthService{
-		userRepo: repository.NewUserRepository(),
+		userRepo:            repository.NewUserRepository(),
+		verificationService: service.NewVerificationService(),
+		deviceService:       service.NewDeviceService(),
 	}
 }
 
-// Register registers a new user
+// Register registers a new customer. Staff accounts are created through invitations.
 func (s *AuthService) Register(ctx context.Context, req *model.UserRequest) (*model.UserResponse, error) {
+	if req.Role != model.RolePelanggan {
+		return nil, model.ErrStaffRegistration
+	}
+
 	// Check if email already exists
 	emailExists, err := s.userRepo.CheckEmailExists(ctx, req.Email, nil)
 	if err != nil {
@@ -75,6 +84,9 @@ func (s *AuthService) Register(ctx context.Context, req *model.UserRequest) (*mo
 		return nil, err
 	}
 
+	// Contacts start unverified; send the email link and phone code
+	s.verificationService.SendInitialVerifications(ctx, user.ID)
+
 	// Return user response
 	response := user.ToResponse()
 	return &response, nil
@@ -98,13 +110,24 @@ func (s *AuthService) Login(ctx context.Context, req *model.LoginRequest) (*mode
 		return nil, model.ErrInvalidPassword
 	}
 
-	// Generate JWT tokens
-	accessToken, err := utils.GenerateAccessToken(user.ID, model.UserRole(user.Role))
+	// Verify second factor when enrolled
+	if user.MFAEnabled {
+		if req.MFACode == "" {
+			return nil, model.ErrMFARequired
+		}
+		if !utils.ValidateTOTP(user.MFASecret, req.MFACode) {
+			return nil, model.ErrInvalidMFACode
+		}
+	}
+
+	// Generate JWT tokens for a new session
+	sessionID := uuid.NewString()
+	accessToken, err := utils.GenerateAccessToken(user.ID, model.UserRole(user.Role), sessionID)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := utils.GenerateRefreshToken(user.ID)
+	refreshToken, err := utils.GenerateRefreshToken(user.ID, sessionID)
 	if err != nil {
 		return nil, err
 	}
@@ -144,14 +167,20 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *model.RefreshTokenR
 		return nil, errors.New("user account is deactivated")
 	}
 
+	// Keep the session; tokens issued before sessions existed start a new one
+	sessionID := claims.SessionID
+	if sessionID == "" {
+		sessionID = uuid.NewString()
+	}
+
 	// Generate new access token
-	accessToken, err := utils.GenerateAccessToken(user.ID, model.UserRole(user.Role))
+	accessToken, err := utils.GenerateAccessToken(user.ID, model.UserRole(user.Role), sessionID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Generate new refresh token (rotation)
-	refreshToken, err := utils.GenerateRefreshToken(user.ID)
+	refreshToken, err := utils.GenerateRefreshToken(user.ID, sessionID)
 	if err != nil {
 		return nil, err
 	}
@@ -172,13 +201,16 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *model.RefreshTokenR
 	return response, nil
 }
 
-// Logout revokes a refresh token
+// Logout revokes a refresh token and unregisters the push devices of its session
 func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
 	parsed, err := utils.ParseRefreshToken(refreshToken)
 	if err != nil || parsed.ExpiresAt == nil {
 		return model.ErrInvalidToken
 	}
-	return utils.RevokeRefreshToken(ctx, refreshToken, parsed.ExpiresAt.Time)
+	if err := utils.RevokeRefreshToken(ctx, refreshToken, parsed.ExpiresAt.Time); err != nil {
+		return err
+	}
+	return s.deviceService.RemoveSession(ctx, parsed.UserID, parsed.SessionID)
 }
 
 // ChangePassword changes user password
@@ -272,7 +304,7 @@ func (s *AuthService) GetProfile(ctx context.Context, userID uuid.UUID) (*model.
 }
 
 // UpdateProfile updates user profile
-func (s *AuthService) UpdateProfile(ctx context.Context, userID uuid.UUID, req *model.UserRequest) (*model.UserResponse, error) {
+func (s *AuthService) UpdateProfile(ctx context.Context, userID uuid.UUID, req *model.ProfileRequest) (*model.UserResponse, error) {
 	// Get user by ID
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -298,25 +330,19 @@ func (s *AuthService) UpdateProfile(ctx context.Context, userID uuid.UUID, req *
 	}
 
 	// Update user fields
-	user.Email = req.Email
+	contactChanged := user.SetContact(req.Email, req.Phone)
 	user.FullName = req.FullName
-	user.Phone = req.Phone
-	user.Role = req.Role
-
-	// Set branch ID if provided
-	if req.BranchID != nil {
-		branchUUID, err := uuid.Parse(*req.BranchID)
-		if err != nil {
-			return nil, errors.New("invalid branch ID")
-		}
-		user.BranchID = &branchUUID
-	}
 
 	// Save user
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, err
 	}
 
+	// A new email or phone has to be verified again
+	if contactChanged {
+		s.verificationService.SendInitialVerifications(ctx, user.ID)
+	}
+
 	// Return user response
 	response := user.ToResponse()
 	return &response, nil
@@ -342,13 +368,34 @@ func (s *AuthService) GetUser(ctx context.Context, id uuid.UUID) (*model.UserRes
 	return &resp, nil
 }
 
-// UpdateUser updates a user by ID (admin)
-func (s *AuthService) UpdateUser(ctx context.Context, id uuid.UUID, req *model.UserRequest) (*model.UserResponse, error) {
+// UpdateUser updates a user by ID (admin). Changing the role needs canManageRoles, the
+// role.manage permission that also guards AssignRole. Both the user's current branch and
+// a new one must be inside the caller's branch scope.
+func (s *AuthService) UpdateUser(ctx context.Context, id uuid.UUID, req *model.UserRequest, canManageRoles bool) (*model.UserResponse, error) {
 	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, model.ErrUserNotFound
 	}
 
+	scope := model.BranchScopeFromContext(ctx)
+	if !scopeAllowsBranch(scope, user.BranchID) {
+		return nil, model.ErrForbidden
+	}
+	branchID := user.BranchID
+	if req.BranchID != nil {
+		bid, err := uuid.Parse(*req.BranchID)
+		if err != nil {
+			return nil, errors.New("invalid branch ID")
+		}
+		branchID = &bid
+	}
+	if !scopeAllowsBranch(scope, branchID) {
+		return nil, model.ErrForbidden
+	}
+	if req.Role != user.Role && !canManageRoles {
+		return nil, model.ErrForbidden
+	}
+
 	// Check uniqueness
 	emailExists, err := s.userRepo.CheckEmailExists(ctx, req.Email, &id)
 	if err != nil {
@@ -366,16 +413,10 @@ func (s *AuthService) UpdateUser(ctx context.Context, id uuid.UUID, req *model.U
 		return nil, model.ErrPhoneExists
 	}
 
-	user.Email = req.Email
+	user.SetContact(req.Email, req.Phone)
 	user.FullName = req.FullName
-	user.Phone = req.Phone
 	user.Role = req.Role
-
-	if req.BranchID != nil {
-		if bid, e := uuid.Parse(*req.BranchID); e == nil {
-			user.BranchID = &bid
-		}
-	}
+	user.BranchID = branchID
 
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, err
@@ -385,6 +426,15 @@ func (s *AuthService) UpdateUser(ctx context.Context, id uuid.UUID, req *model.U
 	return &resp, nil
 }
 
+// scopeAllowsBranch reports whether a user in the given branch is inside the scope. Users
+// without a branch can only be managed by callers that are not limited to branches.
+func scopeAllowsBranch(scope *model.BranchScope, branchID *uuid.UUID) bool {
+	if branchID == nil {
+		return !scope.IsRestricted()
+	}
+	return scope.Allows(*branchID)
+}
+
 // DeleteUser deletes a user by ID (admin)
 func (s *AuthService) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	// ensure exists
@@ -394,17 +444,15 @@ func (s *AuthService) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	return s.userRepo.Delete(ctx, id)
 }
 
-// UpdateFCMToken updates FCM token for a user
-func (s *AuthService) UpdateFCMToken(ctx context.Context, userID uuid.UUID, fcmToken string) error {
-	// Get user
-	user, err := s.userRepo.GetByID(ctx, userID)
-	if err != nil {
+// UpdateFCMToken registers an FCM token as a push device of the current session.
+// Kept for app versions that predate device registration.
+func (s *AuthService) UpdateFCMToken(ctx context.Context, userID uuid.UUID, sessionID, fcmToken string) error {
+	// Ensure user exists
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
 		return model.ErrUserNotFound
 	}
 
-	// Update FCM token
-	user.FCMToken = fcmToken
-	if err := s.userRepo.Update(ctx, user); err != nil {
+	if _, err := s.deviceService.Register(ctx, userID, sessionID, &model.RegisterDeviceRequest{Token: fcmToken}); err != nil {
 		return fmt.Errorf("failed to update FCM token: %w", err)
 	}
 
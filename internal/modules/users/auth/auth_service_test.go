@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"service/internal/shared/model"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// registerUser stores a user in the in-memory repository
+func registerUser(t *testing.T, s *AuthService, role model.UserRole, branchID *uuid.UUID) *model.User {
+	id := uuid.New()
+	user := &model.User{
+		ID:       id,
+		Email:    fmt.Sprintf("%s@example.com", id),
+		Phone:    id.String()[:12],
+		FullName: "Budi Santoso",
+		Role:     role,
+		BranchID: branchID,
+	}
+	assert.NoError(t, s.userRepo.Register(context.Background(), user))
+	return user
+}
+
+func TestUpdateUser(t *testing.T) {
+	branchA, branchB := uuid.New(), uuid.New()
+	scopeA := model.NewBranchScopeForUser(model.RoleAdminCabang, &branchA)
+	other := branchB.String()
+
+	tests := []struct {
+		name           string
+		scope          *model.BranchScope
+		role           model.UserRole
+		branch         *uuid.UUID
+		newRole        model.UserRole
+		newBranch      *string
+		canManageRoles bool
+		wantErr        error
+	}{
+		{"edit within the branch", scopeA, model.RoleKasir, &branchA, model.RoleKasir, nil, false, nil},
+		{"promote without role.manage", scopeA, model.RoleKasir, &branchA, model.RoleAdminPusat, nil, false, model.ErrForbidden},
+		{"promote with role.manage", nil, model.RoleKasir, &branchA, model.RoleAdminCabang, nil, true, nil},
+		{"staff of another branch", scopeA, model.RoleKasir, &branchB, model.RoleKasir, nil, false, model.ErrForbidden},
+		{"move staff out of scope", scopeA, model.RoleKasir, &branchA, model.RoleKasir, &other, false, model.ErrForbidden},
+		{"user without a branch", scopeA, model.RoleAdminPusat, nil, model.RoleAdminPusat, nil, false, model.ErrForbidden},
+		{"move between branches unrestricted", nil, model.RoleKasir, &branchA, model.RoleKasir, &other, false, nil},
+	}
+
+	s := NewAuthService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := registerUser(t, s, tt.role, tt.branch)
+			ctx := context.Background()
+			if tt.scope != nil {
+				ctx = model.WithBranchScope(ctx, tt.scope)
+			}
+			req := &model.UserRequest{
+				Email:    user.Email,
+				Phone:    user.Phone,
+				FullName: "Budi S.",
+				Role:     tt.newRole,
+				BranchID: tt.newBranch,
+			}
+
+			_, err := s.UpdateUser(ctx, user.ID, req, tt.canManageRoles)
+			assert.Equal(t, tt.wantErr, err)
+
+			stored, _ := s.userRepo.GetByID(context.Background(), user.ID)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.role, stored.Role)
+				assert.Equal(t, tt.branch, stored.BranchID)
+				return
+			}
+			assert.Equal(t, tt.newRole, stored.Role)
+			assert.Equal(t, "Budi S.", stored.FullName)
+		})
+	}
+}
+
+func TestUpdateProfileKeepsRoleAndBranch(t *testing.T) {
+	s := NewAuthService()
+	branchID := uuid.New()
+	user := registerUser(t, s, model.RolePelanggan, &branchID)
+
+	resp, err := s.UpdateProfile(context.Background(), user.ID, &model.ProfileRequest{
+		Email:    user.Email,
+		Phone:    user.Phone,
+		FullName: "Budi S.",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Budi S.", resp.FullName)
+
+	stored, _ := s.userRepo.GetByID(context.Background(), user.ID)
+	assert.Equal(t, model.RolePelanggan, stored.Role)
+	assert.Equal(t, &branchID, stored.BranchID)
+}
@@ -0,0 +1,275 @@
+package handler
+
+import (
+	"net/http"
+	userRepository "service/internal/modules/users/repository"
+	"service/internal/modules/users/service"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// APIKeyHandler handles API key management endpoints
+type APIKeyHandler struct {
+	apiKeyService *service.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler() *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: service.NewAPIKeyService(),
+	}
+}
+
+// CreateAPIKey godoc
+// @Summary Create API key
+// @Description Issue an API key for a partner integration. The key is only returned once.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.APIKeyRequest true "API key data"
+// @Success 201 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /admin/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, model.CreateErrorResponse(
+			"unauthorized",
+			"User not authenticated",
+			nil,
+		))
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+			"internal_error",
+			"Invalid user ID type",
+			nil,
+		))
+		return
+	}
+
+	var req model.APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return
+	}
+
+	utils.SanitizeStructStrings(&req)
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return
+	}
+
+	key, err := h.apiKeyService.CreateAPIKey(c.Request.Context(), userUUID, &req)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch err {
+		case model.ErrUserNotFound:
+			status = http.StatusNotFound
+		}
+		c.JSON(status, model.CreateErrorResponse("api_key_creation_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse(key, "API key created successfully. Store the key now, it will not be shown again"))
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description List API keys with optional owner and status filters
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param user_id query string false "Filter by owning user ID"
+// @Param active query bool false "Only return active keys"
+// @Success 200 {object} model.PaginatedResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /admin/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	filters := &userRepository.APIKeyFilters{
+		ActiveOnly: c.Query("active") == "true",
+	}
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		if id, err := uuid.Parse(userIDStr); err == nil {
+			filters.UserID = &id
+		}
+	}
+
+	keys, total, err := h.apiKeyService.ListAPIKeys(c.Request.Context(), page, limit, filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+			"api_keys_fetch_failed",
+			err.Error(),
+			nil,
+		))
+		return
+	}
+
+	pagination := model.PaginationResponse{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}
+
+	c.JSON(http.StatusOK, model.PaginatedSuccessResponse(keys, pagination, "API keys retrieved successfully"))
+}
+
+// GetAPIKey godoc
+// @Summary Get API key
+// @Description Get API key details by ID. The secret itself is never returned.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API key ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /admin/api-keys/{id} [get]
+func (h *APIKeyHandler) GetAPIKey(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid API key ID format",
+			nil,
+		))
+		return
+	}
+
+	key, err := h.apiKeyService.GetAPIKey(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, model.CreateErrorResponse("api_key_not_found", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(key, "API key retrieved successfully"))
+}
+
+// UpdateAPIKey godoc
+// @Summary Update API key
+// @Description Update the name, scopes, rate limit or expiry of an API key
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API key ID"
+// @Param request body model.UpdateAPIKeyRequest true "API key data"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /admin/api-keys/{id} [put]
+func (h *APIKeyHandler) UpdateAPIKey(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid API key ID format",
+			nil,
+		))
+		return
+	}
+
+	var req model.UpdateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return
+	}
+
+	utils.SanitizeStructStrings(&req)
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return
+	}
+
+	key, err := h.apiKeyService.UpdateAPIKey(c.Request.Context(), id, &req)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch err {
+		case model.ErrAPIKeyNotFound, model.ErrUserNotFound:
+			status = http.StatusNotFound
+		}
+		c.JSON(status, model.CreateErrorResponse("api_key_update_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(key, "API key updated successfully"))
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke API key
+// @Description Revoke an API key so it can no longer be used
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API key ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid API key ID format",
+			nil,
+		))
+		return
+	}
+
+	if err := h.apiKeyService.RevokeAPIKey(c.Request.Context(), id); err != nil {
+		status := http.StatusInternalServerError
+		if err == model.ErrAPIKeyNotFound {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, model.CreateErrorResponse("api_key_revoke_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(nil, "API key revoked successfully"))
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 	"service/internal/modules/users/auth"
+	"service/internal/shared/middleware"
 	"service/internal/shared/model"
 	"service/internal/shared/utils"
 	"strconv"
@@ -64,6 +65,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		statusCode := http.StatusInternalServerError
 		if err == model.ErrEmailExists || err == model.ErrPhoneExists {
 			statusCode = http.StatusConflict
+		} else if err == model.ErrStaffRegistration {
+			statusCode = http.StatusForbidden
 		}
 		c.JSON(statusCode, model.CreateErrorResponse(
 			"registration_failed",
@@ -112,7 +115,8 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	response, err := h.authService.Login(c.Request.Context(), &req)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		if err == model.ErrUserNotFound || err == model.ErrInvalidPassword {
+		switch err {
+		case model.ErrUserNotFound, model.ErrInvalidPassword, model.ErrMFARequired, model.ErrInvalidMFACode:
 			statusCode = http.StatusUnauthorized
 		}
 		c.JSON(statusCode, model.CreateErrorResponse(
@@ -128,7 +132,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 // UpdateFCMToken godoc
 // @Summary Update FCM token
-// @Description Update Firebase Cloud Messaging token for push notifications
+// @Description Register a Firebase Cloud Messaging token as a push device of the current session. Prefer POST /auth/devices.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -184,7 +188,7 @@ func (h *AuthHandler) UpdateFCMToken(c *gin.Context) {
 	}
 
 	// Update FCM token
-	if err := h.authService.UpdateFCMToken(c.Request.Context(), userUUID, req.FCMToken); err != nil {
+	if err := h.authService.UpdateFCMToken(c.Request.Context(), userUUID, currentSessionID(c), req.FCMToken); err != nil {
 		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
 			"update_fcm_token_failed",
 			err.Error(),
@@ -248,7 +252,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 
 // Logout godoc
 // @Summary Logout
-// @Description Revoke refresh token
+// @Description Revoke refresh token and unregister the push devices of its session
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -333,12 +337,12 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 
 // UpdateProfile godoc
 // @Summary Update user profile
-// @Description Update current user profile information
+// @Description Update the name, email and phone of the current user. Role and branch cannot be changed here.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param request body dto.UserRequest true "Profile update data"
+// @Param request body dto.ProfileRequest true "Profile update data"
 // @Success 200 {object} dto.APIResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
@@ -367,7 +371,7 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	var req model.UserRequest
+	var req model.ProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
 			"validation_error",
@@ -685,7 +689,7 @@ func (h *AuthHandler) GetUser(c *gin.Context) {
 
 // UpdateUser godoc
 // @Summary Update user (admin)
-// @Description Update user by ID (admin only)
+// @Description Update user by ID (admin only). Changing the role needs the role.manage permission, and the user's current and new branch must be within the caller's branches.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -694,7 +698,9 @@ func (h *AuthHandler) GetUser(c *gin.Context) {
 // @Param request body dto.UserRequest true "User data"
 // @Success 200 {object} dto.APIResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /admin/users/{id} [put]
 func (h *AuthHandler) UpdateUser(c *gin.Context) {
@@ -728,11 +734,17 @@ func (h *AuthHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	user, err := h.authService.UpdateUser(c.Request.Context(), id, &req)
+	canManageRoles := middleware.HasPermissions(c, model.PermissionRoleManage)
+	user, err := h.authService.UpdateUser(c.Request.Context(), id, &req, canManageRoles)
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err == model.ErrUserNotFound {
+		switch err {
+		case model.ErrUserNotFound:
 			status = http.StatusNotFound
+		case model.ErrForbidden:
+			status = http.StatusForbidden
+		case model.ErrEmailExists, model.ErrPhoneExists:
+			status = http.StatusConflict
 		}
 		c.JSON(status, model.CreateErrorResponse("user_update_failed", err.Error(), nil))
 		return
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"net/http"
+	"service/internal/modules/users/service"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DeviceHandler handles push device registration endpoints
+type DeviceHandler struct {
+	deviceService *service.DeviceService
+}
+
+// NewDeviceHandler creates a new device handler
+func NewDeviceHandler() *DeviceHandler {
+	return &DeviceHandler{
+		deviceService: service.NewDeviceService(),
+	}
+}
+
+// RegisterDevice godoc
+// @Summary Register push device
+// @Description Register the calling device for push notifications. Registering a known token refreshes it. The device is removed when this session logs out.
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.RegisterDeviceRequest true "Device data"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Router /auth/devices [post]
+func (h *DeviceHandler) RegisterDevice(c *gin.Context) {
+	userUUID, ok := deviceUserID(c)
+	if !ok {
+		return
+	}
+
+	var req model.RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return
+	}
+
+	utils.SanitizeStructStrings(&req)
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return
+	}
+
+	device, err := h.deviceService.Register(c.Request.Context(), userUUID, currentSessionID(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse("device_registration_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(device, "Device registered successfully"))
+}
+
+// ListDevices godoc
+// @Summary List push devices
+// @Description List the devices the current user receives push notifications on
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Router /auth/devices [get]
+func (h *DeviceHandler) ListDevices(c *gin.Context) {
+	userUUID, ok := deviceUserID(c)
+	if !ok {
+		return
+	}
+
+	devices, err := h.deviceService.List(c.Request.Context(), userUUID, currentSessionID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse("devices_fetch_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(devices, "Devices retrieved successfully"))
+}
+
+// RemoveDevice godoc
+// @Summary Remove push device
+// @Description Stop push notifications to one of the current user's devices
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /auth/devices/{id} [delete]
+func (h *DeviceHandler) RemoveDevice(c *gin.Context) {
+	userUUID, ok := deviceUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse("invalid_id", "Invalid device ID", nil))
+		return
+	}
+
+	if err := h.deviceService.Remove(c.Request.Context(), userUUID, id); err != nil {
+		status := http.StatusInternalServerError
+		if err == model.ErrDeviceNotFound {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, model.CreateErrorResponse("device_remove_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(nil, "Device removed successfully"))
+}
+
+// deviceUserID reads the authenticated user ID, writing an error response when it is missing
+func deviceUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, model.CreateErrorResponse(
+			"unauthorized",
+			"User not authenticated",
+			nil,
+		))
+		return uuid.Nil, false
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+			"internal_error",
+			"Invalid user ID type",
+			nil,
+		))
+		return uuid.Nil, false
+	}
+
+	return userUUID, true
+}
+
+// currentSessionID returns the login session of the access token, empty for API keys and older tokens
+func currentSessionID(c *gin.Context) string {
+	sessionID, _ := c.Get("session_id")
+	id, _ := sessionID.(string)
+	return id
+}
@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"net/http"
+	userRepository "service/internal/modules/users/repository"
+	"service/internal/modules/users/service"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// InvitationHandler handles staff invitation endpoints
+type InvitationHandler struct {
+	invitationService *service.InvitationService
+}
+
+// NewInvitationHandler creates a new invitation handler
+func NewInvitationHandler() *InvitationHandler {
+	return &InvitationHandler{
+		invitationService: service.NewInvitationService(),
+	}
+}
+
+// CreateInvitation godoc
+// @Summary Invite staff member
+// @Description Invite a staff member by email or phone. They receive a single-use link to set their own password.
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.InvitationRequest true "Invitation data"
+// @Success 201 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /admin/invitations [post]
+func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, model.CreateErrorResponse(
+			"unauthorized",
+			"User not authenticated",
+			nil,
+		))
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+			"internal_error",
+			"Invalid user ID type",
+			nil,
+		))
+		return
+	}
+
+	userRole, _ := c.Get("user_role")
+	role, _ := userRole.(model.UserRole)
+
+	var req model.InvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return
+	}
+
+	utils.SanitizeStructStrings(&req)
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return
+	}
+
+	invitation, err := h.invitationService.CreateInvitation(c.Request.Context(), userUUID, role, &req)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch err {
+		case model.ErrForbidden:
+			status = http.StatusForbidden
+		case model.ErrEmailExists, model.ErrPhoneExists:
+			status = http.StatusConflict
+		case model.ErrRoleNotFound:
+			status = http.StatusNotFound
+		}
+		c.JSON(status, model.CreateErrorResponse("invitation_creation_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse(invitation, "Invitation sent successfully"))
+}
+
+// ListInvitations godoc
+// @Summary List invitations
+// @Description List staff invitations, optionally filtered by status or branch
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param status query string false "Filter by status (pending, accepted, revoked, expired)"
+// @Param branch_id query string false "Filter by branch ID"
+// @Success 200 {object} model.PaginatedResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /admin/invitations [get]
+func (h *InvitationHandler) ListInvitations(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	filters := &userRepository.InvitationFilters{
+		Status: model.InvitationStatus(c.Query("status")),
+	}
+	if branchStr := c.Query("branch_id"); branchStr != "" {
+		if id, err := uuid.Parse(branchStr); err == nil {
+			filters.BranchID = &id
+		}
+	}
+
+	invitations, total, err := h.invitationService.ListInvitations(c.Request.Context(), page, limit, filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+			"invitations_fetch_failed",
+			err.Error(),
+			nil,
+		))
+		return
+	}
+
+	pagination := model.PaginationResponse{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}
+
+	c.JSON(http.StatusOK, model.PaginatedSuccessResponse(invitations, pagination, "Invitations retrieved successfully"))
+}
+
+// ResendInvitation godoc
+// @Summary Resend invitation
+// @Description Send a new invite link. The previous link stops working.
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invitation ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /admin/invitations/{id}/resend [post]
+func (h *InvitationHandler) ResendInvitation(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid invitation ID format",
+			nil,
+		))
+		return
+	}
+
+	invitation, err := h.invitationService.ResendInvitation(c.Request.Context(), id)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err == model.ErrInvitationNotFound {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, model.CreateErrorResponse("invitation_resend_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(invitation, "Invitation resent successfully"))
+}
+
+// RevokeInvitation godoc
+// @Summary Revoke invitation
+// @Description Revoke a pending invitation
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invitation ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /admin/invitations/{id} [delete]
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid invitation ID format",
+			nil,
+		))
+		return
+	}
+
+	if err := h.invitationService.RevokeInvitation(c.Request.Context(), id); err != nil {
+		status := http.StatusBadRequest
+		if err == model.ErrInvitationNotFound {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, model.CreateErrorResponse("invitation_revoke_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(nil, "Invitation revoked successfully"))
+}
+
+// GetInvitationDetails godoc
+// @Summary Get invitation details
+// @Description Get the details of an invite link. When MFA is required, an authenticator secret is returned for enrolment.
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Param token query string true "Invitation token"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Router /auth/invitations/details [get]
+func (h *InvitationHandler) GetInvitationDetails(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invitation token is required",
+			nil,
+		))
+		return
+	}
+
+	details, err := h.invitationService.GetInvitationDetails(c.Request.Context(), token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse("invitation_invalid", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(details, "Invitation retrieved successfully"))
+}
+
+// AcceptInvitation godoc
+// @Summary Accept invitation
+// @Description Complete onboarding by setting a password (and MFA code when required)
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Param request body model.AcceptInvitationRequest true "Onboarding data"
+// @Success 201 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /auth/invitations/accept [post]
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	var req model.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return
+	}
+
+	utils.SanitizeStructStrings(&req)
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return
+	}
+
+	user, err := h.invitationService.AcceptInvitation(c.Request.Context(), &req)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch err {
+		case model.ErrMFARequired, model.ErrInvalidMFACode:
+			status = http.StatusUnauthorized
+		case model.ErrEmailExists, model.ErrPhoneExists:
+			status = http.StatusConflict
+		}
+		c.JSON(status, model.CreateErrorResponse("invitation_accept_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse(user, "Account created successfully"))
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+	"service/internal/shared/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler publishes the public keys used to sign JWTs
+type JWKSHandler struct{}
+
+// NewJWKSHandler creates a new JWKS handler
+func NewJWKSHandler() *JWKSHandler {
+	return &JWKSHandler{}
+}
+
+// GetJWKS godoc
+// @Summary JSON Web Key Set
+// @Description Public keys for verifying tokens issued by this service. Tokens carry the key ID in their kid header.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} model.JWKS
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	// New keys are published an hour before they sign, so a short cache is safe
+	c.Header("Cache-Control", "public, max-age=900")
+	c.JSON(http.StatusOK, utils.GetJWKS())
+}
@@ -0,0 +1,350 @@
+package handler
+
+import (
+	"net/http"
+	"service/internal/modules/users/service"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RoleHandler handles role and permission management endpoints
+type RoleHandler struct {
+	roleService *service.RoleService
+}
+
+// NewRoleHandler creates a new role handler
+func NewRoleHandler() *RoleHandler {
+	return &RoleHandler{
+		roleService: service.NewRoleService(),
+	}
+}
+
+// ListPermissions godoc
+// @Summary List permissions
+// @Description List all permissions that can be granted to roles
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /admin/permissions [get]
+func (h *RoleHandler) ListPermissions(c *gin.Context) {
+	c.JSON(http.StatusOK, model.SuccessResponse(h.roleService.ListPermissions(), "Permissions retrieved successfully"))
+}
+
+// GetMyPermissions godoc
+// @Summary Get my permissions
+// @Description Get the permissions granted to the authenticated user's role
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Router /auth/permissions [get]
+func (h *RoleHandler) GetMyPermissions(c *gin.Context) {
+	userRole, exists := c.Get("user_role")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, model.CreateErrorResponse(
+			"unauthorized",
+			"User role not found in context",
+			nil,
+		))
+		return
+	}
+
+	role, ok := userRole.(model.UserRole)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+			"internal_error",
+			"Invalid user role type",
+			nil,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(gin.H{
+		"role":        role,
+		"permissions": h.roleService.GetPermissions(c.Request.Context(), role),
+	}, "Permissions retrieved successfully"))
+}
+
+// ListRoles godoc
+// @Summary List roles
+// @Description List all built-in and custom roles with their permissions
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /admin/roles [get]
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.roleService.ListRoles(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+			"role_list_failed",
+			err.Error(),
+			nil,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(roles, "Roles retrieved successfully"))
+}
+
+// GetRole godoc
+// @Summary Get role
+// @Description Get a role and its permissions by ID
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /admin/roles/{id} [get]
+func (h *RoleHandler) GetRole(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid role ID format",
+			nil,
+		))
+		return
+	}
+
+	role, err := h.roleService.GetRole(c.Request.Context(), id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == model.ErrRoleNotFound {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, model.CreateErrorResponse("role_fetch_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(role, "Role retrieved successfully"))
+}
+
+// CreateRole godoc
+// @Summary Create role
+// @Description Create a custom role with a set of permissions
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.RoleRequest true "Role data"
+// @Success 201 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /admin/roles [post]
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var req model.RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return
+	}
+
+	utils.SanitizeStructStrings(&req)
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return
+	}
+
+	role, err := h.roleService.CreateRole(c.Request.Context(), &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err {
+		case model.ErrRoleExists:
+			status = http.StatusConflict
+		case model.ErrInvalidPermission:
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, model.CreateErrorResponse("role_creation_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse(role, "Role created successfully"))
+}
+
+// UpdateRole godoc
+// @Summary Update role
+// @Description Update a role's description and permissions
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role ID"
+// @Param request body model.RoleRequest true "Role data"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /admin/roles/{id} [put]
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid role ID format",
+			nil,
+		))
+		return
+	}
+
+	var req model.RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return
+	}
+
+	utils.SanitizeStructStrings(&req)
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return
+	}
+
+	role, err := h.roleService.UpdateRole(c.Request.Context(), id, &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err {
+		case model.ErrRoleNotFound:
+			status = http.StatusNotFound
+		case model.ErrRoleExists:
+			status = http.StatusConflict
+		case model.ErrInvalidPermission:
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, model.CreateErrorResponse("role_update_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(role, "Role updated successfully"))
+}
+
+// DeleteRole godoc
+// @Summary Delete role
+// @Description Delete a custom role that is not assigned to any user
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /admin/roles/{id} [delete]
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid role ID format",
+			nil,
+		))
+		return
+	}
+
+	if err := h.roleService.DeleteRole(c.Request.Context(), id); err != nil {
+		status := http.StatusInternalServerError
+		switch err {
+		case model.ErrRoleNotFound:
+			status = http.StatusNotFound
+		case model.ErrSystemRole:
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, model.CreateErrorResponse("role_delete_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(nil, "Role deleted successfully"))
+}
+
+// AssignRole godoc
+// @Summary Assign role to user
+// @Description Assign a built-in or custom role to a user
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body model.AssignRoleRequest true "Role assignment"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /admin/users/{id}/role [put]
+func (h *RoleHandler) AssignRole(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"invalid_id",
+			"Invalid user ID format",
+			nil,
+		))
+		return
+	}
+
+	var req model.AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return
+	}
+
+	user, err := h.roleService.AssignRole(c.Request.Context(), id, req.Role)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err {
+		case model.ErrUserNotFound, model.ErrRoleNotFound:
+			status = http.StatusNotFound
+		}
+		c.JSON(status, model.CreateErrorResponse("role_assign_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(user, "Role assigned successfully"))
+}
@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"net/http"
+	"service/internal/modules/users/service"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// VerificationHandler handles email and phone verification endpoints
+type VerificationHandler struct {
+	verificationService *service.VerificationService
+}
+
+// NewVerificationHandler creates a new verification handler
+func NewVerificationHandler() *VerificationHandler {
+	return &VerificationHandler{
+		verificationService: service.NewVerificationService(),
+	}
+}
+
+// GetVerificationStatus godoc
+// @Summary Get contact verification status
+// @Description Get whether the current user's email and phone number are verified
+// @Tags verification
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /auth/verification [get]
+func (h *VerificationHandler) GetVerificationStatus(c *gin.Context) {
+	userUUID, ok := verificationUserID(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.verificationService.GetStatus(c.Request.Context(), userUUID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, model.CreateErrorResponse("user_not_found", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(status, "Verification status retrieved successfully"))
+}
+
+// ResendEmailVerification godoc
+// @Summary Resend email verification
+// @Description Send a new verification link to the current user's email address. Earlier links stop working.
+// @Tags verification
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 429 {object} model.ErrorResponse
+// @Router /auth/verification/email/resend [post]
+func (h *VerificationHandler) ResendEmailVerification(c *gin.Context) {
+	userUUID, ok := verificationUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.verificationService.SendEmailVerification(c.Request.Context(), userUUID); err != nil {
+		respondVerificationSendError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(nil, "Verification email sent"))
+}
+
+// ResendPhoneVerification godoc
+// @Summary Resend phone verification code
+// @Description Send a new one-time code to the current user's phone number over WhatsApp. Earlier codes stop working.
+// @Tags verification
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 429 {object} model.ErrorResponse
+// @Router /auth/verification/phone/resend [post]
+func (h *VerificationHandler) ResendPhoneVerification(c *gin.Context) {
+	userUUID, ok := verificationUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.verificationService.SendPhoneVerification(c.Request.Context(), userUUID); err != nil {
+		respondVerificationSendError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(nil, "Verification code sent"))
+}
+
+// VerifyPhone godoc
+// @Summary Verify phone number
+// @Description Confirm the current user's phone number with the code sent over WhatsApp
+// @Tags verification
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.VerifyPhoneRequest true "Verification code"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Router /auth/verification/phone [post]
+func (h *VerificationHandler) VerifyPhone(c *gin.Context) {
+	userUUID, ok := verificationUserID(c)
+	if !ok {
+		return
+	}
+
+	var req model.VerifyPhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := h.verificationService.VerifyPhone(c.Request.Context(), userUUID, req.Code); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse("phone_verification_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(nil, "Phone number verified successfully"))
+}
+
+// VerifyEmail godoc
+// @Summary Verify email address
+// @Description Confirm an email address with the token from the verification link
+// @Tags verification
+// @Accept json
+// @Produce json
+// @Param request body model.VerifyEmailRequest true "Verification token"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Router /auth/verify-email [post]
+func (h *VerificationHandler) VerifyEmail(c *gin.Context) {
+	var req model.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Invalid request data",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+			"validation_error",
+			"Validation failed",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := h.verificationService.VerifyEmail(c.Request.Context(), req.Token); err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse("email_verification_failed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(nil, "Email address verified successfully"))
+}
+
+// verificationUserID reads the authenticated user ID, writing an error response when it is missing
+func verificationUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, model.CreateErrorResponse(
+			"unauthorized",
+			"User not authenticated",
+			nil,
+		))
+		return uuid.Nil, false
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+			"internal_error",
+			"Invalid user ID type",
+			nil,
+		))
+		return uuid.Nil, false
+	}
+
+	return userUUID, true
+}
+
+// respondVerificationSendError maps errors from sending a verification to HTTP responses
+func respondVerificationSendError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case model.ErrUserNotFound:
+		status = http.StatusNotFound
+	case model.ErrAlreadyVerified:
+		status = http.StatusConflict
+	case model.ErrVerificationThrottled:
+		status = http.StatusTooManyRequests
+	}
+	c.JSON(status, model.CreateErrorResponse("verification_send_failed", err.Error(), nil))
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository handles API key data operations
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *APIKeyRepository) Available() bool {
+	return r.db != nil
+}
+
+// Create creates a new API key
+func (r *APIKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// GetByID retrieves an API key by ID
+func (r *APIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.APIKey, error) {
+	var key model.APIKey
+	err := r.db.WithContext(ctx).First(&key, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetByHash retrieves an API key by the hash of its secret
+func (r *APIKeyRepository) GetByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	var key model.APIKey
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		First(&key, "key_hash = ?", hash).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// List retrieves API keys with filters
+func (r *APIKeyRepository) List(ctx context.Context, offset, limit int, filters *APIKeyFilters) ([]*model.APIKey, int64, error) {
+	var keys []*model.APIKey
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.APIKey{})
+
+	// Apply filters
+	if filters != nil {
+		if filters.UserID != nil {
+			query = query.Where("user_id = ?", *filters.UserID)
+		}
+		if filters.ActiveOnly {
+			query = query.Where("revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", time.Now())
+		}
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get paginated results
+	err := query.
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&keys).Error
+
+	return keys, total, err
+}
+
+// Update updates an API key
+func (r *APIKeyRepository) Update(ctx context.Context, key *model.APIKey) error {
+	return r.db.WithContext(ctx).Omit("User").Save(key).Error
+}
+
+// Revoke marks an API key as revoked
+func (r *APIKeyRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// TouchLastUsed records when and from where an API key was last used
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time, ip string) error {
+	return r.db.WithContext(ctx).
+		Model(&model.APIKey{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"last_used_at": usedAt,
+			"last_used_ip": ip,
+		}).Error
+}
+
+// APIKeyFilters represents filters for API key queries
+type APIKeyFilters struct {
+	UserID     *uuid.UUID
+	ActiveOnly bool
+}
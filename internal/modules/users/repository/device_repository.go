@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DeviceRepository handles push device data operations
+type DeviceRepository struct {
+	db *gorm.DB
+}
+
+// NewDeviceRepository creates a new device repository
+func NewDeviceRepository() *DeviceRepository {
+	return &DeviceRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *DeviceRepository) Available() bool {
+	return r.db != nil
+}
+
+// Upsert registers a device token. A token already registered, possibly by another
+// user who signed in on the same device before, is moved to the given user and session.
+func (r *DeviceRepository) Upsert(ctx context.Context, device *model.UserDevice) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "token"}},
+			DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "app_version", "device_name", "session_id", "last_seen_at", "updated_at"}),
+		}).
+		Create(device).Error
+}
+
+// ListByUser retrieves the devices of a user, most recently seen first
+func (r *DeviceRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.UserDevice, error) {
+	var devices []*model.UserDevice
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("last_seen_at DESC").
+		Find(&devices).Error
+	return devices, err
+}
+
+// ListActiveTokens retrieves the tokens of a user's devices seen since the given time
+func (r *DeviceRepository) ListActiveTokens(ctx context.Context, userID uuid.UUID, since time.Time) ([]string, error) {
+	var tokens []string
+	err := r.db.WithContext(ctx).
+		Model(&model.UserDevice{}).
+		Where("user_id = ? AND last_seen_at > ?", userID, since).
+		Order("last_seen_at DESC").
+		Pluck("token", &tokens).Error
+	return tokens, err
+}
+
+// ReplaceToken swaps a token for the canonical one reported by FCM.
+// When the canonical token is already registered the old row is simply dropped.
+func (r *DeviceRepository) ReplaceToken(ctx context.Context, oldToken, newToken string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.UserDevice{}).Where("token = ?", newToken).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return tx.Where("token = ?", oldToken).Delete(&model.UserDevice{}).Error
+		}
+		return tx.Model(&model.UserDevice{}).Where("token = ?", oldToken).Update("token", newToken).Error
+	})
+}
+
+// Delete removes a device of a user
+func (r *DeviceRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&model.UserDevice{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteByTokens removes devices whose tokens are no longer valid
+func (r *DeviceRepository) DeleteByTokens(ctx context.Context, tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("token IN ?", tokens).Delete(&model.UserDevice{}).Error
+}
+
+// DeleteBySession removes the devices registered by a login session
+func (r *DeviceRepository) DeleteBySession(ctx context.Context, userID uuid.UUID, sessionID string) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND session_id = ?", userID, sessionID).
+		Delete(&model.UserDevice{}).Error
+}
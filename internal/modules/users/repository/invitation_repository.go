@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InvitationRepository handles staff invitation data operations
+type InvitationRepository struct {
+	db *gorm.DB
+}
+
+// NewInvitationRepository creates a new invitation repository
+func NewInvitationRepository() *InvitationRepository {
+	return &InvitationRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *InvitationRepository) Available() bool {
+	return r.db != nil
+}
+
+// Create creates a new invitation
+func (r *InvitationRepository) Create(ctx context.Context, invitation *model.StaffInvitation) error {
+	return r.db.WithContext(ctx).Create(invitation).Error
+}
+
+// GetByID retrieves an invitation by ID
+func (r *InvitationRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.StaffInvitation, error) {
+	var invitation model.StaffInvitation
+	err := r.db.WithContext(ctx).
+		Scopes(model.ScopeByBranch(ctx, "branch_id")).
+		First(&invitation, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// List retrieves invitations with filters
+func (r *InvitationRepository) List(ctx context.Context, offset, limit int, filters *InvitationFilters) ([]*model.StaffInvitation, int64, error) {
+	var invitations []*model.StaffInvitation
+	var total int64
+
+	query := r.db.WithContext(ctx).
+		Scopes(model.ScopeByBranch(ctx, "branch_id")).
+		Model(&model.StaffInvitation{})
+
+	// Apply filters
+	if filters != nil {
+		if filters.BranchID != nil {
+			query = query.Where("branch_id = ?", *filters.BranchID)
+		}
+		switch filters.Status {
+		case "":
+		case model.InvitationStatusPending:
+			query = query.Where("status = ? AND expires_at > ?", model.InvitationStatusPending, time.Now())
+		case model.InvitationStatusExpired:
+			query = query.Where("status = ? AND expires_at <= ?", model.InvitationStatusPending, time.Now())
+		default:
+			query = query.Where("status = ?", filters.Status)
+		}
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get paginated results
+	err := query.
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&invitations).Error
+
+	return invitations, total, err
+}
+
+// HasPendingForContact reports whether an unexpired pending invitation exists for the email or phone
+func (r *InvitationRepository) HasPendingForContact(ctx context.Context, email, phone string) (bool, error) {
+	query := r.db.WithContext(ctx).
+		Model(&model.StaffInvitation{}).
+		Where("status = ? AND expires_at > ?", model.InvitationStatusPending, time.Now())
+
+	switch {
+	case email != "" && phone != "":
+		query = query.Where("email = ? OR phone = ?", email, phone)
+	case email != "":
+		query = query.Where("email = ?", email)
+	default:
+		query = query.Where("phone = ?", phone)
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// Update updates an invitation
+func (r *InvitationRepository) Update(ctx context.Context, invitation *model.StaffInvitation) error {
+	return r.db.WithContext(ctx).Omit("Branch").Save(invitation).Error
+}
+
+// Accept creates the invited user and marks the invitation as accepted in one transaction.
+// The status condition guarantees the invitation can only be used once.
+func (r *InvitationRepository) Accept(ctx context.Context, invitation *model.StaffInvitation, user *model.User) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&model.StaffInvitation{}).
+			Where("id = ? AND status = ? AND token_hash = ?", invitation.ID, model.InvitationStatusPending, invitation.TokenHash).
+			Updates(map[string]interface{}{
+				"status":      model.InvitationStatusAccepted,
+				"accepted_at": now,
+				"mfa_secret":  "",
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrInvitationInvalid
+		}
+
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		invitation.Status = model.InvitationStatusAccepted
+		invitation.AcceptedAt = &now
+		invitation.AcceptedUserID = &user.ID
+		return tx.Model(&model.StaffInvitation{}).
+			Where("id = ?", invitation.ID).
+			Update("accepted_user_id", user.ID).Error
+	})
+}
+
+// InvitationFilters represents filters for invitation queries
+type InvitationFilters struct {
+	BranchID *uuid.UUID
+	Status   model.InvitationStatus
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RoleRepository handles role and permission data operations
+type RoleRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository creates a new role repository
+func NewRoleRepository() *RoleRepository {
+	return &RoleRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *RoleRepository) Available() bool {
+	return r.db != nil
+}
+
+// Create creates a new role together with its permissions
+func (r *RoleRepository) Create(ctx context.Context, role *model.Role) error {
+	return r.db.WithContext(ctx).Create(role).Error
+}
+
+// GetByID retrieves a role by ID
+func (r *RoleRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Role, error) {
+	var role model.Role
+	err := r.db.WithContext(ctx).
+		Preload("Permissions").
+		First(&role, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// GetByName retrieves a role by name
+func (r *RoleRepository) GetByName(ctx context.Context, name model.UserRole) (*model.Role, error) {
+	var role model.Role
+	err := r.db.WithContext(ctx).
+		Preload("Permissions").
+		First(&role, "name = ?", name).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// List retrieves all roles with their permissions
+func (r *RoleRepository) List(ctx context.Context) ([]*model.Role, error) {
+	var roles []*model.Role
+	err := r.db.WithContext(ctx).
+		Preload("Permissions").
+		Order("name ASC").
+		Find(&roles).Error
+	return roles, err
+}
+
+// Update updates role details
+func (r *RoleRepository) Update(ctx context.Context, role *model.Role) error {
+	return r.db.WithContext(ctx).Omit("Permissions").Save(role).Error
+}
+
+// ReplacePermissions replaces all permissions granted to a role
+func (r *RoleRepository) ReplacePermissions(ctx context.Context, roleID uuid.UUID, permissions []model.Permission) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", roleID).Delete(&model.RolePermission{}).Error; err != nil {
+			return err
+		}
+		if len(permissions) == 0 {
+			return nil
+		}
+		rows := make([]model.RolePermission, 0, len(permissions))
+		for _, p := range permissions {
+			rows = append(rows, model.RolePermission{RoleID: roleID, Permission: p})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// Delete soft deletes a role and removes its permissions
+func (r *RoleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", id).Delete(&model.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Role{}, "id = ?", id).Error
+	})
+}
+
+// CountUsersWithRole counts users currently assigned to a role
+func (r *RoleRepository) CountUsersWithRole(ctx context.Context, name model.UserRole) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.User{}).Where("role = ?", name).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// signingKeyLockID is the Postgres advisory lock taken while rotating keys,
+// so that only one instance rotates at a time
+const signingKeyLockID = 7301
+
+// SigningKeyRepository handles JWT signing key data operations
+type SigningKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewSigningKeyRepository creates a new signing key repository
+func NewSigningKeyRepository() *SigningKeyRepository {
+	return &SigningKeyRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *SigningKeyRepository) Available() bool {
+	return r.db != nil
+}
+
+// ListPublished retrieves every key that has not yet expired, newest first
+func (r *SigningKeyRepository) ListPublished(ctx context.Context) ([]*model.SigningKey, error) {
+	var keys []*model.SigningKey
+	err := r.db.WithContext(ctx).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Order("created_at DESC").
+		Find(&keys).Error
+	return keys, err
+}
+
+// WithRotationLock runs fn in a transaction holding the key rotation lock
+func (r *SigningKeyRepository) WithRotationLock(ctx context.Context, fn func(tx *SigningKeyRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", signingKeyLockID).Error; err != nil {
+			return err
+		}
+		return fn(&SigningKeyRepository{db: tx})
+	})
+}
+
+// Create creates a new signing key
+func (r *SigningKeyRepository) Create(ctx context.Context, key *model.SigningKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// Update updates a signing key
+func (r *SigningKeyRepository) Update(ctx context.Context, key *model.SigningKey) error {
+	return r.db.WithContext(ctx).Save(key).Error
+}
+
+// DeleteExpired removes retired keys that no longer verify any valid token
+func (r *SigningKeyRepository) DeleteExpired(ctx context.Context) error {
+	return r.db.WithContext(ctx).
+		Where("status = ? AND expires_at <= ?", model.SigningKeyRetired, time.Now()).
+		Delete(&model.SigningKey{}).Error
+}
@@ -110,6 +110,43 @@ func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
 	return r.db.WithContext(ctx).Save(user).Error
 }
 
+// MarkContactVerified marks the user's email or phone as verified, provided it still
+// matches the target the verification was sent to. It returns false otherwise.
+func (r *UserRepository) MarkContactVerified(ctx context.Context, userID uuid.UUID, channel model.VerificationChannel, target string) (bool, error) {
+	column, verifiedColumn := "email", "email_verified_at"
+	if channel == model.VerificationChannelPhone {
+		column, verifiedColumn = "phone", "phone_verified_at"
+	}
+	now := time.Now()
+
+	if r.inMemory {
+		sharedUsersMu.Lock()
+		defer sharedUsersMu.Unlock()
+		u, ok := sharedUsers[userID]
+		if !ok {
+			return false, nil
+		}
+		if channel == model.VerificationChannelPhone {
+			if u.Phone != target {
+				return false, nil
+			}
+			u.PhoneVerifiedAt = &now
+		} else {
+			if u.Email != target {
+				return false, nil
+			}
+			u.EmailVerifiedAt = &now
+		}
+		return true, nil
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ? AND "+column+" = ?", userID, target).
+		Update(verifiedColumn, now)
+	return result.RowsAffected > 0, result.Error
+}
+
 // Delete soft deletes a user
 func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	if r.inMemory {
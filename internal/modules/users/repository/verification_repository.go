@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// VerificationRepository handles contact verification data operations
+type VerificationRepository struct {
+	db *gorm.DB
+}
+
+// NewVerificationRepository creates a new verification repository
+func NewVerificationRepository() *VerificationRepository {
+	return &VerificationRepository{
+		db: database.DB,
+	}
+}
+
+// Available reports whether the repository is backed by a database
+func (r *VerificationRepository) Available() bool {
+	return r.db != nil
+}
+
+// Get retrieves the outstanding verification of a user for a channel
+func (r *VerificationRepository) Get(ctx context.Context, userID uuid.UUID, channel model.VerificationChannel) (*model.ContactVerification, error) {
+	var verification model.ContactVerification
+	err := r.db.WithContext(ctx).
+		First(&verification, "user_id = ? AND channel = ?", userID, channel).Error
+	if err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+// Upsert stores the verification, replacing any earlier one for the same user and channel
+func (r *VerificationRepository) Upsert(ctx context.Context, verification *model.ContactVerification) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "channel"}},
+			DoUpdates: clause.AssignmentColumns([]string{"target", "secret_hash", "expires_at", "attempts", "send_count", "last_sent_at", "updated_at"}),
+		}).
+		Create(verification).Error
+}
+
+// IncrementAttempts records a failed verification attempt
+func (r *VerificationRepository) IncrementAttempts(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&model.ContactVerification{}).
+		Where("id = ?", id).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+// Delete removes the verification of a user for a channel once it is completed
+func (r *VerificationRepository) Delete(ctx context.Context, userID uuid.UUID, channel model.VerificationChannel) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND channel = ?", userID, channel).
+		Delete(&model.ContactVerification{}).Error
+}
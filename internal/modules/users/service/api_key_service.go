@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	userRepository "service/internal/modules/users/repository"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// apiKeyTokenPrefix marks secrets issued by this service so they are easy to recognise in logs and scanners
+const apiKeyTokenPrefix = "sk_"
+
+// lastUsedResolution limits how often last-used tracking writes to the database for a busy key
+const lastUsedResolution = time.Minute
+
+// APIKeyService handles API key business logic
+type APIKeyService struct {
+	apiKeyRepo  *userRepository.APIKeyRepository
+	userRepo    *userRepository.UserRepository
+	roleService *RoleService
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService() *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo:  userRepository.NewAPIKeyRepository(),
+		userRepo:    userRepository.NewUserRepository(),
+		roleService: NewRoleService(),
+	}
+}
+
+// CreateAPIKey issues a new API key acting on behalf of the given user.
+// The plain text key is only returned from this call.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, createdBy uuid.UUID, req *model.APIKeyRequest) (*model.APIKeyCreatedResponse, error) {
+	if !s.apiKeyRepo.Available() {
+		return nil, errors.New("api key storage is not available")
+	}
+
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, model.ErrInvalidInput
+	}
+	owner, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	scopes, err := s.validateScopes(ctx, owner, req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expiry must be in the future")
+	}
+
+	secret, err := utils.RandomHex(24)
+	if err != nil {
+		return nil, err
+	}
+	plain := apiKeyTokenPrefix + secret
+
+	rateLimit := req.RateLimit
+	if rateLimit == 0 {
+		rateLimit = model.DefaultAPIKeyRateLimit
+	}
+
+	key := &model.APIKey{
+		Name:      req.Name,
+		Prefix:    plain[:model.APIKeyPrefixLength],
+		KeyHash:   utils.SHA256Hex(plain),
+		UserID:    owner.ID,
+		RateLimit: rateLimit,
+		ExpiresAt: req.ExpiresAt,
+		CreatedBy: createdBy,
+	}
+	key.SetScopes(scopes)
+
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &model.APIKeyCreatedResponse{
+		APIKeyResponse: key.ToResponse(),
+		Key:            plain,
+	}, nil
+}
+
+// GetAPIKey retrieves an API key by ID
+func (s *APIKeyService) GetAPIKey(ctx context.Context, id uuid.UUID) (*model.APIKeyResponse, error) {
+	if !s.apiKeyRepo.Available() {
+		return nil, model.ErrAPIKeyNotFound
+	}
+
+	key, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, model.ErrAPIKeyNotFound
+	}
+
+	response := key.ToResponse()
+	return &response, nil
+}
+
+// ListAPIKeys retrieves API keys with pagination
+func (s *APIKeyService) ListAPIKeys(ctx context.Context, page, limit int, filters *userRepository.APIKeyFilters) ([]model.APIKeyResponse, int64, error) {
+	if !s.apiKeyRepo.Available() {
+		return []model.APIKeyResponse{}, 0, nil
+	}
+
+	offset := (page - 1) * limit
+	keys, total, err := s.apiKeyRepo.List(ctx, offset, limit, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]model.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		responses = append(responses, key.ToResponse())
+	}
+	return responses, total, nil
+}
+
+// UpdateAPIKey updates the name, scopes, rate limit or expiry of an API key
+func (s *APIKeyService) UpdateAPIKey(ctx context.Context, id uuid.UUID, req *model.UpdateAPIKeyRequest) (*model.APIKeyResponse, error) {
+	if !s.apiKeyRepo.Available() {
+		return nil, model.ErrAPIKeyNotFound
+	}
+
+	key, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, model.ErrAPIKeyNotFound
+	}
+	if key.RevokedAt != nil {
+		return nil, errors.New("revoked api keys cannot be updated")
+	}
+
+	if req.Name != "" {
+		key.Name = req.Name
+	}
+	if len(req.Scopes) > 0 {
+		owner, err := s.userRepo.GetByID(ctx, key.UserID)
+		if err != nil {
+			return nil, model.ErrUserNotFound
+		}
+		scopes, err := s.validateScopes(ctx, owner, req.Scopes)
+		if err != nil {
+			return nil, err
+		}
+		key.SetScopes(scopes)
+	}
+	if req.RateLimit > 0 {
+		key.RateLimit = req.RateLimit
+	}
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			return nil, errors.New("expiry must be in the future")
+		}
+		key.ExpiresAt = req.ExpiresAt
+	}
+
+	if err := s.apiKeyRepo.Update(ctx, key); err != nil {
+		return nil, err
+	}
+
+	response := key.ToResponse()
+	return &response, nil
+}
+
+// RevokeAPIKey revokes an API key so it can no longer authenticate
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
+	if !s.apiKeyRepo.Available() {
+		return model.ErrAPIKeyNotFound
+	}
+	return s.apiKeyRepo.Revoke(ctx, id)
+}
+
+// Authenticate resolves a plain text API key to an active key and its owning user
+func (s *APIKeyService) Authenticate(ctx context.Context, plain, clientIP string) (*model.APIKey, error) {
+	if !s.apiKeyRepo.Available() || plain == "" {
+		return nil, model.ErrInvalidAPIKey
+	}
+
+	key, err := s.apiKeyRepo.GetByHash(ctx, utils.SHA256Hex(plain))
+	if err != nil {
+		return nil, model.ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if !key.IsActive(now) || !key.User.IsActive {
+		return nil, model.ErrInvalidAPIKey
+	}
+
+	// Last-used tracking is coarse so busy keys do not write on every request
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution || key.LastUsedIP != clientIP {
+		if err := s.apiKeyRepo.TouchLastUsed(ctx, key.ID, now, clientIP); err == nil {
+			key.LastUsedAt = &now
+			key.LastUsedIP = clientIP
+		}
+	}
+
+	return key, nil
+}
+
+// validateScopes checks that the requested scopes are known and granted to the owner's role,
+// so a key can never do more than the user it acts for
+func (s *APIKeyService) validateScopes(ctx context.Context, owner *model.User, values []string) ([]model.Permission, error) {
+	scopes, err := parsePermissions(values)
+	if err != nil {
+		return nil, err
+	}
+	if len(scopes) == 0 {
+		return nil, model.ErrInvalidPermission
+	}
+	if !s.roleService.HasPermissions(ctx, owner.Role, scopes...) {
+		return nil, errors.New("scopes exceed the permissions of the key owner")
+	}
+	return scopes, nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	userRepository "service/internal/modules/users/repository"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DeviceService manages the devices a user receives push notifications on
+type DeviceService struct {
+	deviceRepo *userRepository.DeviceRepository
+}
+
+// NewDeviceService creates a new device service
+func NewDeviceService() *DeviceService {
+	return &DeviceService{
+		deviceRepo: userRepository.NewDeviceRepository(),
+	}
+}
+
+// Register adds a device for the user or refreshes it when the token is already known.
+// The device is bound to the login session so that logging out stops pushes to it.
+func (s *DeviceService) Register(ctx context.Context, userID uuid.UUID, sessionID string, req *model.RegisterDeviceRequest) (*model.UserDeviceResponse, error) {
+	if !s.deviceRepo.Available() {
+		return nil, errors.New("device storage is not available")
+	}
+
+	device := &model.UserDevice{
+		UserID:     userID,
+		Token:      req.Token,
+		Platform:   req.Platform,
+		AppVersion: req.AppVersion,
+		DeviceName: req.DeviceName,
+		SessionID:  sessionID,
+		LastSeenAt: time.Now(),
+	}
+	if err := s.deviceRepo.Upsert(ctx, device); err != nil {
+		return nil, err
+	}
+
+	response := device.ToResponse(sessionID)
+	return &response, nil
+}
+
+// List retrieves the devices registered by a user
+func (s *DeviceService) List(ctx context.Context, userID uuid.UUID, sessionID string) ([]model.UserDeviceResponse, error) {
+	responses := []model.UserDeviceResponse{}
+	if !s.deviceRepo.Available() {
+		return responses, nil
+	}
+
+	devices, err := s.deviceRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range devices {
+		responses = append(responses, d.ToResponse(sessionID))
+	}
+	return responses, nil
+}
+
+// Remove unregisters a device of a user
+func (s *DeviceService) Remove(ctx context.Context, userID, id uuid.UUID) error {
+	if !s.deviceRepo.Available() {
+		return model.ErrDeviceNotFound
+	}
+
+	if err := s.deviceRepo.Delete(ctx, userID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.ErrDeviceNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// RemoveSession unregisters the devices of a login session when it is logged out
+func (s *DeviceService) RemoveSession(ctx context.Context, userID uuid.UUID, sessionID string) error {
+	if sessionID == "" || !s.deviceRepo.Available() {
+		return nil
+	}
+	return s.deviceRepo.DeleteBySession(ctx, userID, sessionID)
+}
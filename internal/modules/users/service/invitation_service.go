@@ -0,0 +1,420 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	notificationService "service/internal/modules/notification/service"
+	userRepository "service/internal/modules/users/repository"
+	"service/internal/shared/config"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// invitationResendInterval throttles how often an invitation can be re-sent
+const invitationResendInterval = time.Minute
+
+// mfaIssuer is shown as the account issuer in authenticator apps
+const mfaIssuer = "iPhone Service"
+
+// InvitationService handles the staff invitation and onboarding flow
+type InvitationService struct {
+	invitationRepo  *userRepository.InvitationRepository
+	userRepo        *userRepository.UserRepository
+	roleRepo        *userRepository.RoleRepository
+	roleService     *RoleService
+	emailService    *notificationService.EmailService
+	whatsAppService *notificationService.WhatsAppService
+}
+
+// NewInvitationService creates a new invitation service
+func NewInvitationService() *InvitationService {
+	return &InvitationService{
+		invitationRepo:  userRepository.NewInvitationRepository(),
+		userRepo:        userRepository.NewUserRepository(),
+		roleRepo:        userRepository.NewRoleRepository(),
+		roleService:     NewRoleService(),
+		emailService:    notificationService.NewEmailService(),
+		whatsAppService: notificationService.NewWhatsAppService(),
+	}
+}
+
+// CreateInvitation invites a staff member and sends them a single-use invite link
+func (s *InvitationService) CreateInvitation(ctx context.Context, inviterID uuid.UUID, inviterRole model.UserRole, req *model.InvitationRequest) (*model.InvitationResponse, error) {
+	if !s.invitationRepo.Available() {
+		return nil, errors.New("invitation storage is not available")
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	phone := strings.TrimSpace(req.Phone)
+	if email == "" && phone == "" {
+		return nil, errors.New("email or phone is required")
+	}
+
+	role := model.UserRole(strings.ToLower(strings.TrimSpace(string(req.Role))))
+	if err := s.validateRole(ctx, inviterRole, role); err != nil {
+		return nil, err
+	}
+
+	var branchID *uuid.UUID
+	if req.BranchID != nil && *req.BranchID != "" {
+		id, err := uuid.Parse(*req.BranchID)
+		if err != nil {
+			return nil, errors.New("invalid branch ID")
+		}
+		branchID = &id
+	}
+	if branchID == nil && role != model.RoleAdminPusat {
+		return nil, errors.New("branch is required for this role")
+	}
+	if branchID != nil && !model.BranchScopeFromContext(ctx).Allows(*branchID) {
+		return nil, model.ErrForbidden
+	}
+
+	// The contact must not already belong to an account or an open invitation
+	if email != "" {
+		if exists, err := s.userRepo.CheckEmailExists(ctx, email, nil); err != nil {
+			return nil, err
+		} else if exists {
+			return nil, model.ErrEmailExists
+		}
+	}
+	if phone != "" {
+		if exists, err := s.userRepo.CheckPhoneExists(ctx, phone, nil); err != nil {
+			return nil, err
+		} else if exists {
+			return nil, model.ErrPhoneExists
+		}
+	}
+	if pending, err := s.invitationRepo.HasPendingForContact(ctx, email, phone); err != nil {
+		return nil, err
+	} else if pending {
+		return nil, errors.New("a pending invitation already exists for this contact")
+	}
+
+	// Admin accounts always enrol a second factor
+	requireMFA := req.RequireMFA || role == model.RoleAdminPusat || role == model.RoleAdminCabang
+
+	placeholder, err := utils.RandomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := &model.StaffInvitation{
+		Email:      email,
+		Phone:      phone,
+		FullName:   req.FullName,
+		Role:       role,
+		BranchID:   branchID,
+		Status:     model.InvitationStatusPending,
+		RequireMFA: requireMFA,
+		TokenHash:  utils.SHA256Hex(placeholder),
+		ExpiresAt:  time.Now().Add(invitationExpiry()),
+		InvitedBy:  inviterID,
+	}
+
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	if err := s.send(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	response := invitation.ToResponse()
+	return &response, nil
+}
+
+// ListInvitations retrieves invitations with pagination
+func (s *InvitationService) ListInvitations(ctx context.Context, page, limit int, filters *userRepository.InvitationFilters) ([]model.InvitationResponse, int64, error) {
+	if !s.invitationRepo.Available() {
+		return []model.InvitationResponse{}, 0, nil
+	}
+
+	offset := (page - 1) * limit
+	invitations, total, err := s.invitationRepo.List(ctx, offset, limit, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]model.InvitationResponse, 0, len(invitations))
+	for _, invitation := range invitations {
+		responses = append(responses, invitation.ToResponse())
+	}
+	return responses, total, nil
+}
+
+// ResendInvitation issues a new invite link, invalidating the previous one.
+// Expired invitations get a fresh expiry so they can be re-sent instead of recreated.
+func (s *InvitationService) ResendInvitation(ctx context.Context, id uuid.UUID) (*model.InvitationResponse, error) {
+	invitation, err := s.getInvitation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if invitation.Status != model.InvitationStatusPending {
+		return nil, fmt.Errorf("invitation is already %s", invitation.Status)
+	}
+	if invitation.LastSentAt != nil && time.Since(*invitation.LastSentAt) < invitationResendInterval {
+		return nil, errors.New("invitation was sent recently, please try again later")
+	}
+
+	if !invitation.IsPending(time.Now()) {
+		invitation.ExpiresAt = time.Now().Add(invitationExpiry())
+	}
+	invitation.MFASecret = ""
+
+	if err := s.send(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	response := invitation.ToResponse()
+	return &response, nil
+}
+
+// RevokeInvitation revokes a pending invitation so its link can no longer be used
+func (s *InvitationService) RevokeInvitation(ctx context.Context, id uuid.UUID) error {
+	invitation, err := s.getInvitation(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if invitation.Status != model.InvitationStatusPending {
+		return fmt.Errorf("invitation is already %s", invitation.Status)
+	}
+
+	now := time.Now()
+	invitation.Status = model.InvitationStatusRevoked
+	invitation.RevokedAt = &now
+	invitation.MFASecret = ""
+	return s.invitationRepo.Update(ctx, invitation)
+}
+
+// GetInvitationDetails returns what the invitee needs to complete onboarding.
+// When MFA is required an authenticator secret is provisioned for the invitee to enrol.
+func (s *InvitationService) GetInvitationDetails(ctx context.Context, token string) (*model.InvitationDetailsResponse, error) {
+	invitation, err := s.resolveToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &model.InvitationDetailsResponse{
+		Email:      invitation.Email,
+		Phone:      invitation.Phone,
+		FullName:   invitation.FullName,
+		Role:       invitation.Role,
+		BranchID:   invitation.BranchID,
+		ExpiresAt:  invitation.ExpiresAt,
+		RequireMFA: invitation.RequireMFA,
+	}
+
+	if invitation.RequireMFA {
+		if invitation.MFASecret == "" {
+			secret, err := utils.GenerateTOTPSecret()
+			if err != nil {
+				return nil, err
+			}
+			invitation.MFASecret = secret
+			if err := s.invitationRepo.Update(ctx, invitation); err != nil {
+				return nil, err
+			}
+		}
+
+		account := invitation.Email
+		if account == "" {
+			account = invitation.Phone
+		}
+		details.MFASecret = invitation.MFASecret
+		details.MFAProvisioning = utils.TOTPProvisioningURI(invitation.MFASecret, account, mfaIssuer)
+	}
+
+	return details, nil
+}
+
+// AcceptInvitation creates the staff account with the invitee's own password
+func (s *InvitationService) AcceptInvitation(ctx context.Context, req *model.AcceptInvitationRequest) (*model.UserResponse, error) {
+	invitation, err := s.resolveToken(ctx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	email := invitation.Email
+	if email == "" {
+		email = strings.ToLower(strings.TrimSpace(req.Email))
+	}
+	phone := invitation.Phone
+	if phone == "" {
+		phone = strings.TrimSpace(req.Phone)
+	}
+	if email == "" || phone == "" {
+		return nil, errors.New("email and phone are required")
+	}
+
+	fullName := req.FullName
+	if fullName == "" {
+		fullName = invitation.FullName
+	}
+	if fullName == "" {
+		return nil, errors.New("full name is required")
+	}
+
+	if exists, err := s.userRepo.CheckEmailExists(ctx, email, nil); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, model.ErrEmailExists
+	}
+	if exists, err := s.userRepo.CheckPhoneExists(ctx, phone, nil); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, model.ErrPhoneExists
+	}
+
+	// The invitee proves enrolment by entering a code from the provisioned secret
+	if invitation.RequireMFA {
+		if invitation.MFASecret == "" || req.MFACode == "" {
+			return nil, model.ErrMFARequired
+		}
+		if !utils.ValidateTOTP(invitation.MFASecret, req.MFACode) {
+			return nil, model.ErrInvalidMFACode
+		}
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &model.User{
+		Email:      email,
+		Password:   string(hashedPassword),
+		FullName:   fullName,
+		Phone:      phone,
+		Role:       invitation.Role,
+		BranchID:   invitation.BranchID,
+		IsActive:   true,
+		MFAEnabled: invitation.RequireMFA,
+		MFASecret:  invitation.MFASecret,
+	}
+
+	// The invite link proves ownership of the contact it was delivered to
+	now := time.Now()
+	if invitation.Email != "" && invitation.Email == email {
+		user.EmailVerifiedAt = &now
+	} else if invitation.Email == "" && invitation.Phone == phone {
+		user.PhoneVerifiedAt = &now
+	}
+
+	if err := s.invitationRepo.Accept(ctx, invitation, user); err != nil {
+		return nil, err
+	}
+
+	response := user.ToResponse()
+	return &response, nil
+}
+
+// send generates a new single-use link for the invitation and delivers it by email or WhatsApp
+func (s *InvitationService) send(ctx context.Context, invitation *model.StaffInvitation) error {
+	nonce, err := utils.RandomHex(16)
+	if err != nil {
+		return err
+	}
+
+	token, err := utils.GenerateInvitationToken(invitation.ID, nonce, invitation.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	invitation.TokenHash = utils.SHA256Hex(nonce)
+	invitation.SendCount++
+	invitation.LastSentAt = &now
+	if err := s.invitationRepo.Update(ctx, invitation); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/invitations/accept?token=%s", config.Config.BaseURL, token)
+	message := fmt.Sprintf(
+		"You have been invited to join iPhone Service as %s.\n\nSet up your account here: %s\n\nThis link can only be used once and expires on %s.",
+		invitation.Role, link, invitation.ExpiresAt.Format("02 Jan 2006 15:04"),
+	)
+
+	if invitation.Email != "" {
+		return s.emailService.Send(invitation.Email, "You're invited to iPhone Service", message)
+	}
+	return s.whatsAppService.Send(ctx, invitation.Phone, message)
+}
+
+// resolveToken validates an invite link and returns the pending invitation it belongs to
+func (s *InvitationService) resolveToken(ctx context.Context, token string) (*model.StaffInvitation, error) {
+	if !s.invitationRepo.Available() {
+		return nil, model.ErrInvitationInvalid
+	}
+
+	claims, err := utils.ValidateInvitationToken(token)
+	if err != nil {
+		return nil, model.ErrInvitationInvalid
+	}
+
+	invitation, err := s.invitationRepo.GetByID(ctx, claims.InvitationID)
+	if err != nil {
+		return nil, model.ErrInvitationInvalid
+	}
+
+	// Only the most recently sent link is valid, and only while the invitation is pending
+	if !invitation.IsPending(time.Now()) || invitation.TokenHash != utils.SHA256Hex(claims.ID) {
+		return nil, model.ErrInvitationInvalid
+	}
+
+	return invitation, nil
+}
+
+// getInvitation loads an invitation for management operations
+func (s *InvitationService) getInvitation(ctx context.Context, id uuid.UUID) (*model.StaffInvitation, error) {
+	if !s.invitationRepo.Available() {
+		return nil, model.ErrInvitationNotFound
+	}
+
+	invitation, err := s.invitationRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// validateRole checks that the role exists, is a staff role, and grants nothing the inviter lacks
+func (s *InvitationService) validateRole(ctx context.Context, inviterRole, role model.UserRole) error {
+	if role == model.RolePelanggan {
+		return errors.New("customers register themselves and cannot be invited")
+	}
+
+	if _, builtIn := model.DefaultRolePermissions[role]; !builtIn {
+		if !s.roleRepo.Available() {
+			return model.ErrRoleNotFound
+		}
+		if _, err := s.roleRepo.GetByName(ctx, role); err != nil {
+			return model.ErrRoleNotFound
+		}
+	}
+
+	if !s.roleService.HasPermissions(ctx, inviterRole, s.roleService.GetPermissions(ctx, role)...) {
+		return model.ErrForbidden
+	}
+	return nil
+}
+
+// invitationExpiry returns how long invite links stay valid
+func invitationExpiry() time.Duration {
+	if config.Config != nil && config.Config.InvitationExpiry > 0 {
+		return config.Config.InvitationExpiry
+	}
+	return 72 * time.Hour
+}
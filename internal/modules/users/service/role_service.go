@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"errors"
+	userRepository "service/internal/modules/users/repository"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// permissionCacheTTL controls how long resolved role permissions are kept in memory
+const permissionCacheTTL = time.Minute
+
+// permissionCacheVersionKey is bumped in Redis whenever roles change, so every instance
+// drops its cached permissions and not only the one that made the change
+const permissionCacheVersionKey = "role_permissions:version"
+
+type cachedPermissions struct {
+	permissions map[model.Permission]bool
+	expiresAt   time.Time
+	version     int64
+}
+
+var (
+	permissionCache   = make(map[model.UserRole]cachedPermissions)
+	permissionCacheMu sync.RWMutex
+)
+
+// RoleService handles role and permission business logic
+type RoleService struct {
+	roleRepo *userRepository.RoleRepository
+	userRepo *userRepository.UserRepository
+	redis    *redis.Client
+}
+
+// NewRoleService creates a new role service
+func NewRoleService() *RoleService {
+	return &RoleService{
+		roleRepo: userRepository.NewRoleRepository(),
+		userRepo: userRepository.NewUserRepository(),
+		redis:    database.Redis,
+	}
+}
+
+// HasPermissions reports whether the role has been granted all of the given permissions
+func (s *RoleService) HasPermissions(ctx context.Context, role model.UserRole, permissions ...model.Permission) bool {
+	granted := s.resolvePermissions(ctx, role)
+	for _, p := range permissions {
+		if !granted[p] {
+			return false
+		}
+	}
+	return true
+}
+
+// GetPermissions returns the permissions granted to a role
+func (s *RoleService) GetPermissions(ctx context.Context, role model.UserRole) []model.Permission {
+	granted := s.resolvePermissions(ctx, role)
+	permissions := make([]model.Permission, 0, len(granted))
+	for _, p := range model.AllPermissions {
+		if granted[p] {
+			permissions = append(permissions, p)
+		}
+	}
+	return permissions
+}
+
+// resolvePermissions loads the permission set for a role, using the in-memory cache when possible
+func (s *RoleService) resolvePermissions(ctx context.Context, role model.UserRole) map[model.Permission]bool {
+	version, known := s.cacheVersion(ctx)
+	permissionCacheMu.RLock()
+	cached, ok := permissionCache[role]
+	permissionCacheMu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) && (!known || cached.version == version) {
+		return cached.permissions
+	}
+
+	granted := make(map[model.Permission]bool)
+	loaded := false
+	if s.roleRepo.Available() {
+		if r, err := s.roleRepo.GetByName(ctx, role); err == nil {
+			for _, rp := range r.Permissions {
+				granted[rp.Permission] = true
+			}
+			loaded = true
+		}
+	}
+
+	// Fall back to the built-in mapping when the role is not stored in the database
+	if !loaded {
+		for _, p := range model.DefaultRolePermissions[role] {
+			granted[p] = true
+		}
+	}
+
+	permissionCacheMu.Lock()
+	permissionCache[role] = cachedPermissions{
+		permissions: granted,
+		expiresAt:   time.Now().Add(permissionCacheTTL),
+		version:     version,
+	}
+	permissionCacheMu.Unlock()
+
+	return granted
+}
+
+// cacheVersion returns the shared version of the cached permissions. Without Redis, or
+// when it cannot be read, the version is unknown and cached permissions expire by TTL only.
+func (s *RoleService) cacheVersion(ctx context.Context) (int64, bool) {
+	if s.redis == nil {
+		return 0, false
+	}
+	version, err := s.redis.Get(ctx, permissionCacheVersionKey).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, true
+	}
+	return version, err == nil
+}
+
+// invalidateCache drops cached permissions so changes take effect immediately, on every
+// instance when Redis is available
+func (s *RoleService) invalidateCache(ctx context.Context) {
+	permissionCacheMu.Lock()
+	permissionCache = make(map[model.UserRole]cachedPermissions)
+	permissionCacheMu.Unlock()
+
+	if s.redis != nil {
+		_ = s.redis.Incr(ctx, permissionCacheVersionKey).Err()
+	}
+}
+
+// ListPermissions returns all permissions known to the system
+func (s *RoleService) ListPermissions() []model.Permission {
+	return model.AllPermissions
+}
+
+// ListRoles retrieves all roles with their permissions
+func (s *RoleService) ListRoles(ctx context.Context) ([]model.RoleResponse, error) {
+	if !s.roleRepo.Available() {
+		// Without a database only the built-in roles exist
+		var responses []model.RoleResponse
+		for _, role := range []model.UserRole{
+			model.RoleAdminPusat,
+			model.RoleAdminCabang,
+			model.RoleKasir,
+			model.RoleTeknisi,
+			model.RoleKurir,
+			model.RolePelanggan,
+		} {
+			responses = append(responses, model.RoleResponse{
+				Name:        role,
+				IsSystem:    true,
+				Permissions: model.DefaultRolePermissions[role],
+			})
+		}
+		return responses, nil
+	}
+
+	roles, err := s.roleRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []model.RoleResponse
+	for _, role := range roles {
+		responses = append(responses, role.ToResponse())
+	}
+	return responses, nil
+}
+
+// GetRole retrieves a role by ID
+func (s *RoleService) GetRole(ctx context.Context, id uuid.UUID) (*model.RoleResponse, error) {
+	if !s.roleRepo.Available() {
+		return nil, model.ErrRoleNotFound
+	}
+
+	role, err := s.roleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, model.ErrRoleNotFound
+	}
+
+	response := role.ToResponse()
+	return &response, nil
+}
+
+// CreateRole creates a new custom role
+func (s *RoleService) CreateRole(ctx context.Context, req *model.RoleRequest) (*model.RoleResponse, error) {
+	if !s.roleRepo.Available() {
+		return nil, errors.New("role storage is not available")
+	}
+
+	permissions, err := parsePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	name := model.UserRole(strings.ToLower(strings.TrimSpace(req.Name)))
+	if _, builtIn := model.DefaultRolePermissions[name]; builtIn {
+		return nil, model.ErrRoleExists
+	}
+	if _, err := s.roleRepo.GetByName(ctx, name); err == nil {
+		return nil, model.ErrRoleExists
+	}
+
+	role := &model.Role{
+		Name:        name,
+		Description: req.Description,
+		IsSystem:    false,
+	}
+	for _, p := range permissions {
+		role.Permissions = append(role.Permissions, model.RolePermission{Permission: p})
+	}
+
+	if err := s.roleRepo.Create(ctx, role); err != nil {
+		return nil, err
+	}
+	s.invalidateCache(ctx)
+
+	response := role.ToResponse()
+	return &response, nil
+}
+
+// UpdateRole updates a role's description and permissions
+func (s *RoleService) UpdateRole(ctx context.Context, id uuid.UUID, req *model.RoleRequest) (*model.RoleResponse, error) {
+	if !s.roleRepo.Available() {
+		return nil, errors.New("role storage is not available")
+	}
+
+	role, err := s.roleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, model.ErrRoleNotFound
+	}
+
+	permissions, err := parsePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Role names are referenced by users, so only custom roles may be renamed
+	name := model.UserRole(strings.ToLower(strings.TrimSpace(req.Name)))
+	if name != role.Name {
+		if role.IsSystem {
+			return nil, errors.New("system roles cannot be renamed")
+		}
+		if count, err := s.roleRepo.CountUsersWithRole(ctx, role.Name); err != nil {
+			return nil, err
+		} else if count > 0 {
+			return nil, errors.New("role is assigned to users and cannot be renamed")
+		}
+		if _, err := s.roleRepo.GetByName(ctx, name); err == nil {
+			return nil, model.ErrRoleExists
+		}
+		role.Name = name
+	}
+	role.Description = req.Description
+
+	if err := s.roleRepo.Update(ctx, role); err != nil {
+		return nil, err
+	}
+	if err := s.roleRepo.ReplacePermissions(ctx, role.ID, permissions); err != nil {
+		return nil, err
+	}
+	s.invalidateCache(ctx)
+
+	updated, err := s.roleRepo.GetByID(ctx, role.ID)
+	if err != nil {
+		return nil, err
+	}
+	response := updated.ToResponse()
+	return &response, nil
+}
+
+// DeleteRole deletes a custom role
+func (s *RoleService) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	if !s.roleRepo.Available() {
+		return errors.New("role storage is not available")
+	}
+
+	role, err := s.roleRepo.GetByID(ctx, id)
+	if err != nil {
+		return model.ErrRoleNotFound
+	}
+	if role.IsSystem {
+		return model.ErrSystemRole
+	}
+
+	count, err := s.roleRepo.CountUsersWithRole(ctx, role.Name)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("role is assigned to users and cannot be deleted")
+	}
+
+	if err := s.roleRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidateCache(ctx)
+	return nil
+}
+
+// AssignRole assigns a built-in or custom role to a user
+func (s *RoleService) AssignRole(ctx context.Context, userID uuid.UUID, roleName string) (*model.UserResponse, error) {
+	name := model.UserRole(strings.ToLower(strings.TrimSpace(roleName)))
+	if _, builtIn := model.DefaultRolePermissions[name]; !builtIn {
+		if !s.roleRepo.Available() {
+			return nil, model.ErrRoleNotFound
+		}
+		if _, err := s.roleRepo.GetByName(ctx, name); err != nil {
+			return nil, model.ErrRoleNotFound
+		}
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	user.Role = name
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	response := user.ToResponse()
+	return &response, nil
+}
+
+// parsePermissions validates and de-duplicates the requested permissions
+func parsePermissions(values []string) ([]model.Permission, error) {
+	seen := make(map[model.Permission]bool)
+	var permissions []model.Permission
+	for _, v := range values {
+		p := model.Permission(strings.TrimSpace(v))
+		if !model.IsValidPermission(p) {
+			return nil, model.ErrInvalidPermission
+		}
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		permissions = append(permissions, p)
+	}
+	return permissions, nil
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleServiceCacheVersion(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	// Two instances stand in for two processes sharing Redis
+	changed, other := &RoleService{redis: client}, &RoleService{redis: client}
+
+	version, known := other.cacheVersion(ctx)
+	assert.True(t, known)
+	assert.Equal(t, int64(0), version)
+
+	changed.invalidateCache(ctx)
+	version, known = other.cacheVersion(ctx)
+	assert.True(t, known)
+	assert.Equal(t, int64(1), version)
+
+	// Without Redis only the TTL expires cached permissions
+	_, known = (&RoleService{}).cacheVersion(ctx)
+	assert.False(t, known)
+
+	// An unreadable version is treated as unknown
+	server.Set(permissionCacheVersionKey, "not a number")
+	_, known = other.cacheVersion(ctx)
+	assert.False(t, known)
+}
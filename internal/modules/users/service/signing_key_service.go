@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	userRepository "service/internal/modules/users/repository"
+	"service/internal/shared/config"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"time"
+)
+
+// signingKeyPublishLead is how long a new key is published in the JWKS before it
+// signs tokens, so that verifiers caching the key set pick it up in time
+const signingKeyPublishLead = time.Hour
+
+// SigningKeyService rotates the JWT signing keys and keeps the in-memory keyring in sync
+type SigningKeyService struct {
+	keyRepo *userRepository.SigningKeyRepository
+}
+
+// NewSigningKeyService creates a new signing key service
+func NewSigningKeyService() *SigningKeyService {
+	return &SigningKeyService{
+		keyRepo: userRepository.NewSigningKeyRepository(),
+	}
+}
+
+// Sync rotates keys when due and reloads the keyring from the database.
+// It is called at startup and periodically by every instance.
+func (s *SigningKeyService) Sync(ctx context.Context) error {
+	if !s.keyRepo.Available() {
+		return nil
+	}
+
+	err := s.keyRepo.WithRotationLock(ctx, func(repo *userRepository.SigningKeyRepository) error {
+		return s.rotate(ctx, repo)
+	})
+	if err != nil {
+		return err
+	}
+
+	keys, err := s.keyRepo.ListPublished(ctx)
+	if err != nil {
+		return err
+	}
+	return utils.LoadSigningKeys(keys)
+}
+
+// rotate moves keys through pending -> active -> retired.
+// A pending key is created ahead of the rotation date and promoted once it has been
+// published for signingKeyPublishLead; the previous active key keeps verifying tokens
+// until the longest token lifetime has passed.
+func (s *SigningKeyService) rotate(ctx context.Context, repo *userRepository.SigningKeyRepository) error {
+	now := time.Now()
+
+	if err := repo.DeleteExpired(ctx); err != nil {
+		return err
+	}
+
+	keys, err := repo.ListPublished(ctx)
+	if err != nil {
+		return err
+	}
+
+	var active, pending *model.SigningKey
+	for _, k := range keys {
+		switch k.Status {
+		case model.SigningKeyActive:
+			if active == nil {
+				active = k
+			}
+		case model.SigningKeyPending:
+			if pending == nil {
+				pending = k
+			}
+		}
+	}
+
+	// First start: sign with a fresh key straight away
+	if active == nil && pending == nil {
+		key, err := utils.GenerateSigningKey()
+		if err != nil {
+			return err
+		}
+		key.Status = model.SigningKeyActive
+		key.ActivatedAt = &now
+		return repo.Create(ctx, key)
+	}
+
+	rotateAt := now
+	if active != nil && active.ActivatedAt != nil {
+		rotateAt = active.ActivatedAt.Add(rotationInterval())
+	}
+
+	if pending == nil {
+		if now.Before(rotateAt.Add(-signingKeyPublishLead)) {
+			return nil
+		}
+		key, err := utils.GenerateSigningKey()
+		if err != nil {
+			return err
+		}
+		return repo.Create(ctx, key)
+	}
+
+	if now.Before(rotateAt) || now.Before(pending.CreatedAt.Add(signingKeyPublishLead)) {
+		return nil
+	}
+
+	if active != nil {
+		expiresAt := now.Add(utils.MaxTokenLifetime())
+		active.Status = model.SigningKeyRetired
+		active.RetiredAt = &now
+		active.ExpiresAt = &expiresAt
+		if err := repo.Update(ctx, active); err != nil {
+			return err
+		}
+	}
+
+	pending.Status = model.SigningKeyActive
+	pending.ActivatedAt = &now
+	return repo.Update(ctx, pending)
+}
+
+// rotationInterval returns how long a key signs tokens before it is rotated
+func rotationInterval() time.Duration {
+	if config.Config != nil && config.Config.JWTKeyRotationInterval > 0 {
+		return config.Config.JWTKeyRotationInterval
+	}
+	return 30 * 24 * time.Hour
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	notificationService "service/internal/modules/notification/service"
+	userRepository "service/internal/modules/users/repository"
+	"service/internal/shared/config"
+	"service/internal/shared/model"
+	"service/internal/shared/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// verificationResendInterval throttles how often a verification can be re-sent
+	verificationResendInterval = time.Minute
+	// verificationMaxSendsPerDay caps the number of messages sent to one contact per day
+	verificationMaxSendsPerDay = 5
+	// phoneOTPMaxAttempts is the number of wrong codes allowed before a new code is needed
+	phoneOTPMaxAttempts = 5
+	// phoneOTPLength is the number of digits in a phone verification code
+	phoneOTPLength = 6
+)
+
+// VerificationService handles email and phone verification for customers
+type VerificationService struct {
+	verificationRepo *userRepository.VerificationRepository
+	userRepo         *userRepository.UserRepository
+	emailService     *notificationService.EmailService
+	whatsAppService  *notificationService.WhatsAppService
+}
+
+// NewVerificationService creates a new verification service
+func NewVerificationService() *VerificationService {
+	return &VerificationService{
+		verificationRepo: userRepository.NewVerificationRepository(),
+		userRepo:         userRepository.NewUserRepository(),
+		emailService:     notificationService.NewEmailService(),
+		whatsAppService:  notificationService.NewWhatsAppService(),
+	}
+}
+
+// SendInitialVerifications sends the email link and phone code after registration.
+// Failures are logged only; the customer can request them again.
+func (s *VerificationService) SendInitialVerifications(ctx context.Context, userID uuid.UUID) {
+	if !s.verificationRepo.Available() {
+		return
+	}
+	if err := s.SendEmailVerification(ctx, userID); err != nil {
+		log.Printf("Failed to send email verification to user %s: %v", userID, err)
+	}
+	if err := s.SendPhoneVerification(ctx, userID); err != nil {
+		log.Printf("Failed to send phone verification to user %s: %v", userID, err)
+	}
+}
+
+// GetStatus returns the contact verification state of a user
+func (s *VerificationService) GetStatus(ctx context.Context, userID uuid.UUID) (*model.VerificationStatusResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, model.ErrUserNotFound
+	}
+
+	return &model.VerificationStatusResponse{
+		Email:           user.Email,
+		EmailVerified:   user.EmailVerifiedAt != nil,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		Phone:           user.Phone,
+		PhoneVerified:   user.PhoneVerifiedAt != nil,
+		PhoneVerifiedAt: user.PhoneVerifiedAt,
+	}, nil
+}
+
+// SendEmailVerification sends a signed verification link to the user's email address
+func (s *VerificationService) SendEmailVerification(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return model.ErrUserNotFound
+	}
+	if user.EmailVerifiedAt != nil {
+		return model.ErrAlreadyVerified
+	}
+
+	nonce, err := utils.RandomHex(16)
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(emailVerificationExpiry())
+	if err := s.storeSecret(ctx, user.ID, model.VerificationChannelEmail, user.Email, nonce, expiresAt); err != nil {
+		return err
+	}
+
+	token, err := utils.GenerateEmailVerificationToken(user.ID, user.Email, nonce, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", baseURL(), token)
+	message := fmt.Sprintf(
+		"Hi %s,\n\nPlease confirm your email address for iPhone Service: %s\n\nThis link expires on %s. If you did not create an account, you can ignore this email.",
+		user.FullName, link, expiresAt.Format("02 Jan 2006 15:04"),
+	)
+	return s.emailService.Send(user.Email, "Confirm your email address", message)
+}
+
+// SendPhoneVerification sends a one-time code to the user's phone number over WhatsApp
+func (s *VerificationService) SendPhoneVerification(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return model.ErrUserNotFound
+	}
+	if user.PhoneVerifiedAt != nil {
+		return model.ErrAlreadyVerified
+	}
+
+	code, err := utils.RandomDigits(phoneOTPLength)
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(phoneOTPExpiry())
+	if err := s.storeSecret(ctx, user.ID, model.VerificationChannelPhone, user.Phone, code, expiresAt); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf(
+		"Your iPhone Service verification code is %s. It expires in %d minutes. Do not share this code with anyone.",
+		code, int(phoneOTPExpiry().Minutes()),
+	)
+	return s.whatsAppService.Send(ctx, user.Phone, message)
+}
+
+// VerifyEmail confirms an email address from a verification link
+func (s *VerificationService) VerifyEmail(ctx context.Context, token string) error {
+	claims, err := utils.ValidateEmailVerificationToken(token)
+	if err != nil {
+		return model.ErrInvalidToken
+	}
+
+	verification, err := s.getVerification(ctx, claims.UserID, model.VerificationChannelEmail)
+	if err != nil {
+		return err
+	}
+	if verification.SecretHash != hashVerificationSecret(claims.UserID, claims.ID) ||
+		verification.Target != claims.Email || time.Now().After(verification.ExpiresAt) {
+		return model.ErrInvalidToken
+	}
+
+	return s.complete(ctx, verification)
+}
+
+// VerifyPhone confirms the user's phone number with the code sent to it
+func (s *VerificationService) VerifyPhone(ctx context.Context, userID uuid.UUID, code string) error {
+	verification, err := s.getVerification(ctx, userID, model.VerificationChannelPhone)
+	if err != nil {
+		return err
+	}
+	if verification.Attempts >= phoneOTPMaxAttempts || time.Now().After(verification.ExpiresAt) {
+		return model.ErrInvalidVerificationCode
+	}
+
+	if verification.SecretHash != hashVerificationSecret(userID, code) {
+		if err := s.verificationRepo.IncrementAttempts(ctx, verification.ID); err != nil {
+			return err
+		}
+		return model.ErrInvalidVerificationCode
+	}
+
+	return s.complete(ctx, verification)
+}
+
+// storeSecret saves a new verification secret, enforcing the resend throttle
+func (s *VerificationService) storeSecret(ctx context.Context, userID uuid.UUID, channel model.VerificationChannel, target, secret string, expiresAt time.Time) error {
+	if !s.verificationRepo.Available() {
+		return errors.New("verification storage is not available")
+	}
+
+	now := time.Now()
+	verification := &model.ContactVerification{
+		UserID:  userID,
+		Channel: channel,
+	}
+
+	existing, err := s.verificationRepo.Get(ctx, userID, channel)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil {
+		if now.Sub(existing.LastSentAt) < verificationResendInterval {
+			return model.ErrVerificationThrottled
+		}
+		// The daily cap only applies to the same contact; a changed email or phone starts over
+		if existing.Target == target && now.Sub(existing.LastSentAt) < 24*time.Hour {
+			if existing.SendCount >= verificationMaxSendsPerDay {
+				return model.ErrVerificationThrottled
+			}
+			verification.SendCount = existing.SendCount
+		}
+	}
+
+	verification.Target = target
+	verification.SecretHash = hashVerificationSecret(userID, secret)
+	verification.ExpiresAt = expiresAt
+	verification.Attempts = 0
+	verification.SendCount++
+	verification.LastSentAt = now
+	return s.verificationRepo.Upsert(ctx, verification)
+}
+
+// getVerification loads the outstanding verification of a user for a channel
+func (s *VerificationService) getVerification(ctx context.Context, userID uuid.UUID, channel model.VerificationChannel) (*model.ContactVerification, error) {
+	if !s.verificationRepo.Available() {
+		return nil, model.ErrInvalidVerificationCode
+	}
+
+	verification, err := s.verificationRepo.Get(ctx, userID, channel)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrInvalidVerificationCode
+		}
+		return nil, err
+	}
+	return verification, nil
+}
+
+// complete marks the contact verified and discards the used secret
+func (s *VerificationService) complete(ctx context.Context, verification *model.ContactVerification) error {
+	// The contact may have changed since the secret was sent
+	ok, err := s.userRepo.MarkContactVerified(ctx, verification.UserID, verification.Channel, verification.Target)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return model.ErrInvalidVerificationCode
+	}
+
+	return s.verificationRepo.Delete(ctx, verification.UserID, verification.Channel)
+}
+
+// hashVerificationSecret binds a link nonce or code to the user before hashing
+func hashVerificationSecret(userID uuid.UUID, secret string) string {
+	return utils.SHA256Hex(userID.String() + ":" + secret)
+}
+
+// emailVerificationExpiry returns how long an email verification link stays valid
+func emailVerificationExpiry() time.Duration {
+	if config.Config != nil && config.Config.EmailVerificationExpiry > 0 {
+		return config.Config.EmailVerificationExpiry
+	}
+	return 24 * time.Hour
+}
+
+// phoneOTPExpiry returns how long a phone verification code stays valid
+func phoneOTPExpiry() time.Duration {
+	if config.Config != nil && config.Config.PhoneOTPExpiry > 0 {
+		return config.Config.PhoneOTPExpiry
+	}
+	return 10 * time.Minute
+}
+
+// baseURL returns the public URL used in links sent to users
+func baseURL() string {
+	if config.Config != nil {
+		return config.Config.BaseURL
+	}
+	return ""
+}
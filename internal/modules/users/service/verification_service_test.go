@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	userRepository "service/internal/modules/users/repository"
+	"service/internal/shared/database/dbtest"
+	"service/internal/shared/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockedVerificationService returns a verification service whose repositories run on sqlmock
+func mockedVerificationService(t *testing.T) (*VerificationService, sqlmock.Sqlmock) {
+	t.Helper()
+	mock := dbtest.MockGlobal(t)
+	return &VerificationService{
+		verificationRepo: userRepository.NewVerificationRepository(),
+		userRepo:         userRepository.NewUserRepository(),
+	}, mock
+}
+
+// verificationRows returns the stored verification of a user, or no row when it is nil
+func verificationRows(v *model.ContactVerification) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "user_id", "channel", "target", "secret_hash", "expires_at", "attempts", "send_count", "last_sent_at"})
+	if v != nil {
+		rows.AddRow(v.ID, v.UserID, v.Channel, v.Target, v.SecretHash, v.ExpiresAt, v.Attempts, v.SendCount, v.LastSentAt)
+	}
+	return rows
+}
+
+func TestStoreSecretThrottle(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now()
+	sent := func(target string, count int, ago time.Duration) *model.ContactVerification {
+		return &model.ContactVerification{ID: uuid.New(), UserID: userID, Channel: model.VerificationChannelPhone, Target: target, SendCount: count, LastSentAt: now.Add(-ago)}
+	}
+
+	tests := []struct {
+		name      string
+		existing  *model.ContactVerification
+		want      error
+		wantCount int
+	}{
+		{"first send", nil, nil, 1},
+		{"resend after a minute", sent("+6281234", 2, 2*time.Minute), nil, 3},
+		{"resend within a minute", sent("+6281234", 1, 30*time.Second), model.ErrVerificationThrottled, 0},
+		{"daily cap reached", sent("+6281234", verificationMaxSendsPerDay, time.Hour), model.ErrVerificationThrottled, 0},
+		{"daily cap resets the next day", sent("+6281234", verificationMaxSendsPerDay, 25*time.Hour), nil, 1},
+		{"changed contact starts over", sent("+6289999", verificationMaxSendsPerDay, time.Hour), nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := mockedVerificationService(t)
+			mock.ExpectQuery(`SELECT \* FROM "contact_verifications" WHERE user_id = \$1 AND channel = \$2`).
+				WithArgs(userID, model.VerificationChannelPhone, 1).
+				WillReturnRows(verificationRows(tt.existing))
+			if tt.want == nil {
+				// A new secret starts without failed attempts
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO "contact_verifications" .* ON CONFLICT \("user_id","channel"\) DO UPDATE`).
+					WithArgs(userID, model.VerificationChannelPhone, "+6281234", sqlmock.AnyArg(), sqlmock.AnyArg(), 0, tt.wantCount, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+				mock.ExpectCommit()
+			}
+
+			err := s.storeSecret(context.Background(), userID, model.VerificationChannelPhone, "+6281234", "123456", now.Add(10*time.Minute))
+			assert.Equal(t, tt.want, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestVerifyPhone(t *testing.T) {
+	userID := uuid.New()
+	stored := func(attempts int, expiresIn time.Duration) *model.ContactVerification {
+		return &model.ContactVerification{
+			ID:         uuid.New(),
+			UserID:     userID,
+			Channel:    model.VerificationChannelPhone,
+			Target:     "+6281234",
+			SecretHash: hashVerificationSecret(userID, "123456"),
+			ExpiresAt:  time.Now().Add(expiresIn),
+			Attempts:   attempts,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		verification *model.ContactVerification
+		code         string
+		phoneMatched int64 // users whose phone still is the verified number
+		want         error
+	}{
+		{"right code", stored(0, time.Minute), "123456", 1, nil},
+		{"wrong code counts an attempt", stored(0, time.Minute), "654321", 0, model.ErrInvalidVerificationCode},
+		{"right code after too many attempts", stored(phoneOTPMaxAttempts, time.Minute), "123456", 0, model.ErrInvalidVerificationCode},
+		{"expired code", stored(0, -time.Minute), "123456", 0, model.ErrInvalidVerificationCode},
+		{"no code sent", nil, "123456", 0, model.ErrInvalidVerificationCode},
+		{"phone changed since the code was sent", stored(0, time.Minute), "123456", 0, model.ErrInvalidVerificationCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := mockedVerificationService(t)
+			mock.ExpectQuery(`SELECT \* FROM "contact_verifications"`).WillReturnRows(verificationRows(tt.verification))
+
+			usable := tt.verification != nil && tt.verification.Attempts < phoneOTPMaxAttempts && time.Now().Before(tt.verification.ExpiresAt)
+			switch {
+			case usable && tt.code != "123456":
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "contact_verifications" SET "attempts"=attempts \+ 1 WHERE id = \$1`).
+					WithArgs(tt.verification.ID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			case usable:
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "users" SET .*"phone_verified_at"=.* WHERE \(id = \$\d+ AND phone = \$\d+\)`).
+					WillReturnResult(sqlmock.NewResult(0, tt.phoneMatched))
+				mock.ExpectCommit()
+				// The code is only used up once the number it was sent to is verified
+				if tt.phoneMatched > 0 {
+					mock.ExpectBegin()
+					mock.ExpectExec(`DELETE FROM "contact_verifications" WHERE user_id = \$1 AND channel = \$2`).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectCommit()
+				}
+			}
+
+			assert.Equal(t, tt.want, s.VerifyPhone(context.Background(), userID, tt.code))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -3,13 +3,21 @@ package router
 import (
 	"net/http"
 	dashboardHandler "service/internal/modules/admin/handler"
+	appointmentHandler "service/internal/modules/appointments/handler"
 	branchHandler "service/internal/modules/branches/handler"
 	chatHandler "service/internal/modules/chat/handler"
+	corporateHandler "service/internal/modules/corporate/handler"
+	deviceHandler "service/internal/modules/devices/handler"
 	fileHandler "service/internal/modules/media/handler"
 	membershipHandler "service/internal/modules/membership/handler"
 	notificationHandler "service/internal/modules/notification/handler"
+	numberingHandler "service/internal/modules/numbering/handler"
 	orderHandler "service/internal/modules/orders/handler"
 	paymentHandler "service/internal/modules/payments/handler"
+	privacyHandler "service/internal/modules/privacy/handler"
+	searchHandler "service/internal/modules/search/handler"
+	slaHandler "service/internal/modules/sla/handler"
+	trackingHandler "service/internal/modules/tracking/handler"
 	userHandler "service/internal/modules/users/handler"
 	sharedHandlers "service/internal/shared/handlers"
 	"service/internal/shared/middleware"
@@ -37,6 +45,35 @@ func SetupRoutes(r *gin.Engine) {
 	membershipHandler := membershipHandler.NewMembershipHandler()
 	reportHandler := orderHandler.NewReportHandler()
 	ratingHandler := orderHandler.NewRatingHandler()
+	roleHdlr := userHandler.NewRoleHandler()
+	apiKeyHdlr := userHandler.NewAPIKeyHandler()
+	invitationHdlr := userHandler.NewInvitationHandler()
+	privacyHdlr := privacyHandler.NewPrivacyHandler()
+	jwksHdlr := userHandler.NewJWKSHandler()
+	verificationHdlr := userHandler.NewVerificationHandler()
+	deviceHdlr := userHandler.NewDeviceHandler()
+	customerDeviceHdlr := deviceHandler.NewDeviceHandler()
+	orderPartHdlr := orderHandler.NewOrderPartHandler()
+	appointmentHdlr := appointmentHandler.NewAppointmentHandler()
+	assignmentHdlr := orderHandler.NewAssignmentHandler()
+	dispatchHdlr := orderHandler.NewDispatchHandler()
+	trackingHdlr := trackingHandler.NewTrackingHandler()
+	handoverHdlr := orderHandler.NewHandoverHandler()
+	cancellationHdlr := orderHandler.NewCancellationHandler()
+	mediaHdlr := orderHandler.NewMediaHandler()
+	intakeHdlr := orderHandler.NewIntakeHandler()
+	noteHdlr := orderHandler.NewNoteHandler()
+	publicTrackingHdlr := orderHandler.NewPublicTrackingHandler()
+	slaHdlr := slaHandler.NewSLAHandler()
+	searchHdlr := searchHandler.NewSearchHandler()
+	corporateHdlr := corporateHandler.NewCorporateHandler()
+	numberingHdlr := numberingHandler.NewNumberingHandler()
+
+	// Permission checks are declared per route
+	perm := middleware.RequirePermission
+
+	// Retries of these requests with the same Idempotency-Key take effect only once
+	idempotent := middleware.IdempotencyMiddleware()
 
 	// Setup Swagger documentation routes
 	swaggerHandler.SetupSwaggerRoutes(r)
@@ -46,6 +83,9 @@ func SetupRoutes(r *gin.Engine) {
 	r.GET("/health/live", healthHandler.LivenessCheck)
 	r.GET("/health/ready", healthHandler.ReadinessCheck)
 
+	// Public keys for verifying issued tokens
+	r.GET("/.well-known/jwks.json", jwksHdlr.GetJWKS)
+
 	// API v1 routes
 	v1 := r.Group("/api/v1")
 	{
@@ -62,6 +102,9 @@ func SetupRoutes(r *gin.Engine) {
 				authPublic.POST("/logout", authHandler.Logout)
 				authPublic.POST("/forgot-password", authHandler.ForgotPassword)
 				authPublic.POST("/reset-password", authHandler.ResetPassword)
+				authPublic.GET("/invitations/details", invitationHdlr.GetInvitationDetails)
+				authPublic.POST("/invitations/accept", invitationHdlr.AcceptInvitation)
+				authPublic.POST("/verify-email", verificationHdlr.VerifyEmail)
 			}
 
 			// Payment callbacks (public, signature verified in handler)
@@ -71,56 +114,139 @@ func SetupRoutes(r *gin.Engine) {
 			public.GET("/branches", branchHdlr.GetBranches)
 			public.GET("/branches/nearest", branchHdlr.GetNearestBranches)
 			public.GET("/branches/:id", branchHdlr.GetBranch)
+
+			// Public order tracking for customers without an account (rate limited)
+			publicTracking := public.Group("/track")
+			publicTracking.Use(middleware.PublicTrackingRateLimitMiddleware())
+			{
+				publicTracking.POST("", publicTrackingHdlr.TrackOrder)
+				publicTracking.GET("/:token", publicTrackingHdlr.TrackOrderByToken)
+			}
 		}
 
 		// Protected routes (authentication required)
 		protected := v1.Group("/")
 		protected.Use(middleware.AuthMiddleware())
+		protected.Use(middleware.BranchScopeMiddleware())
 		{
 			// User profile routes
 			protected.GET("/auth/profile", authHandler.GetProfile)
 			protected.PUT("/auth/profile", authHandler.UpdateProfile)
 			protected.POST("/auth/change-password", authHandler.ChangePassword)
 			protected.PUT("/auth/fcm-token", authHandler.UpdateFCMToken)
+			protected.POST("/auth/devices", deviceHdlr.RegisterDevice)
+			protected.GET("/auth/devices", deviceHdlr.ListDevices)
+			protected.DELETE("/auth/devices/:id", deviceHdlr.RemoveDevice)
+			protected.GET("/auth/permissions", roleHdlr.GetMyPermissions)
+			protected.GET("/auth/verification", verificationHdlr.GetVerificationStatus)
+			protected.POST("/auth/verification/email/resend", verificationHdlr.ResendEmailVerification)
+			protected.POST("/auth/verification/phone/resend", verificationHdlr.ResendPhoneVerification)
+			protected.POST("/auth/verification/phone", verificationHdlr.VerifyPhone)
 
 			// Order routes
-			protected.POST("/orders", orderHdlr.CreateOrder)
-			protected.GET("/orders", orderHdlr.GetOrders)
-			protected.GET("/orders/:id", orderHdlr.GetOrder)
-			protected.PUT("/orders/:id/status", orderHdlr.UpdateOrderStatus)
-			protected.PUT("/orders/:id/assign-courier", orderHdlr.AssignCourier)
-			protected.PUT("/orders/:id/assign-technician", orderHdlr.AssignTechnician)
+			protected.POST("/orders", perm(model.PermissionOrderCreate), idempotent, orderHdlr.CreateOrder)
+			protected.GET("/orders", perm(model.PermissionOrderView), orderHdlr.GetOrders)
+			protected.GET("/orders/:id", perm(model.PermissionOrderView), orderHdlr.GetOrder)
+			protected.PUT("/orders/:id/status", perm(model.PermissionOrderUpdateStatus), orderHdlr.UpdateOrderStatus)
+			protected.PUT("/orders/:id/assign-courier", perm(model.PermissionOrderAssign), orderHdlr.AssignCourier)
+			protected.PUT("/orders/:id/assign-technician", perm(model.PermissionOrderAssign), orderHdlr.AssignTechnician)
+			protected.POST("/orders/:id/auto-assign", perm(model.PermissionOrderAssign), assignmentHdlr.AutoAssign)
+			protected.GET("/orders/:id/technician-candidates", perm(model.PermissionOrderAssign), assignmentHdlr.GetCandidates)
+			protected.GET("/branches/:id/technician-workload", perm(model.PermissionOrderAssign), assignmentHdlr.GetBranchWorkload)
+			protected.POST("/branches/:id/dispatch-plan", perm(model.PermissionOrderAssign), dispatchHdlr.PlanRoutes)
+			protected.PUT("/orders/:id/delivery-window", perm(model.PermissionOrderView), dispatchHdlr.SetDeliveryWindow)
+
+			// Handover proof
+			protected.POST("/orders/:id/handover-code", perm(model.PermissionOrderView), handoverHdlr.RequestHandoverCode)
+			protected.GET("/orders/:id/handovers", perm(model.PermissionOrderViewAll), handoverHdlr.GetHandoverHistory)
+			protected.POST("/orders/:id/cancel", perm(model.PermissionOrderView), cancellationHdlr.CancelOrder)
+			protected.GET("/orders/:id/media", perm(model.PermissionOrderView), mediaHdlr.ListMedia)
+			protected.POST("/orders/:id/media", perm(model.PermissionOrderUpdateStatus), mediaHdlr.UploadMedia)
+			protected.PUT("/orders/:id/media", perm(model.PermissionOrderUpdateStatus), mediaHdlr.ReorderMedia)
+			protected.PUT("/orders/:id/media/:media_id", perm(model.PermissionOrderUpdateStatus), mediaHdlr.UpdateMedia)
+			protected.DELETE("/orders/:id/media/:media_id", perm(model.PermissionFileManage), mediaHdlr.DeleteMedia)
+			protected.GET("/orders/:id/intake", perm(model.PermissionOrderView), intakeHdlr.GetIntake)
+			protected.PUT("/orders/:id/intake", perm(model.PermissionOrderUpdateStatus), intakeHdlr.FillIntake)
+			protected.POST("/orders/:id/intake/acknowledge", perm(model.PermissionOrderView), intakeHdlr.AcknowledgeIntake)
+			protected.GET("/orders/:id/job-ticket", perm(model.PermissionOrderViewAll), intakeHdlr.GetJobTicket)
+
+			// Internal staff notes
+			protected.GET("/orders/:id/notes", perm(model.PermissionOrderView), noteHdlr.ListNotes)
+			protected.POST("/orders/:id/notes", perm(model.PermissionOrderView), noteHdlr.AddNote)
+			protected.POST("/orders/:id/notes/:note_id/pin", perm(model.PermissionOrderView), noteHdlr.PinNote)
+			protected.DELETE("/orders/:id/notes/:note_id/pin", perm(model.PermissionOrderView), noteHdlr.UnpinNote)
+			protected.GET("/orders/:id/history", perm(model.PermissionOrderView), noteHdlr.GetOrderHistory)
+
+			// SLA monitoring
+			protected.GET("/sla/at-risk", perm(model.PermissionOrderViewAll), slaHdlr.ListAtRiskOrders)
+			protected.GET("/orders/:id/sla", perm(model.PermissionOrderViewAll), slaHdlr.GetOrderSLA)
+
+			// Order search
+			protected.GET("/search/orders", perm(model.PermissionOrderViewAll), searchHdlr.SearchOrders)
+
+			// Corporate accounts and work orders
+			protected.GET("/corporate/accounts", perm(model.PermissionOrderView), corporateHdlr.ListMyAccounts)
+			protected.GET("/corporate/accounts/:id", perm(model.PermissionOrderView), corporateHdlr.GetAccount)
+			protected.GET("/corporate/accounts/:id/work-orders", perm(model.PermissionOrderView), corporateHdlr.ListWorkOrders)
+			protected.GET("/corporate/accounts/:id/invoices", perm(model.PermissionOrderView), corporateHdlr.ListInvoices)
+			protected.POST("/corporate/work-orders", perm(model.PermissionOrderCreate), corporateHdlr.CreateWorkOrder)
+			protected.GET("/corporate/work-orders/:id", perm(model.PermissionOrderView), corporateHdlr.GetWorkOrder)
+			protected.GET("/corporate/invoices/:id", perm(model.PermissionOrderView), corporateHdlr.GetInvoice)
+
+			// Live courier tracking
+			protected.GET("/orders/:id/courier-location", perm(model.PermissionOrderView), trackingHdlr.GetCourierLocation)
+			protected.GET("/orders/:id/courier-location/ws", perm(model.PermissionOrderView), trackingHdlr.SubscribeCourierLocation)
+			protected.GET("/orders/:id/courier-trail", perm(model.PermissionOrderViewAll), trackingHdlr.GetCourierTrail)
+			protected.GET("/orders/:id/parts", perm(model.PermissionOrderView), orderPartHdlr.ListParts)
+			protected.GET("/orders/:id/device-history", perm(model.PermissionOrderView), customerDeviceHdlr.GetOrderDeviceHistory)
+
+			// Customer device registry
+			protected.GET("/device-models", customerDeviceHdlr.ListDeviceModels)
+			protected.POST("/devices", perm(model.PermissionOrderCreate), customerDeviceHdlr.RegisterDevice)
+			protected.GET("/devices", perm(model.PermissionOrderCreate), customerDeviceHdlr.ListDevices)
+			protected.GET("/devices/:id", perm(model.PermissionOrderCreate), customerDeviceHdlr.GetDevice)
+			protected.PUT("/devices/:id", perm(model.PermissionOrderCreate), customerDeviceHdlr.UpdateDevice)
+			protected.DELETE("/devices/:id", perm(model.PermissionOrderCreate), customerDeviceHdlr.DeleteDevice)
+			protected.GET("/devices/:id/history", perm(model.PermissionOrderCreate), customerDeviceHdlr.GetDeviceHistory)
+
+			// Appointment booking
+			protected.GET("/branches/:id/appointment-slots", appointmentHdlr.ListAvailableSlots)
+			protected.POST("/appointments", perm(model.PermissionOrderCreate), appointmentHdlr.BookAppointment)
+			protected.GET("/appointments", perm(model.PermissionOrderCreate), appointmentHdlr.ListMyAppointments)
+			protected.GET("/appointments/:id", perm(model.PermissionOrderView), appointmentHdlr.GetAppointment)
+			protected.PUT("/appointments/:id/reschedule", perm(model.PermissionOrderCreate), appointmentHdlr.RescheduleAppointment)
+			protected.POST("/appointments/:id/cancel", perm(model.PermissionOrderCreate), appointmentHdlr.CancelAppointment)
 
 			// Payment routes
-			protected.POST("/payments/create-invoice", paymentHdlr.CreateInvoice)
-			protected.POST("/payments/process", paymentHdlr.ProcessPayment)
-			protected.GET("/payments/:id", paymentHdlr.GetPayment)
-			protected.GET("/payments/order/:orderId", paymentHdlr.GetPaymentsByOrder)
+			protected.POST("/payments/create-invoice", perm(model.PermissionPaymentCreate), idempotent, paymentHdlr.CreateInvoice)
+			protected.POST("/payments/process", perm(model.PermissionPaymentProcess), paymentHdlr.ProcessPayment)
+			protected.GET("/payments/:id", perm(model.PermissionPaymentView), paymentHdlr.GetPayment)
+			protected.GET("/payments/order/:orderId", perm(model.PermissionPaymentView), paymentHdlr.GetPaymentsByOrder)
 
 			// Notification routes
 			protected.GET("/notifications", notificationHandler.GetNotifications)
 			protected.PUT("/notifications/:id/read", notificationHandler.MarkAsRead)
-			protected.POST("/notifications", notificationHandler.SendNotification)
-			protected.POST("/notifications/order/:orderId/status", notificationHandler.SendOrderStatusNotification)
-			protected.POST("/notifications/order/:orderId/payment", notificationHandler.SendPaymentNotification)
+			protected.POST("/notifications", perm(model.PermissionNotificationSend), notificationHandler.SendNotification)
+			protected.POST("/notifications/order/:orderId/status", perm(model.PermissionNotificationSend), notificationHandler.SendOrderStatusNotification)
+			protected.POST("/notifications/order/:orderId/payment", perm(model.PermissionNotificationSend), notificationHandler.SendPaymentNotification)
 
 			// File upload routes
-			protected.POST("/files/upload", fileHandler.UploadFile)
-			protected.POST("/files/orders/photo", fileHandler.UploadOrderPhoto)
+			protected.POST("/files/upload", perm(model.PermissionFileUpload), fileHandler.UploadFile)
+			protected.POST("/files/orders/photo", perm(model.PermissionFileUpload), fileHandler.UploadOrderPhoto)
 			protected.POST("/files/users/avatar", fileHandler.UploadUserAvatar)
-			protected.GET("/files/url", fileHandler.GetFileURL)
-			protected.GET("/files/list", fileHandler.ListFiles)
-			protected.DELETE("/files/delete", fileHandler.DeleteFile)
+			protected.GET("/files/url", perm(model.PermissionFileUpload), fileHandler.GetFileURL)
+			protected.GET("/files/list", perm(model.PermissionFileManage), fileHandler.ListFiles)
+			protected.DELETE("/files/delete", perm(model.PermissionFileManage), fileHandler.DeleteFile)
 
 			// Chat routes
-			protected.GET("/chat/orders/:orderId", chatHandler.GetChatMessages)
-			protected.POST("/chat/orders/:orderId", chatHandler.SendMessage)
+			protected.GET("/chat/orders/:orderId", perm(model.PermissionChatAccess), chatHandler.GetChatMessages)
+			protected.POST("/chat/orders/:orderId", perm(model.PermissionChatAccess), chatHandler.SendMessage)
 
 			// Dashboard routes
-			protected.GET("/dashboard/overview", dashboardHandler.GetOverview)
-			protected.GET("/dashboard/orders", dashboardHandler.GetOrderStats)
-			protected.GET("/dashboard/revenue", dashboardHandler.GetRevenueStats)
-			protected.GET("/dashboard/branches", dashboardHandler.GetBranchStats)
+			protected.GET("/dashboard/overview", perm(model.PermissionDashboardView), dashboardHandler.GetOverview)
+			protected.GET("/dashboard/orders", perm(model.PermissionDashboardView), dashboardHandler.GetOrderStats)
+			protected.GET("/dashboard/revenue", perm(model.PermissionReportViewRevenue), dashboardHandler.GetRevenueStats)
+			protected.GET("/dashboard/branches", perm(model.PermissionDashboardView), dashboardHandler.GetBranchStats)
 
 			// Membership routes
 			protected.GET("/membership", membershipHandler.GetMembership)
@@ -135,10 +261,11 @@ func SetupRoutes(r *gin.Engine) {
 			protected.GET("/membership/usage", membershipHandler.GetMembershipUsage)
 
 			// Report routes
-			protected.GET("/reports/current-month", reportHandler.GetCurrentMonthReport)
-			protected.GET("/reports/monthly", reportHandler.GetMonthlyReport)
-			protected.GET("/reports/yearly", reportHandler.GetYearlyReport)
-			protected.GET("/reports/summary", reportHandler.GetReportSummary)
+			protected.GET("/reports/current-month", perm(model.PermissionReportViewRevenue), reportHandler.GetCurrentMonthReport)
+			protected.GET("/reports/monthly", perm(model.PermissionReportViewRevenue), reportHandler.GetMonthlyReport)
+			protected.GET("/reports/yearly", perm(model.PermissionReportViewRevenue), reportHandler.GetYearlyReport)
+			protected.GET("/reports/summary", perm(model.PermissionReportView), reportHandler.GetReportSummary)
+			protected.GET("/reports/cancellations", perm(model.PermissionReportView), cancellationHdlr.GetCancellationReport)
 
 			// Rating routes
 			protected.POST("/ratings", ratingHandler.CreateRating)
@@ -147,85 +274,176 @@ func SetupRoutes(r *gin.Engine) {
 			protected.GET("/ratings/:id", ratingHandler.GetRating)
 			protected.PUT("/ratings/:id", ratingHandler.UpdateRating)
 			protected.DELETE("/ratings/:id", ratingHandler.DeleteRating)
+
+			// Personal data requests (UU PDP)
+			protected.POST("/privacy/requests", privacyHdlr.CreateMyRequest)
+			protected.GET("/privacy/requests", privacyHdlr.ListMyRequests)
+			protected.GET("/privacy/requests/:id/download", privacyHdlr.DownloadMyExport)
 		}
 
-		// Admin routes (admin role required)
+		// Admin routes (permission checked per route)
 		admin := v1.Group("/admin")
 		admin.Use(middleware.AuthMiddleware())
-		admin.Use(middleware.RoleMiddleware(model.RoleAdminPusat, model.RoleAdminCabang))
+		admin.Use(middleware.BranchScopeMiddleware())
 		{
 			// Branch management
-			admin.POST("/branches", branchHdlr.CreateBranch)
-			admin.PUT("/branches/:id", branchHdlr.UpdateBranch)
-			admin.DELETE("/branches/:id", branchHdlr.DeleteBranch)
-			admin.GET("/branches", branchHdlr.GetBranches)
+			admin.POST("/branches", perm(model.PermissionBranchManage), branchHdlr.CreateBranch)
+			admin.PUT("/branches/:id", perm(model.PermissionBranchManage), branchHdlr.UpdateBranch)
+			admin.DELETE("/branches/:id", perm(model.PermissionBranchManage), branchHdlr.DeleteBranch)
+			admin.GET("/branches", perm(model.PermissionBranchManage), branchHdlr.GetBranches)
+
+			// Appointment slot templates
+			admin.GET("/branches/:id/slot-templates", perm(model.PermissionBranchManage), appointmentHdlr.ListSlotTemplates)
+			admin.POST("/branches/:id/slot-templates", perm(model.PermissionBranchManage), appointmentHdlr.CreateSlotTemplate)
+			admin.PUT("/slot-templates/:id", perm(model.PermissionBranchManage), appointmentHdlr.UpdateSlotTemplate)
+			admin.DELETE("/slot-templates/:id", perm(model.PermissionBranchManage), appointmentHdlr.DeleteSlotTemplate)
+
+			// Order and invoice number formats
+			admin.GET("/branches/:id/numbering", perm(model.PermissionBranchManage), numberingHdlr.GetFormats)
+			admin.PUT("/branches/:id/numbering/:kind", perm(model.PermissionBranchManage), numberingHdlr.UpdateFormat)
+
+			// Business hours and SLA policies
+			admin.GET("/branches/:id/business-hours", perm(model.PermissionBranchManage), slaHdlr.GetBusinessHours)
+			admin.PUT("/branches/:id/business-hours", perm(model.PermissionBranchManage), slaHdlr.UpdateBusinessHours)
+			admin.GET("/sla-policies", perm(model.PermissionSLAManage), slaHdlr.ListPolicies)
+			admin.POST("/sla-policies", perm(model.PermissionSLAManage), slaHdlr.CreatePolicy)
+			admin.PUT("/sla-policies/:id", perm(model.PermissionSLAManage), slaHdlr.UpdatePolicy)
+			admin.DELETE("/sla-policies/:id", perm(model.PermissionSLAManage), slaHdlr.DeletePolicy)
+			admin.GET("/cancellation-fees", perm(model.PermissionCatalogManage), cancellationHdlr.GetFeeSchedule)
+			admin.PUT("/cancellation-fees", perm(model.PermissionCatalogManage), cancellationHdlr.UpdateFeeSchedule)
+			admin.GET("/intake-checklists", perm(model.PermissionCatalogManage), intakeHdlr.ListChecklists)
+			admin.PUT("/intake-checklists/:service_type", perm(model.PermissionCatalogManage), intakeHdlr.SaveChecklist)
+
+			// Global search across orders, users and branches
+			admin.GET("/search", perm(model.PermissionUserView), searchHdlr.GlobalSearch)
+
+			// Corporate accounts and monthly invoicing
+			admin.GET("/corporate-accounts", perm(model.PermissionCorporateManage), corporateHdlr.ListAccounts)
+			admin.POST("/corporate-accounts", perm(model.PermissionCorporateManage), corporateHdlr.CreateAccount)
+			admin.PUT("/corporate-accounts/:id", perm(model.PermissionCorporateManage), corporateHdlr.UpdateAccount)
+			admin.POST("/corporate-accounts/:id/contacts", perm(model.PermissionCorporateManage), corporateHdlr.SaveContact)
+			admin.DELETE("/corporate-accounts/:id/contacts/:user_id", perm(model.PermissionCorporateManage), corporateHdlr.RemoveContact)
+			admin.POST("/corporate-accounts/:id/invoices", perm(model.PermissionCorporateManage), corporateHdlr.GenerateInvoice)
+			admin.POST("/corporate-invoices/:id/pay", perm(model.PermissionCorporateManage), corporateHdlr.PayInvoice)
 
 			// User management
-			admin.GET("/users", authHandler.GetUsers)
-			admin.GET("/users/:id", authHandler.GetUser)
-			admin.PUT("/users/:id", authHandler.UpdateUser)
-			admin.DELETE("/users/:id", authHandler.DeleteUser)
+			admin.GET("/users", perm(model.PermissionUserView), authHandler.GetUsers)
+			admin.GET("/users/:id", perm(model.PermissionUserView), authHandler.GetUser)
+			admin.PUT("/users/:id", perm(model.PermissionUserManage), authHandler.UpdateUser)
+			admin.DELETE("/users/:id", perm(model.PermissionUserManage), authHandler.DeleteUser)
+			admin.PUT("/users/:id/role", perm(model.PermissionRoleManage), roleHdlr.AssignRole)
+
+			// Technician skills and shifts
+			admin.GET("/technicians/:id/profile", perm(model.PermissionUserView), assignmentHdlr.GetTechnicianProfile)
+			admin.PUT("/technicians/:id/skills", perm(model.PermissionUserManage), assignmentHdlr.UpdateTechnicianSkills)
+			admin.PUT("/technicians/:id/shifts", perm(model.PermissionUserManage), assignmentHdlr.UpdateTechnicianShifts)
+
+			// Staff invitations
+			admin.GET("/invitations", perm(model.PermissionUserManage), invitationHdlr.ListInvitations)
+			admin.POST("/invitations", perm(model.PermissionUserManage), invitationHdlr.CreateInvitation)
+			admin.POST("/invitations/:id/resend", perm(model.PermissionUserManage), invitationHdlr.ResendInvitation)
+			admin.DELETE("/invitations/:id", perm(model.PermissionUserManage), invitationHdlr.RevokeInvitation)
 
 			// Order management
-			admin.GET("/orders", orderHdlr.GetAllOrders)
-			admin.PUT("/orders/:id", orderHdlr.UpdateOrder)
-			admin.DELETE("/orders/:id", orderHdlr.DeleteOrder)
+			admin.GET("/orders", perm(model.PermissionOrderViewAll), orderHdlr.GetAllOrders)
+			admin.PUT("/orders/:id", perm(model.PermissionOrderUpdate), orderHdlr.UpdateOrder)
+			admin.DELETE("/orders/:id", perm(model.PermissionOrderDelete), orderHdlr.DeleteOrder)
 
 			// Payment management
-			admin.GET("/payments", paymentHdlr.GetAllPayments)
-			admin.PUT("/payments/:id", paymentHdlr.UpdatePayment)
+			admin.GET("/payments", perm(model.PermissionPaymentViewAll), paymentHdlr.GetAllPayments)
+			admin.PUT("/payments/:id", perm(model.PermissionPaymentUpdate), paymentHdlr.UpdatePayment)
 
 			// Dashboard admin
-			admin.GET("/dashboard", dashboardHandler.GetAdminDashboard)
+			admin.GET("/dashboard", perm(model.PermissionDashboardAdmin), dashboardHandler.GetAdminDashboard)
 
 			// Membership management
-			admin.GET("/membership/list", membershipHandler.ListMemberships)
-			admin.GET("/membership/stats", membershipHandler.GetMembershipStats)
-			admin.GET("/membership/top-spenders", membershipHandler.GetTopSpenders)
+			admin.GET("/membership/list", perm(model.PermissionMembershipManage), membershipHandler.ListMemberships)
+			admin.GET("/membership/stats", perm(model.PermissionMembershipManage), membershipHandler.GetMembershipStats)
+			admin.GET("/membership/top-spenders", perm(model.PermissionMembershipManage), membershipHandler.GetTopSpenders)
+
+			// Role and permission management
+			admin.GET("/permissions", perm(model.PermissionRoleManage), roleHdlr.ListPermissions)
+			admin.GET("/roles", perm(model.PermissionRoleManage), roleHdlr.ListRoles)
+			admin.GET("/roles/:id", perm(model.PermissionRoleManage), roleHdlr.GetRole)
+			admin.POST("/roles", perm(model.PermissionRoleManage), roleHdlr.CreateRole)
+			admin.PUT("/roles/:id", perm(model.PermissionRoleManage), roleHdlr.UpdateRole)
+			admin.DELETE("/roles/:id", perm(model.PermissionRoleManage), roleHdlr.DeleteRole)
+
+			// API key management for partner integrations
+			admin.GET("/api-keys", perm(model.PermissionAPIKeyManage), apiKeyHdlr.ListAPIKeys)
+			admin.POST("/api-keys", perm(model.PermissionAPIKeyManage), apiKeyHdlr.CreateAPIKey)
+			admin.GET("/api-keys/:id", perm(model.PermissionAPIKeyManage), apiKeyHdlr.GetAPIKey)
+			admin.PUT("/api-keys/:id", perm(model.PermissionAPIKeyManage), apiKeyHdlr.UpdateAPIKey)
+			admin.DELETE("/api-keys/:id", perm(model.PermissionAPIKeyManage), apiKeyHdlr.RevokeAPIKey)
+
+			// Personal data requests (UU PDP)
+			admin.GET("/privacy/requests", perm(model.PermissionPrivacyManage), privacyHdlr.ListRequests)
+			admin.POST("/privacy/requests", perm(model.PermissionPrivacyManage), privacyHdlr.CreateRequest)
+			admin.POST("/privacy/requests/:id/approve", perm(model.PermissionPrivacyManage), privacyHdlr.ApproveRequest)
+			admin.POST("/privacy/requests/:id/reject", perm(model.PermissionPrivacyManage), privacyHdlr.RejectRequest)
+
+			// Device catalog
+			admin.GET("/device-models", perm(model.PermissionCatalogManage), customerDeviceHdlr.ListAllDeviceModels)
+			admin.POST("/device-models", perm(model.PermissionCatalogManage), customerDeviceHdlr.CreateDeviceModel)
+			admin.PUT("/device-models/:id", perm(model.PermissionCatalogManage), customerDeviceHdlr.UpdateDeviceModel)
 		}
 
-		// Cashier routes (kasir role required)
+		// Cashier routes (permission checked per route)
 		cashier := v1.Group("/cashier")
 		cashier.Use(middleware.AuthMiddleware())
-		cashier.Use(middleware.RoleMiddleware(model.RoleKasir))
+		cashier.Use(middleware.BranchScopeMiddleware())
 		{
 			// Order processing
-			cashier.GET("/orders", orderHdlr.GetCashierOrders)
-			cashier.PUT("/orders/:id/status", orderHdlr.UpdateOrderStatus)
-			cashier.POST("/orders/:id/payment", paymentHdlr.ProcessPayment)
+			cashier.GET("/orders", perm(model.PermissionOrderViewAll), orderHdlr.GetCashierOrders)
+			cashier.PUT("/orders/:id/status", perm(model.PermissionOrderUpdateStatus), orderHdlr.UpdateOrderStatus)
+			cashier.POST("/orders/:id/handover", perm(model.PermissionOrderUpdateStatus), handoverHdlr.CompleteHandover)
+			cashier.POST("/orders/:id/payment", perm(model.PermissionPaymentProcess), idempotent, paymentHdlr.ProcessPayment)
 
 			// Branch orders
-			cashier.GET("/branches/:id/orders", orderHdlr.GetBranchOrders)
+			cashier.GET("/branches/:id/orders", perm(model.PermissionOrderViewAll), orderHdlr.GetBranchOrders)
+
+			// Walk-in appointments
+			cashier.GET("/branches/:id/appointments", perm(model.PermissionOrderViewAll), appointmentHdlr.ListBranchAppointments)
+			cashier.POST("/appointments/:id/check-in", perm(model.PermissionOrderCreate), appointmentHdlr.CheckInAppointment)
 		}
 
-		// Technician routes (teknisi role required)
+		// Technician routes (permission checked per route)
 		technician := v1.Group("/technician")
 		technician.Use(middleware.AuthMiddleware())
-		technician.Use(middleware.RoleMiddleware(model.RoleTeknisi))
+		technician.Use(middleware.BranchScopeMiddleware())
 		{
 			// Order management
-			technician.GET("/orders", orderHdlr.GetTechnicianOrders)
-			technician.PUT("/orders/:id/status", orderHdlr.UpdateOrderStatus)
-			technician.POST("/orders/:id/photo", fileHandler.UploadOrderPhoto)
+			technician.GET("/orders", perm(model.PermissionOrderView), orderHdlr.GetTechnicianOrders)
+			technician.PUT("/orders/:id/status", perm(model.PermissionOrderUpdateStatus), orderHdlr.UpdateOrderStatus)
+			technician.POST("/orders/:id/photo", perm(model.PermissionFileUpload), fileHandler.UploadOrderPhoto)
+			technician.POST("/orders/:id/parts", perm(model.PermissionOrderUpdateStatus), orderPartHdlr.RecordPart)
 
 			// Chat
-			technician.GET("/chat/orders/:orderId", chatHandler.GetChatMessages)
-			technician.POST("/chat/orders/:orderId", chatHandler.SendMessage)
+			technician.GET("/chat/orders/:orderId", perm(model.PermissionChatAccess), chatHandler.GetChatMessages)
+			technician.POST("/chat/orders/:orderId", perm(model.PermissionChatAccess), chatHandler.SendMessage)
 		}
 
-		// Courier routes (kurir role required)
+		// Courier routes (permission checked per route)
 		courier := v1.Group("/courier")
 		courier.Use(middleware.AuthMiddleware())
-		courier.Use(middleware.RoleMiddleware(model.RoleKurir))
+		courier.Use(middleware.BranchScopeMiddleware())
 		{
 			// Order management
-			courier.GET("/orders", orderHdlr.GetCourierOrders)
-			courier.PUT("/orders/:id/status", orderHdlr.UpdateOrderStatus)
-			courier.POST("/orders/:id/photo", fileHandler.UploadOrderPhoto)
+			courier.GET("/orders", perm(model.PermissionOrderView), orderHdlr.GetCourierOrders)
+			courier.PUT("/orders/:id/status", perm(model.PermissionOrderUpdateStatus), orderHdlr.UpdateOrderStatus)
+			courier.POST("/orders/:id/photo", perm(model.PermissionFileUpload), fileHandler.UploadOrderPhoto)
+			courier.POST("/orders/:id/handover", perm(model.PermissionOrderUpdateStatus), handoverHdlr.CompleteHandover)
 
 			// Available jobs
-			courier.GET("/jobs", orderHdlr.GetAvailableJobs)
-			courier.POST("/jobs/:id/accept", orderHdlr.AcceptJob)
+			courier.GET("/jobs", perm(model.PermissionOrderAcceptJob), orderHdlr.GetAvailableJobs)
+			courier.POST("/jobs/:id/accept", perm(model.PermissionOrderAcceptJob), orderHdlr.AcceptJob)
+			courier.GET("/routes", perm(model.PermissionOrderAcceptJob), dispatchHdlr.GetCourierRoutes)
+			courier.POST("/routes/:id/accept", perm(model.PermissionOrderAcceptJob), dispatchHdlr.AcceptRoute)
+			courier.GET("/my-routes", perm(model.PermissionOrderView), dispatchHdlr.ListMyRoutes)
+
+			// Live location
+			courier.POST("/orders/:id/location", perm(model.PermissionOrderUpdateStatus), trackingHdlr.RecordLocation)
+			courier.GET("/orders/:id/location/ws", perm(model.PermissionOrderUpdateStatus), trackingHdlr.LocationSocket)
 		}
 	}
 
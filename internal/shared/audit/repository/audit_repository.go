@@ -22,6 +22,11 @@ func NewAuditRepository() *AuditRepository {
 	}
 }
 
+// Available reports whether the repository is backed by a database
+func (r *AuditRepository) Available() bool {
+	return r.db != nil
+}
+
 // Create creates a new audit trail entry
 func (r *AuditRepository) Create(ctx context.Context, audit *model.AuditTrail) error {
 	return r.db.WithContext(ctx).Create(audit).Error
@@ -51,6 +56,9 @@ func (r *AuditRepository) List(ctx context.Context, offset, limit int, filters *
 		if filters.UserID != nil {
 			query = query.Where("user_id = ?", *filters.UserID)
 		}
+		if filters.APIKeyID != nil {
+			query = query.Where("api_key_id = ?", *filters.APIKeyID)
+		}
 		if filters.Action != "" {
 			query = query.Where("action = ?", filters.Action)
 		}
@@ -116,6 +124,7 @@ func (r *AuditRepository) GetByUser(ctx context.Context, userID uuid.UUID, limit
 // AuditFilters represents filters for audit trail queries
 type AuditFilters struct {
 	UserID     *uuid.UUID
+	APIKeyID   *uuid.UUID
 	Action     model.AuditAction
 	Resource   string
 	ResourceID *uuid.UUID
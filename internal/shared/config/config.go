@@ -27,6 +27,21 @@ type AppConfig struct {
 	JWTExpiry     time.Duration
 	RefreshExpiry time.Duration
 
+	// JWT signing key configuration
+	JWTKeyEncryptionKey    string
+	JWTKeyRotationInterval time.Duration
+	JWTKeySyncInterval     time.Duration
+	JWTAcceptLegacyHS256   bool
+
+	// Staff invitation configuration
+	InvitationExpiry time.Duration
+
+	// Contact verification configuration
+	EmailVerificationExpiry        time.Duration
+	PhoneOTPExpiry                 time.Duration
+	RequireVerifiedContactOrders   bool
+	RequireVerifiedContactMessages bool
+
 	// Payment gateway configuration
 	MidtransServerKey    string
 	MidtransClientKey    string
@@ -67,6 +82,66 @@ type AppConfig struct {
 	// Reconciliation
 	ReconcileInterval time.Duration
 
+	// Personal data requests
+	PrivacyJobInterval time.Duration
+
+	// Appointments
+	AppointmentBookingDays      int
+	AppointmentReminderInterval time.Duration
+
+	// Technician assignment
+	TechnicianAutoAssign         bool
+	TechnicianAssignmentStrategy string
+	TechnicianMaxOpenOrders      int
+	TechnicianRatingWindow       time.Duration
+
+	// Courier dispatch planning
+	DispatchClusterRadiusKm int
+	DispatchMaxStops        int
+	DispatchAverageSpeedKmh int
+	DispatchStopDuration    time.Duration
+	DispatchPlanTTL         time.Duration
+
+	// Courier location tracking
+	TrackingLocationTTL      time.Duration
+	TrackingTrailMinInterval time.Duration
+	TrackingTrailMinDistance int
+
+	// Handover proof
+	HandoverProofRequired bool
+	HandoverCodeExpiry    time.Duration
+
+	// Public order tracking
+	PublicTrackingRateLimit   int
+	PublicTrackingRateWindow  time.Duration
+	PublicTrackingMaxFailures int
+	PublicTrackingLockout     time.Duration
+
+	// SLA tracking
+	SLACheckInterval         time.Duration
+	SLADefaultPickupTarget   time.Duration
+	SLADefaultRepairTarget   time.Duration
+	SLADefaultDeliveryTarget time.Duration
+	SLAAtRiskPercent         int
+	SLADefaultOpenTime       string
+	SLADefaultCloseTime      string
+
+	// Corporate accounts
+	CorporateInvoiceInterval time.Duration
+	CorporatePaymentTermDays int
+
+	// Order cancellation
+	CancellationPickupFee     float64 // once a courier is on the way
+	CancellationDiagnosticFee float64 // once the device is at the branch
+
+	// Numbering, the formats branches use until they configure their own
+	OrderNumberFormat   string
+	InvoiceNumberFormat string
+
+	// Idempotency
+	IdempotencyTTL     time.Duration // how long responses are kept for replay
+	IdempotencyLockTTL time.Duration // how long a request holds its key while in flight
+
 	// Observability
 	SentryDSN string
 }
@@ -97,6 +172,21 @@ func LoadConfig() {
 		JWTExpiry:     getDurationEnv("JWT_EXPIRY", 24*time.Hour),
 		RefreshExpiry: getDurationEnv("REFRESH_EXPIRY", 7*24*time.Hour),
 
+		// JWT signing key configuration
+		JWTKeyEncryptionKey:    getEnv("JWT_KEY_ENCRYPTION_KEY", ""),
+		JWTKeyRotationInterval: getDurationEnv("JWT_KEY_ROTATION_INTERVAL", 30*24*time.Hour),
+		JWTKeySyncInterval:     getDurationEnv("JWT_KEY_SYNC_INTERVAL", 5*time.Minute),
+		JWTAcceptLegacyHS256:   getBoolEnv("JWT_ACCEPT_LEGACY_HS256", true),
+
+		// Staff invitation configuration
+		InvitationExpiry: getDurationEnv("INVITATION_EXPIRY", 72*time.Hour),
+
+		// Contact verification configuration
+		EmailVerificationExpiry:        getDurationEnv("EMAIL_VERIFICATION_EXPIRY", 24*time.Hour),
+		PhoneOTPExpiry:                 getDurationEnv("PHONE_OTP_EXPIRY", 10*time.Minute),
+		RequireVerifiedContactOrders:   getBoolEnv("REQUIRE_VERIFIED_CONTACT_ORDERS", true),
+		RequireVerifiedContactMessages: getBoolEnv("REQUIRE_VERIFIED_CONTACT_MESSAGES", true),
+
 		// Payment gateway configuration
 		MidtransServerKey:    getEnv("MIDTRANS_SERVER_KEY", ""),
 		MidtransClientKey:    getEnv("MIDTRANS_CLIENT_KEY", ""),
@@ -137,6 +227,66 @@ func LoadConfig() {
 		// Reconciliation
 		ReconcileInterval: getDurationEnv("RECONCILE_INTERVAL", 5*time.Minute),
 
+		// Personal data requests
+		PrivacyJobInterval: getDurationEnv("PRIVACY_JOB_INTERVAL", 10*time.Minute),
+
+		// Appointments
+		AppointmentBookingDays:      getIntEnv("APPOINTMENT_BOOKING_DAYS", 30),
+		AppointmentReminderInterval: getDurationEnv("APPOINTMENT_REMINDER_INTERVAL", 15*time.Minute),
+
+		// Technician assignment
+		TechnicianAutoAssign:         getBoolEnv("TECHNICIAN_AUTO_ASSIGN", true),
+		TechnicianAssignmentStrategy: getEnv("TECHNICIAN_ASSIGNMENT_STRATEGY", "least_loaded"),
+		TechnicianMaxOpenOrders:      getIntEnv("TECHNICIAN_MAX_OPEN_ORDERS", 10),
+		TechnicianRatingWindow:       getDurationEnv("TECHNICIAN_RATING_WINDOW", 90*24*time.Hour),
+
+		// Courier dispatch planning
+		DispatchClusterRadiusKm: getIntEnv("DISPATCH_CLUSTER_RADIUS_KM", 5),
+		DispatchMaxStops:        getIntEnv("DISPATCH_MAX_STOPS", 8),
+		DispatchAverageSpeedKmh: getIntEnv("DISPATCH_AVERAGE_SPEED_KMH", 25),
+		DispatchStopDuration:    getDurationEnv("DISPATCH_STOP_DURATION", 10*time.Minute),
+		DispatchPlanTTL:         getDurationEnv("DISPATCH_PLAN_TTL", 10*time.Minute),
+
+		// Courier location tracking
+		TrackingLocationTTL:      getDurationEnv("TRACKING_LOCATION_TTL", 10*time.Minute),
+		TrackingTrailMinInterval: getDurationEnv("TRACKING_TRAIL_MIN_INTERVAL", 30*time.Second),
+		TrackingTrailMinDistance: getIntEnv("TRACKING_TRAIL_MIN_DISTANCE", 50),
+
+		// Handover proof
+		HandoverProofRequired: getBoolEnv("HANDOVER_PROOF_REQUIRED", true),
+		HandoverCodeExpiry:    getDurationEnv("HANDOVER_CODE_EXPIRY", 72*time.Hour),
+
+		// Public order tracking
+		PublicTrackingRateLimit:   getIntEnv("PUBLIC_TRACKING_RATE_LIMIT", 10),
+		PublicTrackingRateWindow:  getDurationEnv("PUBLIC_TRACKING_RATE_WINDOW", time.Minute),
+		PublicTrackingMaxFailures: getIntEnv("PUBLIC_TRACKING_MAX_FAILURES", 5),
+		PublicTrackingLockout:     getDurationEnv("PUBLIC_TRACKING_LOCKOUT", 15*time.Minute),
+
+		// SLA tracking
+		SLACheckInterval:         getDurationEnv("SLA_CHECK_INTERVAL", 5*time.Minute),
+		SLADefaultPickupTarget:   getDurationEnv("SLA_DEFAULT_PICKUP_TARGET", 4*time.Hour),
+		SLADefaultRepairTarget:   getDurationEnv("SLA_DEFAULT_REPAIR_TARGET", 24*time.Hour),
+		SLADefaultDeliveryTarget: getDurationEnv("SLA_DEFAULT_DELIVERY_TARGET", 4*time.Hour),
+		SLAAtRiskPercent:         getIntEnv("SLA_AT_RISK_PERCENT", 80),
+		SLADefaultOpenTime:       getEnv("SLA_DEFAULT_OPEN_TIME", "09:00"),
+		SLADefaultCloseTime:      getEnv("SLA_DEFAULT_CLOSE_TIME", "18:00"),
+
+		// Corporate accounts
+		CorporateInvoiceInterval: getDurationEnv("CORPORATE_INVOICE_INTERVAL", time.Hour),
+		CorporatePaymentTermDays: getIntEnv("CORPORATE_PAYMENT_TERM_DAYS", 30),
+
+		// Order cancellation
+		CancellationPickupFee:     float64(getIntEnv("CANCELLATION_PICKUP_FEE", 25000)),
+		CancellationDiagnosticFee: float64(getIntEnv("CANCELLATION_DIAGNOSTIC_FEE", 75000)),
+
+		// Numbering
+		OrderNumberFormat:   getEnv("ORDER_NUMBER_FORMAT", "{branch}-{yyyy}-{seq:6}"),
+		InvoiceNumberFormat: getEnv("INVOICE_NUMBER_FORMAT", "{branch}-INV-{yyyy}-{seq:6}"),
+
+		// Idempotency
+		IdempotencyTTL:     getDurationEnv("IDEMPOTENCY_TTL", 24*time.Hour),
+		IdempotencyLockTTL: getDurationEnv("IDEMPOTENCY_LOCK_TTL", 30*time.Second),
+
 		// Observability
 		SentryDSN: getEnv("SENTRY_DSN", ""),
 	}
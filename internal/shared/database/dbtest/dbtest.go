@@ -0,0 +1,52 @@
+// Package dbtest helps repository tests check the SQL they issue without a database
+package dbtest
+
+import (
+	"service/internal/shared/database"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Mock opens a gorm connection on top of sqlmock. The connection is closed when the test ends.
+func Mock(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, mock
+}
+
+// MockGlobal installs a sqlmock connection as database.DB until the test ends, for services
+// that create their repositories themselves
+func MockGlobal(t *testing.T) sqlmock.Sqlmock {
+	t.Helper()
+	db, mock := Mock(t)
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() { database.DB = previous })
+	return mock
+}
+
+// ExpectVersionedUpdate expects a write of a row of the table that only applies at the
+// version it was read at, and lets it match the given number of rows
+func ExpectVersionedUpdate(mock sqlmock.Sqlmock, table string, matched int64) *sqlmock.ExpectedExec {
+	return mock.ExpectExec(`UPDATE "` + table + `" SET .*"version"=\$\d+.* WHERE version = \$\d+ .*"id" = \$\d+`).
+		WillReturnResult(sqlmock.NewResult(0, matched))
+}
+
+// ExpectCount expects a count of rows of the table and answers it with the given count
+func ExpectCount(mock sqlmock.Sqlmock, table string, count int64) *sqlmock.ExpectedQuery {
+	return mock.ExpectQuery(`SELECT count\(\*\) FROM "` + table + `"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+}
@@ -8,11 +8,21 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware validates JWT token and sets user context
+// AuthMiddleware validates JWT token or API key and sets user context
 func AuthMiddleware() gin.HandlerFunc {
+	apiKeyAuth := newAPIKeyAuthenticator()
 	return func(c *gin.Context) {
 		// Get Authorization header
 		authHeader := c.GetHeader("Authorization")
+
+		// Partner integrations authenticate with an API key instead of a JWT
+		if authHeader == "" {
+			if apiKey := c.GetHeader(APIKeyHeader); apiKey != "" {
+				apiKeyAuth.handle(c, apiKey)
+				return
+			}
+		}
+
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, model.CreateErrorResponse(
 				"unauthorized",
@@ -50,6 +60,9 @@ func AuthMiddleware() gin.HandlerFunc {
 		// Set user context
 		c.Set("user_id", claims.UserID)
 		c.Set("user_role", claims.Role)
+		if claims.SessionID != "" {
+			c.Set("session_id", claims.SessionID)
+		}
 		c.Next()
 	}
 }
@@ -151,6 +164,9 @@ func OptionalAuthMiddleware() gin.HandlerFunc {
 		// Set user context if token is valid
 		c.Set("user_id", claims.UserID)
 		c.Set("user_role", claims.Role)
+		if claims.SessionID != "" {
+			c.Set("session_id", claims.SessionID)
+		}
 		c.Next()
 	}
 }
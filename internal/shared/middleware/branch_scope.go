@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	userRepository "service/internal/modules/users/repository"
+	"service/internal/shared/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BranchScopeMiddleware resolves the set of branches the authenticated user may access
+// and stores it on the request context so repositories can enforce it.
+// It must run after AuthMiddleware.
+func BranchScopeMiddleware() gin.HandlerFunc {
+	userRepo := userRepository.NewUserRepository()
+	return func(c *gin.Context) {
+		// API keys get the owner's identity only past a permission guard, but the
+		// scope has to be known before that
+		userID, exists := c.Get("user_id")
+		if !exists {
+			userID, exists = c.Get("api_key_user_id")
+		}
+		if !exists {
+			c.Next()
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+				"internal_error",
+				"Invalid user ID type",
+				nil,
+			))
+			c.Abort()
+			return
+		}
+
+		role, ok := c.Get("user_role")
+		if !ok {
+			role, _ = c.Get("api_key_role")
+		}
+		userRole, _ := role.(model.UserRole)
+
+		// Only branch-bound roles need the user's home branch
+		var branchID *uuid.UUID
+		if userRole != model.RoleAdminPusat && userRole != model.RolePelanggan {
+			user, err := userRepo.GetByID(c.Request.Context(), userUUID)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, model.CreateErrorResponse(
+					"unauthorized",
+					"User not found",
+					nil,
+				))
+				c.Abort()
+				return
+			}
+			branchID = user.BranchID
+		}
+
+		scope := model.NewBranchScopeForUser(userRole, branchID)
+		if scope != nil {
+			c.Set("branch_scope", scope)
+			c.Request = c.Request.WithContext(model.WithBranchScope(c.Request.Context(), scope))
+		}
+
+		c.Next()
+	}
+}
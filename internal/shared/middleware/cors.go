@@ -14,7 +14,7 @@ func CORSMiddleware() gin.HandlerFunc {
 		// Set CORS headers
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Requested-With")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Requested-With, Idempotency-Key")
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Max-Age", "86400")
 
@@ -64,7 +64,7 @@ func CORSMiddlewareWithConfig(config CORSMiddlewareConfig) gin.HandlerFunc {
 		if config.AllowHeaders != "" {
 			c.Header("Access-Control-Allow-Headers", config.AllowHeaders)
 		} else {
-			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Requested-With")
+			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Requested-With, Idempotency-Key")
 		}
 
 		if config.AllowCredentials {
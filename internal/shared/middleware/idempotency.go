@@ -0,0 +1,272 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"service/internal/shared/config"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// IdempotencyKeyHeader is the header clients send to make a request safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyPollInterval is how often a duplicate waits for the original request to finish
+const idempotencyPollInterval = 100 * time.Millisecond
+
+// replayedHeaders are the response headers kept with a stored response
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// releaseIdempotencyLock deletes a lock only while it is still held by the same request
+var releaseIdempotencyLock = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// idempotentResponse is a response stored for replay, with the fingerprint of the request
+// that produced it
+type idempotentResponse struct {
+	Fingerprint string            `json:"fingerprint"`
+	Status      int               `json:"status"`
+	Headers     map[string]string `json:"headers"`
+	Body        []byte            `json:"body"`
+}
+
+// idempotencyWriter keeps a copy of the response body while it is written
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware makes retries of a mutating request safe. When the client sends an
+// Idempotency-Key, the response is stored and replayed for retries with the same key, so
+// the request takes effect only once. Reusing a key for a different request is rejected
+// with 422. A retry that arrives while the original is still running waits for its
+// response. Keys are scoped to the caller; requests without one are handled as usual, and
+// so are all requests when Redis is not available.
+func IdempotencyMiddleware() gin.HandlerFunc {
+	client := database.Redis
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || client == nil {
+			c.Next()
+			return
+		}
+		if len(key) > 255 {
+			c.JSON(http.StatusBadRequest, model.CreateErrorResponse(
+				"invalid_idempotency_key",
+				"Idempotency-Key must be at most 255 characters",
+				nil,
+			))
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.CreateErrorResponse("invalid_request", "Failed to read request body", nil))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ttl, lockTTL := 24*time.Hour, 30*time.Second
+		if config.Config != nil {
+			if config.Config.IdempotencyTTL > 0 {
+				ttl = config.Config.IdempotencyTTL
+			}
+			if config.Config.IdempotencyLockTTL > 0 {
+				lockTTL = config.Config.IdempotencyLockTTL
+			}
+		}
+
+		ctx := c.Request.Context()
+		scope := idempotencyScope(c, key)
+		responseKey, lockKey := "idempotency:"+scope, "idempotency_lock:"+scope
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.Path, body)
+
+		if stored, err := loadIdempotentResponse(ctx, client, responseKey); err != nil {
+			abortIdempotencyError(c)
+			return
+		} else if stored != nil {
+			replayIdempotentResponse(c, stored, fingerprint)
+			return
+		}
+
+		locked, err := client.SetNX(ctx, lockKey, fingerprint, lockTTL).Result()
+		if err != nil {
+			abortIdempotencyError(c)
+			return
+		}
+		if !locked {
+			waitForIdempotentResponse(c, client, responseKey, lockKey, fingerprint, lockTTL)
+			return
+		}
+		defer releaseIdempotencyLock.Run(context.Background(), client, []string{lockKey}, fingerprint)
+
+		// The original may have finished between the lookup and taking the lock
+		if stored, err := loadIdempotentResponse(ctx, client, responseKey); err != nil {
+			abortIdempotencyError(c)
+			return
+		} else if stored != nil {
+			replayIdempotentResponse(c, stored, fingerprint)
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// Server errors are not kept, so the request can be retried
+		if writer.Status() >= http.StatusInternalServerError {
+			return
+		}
+		stored := &idempotentResponse{
+			Fingerprint: fingerprint,
+			Status:      writer.Status(),
+			Headers:     make(map[string]string, len(replayedHeaders)),
+			Body:        writer.body.Bytes(),
+		}
+		for _, header := range replayedHeaders {
+			if value := writer.Header().Get(header); value != "" {
+				stored.Headers[header] = value
+			}
+		}
+		if data, err := json.Marshal(stored); err == nil {
+			_ = client.Set(context.Background(), responseKey, data, ttl).Err()
+		}
+	}
+}
+
+// idempotencyScope ties a key to the caller, so clients cannot replay each other's responses
+func idempotencyScope(c *gin.Context, key string) string {
+	caller := "ip:" + c.ClientIP()
+	if userID, exists := c.Get("user_id"); exists {
+		caller = fmt.Sprint(userID)
+	}
+	return caller + ":" + key
+}
+
+// requestFingerprint identifies a request by its method, path and body
+func requestFingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// loadIdempotentResponse returns the response stored under a key, or nil when there is none
+func loadIdempotentResponse(ctx context.Context, client *redis.Client, key string) (*idempotentResponse, error) {
+	data, err := client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stored idempotentResponse
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// replayIdempotentResponse writes a stored response again, unless the key was used for a
+// different request
+func replayIdempotentResponse(c *gin.Context, stored *idempotentResponse, fingerprint string) {
+	if stored.Fingerprint != fingerprint {
+		abortIdempotencyMismatch(c)
+		return
+	}
+	for header, value := range stored.Headers {
+		c.Header(header, value)
+	}
+	c.Header("Idempotent-Replayed", "true")
+	c.Status(stored.Status)
+	_, _ = c.Writer.Write(stored.Body)
+	c.Abort()
+}
+
+// waitForIdempotentResponse waits for the request holding the key to finish and replays its
+// response. When the original fails without a response to keep, or takes longer than the
+// lock lasts, the client is asked to retry.
+func waitForIdempotentResponse(c *gin.Context, client *redis.Client, responseKey, lockKey, fingerprint string, timeout time.Duration) {
+	ctx := c.Request.Context()
+	if holder, err := client.Get(ctx, lockKey).Result(); err == nil && holder != fingerprint {
+		abortIdempotencyMismatch(c)
+		return
+	}
+
+	ticker := time.NewTicker(idempotencyPollInterval)
+	defer ticker.Stop()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			c.Abort()
+			return
+		case <-ticker.C:
+		}
+
+		stored, err := loadIdempotentResponse(ctx, client, responseKey)
+		if err != nil {
+			abortIdempotencyError(c)
+			return
+		}
+		if stored != nil {
+			replayIdempotentResponse(c, stored, fingerprint)
+			return
+		}
+		if held, err := client.Exists(ctx, lockKey).Result(); err == nil && held == 0 {
+			break
+		}
+	}
+
+	c.JSON(http.StatusConflict, model.CreateErrorResponse(
+		"idempotency_in_progress",
+		"The request with this Idempotency-Key is still in progress or did not complete, retry it",
+		nil,
+	))
+	c.Abort()
+}
+
+// abortIdempotencyMismatch rejects a key reused for a different request
+func abortIdempotencyMismatch(c *gin.Context) {
+	c.JSON(http.StatusUnprocessableEntity, model.CreateErrorResponse(
+		"idempotency_key_reused",
+		"Idempotency-Key was already used for a different request",
+		nil,
+	))
+	c.Abort()
+}
+
+// abortIdempotencyError fails the request when stored responses cannot be checked
+func abortIdempotencyError(c *gin.Context) {
+	c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+		"idempotency_error",
+		"Idempotency check failed",
+		nil,
+	))
+	c.Abort()
+}
@@ -0,0 +1,228 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"service/internal/shared/database"
+	"service/internal/shared/model"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// idempotentRequest is one request sent through the idempotency middleware
+type idempotentRequest struct {
+	path   string
+	key    string
+	body   string
+	caller string // remote address, the key is scoped to it
+	fail   bool   // the handler answers with a server error
+}
+
+// idempotencyRouter serves POST /orders/* behind the idempotency middleware and counts how
+// often the handler runs. The middleware captures the Redis client when it is built.
+func idempotencyRouter(client *redis.Client, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	previous := database.Redis
+	database.Redis = client
+	defer func() { database.Redis = previous }()
+
+	r := gin.New()
+	r.POST("/orders/*action", IdempotencyMiddleware(), func(c *gin.Context) {
+		*calls++
+		if c.GetHeader("X-Fail") != "" {
+			c.JSON(http.StatusInternalServerError, gin.H{"call": *calls})
+			return
+		}
+		c.Header("ETag", `"1"`)
+		c.JSON(http.StatusCreated, gin.H{"call": *calls})
+	})
+	return r
+}
+
+// send performs a request and returns the response
+func (req idempotentRequest) send(r *gin.Engine) *httptest.ResponseRecorder {
+	path := req.path
+	if path == "" {
+		path = "/orders/create"
+	}
+	httpReq := httptest.NewRequest(http.MethodPost, path, strings.NewReader(req.body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	if req.key != "" {
+		httpReq.Header.Set(IdempotencyKeyHeader, req.key)
+	}
+	if req.caller != "" {
+		httpReq.RemoteAddr = req.caller
+	}
+	if req.fail {
+		httpReq.Header.Set("X-Fail", "true")
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httpReq)
+	return w
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	base := requestFingerprint(http.MethodPost, "/orders", []byte(`{"a":1}`))
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		body      string
+		wantEqual bool
+	}{
+		{"same request", http.MethodPost, "/orders", `{"a":1}`, true},
+		{"other method", http.MethodPut, "/orders", `{"a":1}`, false},
+		{"other path", http.MethodPost, "/payments", `{"a":1}`, false},
+		{"other body", http.MethodPost, "/orders", `{"a":2}`, false},
+		{"body whitespace counts", http.MethodPost, "/orders", `{"a": 1}`, false},
+		{"empty body", http.MethodPost, "/orders", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := requestFingerprint(tt.method, tt.path, []byte(tt.body))
+			assert.Len(t, got, 64)
+			assert.Equal(t, tt.wantEqual, got == base)
+		})
+	}
+
+	// The path and the body cannot run into each other
+	assert.NotEqual(t,
+		requestFingerprint(http.MethodPost, "/orders", []byte("x")),
+		requestFingerprint(http.MethodPost, "/ordersx", nil),
+	)
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	longKey := strings.Repeat("k", 256)
+	tests := []struct {
+		name         string
+		noRedis      bool
+		requests     []idempotentRequest
+		wantStatuses []int
+		wantReplayed []bool
+		wantErrors   []string
+		wantCalls    int
+	}{
+		{
+			name:         "no key runs every request",
+			requests:     []idempotentRequest{{body: `{"a":1}`}, {body: `{"a":1}`}},
+			wantStatuses: []int{http.StatusCreated, http.StatusCreated},
+			wantReplayed: []bool{false, false},
+			wantErrors:   []string{"", ""},
+			wantCalls:    2,
+		},
+		{
+			name:         "retry is replayed",
+			requests:     []idempotentRequest{{key: "k1", body: `{"a":1}`}, {key: "k1", body: `{"a":1}`}},
+			wantStatuses: []int{http.StatusCreated, http.StatusCreated},
+			wantReplayed: []bool{false, true},
+			wantErrors:   []string{"", ""},
+			wantCalls:    1,
+		},
+		{
+			name:         "key reused with another body",
+			requests:     []idempotentRequest{{key: "k1", body: `{"a":1}`}, {key: "k1", body: `{"a":2}`}},
+			wantStatuses: []int{http.StatusCreated, http.StatusUnprocessableEntity},
+			wantReplayed: []bool{false, false},
+			wantErrors:   []string{"", "idempotency_key_reused"},
+			wantCalls:    1,
+		},
+		{
+			name: "key reused on another path",
+			requests: []idempotentRequest{
+				{key: "k1", body: `{"a":1}`},
+				{key: "k1", body: `{"a":1}`, path: "/orders/cancel"},
+			},
+			wantStatuses: []int{http.StatusCreated, http.StatusUnprocessableEntity},
+			wantReplayed: []bool{false, false},
+			wantErrors:   []string{"", "idempotency_key_reused"},
+			wantCalls:    1,
+		},
+		{
+			name:         "different keys run separately",
+			requests:     []idempotentRequest{{key: "k1", body: `{"a":1}`}, {key: "k2", body: `{"a":1}`}},
+			wantStatuses: []int{http.StatusCreated, http.StatusCreated},
+			wantReplayed: []bool{false, false},
+			wantErrors:   []string{"", ""},
+			wantCalls:    2,
+		},
+		{
+			name: "keys are scoped to the caller",
+			requests: []idempotentRequest{
+				{key: "k1", body: `{"a":1}`, caller: "10.0.0.1:1234"},
+				{key: "k1", body: `{"a":2}`, caller: "10.0.0.2:1234"},
+			},
+			wantStatuses: []int{http.StatusCreated, http.StatusCreated},
+			wantReplayed: []bool{false, false},
+			wantErrors:   []string{"", ""},
+			wantCalls:    2,
+		},
+		{
+			name:         "server errors are not kept",
+			requests:     []idempotentRequest{{key: "k1", body: `{"a":1}`, fail: true}, {key: "k1", body: `{"a":1}`}},
+			wantStatuses: []int{http.StatusInternalServerError, http.StatusCreated},
+			wantReplayed: []bool{false, false},
+			wantErrors:   []string{"", ""},
+			wantCalls:    2,
+		},
+		{
+			name:         "key too long",
+			requests:     []idempotentRequest{{key: longKey, body: `{"a":1}`}},
+			wantStatuses: []int{http.StatusBadRequest},
+			wantReplayed: []bool{false},
+			wantErrors:   []string{"invalid_idempotency_key"},
+			wantCalls:    0,
+		},
+		{
+			name:         "without Redis every request runs",
+			noRedis:      true,
+			requests:     []idempotentRequest{{key: "k1", body: `{"a":1}`}, {key: "k1", body: `{"a":2}`}},
+			wantStatuses: []int{http.StatusCreated, http.StatusCreated},
+			wantReplayed: []bool{false, false},
+			wantErrors:   []string{"", ""},
+			wantCalls:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var client *redis.Client
+			if !tt.noRedis {
+				server := miniredis.RunT(t)
+				client = redis.NewClient(&redis.Options{Addr: server.Addr()})
+				defer client.Close()
+			}
+			calls := 0
+			r := idempotencyRouter(client, &calls)
+
+			var first *httptest.ResponseRecorder
+			for i, req := range tt.requests {
+				w := req.send(r)
+				assert.Equal(t, tt.wantStatuses[i], w.Code, "request %d", i)
+				assert.Equal(t, tt.wantReplayed[i], w.Header().Get("Idempotent-Replayed") == "true", "request %d", i)
+
+				if tt.wantErrors[i] != "" {
+					var resp model.ErrorResponse
+					assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+					assert.Equal(t, tt.wantErrors[i], resp.Error, "request %d", i)
+				}
+				if tt.wantReplayed[i] {
+					assert.Equal(t, first.Body.String(), w.Body.String(), "request %d", i)
+					assert.Equal(t, first.Header().Get("ETag"), w.Header().Get("ETag"), "request %d", i)
+					assert.Equal(t, first.Header().Get("Content-Type"), w.Header().Get("Content-Type"), "request %d", i)
+				}
+				if first == nil {
+					first = w
+				}
+			}
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
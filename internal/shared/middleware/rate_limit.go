@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"service/internal/shared/config"
 	"service/internal/shared/database"
 	"service/internal/shared/model"
 	"time"
@@ -213,3 +214,49 @@ func APIKeyRateLimitMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// PublicTrackingRateLimitMiddleware limits public order tracking per client IP, so that
+// order numbers and phone digits cannot be enumerated
+func PublicTrackingRateLimitMiddleware() gin.HandlerFunc {
+	limiter := NewRateLimiter()
+	return func(c *gin.Context) {
+		if limiter.redis == nil {
+			c.Next()
+			return
+		}
+
+		requests := 10
+		window := time.Minute
+		if config.Config != nil {
+			if config.Config.PublicTrackingRateLimit > 0 {
+				requests = config.Config.PublicTrackingRateLimit
+			}
+			if config.Config.PublicTrackingRateWindow > 0 {
+				window = config.Config.PublicTrackingRateWindow
+			}
+		}
+
+		allowed, err := limiter.IsAllowed("public_tracking:"+c.ClientIP(), requests, window)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, model.CreateErrorResponse(
+				"rate_limit_error",
+				"Rate limit check failed",
+				nil,
+			))
+			c.Abort()
+			return
+		}
+
+		if !allowed {
+			c.JSON(http.StatusTooManyRequests, model.CreateErrorResponse(
+				"rate_limit_exceeded",
+				"Too many requests",
+				nil,
+			))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
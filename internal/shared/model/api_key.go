@@ -0,0 +1,150 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKeyPrefixLength is the number of leading characters of a key kept in clear text for display
+const APIKeyPrefixLength = 12
+
+// DefaultAPIKeyRateLimit is the number of requests per minute allowed when no limit is given
+const DefaultAPIKeyRateLimit = 60
+
+// APIKey represents a credential used by partner systems for machine-to-machine access.
+// Only the SHA-256 hash of the key is stored; the key acts on behalf of its owning user
+// and is further restricted to its scopes.
+type APIKey struct {
+	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Name       string         `json:"name" gorm:"not null"`
+	Prefix     string         `json:"prefix" gorm:"type:varchar(20);not null;index"`
+	KeyHash    string         `json:"-" gorm:"type:varchar(64);uniqueIndex;not null"`
+	UserID     uuid.UUID      `json:"user_id" gorm:"type:uuid;not null"`
+	User       User           `json:"-" gorm:"foreignKey:UserID"`
+	Scopes     string         `json:"-" gorm:"type:text;not null"`           // comma separated permissions
+	RateLimit  int            `json:"rate_limit" gorm:"not null;default:60"` // requests per minute
+	ExpiresAt  *time.Time     `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time     `json:"last_used_at,omitempty"`
+	LastUsedIP string         `json:"last_used_ip,omitempty" gorm:"type:varchar(45)"`
+	RevokedAt  *time.Time     `json:"revoked_at,omitempty"`
+	CreatedBy  uuid.UUID      `json:"created_by" gorm:"type:uuid;not null"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName returns the table name for APIKey
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// ScopeList returns the permissions granted to the key
+func (k *APIKey) ScopeList() []Permission {
+	scopes := make([]Permission, 0)
+	for _, s := range strings.Split(k.Scopes, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			scopes = append(scopes, Permission(s))
+		}
+	}
+	return scopes
+}
+
+// SetScopes stores the given permissions as the key's scopes
+func (k *APIKey) SetScopes(scopes []Permission) {
+	values := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		values = append(values, string(s))
+	}
+	k.Scopes = strings.Join(values, ",")
+}
+
+// HasScopes reports whether the key grants all of the given permissions
+func (k *APIKey) HasScopes(permissions ...Permission) bool {
+	return ContainsPermissions(k.ScopeList(), permissions...)
+}
+
+// ContainsPermissions reports whether every required permission is present in granted
+func ContainsPermissions(granted []Permission, required ...Permission) bool {
+	set := make(map[Permission]bool, len(granted))
+	for _, p := range granted {
+		set[p] = true
+	}
+	for _, p := range required {
+		if !set[p] {
+			return false
+		}
+	}
+	return true
+}
+
+// IsActive reports whether the key is neither revoked nor expired at the given time
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// APIKeyRequest represents the request payload for creating an API key
+type APIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,min=3,max=100"`
+	UserID    string     `json:"user_id" validate:"required,uuid"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1"`
+	RateLimit int        `json:"rate_limit,omitempty" validate:"omitempty,min=1,max=10000"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// UpdateAPIKeyRequest represents the request payload for updating an API key
+type UpdateAPIKeyRequest struct {
+	Name      string     `json:"name,omitempty" validate:"omitempty,min=3,max=100"`
+	Scopes    []string   `json:"scopes,omitempty"`
+	RateLimit int        `json:"rate_limit,omitempty" validate:"omitempty,min=1,max=10000"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// APIKeyResponse represents the response payload for API key data
+type APIKeyResponse struct {
+	ID         uuid.UUID    `json:"id"`
+	Name       string       `json:"name"`
+	Prefix     string       `json:"prefix"`
+	UserID     uuid.UUID    `json:"user_id"`
+	Scopes     []Permission `json:"scopes"`
+	RateLimit  int          `json:"rate_limit"`
+	ExpiresAt  *time.Time   `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time   `json:"last_used_at,omitempty"`
+	LastUsedIP string       `json:"last_used_ip,omitempty"`
+	RevokedAt  *time.Time   `json:"revoked_at,omitempty"`
+	Active     bool         `json:"active"`
+	CreatedBy  uuid.UUID    `json:"created_by"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+// APIKeyCreatedResponse includes the plain text key, which is only returned once at creation
+type APIKeyCreatedResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// ToResponse converts APIKey to APIKeyResponse
+func (k *APIKey) ToResponse() APIKeyResponse {
+	return APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		UserID:     k.UserID,
+		Scopes:     k.ScopeList(),
+		RateLimit:  k.RateLimit,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		LastUsedIP: k.LastUsedIP,
+		RevokedAt:  k.RevokedAt,
+		Active:     k.IsActive(time.Now()),
+		CreatedBy:  k.CreatedBy,
+		CreatedAt:  k.CreatedAt,
+		UpdatedAt:  k.UpdatedAt,
+	}
+}
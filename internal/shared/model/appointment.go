@@ -0,0 +1,188 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AppointmentStatus represents the state of an appointment
+type AppointmentStatus string
+
+const (
+	AppointmentStatusBooked    AppointmentStatus = "booked"
+	AppointmentStatusCheckedIn AppointmentStatus = "checked_in"
+	AppointmentStatusCancelled AppointmentStatus = "cancelled"
+)
+
+// AppointmentSlotTemplate is a weekly opening of a branch for walk-in appointments,
+// e.g. every Monday 09:00-10:00 for 3 customers. Times are in the configured timezone.
+type AppointmentSlotTemplate struct {
+	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	BranchID  uuid.UUID      `json:"branch_id" gorm:"type:uuid;not null;index"`
+	Weekday   int            `json:"weekday" gorm:"not null"`                    // 0 = Sunday
+	StartTime string         `json:"start_time" gorm:"type:varchar(5);not null"` // HH:MM
+	EndTime   string         `json:"end_time" gorm:"type:varchar(5);not null"`   // HH:MM
+	Capacity  int            `json:"capacity" gorm:"not null"`
+	IsActive  bool           `json:"is_active" gorm:"default:true"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName returns the table name for AppointmentSlotTemplate
+func (AppointmentSlotTemplate) TableName() string {
+	return "appointment_slot_templates"
+}
+
+// AppointmentSlotTemplateRequest represents the request payload for creating or updating a slot template
+type AppointmentSlotTemplateRequest struct {
+	Weekday   *int   `json:"weekday" validate:"required,min=0,max=6"`
+	StartTime string `json:"start_time" validate:"required,datetime=15:04"`
+	EndTime   string `json:"end_time" validate:"required,datetime=15:04"`
+	Capacity  int    `json:"capacity" validate:"required,min=1,max=100"`
+	IsActive  *bool  `json:"is_active,omitempty"`
+}
+
+// AppointmentSlot is a dated occurrence of a slot template. Slots are created from the
+// templates when a date is first looked at; the check constraint on Booked keeps the
+// number of bookings within capacity even under concurrent bookings.
+type AppointmentSlot struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	BranchID   uuid.UUID  `json:"branch_id" gorm:"type:uuid;not null;uniqueIndex:idx_appointment_slots_branch_start"`
+	TemplateID *uuid.UUID `json:"template_id,omitempty" gorm:"type:uuid;index"`
+	StartsAt   time.Time  `json:"starts_at" gorm:"not null;uniqueIndex:idx_appointment_slots_branch_start"`
+	EndsAt     time.Time  `json:"ends_at" gorm:"not null"`
+	Capacity   int        `json:"capacity" gorm:"not null"`
+	Booked     int        `json:"booked" gorm:"not null;default:0;check:chk_appointment_slots_booked,booked >= 0 AND booked <= capacity"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName returns the table name for AppointmentSlot
+func (AppointmentSlot) TableName() string {
+	return "appointment_slots"
+}
+
+// AppointmentSlotResponse represents a bookable slot in API responses
+type AppointmentSlotResponse struct {
+	ID        uuid.UUID `json:"id"`
+	BranchID  uuid.UUID `json:"branch_id"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Capacity  int       `json:"capacity"`
+	Available int       `json:"available"`
+}
+
+// ToResponse converts AppointmentSlot to AppointmentSlotResponse
+func (s *AppointmentSlot) ToResponse() AppointmentSlotResponse {
+	available := s.Capacity - s.Booked
+	if available < 0 {
+		available = 0
+	}
+	return AppointmentSlotResponse{
+		ID:        s.ID,
+		BranchID:  s.BranchID,
+		StartsAt:  s.StartsAt,
+		EndsAt:    s.EndsAt,
+		Capacity:  s.Capacity,
+		Available: available,
+	}
+}
+
+// Appointment is a customer's booking of a slot at a branch. At check-in it is
+// converted into a service order.
+type Appointment struct {
+	ID             uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	CustomerID     uuid.UUID         `json:"customer_id" gorm:"type:uuid;not null;index"`
+	BranchID       uuid.UUID         `json:"branch_id" gorm:"type:uuid;not null;index"`
+	SlotID         uuid.UUID         `json:"slot_id" gorm:"type:uuid;not null;index"`
+	Slot           AppointmentSlot   `json:"slot" gorm:"foreignKey:SlotID"`
+	ServiceType    ServiceType       `json:"service_type" gorm:"not null"`
+	DeviceID       *uuid.UUID        `json:"device_id,omitempty" gorm:"type:uuid"`
+	Description    string            `json:"description" gorm:"type:text"`
+	Status         AppointmentStatus `json:"status" gorm:"type:varchar(20);not null;default:'booked';index"`
+	OrderID        *uuid.UUID        `json:"order_id,omitempty" gorm:"type:uuid"`
+	CancelReason   string            `json:"cancel_reason,omitempty"`
+	ReminderSentAt *time.Time        `json:"reminder_sent_at,omitempty"`
+	CheckedInAt    *time.Time        `json:"checked_in_at,omitempty"`
+	CancelledAt    *time.Time        `json:"cancelled_at,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// TableName returns the table name for Appointment
+func (Appointment) TableName() string {
+	return "appointments"
+}
+
+// AppointmentRequest represents the request payload for booking an appointment
+type AppointmentRequest struct {
+	SlotID      string      `json:"slot_id" validate:"required,uuid"`
+	ServiceType ServiceType `json:"service_type" validate:"required,oneof=screen_repair battery_replacement water_damage software_issue hardware_repair other"`
+	DeviceID    string      `json:"device_id,omitempty" validate:"omitempty,uuid"`
+	Description string      `json:"description" validate:"required,max=1000"`
+}
+
+// RescheduleAppointmentRequest represents the request payload for moving an appointment to another slot
+type RescheduleAppointmentRequest struct {
+	SlotID string `json:"slot_id" validate:"required,uuid"`
+}
+
+// CancelAppointmentRequest represents the request payload for cancelling an appointment
+type CancelAppointmentRequest struct {
+	Reason string `json:"reason,omitempty" validate:"omitempty,max=500"`
+}
+
+// CheckInAppointmentRequest represents the device details taken at the counter.
+// They are only needed when the appointment was booked without a registered device.
+type CheckInAppointmentRequest struct {
+	DeviceID    string `json:"device_id,omitempty" validate:"omitempty,uuid"`
+	IPhoneModel string `json:"iphone_model,omitempty"`
+	IPhoneColor string `json:"iphone_color,omitempty"`
+	IPhoneIMEI  string `json:"iphone_imei,omitempty"`
+	Notes       string `json:"notes,omitempty" validate:"omitempty,max=1000"`
+}
+
+// AppointmentResponse represents an appointment in API responses
+type AppointmentResponse struct {
+	ID           uuid.UUID         `json:"id"`
+	CustomerID   uuid.UUID         `json:"customer_id"`
+	BranchID     uuid.UUID         `json:"branch_id"`
+	SlotID       uuid.UUID         `json:"slot_id"`
+	StartsAt     time.Time         `json:"starts_at"`
+	EndsAt       time.Time         `json:"ends_at"`
+	ServiceType  ServiceType       `json:"service_type"`
+	DeviceID     *uuid.UUID        `json:"device_id,omitempty"`
+	Description  string            `json:"description"`
+	Status       AppointmentStatus `json:"status"`
+	OrderID      *uuid.UUID        `json:"order_id,omitempty"`
+	CancelReason string            `json:"cancel_reason,omitempty"`
+	CheckedInAt  *time.Time        `json:"checked_in_at,omitempty"`
+	CancelledAt  *time.Time        `json:"cancelled_at,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+// ToResponse converts Appointment to AppointmentResponse
+func (a *Appointment) ToResponse() AppointmentResponse {
+	return AppointmentResponse{
+		ID:           a.ID,
+		CustomerID:   a.CustomerID,
+		BranchID:     a.BranchID,
+		SlotID:       a.SlotID,
+		StartsAt:     a.Slot.StartsAt,
+		EndsAt:       a.Slot.EndsAt,
+		ServiceType:  a.ServiceType,
+		DeviceID:     a.DeviceID,
+		Description:  a.Description,
+		Status:       a.Status,
+		OrderID:      a.OrderID,
+		CancelReason: a.CancelReason,
+		CheckedInAt:  a.CheckedInAt,
+		CancelledAt:  a.CancelledAt,
+		CreatedAt:    a.CreatedAt,
+		UpdatedAt:    a.UpdatedAt,
+	}
+}
@@ -27,6 +27,7 @@ type AuditTrail struct {
 	ID           uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
 	UserID       *uuid.UUID     `json:"user_id,omitempty" gorm:"type:uuid"`
 	User         *User          `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	APIKeyID     *uuid.UUID     `json:"api_key_id,omitempty" gorm:"type:uuid"` // set when the action was made with an API key
 	Action       AuditAction    `json:"action" gorm:"not null"`
 	Resource     string         `json:"resource" gorm:"not null"`               // e.g., "order", "payment", "user"
 	ResourceID   *uuid.UUID     `json:"resource_id,omitempty" gorm:"type:uuid"` // ID of the affected resource
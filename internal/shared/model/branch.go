@@ -1,6 +1,7 @@
 package model
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,6 +12,7 @@ import (
 type Branch struct {
 	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
 	Name      string         `json:"name" gorm:"not null"`
+	Code      string         `json:"code" gorm:"type:varchar(10)"` // printed in order and invoice numbers
 	Address   string         `json:"address" gorm:"not null"`
 	City      string         `json:"city" gorm:"not null"`
 	Province  string         `json:"province" gorm:"not null"`
@@ -31,6 +33,7 @@ func (Branch) TableName() string {
 // BranchRequest represents the request payload for creating/updating a branch
 type BranchRequest struct {
 	Name      string  `json:"name" validate:"required"`
+	Code      string  `json:"code" validate:"omitempty,alphanum,max=10"`
 	Address   string  `json:"address" validate:"required"`
 	City      string  `json:"city" validate:"required"`
 	Province  string  `json:"province" validate:"required"`
@@ -43,6 +46,7 @@ type BranchRequest struct {
 type BranchResponse struct {
 	ID        uuid.UUID `json:"id"`
 	Name      string    `json:"name"`
+	Code      string    `json:"code"`
 	Address   string    `json:"address"`
 	City      string    `json:"city"`
 	Province  string    `json:"province"`
@@ -59,6 +63,7 @@ func (b *Branch) ToResponse() BranchResponse {
 	return BranchResponse{
 		ID:        b.ID,
 		Name:      b.Name,
+		Code:      b.Code,
 		Address:   b.Address,
 		City:      b.City,
 		Province:  b.Province,
@@ -71,6 +76,15 @@ func (b *Branch) ToResponse() BranchResponse {
 	}
 }
 
+// NumberingCode returns the code printed in the order and invoice numbers of the branch.
+// Branches without a code use the start of their ID.
+func (b *Branch) NumberingCode() string {
+	if b.Code != "" {
+		return strings.ToUpper(b.Code)
+	}
+	return strings.ToUpper(b.ID.String()[:6])
+}
+
 // BranchDistance represents a branch with distance information
 type BranchDistance struct {
 	Branch   BranchResponse `json:"branch"`
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// branchScopeContextKey is the context key under which the branch scope is stored
+type branchScopeContextKey struct{}
+
+// BranchScope describes the set of branches whose data the current caller may access
+type BranchScope struct {
+	All       bool        `json:"all"`
+	BranchIDs []uuid.UUID `json:"branch_ids,omitempty"`
+}
+
+// NewBranchScopeForUser builds the branch scope for a user based on role and home branch.
+// admin_pusat sees all branches, customers are not branch-scoped (nil), and every other
+// role is limited to its own branch.
+func NewBranchScopeForUser(role UserRole, branchID *uuid.UUID) *BranchScope {
+	switch role {
+	case RoleAdminPusat:
+		return &BranchScope{All: true}
+	case RolePelanggan:
+		return nil
+	}
+
+	scope := &BranchScope{}
+	if branchID != nil {
+		scope.BranchIDs = []uuid.UUID{*branchID}
+	}
+	return scope
+}
+
+// IsRestricted reports whether the scope limits access to specific branches
+func (s *BranchScope) IsRestricted() bool {
+	return s != nil && !s.All
+}
+
+// Allows reports whether the given branch is inside the scope
+func (s *BranchScope) Allows(branchID uuid.UUID) bool {
+	if !s.IsRestricted() {
+		return true
+	}
+	for _, id := range s.BranchIDs {
+		if id == branchID {
+			return true
+		}
+	}
+	return false
+}
+
+// WithBranchScope returns a copy of ctx carrying the branch scope
+func WithBranchScope(ctx context.Context, scope *BranchScope) context.Context {
+	return context.WithValue(ctx, branchScopeContextKey{}, scope)
+}
+
+// BranchScopeFromContext returns the branch scope stored in ctx, or nil when the caller is unscoped
+func BranchScopeFromContext(ctx context.Context) *BranchScope {
+	if ctx == nil {
+		return nil
+	}
+	scope, _ := ctx.Value(branchScopeContextKey{}).(*BranchScope)
+	return scope
+}
+
+// ScopeByBranch returns a GORM scope restricting column to the branches allowed by the context
+func ScopeByBranch(ctx context.Context, column string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		scope := BranchScopeFromContext(ctx)
+		if !scope.IsRestricted() {
+			return db
+		}
+		if len(scope.BranchIDs) == 0 {
+			return db.Where("1 = 0")
+		}
+		return db.Where(column+" IN ?", scope.BranchIDs)
+	}
+}
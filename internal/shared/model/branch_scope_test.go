@@ -0,0 +1,90 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestBranchScopeAllows(t *testing.T) {
+	home, other := uuid.New(), uuid.New()
+	tests := []struct {
+		name   string
+		scope  *BranchScope
+		branch uuid.UUID
+		want   bool
+	}{
+		{"unscoped", nil, other, true},
+		{"all branches", &BranchScope{All: true}, other, true},
+		{"own branch", &BranchScope{BranchIDs: []uuid.UUID{home}}, home, true},
+		{"other branch", &BranchScope{BranchIDs: []uuid.UUID{home}}, other, false},
+		{"one of several", &BranchScope{BranchIDs: []uuid.UUID{other, home}}, home, true},
+		{"no branches", &BranchScope{}, home, false},
+		{"order without branch", &BranchScope{BranchIDs: []uuid.UUID{home}}, uuid.Nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.scope.Allows(tt.branch))
+		})
+	}
+}
+
+func TestNewBranchScopeForUser(t *testing.T) {
+	home := uuid.New()
+	tests := []struct {
+		name     string
+		role     UserRole
+		branchID *uuid.UUID
+		want     *BranchScope
+	}{
+		{"admin_pusat sees all branches", RoleAdminPusat, &home, &BranchScope{All: true}},
+		{"customer is unscoped", RolePelanggan, nil, nil},
+		{"staff see their branch", RoleKasir, &home, &BranchScope{BranchIDs: []uuid.UUID{home}}},
+		{"staff without branch see none", RoleTeknisi, nil, &BranchScope{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewBranchScopeForUser(tt.role, tt.branchID))
+		})
+	}
+}
+
+func TestScopeByBranch(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	home := uuid.MustParse("7c535786-335b-442a-b3e9-25fe3fed1599")
+	tests := []struct {
+		name  string
+		scope *BranchScope
+		want  string
+	}{
+		{"unscoped", nil, `SELECT * FROM "service_orders" WHERE "service_orders"."deleted_at" IS NULL`},
+		{"all branches", &BranchScope{All: true}, `SELECT * FROM "service_orders" WHERE "service_orders"."deleted_at" IS NULL`},
+		{"own branch", &BranchScope{BranchIDs: []uuid.UUID{home}}, `SELECT * FROM "service_orders" WHERE branch_id IN ('7c535786-335b-442a-b3e9-25fe3fed1599') AND "service_orders"."deleted_at" IS NULL`},
+		{"no branches", &BranchScope{}, `SELECT * FROM "service_orders" WHERE 1 = 0 AND "service_orders"."deleted_at" IS NULL`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithBranchScope(context.Background(), tt.scope)
+			query := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				var orders []ServiceOrder
+				return tx.Scopes(ScopeByBranch(ctx, "branch_id")).Find(&orders)
+			})
+			assert.Equal(t, tt.want, query)
+		})
+	}
+}
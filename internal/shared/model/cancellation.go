@@ -0,0 +1,123 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CancellationReason is the mandatory reason code given when an order is cancelled
+type CancellationReason string
+
+const (
+	CancellationCustomerRequest     CancellationReason = "customer_request"
+	CancellationCustomerUnreachable CancellationReason = "customer_unreachable"
+	CancellationNotRepairable       CancellationReason = "device_not_repairable"
+	CancellationPartsUnavailable    CancellationReason = "parts_unavailable"
+	CancellationPriceRejected       CancellationReason = "price_rejected"
+	CancellationDuplicateOrder      CancellationReason = "duplicate_order"
+	CancellationOther               CancellationReason = "other" // requires a note
+)
+
+// IsCancellable reports whether an order in the given status can still be cancelled.
+// Once the device is back with the customer the order can only be completed.
+func IsCancellable(status OrderStatus) bool {
+	switch status {
+	case StatusPendingPickup, StatusOnPickup, StatusInService, StatusReady:
+		return true
+	}
+	return false
+}
+
+// CancellationFeeRule overrides the configured cancellation fee for orders cancelled
+// in a given status
+type CancellationFeeRule struct {
+	ID        uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Status    OrderStatus `json:"status" gorm:"type:varchar(30);uniqueIndex;not null"`
+	Fee       float64     `json:"fee" gorm:"not null"` // in Rupiah
+	UpdatedBy uuid.UUID   `json:"updated_by" gorm:"type:uuid"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// TableName returns the table name for CancellationFeeRule
+func (CancellationFeeRule) TableName() string {
+	return "cancellation_fee_rules"
+}
+
+// CancellationFeeRequest represents the fee for one status in a fee schedule update
+type CancellationFeeRequest struct {
+	Status OrderStatus `json:"status" validate:"required,oneof=pending_pickup on_pickup in_service ready"`
+	Fee    float64     `json:"fee" validate:"gte=0"`
+}
+
+// CancellationFeeScheduleRequest represents the request payload for updating the fee schedule
+type CancellationFeeScheduleRequest struct {
+	Fees []CancellationFeeRequest `json:"fees" validate:"required,min=1,max=4,dive"`
+}
+
+// CancellationFeeScheduleEntry is the fee charged when an order is cancelled in a status
+type CancellationFeeScheduleEntry struct {
+	Status     OrderStatus `json:"status"`
+	Fee        float64     `json:"fee"`
+	IsOverride bool        `json:"is_override"` // false when the configured default applies
+}
+
+// OrderCancellation records why, when and at what cost an order was cancelled
+type OrderCancellation struct {
+	ID              uuid.UUID          `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderID         uuid.UUID          `json:"order_id" gorm:"type:uuid;not null;uniqueIndex"`
+	BranchID        uuid.UUID          `json:"branch_id" gorm:"type:uuid;not null;index"`
+	ReasonCode      CancellationReason `json:"reason_code" gorm:"type:varchar(30);not null;index"`
+	ReasonNote      string             `json:"reason_note,omitempty"`
+	StatusAtCancel  OrderStatus        `json:"status_at_cancel" gorm:"type:varchar(30);not null"`
+	Fee             float64            `json:"fee" gorm:"not null;default:0"`
+	FeeWaived       bool               `json:"fee_waived" gorm:"default:false"`         // staff waived the scheduled fee
+	PaidAmount      float64            `json:"paid_amount" gorm:"not null;default:0"`   // paid by the customer before cancelling
+	RefundAmount    float64            `json:"refund_amount" gorm:"not null;default:0"` // paid minus the fee, to be returned
+	ChargePaymentID *uuid.UUID         `json:"charge_payment_id,omitempty" gorm:"type:uuid"`
+	PartsReleased   int                `json:"parts_released" gorm:"not null;default:0"`
+	CancelledBy     uuid.UUID          `json:"cancelled_by" gorm:"type:uuid;not null"`
+	CreatedAt       time.Time          `json:"created_at" gorm:"index"`
+}
+
+// TableName returns the table name for OrderCancellation
+func (OrderCancellation) TableName() string {
+	return "order_cancellations"
+}
+
+// CancelOrderRequest represents the request payload for cancelling an order
+type CancelOrderRequest struct {
+	ReasonCode CancellationReason `json:"reason_code" validate:"required,oneof=customer_request customer_unreachable device_not_repairable parts_unavailable price_rejected duplicate_order other"`
+	Note       string             `json:"note,omitempty" validate:"max=500"`
+	WaiveFee   bool               `json:"waive_fee"` // needs the order.waive_fee permission
+}
+
+// CancellationGrants are the rights of the caller that decide what a cancellation may do
+// beyond cancelling the order
+type CancellationGrants struct {
+	UpdateStatus bool // staff may change the status of orders
+	WaiveFee     bool // the cancellation fee may be waived
+	Refund       bool // payments beyond the fee may be refunded
+}
+
+// CancelOrderResponse represents a cancelled order together with its settlement
+type CancelOrderResponse struct {
+	Order        ServiceOrderResponse `json:"order"`
+	Cancellation OrderCancellation    `json:"cancellation"`
+}
+
+// CancellationReport summarizes the cancellations of a period
+type CancellationReport struct {
+	StartDate     time.Time                    `json:"start_date"`
+	EndDate       time.Time                    `json:"end_date"`
+	TotalOrders   int64                        `json:"total_orders"`
+	Cancelled     int64                        `json:"cancelled"`
+	CancelRate    float64                      `json:"cancel_rate"` // percentage of orders created in the period
+	FeesCharged   float64                      `json:"fees_charged"`
+	FeesWaived    int64                        `json:"fees_waived"`
+	RefundAmount  float64                      `json:"refund_amount"`
+	PartsReleased int64                        `json:"parts_released"`
+	ByReason      map[CancellationReason]int64 `json:"by_reason"`
+	ByStage       map[OrderStatus]int64        `json:"by_stage"`
+}
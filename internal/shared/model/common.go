@@ -45,6 +45,7 @@ type PaginatedResponse struct {
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
+	MFACode  string `json:"mfa_code,omitempty"`
 }
 
 // LoginResponse represents the login response payload
@@ -102,6 +103,7 @@ var (
 	ErrInvalidPassword   = errors.New("invalid password")
 	ErrOrderNotFound     = errors.New("order not found")
 	ErrBranchNotFound    = errors.New("branch not found")
+	ErrBranchCodeExists  = errors.New("branch code already exists")
 	ErrPaymentNotFound   = errors.New("payment not found")
 	ErrInvalidToken      = errors.New("invalid token")
 	ErrTokenExpired      = errors.New("token expired")
@@ -109,6 +111,182 @@ var (
 	ErrPhoneExists       = errors.New("phone number already exists")
 	ErrOrderNumberExists = errors.New("order number already exists")
 	ErrInvoiceExists     = errors.New("invoice number already exists")
+	ErrRoleNotFound      = errors.New("role not found")
+	ErrRoleExists        = errors.New("role already exists")
+	ErrSystemRole        = errors.New("system roles cannot be deleted")
+	ErrInvalidPermission = errors.New("invalid permission")
+	ErrAPIKeyNotFound    = errors.New("api key not found")
+	ErrInvalidAPIKey     = errors.New("invalid or expired api key")
+)
+
+// Staff onboarding errors
+var (
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrInvitationInvalid  = errors.New("invitation is invalid or has expired")
+	ErrMFARequired        = errors.New("mfa code is required")
+	ErrInvalidMFACode     = errors.New("invalid mfa code")
+	ErrStaffRegistration  = errors.New("staff accounts must be created through an invitation")
+)
+
+// Contact verification errors
+var (
+	ErrContactNotVerified      = errors.New("email and phone number must be verified first")
+	ErrAlreadyVerified         = errors.New("contact is already verified")
+	ErrVerificationThrottled   = errors.New("please wait before requesting another verification")
+	ErrInvalidVerificationCode = errors.New("invalid or expired verification code")
+)
+
+// Data subject request errors
+var (
+	ErrDataRequestNotFound = errors.New("data subject request not found")
+	ErrDataRequestExists   = errors.New("a request of this type is already in progress")
+	ErrDataRequestState    = errors.New("data subject request cannot be changed in its current state")
+	ErrActiveOrdersExist   = errors.New("personal data cannot be erased while orders are still in progress")
+	ErrExportNotReady      = errors.New("export archive is not available")
+)
+
+// Push device errors
+var (
+	ErrDeviceNotFound = errors.New("device not found")
+)
+
+// Customer device registry errors
+var (
+	ErrCustomerDeviceNotFound = errors.New("customer device not found")
+	ErrCustomerDeviceExists   = errors.New("a device with this IMEI is already registered")
+	ErrDeviceModelNotFound    = errors.New("device model not found")
+	ErrDeviceModelExists      = errors.New("device model already exists")
+	ErrSparePartNotFound      = errors.New("spare part not found")
+	ErrInsufficientStock      = errors.New("insufficient spare part stock")
+)
+
+// Appointment errors
+var (
+	ErrAppointmentNotFound     = errors.New("appointment not found")
+	ErrAppointmentNotBooked    = errors.New("appointment is no longer booked")
+	ErrAppointmentExists       = errors.New("you already have an appointment in this slot")
+	ErrSlotTemplateNotFound    = errors.New("slot template not found")
+	ErrSlotNotFound            = errors.New("appointment slot not found")
+	ErrSlotFull                = errors.New("appointment slot is fully booked")
+	ErrSlotUnavailable         = errors.New("appointment slot can no longer be booked")
+	ErrAppointmentDeviceNeeded = errors.New("device details are required to check in")
+)
+
+// Technician assignment errors
+var (
+	ErrNotTechnician         = errors.New("user is not a technician")
+	ErrNoTechnicianAvailable = errors.New("no technician is available for this order")
+	ErrOrderAlreadyAssigned  = errors.New("order already has a technician")
+)
+
+// Courier dispatch errors
+var (
+	ErrNotCourier          = errors.New("user is not a courier")
+	ErrRouteNotFound       = errors.New("route not found")
+	ErrRouteNotProposed    = errors.New("route has already been taken or replaced")
+	ErrRouteStale          = errors.New("some stops of the route are no longer available")
+	ErrInvalidTimeWindow   = errors.New("time window must end after it starts")
+	ErrDeliveryWindowState = errors.New("delivery window can only be changed before the device is delivered")
+)
+
+// Courier tracking errors
+var (
+	ErrTrackingInactive       = errors.New("order is not on the way with a courier")
+	ErrNotOrderCourier        = errors.New("user is not the courier of this order")
+	ErrCourierLocationUnknown = errors.New("courier location is not known yet")
+)
+
+// Handover errors
+var (
+	ErrHandoverRequired      = errors.New("this status change needs a handover proof")
+	ErrHandoverNotExpected   = errors.New("order is not waiting for a handover")
+	ErrHandoverProofMissing  = errors.New("handover code or customer signature is required")
+	ErrHandoverPhotoRequired = errors.New("handover photo is required")
+	ErrHandoverInvalidImage  = errors.New("handover photo and signature must be images")
+	ErrInvalidHandoverCode   = errors.New("invalid or expired handover code")
+	ErrHandoverCodeThrottled = errors.New("handover code was sent recently, please wait")
+)
+
+// Public tracking errors
+var (
+	ErrInvalidTrackingToken = errors.New("invalid tracking link")
+	ErrTrackingLocked       = errors.New("too many failed tracking attempts, please try again later")
+)
+
+// SLA errors
+var (
+	ErrSLAPolicyNotFound    = errors.New("SLA policy not found")
+	ErrSLAPolicyExists      = errors.New("an SLA policy for this service type and membership tier already exists")
+	ErrInvalidBusinessHours = errors.New("business hours must close after they open")
+)
+
+// Corporate account errors
+var (
+	ErrCorporateAccountNotFound = errors.New("corporate account not found")
+	ErrCorporateAccountExists   = errors.New("a corporate account with this NPWP already exists")
+	ErrCorporateAccountInactive = errors.New("corporate account is inactive")
+	ErrCorporateContactNotFound = errors.New("corporate contact not found")
+	ErrNotCorporateContact      = errors.New("you are not an authorized contact of this corporate account")
+	ErrCreditLimitExceeded      = errors.New("work order exceeds the available credit of the corporate account")
+	ErrWorkOrderNotFound        = errors.New("work order not found")
+	ErrCorporateInvoiceNotFound = errors.New("corporate invoice not found")
+	ErrCorporateInvoicePaid     = errors.New("corporate invoice is already paid")
+	ErrInvalidInvoicePeriod     = errors.New("invoice period must be a past month in YYYY-MM format")
+	ErrOrderBilledToAccount     = errors.New("order is billed to its corporate account on the monthly invoice")
+)
+
+// Search errors
+var (
+	ErrSearchQueryTooShort = errors.New("search query must contain at least 2 letters or digits")
+)
+
+// Cancellation errors
+var (
+	ErrOrderNotCancellable       = errors.New("order can no longer be cancelled")
+	ErrCancelRequiresReason      = errors.New("orders are cancelled through the cancel endpoint with a reason")
+	ErrCancellationNoteRequired  = errors.New("a note is required when the reason is other")
+	ErrInvalidCancellationStatus = errors.New("cancellation fees can only be set for cancellable statuses")
+	ErrCancellationRefundDenied  = errors.New("cancelling this order refunds payments, which only staff allowed to refund can do")
+	ErrCancellationWaiveDenied   = errors.New("only staff allowed to waive fees can cancel an order without the fee")
+)
+
+// Order media errors
+var (
+	ErrMediaNotFound        = errors.New("photo not found")
+	ErrMediaFilesRequired   = errors.New("at least one photo is required")
+	ErrMediaTooManyFiles    = errors.New("too many photos in one upload")
+	ErrMediaInvalidImage    = errors.New("photos must be images of at most 10 MB")
+	ErrMediaStorageDisabled = errors.New("photo storage is not available")
+)
+
+// Intake errors
+var (
+	ErrIntakeNotFound         = errors.New("intake has not been recorded for this order")
+	ErrIntakeAcknowledged     = errors.New("intake was already acknowledged by the customer")
+	ErrIntakeNotOpen          = errors.New("intake can only be recorded before the device is ready")
+	ErrIntakeItemUnknown      = errors.New("checklist item is not on the checklist")
+	ErrIntakeItemMissing      = errors.New("every required checklist item must be filled out")
+	ErrIntakeInvalidResult    = errors.New("result does not fit the checklist item")
+	ErrInvalidChecklistType   = errors.New("checklist must be for a service type or default")
+	ErrDuplicateChecklistItem = errors.New("checklist item keys must be unique")
+)
+
+// Order note errors
+var (
+	ErrNoteNotFound       = errors.New("note not found")
+	ErrNoteMentionInvalid = errors.New("only staff of the order's branch can be mentioned")
+)
+
+// Concurrency errors
+var (
+	ErrVersionConflict = errors.New("record was changed by someone else, reload it and try again")
+	ErrInvalidIfMatch  = errors.New("If-Match must hold a single entity tag")
+)
+
+// Numbering errors
+var (
+	ErrInvalidNumberFormat  = errors.New("number format must hold {branch}, {yyyy} or {yy} and {seq}, and only letters, digits and - / . _ around them")
+	ErrInvalidNumberingKind = errors.New("numbering kind must be order or invoice")
 )
 
 // SuccessResponse creates a success response
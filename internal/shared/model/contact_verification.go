@@ -0,0 +1,56 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VerificationChannel represents the contact being verified
+type VerificationChannel string
+
+const (
+	VerificationChannelEmail VerificationChannel = "email"
+	VerificationChannelPhone VerificationChannel = "phone"
+)
+
+// ContactVerification tracks the outstanding verification of a user's email or phone.
+// There is at most one row per user and channel; each resend replaces the secret.
+type ContactVerification struct {
+	ID         uuid.UUID           `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID     uuid.UUID           `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_contact_verifications_user_channel"`
+	Channel    VerificationChannel `json:"channel" gorm:"type:varchar(10);not null;uniqueIndex:idx_contact_verifications_user_channel"`
+	Target     string              `json:"target" gorm:"not null"`             // email address or phone number the secret was sent to
+	SecretHash string              `json:"-" gorm:"type:varchar(64);not null"` // link nonce or OTP code, hashed
+	ExpiresAt  time.Time           `json:"expires_at" gorm:"not null"`
+	Attempts   int                 `json:"attempts" gorm:"default:0"`
+	SendCount  int                 `json:"send_count" gorm:"default:0"`
+	LastSentAt time.Time           `json:"last_sent_at"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
+
+// TableName returns the table name for ContactVerification
+func (ContactVerification) TableName() string {
+	return "contact_verifications"
+}
+
+// VerifyEmailRequest represents the request payload for confirming an email address
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// VerifyPhoneRequest represents the request payload for confirming a phone number
+type VerifyPhoneRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// VerificationStatusResponse represents the contact verification state of the current user
+type VerificationStatusResponse struct {
+	Email           string     `json:"email"`
+	EmailVerified   bool       `json:"email_verified"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	Phone           string     `json:"phone"`
+	PhoneVerified   bool       `json:"phone_verified"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at,omitempty"`
+}
@@ -0,0 +1,213 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CorporateAccount is a company that sends devices in bulk and is invoiced monthly
+type CorporateAccount struct {
+	ID              uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	CompanyName     string         `json:"company_name" gorm:"not null"`
+	NPWP            string         `json:"npwp" gorm:"type:varchar(20);uniqueIndex;not null"`
+	Address         string         `json:"address" gorm:"not null"`
+	Email           string         `json:"email" gorm:"not null"` // receives the monthly invoices
+	Phone           string         `json:"phone" gorm:"not null"`
+	BranchID        uuid.UUID      `json:"branch_id" gorm:"type:uuid;not null;index"` // branch that manages the account
+	CreditLimit     float64        `json:"credit_limit" gorm:"not null;default:0"`    // 0 means no limit
+	PaymentTermDays int            `json:"payment_term_days" gorm:"not null;default:30"`
+	IsActive        bool           `json:"is_active" gorm:"default:true"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName returns the table name for CorporateAccount
+func (CorporateAccount) TableName() string {
+	return "corporate_accounts"
+}
+
+// CorporateAccountRequest represents the request payload for creating or updating a corporate account
+type CorporateAccountRequest struct {
+	CompanyName     string  `json:"company_name" validate:"required,max=200"`
+	NPWP            string  `json:"npwp" validate:"required,min=15,max=20"`
+	Address         string  `json:"address" validate:"required"`
+	Email           string  `json:"email" validate:"required,email"`
+	Phone           string  `json:"phone" validate:"required,phone"`
+	BranchID        string  `json:"branch_id" validate:"required,uuid"`
+	CreditLimit     float64 `json:"credit_limit" validate:"gte=0"`
+	PaymentTermDays int     `json:"payment_term_days,omitempty" validate:"omitempty,min=1,max=120"`
+	IsActive        *bool   `json:"is_active,omitempty"`
+}
+
+// CorporateContact is a user allowed to send work orders for a corporate account
+type CorporateContact struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	AccountID uuid.UUID `json:"account_id" gorm:"type:uuid;not null;uniqueIndex:idx_corporate_contacts_account_user"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_corporate_contacts_account_user;index"`
+	Position  string    `json:"position,omitempty"`
+	IsPrimary bool      `json:"is_primary" gorm:"default:false"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for CorporateContact
+func (CorporateContact) TableName() string {
+	return "corporate_contacts"
+}
+
+// CorporateContactRequest represents the request payload for authorizing a contact
+type CorporateContactRequest struct {
+	UserID    string `json:"user_id" validate:"required,uuid"`
+	Position  string `json:"position,omitempty" validate:"max=100"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+// CorporateContactResponse represents an authorized contact with their user details
+type CorporateContactResponse struct {
+	UserID    uuid.UUID `json:"user_id"`
+	FullName  string    `json:"full_name"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone"`
+	Position  string    `json:"position,omitempty"`
+	IsPrimary bool      `json:"is_primary"`
+}
+
+// CorporateAccountResponse represents a corporate account with its contacts and credit usage
+type CorporateAccountResponse struct {
+	CorporateAccount
+	Contacts        []CorporateContactResponse `json:"contacts"`
+	CreditUsed      float64                    `json:"credit_used"`      // unpaid invoices plus work not yet paid for
+	CreditAvailable *float64                   `json:"credit_available"` // empty when the account has no limit
+}
+
+// WorkOrder groups the devices a corporate account sends in at once. Each device is a
+// service order of its own that progresses independently.
+type WorkOrder struct {
+	ID              uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	WorkOrderNumber string         `json:"work_order_number" gorm:"uniqueIndex;not null"`
+	AccountID       uuid.UUID      `json:"account_id" gorm:"type:uuid;not null;index"`
+	BranchID        uuid.UUID      `json:"branch_id" gorm:"type:uuid;not null;index"`
+	RequestedBy     uuid.UUID      `json:"requested_by" gorm:"type:uuid;not null"`
+	PurchaseOrder   string         `json:"purchase_order,omitempty"` // the company's own reference
+	PickupAddress   string         `json:"pickup_address" gorm:"not null"`
+	PickupLatitude  float64        `json:"pickup_latitude" gorm:"not null"`
+	PickupLongitude float64        `json:"pickup_longitude" gorm:"not null"`
+	Notes           string         `json:"notes,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName returns the table name for WorkOrder
+func (WorkOrder) TableName() string {
+	return "corporate_work_orders"
+}
+
+// WorkOrderLineRequest represents one device of a work order
+type WorkOrderLineRequest struct {
+	IPhoneModel   string      `json:"iphone_model" validate:"required"`
+	IPhoneColor   string      `json:"iphone_color" validate:"required"`
+	IPhoneIMEI    string      `json:"iphone_imei" validate:"required,imei"`
+	ServiceType   ServiceType `json:"service_type" validate:"required,oneof=screen_repair battery_replacement water_damage software_issue hardware_repair other"`
+	Description   string      `json:"description" validate:"required"`
+	EstimatedCost float64     `json:"estimated_cost" validate:"gte=0"`
+}
+
+// WorkOrderRequest represents the request payload for creating a work order
+type WorkOrderRequest struct {
+	AccountID       string                 `json:"account_id" validate:"required,uuid"`
+	BranchID        string                 `json:"branch_id" validate:"required,uuid"`
+	PurchaseOrder   string                 `json:"purchase_order,omitempty" validate:"max=100"`
+	PickupAddress   string                 `json:"pickup_address" validate:"required"`
+	PickupLatitude  float64                `json:"pickup_latitude" validate:"required"`
+	PickupLongitude float64                `json:"pickup_longitude" validate:"required"`
+	Notes           string                 `json:"notes,omitempty"`
+	Lines           []WorkOrderLineRequest `json:"lines" validate:"required,min=1,max=200,dive"`
+}
+
+// WorkOrderResponse represents a work order with its device lines and their progress
+type WorkOrderResponse struct {
+	WorkOrder
+	Completed      bool                   `json:"completed"` // every line is completed or cancelled
+	StatusCounts   map[OrderStatus]int    `json:"status_counts"`
+	EstimatedTotal float64                `json:"estimated_total"`
+	Lines          []ServiceOrderResponse `json:"lines"`
+}
+
+// CorporateInvoiceStatus represents the status of a monthly corporate invoice
+type CorporateInvoiceStatus string
+
+const (
+	CorporateInvoiceStatusIssued  CorporateInvoiceStatus = "issued"
+	CorporateInvoiceStatusPaid    CorporateInvoiceStatus = "paid"
+	CorporateInvoiceStatusOverdue CorporateInvoiceStatus = "overdue" // reported only, never stored
+)
+
+// CorporateInvoice bills all work a corporate account completed in a month
+type CorporateInvoice struct {
+	ID            uuid.UUID              `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	InvoiceNumber string                 `json:"invoice_number" gorm:"uniqueIndex;not null"`
+	AccountID     uuid.UUID              `json:"account_id" gorm:"type:uuid;not null;uniqueIndex:idx_corporate_invoices_account_period"`
+	PeriodStart   time.Time              `json:"period_start" gorm:"not null;uniqueIndex:idx_corporate_invoices_account_period"`
+	PeriodEnd     time.Time              `json:"period_end" gorm:"not null"` // exclusive
+	IssuedAt      time.Time              `json:"issued_at" gorm:"not null"`
+	DueAt         time.Time              `json:"due_at" gorm:"not null"`
+	Subtotal      float64                `json:"subtotal" gorm:"not null"`
+	TaxAmount     float64                `json:"tax_amount" gorm:"not null"`
+	Total         float64                `json:"total" gorm:"not null"`
+	Status        CorporateInvoiceStatus `json:"status" gorm:"type:varchar(20);not null;default:'issued'"`
+	PaidAt        *time.Time             `json:"paid_at,omitempty"`
+	PaymentRef    string                 `json:"payment_ref,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+
+	Lines []CorporateInvoiceLine `json:"lines,omitempty" gorm:"foreignKey:InvoiceID"`
+}
+
+// TableName returns the table name for CorporateInvoice
+func (CorporateInvoice) TableName() string {
+	return "corporate_invoices"
+}
+
+// StatusAt returns the status of the invoice at the given moment, reporting unpaid
+// invoices past their due date as overdue
+func (i *CorporateInvoice) StatusAt(now time.Time) CorporateInvoiceStatus {
+	if i.Status == CorporateInvoiceStatusIssued && now.After(i.DueAt) {
+		return CorporateInvoiceStatusOverdue
+	}
+	return i.Status
+}
+
+// CorporateInvoiceLine is one completed device on a corporate invoice. An order is
+// invoiced only once.
+type CorporateInvoiceLine struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	InvoiceID   uuid.UUID  `json:"invoice_id" gorm:"type:uuid;not null;index"`
+	OrderID     uuid.UUID  `json:"order_id" gorm:"type:uuid;not null;uniqueIndex"`
+	WorkOrderID *uuid.UUID `json:"work_order_id,omitempty" gorm:"type:uuid"`
+	OrderNumber string     `json:"order_number" gorm:"not null"`
+	Description string     `json:"description" gorm:"not null"`
+	CompletedAt time.Time  `json:"completed_at" gorm:"not null"`
+	Amount      float64    `json:"amount" gorm:"not null"`
+	TaxAmount   float64    `json:"tax_amount" gorm:"not null"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// TableName returns the table name for CorporateInvoiceLine
+func (CorporateInvoiceLine) TableName() string {
+	return "corporate_invoice_lines"
+}
+
+// GenerateCorporateInvoiceRequest represents the request payload for invoicing a month by hand
+type GenerateCorporateInvoiceRequest struct {
+	Period string `json:"period" validate:"required,datetime=2006-01"` // YYYY-MM
+}
+
+// PayCorporateInvoiceRequest represents the request payload for recording an invoice payment
+type PayCorporateInvoiceRequest struct {
+	PaymentRef string     `json:"payment_ref" validate:"required,max=100"`
+	PaidAt     *time.Time `json:"paid_at,omitempty"`
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CourierLocation is a point of the thinned trail a courier drove for an order
+type CourierLocation struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderID    uuid.UUID `json:"order_id" gorm:"type:uuid;not null;index:idx_courier_locations_order_time"`
+	CourierID  uuid.UUID `json:"courier_id" gorm:"type:uuid;not null;index"`
+	Latitude   float64   `json:"latitude" gorm:"type:decimal(10,6);not null"`
+	Longitude  float64   `json:"longitude" gorm:"type:decimal(10,6);not null"`
+	Accuracy   float64   `json:"accuracy,omitempty"` // meters
+	RecordedAt time.Time `json:"recorded_at" gorm:"not null;index:idx_courier_locations_order_time"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for CourierLocation
+func (CourierLocation) TableName() string {
+	return "courier_locations"
+}
+
+// CourierLocationRequest represents a GPS ping sent by a courier
+type CourierLocationRequest struct {
+	Latitude   float64    `json:"latitude" validate:"required,min=-90,max=90"`
+	Longitude  float64    `json:"longitude" validate:"required,min=-180,max=180"`
+	Accuracy   float64    `json:"accuracy,omitempty" validate:"omitempty,min=0"`        // meters
+	Heading    float64    `json:"heading,omitempty" validate:"omitempty,min=0,max=360"` // degrees from north
+	Speed      float64    `json:"speed,omitempty" validate:"omitempty,min=0"`           // km/h
+	RecordedAt *time.Time `json:"recorded_at,omitempty"`                                // when the device took the fix; defaults to now
+}
+
+// CourierPosition is the latest known position of the courier of an order with the
+// estimated arrival at the customer
+type CourierPosition struct {
+	OrderID          uuid.UUID   `json:"order_id"`
+	CourierID        uuid.UUID   `json:"courier_id"`
+	Status           OrderStatus `json:"status"`
+	Latitude         float64     `json:"latitude"`
+	Longitude        float64     `json:"longitude"`
+	Accuracy         float64     `json:"accuracy,omitempty"`
+	Heading          float64     `json:"heading,omitempty"`
+	Speed            float64     `json:"speed,omitempty"`
+	RecordedAt       time.Time   `json:"recorded_at"`
+	DistanceKm       float64     `json:"distance_km"` // straight line to the pickup address
+	ETAMinutes       int         `json:"eta_minutes"`
+	EstimatedArrival time.Time   `json:"estimated_arrival"`
+}
+
+// CourierLocationEvent is pushed to subscribers of an order's courier location
+type CourierLocationEvent struct {
+	Type     string           `json:"type"` // "location" or "stopped"
+	OrderID  uuid.UUID        `json:"order_id"`
+	Status   OrderStatus      `json:"status,omitempty"`
+	Position *CourierPosition `json:"position,omitempty"`
+}
+
+// Courier location event types
+const (
+	CourierLocationEventLocation = "location"
+	CourierLocationEventStopped  = "stopped"
+)
+
+// IsCourierTracked reports whether a courier is on the way for the order, either to
+// collect the device or to bring it back
+func (so *ServiceOrder) IsCourierTracked() bool {
+	if so.CourierID == nil {
+		return false
+	}
+	return so.Status == StatusOnPickup || so.Status == StatusReady
+}
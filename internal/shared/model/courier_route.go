@@ -0,0 +1,95 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CourierRouteStatus represents the state of a planned courier route
+type CourierRouteStatus string
+
+const (
+	CourierRouteStatusProposed   CourierRouteStatus = "proposed"   // planned, waiting for a courier
+	CourierRouteStatusAccepted   CourierRouteStatus = "accepted"   // taken by a courier
+	CourierRouteStatusCompleted  CourierRouteStatus = "completed"  // every stop has been visited
+	CourierRouteStatusSuperseded CourierRouteStatus = "superseded" // replaced by a newer plan before anyone took it
+)
+
+// RouteStopKind tells whether the courier collects or returns the device at a stop
+type RouteStopKind string
+
+const (
+	RouteStopPickup   RouteStopKind = "pickup"
+	RouteStopDelivery RouteStopKind = "delivery"
+)
+
+// CourierRoute is an ordered multi-stop trip that starts and ends at a branch
+type CourierRoute struct {
+	ID                uuid.UUID          `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	BranchID          uuid.UUID          `json:"branch_id" gorm:"type:uuid;not null;index"`
+	CourierID         *uuid.UUID         `json:"courier_id,omitempty" gorm:"type:uuid;index"`
+	Status            CourierRouteStatus `json:"status" gorm:"type:varchar(20);not null;default:'proposed'"`
+	DepartAt          time.Time          `json:"depart_at" gorm:"not null"`
+	ReturnAt          time.Time          `json:"return_at" gorm:"not null"`
+	TotalDistanceKm   float64            `json:"total_distance_km"`
+	EstimatedDuration int                `json:"estimated_duration"` // minutes
+	LateStops         int                `json:"late_stops"`         // stops that cannot be reached inside their time window
+	Stops             []CourierRouteStop `json:"stops" gorm:"foreignKey:RouteID"`
+	AcceptedAt        *time.Time         `json:"accepted_at,omitempty"`
+	CompletedAt       *time.Time         `json:"completed_at,omitempty"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+}
+
+// TableName returns the table name for CourierRoute
+func (CourierRoute) TableName() string {
+	return "courier_routes"
+}
+
+// CourierRouteStop is one visit of a route, in driving order
+type CourierRouteStop struct {
+	ID               uuid.UUID     `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	RouteID          uuid.UUID     `json:"route_id" gorm:"type:uuid;not null;index"`
+	OrderID          uuid.UUID     `json:"order_id" gorm:"type:uuid;not null;index"`
+	OrderNumber      string        `json:"order_number" gorm:"not null"`
+	Sequence         int           `json:"sequence" gorm:"not null"`
+	Kind             RouteStopKind `json:"kind" gorm:"type:varchar(20);not null"`
+	Address          string        `json:"address"`
+	Latitude         float64       `json:"latitude"`
+	Longitude        float64       `json:"longitude"`
+	WindowStart      *time.Time    `json:"window_start,omitempty"`
+	WindowEnd        *time.Time    `json:"window_end,omitempty"`
+	EstimatedArrival time.Time     `json:"estimated_arrival"`
+	DistanceKm       float64       `json:"distance_km"` // from the previous stop or the branch
+	Late             bool          `json:"late"`
+	CompletedAt      *time.Time    `json:"completed_at,omitempty"`
+}
+
+// TableName returns the table name for CourierRouteStop
+func (CourierRouteStop) TableName() string {
+	return "courier_route_stops"
+}
+
+// DeliveryWindowRequest represents the request payload for choosing when a device is returned
+type DeliveryWindowRequest struct {
+	Start *time.Time `json:"start" validate:"required"`
+	End   *time.Time `json:"end" validate:"required"`
+}
+
+// DispatchPlanResponse represents the proposed routes of a branch
+type DispatchPlanResponse struct {
+	BranchID   uuid.UUID       `json:"branch_id"`
+	PlannedAt  time.Time       `json:"planned_at"`
+	Routes     []*CourierRoute `json:"routes"`
+	TotalStops int             `json:"total_stops"`
+}
+
+// IsValidTimeWindow reports whether a time window is usable. Either end may be open,
+// but a closed window must end after it starts.
+func IsValidTimeWindow(start, end *time.Time) bool {
+	if start == nil || end == nil {
+		return true
+	}
+	return end.After(*start)
+}
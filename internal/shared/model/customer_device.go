@@ -0,0 +1,132 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DeviceModel is an entry in the catalog of iPhone models we service
+type DeviceModel struct {
+	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Name        string         `json:"name" gorm:"type:varchar(100);uniqueIndex;not null"` // e.g. "iPhone 13 Pro"
+	Identifier  string         `json:"identifier,omitempty" gorm:"type:varchar(50)"`       // Apple model identifier, e.g. "iPhone14,2"
+	ReleaseYear int            `json:"release_year,omitempty"`
+	IsActive    bool           `json:"is_active" gorm:"default:true"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName returns the table name for DeviceModel
+func (DeviceModel) TableName() string {
+	return "device_models"
+}
+
+// DeviceModelRequest represents the request payload for creating or updating a catalog model
+type DeviceModelRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Identifier  string `json:"identifier,omitempty" validate:"omitempty,max=50"`
+	ReleaseYear int    `json:"release_year,omitempty" validate:"omitempty,min=2007,max=2100"`
+	IsActive    *bool  `json:"is_active,omitempty"`
+}
+
+// CustomerDevice is a phone owned by a customer. Orders link to it so that the
+// service history follows the phone rather than the free-text fields of each order.
+type CustomerDevice struct {
+	ID            uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	CustomerID    uuid.UUID      `json:"customer_id" gorm:"type:uuid;not null;index"`
+	DeviceModelID uuid.UUID      `json:"device_model_id" gorm:"type:uuid;not null"`
+	DeviceModel   DeviceModel    `json:"device_model" gorm:"foreignKey:DeviceModelID"`
+	IMEI          string         `json:"imei" gorm:"type:varchar(15);not null;index"`
+	SerialNumber  string         `json:"serial_number,omitempty" gorm:"type:varchar(20)"`
+	Color         string         `json:"color" gorm:"type:varchar(50)"`
+	PurchaseDate  *time.Time     `json:"purchase_date,omitempty" gorm:"type:date"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName returns the table name for CustomerDevice
+func (CustomerDevice) TableName() string {
+	return "customer_devices"
+}
+
+// CustomerDeviceRequest represents the request payload for registering or updating a device
+type CustomerDeviceRequest struct {
+	DeviceModelID string `json:"device_model_id" validate:"required,uuid"`
+	IMEI          string `json:"imei" validate:"required,imei"`
+	SerialNumber  string `json:"serial_number,omitempty" validate:"omitempty,alphanum,max=20"`
+	Color         string `json:"color" validate:"required,max=50"`
+	PurchaseDate  string `json:"purchase_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// CustomerDeviceResponse represents a customer device in API responses
+type CustomerDeviceResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	CustomerID    uuid.UUID  `json:"customer_id"`
+	DeviceModelID uuid.UUID  `json:"device_model_id"`
+	ModelName     string     `json:"model_name"`
+	IMEI          string     `json:"imei"`
+	SerialNumber  string     `json:"serial_number,omitempty"`
+	Color         string     `json:"color"`
+	PurchaseDate  *time.Time `json:"purchase_date,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// ToResponse converts CustomerDevice to CustomerDeviceResponse
+func (d *CustomerDevice) ToResponse() CustomerDeviceResponse {
+	return CustomerDeviceResponse{
+		ID:            d.ID,
+		CustomerID:    d.CustomerID,
+		DeviceModelID: d.DeviceModelID,
+		ModelName:     d.DeviceModel.Name,
+		IMEI:          d.IMEI,
+		SerialNumber:  d.SerialNumber,
+		Color:         d.Color,
+		PurchaseDate:  d.PurchaseDate,
+		CreatedAt:     d.CreatedAt,
+		UpdatedAt:     d.UpdatedAt,
+	}
+}
+
+// DeviceServiceRecord is one visit of a device in its service history
+type DeviceServiceRecord struct {
+	OrderID      uuid.UUID           `json:"order_id"`
+	OrderNumber  string              `json:"order_number"`
+	BranchID     uuid.UUID           `json:"branch_id"`
+	TechnicianID *uuid.UUID          `json:"technician_id,omitempty"`
+	ServiceType  ServiceType         `json:"service_type"`
+	Description  string              `json:"description"`
+	Status       OrderStatus         `json:"status"`
+	ActualCost   float64             `json:"actual_cost"`
+	Warranty     *DeviceWarranty     `json:"warranty,omitempty"`
+	Parts        []OrderPartResponse `json:"parts"`
+	Photos       []DevicePhoto       `json:"photos"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+// DeviceWarranty is the warranty given on one service of a device
+type DeviceWarranty struct {
+	StartDate     time.Time `json:"start_date"`
+	EndDate       time.Time `json:"end_date"`
+	IsActive      bool      `json:"is_active"`
+	DaysRemaining int       `json:"days_remaining"`
+}
+
+// DevicePhoto is a photo taken of a device during an order
+type DevicePhoto struct {
+	Stage      string    `json:"stage"` // pickup, service or delivery
+	URL        string    `json:"url"`
+	UploadedAt time.Time `json:"uploaded_at"`
+}
+
+// DeviceHistoryResponse is the service timeline of a device, newest visit first
+type DeviceHistoryResponse struct {
+	Device  *CustomerDeviceResponse `json:"device,omitempty"`
+	IMEI    string                  `json:"imei"`
+	Records []DeviceServiceRecord   `json:"records"`
+}
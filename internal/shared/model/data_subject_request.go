@@ -0,0 +1,202 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DataSubjectRequestType represents what a data subject asked for under UU PDP
+type DataSubjectRequestType string
+
+const (
+	DataSubjectRequestExport  DataSubjectRequestType = "export"
+	DataSubjectRequestErasure DataSubjectRequestType = "erasure"
+)
+
+// DataSubjectRequestStatus represents the processing state of a data subject request
+type DataSubjectRequestStatus string
+
+const (
+	DataSubjectRequestPending    DataSubjectRequestStatus = "pending"  // waiting for approval (erasure only)
+	DataSubjectRequestApproved   DataSubjectRequestStatus = "approved" // queued for the background job
+	DataSubjectRequestProcessing DataSubjectRequestStatus = "processing"
+	DataSubjectRequestCompleted  DataSubjectRequestStatus = "completed"
+	DataSubjectRequestFailed     DataSubjectRequestStatus = "failed"
+	DataSubjectRequestRejected   DataSubjectRequestStatus = "rejected"
+)
+
+// DataSubjectRequest represents a personal data export or erasure request
+type DataSubjectRequest struct {
+	ID            uuid.UUID                `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID        uuid.UUID                `json:"user_id" gorm:"type:uuid;not null;index"`
+	Type          DataSubjectRequestType   `json:"type" gorm:"type:varchar(20);not null"`
+	Status        DataSubjectRequestStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	Reason        string                   `json:"reason,omitempty" gorm:"type:text"`
+	RequestedBy   uuid.UUID                `json:"requested_by" gorm:"type:uuid;not null"`
+	ReviewedBy    *uuid.UUID               `json:"reviewed_by,omitempty" gorm:"type:uuid"`
+	ReviewNotes   string                   `json:"review_notes,omitempty" gorm:"type:text"`
+	ArchiveObject string                   `json:"-" gorm:"type:text"` // object name of the export archive in storage
+	ArchiveSize   int64                    `json:"archive_size,omitempty"`
+	ErrorMessage  string                   `json:"error_message,omitempty" gorm:"type:text"`
+	StartedAt     *time.Time               `json:"started_at,omitempty"`
+	CompletedAt   *time.Time               `json:"completed_at,omitempty"`
+	CreatedAt     time.Time                `json:"created_at"`
+	UpdatedAt     time.Time                `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt           `json:"-" gorm:"index"`
+}
+
+// TableName returns the table name for DataSubjectRequest
+func (DataSubjectRequest) TableName() string {
+	return "data_subject_requests"
+}
+
+// DataSubjectRequestRequest represents the request payload for a data subject request
+type DataSubjectRequestRequest struct {
+	Type   DataSubjectRequestType `json:"type" validate:"required,oneof=export erasure"`
+	Reason string                 `json:"reason,omitempty"`
+	UserID string                 `json:"user_id,omitempty" validate:"omitempty,uuid"` // admin only, defaults to the caller
+}
+
+// ReviewDataSubjectRequest represents the request payload for approving or rejecting a request
+type ReviewDataSubjectRequest struct {
+	Notes string `json:"notes,omitempty"`
+}
+
+// DataExportDownloadResponse represents a short-lived download link for an export archive
+type DataExportDownloadResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PersonalDataExport is the machine-readable content of an export archive
+type PersonalDataExport struct {
+	GeneratedAt   time.Time                `json:"generated_at"`
+	User          UserDataExport           `json:"user"`
+	Orders        []OrderDataExport        `json:"orders"`
+	Payments      []PaymentDataExport      `json:"payments"`
+	ChatMessages  []ChatMessageDataExport  `json:"chat_messages"`
+	Notifications []NotificationDataExport `json:"notifications"`
+	Ratings       []RatingDataExport       `json:"ratings"`
+	Devices       []UserDeviceResponse     `json:"devices"`
+	Membership    *MembershipResponse      `json:"membership,omitempty"`
+	Files         []string                 `json:"files"` // object names of stored photos included in the archive
+
+	RegisteredDevices []CustomerDeviceResponse `json:"registered_devices"`
+	Appointments      []AppointmentResponse    `json:"appointments"`
+}
+
+// UserDataExport is the exported account data of a user
+type UserDataExport struct {
+	ID          uuid.UUID  `json:"id"`
+	Email       string     `json:"email"`
+	FullName    string     `json:"full_name"`
+	Phone       string     `json:"phone"`
+	Role        UserRole   `json:"role"`
+	AvatarURL   string     `json:"avatar_url,omitempty"`
+	BranchID    *uuid.UUID `json:"branch_id,omitempty"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// OrderDataExport is the exported data of a service order, including identity documents
+type OrderDataExport struct {
+	ID              uuid.UUID   `json:"id"`
+	OrderNumber     string      `json:"order_number"`
+	BranchID        uuid.UUID   `json:"branch_id"`
+	IPhoneModel     string      `json:"iphone_model"`
+	IPhoneColor     string      `json:"iphone_color"`
+	IPhoneIMEI      string      `json:"iphone_imei"`
+	ServiceType     ServiceType `json:"service_type"`
+	Description     string      `json:"description"`
+	PickupAddress   string      `json:"pickup_address"`
+	PickupLatitude  float64     `json:"pickup_latitude"`
+	PickupLongitude float64     `json:"pickup_longitude"`
+	Status          OrderStatus `json:"status"`
+	EstimatedCost   float64     `json:"estimated_cost"`
+	ActualCost      float64     `json:"actual_cost"`
+	TaxAmount       float64     `json:"tax_amount"`
+	InvoiceNumber   string      `json:"invoice_number,omitempty"`
+	CustomerIDCard  string      `json:"customer_id_card,omitempty"`
+	CustomerNPWP    string      `json:"customer_npwp,omitempty"`
+	PickupPhoto     string      `json:"pickup_photo,omitempty"`
+	ServicePhoto    string      `json:"service_photo,omitempty"`
+	DeliveryPhoto   string      `json:"delivery_photo,omitempty"`
+	Notes           string      `json:"notes,omitempty"`
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
+
+// PaymentDataExport is the exported data of a payment
+type PaymentDataExport struct {
+	ID            uuid.UUID     `json:"id"`
+	OrderID       uuid.UUID     `json:"order_id"`
+	InvoiceNumber string        `json:"invoice_number"`
+	Amount        float64       `json:"amount"`
+	PaymentMethod PaymentMethod `json:"payment_method"`
+	Status        PaymentStatus `json:"status"`
+	PaidAt        *time.Time    `json:"paid_at,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+// ChatMessageDataExport is the exported data of a chat message
+type ChatMessageDataExport struct {
+	ID         uuid.UUID `json:"id"`
+	OrderID    uuid.UUID `json:"order_id"`
+	SenderID   uuid.UUID `json:"sender_id"`
+	ReceiverID uuid.UUID `json:"receiver_id"`
+	Message    string    `json:"message"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NotificationDataExport is the exported data of a notification
+type NotificationDataExport struct {
+	ID        uuid.UUID        `json:"id"`
+	OrderID   *uuid.UUID       `json:"order_id,omitempty"`
+	Type      NotificationType `json:"type"`
+	Title     string           `json:"title"`
+	Message   string           `json:"message"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// RatingDataExport is the exported data of a rating
+type RatingDataExport struct {
+	ID        uuid.UUID `json:"id"`
+	OrderID   uuid.UUID `json:"order_id"`
+	Rating    int       `json:"rating"`
+	Review    string    `json:"review,omitempty"`
+	IsPublic  bool      `json:"is_public"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewOrderDataExport converts a service order to its export representation
+func NewOrderDataExport(o *ServiceOrder) OrderDataExport {
+	return OrderDataExport{
+		ID:              o.ID,
+		OrderNumber:     o.OrderNumber,
+		BranchID:        o.BranchID,
+		IPhoneModel:     o.IPhoneModel,
+		IPhoneColor:     o.IPhoneColor,
+		IPhoneIMEI:      o.IPhoneIMEI,
+		ServiceType:     o.ServiceType,
+		Description:     o.Description,
+		PickupAddress:   o.PickupAddress,
+		PickupLatitude:  o.PickupLatitude,
+		PickupLongitude: o.PickupLongitude,
+		Status:          o.Status,
+		EstimatedCost:   o.EstimatedCost,
+		ActualCost:      o.ActualCost,
+		TaxAmount:       o.TaxAmount,
+		InvoiceNumber:   o.InvoiceNumber,
+		CustomerIDCard:  o.CustomerIDCard,
+		CustomerNPWP:    o.CustomerNPWP,
+		PickupPhoto:     o.PickupPhoto,
+		ServicePhoto:    o.ServicePhoto,
+		DeliveryPhoto:   o.DeliveryPhoto,
+		Notes:           o.Notes,
+		CreatedAt:       o.CreatedAt,
+		UpdatedAt:       o.UpdatedAt,
+	}
+}
@@ -0,0 +1,137 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HandoverKind tells which handover of an order is being proven
+type HandoverKind string
+
+const (
+	HandoverPickup   HandoverKind = "pickup"   // the customer gives the device to the courier
+	HandoverDelivery HandoverKind = "delivery" // the courier returns the device to the customer
+)
+
+// HandoverMethod is how the customer confirmed a handover
+type HandoverMethod string
+
+const (
+	HandoverMethodCode      HandoverMethod = "code"
+	HandoverMethodSignature HandoverMethod = "signature"
+)
+
+// Reasons recorded for failed handover attempts
+const (
+	HandoverFailMissingProof = "missing_proof"  // neither code nor signature
+	HandoverFailMissingPhoto = "missing_photo"  // no photo of the device
+	HandoverFailInvalidImage = "invalid_image"  // an upload is not an image
+	HandoverFailNoCode       = "no_code_issued" // the customer has no code for this handover
+	HandoverFailCodeExpired  = "code_expired"
+	HandoverFailCodeLocked   = "code_locked" // too many wrong codes
+	HandoverFailWrongCode    = "wrong_code"
+)
+
+// HandoverCode is the one-time code the customer shows the courier. There is at most one
+// per order and handover; issuing a new code replaces the old one.
+type HandoverCode struct {
+	ID        uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderID   uuid.UUID    `json:"order_id" gorm:"type:uuid;not null;uniqueIndex:idx_handover_codes_order_kind"`
+	Kind      HandoverKind `json:"kind" gorm:"type:varchar(20);not null;uniqueIndex:idx_handover_codes_order_kind"`
+	CodeHash  string       `json:"-" gorm:"type:varchar(64);not null"`
+	ExpiresAt time.Time    `json:"expires_at" gorm:"not null"`
+	Attempts  int          `json:"attempts" gorm:"default:0"`
+	UsedAt    *time.Time   `json:"used_at,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// TableName returns the table name for HandoverCode
+func (HandoverCode) TableName() string {
+	return "handover_codes"
+}
+
+// HandoverProof records a completed handover with its evidence
+type HandoverProof struct {
+	ID              uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderID         uuid.UUID      `json:"order_id" gorm:"type:uuid;not null;index"`
+	Kind            HandoverKind   `json:"kind" gorm:"type:varchar(20);not null"`
+	PerformedBy     uuid.UUID      `json:"performed_by" gorm:"type:uuid;not null"`
+	Method          HandoverMethod `json:"method" gorm:"type:varchar(20);not null"`
+	PhotoObject     string         `json:"-" gorm:"not null"`
+	SignatureObject string         `json:"-"`
+	Latitude        float64        `json:"latitude" gorm:"type:decimal(10,6);not null"`
+	Longitude       float64        `json:"longitude" gorm:"type:decimal(10,6);not null"`
+	Accuracy        float64        `json:"accuracy,omitempty"` // meters
+	DistanceKm      float64        `json:"distance_km"`        // from the customer's address
+	CapturedAt      time.Time      `json:"captured_at" gorm:"not null"`
+	Notes           string         `json:"notes,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+}
+
+// TableName returns the table name for HandoverProof
+func (HandoverProof) TableName() string {
+	return "handover_proofs"
+}
+
+// HandoverAttempt logs a handover that was refused, for dispute resolution
+type HandoverAttempt struct {
+	ID          uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderID     uuid.UUID    `json:"order_id" gorm:"type:uuid;not null;index"`
+	Kind        HandoverKind `json:"kind" gorm:"type:varchar(20);not null"`
+	PerformedBy uuid.UUID    `json:"performed_by" gorm:"type:uuid;not null"`
+	Reason      string       `json:"reason" gorm:"type:varchar(30);not null"`
+	Latitude    float64      `json:"latitude" gorm:"type:decimal(10,6)"`
+	Longitude   float64      `json:"longitude" gorm:"type:decimal(10,6)"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// TableName returns the table name for HandoverAttempt
+func (HandoverAttempt) TableName() string {
+	return "handover_attempts"
+}
+
+// HandoverRequest represents the form fields sent with a handover. The photo and the
+// optional signature are uploaded as files alongside.
+type HandoverRequest struct {
+	Code       string     `form:"code" validate:"omitempty,len=6,numeric"`
+	Latitude   float64    `form:"latitude" validate:"required,min=-90,max=90"`
+	Longitude  float64    `form:"longitude" validate:"required,min=-180,max=180"`
+	Accuracy   float64    `form:"accuracy" validate:"omitempty,min=0"`
+	CapturedAt *time.Time `form:"captured_at"`
+	Notes      string     `form:"notes" validate:"omitempty,max=500"`
+}
+
+// HandoverCodeResponse represents a freshly issued handover code for the customer
+type HandoverCodeResponse struct {
+	OrderID   uuid.UUID    `json:"order_id"`
+	Kind      HandoverKind `json:"kind"`
+	Code      string       `json:"code"`
+	ExpiresAt time.Time    `json:"expires_at"`
+}
+
+// HandoverProofResponse represents a completed handover with temporary links to its evidence
+type HandoverProofResponse struct {
+	HandoverProof
+	PhotoURL     string `json:"photo_url,omitempty"`
+	SignatureURL string `json:"signature_url,omitempty"`
+}
+
+// HandoverHistoryResponse represents the handovers of an order and the refused attempts
+type HandoverHistoryResponse struct {
+	Proofs   []HandoverProofResponse `json:"proofs"`
+	Attempts []*HandoverAttempt      `json:"attempts"`
+}
+
+// HandoverKindFor returns the handover that ends the order's current courier leg and the
+// status the order moves to afterwards
+func HandoverKindFor(status OrderStatus) (HandoverKind, OrderStatus, bool) {
+	switch status {
+	case StatusOnPickup:
+		return HandoverPickup, StatusInService, true
+	case StatusReady:
+		return HandoverDelivery, StatusDelivered, true
+	}
+	return "", "", false
+}
@@ -0,0 +1,186 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IntakeDefaultChecklist is the service type key of the checklist used for service types
+// without a checklist of their own
+const IntakeDefaultChecklist = "default"
+
+// IntakeItemKind tells which results an intake checklist item accepts
+type IntakeItemKind string
+
+const (
+	IntakeItemCondition IntakeItemKind = "condition" // ok, damaged, not_working or not_applicable
+	IntakeItemYesNo     IntakeItemKind = "yes_no"    // yes or no
+)
+
+// IntakeResult is the recorded state of one checklist item
+type IntakeResult string
+
+const (
+	IntakeResultOK            IntakeResult = "ok"
+	IntakeResultDamaged       IntakeResult = "damaged"
+	IntakeResultNotWorking    IntakeResult = "not_working"
+	IntakeResultNotApplicable IntakeResult = "not_applicable"
+	IntakeResultYes           IntakeResult = "yes"
+	IntakeResultNo            IntakeResult = "no"
+)
+
+// Accepts reports whether a result is valid for items of this kind
+func (k IntakeItemKind) Accepts(result IntakeResult) bool {
+	switch k {
+	case IntakeItemCondition:
+		return result == IntakeResultOK || result == IntakeResultDamaged ||
+			result == IntakeResultNotWorking || result == IntakeResultNotApplicable
+	case IntakeItemYesNo:
+		return result == IntakeResultYes || result == IntakeResultNo
+	}
+	return false
+}
+
+// DefaultIntakeItems is the checklist used until admins configure one
+var DefaultIntakeItems = []IntakeChecklistItem{
+	{Key: "screen", Label: "Screen (cracks, scratches, dead pixels)", Kind: IntakeItemCondition, Required: true},
+	{Key: "housing", Label: "Housing and back glass", Kind: IntakeItemCondition, Required: true},
+	{Key: "face_id", Label: "Face ID / Touch ID", Kind: IntakeItemCondition, Required: true},
+	{Key: "cameras", Label: "Front and rear cameras", Kind: IntakeItemCondition, Required: true},
+	{Key: "buttons", Label: "Power, volume and mute buttons", Kind: IntakeItemCondition, Required: true},
+	{Key: "water_indicators", Label: "Liquid contact indicators", Kind: IntakeItemCondition, Required: true},
+	{Key: "passcode_provided", Label: "Passcode provided", Kind: IntakeItemYesNo, Required: true},
+	{Key: "find_my_disabled", Label: "Find My disabled", Kind: IntakeItemYesNo, Required: true},
+}
+
+// IntakeChecklist is the intake checklist configured for a service type
+type IntakeChecklist struct {
+	ID          uuid.UUID             `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ServiceType string                `json:"service_type" gorm:"type:varchar(50);uniqueIndex;not null"` // a ServiceType or IntakeDefaultChecklist
+	Name        string                `json:"name" gorm:"not null"`
+	UpdatedBy   uuid.UUID             `json:"updated_by" gorm:"type:uuid"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+	Items       []IntakeChecklistItem `json:"items" gorm:"foreignKey:ChecklistID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName returns the table name for IntakeChecklist
+func (IntakeChecklist) TableName() string {
+	return "intake_checklists"
+}
+
+// IntakeChecklistItem is one point to check on a device at intake
+type IntakeChecklistItem struct {
+	ID          uuid.UUID      `json:"-" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ChecklistID uuid.UUID      `json:"-" gorm:"type:uuid;not null;index"`
+	Key         string         `json:"key" gorm:"type:varchar(50);not null"`
+	Label       string         `json:"label" gorm:"not null"`
+	Kind        IntakeItemKind `json:"kind" gorm:"type:varchar(20);not null"`
+	Required    bool           `json:"required" gorm:"default:true"`
+	Position    int            `json:"position" gorm:"not null;default:0"`
+}
+
+// TableName returns the table name for IntakeChecklistItem
+func (IntakeChecklistItem) TableName() string {
+	return "intake_checklist_items"
+}
+
+// IntakeChecklistItemRequest represents one item of a checklist update
+type IntakeChecklistItemRequest struct {
+	Key      string         `json:"key" validate:"required,max=50"`
+	Label    string         `json:"label" validate:"required,max=200"`
+	Kind     IntakeItemKind `json:"kind" validate:"required,oneof=condition yes_no"`
+	Required bool           `json:"required"`
+}
+
+// IntakeChecklistRequest represents the request payload for configuring a checklist
+type IntakeChecklistRequest struct {
+	Name  string                       `json:"name" validate:"required,max=100"`
+	Items []IntakeChecklistItemRequest `json:"items" validate:"required,min=1,max=50,dive"`
+}
+
+// OrderIntake is the condition of a device recorded at intake. Items are copied from the
+// checklist so the record survives checklist changes. Once the customer acknowledged it,
+// it can no longer be changed.
+type OrderIntake struct {
+	ID              uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderID         uuid.UUID         `json:"order_id" gorm:"type:uuid;not null;uniqueIndex"`
+	ChecklistName   string            `json:"checklist_name" gorm:"not null"`
+	Notes           string            `json:"notes,omitempty"`
+	FilledBy        uuid.UUID         `json:"filled_by" gorm:"type:uuid;not null"`
+	FilledAt        time.Time         `json:"filled_at" gorm:"not null"`
+	AcknowledgedAt  *time.Time        `json:"acknowledged_at,omitempty"`
+	CustomerComment string            `json:"customer_comment,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+	Items           []OrderIntakeItem `json:"items" gorm:"foreignKey:IntakeID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName returns the table name for OrderIntake
+func (OrderIntake) TableName() string {
+	return "order_intakes"
+}
+
+// OrderIntakeItem is the recorded state of one checklist item
+type OrderIntakeItem struct {
+	ID       uuid.UUID      `json:"-" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	IntakeID uuid.UUID      `json:"-" gorm:"type:uuid;not null;index"`
+	Key      string         `json:"key" gorm:"type:varchar(50);not null"`
+	Label    string         `json:"label" gorm:"not null"`
+	Kind     IntakeItemKind `json:"kind" gorm:"type:varchar(20);not null"`
+	Result   IntakeResult   `json:"result" gorm:"type:varchar(20);not null"`
+	Note     string         `json:"note,omitempty"`
+	Position int            `json:"position" gorm:"not null;default:0"`
+}
+
+// TableName returns the table name for OrderIntakeItem
+func (OrderIntakeItem) TableName() string {
+	return "order_intake_items"
+}
+
+// IntakeItemRequest represents the recorded state of one checklist item
+type IntakeItemRequest struct {
+	Key    string       `json:"key" validate:"required,max=50"`
+	Result IntakeResult `json:"result" validate:"required,oneof=ok damaged not_working not_applicable yes no"`
+	Note   string       `json:"note,omitempty" validate:"max=500"`
+}
+
+// OrderIntakeRequest represents the request payload for filling out the intake checklist
+type OrderIntakeRequest struct {
+	Items []IntakeItemRequest `json:"items" validate:"required,min=1,max=50,dive"`
+	Notes string              `json:"notes,omitempty" validate:"max=1000"`
+}
+
+// AcknowledgeIntakeRequest represents the customer's acknowledgement of the intake
+type AcknowledgeIntakeRequest struct {
+	Comment string `json:"comment,omitempty" validate:"max=1000"`
+}
+
+// OrderIntakeResponse represents the intake of an order, or the checklist to fill out
+// when the intake has not been recorded yet
+type OrderIntakeResponse struct {
+	Intake    *OrderIntake     `json:"intake,omitempty"`
+	Checklist *IntakeChecklist `json:"checklist,omitempty"`
+}
+
+// JobTicket holds everything printed on the ticket that travels with a device
+type JobTicket struct {
+	OrderNumber   string       `json:"order_number"`
+	CreatedAt     time.Time    `json:"created_at"`
+	Status        OrderStatus  `json:"status"`
+	BranchName    string       `json:"branch_name"`
+	BranchAddress string       `json:"branch_address"`
+	BranchPhone   string       `json:"branch_phone"`
+	CustomerName  string       `json:"customer_name"`
+	CustomerPhone string       `json:"customer_phone"`
+	IPhoneModel   string       `json:"iphone_model"`
+	IPhoneColor   string       `json:"iphone_color"`
+	IPhoneIMEI    string       `json:"iphone_imei"`
+	ServiceType   ServiceType  `json:"service_type"`
+	Description   string       `json:"description"`
+	EstimatedCost float64      `json:"estimated_cost"`
+	Intake        *OrderIntake `json:"intake,omitempty"`
+	TrackingQR    string       `json:"tracking_qr,omitempty"` // PNG as a data URL
+	PrintedAt     time.Time    `json:"printed_at"`
+}
@@ -0,0 +1,137 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InvitationStatus represents the state of a staff invitation
+type InvitationStatus string
+
+const (
+	InvitationStatusPending  InvitationStatus = "pending"
+	InvitationStatusAccepted InvitationStatus = "accepted"
+	InvitationStatusRevoked  InvitationStatus = "revoked"
+	InvitationStatusExpired  InvitationStatus = "expired"
+)
+
+// StaffInvitation represents an invitation for a staff member to create their own account
+type StaffInvitation struct {
+	ID             uuid.UUID        `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Email          string           `json:"email,omitempty" gorm:"type:varchar(255);index"`
+	Phone          string           `json:"phone,omitempty" gorm:"type:varchar(50);index"`
+	FullName       string           `json:"full_name,omitempty"`
+	Role           UserRole         `json:"role" gorm:"type:varchar(50);not null"`
+	BranchID       *uuid.UUID       `json:"branch_id,omitempty" gorm:"type:uuid"`
+	Branch         *Branch          `json:"branch,omitempty" gorm:"foreignKey:BranchID"`
+	Status         InvitationStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	RequireMFA     bool             `json:"require_mfa" gorm:"default:false"`
+	TokenHash      string           `json:"-" gorm:"type:varchar(64);not null"` // hash of the nonce in the current link
+	MFASecret      string           `json:"-" gorm:"type:varchar(64)"`          // pending authenticator secret while MFA is being enrolled
+	ExpiresAt      time.Time        `json:"expires_at" gorm:"not null"`
+	InvitedBy      uuid.UUID        `json:"invited_by" gorm:"type:uuid;not null"`
+	SendCount      int              `json:"send_count" gorm:"default:0"`
+	LastSentAt     *time.Time       `json:"last_sent_at,omitempty"`
+	AcceptedAt     *time.Time       `json:"accepted_at,omitempty"`
+	AcceptedUserID *uuid.UUID       `json:"accepted_user_id,omitempty" gorm:"type:uuid"`
+	RevokedAt      *time.Time       `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt   `json:"-" gorm:"index"`
+}
+
+// TableName returns the table name for StaffInvitation
+func (StaffInvitation) TableName() string {
+	return "staff_invitations"
+}
+
+// IsPending reports whether the invitation can still be accepted at the given time
+func (i *StaffInvitation) IsPending(now time.Time) bool {
+	return i.Status == InvitationStatusPending && now.Before(i.ExpiresAt)
+}
+
+// EffectiveStatus returns the status, reporting pending invitations past their expiry as expired
+func (i *StaffInvitation) EffectiveStatus(now time.Time) InvitationStatus {
+	if i.Status == InvitationStatusPending && !now.Before(i.ExpiresAt) {
+		return InvitationStatusExpired
+	}
+	return i.Status
+}
+
+// InvitationRequest represents the request payload for inviting a staff member
+type InvitationRequest struct {
+	Email      string   `json:"email,omitempty" validate:"omitempty,email"`
+	Phone      string   `json:"phone,omitempty"`
+	FullName   string   `json:"full_name,omitempty"`
+	Role       UserRole `json:"role" validate:"required"`
+	BranchID   *string  `json:"branch_id,omitempty"`
+	RequireMFA bool     `json:"require_mfa,omitempty"`
+}
+
+// AcceptInvitationRequest represents the request payload for accepting an invitation.
+// Email or phone are only required when the invitation was not sent to one.
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8"`
+	FullName string `json:"full_name,omitempty"`
+	Email    string `json:"email,omitempty" validate:"omitempty,email"`
+	Phone    string `json:"phone,omitempty"`
+	MFACode  string `json:"mfa_code,omitempty"`
+}
+
+// InvitationResponse represents the response payload for invitation data
+type InvitationResponse struct {
+	ID             uuid.UUID        `json:"id"`
+	Email          string           `json:"email,omitempty"`
+	Phone          string           `json:"phone,omitempty"`
+	FullName       string           `json:"full_name,omitempty"`
+	Role           UserRole         `json:"role"`
+	BranchID       *uuid.UUID       `json:"branch_id,omitempty"`
+	Status         InvitationStatus `json:"status"`
+	RequireMFA     bool             `json:"require_mfa"`
+	ExpiresAt      time.Time        `json:"expires_at"`
+	InvitedBy      uuid.UUID        `json:"invited_by"`
+	SendCount      int              `json:"send_count"`
+	LastSentAt     *time.Time       `json:"last_sent_at,omitempty"`
+	AcceptedAt     *time.Time       `json:"accepted_at,omitempty"`
+	AcceptedUserID *uuid.UUID       `json:"accepted_user_id,omitempty"`
+	RevokedAt      *time.Time       `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
+
+// InvitationDetailsResponse is returned to the invitee when opening an invite link
+type InvitationDetailsResponse struct {
+	Email           string     `json:"email,omitempty"`
+	Phone           string     `json:"phone,omitempty"`
+	FullName        string     `json:"full_name,omitempty"`
+	Role            UserRole   `json:"role"`
+	BranchID        *uuid.UUID `json:"branch_id,omitempty"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	RequireMFA      bool       `json:"require_mfa"`
+	MFASecret       string     `json:"mfa_secret,omitempty"`
+	MFAProvisioning string     `json:"mfa_provisioning_uri,omitempty"`
+}
+
+// ToResponse converts StaffInvitation to InvitationResponse
+func (i *StaffInvitation) ToResponse() InvitationResponse {
+	return InvitationResponse{
+		ID:             i.ID,
+		Email:          i.Email,
+		Phone:          i.Phone,
+		FullName:       i.FullName,
+		Role:           i.Role,
+		BranchID:       i.BranchID,
+		Status:         i.EffectiveStatus(time.Now()),
+		RequireMFA:     i.RequireMFA,
+		ExpiresAt:      i.ExpiresAt,
+		InvitedBy:      i.InvitedBy,
+		SendCount:      i.SendCount,
+		LastSentAt:     i.LastSentAt,
+		AcceptedAt:     i.AcceptedAt,
+		AcceptedUserID: i.AcceptedUserID,
+		RevokedAt:      i.RevokedAt,
+		CreatedAt:      i.CreatedAt,
+	}
+}
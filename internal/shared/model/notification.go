@@ -38,6 +38,7 @@ const (
 	NotificationStatusPending NotificationStatus = "pending"
 	NotificationStatusSent    NotificationStatus = "sent"
 	NotificationStatusFailed  NotificationStatus = "failed"
+	NotificationStatusSkipped NotificationStatus = "skipped" // recipient contact is not verified
 )
 
 // Notification represents a notification in the system
@@ -0,0 +1,138 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NumberingKind identifies a numbered document
+type NumberingKind string
+
+const (
+	NumberingKindOrder   NumberingKind = "order"
+	NumberingKindInvoice NumberingKind = "invoice"
+)
+
+// IsValid reports whether the kind is known
+func (k NumberingKind) IsValid() bool {
+	return k == NumberingKindOrder || k == NumberingKindInvoice
+}
+
+// Default number formats, used until a branch configures its own
+const (
+	DefaultOrderNumberFormat   = "{branch}-{yyyy}-{seq:6}"
+	DefaultInvoiceNumberFormat = "{branch}-INV-{yyyy}-{seq:6}"
+)
+
+// NumberingFormat is the number format a branch uses for a kind of document
+type NumberingFormat struct {
+	ID        uuid.UUID     `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	BranchID  uuid.UUID     `json:"branch_id" gorm:"type:uuid;not null;uniqueIndex:idx_numbering_formats_branch_kind"`
+	Kind      NumberingKind `json:"kind" gorm:"type:varchar(20);not null;uniqueIndex:idx_numbering_formats_branch_kind"`
+	Format    string        `json:"format" gorm:"type:varchar(60);not null"`
+	UpdatedBy uuid.UUID     `json:"updated_by" gorm:"type:uuid;not null"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// TableName returns the table name for NumberingFormat
+func (NumberingFormat) TableName() string {
+	return "numbering_formats"
+}
+
+// NumberSequence is the counter numbers of a kind are taken from, per branch and year.
+// A new year starts a new row, which resets the sequence.
+type NumberSequence struct {
+	BranchID  uuid.UUID     `json:"branch_id" gorm:"type:uuid;primaryKey"`
+	Kind      NumberingKind `json:"kind" gorm:"type:varchar(20);primaryKey"`
+	Year      int           `json:"year" gorm:"primaryKey;autoIncrement:false"`
+	LastValue int64         `json:"last_value" gorm:"not null;default:0"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// TableName returns the table name for NumberSequence
+func (NumberSequence) TableName() string {
+	return "number_sequences"
+}
+
+// NumberingFormatRequest represents the request payload for setting the number format of a branch
+type NumberingFormatRequest struct {
+	Format string `json:"format" validate:"required,max=60"`
+}
+
+// NumberingFormatResponse represents the number format of a branch with the next number it gives out
+type NumberingFormatResponse struct {
+	Kind       NumberingKind `json:"kind"`
+	Format     string        `json:"format"`
+	IsDefault  bool          `json:"is_default"`
+	NextNumber string        `json:"next_number"`
+}
+
+// NumberScheme is everything needed to render the numbers of a kind for a branch
+type NumberScheme struct {
+	BranchID   uuid.UUID
+	Kind       NumberingKind
+	BranchCode string
+	Format     string
+}
+
+// numberToken matches the placeholders of a number format
+var numberToken = regexp.MustCompile(`\{([a-z]+)(?::(\d+))?\}`)
+
+// numberLiteral lists the characters allowed around the placeholders
+var numberLiteral = regexp.MustCompile(`^[A-Za-z0-9/._-]*$`)
+
+// ValidateNumberFormat checks that a format only uses known placeholders and holds the
+// branch, the year and the sequence, so that numbers stay unique across branches and years.
+// Supported placeholders are {branch}, {yyyy}, {yy} and {seq} or {seq:N} to pad to N digits.
+func ValidateNumberFormat(format string) error {
+	var hasBranch, hasYear, hasSeq bool
+	for _, match := range numberToken.FindAllStringSubmatch(format, -1) {
+		if match[1] != "seq" && match[2] != "" {
+			return ErrInvalidNumberFormat
+		}
+		switch match[1] {
+		case "branch":
+			hasBranch = true
+		case "yyyy", "yy":
+			hasYear = true
+		case "seq":
+			if width, _ := strconv.Atoi(match[2]); match[2] != "" && (width < 1 || width > 12) {
+				return ErrInvalidNumberFormat
+			}
+			hasSeq = true
+		default:
+			return ErrInvalidNumberFormat
+		}
+	}
+	if !hasBranch || !hasYear || !hasSeq {
+		return ErrInvalidNumberFormat
+	}
+	if !numberLiteral.MatchString(numberToken.ReplaceAllString(format, "")) {
+		return ErrInvalidNumberFormat
+	}
+	return nil
+}
+
+// Render returns the number with the given sequence value, issued in the given year
+func (s *NumberScheme) Render(year int, seq int64) string {
+	return numberToken.ReplaceAllStringFunc(s.Format, func(token string) string {
+		match := numberToken.FindStringSubmatch(token)
+		switch match[1] {
+		case "branch":
+			return s.BranchCode
+		case "yyyy":
+			return fmt.Sprintf("%04d", year)
+		case "yy":
+			return fmt.Sprintf("%02d", year%100)
+		case "seq":
+			width, _ := strconv.Atoi(match[2])
+			return fmt.Sprintf("%0*d", width, seq)
+		}
+		return token
+	})
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateNumberFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		wantErr error
+	}{
+		{"default order format", DefaultOrderNumberFormat, nil},
+		{"default invoice format", DefaultInvoiceNumberFormat, nil},
+		{"two digit year", "{branch}/INV/{yy}/{seq}", nil},
+		{"widest padding", "{branch}.{yyyy}.{seq:12}", nil},
+		{"placeholders only", "{branch}{yy}{seq:4}", nil},
+		{"missing year", "{branch}-{seq}", ErrInvalidNumberFormat},
+		{"missing branch", "{yyyy}-{seq:6}", ErrInvalidNumberFormat},
+		{"missing sequence", "{branch}-{yyyy}", ErrInvalidNumberFormat},
+		{"zero padding", "{branch}-{yyyy}-{seq:0}", ErrInvalidNumberFormat},
+		{"padding too wide", "{branch}-{yyyy}-{seq:13}", ErrInvalidNumberFormat},
+		{"width on another placeholder", "{branch:2}-{yyyy}-{seq}", ErrInvalidNumberFormat},
+		{"unknown placeholder", "{branch}-{yyyy}-{foo}-{seq}", ErrInvalidNumberFormat},
+		{"upper case placeholder", "{BRANCH}-{branch}-{yyyy}-{seq}", ErrInvalidNumberFormat},
+		{"spaces", "{branch} {yyyy} {seq}", ErrInvalidNumberFormat},
+		{"unclosed brace", "{branch}-{yyyy}-{seq", ErrInvalidNumberFormat},
+		{"empty", "", ErrInvalidNumberFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, ValidateNumberFormat(tt.format))
+		})
+	}
+}
+
+func TestNumberSchemeRender(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		year   int
+		seq    int64
+		want   string
+	}{
+		{"default order format", DefaultOrderNumberFormat, 2026, 123, "JKT01-2026-000123"},
+		{"default invoice format", DefaultInvoiceNumberFormat, 2026, 1, "JKT01-INV-2026-000001"},
+		{"unpadded sequence", "{branch}/INV/{yy}/{seq}", 2026, 7, "JKT01/INV/26/7"},
+		{"sequence wider than the padding", "{branch}-{yyyy}-{seq:3}", 2026, 12345, "JKT01-2026-12345"},
+		{"two digit year keeps its zero", "{branch}-{yy}-{seq:2}", 2009, 5, "JKT01-09-05"},
+		{"repeated placeholder", "{branch}-{seq:2}-{branch}-{yyyy}", 2026, 3, "JKT01-03-JKT01-2026"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := &NumberScheme{BranchCode: "JKT01", Kind: NumberingKindOrder, Format: tt.format}
+			assert.Equal(t, tt.want, scheme.Render(tt.year, tt.seq))
+		})
+	}
+}
@@ -67,6 +67,23 @@ type ServiceOrder struct {
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 	DeletedAt         gorm.DeletedAt `gorm:"index"`
+
+	// Registered device of the customer; the iPhone fields above keep a snapshot of it
+	DeviceID *uuid.UUID `gorm:"type:uuid;index"`
+
+	// When the customer can hand over and receive the device; empty means any time
+	PickupWindowStart   *time.Time
+	PickupWindowEnd     *time.Time
+	DeliveryWindowStart *time.Time
+	DeliveryWindowEnd   *time.Time
+
+	// Corporate work order the device is a line of; such orders are billed on the
+	// account's monthly invoice instead of being paid one by one
+	WorkOrderID        *uuid.UUID `gorm:"type:uuid;index"`
+	CorporateAccountID *uuid.UUID `gorm:"type:uuid;index"`
+
+	// Raised on every write; an update only applies to the version it was read at
+	Version int64 `gorm:"not null;default:1"`
 }
 
 func (ServiceOrder) TableName() string {
@@ -81,10 +98,10 @@ func (so *ServiceOrder) SetAliasFields() {
 }
 
 type ServiceOrderRequest struct {
-	IPhoneModel       string      `json:"iphone_model" validate:"required"`
-	IPhoneColor       string      `json:"iphone_color" validate:"required"`
-	IPhoneIMEI        string      `json:"iphone_imei" validate:"required"`
-	IPhoneType        string      `json:"iphone_type" validate:"required"`
+	IPhoneModel       string      `json:"iphone_model" validate:"required_without=DeviceID"`
+	IPhoneColor       string      `json:"iphone_color" validate:"required_without=DeviceID"`
+	IPhoneIMEI        string      `json:"iphone_imei" validate:"required_without=DeviceID"`
+	IPhoneType        string      `json:"iphone_type" validate:"required_without=DeviceID"`
 	ServiceType       ServiceType `json:"service_type" validate:"required"`
 	Description       string      `json:"description" validate:"required"`
 	Complaint         string      `json:"complaint" validate:"required"`
@@ -95,6 +112,13 @@ type ServiceOrderRequest struct {
 	EstimatedCost     float64     `json:"estimated_cost"`
 	EstimatedDuration int         `json:"estimated_duration"`
 	BranchID          string      `json:"branch_id" validate:"required"`
+
+	// Registered device to service; when set the iPhone fields are taken from it
+	DeviceID string `json:"device_id,omitempty" validate:"omitempty,uuid"`
+
+	// Optional time window for the courier pickup
+	PickupWindowStart *time.Time `json:"pickup_window_start,omitempty"`
+	PickupWindowEnd   *time.Time `json:"pickup_window_end,omitempty"`
 }
 
 type ServiceOrderResponse struct {
@@ -127,6 +151,18 @@ type ServiceOrderResponse struct {
 	Notes             string         `json:"notes,omitempty"`
 	CreatedAt         time.Time      `json:"created_at"`
 	UpdatedAt         time.Time      `json:"updated_at"`
+
+	DeviceID *uuid.UUID `json:"device_id,omitempty"`
+
+	PickupWindowStart   *time.Time `json:"pickup_window_start,omitempty"`
+	PickupWindowEnd     *time.Time `json:"pickup_window_end,omitempty"`
+	DeliveryWindowStart *time.Time `json:"delivery_window_start,omitempty"`
+	DeliveryWindowEnd   *time.Time `json:"delivery_window_end,omitempty"`
+
+	WorkOrderID        *uuid.UUID `json:"work_order_id,omitempty"`
+	CorporateAccountID *uuid.UUID `json:"corporate_account_id,omitempty"`
+
+	Version int64 `json:"version"`
 }
 
 type UpdateOrderStatusRequest struct {
@@ -161,6 +197,17 @@ func (so *ServiceOrder) ToResponse() ServiceOrderResponse {
 		Notes:             so.Notes,
 		CreatedAt:         so.CreatedAt,
 		UpdatedAt:         so.UpdatedAt,
+		DeviceID:          so.DeviceID,
+
+		PickupWindowStart:   so.PickupWindowStart,
+		PickupWindowEnd:     so.PickupWindowEnd,
+		DeliveryWindowStart: so.DeliveryWindowStart,
+		DeliveryWindowEnd:   so.DeliveryWindowEnd,
+
+		WorkOrderID:        so.WorkOrderID,
+		CorporateAccountID: so.CorporateAccountID,
+
+		Version: so.Version,
 	}
 
 	// opsional: isi relasi jika sudah dipreload
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MediaStage is the stage of an order a photo was taken in
+type MediaStage string
+
+const (
+	MediaStagePickup   MediaStage = "pickup"   // condition when collected from the customer
+	MediaStageService  MediaStage = "service"  // damage, internals and repair work at the branch
+	MediaStageDelivery MediaStage = "delivery" // condition when returned to the customer
+)
+
+// MediaVisibility tells who can see a photo of an order
+type MediaVisibility string
+
+const (
+	MediaVisibilityInternal MediaVisibility = "internal" // staff only
+	MediaVisibilityCustomer MediaVisibility = "customer" // also shown to the customer
+)
+
+// OrderMedia is a photo in the gallery of an order
+type OrderMedia struct {
+	ID          uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderID     uuid.UUID       `json:"order_id" gorm:"type:uuid;not null;index:idx_order_media_order_stage"`
+	Stage       MediaStage      `json:"stage" gorm:"type:varchar(20);not null;index:idx_order_media_order_stage"`
+	ObjectName  string          `json:"-" gorm:"not null"`
+	ContentType string          `json:"content_type"`
+	Size        int64           `json:"size"`
+	Caption     string          `json:"caption,omitempty"`
+	Visibility  MediaVisibility `json:"visibility" gorm:"type:varchar(20);not null;default:'internal'"`
+	Position    int             `json:"position" gorm:"not null;default:0"` // order within the stage
+	TakenAt     *time.Time      `json:"taken_at,omitempty"`                 // from the photo's EXIF data
+	UploadedBy  uuid.UUID       `json:"uploaded_by" gorm:"type:uuid;not null"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt  `json:"-" gorm:"index"`
+}
+
+// TableName returns the table name for OrderMedia
+func (OrderMedia) TableName() string {
+	return "order_media"
+}
+
+// UploadOrderMediaRequest represents the form fields sent with a batch of photos
+type UploadOrderMediaRequest struct {
+	Stage      MediaStage      `form:"stage" validate:"required,oneof=pickup service delivery"`
+	Caption    string          `form:"caption" validate:"max=500"`
+	Visibility MediaVisibility `form:"visibility" validate:"omitempty,oneof=internal customer"`
+}
+
+// UpdateOrderMediaRequest represents the request payload for editing a photo
+type UpdateOrderMediaRequest struct {
+	Caption    *string          `json:"caption,omitempty" validate:"omitempty,max=500"`
+	Visibility *MediaVisibility `json:"visibility,omitempty" validate:"omitempty,oneof=internal customer"`
+}
+
+// ReorderOrderMediaRequest represents the request payload for ordering the photos of a stage
+type ReorderOrderMediaRequest struct {
+	Stage    MediaStage `json:"stage" validate:"required,oneof=pickup service delivery"`
+	MediaIDs []string   `json:"media_ids" validate:"required,min=1,max=200,dive,uuid"`
+}
+
+// OrderMediaResponse represents a photo with a temporary link to it
+type OrderMediaResponse struct {
+	OrderMedia
+	URL string `json:"url,omitempty"`
+}
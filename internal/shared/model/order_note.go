@@ -0,0 +1,77 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OrderNote is an internal note on an order. Notes are only visible to staff and are kept
+// apart from the chat with the customer.
+type OrderNote struct {
+	ID         uuid.UUID          `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderID    uuid.UUID          `json:"order_id" gorm:"type:uuid;not null;index"`
+	AuthorID   uuid.UUID          `json:"author_id" gorm:"type:uuid;not null"`
+	AuthorName string             `json:"author_name" gorm:"not null"`
+	AuthorRole UserRole           `json:"author_role" gorm:"type:varchar(50);not null"`
+	Body       string             `json:"body" gorm:"type:text;not null"`
+	Pinned     bool               `json:"pinned" gorm:"default:false"`
+	PinnedBy   *uuid.UUID         `json:"pinned_by,omitempty" gorm:"type:uuid"`
+	PinnedAt   *time.Time         `json:"pinned_at,omitempty"`
+	Mentions   []OrderNoteMention `json:"mentions" gorm:"foreignKey:NoteID;constraint:OnDelete:CASCADE"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt     `json:"-" gorm:"index"`
+}
+
+// TableName returns the table name for OrderNote
+func (OrderNote) TableName() string {
+	return "order_notes"
+}
+
+// OrderNoteMention is a staff user mentioned in a note
+type OrderNoteMention struct {
+	ID     uuid.UUID `json:"-" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	NoteID uuid.UUID `json:"-" gorm:"type:uuid;not null;index"`
+	UserID uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name   string    `json:"name" gorm:"not null"`
+}
+
+// TableName returns the table name for OrderNoteMention
+func (OrderNoteMention) TableName() string {
+	return "order_note_mentions"
+}
+
+// OrderNoteRequest represents the request payload for adding a note. Mentions are the
+// IDs of the staff picked while typing @ in the note.
+type OrderNoteRequest struct {
+	Body       string   `json:"body" validate:"required,max=2000"`
+	MentionIDs []string `json:"mention_ids,omitempty" validate:"max=20,dive,uuid"`
+}
+
+// OrderHistoryEntryKind tells what an order history entry records
+type OrderHistoryEntryKind string
+
+const (
+	OrderHistoryStatus OrderHistoryEntryKind = "status"
+	OrderHistoryNote   OrderHistoryEntryKind = "note"
+)
+
+// OrderHistoryEntry is one status change or internal note in the history of an order
+type OrderHistoryEntry struct {
+	Kind   OrderHistoryEntryKind `json:"kind"`
+	At     time.Time             `json:"at"`
+	Status OrderStatus           `json:"status,omitempty"`
+	Note   *OrderNote            `json:"note,omitempty"`
+}
+
+// OrderHistoryResponse represents the staff view of an order's history: pinned notes
+// first, then status changes and notes by time
+type OrderHistoryResponse struct {
+	OrderID     uuid.UUID           `json:"order_id"`
+	OrderNumber string              `json:"order_number"`
+	Status      OrderStatus         `json:"status"`
+	Pinned      []*OrderNote        `json:"pinned"`
+	Entries     []OrderHistoryEntry `json:"entries"`
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderPart records a spare part installed in a device during an order.
+// Part code, name and price are copied so the record survives inventory changes.
+type OrderPart struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderID     uuid.UUID  `json:"order_id" gorm:"type:uuid;not null;index"`
+	SparePartID uuid.UUID  `json:"spare_part_id" gorm:"type:uuid;not null"`
+	PartCode    string     `json:"part_code" gorm:"not null"`
+	PartName    string     `json:"part_name" gorm:"not null"`
+	Quantity    int        `json:"quantity" gorm:"not null"`
+	UnitPrice   int64      `json:"unit_price" gorm:"not null"` // Price in Rupiah
+	InstalledBy uuid.UUID  `json:"installed_by" gorm:"type:uuid;not null"`
+	ReleasedAt  *time.Time `json:"released_at,omitempty"` // put back into stock when the order was cancelled
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// TableName returns the table name for OrderPart
+func (OrderPart) TableName() string {
+	return "order_parts"
+}
+
+// OrderPartRequest represents the request payload for recording an installed part
+type OrderPartRequest struct {
+	SparePartID string `json:"spare_part_id" validate:"required,uuid"`
+	Quantity    int    `json:"quantity" validate:"required,min=1,max=20"`
+}
+
+// OrderPartResponse represents an installed part in API responses
+type OrderPartResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	SparePartID uuid.UUID  `json:"spare_part_id"`
+	PartCode    string     `json:"part_code"`
+	PartName    string     `json:"part_name"`
+	Quantity    int        `json:"quantity"`
+	UnitPrice   int64      `json:"unit_price"`
+	InstalledBy uuid.UUID  `json:"installed_by"`
+	ReleasedAt  *time.Time `json:"released_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ToResponse converts OrderPart to OrderPartResponse
+func (p *OrderPart) ToResponse() OrderPartResponse {
+	return OrderPartResponse{
+		ID:          p.ID,
+		SparePartID: p.SparePartID,
+		PartCode:    p.PartCode,
+		PartName:    p.PartName,
+		Quantity:    p.Quantity,
+		UnitPrice:   p.UnitPrice,
+		InstalledBy: p.InstalledBy,
+		ReleasedAt:  p.ReleasedAt,
+		CreatedAt:   p.CreatedAt,
+	}
+}
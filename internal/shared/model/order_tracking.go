@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderStatusEvent records when an order reached a status
+type OrderStatusEvent struct {
+	ID        uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderID   uuid.UUID   `json:"order_id" gorm:"type:uuid;not null;index"`
+	Status    OrderStatus `json:"status" gorm:"type:varchar(20);not null"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// TableName returns the table name for OrderStatusEvent
+func (OrderStatusEvent) TableName() string {
+	return "order_status_events"
+}
+
+// PublicTrackingRequest represents a tracking lookup by customers without an account
+type PublicTrackingRequest struct {
+	OrderNumber string `json:"order_number" validate:"required,max=50"`
+	PhoneLast4  string `json:"phone_last4" validate:"required,len=4,numeric"`
+}
+
+// PublicTrackingStep is one status the order has reached
+type PublicTrackingStep struct {
+	Status OrderStatus `json:"status"`
+	At     time.Time   `json:"at"`
+}
+
+// PublicTrackingResponse is what anyone holding the order number can see. It carries no
+// personal data: no names, contact details, addresses, IMEI or costs.
+type PublicTrackingResponse struct {
+	OrderNumber string               `json:"order_number"`
+	Status      OrderStatus          `json:"status"`
+	ServiceType ServiceType          `json:"service_type"`
+	IPhoneModel string               `json:"iphone_model"`
+	BranchName  string               `json:"branch_name,omitempty"`
+	BranchPhone string               `json:"branch_phone,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+	Timeline    []PublicTrackingStep `json:"timeline"`
+}
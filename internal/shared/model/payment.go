@@ -47,6 +47,7 @@ type Payment struct {
 	InvoiceURL    string         `json:"invoice_url,omitempty"` // URL to the invoice
 	PaidAt        *time.Time     `json:"paid_at,omitempty"`
 	Notes         string         `json:"notes,omitempty"`
+	Version       int64          `json:"version" gorm:"not null;default:1"` // raised on every write
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
@@ -77,6 +78,7 @@ type PaymentResponse struct {
 	InvoiceNumber string               `json:"invoice_number"`
 	PaidAt        *time.Time           `json:"paid_at,omitempty"`
 	Notes         string               `json:"notes,omitempty"`
+	Version       int64                `json:"version"`
 	CreatedAt     time.Time            `json:"created_at"`
 	UpdatedAt     time.Time            `json:"updated_at"`
 }
@@ -94,6 +96,7 @@ func (p *Payment) ToResponse() PaymentResponse {
 		InvoiceNumber: p.InvoiceNumber,
 		PaidAt:        p.PaidAt,
 		Notes:         p.Notes,
+		Version:       p.Version,
 		CreatedAt:     p.CreatedAt,
 		UpdatedAt:     p.UpdatedAt,
 	}
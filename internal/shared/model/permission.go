@@ -0,0 +1,249 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Permission represents a single fine-grained capability that can be granted to a role
+type Permission string
+
+const (
+	// Order permissions
+	PermissionOrderCreate       Permission = "order.create"
+	PermissionOrderView         Permission = "order.view"
+	PermissionOrderViewAll      Permission = "order.view_all"
+	PermissionOrderUpdate       Permission = "order.update"
+	PermissionOrderUpdateStatus Permission = "order.update_status"
+	PermissionOrderAssign       Permission = "order.assign"
+	PermissionOrderDelete       Permission = "order.delete"
+	PermissionOrderAcceptJob    Permission = "order.accept_job"
+	PermissionOrderWaiveFee     Permission = "order.waive_fee"
+
+	// Payment permissions
+	PermissionPaymentCreate  Permission = "payment.create"
+	PermissionPaymentProcess Permission = "payment.process"
+	PermissionPaymentView    Permission = "payment.view"
+	PermissionPaymentViewAll Permission = "payment.view_all"
+	PermissionPaymentUpdate  Permission = "payment.update"
+	PermissionPaymentRefund  Permission = "payment.refund"
+
+	// Notification permissions
+	PermissionNotificationSend Permission = "notification.send"
+
+	// File permissions
+	PermissionFileUpload Permission = "file.upload"
+	PermissionFileManage Permission = "file.manage"
+
+	// Chat permissions
+	PermissionChatAccess Permission = "chat.access"
+
+	// Dashboard and report permissions
+	PermissionDashboardView     Permission = "dashboard.view"
+	PermissionDashboardAdmin    Permission = "dashboard.admin"
+	PermissionReportView        Permission = "report.view"
+	PermissionReportViewRevenue Permission = "report.view_revenue"
+
+	// Administration permissions
+	PermissionBranchManage     Permission = "branch.manage"
+	PermissionUserView         Permission = "user.view"
+	PermissionUserManage       Permission = "user.manage"
+	PermissionMembershipManage Permission = "membership.manage"
+	PermissionRoleManage       Permission = "role.manage"
+	PermissionAPIKeyManage     Permission = "apikey.manage"
+	PermissionPrivacyManage    Permission = "privacy.manage"
+	PermissionCatalogManage    Permission = "catalog.manage"
+	PermissionSLAManage        Permission = "sla.manage"
+	PermissionCorporateManage  Permission = "corporate.manage"
+)
+
+// AllPermissions lists every permission known to the system
+var AllPermissions = []Permission{
+	PermissionOrderCreate,
+	PermissionOrderView,
+	PermissionOrderViewAll,
+	PermissionOrderUpdate,
+	PermissionOrderUpdateStatus,
+	PermissionOrderAssign,
+	PermissionOrderDelete,
+	PermissionOrderAcceptJob,
+	PermissionOrderWaiveFee,
+	PermissionPaymentCreate,
+	PermissionPaymentProcess,
+	PermissionPaymentView,
+	PermissionPaymentViewAll,
+	PermissionPaymentUpdate,
+	PermissionPaymentRefund,
+	PermissionNotificationSend,
+	PermissionFileUpload,
+	PermissionFileManage,
+	PermissionChatAccess,
+	PermissionDashboardView,
+	PermissionDashboardAdmin,
+	PermissionReportView,
+	PermissionReportViewRevenue,
+	PermissionBranchManage,
+	PermissionUserView,
+	PermissionUserManage,
+	PermissionMembershipManage,
+	PermissionRoleManage,
+	PermissionAPIKeyManage,
+	PermissionPrivacyManage,
+	PermissionCatalogManage,
+	PermissionSLAManage,
+	PermissionCorporateManage,
+}
+
+// IsValidPermission checks whether the permission is known to the system
+func IsValidPermission(p Permission) bool {
+	for _, known := range AllPermissions {
+		if known == p {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultRolePermissions contains the built-in role to permission mappings.
+// They are seeded into the database on migration and used as a fallback when
+// the database is not available.
+var DefaultRolePermissions = map[UserRole][]Permission{
+	RoleAdminPusat: AllPermissions,
+	RoleAdminCabang: {
+		PermissionOrderCreate,
+		PermissionOrderView,
+		PermissionOrderViewAll,
+		PermissionOrderUpdate,
+		PermissionOrderUpdateStatus,
+		PermissionOrderAssign,
+		PermissionOrderDelete,
+		PermissionOrderWaiveFee,
+		PermissionPaymentCreate,
+		PermissionPaymentProcess,
+		PermissionPaymentView,
+		PermissionPaymentViewAll,
+		PermissionPaymentUpdate,
+		PermissionPaymentRefund,
+		PermissionNotificationSend,
+		PermissionFileUpload,
+		PermissionFileManage,
+		PermissionChatAccess,
+		PermissionDashboardView,
+		PermissionDashboardAdmin,
+		PermissionReportView,
+		PermissionReportViewRevenue,
+		PermissionBranchManage,
+		PermissionUserView,
+		PermissionUserManage,
+		PermissionMembershipManage,
+	},
+	RoleKasir: {
+		PermissionOrderCreate,
+		PermissionOrderView,
+		PermissionOrderViewAll,
+		PermissionOrderUpdateStatus,
+		PermissionOrderWaiveFee,
+		PermissionPaymentCreate,
+		PermissionPaymentProcess,
+		PermissionPaymentView,
+		PermissionNotificationSend,
+		PermissionFileUpload,
+		PermissionChatAccess,
+		PermissionDashboardView,
+		PermissionReportView,
+	},
+	RoleTeknisi: {
+		PermissionOrderView,
+		PermissionOrderUpdateStatus,
+		PermissionFileUpload,
+		PermissionChatAccess,
+	},
+	RoleKurir: {
+		PermissionOrderView,
+		PermissionOrderUpdateStatus,
+		PermissionOrderAcceptJob,
+		PermissionFileUpload,
+		PermissionChatAccess,
+	},
+	RolePelanggan: {
+		PermissionOrderCreate,
+		PermissionOrderView,
+		PermissionPaymentCreate,
+		PermissionPaymentProcess,
+		PermissionPaymentView,
+		PermissionFileUpload,
+		PermissionChatAccess,
+	},
+}
+
+// Role represents a role definition with its granted permissions
+type Role struct {
+	ID          uuid.UUID        `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Name        UserRole         `json:"name" gorm:"type:varchar(50);uniqueIndex;not null"`
+	Description string           `json:"description,omitempty" gorm:"type:text"`
+	IsSystem    bool             `json:"is_system" gorm:"default:false"` // built-in roles cannot be deleted
+	Permissions []RolePermission `json:"permissions,omitempty" gorm:"foreignKey:RoleID"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt   `json:"-" gorm:"index"`
+}
+
+// TableName returns the table name for Role
+func (Role) TableName() string {
+	return "roles"
+}
+
+// RolePermission represents a permission granted to a role
+type RolePermission struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	RoleID     uuid.UUID  `json:"role_id" gorm:"type:uuid;not null;uniqueIndex:idx_role_permission"`
+	Permission Permission `json:"permission" gorm:"type:varchar(100);not null;uniqueIndex:idx_role_permission"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TableName returns the table name for RolePermission
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
+
+// RoleRequest represents the request payload for creating or updating a role
+type RoleRequest struct {
+	Name        string   `json:"name" validate:"required,min=3,max=50"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions" validate:"required,min=1"`
+}
+
+// AssignRoleRequest represents the request payload for assigning a role to a user
+type AssignRoleRequest struct {
+	Role string `json:"role" validate:"required"`
+}
+
+// RoleResponse represents the response payload for role data
+type RoleResponse struct {
+	ID          uuid.UUID    `json:"id"`
+	Name        UserRole     `json:"name"`
+	Description string       `json:"description,omitempty"`
+	IsSystem    bool         `json:"is_system"`
+	Permissions []Permission `json:"permissions"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// ToResponse converts Role to RoleResponse
+func (r *Role) ToResponse() RoleResponse {
+	permissions := make([]Permission, 0, len(r.Permissions))
+	for _, rp := range r.Permissions {
+		permissions = append(permissions, rp.Permission)
+	}
+	return RoleResponse{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		IsSystem:    r.IsSystem,
+		Permissions: permissions,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
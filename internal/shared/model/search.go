@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SearchHighlightStart and SearchHighlightStop mark the matched parts of highlighted fields
+const (
+	SearchHighlightStart = "<mark>"
+	SearchHighlightStop  = "</mark>"
+)
+
+// OrderSearchResult represents an order matching a search, best match first. Highlights
+// holds the matched fields keyed by their JSON name, with the matches marked.
+type OrderSearchResult struct {
+	ID            uuid.UUID         `json:"id"`
+	OrderNumber   string            `json:"order_number"`
+	InvoiceNumber string            `json:"invoice_number,omitempty"`
+	BranchID      uuid.UUID         `json:"branch_id"`
+	Status        OrderStatus       `json:"status"`
+	ServiceType   ServiceType       `json:"service_type"`
+	IPhoneModel   string            `json:"iphone_model"`
+	IPhoneIMEI    string            `json:"iphone_imei"`
+	CustomerID    uuid.UUID         `json:"customer_id"`
+	CustomerName  string            `json:"customer_name"`
+	CustomerPhone string            `json:"customer_phone"`
+	CustomerEmail string            `json:"customer_email"`
+	Description   string            `json:"description"`
+	Rank          float64           `json:"rank"`
+	Highlights    map[string]string `json:"highlights" gorm:"-"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+// UserSearchResult represents a user matching a global search
+type UserSearchResult struct {
+	ID         uuid.UUID         `json:"id"`
+	FullName   string            `json:"full_name"`
+	Email      string            `json:"email"`
+	Phone      string            `json:"phone"`
+	Role       UserRole          `json:"role"`
+	BranchID   *uuid.UUID        `json:"branch_id,omitempty"`
+	IsActive   bool              `json:"is_active"`
+	Rank       float64           `json:"rank"`
+	Highlights map[string]string `json:"highlights" gorm:"-"`
+}
+
+// BranchSearchResult represents a branch matching a global search
+type BranchSearchResult struct {
+	ID         uuid.UUID         `json:"id"`
+	Name       string            `json:"name"`
+	Address    string            `json:"address"`
+	City       string            `json:"city"`
+	Province   string            `json:"province"`
+	Phone      string            `json:"phone"`
+	IsActive   bool              `json:"is_active"`
+	Rank       float64           `json:"rank"`
+	Highlights map[string]string `json:"highlights" gorm:"-"`
+}
+
+// GlobalSearchResponse represents the best matching orders, users and branches
+type GlobalSearchResponse struct {
+	Query    string               `json:"query"`
+	Orders   []OrderSearchResult  `json:"orders"`
+	Users    []UserSearchResult   `json:"users"`
+	Branches []BranchSearchResult `json:"branches"`
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SigningKeyStatus represents the lifecycle state of a JWT signing key
+type SigningKeyStatus string
+
+const (
+	SigningKeyPending SigningKeyStatus = "pending" // published in the JWKS, not yet used for signing
+	SigningKeyActive  SigningKeyStatus = "active"  // used to sign new tokens
+	SigningKeyRetired SigningKeyStatus = "retired" // only verifies tokens issued before rotation
+)
+
+// SigningKeyAlgorithm is the JWS algorithm used by rotating signing keys
+const SigningKeyAlgorithm = "EdDSA"
+
+// SigningKey represents an asymmetric key pair used to sign JWTs
+type SigningKey struct {
+	ID          uuid.UUID        `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Kid         string           `json:"kid" gorm:"type:varchar(64);uniqueIndex;not null"`
+	Algorithm   string           `json:"algorithm" gorm:"type:varchar(20);not null"`
+	PublicKey   string           `json:"public_key" gorm:"type:text;not null"` // base64url encoded
+	PrivateKey  string           `json:"-" gorm:"type:text;not null"`          // encrypted, base64 encoded
+	Status      SigningKeyStatus `json:"status" gorm:"type:varchar(20);not null;index"`
+	ActivatedAt *time.Time       `json:"activated_at,omitempty"`
+	RetiredAt   *time.Time       `json:"retired_at,omitempty"`
+	ExpiresAt   *time.Time       `json:"expires_at,omitempty"` // retired keys are removed once every token they signed has expired
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// TableName returns the table name for SigningKey
+func (SigningKey) TableName() string {
+	return "signing_keys"
+}
+
+// JWK represents a public key in JSON Web Key format (RFC 8037 for Ed25519)
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	X   string `json:"x"`
+}
+
+// JWKS represents a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// ToJWK converts the public part of the signing key to a JWK
+func (k *SigningKey) ToJWK() JWK {
+	return JWK{
+		Kty: "OKP",
+		Crv: "Ed25519",
+		Alg: k.Algorithm,
+		Use: "sig",
+		Kid: k.Kid,
+		X:   k.PublicKey,
+	}
+}
@@ -0,0 +1,222 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SLAStage is a part of an order's journey with its own SLA target
+type SLAStage string
+
+const (
+	SLAStagePickup   SLAStage = "pickup"   // from the order until the device is at the branch
+	SLAStageRepair   SLAStage = "repair"   // from arrival at the branch until the device is ready
+	SLAStageDelivery SLAStage = "delivery" // from ready until the device is back with the customer
+)
+
+// SLAStatus tells how a stage is doing against its target
+type SLAStatus string
+
+const (
+	SLAStatusOnTrack  SLAStatus = "on_track"
+	SLAStatusAtRisk   SLAStatus = "at_risk"
+	SLAStatusBreached SLAStatus = "breached"
+	SLAStatusMet      SLAStatus = "met"
+	SLAStatusMissed   SLAStatus = "missed"
+)
+
+// SLAStageFor returns the stage an order in the given status is in
+func SLAStageFor(status OrderStatus) (SLAStage, bool) {
+	switch status {
+	case StatusPendingPickup, StatusOnPickup:
+		return SLAStagePickup, true
+	case StatusInService:
+		return SLAStageRepair, true
+	case StatusReady:
+		return SLAStageDelivery, true
+	}
+	return "", false
+}
+
+// SLAPolicy sets the targets of each stage in business minutes. An empty service type or
+// membership tier matches any; the most specific active policy wins.
+type SLAPolicy struct {
+	ID              uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Name            string         `json:"name" gorm:"not null"`
+	ServiceType     ServiceType    `json:"service_type,omitempty" gorm:"type:varchar(50);not null;default:'';uniqueIndex:idx_sla_policies_scope"`
+	MembershipTier  MembershipTier `json:"membership_tier,omitempty" gorm:"type:varchar(20);not null;default:'';uniqueIndex:idx_sla_policies_scope"`
+	PickupMinutes   int            `json:"pickup_minutes" gorm:"not null"`
+	RepairMinutes   int            `json:"repair_minutes" gorm:"not null"`
+	DeliveryMinutes int            `json:"delivery_minutes" gorm:"not null"`
+	AtRiskPercent   int            `json:"at_risk_percent" gorm:"not null;default:80"` // share of the target after which the order is at risk
+	Priority        int            `json:"priority" gorm:"default:0"`                  // higher is handled first
+	IsActive        bool           `json:"is_active" gorm:"default:true"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+}
+
+// TableName returns the table name for SLAPolicy
+func (SLAPolicy) TableName() string {
+	return "sla_policies"
+}
+
+// StageMinutes returns the target of a stage in business minutes
+func (p *SLAPolicy) StageMinutes(stage SLAStage) int {
+	switch stage {
+	case SLAStagePickup:
+		return p.PickupMinutes
+	case SLAStageRepair:
+		return p.RepairMinutes
+	case SLAStageDelivery:
+		return p.DeliveryMinutes
+	}
+	return 0
+}
+
+// Matches reports whether the policy applies to an order of the service type for a
+// customer of the tier, and how specific the match is
+func (p *SLAPolicy) Matches(serviceType ServiceType, tier MembershipTier) (int, bool) {
+	if !p.IsActive {
+		return 0, false
+	}
+	if p.ServiceType != "" && p.ServiceType != serviceType {
+		return 0, false
+	}
+	if p.MembershipTier != "" && p.MembershipTier != tier {
+		return 0, false
+	}
+
+	specificity := 0
+	if p.ServiceType != "" {
+		specificity += 2
+	}
+	if p.MembershipTier != "" {
+		specificity++
+	}
+	return specificity, true
+}
+
+// SLAPolicyRequest represents the request payload for creating or updating an SLA policy
+type SLAPolicyRequest struct {
+	Name            string         `json:"name" validate:"required,max=100"`
+	ServiceType     ServiceType    `json:"service_type,omitempty" validate:"omitempty,oneof=screen_repair battery_replacement water_damage software_issue hardware_repair other"`
+	MembershipTier  MembershipTier `json:"membership_tier,omitempty" validate:"omitempty,oneof=basic premium vip elite"`
+	PickupMinutes   int            `json:"pickup_minutes" validate:"required,min=1"`
+	RepairMinutes   int            `json:"repair_minutes" validate:"required,min=1"`
+	DeliveryMinutes int            `json:"delivery_minutes" validate:"required,min=1"`
+	AtRiskPercent   int            `json:"at_risk_percent,omitempty" validate:"omitempty,min=1,max=99"`
+	Priority        int            `json:"priority" validate:"min=0,max=100"`
+	IsActive        *bool          `json:"is_active,omitempty"`
+}
+
+// BranchBusinessHours is a weekly opening of a branch. SLA targets only count time while
+// the branch is open. Times are in the configured timezone.
+type BranchBusinessHours struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	BranchID  uuid.UUID `json:"branch_id" gorm:"type:uuid;not null;index"`
+	Weekday   int       `json:"weekday" gorm:"not null"`                    // 0 = Sunday
+	OpenTime  string    `json:"open_time" gorm:"type:varchar(5);not null"`  // HH:MM
+	CloseTime string    `json:"close_time" gorm:"type:varchar(5);not null"` // HH:MM
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for BranchBusinessHours
+func (BranchBusinessHours) TableName() string {
+	return "branch_business_hours"
+}
+
+// BusinessHoursRequest represents one opening in a business hours update
+type BusinessHoursRequest struct {
+	Weekday   *int   `json:"weekday" validate:"required,min=0,max=6"`
+	OpenTime  string `json:"open_time" validate:"required,datetime=15:04"`
+	CloseTime string `json:"close_time" validate:"required,datetime=15:04"`
+}
+
+// BranchBusinessHoursRequest represents the request payload for replacing the business hours of a branch
+type BranchBusinessHoursRequest struct {
+	Hours []BusinessHoursRequest `json:"hours" validate:"dive"`
+}
+
+// OrderSLA tracks one stage of an order against its target
+type OrderSLA struct {
+	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderID          uuid.UUID  `json:"order_id" gorm:"type:uuid;not null;uniqueIndex:idx_order_slas_order_stage"`
+	Stage            SLAStage   `json:"stage" gorm:"type:varchar(20);not null;uniqueIndex:idx_order_slas_order_stage"`
+	BranchID         uuid.UUID  `json:"branch_id" gorm:"type:uuid;not null"`
+	PolicyID         *uuid.UUID `json:"policy_id,omitempty" gorm:"type:uuid"`
+	Priority         int        `json:"priority" gorm:"default:0"`
+	StartedAt        time.Time  `json:"started_at" gorm:"not null"`
+	RiskAt           time.Time  `json:"risk_at" gorm:"not null"`
+	DueAt            time.Time  `json:"due_at" gorm:"not null"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	AtRiskNotifiedAt *time.Time `json:"at_risk_notified_at,omitempty"`
+	BreachNotifiedAt *time.Time `json:"breach_notified_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// TableName returns the table name for OrderSLA
+func (OrderSLA) TableName() string {
+	return "order_slas"
+}
+
+// StatusAt tells how the stage is doing at the given moment
+func (s *OrderSLA) StatusAt(now time.Time) SLAStatus {
+	if s.CompletedAt != nil {
+		if s.CompletedAt.After(s.DueAt) {
+			return SLAStatusMissed
+		}
+		return SLAStatusMet
+	}
+	switch {
+	case !now.Before(s.DueAt):
+		return SLAStatusBreached
+	case !now.Before(s.RiskAt):
+		return SLAStatusAtRisk
+	}
+	return SLAStatusOnTrack
+}
+
+// OrderSLAStageResponse represents one stage of an order with its SLA status
+type OrderSLAStageResponse struct {
+	Stage            SLAStage   `json:"stage"`
+	Status           SLAStatus  `json:"status"`
+	StartedAt        time.Time  `json:"started_at"`
+	DueAt            time.Time  `json:"due_at"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	RemainingMinutes int        `json:"remaining_minutes"` // negative once breached; only for open stages
+}
+
+// StatusDuration is how long an order spent in one status
+type StatusDuration struct {
+	Status  OrderStatus `json:"status"`
+	Since   time.Time   `json:"since"`
+	Until   *time.Time  `json:"until,omitempty"` // empty while the order is still in the status
+	Minutes int         `json:"minutes"`
+}
+
+// OrderSLAResponse represents the SLA stages of an order and its time in each status
+type OrderSLAResponse struct {
+	OrderID      uuid.UUID               `json:"order_id"`
+	Status       OrderStatus             `json:"status"`
+	Priority     int                     `json:"priority"`
+	Stages       []OrderSLAStageResponse `json:"stages"`
+	TimeInStatus []StatusDuration        `json:"time_in_status"`
+}
+
+// AtRiskOrder represents an open order that is about to miss or has missed its SLA
+type AtRiskOrder struct {
+	OrderID          uuid.UUID   `json:"order_id"`
+	OrderNumber      string      `json:"order_number"`
+	BranchID         uuid.UUID   `json:"branch_id"`
+	Status           OrderStatus `json:"status"`
+	Stage            SLAStage    `json:"stage"`
+	SLAStatus        SLAStatus   `json:"sla_status"`
+	Priority         int         `json:"priority"`
+	DueAt            time.Time   `json:"due_at"`
+	RemainingMinutes int         `json:"remaining_minutes"`
+	TechnicianID     *uuid.UUID  `json:"technician_id,omitempty"`
+	CourierID        *uuid.UUID  `json:"courier_id,omitempty"`
+}
@@ -18,6 +18,7 @@ type SparePartInventory struct {
 	MinStock  int            `json:"min_stock" gorm:"default:5"` // Threshold untuk reorder
 	Price     int64          `json:"price" gorm:"not null"`      // Price in Rupiah
 	Supplier  string         `json:"supplier" gorm:"not null"`
+	Version   int64          `json:"version" gorm:"not null;default:1"` // raised on every write
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
@@ -62,6 +63,7 @@ type SparePartInventoryResponse struct {
 	Supplier     string         `json:"supplier"`
 	IsLowStock   bool           `json:"is_low_stock"`
 	NeedsReorder bool           `json:"needs_reorder"`
+	Version      int64          `json:"version"`
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 }
@@ -79,6 +81,7 @@ func (s *SparePartInventory) ToResponse() SparePartInventoryResponse {
 		Supplier:     s.Supplier,
 		IsLowStock:   s.IsLowStock(),
 		NeedsReorder: s.NeedsReorder(),
+		Version:      s.Version,
 		CreatedAt:    s.CreatedAt,
 		UpdatedAt:    s.UpdatedAt,
 	}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AssignmentStrategy decides how a technician is picked for an order
+type AssignmentStrategy string
+
+const (
+	AssignmentStrategyRoundRobin    AssignmentStrategy = "round_robin"    // the technician who waited longest for a job
+	AssignmentStrategyLeastLoaded   AssignmentStrategy = "least_loaded"   // the technician with the fewest open orders
+	AssignmentStrategySkillWeighted AssignmentStrategy = "skill_weighted" // a mix of skill level, load and rating
+	AssignmentStrategyManual        AssignmentStrategy = "manual"         // picked by staff, never chosen automatically
+)
+
+// IsValidAssignmentStrategy checks whether the strategy can be used for automatic assignment
+func IsValidAssignmentStrategy(s AssignmentStrategy) bool {
+	switch s {
+	case AssignmentStrategyRoundRobin, AssignmentStrategyLeastLoaded, AssignmentStrategySkillWeighted:
+		return true
+	}
+	return false
+}
+
+// TechnicianSkill is how well a technician handles a service type, from 1 (basic) to 5 (expert)
+type TechnicianSkill struct {
+	ID          uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID      uuid.UUID   `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_technician_skill"`
+	ServiceType ServiceType `json:"service_type" gorm:"type:varchar(50);not null;uniqueIndex:idx_technician_skill"`
+	Level       int         `json:"level" gorm:"not null;default:3;check:level >= 1 AND level <= 5"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// TableName returns the table name for TechnicianSkill
+func (TechnicianSkill) TableName() string {
+	return "technician_skills"
+}
+
+// TechnicianShift is a weekly working period of a technician. Times are in the configured timezone.
+type TechnicianShift struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Weekday   int       `json:"weekday" gorm:"not null"`                    // 0 = Sunday
+	StartTime string    `json:"start_time" gorm:"type:varchar(5);not null"` // HH:MM
+	EndTime   string    `json:"end_time" gorm:"type:varchar(5);not null"`   // HH:MM
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for TechnicianShift
+func (TechnicianShift) TableName() string {
+	return "technician_shifts"
+}
+
+// Covers reports whether the shift includes the moment t
+func (s *TechnicianShift) Covers(t time.Time) bool {
+	t = t.In(time.Local)
+	if int(t.Weekday()) != s.Weekday {
+		return false
+	}
+	clock := t.Format("15:04")
+	return clock >= s.StartTime && clock < s.EndTime
+}
+
+// TechnicianAssignment records who was assigned to an order and how
+type TechnicianAssignment struct {
+	ID           uuid.UUID          `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderID      uuid.UUID          `json:"order_id" gorm:"type:uuid;not null;index"`
+	TechnicianID uuid.UUID          `json:"technician_id" gorm:"type:uuid;not null;index"`
+	Strategy     AssignmentStrategy `json:"strategy" gorm:"type:varchar(20);not null"`
+	AssignedBy   *uuid.UUID         `json:"assigned_by,omitempty" gorm:"type:uuid"` // empty for automatic assignments
+	Score        float64            `json:"score"`
+	CreatedAt    time.Time          `json:"created_at"`
+}
+
+// TableName returns the table name for TechnicianAssignment
+func (TechnicianAssignment) TableName() string {
+	return "technician_assignments"
+}
+
+// TechnicianSkillRequest represents one skill in a skill update
+type TechnicianSkillRequest struct {
+	ServiceType ServiceType `json:"service_type" validate:"required,oneof=screen_repair battery_replacement water_damage software_issue hardware_repair other"`
+	Level       int         `json:"level" validate:"required,min=1,max=5"`
+}
+
+// TechnicianSkillsRequest represents the request payload for replacing the skills of a technician
+type TechnicianSkillsRequest struct {
+	Skills []TechnicianSkillRequest `json:"skills" validate:"dive"`
+}
+
+// TechnicianShiftRequest represents one shift in a shift update
+type TechnicianShiftRequest struct {
+	Weekday   *int   `json:"weekday" validate:"required,min=0,max=6"`
+	StartTime string `json:"start_time" validate:"required,datetime=15:04"`
+	EndTime   string `json:"end_time" validate:"required,datetime=15:04"`
+}
+
+// TechnicianShiftsRequest represents the request payload for replacing the shifts of a technician
+type TechnicianShiftsRequest struct {
+	Shifts []TechnicianShiftRequest `json:"shifts" validate:"dive"`
+}
+
+// AutoAssignRequest represents the request payload for assigning a technician automatically
+type AutoAssignRequest struct {
+	Strategy AssignmentStrategy `json:"strategy,omitempty" validate:"omitempty,oneof=round_robin least_loaded skill_weighted"`
+}
+
+// TechnicianProfileResponse represents the skills and shifts of a technician
+type TechnicianProfileResponse struct {
+	TechnicianID uuid.UUID          `json:"technician_id"`
+	Skills       []*TechnicianSkill `json:"skills"`
+	Shifts       []*TechnicianShift `json:"shifts"`
+}
+
+// TechnicianCandidate is a technician as weighed by the assignment engine
+type TechnicianCandidate struct {
+	TechnicianID   uuid.UUID  `json:"technician_id"`
+	FullName       string     `json:"full_name"`
+	OpenOrders     int64      `json:"open_orders"`
+	SkillLevel     int        `json:"skill_level"` // 0 when the technician has no skill for the service type
+	OnShift        bool       `json:"on_shift"`
+	AverageRating  float64    `json:"average_rating"`
+	TotalRatings   int64      `json:"total_ratings"`
+	LastAssignedAt *time.Time `json:"last_assigned_at,omitempty"`
+	Eligible       bool       `json:"eligible"`
+	Reason         string     `json:"reason,omitempty"` // why the technician is not eligible
+	Score          float64    `json:"score"`
+}
@@ -43,10 +43,15 @@ type User struct {
 	BranchID    *uuid.UUID `gorm:"type:uuid;references:id"`
 	Branch      *Branch    `gorm:"foreignKey:BranchID"`
 	IsActive    bool       `gorm:"default:true"`
-	FCMToken    string     `json:"fcm_token,omitempty" gorm:"type:text"`
+	MFAEnabled  bool       `json:"mfa_enabled" gorm:"default:false"`
+	MFASecret   string     `json:"-" gorm:"type:varchar(64)"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	DeletedAt   gorm.DeletedAt `gorm:"index"`
+
+	// Contact verification, nil until the customer confirms the address or number
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at,omitempty"`
 }
 
 // TableName overrides the table name
@@ -59,6 +64,28 @@ func (u *User) SetName() {
 	u.Name = u.FullName
 }
 
+// SetContact updates the email and phone, clearing the verification of whichever changed.
+// It reports whether anything changed.
+func (u *User) SetContact(email, phone string) bool {
+	changed := false
+	if u.Email != email {
+		u.Email = email
+		u.EmailVerifiedAt = nil
+		changed = true
+	}
+	if u.Phone != phone {
+		u.Phone = phone
+		u.PhoneVerifiedAt = nil
+		changed = true
+	}
+	return changed
+}
+
+// IsContactVerified reports whether both the email address and phone number are verified
+func (u *User) IsContactVerified() bool {
+	return u.EmailVerifiedAt != nil && u.PhoneVerifiedAt != nil
+}
+
 // UserRequest defines request payload for creating or updating user
 type UserRequest struct {
 	Email    string   `json:"email" validate:"required,email"`
@@ -69,6 +96,14 @@ type UserRequest struct {
 	BranchID *string  `json:"branch_id,omitempty"`
 }
 
+// ProfileRequest represents the fields users may change on their own account. Role and
+// branch are left out: roles are assigned through role management and branches by admins.
+type ProfileRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	FullName string `json:"full_name" validate:"required"`
+	Phone    string `json:"phone" validate:"required"`
+}
+
 // UserResponse defines how user data is returned to the client
 type UserResponse struct {
 	ID          uuid.UUID       `json:"id"`
@@ -82,8 +117,13 @@ type UserResponse struct {
 	BranchID    *uuid.UUID      `json:"branch_id,omitempty"`
 	Branch      *BranchResponse `json:"branch,omitempty"`
 	IsActive    bool            `json:"is_active"`
+	MFAEnabled  bool            `json:"mfa_enabled"`
 	CreatedAt   time.Time       `json:"created_at"`
 	UpdatedAt   time.Time       `json:"updated_at"`
+
+	// Contact verification state
+	EmailVerified bool `json:"email_verified"`
+	PhoneVerified bool `json:"phone_verified"`
 }
 
 type UserUpdateRequest struct {
@@ -105,7 +145,11 @@ func (u *User) ToResponse() UserResponse {
 		Role:        u.Role,
 		Status:      u.Status,
 		LastLoginAt: u.LastLoginAt,
+		MFAEnabled:  u.MFAEnabled,
 		CreatedAt:   u.CreatedAt,
 		UpdatedAt:   u.UpdatedAt,
+
+		EmailVerified: u.EmailVerifiedAt != nil,
+		PhoneVerified: u.PhoneVerifiedAt != nil,
 	}
 }
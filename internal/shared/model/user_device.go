@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DevicePlatform represents the platform of a push notification device
+type DevicePlatform string
+
+const (
+	DevicePlatformAndroid DevicePlatform = "android"
+	DevicePlatformIOS     DevicePlatform = "ios"
+	DevicePlatformWeb     DevicePlatform = "web"
+)
+
+// UserDevice is a device registered for push notifications.
+// A user can have several devices; each one is tied to the login session that registered it.
+type UserDevice struct {
+	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID     uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`
+	Token      string         `json:"-" gorm:"type:text;not null;uniqueIndex"` // FCM registration token
+	Platform   DevicePlatform `json:"platform" gorm:"type:varchar(20)"`
+	AppVersion string         `json:"app_version" gorm:"type:varchar(50)"`
+	DeviceName string         `json:"device_name" gorm:"type:varchar(100)"`
+	SessionID  string         `json:"-" gorm:"type:varchar(64);index"` // login session from the token's sid claim
+	LastSeenAt time.Time      `json:"last_seen_at" gorm:"not null"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// TableName returns the table name for UserDevice
+func (UserDevice) TableName() string {
+	return "user_devices"
+}
+
+// RegisterDeviceRequest represents the request payload for registering a push device
+type RegisterDeviceRequest struct {
+	Token      string         `json:"token" validate:"required,max=4096"`
+	Platform   DevicePlatform `json:"platform" validate:"omitempty,oneof=android ios web"`
+	AppVersion string         `json:"app_version,omitempty" validate:"omitempty,max=50"`
+	DeviceName string         `json:"device_name,omitempty" validate:"omitempty,max=100"`
+}
+
+// UserDeviceResponse represents a registered device in API responses
+type UserDeviceResponse struct {
+	ID         uuid.UUID      `json:"id"`
+	Platform   DevicePlatform `json:"platform"`
+	AppVersion string         `json:"app_version,omitempty"`
+	DeviceName string         `json:"device_name,omitempty"`
+	Current    bool           `json:"current"` // registered by the session making the request
+	LastSeenAt time.Time      `json:"last_seen_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// ToResponse converts UserDevice to UserDeviceResponse
+func (d *UserDevice) ToResponse(sessionID string) UserDeviceResponse {
+	return UserDeviceResponse{
+		ID:         d.ID,
+		Platform:   d.Platform,
+		AppVersion: d.AppVersion,
+		DeviceName: d.DeviceName,
+		Current:    sessionID != "" && d.SessionID == sessionID,
+		LastSeenAt: d.LastSeenAt,
+		CreatedAt:  d.CreatedAt,
+	}
+}
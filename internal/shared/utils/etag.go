@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"net/http"
+	"service/internal/shared/model"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FormatETag returns the entity tag of a record version
+func FormatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ParseIfMatch returns the record version required by an If-Match header, or nil when
+// the header is absent or matches any version. Weak tags are accepted.
+func ParseIfMatch(header string) (*int64, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	tag := strings.TrimPrefix(header, "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return nil, model.ErrInvalidIfMatch
+	}
+	version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+	if err != nil {
+		return nil, model.ErrInvalidIfMatch
+	}
+	return &version, nil
+}
+
+// CheckVersion fails with ErrVersionConflict when a version is required and the record
+// is at another one
+func CheckVersion(current int64, expected *int64) error {
+	if expected != nil && *expected != current {
+		return model.ErrVersionConflict
+	}
+	return nil
+}
+
+// IfMatchVersion reads the version required by the If-Match header of the request,
+// responding with 400 when the header is malformed
+func IfMatchVersion(c *gin.Context) (*int64, bool) {
+	version, err := ParseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.CreateErrorResponse("invalid_if_match", err.Error(), nil))
+		return nil, false
+	}
+	return version, true
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"service/internal/shared/model"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	version := func(v int64) *int64 { return &v }
+	tests := []struct {
+		name    string
+		header  string
+		want    *int64
+		wantErr error
+	}{
+		{"absent", "", nil, nil},
+		{"any version", "*", nil, nil},
+		{"strong tag", `"3"`, version(3), nil},
+		{"weak tag", `W/"3"`, version(3), nil},
+		{"surrounding spaces", ` "12" `, version(12), nil},
+		{"round trip", FormatETag(42), version(42), nil},
+		{"unquoted", "3", nil, model.ErrInvalidIfMatch},
+		{"not a number", `"abc"`, nil, model.ErrInvalidIfMatch},
+		{"empty tag", `""`, nil, model.ErrInvalidIfMatch},
+		{"several tags", `"1", "2"`, nil, model.ErrInvalidIfMatch},
+		{"lone quote", `"`, nil, model.ErrInvalidIfMatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseIfMatch(tt.header)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	version := func(v int64) *int64 { return &v }
+	tests := []struct {
+		name     string
+		current  int64
+		expected *int64
+		want     error
+	}{
+		{"no version required", 4, nil, nil},
+		{"same version", 4, version(4), nil},
+		{"stale version", 4, version(3), model.ErrVersionConflict},
+		{"future version", 4, version(5), model.ErrVersionConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CheckVersion(tt.current, tt.expected))
+		})
+	}
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		wantOK     bool
+		wantStatus int
+	}{
+		{"absent", "", true, http.StatusOK},
+		{"valid", `"7"`, true, http.StatusOK},
+		{"malformed", "seven", false, http.StatusBadRequest},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodPut, "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("If-Match", tt.header)
+			}
+
+			_, ok := IfMatchVersion(c)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"encoding/binary"
+	"strings"
+	"time"
+)
+
+const (
+	exifTagDateTime         = 0x0132 // IFD0: when the file was last changed
+	exifTagExifIFD          = 0x8769 // IFD0: offset of the Exif sub-IFD
+	exifTagDateTimeOriginal = 0x9003 // Exif IFD: when the photo was taken
+	exifDateLayout          = "2006:01:02 15:04:05"
+)
+
+// ExifDateTaken returns when a JPEG photo was taken according to its EXIF metadata. EXIF
+// dates carry no time zone, so they are read in the local time zone. ok is false when the
+// data is not a JPEG or has no usable date.
+func ExifDateTaken(data []byte) (taken time.Time, ok bool) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return time.Time{}, false
+	}
+
+	// Walk the segments up to the image data looking for the Exif APP1 segment
+	pos := 2
+	for pos+4 <= len(data) && data[pos] == 0xFF {
+		marker := data[pos+1]
+		if marker == 0xDA || marker == 0xD9 {
+			break
+		}
+		size := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if size < 2 || pos+2+size > len(data) {
+			break
+		}
+		segment := data[pos+4 : pos+2+size]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return exifDate(segment[6:])
+		}
+		pos += 2 + size
+	}
+	return time.Time{}, false
+}
+
+// exifDate reads the date a photo was taken from the TIFF structure of an Exif segment,
+// falling back to the modification date
+func exifDate(tiff []byte) (time.Time, bool) {
+	if len(tiff) < 8 {
+		return time.Time{}, false
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return time.Time{}, false
+	}
+
+	var modified, original string
+	exifIFD := 0
+	exifEntries(tiff, order, int(order.Uint32(tiff[4:])), func(tag uint16, entry []byte) {
+		switch tag {
+		case exifTagDateTime:
+			modified = exifString(tiff, order, entry)
+		case exifTagExifIFD:
+			exifIFD = int(order.Uint32(entry[8:]))
+		}
+	})
+	exifEntries(tiff, order, exifIFD, func(tag uint16, entry []byte) {
+		if tag == exifTagDateTimeOriginal {
+			original = exifString(tiff, order, entry)
+		}
+	})
+
+	for _, value := range []string{original, modified} {
+		if taken, err := time.ParseInLocation(exifDateLayout, value, time.Local); err == nil {
+			return taken, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// exifEntries calls fn with the tag and the raw 12-byte entry of every entry of the IFD
+// at the given offset
+func exifEntries(tiff []byte, order binary.ByteOrder, offset int, fn func(tag uint16, entry []byte)) {
+	if offset < 8 || offset+2 > len(tiff) {
+		return
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		start := offset + 2 + i*12
+		if start+12 > len(tiff) {
+			return
+		}
+		fn(order.Uint16(tiff[start:]), tiff[start:start+12])
+	}
+}
+
+// exifString reads an ASCII value, stored inline when it fits in 4 bytes
+func exifString(tiff []byte, order binary.ByteOrder, entry []byte) string {
+	count := int(order.Uint32(entry[4:]))
+	var value []byte
+	if count <= 4 {
+		value = entry[8 : 8+count]
+	} else {
+		offset := int(order.Uint32(entry[8:]))
+		if offset < 0 || offset+count > len(tiff) {
+			return ""
+		}
+		value = tiff[offset : offset+count]
+	}
+	return strings.TrimRight(string(value), "\x00 ")
+}
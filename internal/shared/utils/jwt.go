@@ -10,16 +10,29 @@ import (
 	"github.com/google/uuid"
 )
 
+// Token audiences. Every token type is signed with the same keys, so each carries the
+// audience it was issued for and is only accepted where that audience is expected; a
+// refresh or email verification token cannot be used as an access token.
+const (
+	audienceAccess            = "access"
+	audienceRefresh           = "refresh"
+	audiencePasswordReset     = "password_reset"
+	audienceInvitation        = "invitation"
+	audienceEmailVerification = "email_verification"
+)
+
 // JWTClaims represents JWT claims
 type JWTClaims struct {
-	UserID uuid.UUID      `json:"user_id"`
-	Role   model.UserRole `json:"role"`
+	UserID    uuid.UUID      `json:"user_id"`
+	Role      model.UserRole `json:"role"`
+	SessionID string         `json:"sid,omitempty"` // login session, kept across refreshes
 	jwt.RegisteredClaims
 }
 
 // RefreshTokenClaims represents refresh token claims
 type RefreshTokenClaims struct {
-	UserID uuid.UUID `json:"user_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	SessionID string    `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -29,15 +42,24 @@ type PasswordResetClaims struct {
 	jwt.RegisteredClaims
 }
 
-// GenerateAccessToken generates a new access token
-func GenerateAccessToken(userID uuid.UUID, role model.UserRole) (string, error) {
+// InvitationClaims represents staff invitation token claims
+type InvitationClaims struct {
+	InvitationID uuid.UUID `json:"invitation_id"`
+	jwt.RegisteredClaims
+}
+
+// EmailVerificationClaims represents email verification token claims
+type EmailVerificationClaims struct {
+	UserID uuid.UUID `json:"user_id"`
+	Email  string    `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// GenerateAccessToken generates a new access token for a login session
+func GenerateAccessToken(userID uuid.UUID, role model.UserRole, sessionID string) (string, error) {
 	// safe defaults if config not loaded (e.g., during tests)
-	jwtSecret := "test-secret"
 	expiry := 24 * time.Hour
 	if config.Config != nil {
-		if config.Config.JWTSecret != "" {
-			jwtSecret = config.Config.JWTSecret
-		}
 		expiry = config.Config.JWTExpiry
 		if expiry == 0 {
 			expiry = 24 * time.Hour
@@ -45,30 +67,27 @@ func GenerateAccessToken(userID uuid.UUID, role model.UserRole) (string, error)
 	}
 
 	claims := JWTClaims{
-		UserID: userID,
-		Role:   role,
+		UserID:    userID,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
+			Audience:  jwt.ClaimStrings{audienceAccess},
 			Issuer:    "iphone-service-api",
 			Subject:   userID.String(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(jwtSecret))
+	return signToken(claims)
 }
 
-// GenerateRefreshToken generates a new refresh token
-func GenerateRefreshToken(userID uuid.UUID) (string, error) {
+// GenerateRefreshToken generates a new refresh token for a login session
+func GenerateRefreshToken(userID uuid.UUID, sessionID string) (string, error) {
 	// safe defaults if config not loaded
-	jwtSecret := "test-secret"
 	expiry := 7 * 24 * time.Hour
 	if config.Config != nil {
-		if config.Config.JWTSecret != "" {
-			jwtSecret = config.Config.JWTSecret
-		}
 		expiry = config.Config.RefreshExpiry
 		if expiry == 0 {
 			expiry = 7 * 24 * time.Hour
@@ -76,18 +95,19 @@ func GenerateRefreshToken(userID uuid.UUID) (string, error) {
 	}
 
 	claims := RefreshTokenClaims{
-		UserID: userID,
+		UserID:    userID,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
+			Audience:  jwt.ClaimStrings{audienceRefresh},
 			Issuer:    "iphone-service-api",
 			Subject:   userID.String(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(jwtSecret))
+	return signToken(claims)
 }
 
 // GeneratePasswordResetToken generates a password reset token
@@ -98,23 +118,57 @@ func GeneratePasswordResetToken(userID uuid.UUID) (string, error) {
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(1 * time.Hour)), // 1 hour expiry
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
+			Audience:  jwt.ClaimStrings{audiencePasswordReset},
 			Issuer:    "iphone-service-api",
 			Subject:   userID.String(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(config.Config.JWTSecret))
+	return signToken(claims)
+}
+
+// GenerateInvitationToken generates a signed staff invitation token.
+// The nonce is stored hashed on the invitation so that each link can only be used once.
+func GenerateInvitationToken(invitationID uuid.UUID, nonce string, expiresAt time.Time) (string, error) {
+	claims := InvitationClaims{
+		InvitationID: invitationID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        nonce,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Audience:  jwt.ClaimStrings{audienceInvitation},
+			Issuer:    "iphone-service-api",
+			Subject:   invitationID.String(),
+		},
+	}
+
+	return signToken(claims)
+}
+
+// GenerateEmailVerificationToken generates a signed email verification link token.
+// The nonce is stored hashed so that only the most recently sent link works.
+func GenerateEmailVerificationToken(userID uuid.UUID, email, nonce string, expiresAt time.Time) (string, error) {
+	claims := EmailVerificationClaims{
+		UserID: userID,
+		Email:  email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        nonce,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Audience:  jwt.ClaimStrings{audienceEmailVerification},
+			Issuer:    "iphone-service-api",
+			Subject:   userID.String(),
+		},
+	}
+
+	return signToken(claims)
 }
 
 // ValidateAccessToken validates an access token and returns claims
 func ValidateAccessToken(tokenString string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return []byte(config.Config.JWTSecret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, verificationKey, jwt.WithAudience(audienceAccess))
 
 	if err != nil {
 		return nil, err
@@ -129,12 +183,7 @@ func ValidateAccessToken(tokenString string) (*JWTClaims, error) {
 
 // ValidateRefreshToken validates a refresh token and returns claims
 func ValidateRefreshToken(tokenString string) (*RefreshTokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &RefreshTokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return []byte(config.Config.JWTSecret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &RefreshTokenClaims{}, verificationKey, jwt.WithAudience(audienceRefresh))
 
 	if err != nil {
 		return nil, err
@@ -149,9 +198,7 @@ func ValidateRefreshToken(tokenString string) (*RefreshTokenClaims, error) {
 
 // ParseRefreshToken parses a refresh token without validating expiry for metadata extraction
 func ParseRefreshToken(tokenString string) (*RefreshTokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &RefreshTokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(config.Config.JWTSecret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &RefreshTokenClaims{}, verificationKey, jwt.WithAudience(audienceRefresh))
 	if err != nil {
 		return nil, err
 	}
@@ -163,12 +210,7 @@ func ParseRefreshToken(tokenString string) (*RefreshTokenClaims, error) {
 
 // ValidatePasswordResetToken validates a password reset token and returns claims
 func ValidatePasswordResetToken(tokenString string) (*PasswordResetClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &PasswordResetClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return []byte(config.Config.JWTSecret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &PasswordResetClaims{}, verificationKey, jwt.WithAudience(audiencePasswordReset))
 
 	if err != nil {
 		return nil, err
@@ -181,6 +223,36 @@ func ValidatePasswordResetToken(tokenString string) (*PasswordResetClaims, error
 	return nil, errors.New("invalid token")
 }
 
+// ValidateInvitationToken validates a staff invitation token and returns claims
+func ValidateInvitationToken(tokenString string) (*InvitationClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &InvitationClaims{}, verificationKey, jwt.WithAudience(audienceInvitation))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*InvitationClaims); ok && token.Valid {
+		return claims, nil
+	}
+
+	return nil, errors.New("invalid token")
+}
+
+// ValidateEmailVerificationToken validates an email verification token and returns claims
+func ValidateEmailVerificationToken(tokenString string) (*EmailVerificationClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &EmailVerificationClaims{}, verificationKey, jwt.WithAudience(audienceEmailVerification))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*EmailVerificationClaims); ok && token.Valid {
+		return claims, nil
+	}
+
+	return nil, errors.New("invalid token")
+}
+
 // ExtractTokenFromHeader extracts token from Authorization header
 func ExtractTokenFromHeader(authHeader string) (string, error) {
 	if authHeader == "" {
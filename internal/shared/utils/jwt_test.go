@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"service/internal/shared/model"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenAudiences(t *testing.T) {
+	t.Cleanup(func() { _ = LoadSigningKeys(nil) })
+	loadKeys(t, signingKey(t, model.SigningKeyActive))
+
+	userID := uuid.New()
+	expires := time.Now().Add(time.Hour)
+	issue := func(generate func() (string, error)) string {
+		token, err := generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	tokens := map[string]string{
+		"access":         issue(func() (string, error) { return GenerateAccessToken(userID, model.RoleKasir, "session") }),
+		"refresh":        issue(func() (string, error) { return GenerateRefreshToken(userID, "session") }),
+		"password reset": issue(func() (string, error) { return GeneratePasswordResetToken(userID) }),
+		"invitation":     issue(func() (string, error) { return GenerateInvitationToken(uuid.New(), "nonce", expires) }),
+		"email verification": issue(func() (string, error) {
+			return GenerateEmailVerificationToken(userID, "a@example.com", "nonce", expires)
+		}),
+	}
+
+	validators := []struct {
+		accepts  string
+		validate func(string) error
+	}{
+		{"access", func(token string) error { _, err := ValidateAccessToken(token); return err }},
+		{"refresh", func(token string) error { _, err := ValidateRefreshToken(token); return err }},
+		{"refresh", func(token string) error { _, err := ParseRefreshToken(token); return err }},
+		{"password reset", func(token string) error { _, err := ValidatePasswordResetToken(token); return err }},
+		{"invitation", func(token string) error { _, err := ValidateInvitationToken(token); return err }},
+		{"email verification", func(token string) error { _, err := ValidateEmailVerificationToken(token); return err }},
+	}
+
+	for _, v := range validators {
+		for kind, token := range tokens {
+			t.Run(kind+" as "+v.accepts, func(t *testing.T) {
+				err := v.validate(token)
+				if kind == v.accepts {
+					assert.NoError(t, err)
+				} else {
+					assert.Error(t, err)
+				}
+			})
+		}
+	}
+}
@@ -2,10 +2,15 @@ package utils
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"image"
 	"image/png"
 	"io"
 	"service/internal/shared/config"
+	"strings"
 
 	"github.com/skip2/go-qrcode"
 )
@@ -34,17 +39,43 @@ func GenerateQRCode(text string, size int) ([]byte, error) {
 }
 
 // GenerateQRCodeForOrder generates a QR code for a service order
-// Contains order number and URL for tracking
+// Contains a signed public tracking URL, so the order can be followed without an account
 func GenerateQRCodeForOrder(orderNumber string) ([]byte, error) {
 	baseURL := config.Config.BaseURL
 	if baseURL == "" {
 		baseURL = "https://service.example.com"
 	}
 
-	qrText := baseURL + "/orders/" + orderNumber
+	qrText := baseURL + "/track/" + SignOrderTrackingToken(orderNumber, config.Config.JWTSecret)
 	return GenerateQRCode(qrText, 256)
 }
 
+// SignOrderTrackingToken returns a token granting public tracking of one order.
+// Format: <order number>.<base64url HMAC-SHA256>
+func SignOrderTrackingToken(orderNumber, secret string) string {
+	return orderNumber + "." + orderTrackingSignature(orderNumber, secret)
+}
+
+// ParseOrderTrackingToken verifies a token made by SignOrderTrackingToken and returns its order number
+func ParseOrderTrackingToken(token, secret string) (string, error) {
+	i := strings.LastIndex(token, ".")
+	if i <= 0 {
+		return "", errors.New("malformed tracking token")
+	}
+	orderNumber, signature := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(signature), []byte(orderTrackingSignature(orderNumber, secret))) {
+		return "", errors.New("invalid tracking token signature")
+	}
+	return orderNumber, nil
+}
+
+// orderTrackingSignature signs an order number with a key reserved for tracking links
+func orderTrackingSignature(orderNumber, secret string) string {
+	mac := hmac.New(sha256.New, []byte("order-tracking:"+secret))
+	mac.Write([]byte(orderNumber))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
 // GenerateQRCodeWriter generates QR code and writes to io.Writer
 func GenerateQRCodeWriter(text string, size int, writer io.Writer) error {
 	qrBytes, err := GenerateQRCode(text, size)
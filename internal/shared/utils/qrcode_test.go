@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOrderTrackingToken(t *testing.T) {
+	const secret = "tracking-secret"
+	valid := SignOrderTrackingToken("JKT01-2026-000123", secret)
+	dotted := SignOrderTrackingToken("JKT01.2026.000123", secret)
+
+	tests := []struct {
+		name    string
+		token   string
+		want    string
+		wantErr bool
+	}{
+		{"valid", valid, "JKT01-2026-000123", false},
+		{"order number with dots", dotted, "JKT01.2026.000123", false},
+		{"signed with another secret", SignOrderTrackingToken("JKT01-2026-000123", "other-secret"), "", true},
+		{"order number swapped", "JKT01-2026-000124" + valid[len("JKT01-2026-000123"):], "", true},
+		{"signature truncated", valid[:len(valid)-1], "", true},
+		{"signature missing", "JKT01-2026-000123.", "", true},
+		{"no separator", "JKT01-2026-000123", "", true},
+		{"order number missing", valid[len("JKT01-2026-000123"):], "", true},
+		{"empty", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOrderTrackingToken(tt.token, secret)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"service/internal/shared/config"
+	"service/internal/shared/model"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// keyring holds the signing keys loaded from the database. Keys are looked up by kid
+// when verifying; the active key signs new tokens. When no key is loaded (e.g. during
+// tests or without a database) tokens fall back to HS256 with the shared JWT secret.
+var keyring = struct {
+	sync.RWMutex
+	activeKid string
+	private   ed25519.PrivateKey
+	public    map[string]ed25519.PublicKey
+	jwks      model.JWKS
+}{
+	public: map[string]ed25519.PublicKey{},
+	jwks:   model.JWKS{Keys: []model.JWK{}},
+}
+
+// GenerateSigningKey creates a new Ed25519 key pair with its private key encrypted for storage
+func GenerateSigningKey() (*model.SigningKey, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	kid, err := RandomHex(8)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := EncryptString(base64.StdEncoding.EncodeToString(private.Seed()), keyEncryptionSecret())
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.SigningKey{
+		Kid:        kid,
+		Algorithm:  model.SigningKeyAlgorithm,
+		PublicKey:  base64.RawURLEncoding.EncodeToString(public),
+		PrivateKey: encrypted,
+		Status:     model.SigningKeyPending,
+	}, nil
+}
+
+// LoadSigningKeys replaces the in-memory keyring with the given keys.
+// Every key is published for verification; the active key is also used for signing.
+func LoadSigningKeys(keys []*model.SigningKey) error {
+	public := make(map[string]ed25519.PublicKey, len(keys))
+	jwks := model.JWKS{Keys: []model.JWK{}}
+	var activeKid string
+	var private ed25519.PrivateKey
+
+	for _, k := range keys {
+		raw, err := base64.RawURLEncoding.DecodeString(k.PublicKey)
+		if err != nil || len(raw) != ed25519.PublicKeySize {
+			return fmt.Errorf("invalid public key for kid %s", k.Kid)
+		}
+		public[k.Kid] = ed25519.PublicKey(raw)
+		jwks.Keys = append(jwks.Keys, k.ToJWK())
+
+		if k.Status != model.SigningKeyActive {
+			continue
+		}
+		seed, err := DecryptString(k.PrivateKey, keyEncryptionSecret())
+		if err != nil {
+			return fmt.Errorf("decrypt private key for kid %s: %w", k.Kid, err)
+		}
+		rawSeed, err := base64.StdEncoding.DecodeString(seed)
+		if err != nil || len(rawSeed) != ed25519.SeedSize {
+			return fmt.Errorf("invalid private key for kid %s", k.Kid)
+		}
+		activeKid = k.Kid
+		private = ed25519.NewKeyFromSeed(rawSeed)
+	}
+
+	keyring.Lock()
+	defer keyring.Unlock()
+	keyring.activeKid = activeKid
+	keyring.private = private
+	keyring.public = public
+	keyring.jwks = jwks
+	return nil
+}
+
+// GetJWKS returns the public signing keys in JWKS format
+func GetJWKS() model.JWKS {
+	keyring.RLock()
+	defer keyring.RUnlock()
+	return keyring.jwks
+}
+
+// signToken signs claims with the active key, or with the shared secret when no key is loaded
+func signToken(claims jwt.Claims) (string, error) {
+	keyring.RLock()
+	kid, private := keyring.activeKid, keyring.private
+	keyring.RUnlock()
+
+	if private == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(legacyJWTSecret()))
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = kid
+	return token.SignedString(private)
+}
+
+// verificationKey resolves the key for a token by its kid header.
+// Legacy HS256 tokens are accepted while no key is loaded or while legacy tokens are allowed.
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodEd25519:
+		kid, _ := token.Header["kid"].(string)
+		keyring.RLock()
+		key, ok := keyring.public[kid]
+		keyring.RUnlock()
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		return key, nil
+	case *jwt.SigningMethodHMAC:
+		if !acceptLegacyTokens() {
+			return nil, errors.New("legacy tokens are no longer accepted")
+		}
+		return []byte(legacyJWTSecret()), nil
+	default:
+		return nil, errors.New("unexpected signing method")
+	}
+}
+
+// acceptLegacyTokens reports whether HS256 tokens signed with the shared secret are still valid
+func acceptLegacyTokens() bool {
+	keyring.RLock()
+	loaded := keyring.private != nil
+	keyring.RUnlock()
+	if !loaded || config.Config == nil {
+		return true
+	}
+	return config.Config.JWTAcceptLegacyHS256
+}
+
+// legacyJWTSecret returns the shared HS256 secret, with a safe default if config is not loaded
+func legacyJWTSecret() string {
+	if config.Config != nil && config.Config.JWTSecret != "" {
+		return config.Config.JWTSecret
+	}
+	return "test-secret"
+}
+
+// keyEncryptionSecret returns the secret used to encrypt stored private keys
+func keyEncryptionSecret() string {
+	if config.Config != nil && config.Config.JWTKeyEncryptionKey != "" {
+		return config.Config.JWTKeyEncryptionKey
+	}
+	return legacyJWTSecret()
+}
+
+// MaxTokenLifetime returns the longest validity of any token signed by the keyring.
+// A retired key must stay published at least this long.
+func MaxTokenLifetime() time.Duration {
+	if config.Config == nil {
+		return 7 * 24 * time.Hour
+	}
+	lifetime := time.Hour // password reset tokens
+	for _, d := range []time.Duration{config.Config.JWTExpiry, config.Config.RefreshExpiry, config.Config.InvitationExpiry} {
+		if d > lifetime {
+			lifetime = d
+		}
+	}
+	return lifetime
+}
@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"service/internal/shared/config"
+	"service/internal/shared/model"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// signingKey generates a key in the given state, failing the test on error
+func signingKey(t *testing.T, status model.SigningKeyStatus) *model.SigningKey {
+	t.Helper()
+	key, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key.Status = status
+	return key
+}
+
+// loadKeys replaces the keyring, failing the test on error
+func loadKeys(t *testing.T, keys ...*model.SigningKey) {
+	t.Helper()
+	if err := LoadSigningKeys(keys); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// accessToken issues an access token with the keyring as it is, failing the test on error
+func accessToken(t *testing.T) string {
+	t.Helper()
+	token, err := GenerateAccessToken(uuid.New(), model.RoleKasir, "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// tokenHeader returns the algorithm and kid a token was signed with
+func tokenHeader(t *testing.T, token string) (string, string) {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &JWTClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return parsed.Method.Alg(), kid
+}
+
+func TestSigningKeyRotation(t *testing.T) {
+	t.Cleanup(func() { _ = LoadSigningKeys(nil) })
+
+	// Without keys, tokens fall back to HS256 with the shared secret
+	loadKeys(t)
+	legacy := accessToken(t)
+	alg, kid := tokenHeader(t, legacy)
+	assert.Equal(t, "HS256", alg)
+	assert.Empty(t, kid)
+
+	first := signingKey(t, model.SigningKeyActive)
+	next := signingKey(t, model.SigningKeyPending)
+	loadKeys(t, first, next)
+	signedFirst := accessToken(t)
+	alg, kid = tokenHeader(t, signedFirst)
+	assert.Equal(t, "EdDSA", alg)
+	assert.Equal(t, first.Kid, kid)
+	assert.Len(t, GetJWKS().Keys, 2)
+
+	// Rotation: the pending key takes over, the old one keeps verifying
+	first.Status, next.Status = model.SigningKeyRetired, model.SigningKeyActive
+	loadKeys(t, first, next)
+	signedNext := accessToken(t)
+	_, kid = tokenHeader(t, signedNext)
+	assert.Equal(t, next.Kid, kid)
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"token of the retired key", signedFirst, true},
+		{"token of the active key", signedNext, true},
+		{"legacy token while allowed", legacy, true},
+		{"garbage", "not-a-token", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateAccessToken(tt.token)
+			assert.Equal(t, tt.valid, err == nil, err)
+		})
+	}
+
+	// Once the retired key is dropped its tokens no longer verify
+	loadKeys(t, next)
+	_, err := ValidateAccessToken(signedFirst)
+	assert.Error(t, err)
+	_, err = ValidateAccessToken(signedNext)
+	assert.NoError(t, err)
+}
+
+func TestLegacyTokenFallback(t *testing.T) {
+	previous := config.Config
+	t.Cleanup(func() {
+		config.Config = previous
+		_ = LoadSigningKeys(nil)
+	})
+
+	loadKeys(t)
+	legacy := accessToken(t)
+
+	tests := []struct {
+		name        string
+		keyLoaded   bool
+		allowLegacy bool
+		valid       bool
+	}{
+		{"no key loaded", false, false, true},
+		{"key loaded, legacy allowed", true, true, true},
+		{"key loaded, legacy rejected", true, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.Config = &config.AppConfig{JWTAcceptLegacyHS256: tt.allowLegacy}
+			if tt.keyLoaded {
+				loadKeys(t, signingKey(t, model.SigningKeyActive))
+			} else {
+				loadKeys(t)
+			}
+			_, err := ValidateAccessToken(legacy)
+			assert.Equal(t, tt.valid, err == nil, err)
+		})
+	}
+}
+
+func TestLoadSigningKeysRejectsInvalidKeys(t *testing.T) {
+	t.Cleanup(func() { _ = LoadSigningKeys(nil) })
+
+	badPublic := signingKey(t, model.SigningKeyPending)
+	badPublic.PublicKey = "too-short"
+	badPrivate := signingKey(t, model.SigningKeyActive)
+	badPrivate.PrivateKey = "not-encrypted"
+
+	tests := []struct {
+		name string
+		key  *model.SigningKey
+	}{
+		{"invalid public key", badPublic},
+		{"invalid private key", badPrivate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, LoadSigningKeys([]*model.SigningKey{tt.key}))
+		})
+	}
+}
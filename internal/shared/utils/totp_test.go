@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcTOTPSecret is the SHA-1 test key of RFC 6238
+const rfcTOTPSecret = "12345678901234567890"
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 appendix B, truncated to the last six digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, totpCode([]byte(rfcTOTPSecret), uint64(tt.unix/totpPeriod)))
+		})
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte(rfcTOTPSecret))
+	step := uint64(time.Now().Unix() / totpPeriod)
+	codeAt := func(offset int64) string {
+		return totpCode([]byte(rfcTOTPSecret), uint64(int64(step)+offset))
+	}
+
+	// Offsets stay valid or invalid when the clock moves into the next step mid-test
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		want   bool
+	}{
+		{"current step", secret, codeAt(0), true},
+		{"next step within skew", secret, codeAt(1), true},
+		{"lowercase secret", strings.ToLower(secret), codeAt(0), true},
+		{"surrounding spaces", secret, " " + codeAt(0) + " ", true},
+		{"too far ahead", secret, codeAt(3), false},
+		{"too far behind", secret, codeAt(-3), false},
+		{"too short", secret, codeAt(0)[:5], false},
+		{"too long", secret, codeAt(0) + "0", false},
+		{"invalid secret", "not base32!", codeAt(0), false},
+		{"empty code", secret, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidateTOTP(tt.secret, tt.code))
+		})
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	assert.NoError(t, err)
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	assert.NoError(t, err)
+	assert.Len(t, key, 20)
+
+	other, err := GenerateTOTPSecret()
+	assert.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := TOTPProvisioningURI("JBSWY3DPEHPK3PXP", "staff@example.com", "Service Center")
+	assert.Equal(t, "otpauth://totp/Service%20Center:staff@example.com?digits=6&issuer=Service+Center&period=30&secret=JBSWY3DPEHPK3PXP", uri)
+}
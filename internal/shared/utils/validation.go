@@ -22,6 +22,7 @@ func InitValidator() {
 	// Register custom validators
 	validate.RegisterValidation("password", validatePassword)
 	validate.RegisterValidation("phone", validatePhone)
+	validate.RegisterValidation("imei", validateIMEI)
 }
 
 // ValidateStruct validates a struct using go-playground/validator
@@ -87,6 +88,36 @@ func validatePhone(fl validator.FieldLevel) bool {
 	return false
 }
 
+// validateIMEI validates a device IMEI
+func validateIMEI(fl validator.FieldLevel) bool {
+	return IsValidIMEI(fl.Field().String())
+}
+
+// IsValidIMEI checks that an IMEI has 15 digits and a valid Luhn check digit
+func IsValidIMEI(imei string) bool {
+	if len(imei) != 15 {
+		return false
+	}
+
+	sum := 0
+	for i, char := range imei {
+		if char < '0' || char > '9' {
+			return false
+		}
+		digit := int(char - '0')
+		// Every second digit from the left is doubled
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+
+	return sum%10 == 0
+}
+
 // GetValidationErrors returns formatted validation errors
 func GetValidationErrors(err error) map[string]string {
 	errors := make(map[string]string)
@@ -116,6 +147,8 @@ func getValidationMessage(e validator.FieldError) string {
 		return fmt.Sprintf("%s must be at least 6 characters and contain letters and numbers", e.Field())
 	case "phone":
 		return fmt.Sprintf("%s must be a valid Indonesian phone number", e.Field())
+	case "imei":
+		return fmt.Sprintf("%s must be a valid 15-digit IMEI", e.Field())
 	default:
 		return fmt.Sprintf("%s is invalid", e.Field())
 	}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidIMEI(t *testing.T) {
+	tests := []struct {
+		name string
+		imei string
+		want bool
+	}{
+		{"valid", "490154203237518", true},
+		{"valid with doubled digits above nine", "356938035643809", true},
+		{"all zeros pass the checksum", "000000000000000", true},
+		{"wrong check digit", "490154203237519", false},
+		{"transposed digits", "940154203237518", false},
+		{"too short", "49015420323751", false},
+		{"too long", "4901542032375180", false},
+		{"letters", "49015420323751A", false},
+		{"spaces", "490154 03237518", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsValidIMEI(tt.imei))
+		})
+	}
+}
+
+func TestValidateStructIMEI(t *testing.T) {
+	type device struct {
+		IMEI string `validate:"omitempty,imei"`
+	}
+
+	tests := []struct {
+		name    string
+		imei    string
+		wantErr bool
+	}{
+		{"valid", "490154203237518", false},
+		{"optional", "", false},
+		{"invalid", "490154203237519", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStruct(&device{IMEI: tt.imei})
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}